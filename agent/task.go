@@ -271,6 +271,15 @@ func (a *Agent) makeTaskConfig(ctx context.Context, tc *taskContext) (*model.Tas
 			return nil, err
 		}
 	}
+	if confVersion.Requester == evergreen.GitlabMRRequester {
+		tc.logger.Execution().Info("Fetching patch document for GitLab MR request.")
+		confPatch, err = a.comm.GetTaskPatch(ctx, tc.task)
+		if err != nil {
+			err = errors.Wrap(err, "couldn't fetch patch for GitLab MR request")
+			tc.logger.Execution().Error(err.Error())
+			return nil, err
+		}
+	}
 
 	tc.logger.Execution().Info("Constructing TaskConfig.")
 	return model.NewTaskConfig(confDistro, confVersion, confProject, confTask, confRef, confPatch)
@@ -5,12 +5,13 @@ import (
 
 	"github.com/evergreen-ci/evergreen"
 	"github.com/evergreen-ci/evergreen/model"
+	"github.com/evergreen-ci/evergreen/model/role"
 	"github.com/evergreen-ci/evergreen/util"
 	"github.com/evergreen-ci/gimlet"
 	"github.com/pkg/errors"
 )
 
-//LoadUserManager is used to check the configuration for authentication and create a UserManager depending on what type of authentication (Crowd or Naive) is used.
+//LoadUserManager is used to check the configuration for authentication and create a UserManager depending on what type of authentication (Crowd, Naive, GitHub or OpenID Connect) is used.
 func LoadUserManager(authConfig evergreen.AuthConfig) (gimlet.UserManager, error) {
 	var manager gimlet.UserManager
 	var err error
@@ -38,6 +39,15 @@ func LoadUserManager(authConfig evergreen.AuthConfig) (gimlet.UserManager, error
 			return nil, err
 		}
 	}
+	if authConfig.OIDC != nil {
+		if manager != nil {
+			return nil, errors.New("Cannot have multiple forms of authentication in configuration")
+		}
+		manager, err = NewOIDCUserManager(authConfig.OIDC)
+		if err != nil {
+			return nil, err
+		}
+	}
 
 	if manager != nil {
 		return manager, nil
@@ -73,6 +83,34 @@ func IsSuperUser(superUsers []string, u gimlet.User) bool {
 
 }
 
+// HasProjectPermission verifies that a given user has a permission on a
+// project. Super users and the project's admins have every permission on
+// the project, and other users need a role that grants it.
+func HasProjectPermission(superUsers []string, u gimlet.User, project *model.ProjectRef, permission string) (bool, error) {
+	if u == nil || project == nil {
+		return false, nil
+	}
+	if IsSuperUser(superUsers, u) || util.StringSliceContains(project.Admins, u.Username()) {
+		return true, nil
+	}
+
+	return role.UserHasPermission(u, role.ResourceTypeProject, project.Identifier, permission)
+}
+
+// HasDistroPermission verifies that a given user has a permission on a
+// distro. Super users have every permission, and other users need a role
+// that grants it.
+func HasDistroPermission(superUsers []string, u gimlet.User, distroID, permission string) (bool, error) {
+	if u == nil {
+		return false, nil
+	}
+	if IsSuperUser(superUsers, u) {
+		return true, nil
+	}
+
+	return role.UserHasPermission(u, role.ResourceTypeDistro, distroID, permission)
+}
+
 func getOrCreateUser(u gimlet.User) (gimlet.User, error) {
 	return model.GetOrCreateUser(u.Username(), u.DisplayName(), u.Email())
 }
@@ -4,8 +4,13 @@ import (
 	"testing"
 
 	"github.com/evergreen-ci/evergreen"
+	"github.com/evergreen-ci/evergreen/db"
+	"github.com/evergreen-ci/evergreen/model"
+	"github.com/evergreen-ci/evergreen/model/role"
+	"github.com/evergreen-ci/evergreen/testutil"
 	. "github.com/smartystreets/goconvey/convey"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestLoadUserManager(t *testing.T) {
@@ -73,3 +78,44 @@ func TestSuperUserValidation(t *testing.T) {
 	assert.False(IsSuperUser(superUsers, ru))
 	assert.False(IsSuperUser(superUsers, nil))
 }
+
+func TestPermissions(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+	db.SetGlobalSessionProvider(testutil.TestConfig().SessionFactory())
+	require.NoError(db.ClearCollections(role.Collection))
+
+	superUsers := []string{"super"}
+	su := &simpleUser{UserId: "super"}
+	admin := &simpleUser{UserId: "admin"}
+	ru := &simpleUser{UserId: "regular"}
+	project := &model.ProjectRef{
+		Identifier: "mci",
+		Admins:     []string{"admin"},
+	}
+
+	for _, u := range []*simpleUser{su, admin} {
+		ok, err := HasProjectPermission(superUsers, u, project, role.PermissionManageVars)
+		assert.NoError(err)
+		assert.True(ok)
+	}
+	ok, err := HasProjectPermission(superUsers, ru, project, role.PermissionManageVars)
+	assert.NoError(err)
+	assert.False(ok)
+	ok, err = HasProjectPermission(superUsers, ru, project, role.PermissionPatch)
+	assert.NoError(err)
+	assert.True(ok)
+	ok, err = HasProjectPermission(superUsers, nil, project, role.PermissionPatch)
+	assert.NoError(err)
+	assert.False(ok)
+
+	ok, err = HasDistroPermission(superUsers, su, "ubuntu", role.PermissionEditSettings)
+	assert.NoError(err)
+	assert.True(ok)
+	ok, err = HasDistroPermission(superUsers, admin, "ubuntu", role.PermissionEditSettings)
+	assert.NoError(err)
+	assert.False(ok)
+	ok, err = HasDistroPermission(superUsers, ru, "ubuntu", role.PermissionSpawnHosts)
+	assert.NoError(err)
+	assert.True(ok)
+}
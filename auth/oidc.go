@@ -0,0 +1,590 @@
+package auth
+
+import (
+	"context"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/evergreen-ci/evergreen"
+	"github.com/evergreen-ci/evergreen/model"
+	"github.com/evergreen-ci/evergreen/model/user"
+	"github.com/evergreen-ci/evergreen/util"
+	"github.com/evergreen-ci/gimlet"
+	"github.com/mongodb/grip"
+	"github.com/mongodb/grip/message"
+	"github.com/pkg/errors"
+	"golang.org/x/oauth2"
+	"golang.org/x/oauth2/jws"
+)
+
+const (
+	oidcStateCookie    = "evergreen-oidc-state"
+	oidcRequestTimeout = 10 * time.Second
+)
+
+// OIDCUserManager implements the UserManager with an OpenID Connect provider
+// using the authorization code flow.
+// The login handler redirects the user to the provider's authorization
+// endpoint with an unguessable state, which is also stored in a short-lived
+// cookie, and a nonce derived from the state. When the provider redirects the
+// user back, the callback handler checks the state against the cookie,
+// exchanges the code for tokens, and verifies the ID token's signature
+// against the provider's published keys, along with its issuer, audience,
+// expiration and nonce. The ID token's claims are used to create or update
+// the user and its roles, and the user is given a random login token that
+// is stored with the provider's refresh token on the user.
+// Once the ID token has expired, GetUserByToken uses the refresh token to
+// check that the user is still authorized by the provider, so that users who
+// have been removed from the provider lose access.
+type OIDCUserManager struct {
+	conf        evergreen.OIDCConfig
+	client      *http.Client
+	callbackURI string
+
+	mu        sync.Mutex
+	discovery *oidcDiscovery
+	keys      map[string]*rsa.PublicKey
+}
+
+type oidcDiscovery struct {
+	Issuer                string `json:"issuer"`
+	AuthorizationEndpoint string `json:"authorization_endpoint"`
+	TokenEndpoint         string `json:"token_endpoint"`
+	JWKSURI               string `json:"jwks_uri"`
+}
+
+type oidcClaims map[string]interface{}
+
+// NewOIDCUserManager initializes an OIDCUserManager. The provider's
+// configuration is discovered from the issuer when it is first needed.
+func NewOIDCUserManager(conf *evergreen.OIDCConfig) (gimlet.UserManager, error) {
+	c := *conf
+	if err := c.ValidateAndDefault(); err != nil {
+		return nil, errors.Wrap(err, "invalid OpenID Connect configuration")
+	}
+
+	return &OIDCUserManager{
+		conf:   c,
+		client: &http.Client{Timeout: oidcRequestTimeout},
+	}, nil
+}
+
+// GetUserByToken returns the user with the login token. If the user's ID
+// token has expired, the session is refreshed with the provider first.
+func (um *OIDCUserManager) GetUserByToken(ctx context.Context, token string) (gimlet.User, error) {
+	if token == "" {
+		return nil, errors.New("no login token given")
+	}
+
+	u, err := user.FindOne(user.ByLoginToken(token))
+	if err != nil {
+		return nil, errors.Wrap(err, "problem finding user")
+	}
+	if u == nil {
+		return nil, errors.New("invalid login token")
+	}
+	if time.Now().Before(u.LoginCache.TTL) {
+		return u, nil
+	}
+
+	if err = um.refresh(ctx, u); err != nil {
+		grip.Info(message.WrapError(err, message.Fields{
+			"message": "could not refresh OpenID Connect session",
+			"user":    u.Id,
+		}))
+		return nil, errors.Wrap(err, "login session has expired")
+	}
+
+	return u, nil
+}
+
+// CreateUserToken is not implemented in OIDCUserManager
+func (*OIDCUserManager) CreateUserToken(string, string) (string, error) {
+	return "", errors.New("OIDCUserManager does not create tokens via username/password")
+}
+
+// GetLoginHandler returns the function that starts the authorization code
+// flow by redirecting the user to the provider.
+func (um *OIDCUserManager) GetLoginHandler(callbackUri string) http.HandlerFunc {
+	um.mu.Lock()
+	um.callbackURI = fmt.Sprintf("%s/login/redirect/callback", strings.TrimRight(callbackUri, "/"))
+	um.mu.Unlock()
+
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx, cancel := context.WithTimeout(r.Context(), oidcRequestTimeout)
+		defer cancel()
+
+		conf, err := um.oauthConfig(ctx)
+		if err != nil {
+			grip.Error(errors.Wrap(err, "problem discovering OpenID Connect provider"))
+			http.Error(w, "authentication provider is unavailable", http.StatusServiceUnavailable)
+			return
+		}
+
+		state := util.RandomString()
+		cookie := url.Values{}
+		cookie.Set("state", state)
+		cookie.Set("redirect", r.FormValue("redirect"))
+		http.SetCookie(w, &http.Cookie{
+			Name:     oidcStateCookie,
+			Value:    cookie.Encode(),
+			HttpOnly: true,
+			Path:     "/login",
+			Expires:  time.Now().Add(10 * time.Minute),
+		})
+
+		http.Redirect(w, r, conf.AuthCodeURL(state, oauth2.SetAuthURLParam("nonce", oidcNonce(state))), http.StatusFound)
+	}
+}
+
+// GetLoginCallbackHandler returns the function that is called when the
+// provider redirects the user back to Evergreen.
+func (um *OIDCUserManager) GetLoginCallbackHandler() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		cookie, err := r.Cookie(oidcStateCookie)
+		if err != nil {
+			http.Error(w, "login session not found", http.StatusBadRequest)
+			return
+		}
+		http.SetCookie(w, &http.Cookie{
+			Name:    oidcStateCookie,
+			Path:    "/login",
+			Expires: time.Unix(0, 0),
+		})
+		session, err := url.ParseQuery(cookie.Value)
+		if err != nil {
+			http.Error(w, "invalid login session", http.StatusBadRequest)
+			return
+		}
+
+		state := session.Get("state")
+		if state == "" || subtle.ConstantTimeCompare([]byte(state), []byte(r.FormValue("state"))) != 1 {
+			grip.Error("Error unmatching states when authenticating with OpenID Connect provider")
+			http.Error(w, "invalid login state", http.StatusBadRequest)
+			return
+		}
+		if providerErr := r.FormValue("error"); providerErr != "" {
+			grip.Error(message.Fields{
+				"message":     "OpenID Connect provider returned an error",
+				"error":       providerErr,
+				"description": r.FormValue("error_description"),
+			})
+			http.Error(w, fmt.Sprintf("authentication failed: %s", providerErr), http.StatusUnauthorized)
+			return
+		}
+		code := r.FormValue("code")
+		if code == "" {
+			http.Error(w, "no authorization code given", http.StatusBadRequest)
+			return
+		}
+
+		ctx, cancel := context.WithTimeout(r.Context(), oidcRequestTimeout)
+		defer cancel()
+
+		token, err := um.login(ctx, code, oidcNonce(state))
+		if err != nil {
+			grip.Error(errors.Wrap(err, "problem authenticating with OpenID Connect provider"))
+			http.Error(w, "authentication failed", http.StatusUnauthorized)
+			return
+		}
+		setLoginToken(token, w)
+
+		// only redirect within Evergreen
+		redirect := session.Get("redirect")
+		if !strings.HasPrefix(redirect, "/") || strings.HasPrefix(redirect, "//") {
+			redirect = "/"
+		}
+		http.Redirect(w, r, redirect, http.StatusFound)
+	}
+}
+
+func (*OIDCUserManager) IsRedirect() bool                           { return true }
+func (*OIDCUserManager) GetUserByID(id string) (gimlet.User, error) { return getUserByID(id) }
+func (*OIDCUserManager) GetOrCreateUser(u gimlet.User) (gimlet.User, error) {
+	return getOrCreateUser(u)
+}
+
+// login exchanges the authorization code for tokens, updates the user
+// described by the ID token, and returns the user's new login token.
+func (um *OIDCUserManager) login(ctx context.Context, code, nonce string) (string, error) {
+	conf, err := um.oauthConfig(ctx)
+	if err != nil {
+		return "", errors.Wrap(err, "problem discovering provider")
+	}
+	token, err := conf.Exchange(um.clientContext(ctx), code)
+	if err != nil {
+		return "", errors.Wrap(err, "problem exchanging authorization code")
+	}
+
+	claims, expiry, err := um.verifyTokenResponse(ctx, token, nonce)
+	if err != nil {
+		return "", err
+	}
+	u, err := um.updateUser(claims)
+	if err != nil {
+		return "", err
+	}
+
+	loginToken := util.RandomString()
+	if err = u.SetLoginCache(user.LoginCache{
+		Token:        loginToken,
+		TTL:          expiry,
+		RefreshToken: token.RefreshToken,
+	}); err != nil {
+		return "", err
+	}
+
+	return loginToken, nil
+}
+
+// refresh uses the user's refresh token to renew their session, and updates
+// the user from the new ID token if the provider issues one.
+func (um *OIDCUserManager) refresh(ctx context.Context, u *user.DBUser) error {
+	if u.LoginCache.RefreshToken == "" {
+		return errors.New("session cannot be refreshed")
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, oidcRequestTimeout)
+	defer cancel()
+
+	conf, err := um.oauthConfig(ctx)
+	if err != nil {
+		return errors.Wrap(err, "problem discovering provider")
+	}
+	token, err := conf.TokenSource(um.clientContext(ctx), &oauth2.Token{
+		RefreshToken: u.LoginCache.RefreshToken,
+	}).Token()
+	if err != nil {
+		return errors.Wrap(err, "problem refreshing token")
+	}
+
+	cache := u.LoginCache
+	cache.TTL = token.Expiry
+	if token.RefreshToken != "" {
+		cache.RefreshToken = token.RefreshToken
+	}
+
+	if _, ok := token.Extra("id_token").(string); ok {
+		var claims oidcClaims
+		claims, cache.TTL, err = um.verifyTokenResponse(ctx, token, "")
+		if err != nil {
+			return err
+		}
+		if claims.getString(um.conf.UsernameClaim) != u.Id {
+			return errors.New("refreshed ID token is for a different user")
+		}
+		var updated *user.DBUser
+		if updated, err = um.updateUser(claims); err != nil {
+			return err
+		}
+		*u = *updated
+	}
+
+	if cache.TTL.IsZero() {
+		return errors.New("refreshed token has no expiration")
+	}
+
+	return u.SetLoginCache(cache)
+}
+
+// updateUser creates or updates the user described by the claims and syncs
+// the roles that are managed by the configured role mappings.
+func (um *OIDCUserManager) updateUser(claims oidcClaims) (*user.DBUser, error) {
+	username := claims.getString(um.conf.UsernameClaim)
+	if username == "" {
+		return nil, errors.Errorf("ID token has no '%s' claim", um.conf.UsernameClaim)
+	}
+
+	u, err := model.GetOrCreateUser(username, claims.getString(um.conf.DisplayNameClaim), claims.getString(um.conf.EmailClaim))
+	if err != nil {
+		return nil, err
+	}
+
+	granted := um.mapRoles(claims)
+	for _, mapping := range um.conf.RoleMappings {
+		for _, r := range mapping.Roles {
+			hasRole := util.StringSliceContains(u.Roles(), r)
+			shouldHaveRole := util.StringSliceContains(granted, r)
+			if shouldHaveRole && !hasRole {
+				err = u.AddRole(r)
+			} else if !shouldHaveRole && hasRole {
+				err = u.RemoveRole(r)
+			}
+			if err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	return u, nil
+}
+
+// mapRoles returns the Evergreen roles granted by the values of the roles
+// claim.
+func (um *OIDCUserManager) mapRoles(claims oidcClaims) []string {
+	if um.conf.RolesClaim == "" {
+		return nil
+	}
+
+	values := claims.getStrings(um.conf.RolesClaim)
+	roles := []string{}
+	for _, mapping := range um.conf.RoleMappings {
+		if !util.StringSliceContains(values, mapping.ClaimValue) {
+			continue
+		}
+		for _, r := range mapping.Roles {
+			if !util.StringSliceContains(roles, r) {
+				roles = append(roles, r)
+			}
+		}
+	}
+
+	return roles
+}
+
+// verifyTokenResponse verifies the ID token in a token response and returns
+// its claims and expiration. If nonce is empty, the token's nonce is not
+// checked, as providers don't include one in refreshed tokens.
+func (um *OIDCUserManager) verifyTokenResponse(ctx context.Context, token *oauth2.Token, nonce string) (oidcClaims, time.Time, error) {
+	idToken, ok := token.Extra("id_token").(string)
+	if !ok || idToken == "" {
+		return nil, time.Time{}, errors.New("token response has no ID token")
+	}
+
+	claims, err := um.verifyIDToken(ctx, idToken, nonce, time.Now())
+	if err != nil {
+		return nil, time.Time{}, errors.Wrap(err, "invalid ID token")
+	}
+
+	exp, _ := claims["exp"].(float64)
+	return claims, time.Unix(int64(exp), 0), nil
+}
+
+func (um *OIDCUserManager) verifyIDToken(ctx context.Context, idToken, nonce string, now time.Time) (oidcClaims, error) {
+	parts := strings.Split(idToken, ".")
+	if len(parts) != 3 {
+		return nil, errors.New("token must have 3 parts")
+	}
+
+	header := jws.Header{}
+	if err := decodeJWTSegment(parts[0], &header); err != nil {
+		return nil, errors.Wrap(err, "problem decoding header")
+	}
+	if header.Algorithm != "RS256" {
+		return nil, errors.Errorf("unsupported signing algorithm '%s'", header.Algorithm)
+	}
+	key, err := um.signingKey(ctx, header.KeyID)
+	if err != nil {
+		return nil, err
+	}
+	if err = jws.Verify(idToken, key); err != nil {
+		return nil, errors.Wrap(err, "invalid signature")
+	}
+
+	claims := oidcClaims{}
+	if err = decodeJWTSegment(parts[1], &claims); err != nil {
+		return nil, errors.Wrap(err, "problem decoding claims")
+	}
+
+	discovery, err := um.getDiscovery(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if claims.getString("iss") != discovery.Issuer {
+		return nil, errors.Errorf("unexpected issuer '%s'", claims.getString("iss"))
+	}
+	if !util.StringSliceContains(claims.getStrings("aud"), um.conf.ClientId) {
+		return nil, errors.New("token is not intended for this client")
+	}
+	exp, ok := claims["exp"].(float64)
+	if !ok || !now.Before(time.Unix(int64(exp), 0)) {
+		return nil, errors.New("token has expired")
+	}
+	if nonce != "" && subtle.ConstantTimeCompare([]byte(claims.getString("nonce")), []byte(nonce)) != 1 {
+		return nil, errors.New("token nonce does not match")
+	}
+
+	return claims, nil
+}
+
+// signingKey returns the provider's key with the given id, fetching the
+// provider's keys again if it isn't known, as providers rotate their keys.
+func (um *OIDCUserManager) signingKey(ctx context.Context, kid string) (*rsa.PublicKey, error) {
+	um.mu.Lock()
+	key, ok := um.findKey(kid)
+	um.mu.Unlock()
+	if ok {
+		return key, nil
+	}
+
+	discovery, err := um.getDiscovery(ctx)
+	if err != nil {
+		return nil, err
+	}
+	keys := struct {
+		Keys []struct {
+			Kty string `json:"kty"`
+			Kid string `json:"kid"`
+			Use string `json:"use"`
+			N   string `json:"n"`
+			E   string `json:"e"`
+		} `json:"keys"`
+	}{}
+	if err = um.getJSON(ctx, discovery.JWKSURI, &keys); err != nil {
+		return nil, errors.Wrap(err, "problem fetching provider keys")
+	}
+
+	parsed := map[string]*rsa.PublicKey{}
+	for _, k := range keys.Keys {
+		if k.Kty != "RSA" || (k.Use != "" && k.Use != "sig") {
+			continue
+		}
+		n, nErr := base64.RawURLEncoding.DecodeString(k.N)
+		e, eErr := base64.RawURLEncoding.DecodeString(k.E)
+		if nErr != nil || eErr != nil {
+			continue
+		}
+		parsed[k.Kid] = &rsa.PublicKey{
+			N: new(big.Int).SetBytes(n),
+			E: int(new(big.Int).SetBytes(e).Int64()),
+		}
+	}
+
+	um.mu.Lock()
+	defer um.mu.Unlock()
+	um.keys = parsed
+	if key, ok = um.findKey(kid); !ok {
+		return nil, errors.Errorf("provider has no signing key '%s'", kid)
+	}
+
+	return key, nil
+}
+
+// findKey must be called with the lock held. A token without a key id may
+// be verified with the provider's only key.
+func (um *OIDCUserManager) findKey(kid string) (*rsa.PublicKey, bool) {
+	if kid == "" && len(um.keys) == 1 {
+		for _, key := range um.keys {
+			return key, true
+		}
+	}
+	key, ok := um.keys[kid]
+	return key, ok
+}
+
+func (um *OIDCUserManager) getDiscovery(ctx context.Context) (*oidcDiscovery, error) {
+	um.mu.Lock()
+	discovery := um.discovery
+	um.mu.Unlock()
+	if discovery != nil {
+		return discovery, nil
+	}
+
+	discovery = &oidcDiscovery{}
+	if err := um.getJSON(ctx, strings.TrimRight(um.conf.Issuer, "/")+"/.well-known/openid-configuration", discovery); err != nil {
+		return nil, errors.Wrap(err, "problem fetching provider configuration")
+	}
+	if discovery.Issuer != um.conf.Issuer {
+		return nil, errors.Errorf("provider issuer '%s' does not match configured issuer '%s'", discovery.Issuer, um.conf.Issuer)
+	}
+	if discovery.AuthorizationEndpoint == "" || discovery.TokenEndpoint == "" || discovery.JWKSURI == "" {
+		return nil, errors.New("provider configuration is missing endpoints")
+	}
+
+	um.mu.Lock()
+	um.discovery = discovery
+	um.mu.Unlock()
+
+	return discovery, nil
+}
+
+func (um *OIDCUserManager) oauthConfig(ctx context.Context) (*oauth2.Config, error) {
+	discovery, err := um.getDiscovery(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	um.mu.Lock()
+	defer um.mu.Unlock()
+	return &oauth2.Config{
+		ClientID:     um.conf.ClientId,
+		ClientSecret: um.conf.ClientSecret,
+		RedirectURL:  um.callbackURI,
+		Scopes:       um.conf.Scopes,
+		Endpoint: oauth2.Endpoint{
+			AuthURL:  discovery.AuthorizationEndpoint,
+			TokenURL: discovery.TokenEndpoint,
+		},
+	}, nil
+}
+
+func (um *OIDCUserManager) clientContext(ctx context.Context) context.Context {
+	return context.WithValue(ctx, oauth2.HTTPClient, um.client)
+}
+
+func (um *OIDCUserManager) getJSON(ctx context.Context, url string, out interface{}) error {
+	req, err := http.NewRequest(http.MethodGet, url, nil)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	resp, err := um.client.Do(req.WithContext(ctx))
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return errors.Errorf("received status %s from '%s'", resp.Status, url)
+	}
+
+	return errors.Wrapf(json.NewDecoder(resp.Body).Decode(out), "problem parsing response from '%s'", url)
+}
+
+func decodeJWTSegment(segment string, out interface{}) error {
+	data, err := base64.RawURLEncoding.DecodeString(strings.TrimRight(segment, "="))
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	return errors.WithStack(json.Unmarshal(data, out))
+}
+
+// oidcNonce derives the nonce sent to the provider from the login state, so
+// that an ID token can only be used by the browser that started the login.
+func oidcNonce(state string) string {
+	sum := sha256.Sum256([]byte(state))
+	return hex.EncodeToString(sum[:])
+}
+
+func (c oidcClaims) getString(name string) string {
+	s, _ := c[name].(string)
+	return s
+}
+
+// getStrings returns the values of a claim that may be either a string or a
+// list of strings.
+func (c oidcClaims) getStrings(name string) []string {
+	switch v := c[name].(type) {
+	case string:
+		return []string{v}
+	case []interface{}:
+		out := []string{}
+		for _, item := range v {
+			if s, ok := item.(string); ok {
+				out = append(out, s)
+			}
+		}
+		return out
+	default:
+		return nil
+	}
+}
@@ -0,0 +1,381 @@
+package auth
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/evergreen-ci/evergreen"
+	"github.com/evergreen-ci/evergreen/db"
+	"github.com/evergreen-ci/evergreen/model/user"
+	"github.com/evergreen-ci/evergreen/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/oauth2/jws"
+)
+
+// mockOIDCIssuer is a minimal OpenID Connect provider that issues tokens for
+// a single user.
+type mockOIDCIssuer struct {
+	server   *httptest.Server
+	key      *rsa.PrivateKey
+	clientID string
+	claims   map[string]interface{}
+
+	mu            sync.Mutex
+	codes         map[string]string
+	refreshTokens map[string]bool
+}
+
+func newMockOIDCIssuer(t *testing.T, clientID string) *mockOIDCIssuer {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+
+	m := &mockOIDCIssuer{
+		key:      key,
+		clientID: clientID,
+		claims: map[string]interface{}{
+			"sub":                "annie",
+			"preferred_username": "annie",
+			"name":               "Annie Admin",
+			"email":              "annie@example.com",
+			"groups":             []string{"evergreen-admins", "staff"},
+		},
+		codes:         map[string]string{},
+		refreshTokens: map[string]bool{},
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/.well-known/openid-configuration", func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(w, map[string]string{
+			"issuer":                 m.server.URL,
+			"authorization_endpoint": m.server.URL + "/authorize",
+			"token_endpoint":         m.server.URL + "/token",
+			"jwks_uri":               m.server.URL + "/keys",
+		})
+	})
+	mux.HandleFunc("/keys", func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(w, map[string]interface{}{
+			"keys": []map[string]string{{
+				"kty": "RSA",
+				"kid": "key1",
+				"use": "sig",
+				"n":   base64.RawURLEncoding.EncodeToString(key.N.Bytes()),
+				"e":   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(key.E)).Bytes()),
+			}},
+		})
+	})
+	mux.HandleFunc("/token", func(w http.ResponseWriter, r *http.Request) {
+		id, secret, ok := r.BasicAuth()
+		if !ok {
+			id, secret = r.FormValue("client_id"), r.FormValue("client_secret")
+		}
+		if id != clientID || secret != "secret" {
+			http.Error(w, `{"error":"invalid_client"}`, http.StatusUnauthorized)
+			return
+		}
+
+		m.mu.Lock()
+		defer m.mu.Unlock()
+		var nonce string
+		switch r.FormValue("grant_type") {
+		case "authorization_code":
+			var ok bool
+			nonce, ok = m.codes[r.FormValue("code")]
+			if !ok {
+				http.Error(w, `{"error":"invalid_grant"}`, http.StatusBadRequest)
+				return
+			}
+			delete(m.codes, r.FormValue("code"))
+		case "refresh_token":
+			if !m.refreshTokens[r.FormValue("refresh_token")] {
+				http.Error(w, `{"error":"invalid_grant"}`, http.StatusBadRequest)
+				return
+			}
+		default:
+			http.Error(w, `{"error":"unsupported_grant_type"}`, http.StatusBadRequest)
+			return
+		}
+
+		refreshToken := "refresh-" + time.Now().Format(time.RFC3339Nano)
+		m.refreshTokens[refreshToken] = true
+		writeJSON(w, map[string]interface{}{
+			"access_token":  "access",
+			"token_type":    "Bearer",
+			"expires_in":    3600,
+			"refresh_token": refreshToken,
+			"id_token":      m.idToken(t, nonce, time.Now().Add(time.Hour), "key1"),
+		})
+	})
+	m.server = httptest.NewServer(mux)
+
+	return m
+}
+
+func (m *mockOIDCIssuer) idToken(t *testing.T, nonce string, exp time.Time, kid string) string {
+	claims := map[string]interface{}{}
+	for k, v := range m.claims {
+		claims[k] = v
+	}
+	if nonce != "" {
+		claims["nonce"] = nonce
+	}
+	token, err := jws.Encode(&jws.Header{Algorithm: "RS256", Typ: "JWT", KeyID: kid}, &jws.ClaimSet{
+		Iss:           m.server.URL,
+		Aud:           m.clientID,
+		Sub:           "1234",
+		Iat:           exp.Add(-time.Hour).Unix(),
+		Exp:           exp.Unix(),
+		PrivateClaims: claims,
+	}, m.key)
+	require.NoError(t, err)
+	return token
+}
+
+// authorize simulates the user logging in to the provider, and returns the
+// authorization code the provider would send back.
+func (m *mockOIDCIssuer) authorize(nonce string) string {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	code := "code-" + nonce
+	m.codes[code] = nonce
+	return code
+}
+
+func (m *mockOIDCIssuer) revokeRefreshTokens() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.refreshTokens = map[string]bool{}
+}
+
+func writeJSON(w http.ResponseWriter, data interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(data)
+}
+
+func newTestOIDCManager(t *testing.T, issuer *mockOIDCIssuer) *OIDCUserManager {
+	um, err := NewOIDCUserManager(&evergreen.OIDCConfig{
+		Issuer:       issuer.server.URL,
+		ClientId:     issuer.clientID,
+		ClientSecret: "secret",
+		RolesClaim:   "groups",
+		RoleMappings: []evergreen.OIDCRoleMapping{
+			{ClaimValue: "evergreen-admins", Roles: []string{"admins"}},
+			{ClaimValue: "evergreen-testers", Roles: []string{"testers"}},
+		},
+	})
+	require.NoError(t, err)
+	return um.(*OIDCUserManager)
+}
+
+func TestOIDCUserManagerConfig(t *testing.T) {
+	assert := assert.New(t)
+
+	_, err := NewOIDCUserManager(&evergreen.OIDCConfig{Issuer: "https://idp.example.com"})
+	assert.Error(err)
+
+	_, err = LoadUserManager(evergreen.AuthConfig{
+		OIDC: &evergreen.OIDCConfig{
+			Issuer:       "https://idp.example.com",
+			ClientId:     "client",
+			ClientSecret: "secret",
+		},
+	})
+	assert.NoError(err)
+
+	_, err = LoadUserManager(evergreen.AuthConfig{
+		Naive: &evergreen.NaiveAuthConfig{},
+		OIDC: &evergreen.OIDCConfig{
+			Issuer:       "https://idp.example.com",
+			ClientId:     "client",
+			ClientSecret: "secret",
+		},
+	})
+	assert.Error(err)
+}
+
+func TestOIDCVerifyIDToken(t *testing.T) {
+	assert := assert.New(t)
+	issuer := newMockOIDCIssuer(t, "evergreen")
+	defer issuer.server.Close()
+	um := newTestOIDCManager(t, issuer)
+	ctx := context.Background()
+	now := time.Now()
+
+	claims, err := um.verifyIDToken(ctx, issuer.idToken(t, "nonce", now.Add(time.Hour), "key1"), "nonce", now)
+	assert.NoError(err)
+	assert.Equal("annie", claims.getString("preferred_username"))
+	assert.Equal([]string{"evergreen-admins", "staff"}, claims.getStrings("groups"))
+	assert.Equal([]string{"admins"}, um.mapRoles(claims))
+
+	// nonces are only checked when one is expected
+	_, err = um.verifyIDToken(ctx, issuer.idToken(t, "", now.Add(time.Hour), "key1"), "", now)
+	assert.NoError(err)
+	_, err = um.verifyIDToken(ctx, issuer.idToken(t, "other", now.Add(time.Hour), "key1"), "nonce", now)
+	assert.Error(err)
+
+	// expired tokens aren't valid
+	_, err = um.verifyIDToken(ctx, issuer.idToken(t, "nonce", now.Add(-time.Minute), "key1"), "nonce", now)
+	assert.Error(err)
+
+	// tokens signed with unknown keys aren't valid
+	_, err = um.verifyIDToken(ctx, issuer.idToken(t, "nonce", now.Add(time.Hour), "key2"), "nonce", now)
+	assert.Error(err)
+
+	// tokens for other clients aren't valid
+	issuer.clientID = "other-client"
+	token := issuer.idToken(t, "nonce", now.Add(time.Hour), "key1")
+	issuer.clientID = "evergreen"
+	_, err = um.verifyIDToken(ctx, token, "nonce", now)
+	assert.Error(err)
+
+	// tampered tokens aren't valid
+	token = issuer.idToken(t, "nonce", now.Add(time.Hour), "key1")
+	parts := strings.Split(token, ".")
+	parts[1] = base64.RawURLEncoding.EncodeToString([]byte(`{"iss":"` + issuer.server.URL + `","aud":"evergreen","sub":"root","nonce":"nonce","exp":9999999999}`))
+	_, err = um.verifyIDToken(ctx, strings.Join(parts, "."), "nonce", now)
+	assert.Error(err)
+
+	// unsigned tokens aren't valid
+	parts[0] = base64.RawURLEncoding.EncodeToString([]byte(`{"alg":"none"}`))
+	parts[2] = ""
+	_, err = um.verifyIDToken(ctx, strings.Join(parts, "."), "nonce", now)
+	assert.Error(err)
+}
+
+func TestOIDCLoginHandler(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+	issuer := newMockOIDCIssuer(t, "evergreen")
+	defer issuer.server.Close()
+	um := newTestOIDCManager(t, issuer)
+
+	rw := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodGet, "/login/redirect?redirect=/waterfall", nil)
+	um.GetLoginHandler("https://evergreen.example.com/")(rw, req)
+	require.Equal(http.StatusFound, rw.Code)
+
+	location, err := url.Parse(rw.Header().Get("Location"))
+	require.NoError(err)
+	assert.Equal(issuer.server.URL+"/authorize", location.Scheme+"://"+location.Host+location.Path)
+	query := location.Query()
+	assert.Equal("evergreen", query.Get("client_id"))
+	assert.Equal("https://evergreen.example.com/login/redirect/callback", query.Get("redirect_uri"))
+	assert.Equal("code", query.Get("response_type"))
+	assert.Contains(query.Get("scope"), "openid")
+
+	cookies := rw.Result().Cookies()
+	require.Len(cookies, 1)
+	session, err := url.ParseQuery(cookies[0].Value)
+	require.NoError(err)
+	assert.Equal(query.Get("state"), session.Get("state"))
+	assert.Equal(oidcNonce(session.Get("state")), query.Get("nonce"))
+	assert.Equal("/waterfall", session.Get("redirect"))
+
+	// the callback rejects a state that doesn't match the cookie
+	rw = httptest.NewRecorder()
+	req = httptest.NewRequest(http.MethodGet, "/login/redirect/callback?code=code&state=forged", nil)
+	req.AddCookie(cookies[0])
+	um.GetLoginCallbackHandler()(rw, req)
+	assert.Equal(http.StatusBadRequest, rw.Code)
+
+	rw = httptest.NewRecorder()
+	req = httptest.NewRequest(http.MethodGet, "/login/redirect/callback?code=code&state="+session.Get("state"), nil)
+	um.GetLoginCallbackHandler()(rw, req)
+	assert.Equal(http.StatusBadRequest, rw.Code)
+}
+
+func TestOIDCLoginAndRefresh(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+	db.SetGlobalSessionProvider(testutil.TestConfig().SessionFactory())
+	require.NoError(db.Clear(user.Collection))
+
+	existing := &user.DBUser{Id: "annie", SystemRoles: []string{"testers", "other"}}
+	require.NoError(existing.Insert())
+
+	issuer := newMockOIDCIssuer(t, "evergreen")
+	defer issuer.server.Close()
+	um := newTestOIDCManager(t, issuer)
+
+	// start the login
+	rw := httptest.NewRecorder()
+	um.GetLoginHandler("https://evergreen.example.com")(rw, httptest.NewRequest(http.MethodGet, "/login/redirect?redirect=/waterfall", nil))
+	require.Equal(http.StatusFound, rw.Code)
+	stateCookie := rw.Result().Cookies()[0]
+	location, err := url.Parse(rw.Header().Get("Location"))
+	require.NoError(err)
+	state := location.Query().Get("state")
+	code := issuer.authorize(location.Query().Get("nonce"))
+
+	// finish the login
+	rw = httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodGet, "/login/redirect/callback?code="+code+"&state="+state, nil)
+	req.AddCookie(stateCookie)
+	um.GetLoginCallbackHandler()(rw, req)
+	require.Equal(http.StatusFound, rw.Code)
+	assert.Equal("/waterfall", rw.Header().Get("Location"))
+
+	var loginToken string
+	for _, c := range rw.Result().Cookies() {
+		if c.Name == evergreen.AuthTokenCookie {
+			loginToken = c.Value
+		}
+	}
+	require.NotEmpty(loginToken)
+
+	// the user is updated from the ID token's claims, and mapped roles are
+	// synced without affecting other roles
+	u, err := um.GetUserByToken(context.Background(), loginToken)
+	require.NoError(err)
+	dbUser := u.(*user.DBUser)
+	assert.Equal("annie", dbUser.Id)
+	assert.Equal("Annie Admin", dbUser.DisplayName())
+	assert.Equal("annie@example.com", dbUser.Email())
+	assert.Contains(dbUser.Roles(), "admins")
+	assert.Contains(dbUser.Roles(), "other")
+	assert.NotContains(dbUser.Roles(), "testers")
+
+	// codes can't be reused
+	rw = httptest.NewRecorder()
+	req = httptest.NewRequest(http.MethodGet, "/login/redirect/callback?code="+code+"&state="+state, nil)
+	req.AddCookie(stateCookie)
+	um.GetLoginCallbackHandler()(rw, req)
+	assert.Equal(http.StatusUnauthorized, rw.Code)
+
+	// expired sessions are refreshed with the provider
+	issuer.claims["groups"] = []string{"evergreen-testers"}
+	cache := dbUser.LoginCache
+	cache.TTL = time.Now().Add(-time.Minute)
+	require.NoError(dbUser.SetLoginCache(cache))
+	u, err = um.GetUserByToken(context.Background(), loginToken)
+	require.NoError(err)
+	dbUser = u.(*user.DBUser)
+	assert.True(dbUser.LoginCache.TTL.After(time.Now()))
+	assert.NotEqual(cache.RefreshToken, dbUser.LoginCache.RefreshToken)
+	assert.Contains(dbUser.Roles(), "testers")
+	assert.NotContains(dbUser.Roles(), "admins")
+
+	// sessions that the provider won't refresh are invalid
+	issuer.revokeRefreshTokens()
+	cache = dbUser.LoginCache
+	cache.TTL = time.Now().Add(-time.Minute)
+	require.NoError(dbUser.SetLoginCache(cache))
+	_, err = um.GetUserByToken(context.Background(), loginToken)
+	assert.Error(err)
+
+	_, err = um.GetUserByToken(context.Background(), "forged")
+	assert.Error(err)
+	_, err = um.GetUserByToken(context.Background(), "")
+	assert.Error(err)
+}
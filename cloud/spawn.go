@@ -31,7 +31,6 @@ var passwordRegexps = []*regexp.Regexp{
 }
 
 const (
-	MaxSpawnHostsPerUser                = 3
 	DefaultSpawnHostExpiration          = 24 * time.Hour
 	MaxSpawnHostExpirationDurationHours = 24 * time.Hour * 7 // 7 days
 )
@@ -46,8 +45,8 @@ type SpawnOptions struct {
 }
 
 // Validate returns an instance of BadOptionsErr if the SpawnOptions object contains invalid
-// data, SpawnLimitErr if the user is already at the spawned host limit, or some other untyped
-// instance of Error if something fails during validation.
+// data, or some other untyped instance of Error if something fails during validation. The
+// user's spawn host quota is checked by the REST data layer.
 func (so *SpawnOptions) validate() error {
 	if so.Owner == nil {
 		return errors.New("spawn options include nil user")
@@ -62,17 +61,6 @@ func (so *SpawnOptions) validate() error {
 		return errors.Errorf("Invalid spawn options: spawning not allowed for distro  %v", so.Distro)
 	}
 
-	// if the user already has too many active spawned hosts, deny the request
-	activeSpawnedHosts, err := host.Find(host.ByUserWithRunningStatus(so.UserName))
-	if err != nil {
-		return errors.Wrap(err, "Error occurred finding user's current hosts")
-	}
-
-	if len(activeSpawnedHosts) >= MaxSpawnHostsPerUser {
-		return errors.Errorf("User is already running the max allowed number of spawn hosts (%d of %d)",
-			len(activeSpawnedHosts), MaxSpawnHostsPerUser)
-	}
-
 	// validate public key
 	rsa := "ssh-rsa"
 	dss := "ssh-dss"
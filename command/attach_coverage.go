@@ -0,0 +1,214 @@
+package command
+
+import (
+	"bufio"
+	"context"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/evergreen-ci/evergreen/model"
+	"github.com/evergreen-ci/evergreen/rest/client"
+	"github.com/evergreen-ci/evergreen/util"
+	"github.com/mitchellh/mapstructure"
+	"github.com/mongodb/grip"
+	"github.com/pkg/errors"
+)
+
+const (
+	coverageFormatLCOV = "lcov"
+	coverageFormatGo   = "gocover"
+)
+
+// attachCoverage reads coverage reports and records the source files that the
+// task executes, so that patches to projects with impact selection enabled
+// only run this task when they change one of those files.
+type attachCoverage struct {
+	// Files are the paths or patterns of the coverage reports.
+	Files []string `mapstructure:"files" plugin:"expand"`
+
+	// Format is the format of the reports, either "lcov" or "gocover" for
+	// Go cover profiles. If unset, it's detected from each report.
+	Format string `mapstructure:"format"`
+
+	// StripPrefix is removed from the paths in the reports to make them
+	// relative to the root of the project's repository, such as the
+	// package path of a Go project followed by a slash.
+	StripPrefix string `mapstructure:"strip_prefix" plugin:"expand"`
+
+	// Optional causes the command to succeed when no reports match.
+	Optional bool `mapstructure:"optional"`
+
+	base
+}
+
+func attachCoverageFactory() Command   { return &attachCoverage{} }
+func (c *attachCoverage) Name() string { return "attach.coverage" }
+
+func (c *attachCoverage) ParseParams(params map[string]interface{}) error {
+	if err := mapstructure.Decode(params, c); err != nil {
+		return errors.Wrapf(err, "error decoding '%s' params", c.Name())
+	}
+
+	if len(c.Files) == 0 {
+		return errors.Errorf("error validating params: must specify at least one "+
+			"coverage file pattern: '%+v'", params)
+	}
+	if c.Format != "" && c.Format != coverageFormatLCOV && c.Format != coverageFormatGo {
+		return errors.Errorf("invalid coverage format '%s', must be '%s' or '%s'",
+			c.Format, coverageFormatLCOV, coverageFormatGo)
+	}
+	return nil
+}
+
+func (c *attachCoverage) Execute(ctx context.Context,
+	comm client.Communicator, logger client.LoggerProducer, conf *model.TaskConfig) error {
+
+	var err error
+
+	if err = util.ExpandValues(c, conf.Expansions); err != nil {
+		err = errors.Wrap(err, "error expanding params")
+		logger.Task().Error(err)
+		return err
+	}
+
+	c.Files, err = util.BuildFileList(conf.WorkDir, c.Files...)
+	if err != nil {
+		err = errors.Wrap(err, "problem building wildcard paths")
+		logger.Task().Error(err)
+		return err
+	}
+
+	if len(c.Files) == 0 {
+		if c.Optional {
+			logger.Task().Warning("no coverage files found")
+			return nil
+		}
+		err = errors.New("expanded file specification had no items")
+		logger.Task().Error(err)
+		return err
+	}
+
+	catcher := grip.NewBasicCatcher()
+	covered := map[string]bool{}
+	for _, fn := range c.Files {
+		if !filepath.IsAbs(fn) {
+			fn = filepath.Join(conf.WorkDir, fn)
+		}
+		catcher.Add(c.readCoverageFile(fn, covered))
+	}
+	if catcher.HasErrors() {
+		err = errors.Wrap(catcher.Resolve(), "encountered errors reading coverage files")
+		logger.Task().Error(err)
+		return err
+	}
+
+	files := make([]string, 0, len(covered))
+	for file := range covered {
+		files = append(files, file)
+	}
+	sort.Strings(files)
+
+	td := client.TaskData{ID: conf.Task.Id, Secret: conf.Task.Secret}
+	if err = comm.SendCoverage(ctx, td, files); err != nil {
+		return errors.Wrap(err, "attach coverage failed")
+	}
+
+	logger.Task().Infof("'%s' attached coverage of %d files to task", c.Name(), len(files))
+	return nil
+}
+
+func (c *attachCoverage) readCoverageFile(fn string, covered map[string]bool) error {
+	file, err := os.Open(fn)
+	if err != nil {
+		return errors.Wrapf(err, "problem opening file '%s'", fn)
+	}
+	defer file.Close()
+
+	files, err := parseCoverage(file, c.Format)
+	if err != nil {
+		return errors.Wrapf(err, "problem parsing coverage file '%s'", fn)
+	}
+	for _, f := range files {
+		covered[strings.TrimPrefix(f, c.StripPrefix)] = true
+	}
+	return nil
+}
+
+// parseCoverage returns the source files with at least one executed line in
+// a coverage report. If format is empty, Go cover profiles are recognized by
+// their mode line and anything else is read as lcov.
+func parseCoverage(r io.Reader, format string) ([]string, error) {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+
+	covered := map[string]bool{}
+	files := []string{}
+	add := func(file string) {
+		if file != "" && !covered[file] {
+			covered[file] = true
+			files = append(files, file)
+		}
+	}
+
+	lcovFile := ""
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" {
+			continue
+		}
+		if format == "" {
+			format = coverageFormatLCOV
+			if strings.HasPrefix(line, "mode:") {
+				format = coverageFormatGo
+			}
+		}
+
+		switch format {
+		case coverageFormatGo:
+			// blocks are "file:startLine.col,endLine.col statements count"
+			if strings.HasPrefix(line, "mode:") {
+				continue
+			}
+			fields := strings.Fields(line)
+			if len(fields) != 3 {
+				return nil, errors.Errorf("malformed cover profile line '%s'", line)
+			}
+			sep := strings.LastIndex(fields[0], ":")
+			count, err := strconv.Atoi(fields[2])
+			if sep < 0 || err != nil {
+				return nil, errors.Errorf("malformed cover profile line '%s'", line)
+			}
+			if count > 0 {
+				add(fields[0][:sep])
+			}
+		default:
+			switch {
+			case strings.HasPrefix(line, "SF:"):
+				lcovFile = strings.TrimPrefix(line, "SF:")
+			case strings.HasPrefix(line, "DA:"):
+				// executed lines are "DA:line,count[,checksum]"
+				parts := strings.Split(strings.TrimPrefix(line, "DA:"), ",")
+				if len(parts) >= 2 {
+					if count, err := strconv.Atoi(parts[1]); err == nil && count > 0 {
+						add(lcovFile)
+					}
+				}
+			case strings.HasPrefix(line, "LH:"):
+				if count, err := strconv.Atoi(strings.TrimPrefix(line, "LH:")); err == nil && count > 0 {
+					add(lcovFile)
+				}
+			case line == "end_of_record":
+				lcovFile = ""
+			}
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, errors.Wrap(err, "problem reading coverage")
+	}
+
+	return files, nil
+}
@@ -0,0 +1,92 @@
+package command
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/evergreen-ci/evergreen/model"
+	"github.com/evergreen-ci/evergreen/model/task"
+	"github.com/evergreen-ci/evergreen/rest/client"
+	"github.com/evergreen-ci/evergreen/util"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseCoverage(t *testing.T) {
+	assert := assert.New(t)
+
+	lcov := `TN:
+SF:/src/app/main.js
+DA:1,1
+DA:2,0
+end_of_record
+SF:/src/app/unused.js
+DA:1,0
+LH:0
+end_of_record
+SF:/src/app/util.js
+LH:3
+end_of_record
+`
+	files, err := parseCoverage(strings.NewReader(lcov), "")
+	assert.NoError(err)
+	assert.Equal([]string{"/src/app/main.js", "/src/app/util.js"}, files)
+
+	profile := `mode: set
+github.com/org/app/main.go:10.2,12.3 2 1
+github.com/org/app/main.go:14.2,15.3 1 0
+github.com/org/app/unused.go:3.2,4.3 1 0
+github.com/org/app/pkg/util.go:3.2,4.3 1 4
+`
+	files, err = parseCoverage(strings.NewReader(profile), "")
+	assert.NoError(err)
+	assert.Equal([]string{"github.com/org/app/main.go", "github.com/org/app/pkg/util.go"}, files)
+
+	_, err = parseCoverage(strings.NewReader("mode: set\nnot a block\n"), coverageFormatGo)
+	assert.Error(err)
+}
+
+func TestAttachCoverage(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	tmpdir, err := ioutil.TempDir("", "evergreen.command.attach_coverage.test")
+	require.NoError(err)
+	defer os.RemoveAll(tmpdir)
+	require.NoError(ioutil.WriteFile(filepath.Join(tmpdir, "a.out"), []byte("mode: count\ngithub.com/org/app/a.go:1.1,2.2 1 3\n"), 0644))
+	require.NoError(ioutil.WriteFile(filepath.Join(tmpdir, "b.out"), []byte("mode: count\ngithub.com/org/app/b/b.go:1.1,2.2 1 1\ngithub.com/org/app/a.go:1.1,2.2 1 1\n"), 0644))
+
+	comm := client.NewMock("http://localhost.com")
+	conf := &model.TaskConfig{
+		Expansions: util.NewExpansions(map[string]string{"prefix": "github.com/org/app/"}),
+		Task:       &task.Task{Id: "t1"},
+		Project:    &model.Project{},
+		WorkDir:    tmpdir,
+	}
+	logger := comm.GetLoggerProducer(ctx, client.TaskData{ID: conf.Task.Id, Secret: conf.Task.Secret})
+
+	cmd := attachCoverageFactory()
+	assert.Error(cmd.ParseParams(map[string]interface{}{}))
+	assert.Error(cmd.ParseParams(map[string]interface{}{"files": []string{"*.out"}, "format": "xml"}))
+	cmd = attachCoverageFactory()
+	require.NoError(cmd.ParseParams(map[string]interface{}{
+		"files":        []string{"*.out"},
+		"strip_prefix": "${prefix}",
+	}))
+	require.NoError(cmd.Execute(ctx, comm, logger, conf))
+	assert.Equal([]string{"a.go", "b/b.go"}, comm.CoveredFiles["t1"])
+
+	cmd = attachCoverageFactory()
+	require.NoError(cmd.ParseParams(map[string]interface{}{"files": []string{"*.info"}}))
+	assert.Error(cmd.Execute(ctx, comm, logger, conf))
+
+	cmd = attachCoverageFactory()
+	require.NoError(cmd.ParseParams(map[string]interface{}{"files": []string{"*.info"}, "optional": true}))
+	assert.NoError(cmd.Execute(ctx, comm, logger, conf))
+}
@@ -95,16 +95,51 @@ func buildHTTPCloneCommand(location *url.URL, branch, dir, token string) ([]stri
 	}, nil
 }
 
+const (
+	gitUsernameEnv = "EVG_GIT_USERNAME"
+	gitPasswordEnv = "EVG_GIT_PASSWORD"
+
+	// gitEnvCredentialHelper answers git's requests for credentials with
+	// the credentials in the environment.
+	gitEnvCredentialHelper = "!f() { echo \"username=${" + gitUsernameEnv + "}\"; echo \"password=${" + gitPasswordEnv + "}\"; }; f"
+)
+
+// shellQuote quotes a string as a single word for the shell.
+func shellQuote(s string) string {
+	return "'" + strings.Replace(s, "'", `'\''`, -1) + "'"
+}
+
 func buildSSHCloneCommand(location, branch, dir string) ([]string, error) {
-	cloneCmd := fmt.Sprintf("git clone '%s' '%s'", location, dir)
+	cmds := []string{}
+	cloneFlags := ""
+
+	// the remotes of projects that aren't on GitHub can include
+	// credentials, which the shell would print with the command, so they're
+	// passed to git in the environment instead
+	u, err := url.Parse(location)
+	if err == nil && (u.Scheme == "https" || u.Scheme == "http") && u.User != nil {
+		password, _ := u.User.Password()
+		cmds = append(cmds,
+			"set +o xtrace",
+			fmt.Sprintf("export %s=%s %s=%s", gitUsernameEnv, shellQuote(u.User.Username()), gitPasswordEnv, shellQuote(password)),
+			"set -o xtrace",
+		)
+		cloneFlags = fmt.Sprintf(" -c credential.helper=%s", shellQuote(gitEnvCredentialHelper))
+		u.User = nil
+		location = u.String()
+	}
+
+	cloneCmd := fmt.Sprintf("git%s clone %s %s", cloneFlags, shellQuote(location), shellQuote(dir))
 	if branch != "" {
-		cloneCmd = fmt.Sprintf("%s --branch '%s'", cloneCmd, branch)
+		cloneCmd = fmt.Sprintf("%s --branch %s", cloneCmd, shellQuote(branch))
+	}
+	cmds = append(cmds, cloneCmd, fmt.Sprintf("cd %s", dir))
+	if cloneFlags != "" {
+		// later fetches need the credentials too
+		cmds = append(cmds, fmt.Sprintf("git config credential.helper %s", shellQuote(gitEnvCredentialHelper)))
 	}
 
-	return []string{
-		cloneCmd,
-		fmt.Sprintf("cd %s", dir),
-	}, nil
+	return cmds, nil
 }
 
 func (c *gitFetchProject) buildCloneCommand(conf *model.TaskConfig) ([]string, error) {
@@ -149,6 +184,17 @@ func (c *gitFetchProject) buildCloneCommand(conf *model.TaskConfig) ([]string, e
 			fmt.Sprintf("git reset --hard %s", conf.GithubPatchData.HeadHash),
 		}...)
 
+	} else if conf.GitlabPatchData.MRNumber != 0 {
+		branchName := fmt.Sprintf("evg-mr-test-%s", util.RandomString())
+
+		gitCommands = append(gitCommands, []string{
+			// GitLab creates a ref called refs/merge-requests/[mr number]/head
+			// in the target project, including for merge requests from forks
+			fmt.Sprintf(`git fetch origin "merge-requests/%d/head:%s"`, conf.GitlabPatchData.MRNumber, branchName),
+			fmt.Sprintf(`git checkout "%s"`, branchName),
+			fmt.Sprintf("git reset --hard %s", conf.GitlabPatchData.HeadHash),
+		}...)
+
 	} else {
 		gitCommands = append(gitCommands,
 			fmt.Sprintf("git reset --hard %s", conf.Task.Revision))
@@ -26,6 +26,7 @@ func init() {
 		"attach.results":                attachResultsFactory,
 		"attach.xunit_results":          xunitResultsFactory,
 		"attach.artifacts":              attachArtifactsFactory,
+		"attach.coverage":               attachCoverageFactory,
 		evergreen.CreateHostCommandName: createHostFactory,
 		"host.list":                     listHostFactory,
 		"expansions.fetch_vars":         fetchVarsFactory,
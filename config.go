@@ -72,6 +72,7 @@ type Settings struct {
 	PluginsNew         util.KeyValuePairSlice    `yaml:"plugins_new" bson:"plugins_new" json:"plugins_new"`
 	PprofPort          string                    `yaml:"pprof_port" bson:"pprof_port" json:"pprof_port"`
 	Providers          CloudProviders            `yaml:"providers" bson:"providers" json:"providers" id:"providers"`
+	RateLimit          RateLimitConfig           `yaml:"rate_limit" bson:"rate_limit" json:"rate_limit" id:"rate_limit"`
 	RepoTracker        RepoTrackerConfig         `yaml:"repotracker" bson:"repotracker" json:"repotracker" id:"repotracker"`
 	Scheduler          SchedulerConfig           `yaml:"scheduler" bson:"scheduler" json:"scheduler" id:"scheduler"`
 	ServiceFlags       ServiceFlags              `bson:"service_flags" json:"service_flags" id:"service_flags"`
@@ -463,6 +464,15 @@ func (s *Settings) GetGithubOauthToken() (string, error) {
 	return "", errors.New("no github token in settings")
 }
 
+func (s *Settings) GetGitlabToken() (string, error) {
+	token, ok := s.Credentials["gitlab"]
+	if ok && token != "" {
+		return token, nil
+	}
+
+	return "", errors.New("no gitlab token in settings")
+}
+
 func GetServiceFlags() (*ServiceFlags, error) {
 	section := ConfigRegistry.GetSection("service_flags")
 	if section == nil {
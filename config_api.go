@@ -1,6 +1,8 @@
 package evergreen
 
 import (
+	"net"
+
 	"github.com/evergreen-ci/evergreen/db"
 	"github.com/pkg/errors"
 	"gopkg.in/mgo.v2/bson"
@@ -10,6 +12,11 @@ import (
 type APIConfig struct {
 	HttpListenAddr      string `bson:"http_listen_addr" json:"http_listen_addr" yaml:"httplistenaddr"`
 	GithubWebhookSecret string `bson:"github_webhook_secret" json:"github_webhook_secret" yaml:"github_webhook_secret"`
+	GitlabWebhookSecret string `bson:"gitlab_webhook_secret" json:"gitlab_webhook_secret" yaml:"gitlab_webhook_secret"`
+	// TrustedProxies are the addresses or CIDR ranges of the reverse
+	// proxies in front of the service. Client addresses are only read from
+	// the X-Forwarded-For header of requests made through them.
+	TrustedProxies []string `bson:"trusted_proxies" json:"trusted_proxies" yaml:"trusted_proxies"`
 }
 
 func (c *APIConfig) SectionId() string { return "api" }
@@ -28,9 +35,21 @@ func (c *APIConfig) Set() error {
 		"$set": bson.M{
 			"http_listen_addr":      c.HttpListenAddr,
 			"github_webhook_secret": c.GithubWebhookSecret,
+			"gitlab_webhook_secret": c.GitlabWebhookSecret,
+			"trusted_proxies":       c.TrustedProxies,
 		},
 	})
 	return errors.Wrapf(err, "error updating section %s", c.SectionId())
 }
 
-func (c *APIConfig) ValidateAndDefault() error { return nil }
+func (c *APIConfig) ValidateAndDefault() error {
+	for _, proxy := range c.TrustedProxies {
+		if net.ParseIP(proxy) != nil {
+			continue
+		}
+		if _, _, err := net.ParseCIDR(proxy); err != nil {
+			return errors.Errorf("trusted proxy '%s' is not an IP address or CIDR range", proxy)
+		}
+	}
+	return nil
+}
@@ -39,11 +39,35 @@ type GithubAuthConfig struct {
 	Organization string   `bson:"organization" json:"organization" yaml:"organization"`
 }
 
+// OIDCConfig holds settings for authenticating users with an OpenID Connect
+// provider using the authorization code flow. The claims of a user's ID token
+// named by the claim settings are used to populate the user, and the values of
+// the RolesClaim are mapped to Evergreen roles by RoleMappings.
+type OIDCConfig struct {
+	Issuer           string            `bson:"issuer" json:"issuer" yaml:"issuer"`
+	ClientId         string            `bson:"client_id" json:"client_id" yaml:"client_id"`
+	ClientSecret     string            `bson:"client_secret" json:"client_secret" yaml:"client_secret"`
+	Scopes           []string          `bson:"scopes" json:"scopes" yaml:"scopes"`
+	UsernameClaim    string            `bson:"username_claim" json:"username_claim" yaml:"username_claim"`
+	DisplayNameClaim string            `bson:"display_name_claim" json:"display_name_claim" yaml:"display_name_claim"`
+	EmailClaim       string            `bson:"email_claim" json:"email_claim" yaml:"email_claim"`
+	RolesClaim       string            `bson:"roles_claim" json:"roles_claim" yaml:"roles_claim"`
+	RoleMappings     []OIDCRoleMapping `bson:"role_mappings" json:"role_mappings" yaml:"role_mappings"`
+}
+
+// OIDCRoleMapping gives users whose roles claim contains ClaimValue the
+// Evergreen roles in Roles.
+type OIDCRoleMapping struct {
+	ClaimValue string   `bson:"claim_value" json:"claim_value" yaml:"claim_value"`
+	Roles      []string `bson:"roles" json:"roles" yaml:"roles"`
+}
+
 // AuthConfig has a pointer to either a CrowConfig or a NaiveAuthConfig.
 type AuthConfig struct {
 	Crowd  *CrowdConfig      `bson:"crowd" json:"crowd" yaml:"crowd"`
 	Naive  *NaiveAuthConfig  `bson:"naive" json:"naive" yaml:"naive"`
 	Github *GithubAuthConfig `bson:"github" json:"github" yaml:"github"`
+	OIDC   *OIDCConfig       `bson:"oidc" json:"oidc" yaml:"oidc"`
 }
 
 func (c *AuthConfig) SectionId() string { return "auth" }
@@ -63,6 +87,7 @@ func (c *AuthConfig) Set() error {
 			"crowd":  c.Crowd,
 			"naive":  c.Naive,
 			"github": c.Github,
+			"oidc":   c.OIDC,
 		},
 	})
 	return errors.Wrapf(err, "error updating section %s", c.SectionId())
@@ -70,7 +95,7 @@ func (c *AuthConfig) Set() error {
 
 func (c *AuthConfig) ValidateAndDefault() error {
 	catcher := grip.NewSimpleCatcher()
-	if c.Crowd == nil && c.Naive == nil && c.Github == nil {
+	if c.Crowd == nil && c.Naive == nil && c.Github == nil && c.OIDC == nil {
 		catcher.Add(errors.New("You must specify one form of authentication"))
 	}
 	if c.Naive != nil {
@@ -87,5 +112,52 @@ func (c *AuthConfig) ValidateAndDefault() error {
 			catcher.Add(errors.New("Must specify either a set of users or an organization for Github Authentication"))
 		}
 	}
+	if c.OIDC != nil {
+		catcher.Add(c.OIDC.ValidateAndDefault())
+	}
+	return catcher.Resolve()
+}
+
+func (c *OIDCConfig) ValidateAndDefault() error {
+	catcher := grip.NewSimpleCatcher()
+	if c.Issuer == "" {
+		catcher.Add(errors.New("Must specify an issuer for OpenID Connect authentication"))
+	}
+	if c.ClientId == "" || c.ClientSecret == "" {
+		catcher.Add(errors.New("Must specify a client id and secret for OpenID Connect authentication"))
+	}
+	for _, m := range c.RoleMappings {
+		if m.ClaimValue == "" || len(m.Roles) == 0 {
+			catcher.Add(errors.New("OpenID Connect role mappings must have a claim value and roles"))
+		}
+	}
+	if len(c.RoleMappings) > 0 && c.RolesClaim == "" {
+		catcher.Add(errors.New("Must specify a roles claim to map OpenID Connect claims to roles"))
+	}
+
+	hasOpenID := false
+	for _, scope := range c.Scopes {
+		if scope == "openid" {
+			hasOpenID = true
+		}
+	}
+	if !hasOpenID {
+		c.Scopes = append([]string{"openid"}, c.Scopes...)
+	}
+	if len(c.Scopes) == 1 {
+		c.Scopes = append(c.Scopes, "profile", "email")
+	}
+	// The username becomes the user's id, so default to the subject, which
+	// users can't change at the identity provider.
+	if c.UsernameClaim == "" {
+		c.UsernameClaim = "sub"
+	}
+	if c.DisplayNameClaim == "" {
+		c.DisplayNameClaim = "name"
+	}
+	if c.EmailClaim == "" {
+		c.EmailClaim = "email"
+	}
+
 	return catcher.Resolve()
 }
@@ -0,0 +1,168 @@
+package evergreen
+
+import (
+	"strings"
+
+	"github.com/evergreen-ci/evergreen/db"
+	"github.com/evergreen-ci/evergreen/util"
+	"github.com/mongodb/grip"
+	"github.com/pkg/errors"
+	"gopkg.in/mgo.v2/bson"
+)
+
+const (
+	// DefaultRateLimitRouteGroup is the route group of REST routes that
+	// aren't in any configured group.
+	DefaultRateLimitRouteGroup = "default"
+
+	// DefaultMaxSpawnHostsPerUser is the number of spawn hosts a user can
+	// have running at once when no quota is configured.
+	DefaultMaxSpawnHostsPerUser = 3
+)
+
+// RateLimitConfig holds the limits on how much users can use the REST API,
+// and quotas on the resources they can create. Zero values mean that there
+// is no limit, except for spawn hosts, which have a default quota.
+type RateLimitConfig struct {
+	// Default is the rate limit of routes that aren't in any route group.
+	Default RateLimit `bson:"default" json:"default" yaml:"default"`
+	// RouteGroups have their own rate limits, which each user has a
+	// separate allowance for.
+	RouteGroups []RouteGroupRateLimit `bson:"route_groups" json:"route_groups" yaml:"route_groups"`
+	// ExemptUsers are not rate limited and have no quotas.
+	ExemptUsers []string `bson:"exempt_users" json:"exempt_users" yaml:"exempt_users"`
+
+	MaxPatchesPerDay     int `bson:"max_patches_per_day" json:"max_patches_per_day" yaml:"max_patches_per_day"`
+	MaxSpawnHostsPerUser int `bson:"max_spawn_hosts_per_user" json:"max_spawn_hosts_per_user" yaml:"max_spawn_hosts_per_user"`
+}
+
+// RateLimit is a token bucket limit: users can make Burst requests at once,
+// and their allowance refills at RequestsPerMinute.
+type RateLimit struct {
+	RequestsPerMinute int `bson:"requests_per_minute" json:"requests_per_minute" yaml:"requests_per_minute"`
+	Burst             int `bson:"burst" json:"burst" yaml:"burst"`
+}
+
+// RouteGroupRateLimit is the rate limit of the REST v2 routes whose paths,
+// relative to /rest/v2, start with any of the prefixes.
+type RouteGroupRateLimit struct {
+	Name         string    `bson:"name" json:"name" yaml:"name"`
+	PathPrefixes []string  `bson:"path_prefixes" json:"path_prefixes" yaml:"path_prefixes"`
+	Limit        RateLimit `bson:"limit" json:"limit" yaml:"limit"`
+}
+
+func (c *RateLimitConfig) SectionId() string { return "rate_limit" }
+
+func (c *RateLimitConfig) Get() error {
+	err := db.FindOneQ(ConfigCollection, db.Query(byId(c.SectionId())), c)
+	if err != nil && err.Error() == errNotFound {
+		*c = RateLimitConfig{}
+		return nil
+	}
+	return errors.Wrapf(err, "error retrieving section %s", c.SectionId())
+}
+
+func (c *RateLimitConfig) Set() error {
+	_, err := db.Upsert(ConfigCollection, byId(c.SectionId()), bson.M{
+		"$set": bson.M{
+			"default":                  c.Default,
+			"route_groups":             c.RouteGroups,
+			"exempt_users":             c.ExemptUsers,
+			"max_patches_per_day":      c.MaxPatchesPerDay,
+			"max_spawn_hosts_per_user": c.MaxSpawnHostsPerUser,
+		},
+	})
+	return errors.Wrapf(err, "error updating section %s", c.SectionId())
+}
+
+func (c *RateLimitConfig) ValidateAndDefault() error {
+	catcher := grip.NewSimpleCatcher()
+	catcher.Add(errors.Wrap(c.Default.validateAndDefault(), "invalid default rate limit"))
+
+	names := map[string]bool{DefaultRateLimitRouteGroup: true}
+	for i := range c.RouteGroups {
+		group := &c.RouteGroups[i]
+		if group.Name == "" {
+			catcher.Add(errors.New("route groups must have a name"))
+		} else if names[group.Name] {
+			catcher.Add(errors.Errorf("duplicate route group name '%s'", group.Name))
+		}
+		names[group.Name] = true
+
+		if len(group.PathPrefixes) == 0 {
+			catcher.Add(errors.Errorf("route group '%s' must have at least one path prefix", group.Name))
+		}
+		for _, prefix := range group.PathPrefixes {
+			if !strings.HasPrefix(prefix, "/") {
+				catcher.Add(errors.Errorf("path prefix '%s' of route group '%s' must start with '/'", prefix, group.Name))
+			}
+		}
+		catcher.Add(errors.Wrapf(group.Limit.validateAndDefault(), "invalid rate limit for route group '%s'", group.Name))
+	}
+
+	if c.MaxPatchesPerDay < 0 {
+		catcher.Add(errors.New("max patches per day cannot be negative"))
+	}
+	if c.MaxSpawnHostsPerUser < 0 {
+		catcher.Add(errors.New("max spawn hosts per user cannot be negative"))
+	}
+
+	return catcher.Resolve()
+}
+
+func (l *RateLimit) validateAndDefault() error {
+	if l.RequestsPerMinute < 0 || l.Burst < 0 {
+		return errors.New("requests per minute and burst cannot be negative")
+	}
+	if l.RequestsPerMinute > 0 && l.Burst == 0 {
+		l.Burst = l.RequestsPerMinute
+	}
+
+	return nil
+}
+
+// IsZero returns whether the limit doesn't restrict anything.
+func (l RateLimit) IsZero() bool { return l.RequestsPerMinute == 0 }
+
+// LimitForPath returns the route group of the REST v2 path, which is
+// relative to /rest/v2, and the group's rate limit. The group with the
+// longest matching prefix wins.
+func (c *RateLimitConfig) LimitForPath(path string) (string, RateLimit) {
+	group := DefaultRateLimitRouteGroup
+	limit := c.Default
+	longest := 0
+	for _, g := range c.RouteGroups {
+		for _, prefix := range g.PathPrefixes {
+			if len(prefix) > longest && strings.HasPrefix(path, prefix) {
+				group = g.Name
+				limit = g.Limit
+				longest = len(prefix)
+			}
+		}
+	}
+
+	return group, limit
+}
+
+// IsExempt returns whether the user has no rate limits or quotas.
+func (c *RateLimitConfig) IsExempt(user string) bool {
+	return util.StringSliceContains(c.ExemptUsers, user)
+}
+
+// SpawnHostQuota returns the number of spawn hosts each user can have
+// running at once.
+func (c *RateLimitConfig) SpawnHostQuota() int {
+	if c.MaxSpawnHostsPerUser == 0 {
+		return DefaultMaxSpawnHostsPerUser
+	}
+	return c.MaxSpawnHostsPerUser
+}
+
+// GetRateLimitConfig retrieves the rate limit and quota settings.
+func GetRateLimitConfig() (*RateLimitConfig, error) {
+	config := &RateLimitConfig{}
+	if err := config.Get(); err != nil {
+		return nil, err
+	}
+	return config, nil
+}
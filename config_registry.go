@@ -41,6 +41,7 @@ func resetRegistry() error {
 		&JiraConfig{},
 		&LoggerConfig{},
 		&NotifyConfig{},
+		&RateLimitConfig{},
 		&RepoTrackerConfig{},
 		&SchedulerConfig{},
 		&ServiceFlags{},
@@ -11,6 +11,10 @@ type RepoTrackerConfig struct {
 	NumNewRepoRevisionsToFetch int `bson:"revs_to_fetch" json:"revs_to_fetch" yaml:"numnewreporevisionstofetch"`
 	MaxRepoRevisionsToSearch   int `bson:"max_revs_to_search" json:"max_revs_to_search" yaml:"maxreporevisionstosearch"`
 	MaxConcurrentRequests      int `bson:"max_con_requests" json:"max_con_requests" yaml:"maxconcurrentrequests"`
+
+	// MirrorDirectory holds the local mirrors of the repositories of
+	// projects that aren't on GitHub.
+	MirrorDirectory string `bson:"mirror_directory" json:"mirror_directory" yaml:"mirror_directory"`
 }
 
 func (c *RepoTrackerConfig) SectionId() string { return "repotracker" }
@@ -30,6 +34,7 @@ func (c *RepoTrackerConfig) Set() error {
 			"revs_to_fetch":      c.NumNewRepoRevisionsToFetch,
 			"max_revs_to_search": c.MaxRepoRevisionsToSearch,
 			"max_con_requests":   c.MaxConcurrentRequests,
+			"mirror_directory":   c.MirrorDirectory,
 		},
 	})
 	return errors.Wrapf(err, "error updating section %s", c.SectionId())
@@ -11,11 +11,11 @@ type ServiceFlags struct {
 	TaskDispatchDisabled         bool `bson:"task_dispatch_disabled" json:"task_dispatch_disabled"`
 	HostinitDisabled             bool `bson:"hostinit_disabled" json:"hostinit_disabled"`
 	MonitorDisabled              bool `bson:"monitor_disabled" json:"monitor_disabled"`
-	AlertsDisabled               bool `bson:"alerts_disabled" json:"alerts_disabled"`
 	TaskrunnerDisabled           bool `bson:"taskrunner_disabled" json:"taskrunner_disabled"`
 	RepotrackerDisabled          bool `bson:"repotracker_disabled" json:"repotracker_disabled"`
 	SchedulerDisabled            bool `bson:"scheduler_disabled" json:"scheduler_disabled"`
 	GithubPRTestingDisabled      bool `bson:"github_pr_testing_disabled" json:"github_pr_testing_disabled"`
+	GitlabMRTestingDisabled      bool `bson:"gitlab_mr_testing_disabled" json:"gitlab_mr_testing_disabled"`
 	RepotrackerPushEventDisabled bool `bson:"repotracker_push_event_disabled" json:"repotracker_push_event_disabled"`
 	CLIUpdatesDisabled           bool `bson:"cli_updates_disabled" json:"cli_updates_disabled"`
 	BackgroundStatsDisabled      bool `bson:"background_stats_disabled" json:"background_stats_disabled"`
@@ -28,6 +28,7 @@ type ServiceFlags struct {
 	EmailNotificationsDisabled   bool `bson:"email_notifications_disabled" json:"email_notifications_disabled"`
 	WebhookNotificationsDisabled bool `bson:"webhook_notifications_disabled" json:"webhook_notifications_disabled"`
 	GithubStatusAPIDisabled      bool `bson:"github_status_api_disabled" json:"github_status_api_disabled"`
+	GitlabStatusAPIDisabled      bool `bson:"gitlab_status_api_disabled" json:"gitlab_status_api_disabled"`
 }
 
 func (c *ServiceFlags) SectionId() string { return "service_flags" }
@@ -47,11 +48,11 @@ func (c *ServiceFlags) Set() error {
 			taskDispatchKey:                 c.TaskDispatchDisabled,
 			hostinitKey:                     c.HostinitDisabled,
 			monitorKey:                      c.MonitorDisabled,
-			alertsKey:                       c.AlertsDisabled,
 			taskrunnerKey:                   c.TaskrunnerDisabled,
 			repotrackerKey:                  c.RepotrackerDisabled,
 			schedulerKey:                    c.SchedulerDisabled,
 			githubPRTestingDisabledKey:      c.GithubPRTestingDisabled,
+			gitlabMRTestingDisabledKey:      c.GitlabMRTestingDisabled,
 			repotrackerPushEventDisabledKey: c.RepotrackerPushEventDisabled,
 			cliUpdatesDisabledKey:           c.CLIUpdatesDisabled,
 			backgroundStatsDisabledKey:      c.BackgroundStatsDisabled,
@@ -61,6 +62,7 @@ func (c *ServiceFlags) Set() error {
 			emailNotificationsDisabledKey:   c.EmailNotificationsDisabled,
 			webhookNotificationsDisabledKey: c.WebhookNotificationsDisabled,
 			githubStatusAPIDisabledKey:      c.GithubStatusAPIDisabled,
+			gitlabStatusAPIDisabledKey:      c.GitlabStatusAPIDisabled,
 			taskLoggingDisabledKey:          c.TaskLoggingDisabled,
 		},
 	})
@@ -230,6 +230,8 @@ func (s *AdminSuite) TestApiConfig() {
 	config := APIConfig{
 		HttpListenAddr:      "addr",
 		GithubWebhookSecret: "secret",
+		GitlabWebhookSecret: "gitlab secret",
+		TrustedProxies:      []string{"10.0.0.0/8"},
 	}
 
 	err := config.Set()
@@ -256,6 +258,17 @@ func (s *AdminSuite) TestAuthConfig() {
 			Users:        []string{"ghuser"},
 			Organization: "ghorg",
 		},
+		OIDC: &OIDCConfig{
+			Issuer:        "https://idp.example.com",
+			ClientId:      "oidcclient",
+			ClientSecret:  "oidcsecret",
+			Scopes:        []string{"openid", "email"},
+			UsernameClaim: "preferred_username",
+			RolesClaim:    "groups",
+			RoleMappings: []OIDCRoleMapping{
+				{ClaimValue: "evergreen-admins", Roles: []string{"admins"}},
+			},
+		},
 	}
 
 	err := config.Set()
@@ -339,6 +352,7 @@ func (s *AdminSuite) TestRepotrackerConfig() {
 		NumNewRepoRevisionsToFetch: 10,
 		MaxRepoRevisionsToSearch:   20,
 		MaxConcurrentRequests:      30,
+		MirrorDirectory:            "/data/mirrors",
 	}
 
 	err := config.Set()
@@ -557,6 +571,28 @@ func (s *AdminSuite) TestContainerPoolsConfig() {
 	s.Nil(lookup)
 }
 
+func (s *AdminSuite) TestRateLimitConfig() {
+	config := RateLimitConfig{
+		Default: RateLimit{RequestsPerMinute: 600, Burst: 100},
+		RouteGroups: []RouteGroupRateLimit{
+			{Name: "hosts", PathPrefixes: []string{"/hosts"}, Limit: RateLimit{RequestsPerMinute: 60, Burst: 10}},
+		},
+		ExemptUsers:          []string{"robot"},
+		MaxPatchesPerDay:     50,
+		MaxSpawnHostsPerUser: 5,
+	}
+
+	s.NoError(config.Set())
+	settings, err := GetConfig()
+	s.NoError(err)
+	s.NotNil(settings)
+	s.Equal(config, settings.RateLimit)
+
+	limits, err := GetRateLimitConfig()
+	s.NoError(err)
+	s.Equal(config, *limits)
+}
+
 func (s *AdminSuite) TestJIRANotificationsConfig() {
 	c := JIRANotificationsConfig{
 		CustomFields: JIRACustomFieldsByProject{
@@ -617,3 +653,88 @@ func (s *AdminSuite) TestJIRANotificationsConfig() {
 	}
 	s.EqualError(c.ValidateAndDefault(), "template: this-is:1: unexpected \"}\" in operand")
 }
+
+func TestOIDCConfigValidateAndDefault(t *testing.T) {
+	assert := assert.New(t)
+
+	c := OIDCConfig{
+		Issuer:       "https://idp.example.com",
+		ClientId:     "client",
+		ClientSecret: "secret",
+	}
+	assert.NoError(c.ValidateAndDefault())
+	assert.Equal([]string{"openid", "profile", "email"}, c.Scopes)
+	assert.Equal("sub", c.UsernameClaim)
+	assert.Equal("name", c.DisplayNameClaim)
+	assert.Equal("email", c.EmailClaim)
+
+	c = OIDCConfig{
+		Issuer:       "https://idp.example.com",
+		ClientId:     "client",
+		ClientSecret: "secret",
+		Scopes:       []string{"email", "groups"},
+	}
+	assert.NoError(c.ValidateAndDefault())
+	assert.Equal([]string{"openid", "email", "groups"}, c.Scopes)
+
+	c.RoleMappings = []OIDCRoleMapping{{ClaimValue: "admins", Roles: []string{"admin"}}}
+	assert.Error(c.ValidateAndDefault())
+	c.RolesClaim = "groups"
+	assert.NoError(c.ValidateAndDefault())
+	c.RoleMappings = append(c.RoleMappings, OIDCRoleMapping{ClaimValue: "users"})
+	assert.Error(c.ValidateAndDefault())
+
+	c = OIDCConfig{ClientId: "client"}
+	assert.Error(c.ValidateAndDefault())
+}
+
+func TestRateLimitConfig(t *testing.T) {
+	assert := assert.New(t)
+
+	c := RateLimitConfig{}
+	assert.NoError(c.ValidateAndDefault())
+	assert.Equal(DefaultMaxSpawnHostsPerUser, c.SpawnHostQuota())
+	group, limit := c.LimitForPath("/hosts")
+	assert.Equal(DefaultRateLimitRouteGroup, group)
+	assert.True(limit.IsZero())
+
+	c = RateLimitConfig{
+		Default: RateLimit{RequestsPerMinute: 600},
+		RouteGroups: []RouteGroupRateLimit{
+			{Name: "hosts", PathPrefixes: []string{"/hosts"}, Limit: RateLimit{RequestsPerMinute: 60, Burst: 10}},
+			{Name: "spawn", PathPrefixes: []string{"/hosts/spawn", "/users"}, Limit: RateLimit{RequestsPerMinute: 6}},
+		},
+		ExemptUsers:          []string{"robot"},
+		MaxSpawnHostsPerUser: 5,
+	}
+	assert.NoError(c.ValidateAndDefault())
+	assert.Equal(600, c.Default.Burst)
+	assert.Equal(6, c.RouteGroups[1].Limit.Burst)
+	assert.Equal(5, c.SpawnHostQuota())
+	assert.True(c.IsExempt("robot"))
+	assert.False(c.IsExempt("me"))
+
+	group, limit = c.LimitForPath("/hosts/h1")
+	assert.Equal("hosts", group)
+	assert.Equal(60, limit.RequestsPerMinute)
+	group, _ = c.LimitForPath("/hosts/spawn/x")
+	assert.Equal("spawn", group)
+	group, _ = c.LimitForPath("/users/me/hosts")
+	assert.Equal("spawn", group)
+	group, limit = c.LimitForPath("/tasks/t1")
+	assert.Equal(DefaultRateLimitRouteGroup, group)
+	assert.Equal(600, limit.RequestsPerMinute)
+
+	for _, invalid := range []RateLimitConfig{
+		{Default: RateLimit{RequestsPerMinute: -1}},
+		{RouteGroups: []RouteGroupRateLimit{{PathPrefixes: []string{"/hosts"}}}},
+		{RouteGroups: []RouteGroupRateLimit{{Name: DefaultRateLimitRouteGroup, PathPrefixes: []string{"/hosts"}}}},
+		{RouteGroups: []RouteGroupRateLimit{{Name: "a", PathPrefixes: []string{"/a"}}, {Name: "a", PathPrefixes: []string{"/b"}}}},
+		{RouteGroups: []RouteGroupRateLimit{{Name: "a"}}},
+		{RouteGroups: []RouteGroupRateLimit{{Name: "a", PathPrefixes: []string{"hosts"}}}},
+		{MaxPatchesPerDay: -1},
+		{MaxSpawnHostsPerUser: -1},
+	} {
+		assert.Error(invalid.ValidateAndDefault())
+	}
+}
@@ -10,6 +10,7 @@ import (
 const (
 	User            = "mci"
 	GithubPatchUser = "github_pull_request"
+	GitlabPatchUser = "gitlab_merge_request"
 
 	HostRunning         = "running"
 	HostTerminated      = "terminated"
@@ -96,6 +97,7 @@ const (
 
 	DefaultTaskActivator   = ""
 	StepbackTaskActivator  = "stepback"
+	BisectTaskActivator    = "bisect"
 	APIServerTaskActivator = "apiserver"
 
 	RestRoutePrefix = "rest"
@@ -173,7 +175,10 @@ const (
 	// version requester types
 	PatchVersionRequester       = "patch_request"
 	GithubPRRequester           = "github_pull_request"
+	GitlabMRRequester           = "gitlab_merge_request"
 	RepotrackerVersionRequester = "gitter_request"
+	ScheduledVersionRequester   = "scheduled_request"
+	TriggerRequester            = "trigger_request"
 )
 
 const (
@@ -209,6 +214,7 @@ var (
 	PatchRequesters = []string{
 		PatchVersionRequester,
 		GithubPRRequester,
+		GitlabMRRequester,
 	}
 
 	// UphostStatus is a list of all host statuses that are considered "up."
@@ -255,5 +261,6 @@ func IsSystemActivator(caller string) bool {
 }
 
 func IsPatchRequester(requester string) bool {
-	return requester == PatchVersionRequester || requester == GithubPRRequester
+	return requester == PatchVersionRequester || requester == GithubPRRequester ||
+		requester == GitlabMRRequester
 }
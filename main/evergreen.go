@@ -49,12 +49,14 @@ func buildApp() *cli.App {
 
 		// Top-level commands.
 		operations.Keys(),
+		operations.Tokens(),
 		operations.Fetch(),
 		operations.Evaluate(),
 		operations.Validate(),
 		operations.List(),
 		operations.TestHistory(),
 		operations.LastGreen(),
+		operations.Watch(),
 		operations.Subscriptions(),
 
 		// Patch creation and management commands (top-level)
@@ -64,6 +66,7 @@ func buildApp() *cli.App {
 		operations.PatchSetModule(),
 		operations.PatchRemoveModule(),
 		operations.PatchFinalize(),
+		operations.PatchRebase(),
 		operations.PatchCancel(),
 	}
 
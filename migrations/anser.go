@@ -98,6 +98,8 @@ func (opts Options) Application(env anser.Environment, evgEnv evergreen.Environm
 		migrationAdminMapRestructure:           adminMapRestructureGenerator,
 		migrationSpawnhostExpirationPreference: setSpawnhostPreferenceGenerator,
 		migrationDistroSecurityGroups:          distroSecurityGroupsGenerator,
+		migrationProjectAlertsToSubscriptions:  projectAlertsToSubscriptionsGenerator,
+		migrationSuperUserProvisioningAlerts:   superUserProvisioningAlertsGenerator(evgEnv.Settings().SuperUsers),
 	}
 	catcher := grip.NewBasicCatcher()
 
@@ -0,0 +1,213 @@
+package migrations
+
+import (
+	"crypto/sha1"
+	"encoding/hex"
+	"fmt"
+	"strings"
+
+	"github.com/mongodb/anser"
+	"github.com/mongodb/anser/db"
+	"github.com/mongodb/anser/model"
+	"github.com/mongodb/grip"
+	"github.com/mongodb/grip/message"
+	"github.com/pkg/errors"
+	"gopkg.in/mgo.v2/bson"
+)
+
+const migrationProjectAlertsToSubscriptions = "project-alerts-to-subscriptions"
+
+// legacyAlertTriggers maps the ids of the legacy alert triggers to the
+// equivalent task triggers
+var legacyAlertTriggers = map[string]string{
+	"task_failed":             "failure",
+	"first_version_failure":   "first-failure-in-version",
+	"first_variant_failure":   "first-failure-in-build",
+	"first_tasktype_failure":  "first-failure-in-version-with-name",
+	"task_transition_failure": "regression",
+}
+
+type legacyAlertConfig struct {
+	Provider string `bson:"provider"`
+	Settings bson.M `bson:"settings"`
+}
+
+type projectSubscription struct {
+	ID         string          `bson:"_id"`
+	Type       string          `bson:"type"`
+	Trigger    string          `bson:"trigger"`
+	Selectors  []emailSelector `bson:"selectors"`
+	Subscriber struct {
+		Type   string      `bson:"type"`
+		Target interface{} `bson:"target"`
+	} `bson:"subscriber"`
+	OwnerType string `bson:"owner_type"`
+	Owner     string `bson:"owner"`
+}
+
+func projectAlertsToSubscriptionsGenerator(env anser.Environment, args migrationGeneratorFactoryOptions) (anser.Generator, error) {
+	const projectRefCollection = "project_ref"
+
+	if err := env.RegisterManualMigrationOperation(migrationProjectAlertsToSubscriptions, makeProjectAlertsMigration(args.db)); err != nil {
+		return nil, err
+	}
+
+	opts := model.GeneratorOptions{
+		NS: model.Namespace{
+			DB:         args.db,
+			Collection: projectRefCollection,
+		},
+		Limit: args.limit,
+		Query: bson.M{
+			"alert_settings": bson.M{
+				"$exists": true,
+			},
+		},
+		JobID: args.id,
+	}
+
+	return anser.NewManualMigrationGenerator(env, opts, migrationProjectAlertsToSubscriptions), nil
+}
+
+func makeProjectAlertsMigration(database string) db.MigrationOperation {
+	const (
+		projectRefCollection = "project_ref"
+		idKey                = "_id"
+		identifierKey        = "identifier"
+		alertSettingsKey     = "alert_settings"
+	)
+
+	return func(session db.Session, rawD bson.RawD) error {
+		defer session.Close()
+
+		var id interface{}
+		identifier := ""
+		alerts := map[string][]legacyAlertConfig{}
+		for _, raw := range rawD {
+			switch raw.Name {
+			case idKey:
+				if err := raw.Value.Unmarshal(&id); err != nil {
+					return errors.Wrap(err, "error unmarshaling id")
+				}
+			case identifierKey:
+				if err := raw.Value.Unmarshal(&identifier); err != nil {
+					return errors.Wrap(err, "error unmarshaling identifier")
+				}
+			case alertSettingsKey:
+				if err := raw.Value.Unmarshal(&alerts); err != nil {
+					return errors.Wrap(err, "error unmarshaling alert settings")
+				}
+			}
+		}
+
+		catcher := grip.NewSimpleCatcher()
+		for legacyTrigger, configs := range alerts {
+			trigger, ok := legacyAlertTriggers[legacyTrigger]
+			if !ok {
+				grip.Warning(message.Fields{
+					"message":   "skipping unknown legacy alert trigger",
+					"migration": migrationProjectAlertsToSubscriptions,
+					"project":   identifier,
+					"trigger":   legacyTrigger,
+				})
+				continue
+			}
+
+			for _, config := range configs {
+				sub, err := makeProjectAlertSubscription(identifier, trigger, config)
+				if err != nil {
+					catcher.Add(errors.Wrapf(err, "error converting '%s' alert for project '%s'", legacyTrigger, identifier))
+					continue
+				}
+				_, err = session.DB(database).C(subscriptionCollection).UpsertId(sub.ID, sub)
+				catcher.Add(err)
+			}
+		}
+
+		if catcher.HasErrors() {
+			return catcher.Resolve()
+		}
+
+		return session.DB(database).C(projectRefCollection).UpdateId(id,
+			bson.M{
+				"$unset": bson.M{
+					alertSettingsKey: 1,
+				},
+			})
+	}
+}
+
+// migratedSubscriptionID returns an ID in the format of subscription IDs
+// that is the same each time a migration creates the same subscription, so
+// that rerunning a migration after a partial failure doesn't duplicate the
+// subscriptions it already created.
+func migratedSubscriptionID(migration string, parts ...interface{}) string {
+	key := migration
+	for _, part := range parts {
+		key += fmt.Sprintf("\x00%v", part)
+	}
+	sum := sha1.Sum([]byte(key))
+	return hex.EncodeToString(sum[:12])
+}
+
+// makeProjectAlertSubscription creates a project subscription equivalent
+// to a legacy alert config. Like the subscriptions created from the
+// project page, it matches mainline tasks in the project.
+func makeProjectAlertSubscription(project, trigger string, config legacyAlertConfig) (*projectSubscription, error) {
+	sub := &projectSubscription{
+		Type:    "TASK",
+		Trigger: trigger,
+		Selectors: []emailSelector{
+			{Type: "project", Data: project},
+			{Type: "requester", Data: "gitter_request"},
+		},
+		OwnerType: "project",
+		Owner:     project,
+	}
+
+	setting := func(key string) string {
+		val, _ := config.Settings[key].(string)
+		return val
+	}
+
+	switch config.Provider {
+	case "email":
+		recipient := setting("recipient")
+		if recipient == "" {
+			recipient = setting("rcpt")
+		}
+		if recipient == "" {
+			return nil, errors.New("email alert has no recipient")
+		}
+		sub.Subscriber.Type = "email"
+		sub.Subscriber.Target = recipient
+
+	case "jira":
+		project, issueType := setting("project"), setting("issue")
+		if project == "" || issueType == "" {
+			return nil, errors.New("jira alert must have a project and issue type")
+		}
+		sub.Subscriber.Type = "jira-issue"
+		sub.Subscriber.Target = bson.M{
+			"project":    project,
+			"issue_type": issueType,
+		}
+
+	case "slack":
+		channel := setting("channel")
+		if channel == "" {
+			return nil, errors.New("slack alert has no channel")
+		}
+		if !strings.HasPrefix(channel, "#") && !strings.HasPrefix(channel, "@") {
+			channel = "#" + channel
+		}
+		sub.Subscriber.Type = "slack"
+		sub.Subscriber.Target = channel
+
+	default:
+		return nil, errors.Errorf("unknown alert provider '%s'", config.Provider)
+	}
+	sub.ID = migratedSubscriptionID(migrationProjectAlertsToSubscriptions, project, trigger, sub.Subscriber.Type, sub.Subscriber.Target)
+
+	return sub, nil
+}
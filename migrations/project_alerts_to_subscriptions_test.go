@@ -0,0 +1,155 @@
+package migrations
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+
+	"github.com/evergreen-ci/evergreen"
+	evgdb "github.com/evergreen-ci/evergreen/db"
+	"github.com/evergreen-ci/evergreen/mock"
+	"github.com/evergreen-ci/evergreen/model/event"
+	"github.com/evergreen-ci/evergreen/testutil"
+	"github.com/mongodb/anser"
+	"github.com/mongodb/anser/db"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+	"gopkg.in/mgo.v2/bson"
+)
+
+type projectAlertsMigration struct {
+	env      *mock.Environment
+	session  db.Session
+	database string
+	cancel   func()
+	suite.Suite
+}
+
+func TestProjectAlertsMigration(t *testing.T) {
+	require := require.New(t)
+
+	mgoSession, database, err := evgdb.GetGlobalSessionFactory().GetSession()
+	require.NoError(err)
+	defer mgoSession.Close()
+
+	session := db.WrapSession(mgoSession.Copy())
+	defer session.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	s := &projectAlertsMigration{
+		env:      &mock.Environment{},
+		session:  session,
+		database: database.Name,
+		cancel:   cancel,
+	}
+
+	require.NoError(s.env.Configure(ctx, filepath.Join(evergreen.FindEvergreenHome(), testutil.TestDir, testutil.TestSettings), nil))
+	require.NoError(s.env.LocalQueue().Start(ctx))
+
+	anser.ResetEnvironment()
+	require.NoError(anser.GetEnvironment().Setup(s.env.LocalQueue(), s.session))
+	anser.GetEnvironment().RegisterCloser(func() error { cancel(); return nil })
+
+	suite.Run(t, s)
+}
+
+func (s *projectAlertsMigration) SetupTest() {
+	s.NoError(evgdb.ClearCollections("project_ref", subscriptionCollection))
+
+	data := bson.M{
+		"_id":        bson.NewObjectId(),
+		"identifier": "mci",
+		"alert_settings": bson.M{
+			"task_failed": []bson.M{
+				{
+					"provider": "email",
+					"settings": bson.M{"recipient": "someone@example.com"},
+				},
+			},
+			"task_transition_failure": []bson.M{
+				{
+					"provider": "jira",
+					"settings": bson.M{"project": "EVG", "issue": "Build Failure"},
+				},
+				{
+					"provider": "slack",
+					"settings": bson.M{"channel": "evergreen"},
+				},
+			},
+		},
+	}
+	s.NoError(evgdb.Insert("project_ref", data))
+	s.NoError(evgdb.Insert("project_ref", bson.M{
+		"_id":        bson.NewObjectId(),
+		"identifier": "no-alerts",
+	}))
+}
+
+func (s *projectAlertsMigration) TestMigration() {
+	args := migrationGeneratorFactoryOptions{
+		db:    s.database,
+		limit: 50,
+		id:    "migration-project-alerts-to-subscriptions",
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	gen, err := projectAlertsToSubscriptionsGenerator(anser.GetEnvironment(), args)
+	s.NoError(err)
+	gen.Run(ctx)
+	s.NoError(gen.Error())
+
+	i := 0
+	for j := range gen.Jobs() {
+		i++
+		j.Run(ctx)
+		s.NoError(j.Error())
+	}
+	s.Equal(1, i)
+
+	subs, err := event.FindSubscriptionsByOwner("mci", event.OwnerTypeProject)
+	s.NoError(err)
+	s.Require().Len(subs, 3)
+
+	byTarget := map[string]event.Subscription{}
+	for _, sub := range subs {
+		s.NoError(sub.Validate())
+		s.Equal(event.ResourceTypeTask, sub.Type)
+		s.Contains(sub.Selectors, event.Selector{Type: "project", Data: "mci"})
+		byTarget[sub.Subscriber.Type] = sub
+	}
+
+	s.Equal("failure", byTarget[event.EmailSubscriberType].Trigger)
+	s.Equal("someone@example.com", *byTarget[event.EmailSubscriberType].Subscriber.Target.(*string))
+
+	s.Equal("regression", byTarget[event.JIRAIssueSubscriberType].Trigger)
+	jira := byTarget[event.JIRAIssueSubscriberType].Subscriber.Target.(*event.JIRAIssueSubscriber)
+	s.Equal("EVG", jira.Project)
+	s.Equal("Build Failure", jira.IssueType)
+
+	s.Equal("regression", byTarget[event.SlackSubscriberType].Trigger)
+	s.Equal("#evergreen", *byTarget[event.SlackSubscriberType].Subscriber.Target.(*string))
+
+	out := []bson.M{}
+	s.NoError(evgdb.FindAllQ("project_ref", evgdb.Query(bson.M{"alert_settings": bson.M{"$exists": true}}), &out))
+	s.Empty(out)
+}
+
+func (s *projectAlertsMigration) TestMigrationIsIdempotent() {
+	var doc bson.RawD
+	s.Require().NoError(s.session.DB(s.database).C("project_ref").Find(bson.M{"identifier": "mci"}).One(&doc))
+
+	// a rerun after a partial failure sees the same alert settings again
+	migrate := makeProjectAlertsMigration(s.database)
+	s.NoError(migrate(s.session.Copy(), doc))
+	s.NoError(migrate(s.session.Copy(), doc))
+
+	subs, err := event.FindSubscriptionsByOwner("mci", event.OwnerTypeProject)
+	s.NoError(err)
+	s.Len(subs, 3)
+}
+
+func (s *projectAlertsMigration) TearDownSuite() {
+	s.cancel()
+}
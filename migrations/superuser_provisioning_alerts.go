@@ -0,0 +1,96 @@
+package migrations
+
+import (
+	"github.com/mongodb/anser"
+	"github.com/mongodb/anser/db"
+	"github.com/mongodb/anser/model"
+	"github.com/pkg/errors"
+	"gopkg.in/mgo.v2/bson"
+)
+
+const migrationSuperUserProvisioningAlerts = "superuser-provisioning-alerts-to-subscriptions"
+
+type personSubscription struct {
+	ID         string          `bson:"_id"`
+	Type       string          `bson:"type"`
+	Trigger    string          `bson:"trigger"`
+	Selectors  []emailSelector `bson:"selectors"`
+	Subscriber emailSubscriber `bson:"subscriber"`
+	OwnerType  string          `bson:"owner_type"`
+	Owner      string          `bson:"owner"`
+}
+
+// superUserProvisioningAlertsGenerator subscribes each of the superusers
+// to emails about hosts that fail to provision, which the legacy alerts
+// sent them.
+func superUserProvisioningAlertsGenerator(superUsers []string) migrationGeneratorFactory {
+	return func(env anser.Environment, args migrationGeneratorFactoryOptions) (anser.Generator, error) {
+		if err := env.RegisterManualMigrationOperation(migrationSuperUserProvisioningAlerts, makeSuperUserProvisioningAlertsMigration(args.db)); err != nil {
+			return nil, err
+		}
+
+		if superUsers == nil {
+			superUsers = []string{}
+		}
+		opts := model.GeneratorOptions{
+			NS: model.Namespace{
+				DB:         args.db,
+				Collection: userCollection,
+			},
+			Limit: args.limit,
+			Query: bson.M{
+				"_id": bson.M{
+					"$in": superUsers,
+				},
+			},
+			JobID: args.id,
+		}
+
+		return anser.NewManualMigrationGenerator(env, opts, migrationSuperUserProvisioningAlerts), nil
+	}
+}
+
+func makeSuperUserProvisioningAlertsMigration(database string) db.MigrationOperation {
+	const (
+		idKey    = "_id"
+		emailKey = "email"
+	)
+
+	return func(session db.Session, rawD bson.RawD) error {
+		defer session.Close()
+
+		var userID, email string
+		for _, raw := range rawD {
+			switch raw.Name {
+			case idKey:
+				if err := raw.Value.Unmarshal(&userID); err != nil {
+					return errors.Wrap(err, "error unmarshaling id")
+				}
+			case emailKey:
+				if err := raw.Value.Unmarshal(&email); err != nil {
+					return errors.Wrap(err, "error unmarshaling email")
+				}
+			}
+		}
+		if email == "" {
+			return errors.Errorf("superuser '%s' has no email address", userID)
+		}
+
+		sub := personSubscription{
+			ID:      migratedSubscriptionID(migrationSuperUserProvisioningAlerts, userID),
+			Type:    "HOST",
+			Trigger: "provision-failed",
+			Selectors: []emailSelector{
+				{Type: "object", Data: "host"},
+			},
+			Subscriber: emailSubscriber{
+				Type:   "email",
+				Target: email,
+			},
+			OwnerType: "person",
+			Owner:     userID,
+		}
+		_, err := session.DB(database).C(subscriptionCollection).UpsertId(sub.ID, sub)
+		return errors.Wrapf(err, "error creating provisioning subscription for superuser '%s'", userID)
+	}
+}
@@ -0,0 +1,116 @@
+package migrations
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+
+	"github.com/evergreen-ci/evergreen"
+	evgdb "github.com/evergreen-ci/evergreen/db"
+	"github.com/evergreen-ci/evergreen/mock"
+	"github.com/evergreen-ci/evergreen/model/event"
+	"github.com/evergreen-ci/evergreen/testutil"
+	"github.com/mongodb/anser"
+	"github.com/mongodb/anser/db"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+	"gopkg.in/mgo.v2/bson"
+)
+
+type superUserProvisioningAlertsSuite struct {
+	env      *mock.Environment
+	session  db.Session
+	database string
+	cancel   func()
+	suite.Suite
+}
+
+func TestSuperUserProvisioningAlertsMigration(t *testing.T) {
+	require := require.New(t)
+
+	mgoSession, database, err := evgdb.GetGlobalSessionFactory().GetSession()
+	require.NoError(err)
+	defer mgoSession.Close()
+
+	session := db.WrapSession(mgoSession.Copy())
+	defer session.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	s := &superUserProvisioningAlertsSuite{
+		env:      &mock.Environment{},
+		session:  session,
+		database: database.Name,
+		cancel:   cancel,
+	}
+
+	require.NoError(s.env.Configure(ctx, filepath.Join(evergreen.FindEvergreenHome(), testutil.TestDir, testutil.TestSettings), nil))
+	require.NoError(s.env.LocalQueue().Start(ctx))
+
+	anser.ResetEnvironment()
+	require.NoError(anser.GetEnvironment().Setup(s.env.LocalQueue(), s.session))
+	anser.GetEnvironment().RegisterCloser(func() error { cancel(); return nil })
+
+	suite.Run(t, s)
+}
+
+func (s *superUserProvisioningAlertsSuite) SetupTest() {
+	s.NoError(evgdb.ClearCollections(userCollection, subscriptionCollection))
+
+	s.NoError(evgdb.Insert(userCollection, bson.M{"_id": "admin", "email": "admin@example.com"}))
+	s.NoError(evgdb.Insert(userCollection, bson.M{"_id": "regular", "email": "regular@example.com"}))
+}
+
+func (s *superUserProvisioningAlertsSuite) TestMigration() {
+	args := migrationGeneratorFactoryOptions{
+		db:    s.database,
+		limit: 50,
+		id:    "migration-superuser-provisioning-alerts",
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	gen, err := superUserProvisioningAlertsGenerator([]string{"admin"})(anser.GetEnvironment(), args)
+	s.NoError(err)
+	gen.Run(ctx)
+	s.NoError(gen.Error())
+
+	i := 0
+	for j := range gen.Jobs() {
+		i++
+		j.Run(ctx)
+		s.NoError(j.Error())
+	}
+	s.Equal(1, i)
+
+	subs, err := event.FindSubscriptionsByOwner("admin", event.OwnerTypePerson)
+	s.NoError(err)
+	s.Require().Len(subs, 1)
+	s.NoError(subs[0].Validate())
+	s.Equal(event.ResourceTypeHost, subs[0].Type)
+	s.Equal("provision-failed", subs[0].Trigger)
+	s.Equal([]event.Selector{{Type: "object", Data: "host"}}, subs[0].Selectors)
+	s.Equal(event.EmailSubscriberType, subs[0].Subscriber.Type)
+	s.Equal("admin@example.com", *subs[0].Subscriber.Target.(*string))
+
+	subs, err = event.FindSubscriptionsByOwner("regular", event.OwnerTypePerson)
+	s.NoError(err)
+	s.Empty(subs)
+}
+
+func (s *superUserProvisioningAlertsSuite) TestMigrationIsIdempotent() {
+	var doc bson.RawD
+	s.Require().NoError(s.session.DB(s.database).C(userCollection).FindId("admin").One(&doc))
+
+	migrate := makeSuperUserProvisioningAlertsMigration(s.database)
+	s.NoError(migrate(s.session.Copy(), doc))
+	s.NoError(migrate(s.session.Copy(), doc))
+
+	subs, err := event.FindSubscriptionsByOwner("admin", event.OwnerTypePerson)
+	s.NoError(err)
+	s.Len(subs, 1)
+}
+
+func (s *superUserProvisioningAlertsSuite) TearDownSuite() {
+	s.cancel()
+}
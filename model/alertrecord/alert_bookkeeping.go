@@ -18,20 +18,17 @@ const (
 	FirstVariantFailureId  = "first_variant_failure"
 	FirstTaskTypeFailureId = "first_tasktype_failure"
 	TaskFailTransitionId   = "task_transition_failure"
-	// TODO: EVG-3408
-	TaskFailedId                    = "task_failed"
-	LastRevisionNotFound            = "last_revision_not_found"
+
 	taskRegressionByTest            = "task-regression-by-test"
 	taskRegressionByTestWithNoTests = "task-regression-by-test-with-no-tests"
+	testStartedFailing              = "test-started-failing"
 )
 
 // Host triggers
 const (
-	SpawnFailed                = "spawn_failed"
 	SpawnHostTwoHourWarning    = "spawn_twohour"
 	SpawnHostTwelveHourWarning = "spawn_twelvehour"
-	SlowProvisionWarning       = "slow_provision"
-	ProvisionFailed            = "provision_failed"
+	hostIdle                   = "host_idle"
 )
 
 type AlertRecord struct {
@@ -46,6 +43,7 @@ type AlertRecord struct {
 	Variant             string        `bson:"variant,omitempty"`
 	TestName            string        `bson:"test_name,omitempty"`
 	RevisionOrderNumber int           `bson:"order,omitempty"`
+	SubscriptionID      string        `bson:"subscription_id,omitempty"`
 }
 
 //nolint: deadcode, megacheck
@@ -61,6 +59,7 @@ var (
 	VersionIdKey           = bsonutil.MustHaveTag(AlertRecord{}, "VersionId")
 	testNameKey            = bsonutil.MustHaveTag(AlertRecord{}, "TestName")
 	RevisionOrderNumberKey = bsonutil.MustHaveTag(AlertRecord{}, "RevisionOrderNumber")
+	subscriptionIDKey      = bsonutil.MustHaveTag(AlertRecord{}, "SubscriptionID")
 )
 
 // FindOne gets one AlertRecord for the given query.
@@ -115,14 +114,6 @@ func ByHostAlertRecordType(hostId, triggerId string) db.Q {
 	}).Limit(1)
 }
 
-func ByLastRevNotFound(projectId, versionId string) db.Q {
-	return db.Query(bson.M{
-		TypeKey:      LastRevisionNotFound,
-		ProjectIdKey: projectId,
-		VersionIdKey: versionId,
-	}).Limit(1)
-}
-
 func FindByLastTaskRegressionByTest(testName, taskDisplayName, variant, projectID string, beforeRevision int) (*AlertRecord, error) {
 	return FindOne(db.Query(bson.M{
 		TypeKey:      taskRegressionByTest,
@@ -179,3 +170,56 @@ func InsertNewTaskRegressionByTestWithNoTestsRecord(taskDisplayName, taskStatus,
 
 	return errors.Wrapf(record.Insert(), "failed to insert alert record %s", taskRegressionByTestWithNoTests)
 }
+
+// FindByLastTestStartedFailing finds the most recent record of a test
+// starting to fail at or before the given revision.
+func FindByLastTestStartedFailing(testName, taskDisplayName, variant, projectID string, beforeRevision int) (*AlertRecord, error) {
+	return FindOne(db.Query(bson.M{
+		TypeKey:      testStartedFailing,
+		testNameKey:  testName,
+		TaskNameKey:  taskDisplayName,
+		VariantKey:   variant,
+		ProjectIdKey: projectID,
+		RevisionOrderNumberKey: bson.M{
+			"$lte": beforeRevision,
+		},
+	}).Sort([]string{"-" + RevisionOrderNumberKey}))
+}
+
+func InsertNewTestStartedFailingRecord(testName, taskID, taskDisplayName, variant, projectID string, revision int) error {
+	record := AlertRecord{
+		Id:                  bson.NewObjectId(),
+		Type:                testStartedFailing,
+		ProjectId:           projectID,
+		TaskId:              taskID,
+		TaskName:            taskDisplayName,
+		Variant:             variant,
+		TestName:            testName,
+		RevisionOrderNumber: revision,
+	}
+
+	return errors.Wrapf(record.Insert(), "failed to insert alert record %s", testStartedFailing)
+}
+
+// FindByHostIdle finds the alert record for a subscription that was
+// notified about a host being idle since it finished its last task.
+func FindByHostIdle(subscriptionID, hostID, lastTaskID string) (*AlertRecord, error) {
+	return FindOne(db.Query(bson.M{
+		TypeKey:           hostIdle,
+		subscriptionIDKey: subscriptionID,
+		HostIdKey:         hostID,
+		TaskIdKey:         lastTaskID,
+	}))
+}
+
+func InsertNewHostIdleRecord(subscriptionID, hostID, lastTaskID string) error {
+	record := AlertRecord{
+		Id:             bson.NewObjectId(),
+		Type:           hostIdle,
+		SubscriptionID: subscriptionID,
+		HostId:         hostID,
+		TaskId:         lastTaskID,
+	}
+
+	return errors.Wrapf(record.Insert(), "failed to insert alert record %s", hostIdle)
+}
@@ -0,0 +1,460 @@
+package audit
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"reflect"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/evergreen-ci/evergreen/db"
+	"github.com/mongodb/anser/bsonutil"
+	"github.com/mongodb/grip"
+	"github.com/mongodb/grip/message"
+	"github.com/pkg/errors"
+	"gopkg.in/mgo.v2/bson"
+)
+
+const (
+	Collection = "audit_log"
+
+	// RedactedValue replaces secret values, such as private project
+	// variables, in recorded changes.
+	RedactedValue = "{REDACTED}"
+)
+
+// Entry records a mutating action taken by a user.
+type Entry struct {
+	ID           bson.ObjectId `bson:"_id" json:"id"`
+	Timestamp    time.Time     `bson:"ts" json:"timestamp"`
+	Actor        string        `bson:"actor" json:"actor"`
+	SourceIP     string        `bson:"source_ip" json:"source_ip"`
+	Method       string        `bson:"method" json:"method"`
+	Path         string        `bson:"path" json:"path"`
+	Status       int           `bson:"status" json:"status"`
+	Action       string        `bson:"action" json:"action"`
+	ResourceType string        `bson:"resource_type,omitempty" json:"resource_type,omitempty"`
+	ResourceID   string        `bson:"resource_id,omitempty" json:"resource_id,omitempty"`
+	Changes      []Change      `bson:"changes,omitempty" json:"changes,omitempty"`
+
+	skipped bool
+}
+
+// Change is the before and after value of one field of a resource that an
+// action modified.
+type Change struct {
+	ResourceType string      `bson:"resource_type" json:"resource_type"`
+	ResourceID   string      `bson:"resource_id" json:"resource_id"`
+	Field        string      `bson:"field" json:"field"`
+	Before       interface{} `bson:"before,omitempty" json:"before,omitempty"`
+	After        interface{} `bson:"after,omitempty" json:"after,omitempty"`
+}
+
+var (
+	IDKey           = bsonutil.MustHaveTag(Entry{}, "ID")
+	TimestampKey    = bsonutil.MustHaveTag(Entry{}, "Timestamp")
+	ActorKey        = bsonutil.MustHaveTag(Entry{}, "Actor")
+	ActionKey       = bsonutil.MustHaveTag(Entry{}, "Action")
+	ResourceTypeKey = bsonutil.MustHaveTag(Entry{}, "ResourceType")
+	ResourceIDKey   = bsonutil.MustHaveTag(Entry{}, "ResourceID")
+)
+
+// resourceTypes maps the path segments of REST and UI routes to the type
+// of resource that follows them.
+var resourceTypes = map[string]string{
+	"admin":         "admin",
+	"build":         "build",
+	"builds":        "build",
+	"distro":        "distro",
+	"distros":       "distro",
+	"host":          "host",
+	"hosts":         "host",
+	"patch":         "patch",
+	"patches":       "patch",
+	"project":       "project",
+	"projects":      "project",
+	"roles":         "role",
+	"spawn":         "host",
+	"subscriptions": "subscription",
+	"task":          "task",
+	"tasks":         "task",
+	"user":          "user",
+	"users":         "user",
+	"version":       "version",
+	"versions":      "version",
+}
+
+var methodActions = map[string]string{
+	http.MethodPost:   "update",
+	http.MethodPut:    "update",
+	http.MethodPatch:  "update",
+	http.MethodDelete: "delete",
+}
+
+// IsMutating returns whether requests with the method can modify
+// anything, and should be audited.
+func IsMutating(method string) bool {
+	_, ok := methodActions[method]
+	return ok
+}
+
+// NewEntry creates the entry for a request made by the actor. The resource
+// and action are guessed from the request's path, so that every request is
+// attributed to something, but handlers can set them more precisely. The
+// trusted proxies are the addresses and CIDR ranges of the reverse proxies
+// whose X-Forwarded-For headers can be believed.
+func NewEntry(r *http.Request, actor string, trustedProxies []string) *Entry {
+	e := &Entry{
+		ID:        bson.NewObjectId(),
+		Timestamp: time.Now(),
+		Actor:     actor,
+		SourceIP:  sourceIP(r, trustedProxies),
+		Method:    r.Method,
+		Path:      r.URL.Path,
+	}
+
+	var rest []string
+	segments := strings.Split(strings.Trim(r.URL.Path, "/"), "/")
+	for i := 0; i < len(segments); i++ {
+		resourceType, ok := resourceTypes[segments[i]]
+		if !ok {
+			continue
+		}
+		e.ResourceType = resourceType
+		e.ResourceID = ""
+		rest = nil
+		if i+1 < len(segments) {
+			e.ResourceID = segments[i+1]
+			rest = segments[i+2:]
+			i++
+		}
+	}
+
+	switch {
+	case len(rest) > 0:
+		e.Action = strings.Join(rest, "/")
+	case e.ResourceID == "" && r.Method != http.MethodDelete:
+		e.Action = "create"
+	default:
+		e.Action = methodActions[r.Method]
+	}
+
+	return e
+}
+
+// sourceIP returns the address of the client that made the request. Anyone
+// can set the X-Forwarded-For header, so it's only read for requests that
+// came through a trusted proxy, and the client is the last address in it
+// that isn't another trusted proxy.
+func sourceIP(r *http.Request, trustedProxies []string) string {
+	remote, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		remote = r.RemoteAddr
+	}
+	if !isTrustedProxy(remote, trustedProxies) {
+		return remote
+	}
+
+	forwarded := strings.Split(r.Header.Get("X-Forwarded-For"), ",")
+	for i := len(forwarded) - 1; i >= 0; i-- {
+		addr := strings.TrimSpace(forwarded[i])
+		if addr != "" && !isTrustedProxy(addr, trustedProxies) {
+			return addr
+		}
+	}
+
+	return remote
+}
+
+func isTrustedProxy(addr string, trustedProxies []string) bool {
+	ip := net.ParseIP(addr)
+	if ip == nil {
+		return false
+	}
+	for _, proxy := range trustedProxies {
+		if _, network, err := net.ParseCIDR(proxy); err == nil {
+			if network.Contains(ip) {
+				return true
+			}
+			continue
+		}
+		if proxyIP := net.ParseIP(proxy); proxyIP != nil && proxyIP.Equal(ip) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Insert saves the entry.
+func (e *Entry) Insert() error {
+	return errors.Wrap(db.Insert(Collection, e), "failed to save audit log entry")
+}
+
+// Filter selects entries from the audit log. Zero values match everything.
+// Entries are returned newest first, and Before is the id of the entry
+// after which to start.
+type Filter struct {
+	Actor        string
+	ResourceType string
+	ResourceID   string
+	Action       string
+	Start        time.Time
+	End          time.Time
+	Before       string
+	Limit        int
+}
+
+// Find returns the entries matching the filter, newest first.
+func Find(f Filter) ([]Entry, error) {
+	query := bson.M{}
+	if f.Actor != "" {
+		query[ActorKey] = f.Actor
+	}
+	if f.ResourceType != "" {
+		query[ResourceTypeKey] = f.ResourceType
+	}
+	if f.ResourceID != "" {
+		query[ResourceIDKey] = f.ResourceID
+	}
+	if f.Action != "" {
+		query[ActionKey] = f.Action
+	}
+	ts := bson.M{}
+	if !f.Start.IsZero() {
+		ts["$gte"] = f.Start
+	}
+	if !f.End.IsZero() {
+		ts["$lt"] = f.End
+	}
+	if len(ts) > 0 {
+		query[TimestampKey] = ts
+	}
+	if f.Before != "" {
+		if !bson.IsObjectIdHex(f.Before) {
+			return nil, errors.Errorf("'%s' is not a valid audit log entry id", f.Before)
+		}
+		query[IDKey] = bson.M{"$lt": bson.ObjectIdHex(f.Before)}
+	}
+
+	q := db.Query(query).Sort([]string{"-" + IDKey})
+	if f.Limit > 0 {
+		q = q.Limit(f.Limit)
+	}
+
+	entries := []Entry{}
+	err := db.FindAllQ(Collection, q, &entries)
+	return entries, errors.Wrap(err, "failed to fetch audit log")
+}
+
+// Diff returns the fields that differ between two versions of a resource.
+// Either version may be nil, if the action created or deleted the
+// resource. Nested documents are compared field by field, and changes are
+// named by their dotted path. The values of secrets, and of any fields
+// under the redacted paths, are replaced with RedactedValue.
+func Diff(resourceType, resourceID string, before, after interface{}, redacted ...string) ([]Change, error) {
+	beforeDoc, err := toDocument(before)
+	if err != nil {
+		return nil, errors.Wrap(err, "problem reading resource before change")
+	}
+	afterDoc, err := toDocument(after)
+	if err != nil {
+		return nil, errors.Wrap(err, "problem reading resource after change")
+	}
+
+	changes := []Change{}
+	diffDocuments("", beforeDoc, afterDoc, func(field string, before, after interface{}) {
+		if isRedacted(field, redacted) {
+			before = redactValue(before)
+			after = redactValue(after)
+		} else {
+			before = redactSecrets(before)
+			after = redactSecrets(after)
+		}
+		changes = append(changes, Change{
+			ResourceType: resourceType,
+			ResourceID:   resourceID,
+			Field:        field,
+			Before:       before,
+			After:        after,
+		})
+	})
+
+	return changes, nil
+}
+
+func diffDocuments(prefix string, before, after bson.M, add func(string, interface{}, interface{})) {
+	fields := []string{}
+	for field := range before {
+		fields = append(fields, field)
+	}
+	for field := range after {
+		if _, ok := before[field]; !ok {
+			fields = append(fields, field)
+		}
+	}
+	sort.Strings(fields)
+
+	for _, field := range fields {
+		b, a := before[field], after[field]
+		if reflect.DeepEqual(b, a) {
+			continue
+		}
+
+		beforeDoc, beforeIsDoc := b.(bson.M)
+		afterDoc, afterIsDoc := a.(bson.M)
+		if (beforeIsDoc || b == nil) && (afterIsDoc || a == nil) {
+			diffDocuments(prefix+field+".", beforeDoc, afterDoc, add)
+			continue
+		}
+
+		add(prefix+field, b, a)
+	}
+}
+
+// secretFields are substrings of the names of fields whose values are
+// never recorded. Names are matched regardless of case and underscores, so
+// that "apikey" matches "api_key" and "APIKey".
+var secretFields = []string{"secret", "password", "apikey", "token"}
+
+func isSecret(field string) bool {
+	field = strings.Replace(strings.ToLower(field), "_", "", -1)
+	for _, secret := range secretFields {
+		if strings.Contains(field, secret) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func isRedacted(field string, redacted []string) bool {
+	for _, segment := range strings.Split(field, ".") {
+		if isSecret(segment) {
+			return true
+		}
+	}
+	for _, r := range redacted {
+		if field == r || strings.HasPrefix(field, r+".") {
+			return true
+		}
+	}
+
+	return false
+}
+
+func redactValue(v interface{}) interface{} {
+	if v == nil {
+		return nil
+	}
+
+	return RedactedValue
+}
+
+// redactSecrets replaces the values of secret fields nested anywhere in v.
+func redactSecrets(v interface{}) interface{} {
+	switch val := v.(type) {
+	case bson.M:
+		out := bson.M{}
+		for k, nested := range val {
+			if isSecret(k) {
+				out[k] = redactValue(nested)
+			} else {
+				out[k] = redactSecrets(nested)
+			}
+		}
+		return out
+	case []interface{}:
+		out := make([]interface{}, len(val))
+		for i := range val {
+			out[i] = redactSecrets(val[i])
+		}
+		return out
+	default:
+		return v
+	}
+}
+
+func toDocument(in interface{}) (bson.M, error) {
+	doc := bson.M{}
+	if in == nil || (reflect.ValueOf(in).Kind() == reflect.Ptr && reflect.ValueOf(in).IsNil()) {
+		return doc, nil
+	}
+
+	raw, err := bson.Marshal(in)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	if err = bson.Unmarshal(raw, &doc); err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	return doc, nil
+}
+
+type contextKey int
+
+const entryKey contextKey = 0
+
+// WithEntry attaches the entry for a request to its context, so that
+// handlers can add details to it.
+func WithEntry(ctx context.Context, e *Entry) context.Context {
+	return context.WithValue(ctx, entryKey, e)
+}
+
+// GetEntry returns the entry attached to the context, or nil if the
+// request isn't audited.
+func GetEntry(ctx context.Context) *Entry {
+	e, _ := ctx.Value(entryKey).(*Entry)
+	return e
+}
+
+// SetAction sets the resource and action of the audited request in the
+// context, if there is one.
+func SetAction(ctx context.Context, resourceType, resourceID, action string) {
+	e := GetEntry(ctx)
+	if e == nil {
+		return
+	}
+
+	e.ResourceType = resourceType
+	e.ResourceID = resourceID
+	e.Action = action
+}
+
+// Skip stops the audited request in the context from being recorded. It's
+// for requests that use a mutating method but turn out not to modify
+// anything.
+func Skip(ctx context.Context) {
+	if e := GetEntry(ctx); e != nil {
+		e.skipped = true
+	}
+}
+
+// Skipped returns whether the entry should not be recorded.
+func (e *Entry) Skipped() bool { return e.skipped }
+
+// RecordChange adds the difference between two versions of a resource to
+// the audited request in the context, if there is one. The values of
+// fields under the redacted paths are not recorded. Failing to record a
+// change doesn't fail the action, so errors are only logged.
+func RecordChange(ctx context.Context, resourceType, resourceID string, before, after interface{}, redacted ...string) {
+	e := GetEntry(ctx)
+	if e == nil {
+		return
+	}
+
+	changes, err := Diff(resourceType, resourceID, before, after, redacted...)
+	if err != nil {
+		grip.Warning(message.WrapError(err, message.Fields{
+			"message":       "problem recording change in audit log",
+			"resource_type": resourceType,
+			"resource_id":   resourceID,
+			"path":          e.Path,
+		}))
+		return
+	}
+	e.Changes = append(e.Changes, changes...)
+}
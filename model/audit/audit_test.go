@@ -0,0 +1,262 @@
+package audit
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/evergreen-ci/evergreen/db"
+	"github.com/evergreen-ci/evergreen/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/mgo.v2/bson"
+)
+
+func init() {
+	db.SetGlobalSessionProvider(testutil.TestConfig().SessionFactory())
+}
+
+func TestNewEntry(t *testing.T) {
+	for name, test := range map[string]struct {
+		method       string
+		path         string
+		resourceType string
+		resourceID   string
+		action       string
+	}{
+		"RestartTask": {
+			method:       http.MethodPost,
+			path:         "/rest/v2/tasks/t1/restart",
+			resourceType: "task",
+			resourceID:   "t1",
+			action:       "restart",
+		},
+		"UpdateTask": {
+			method:       http.MethodPatch,
+			path:         "/rest/v2/tasks/t1",
+			resourceType: "task",
+			resourceID:   "t1",
+			action:       "update",
+		},
+		"NestedResource": {
+			method:       http.MethodDelete,
+			path:         "/rest/v2/projects/p1/subscriptions/s1",
+			resourceType: "subscription",
+			resourceID:   "s1",
+			action:       "delete",
+		},
+		"CreateResource": {
+			method:       http.MethodPost,
+			path:         "/rest/v2/subscriptions",
+			resourceType: "subscription",
+			action:       "create",
+		},
+		"UIRoute": {
+			method:       http.MethodPost,
+			path:         "/spawn/h1/extend",
+			resourceType: "host",
+			resourceID:   "h1",
+			action:       "extend",
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			r, err := http.NewRequest(test.method, test.path, nil)
+			require.NoError(t, err)
+			r.RemoteAddr = "10.0.0.1:1234"
+
+			e := NewEntry(r, "me", nil)
+			assert.Equal(t, "me", e.Actor)
+			assert.Equal(t, "10.0.0.1", e.SourceIP)
+			assert.Equal(t, test.method, e.Method)
+			assert.Equal(t, test.path, e.Path)
+			assert.Equal(t, test.resourceType, e.ResourceType)
+			assert.Equal(t, test.resourceID, e.ResourceID)
+			assert.Equal(t, test.action, e.Action)
+		})
+	}
+
+	t.Run("ForwardedFor", func(t *testing.T) {
+		r, err := http.NewRequest(http.MethodPost, "/rest/v2/tasks/t1/abort", nil)
+		require.NoError(t, err)
+		r.RemoteAddr = "10.0.0.1:1234"
+		r.Header.Set("X-Forwarded-For", "1.2.3.4, 192.168.1.1, 10.0.0.2")
+
+		// the header is ignored unless the request came through a proxy
+		assert.Equal(t, "10.0.0.1", NewEntry(r, "me", nil).SourceIP)
+		assert.Equal(t, "10.0.0.1", NewEntry(r, "me", []string{"10.0.0.2"}).SourceIP)
+
+		// only the addresses appended by trusted proxies are believed
+		assert.Equal(t, "10.0.0.2", NewEntry(r, "me", []string{"10.0.0.1"}).SourceIP)
+		assert.Equal(t, "192.168.1.1", NewEntry(r, "me", []string{"10.0.0.0/8"}).SourceIP)
+		assert.Equal(t, "1.2.3.4", NewEntry(r, "me", []string{"10.0.0.0/8", "192.168.0.0/16"}).SourceIP)
+	})
+}
+
+func TestDiff(t *testing.T) {
+	type resource struct {
+		Name     string `bson:"name"`
+		Priority int64  `bson:"priority"`
+		Tags     []string
+	}
+
+	t.Run("ChangedFields", func(t *testing.T) {
+		before := resource{Name: "r", Priority: 1, Tags: []string{"a"}}
+		after := resource{Name: "r", Priority: 10, Tags: []string{"a", "b"}}
+
+		changes, err := Diff("task", "t1", before, after)
+		require.NoError(t, err)
+		require.Len(t, changes, 2)
+		assert.Equal(t, "priority", changes[0].Field)
+		assert.EqualValues(t, 1, changes[0].Before)
+		assert.EqualValues(t, 10, changes[0].After)
+		assert.Equal(t, "tags", changes[1].Field)
+		assert.Equal(t, "task", changes[1].ResourceType)
+		assert.Equal(t, "t1", changes[1].ResourceID)
+	})
+	t.Run("Unchanged", func(t *testing.T) {
+		r := &resource{Name: "r"}
+		changes, err := Diff("task", "t1", r, r)
+		require.NoError(t, err)
+		assert.Empty(t, changes)
+	})
+	t.Run("Created", func(t *testing.T) {
+		var before *resource
+		changes, err := Diff("task", "t1", before, &resource{Name: "r"})
+		require.NoError(t, err)
+		require.Len(t, changes, 3)
+		for _, change := range changes {
+			assert.Nil(t, change.Before)
+		}
+	})
+	t.Run("NestedFields", func(t *testing.T) {
+		before := map[string]interface{}{"target": map[string]string{"url": "a", "secret": "s1"}}
+		after := map[string]interface{}{"target": map[string]string{"url": "b", "secret": "s2"}}
+
+		changes, err := Diff("subscription", "s1", before, after)
+		require.NoError(t, err)
+		require.Len(t, changes, 2)
+		assert.Equal(t, "target.secret", changes[0].Field)
+		assert.Equal(t, RedactedValue, changes[0].Before)
+		assert.Equal(t, RedactedValue, changes[0].After)
+		assert.Equal(t, "target.url", changes[1].Field)
+		assert.Equal(t, "a", changes[1].Before)
+		assert.Equal(t, "b", changes[1].After)
+	})
+	t.Run("RedactedFields", func(t *testing.T) {
+		before := map[string]interface{}{"vars": map[string]string{"a": "1"}}
+		after := map[string]interface{}{"vars": map[string]string{"a": "2", "b": "3"}}
+
+		changes, err := Diff("project", "p1", before, after, "vars")
+		require.NoError(t, err)
+		require.Len(t, changes, 2)
+		assert.Equal(t, "vars.a", changes[0].Field)
+		assert.Equal(t, RedactedValue, changes[0].Before)
+		assert.Equal(t, RedactedValue, changes[0].After)
+		assert.Equal(t, "vars.b", changes[1].Field)
+		assert.Nil(t, changes[1].Before)
+		assert.Equal(t, RedactedValue, changes[1].After)
+	})
+	t.Run("SecretsInLists", func(t *testing.T) {
+		after := map[string]interface{}{"secrets": []map[string]string{{"secret": "s"}}, "hooks": []map[string]string{{"url": "u", "secret": "s"}}}
+
+		changes, err := Diff("subscription", "s1", nil, after)
+		require.NoError(t, err)
+		require.Len(t, changes, 2)
+		assert.Equal(t, "hooks", changes[0].Field)
+		assert.Equal(t, []interface{}{bson.M{"url": "u", "secret": RedactedValue}}, changes[0].After)
+		assert.Equal(t, "secrets", changes[1].Field)
+		assert.Equal(t, RedactedValue, changes[1].After)
+	})
+	t.Run("SecretFieldNames", func(t *testing.T) {
+		after := map[string]interface{}{"apikey": "k1", "api_key": "k2", "APIKey": "k3", "github_token": "t", "name": "n"}
+
+		changes, err := Diff("user", "u1", nil, after)
+		require.NoError(t, err)
+		require.Len(t, changes, 5)
+		for _, change := range changes {
+			if change.Field == "name" {
+				assert.Equal(t, "n", change.After)
+				continue
+			}
+			assert.Equal(t, RedactedValue, change.After, change.Field)
+		}
+	})
+	t.Run("Deleted", func(t *testing.T) {
+		changes, err := Diff("task", "t1", map[string]string{"name": "r"}, nil)
+		require.NoError(t, err)
+		require.Len(t, changes, 1)
+		assert.Equal(t, "r", changes[0].Before)
+		assert.Nil(t, changes[0].After)
+	})
+}
+
+func TestRecordChange(t *testing.T) {
+	RecordChange(context.Background(), "task", "t1", nil, map[string]int{"priority": 1})
+
+	e := &Entry{Action: "update"}
+	ctx := WithEntry(context.Background(), e)
+	assert.Equal(t, e, GetEntry(ctx))
+
+	RecordChange(ctx, "task", "t1", map[string]int{"priority": 0}, map[string]int{"priority": 1})
+	require.Len(t, e.Changes, 1)
+	assert.Equal(t, "priority", e.Changes[0].Field)
+
+	SetAction(ctx, "task", "t1", "set_priority")
+	assert.Equal(t, "task", e.ResourceType)
+	assert.Equal(t, "t1", e.ResourceID)
+	assert.Equal(t, "set_priority", e.Action)
+
+	assert.False(t, e.Skipped())
+	Skip(ctx)
+	assert.True(t, e.Skipped())
+}
+
+func TestFind(t *testing.T) {
+	require.NoError(t, db.Clear(Collection))
+	defer func() {
+		assert.NoError(t, db.Clear(Collection))
+	}()
+
+	now := time.Now().Round(time.Millisecond)
+	entries := []Entry{
+		{Actor: "a", ResourceType: "task", ResourceID: "t1", Action: "restart", Timestamp: now.Add(-2 * time.Hour)},
+		{Actor: "b", ResourceType: "task", ResourceID: "t2", Action: "abort", Timestamp: now.Add(-time.Hour)},
+		{Actor: "a", ResourceType: "host", ResourceID: "h1", Action: "extend", Timestamp: now},
+	}
+	for i := range entries {
+		r, err := http.NewRequest(http.MethodPost, "/", nil)
+		require.NoError(t, err)
+		e := NewEntry(r, entries[i].Actor, nil)
+		e.ResourceType = entries[i].ResourceType
+		e.ResourceID = entries[i].ResourceID
+		e.Action = entries[i].Action
+		e.Timestamp = entries[i].Timestamp
+		require.NoError(t, e.Insert())
+		entries[i] = *e
+	}
+
+	found, err := Find(Filter{})
+	require.NoError(t, err)
+	require.Len(t, found, 3)
+	assert.Equal(t, entries[2].ID, found[0].ID)
+	assert.Equal(t, entries[0].ID, found[2].ID)
+
+	found, err = Find(Filter{Actor: "a", ResourceType: "task"})
+	require.NoError(t, err)
+	require.Len(t, found, 1)
+	assert.Equal(t, "t1", found[0].ResourceID)
+
+	found, err = Find(Filter{Start: now.Add(-90 * time.Minute), End: now})
+	require.NoError(t, err)
+	require.Len(t, found, 1)
+	assert.Equal(t, "abort", found[0].Action)
+
+	found, err = Find(Filter{Before: entries[2].ID.Hex(), Limit: 1})
+	require.NoError(t, err)
+	require.Len(t, found, 1)
+	assert.Equal(t, entries[1].ID, found[0].ID)
+
+	_, err = Find(Filter{Before: "not an id"})
+	assert.Error(t, err)
+}
@@ -0,0 +1,210 @@
+package model
+
+import (
+	"github.com/evergreen-ci/evergreen"
+	"github.com/evergreen-ci/evergreen/db"
+	"github.com/evergreen-ci/evergreen/model/event"
+	"github.com/evergreen-ci/evergreen/model/task"
+	"github.com/mongodb/grip"
+	"github.com/mongodb/grip/message"
+	"github.com/pkg/errors"
+	"gopkg.in/mgo.v2/bson"
+)
+
+// getBisect returns true if failures of the task should be bisected rather
+// than stepped back one commit at a time. Only mainline tasks of projects
+// that enable bisection are bisected. Bisection replaces stepback, so it's
+// only considered for tasks that stepback is enabled for, and projects must
+// enable both.
+func getBisect(t *task.Task) (bool, error) {
+	if t.Requester != evergreen.RepotrackerVersionRequester {
+		return false, nil
+	}
+
+	project, err := FindProjectFromTask(t)
+	if err != nil {
+		return false, errors.WithStack(err)
+	}
+
+	return project.Bisect, nil
+}
+
+// doBisect continues the search for the commit that first broke a failed
+// mainline task. The search covers the executions of the task after its
+// most recent success, up to the failure. While tasks in that range have
+// never run, the one at the midpoint of the unknown commits is activated.
+// Once every task in the range has either failed or won't run, the first
+// failure is the culprit, which is recorded on the failures that follow it
+// and logged as an event. Like stepback, display tasks are bisected through
+// their failed execution tasks. System failures say nothing about the
+// commit, so they don't start a search, and tasks in the range that failed
+// that way are skipped like tasks that won't run.
+func doBisect(t *task.Task) error {
+	if t.DisplayOnly {
+		execTasks, err := task.Find(task.ByIds(t.ExecutionTasks))
+		if err != nil {
+			return errors.Wrapf(err, "error finding tasks to bisect for %s", t.Id)
+		}
+		catcher := grip.NewSimpleCatcher()
+		for i := range execTasks {
+			if execTasks[i].Status == evergreen.TaskFailed {
+				catcher.Add(doBisect(&execTasks[i]))
+			}
+		}
+		return catcher.Resolve()
+	}
+	if isSystemFailure(t) {
+		return nil
+	}
+
+	lastPassing, err := task.FindOne(task.ByBeforeRevisionWithStatusesAndRequester(t.RevisionOrderNumber,
+		[]string{evergreen.TaskSucceeded}, t.BuildVariant, t.DisplayName, t.Project, t.Requester))
+	if err != nil {
+		return errors.Wrap(err, "error locating previous successful task")
+	}
+	// without a prior success, the search could step back through the
+	// whole history of the task
+	if lastPassing == nil {
+		return nil
+	}
+
+	tasks, err := task.Find(task.ByRevisionOrderRange(lastPassing.RevisionOrderNumber, t.RevisionOrderNumber,
+		t.BuildVariant, t.DisplayName, t.Project, t.Requester))
+	if err != nil {
+		return errors.Wrapf(err, "error finding tasks to bisect for %s", t.Id)
+	}
+
+	// every finished task after the last success has failed, so the
+	// first one bounds the search
+	var firstFailure *task.Task
+	unknown := []task.Task{}
+	bisected := lastPassing.ActivatedBy == evergreen.BisectTaskActivator
+	for i := range tasks {
+		if tasks[i].ActivatedBy == evergreen.BisectTaskActivator {
+			bisected = true
+		}
+		if firstFailure != nil || isSystemFailure(&tasks[i]) {
+			continue
+		}
+		if tasks[i].IsFinished() {
+			firstFailure = &tasks[i]
+			continue
+		}
+		if tasks[i].Priority < 0 {
+			continue
+		}
+		if tasks[i].Activated {
+			// wait for the task that's already running to narrow the range
+			return nil
+		}
+		unknown = append(unknown, tasks[i])
+	}
+	if firstFailure == nil {
+		return nil
+	}
+
+	if len(unknown) > 0 {
+		midpoint := unknown[len(unknown)/2]
+		grip.Info(message.Fields{
+			"message":      "activating task to bisect failure",
+			"task_id":      midpoint.Id,
+			"failed_task":  t.Id,
+			"last_passing": lastPassing.Id,
+			"remaining":    len(unknown),
+		})
+		return errors.WithStack(SetActiveState(midpoint.Id, evergreen.BisectTaskActivator, true))
+	}
+
+	// failures that didn't skip any commits are already attributed by
+	// the ordinary failure notifications
+	if !bisected || firstFailure.BisectCulprit != "" {
+		return nil
+	}
+
+	// the culprit is recorded on the failures up to the next success
+	nextSuccess, err := task.FindOne(task.ByAfterRevisionWithStatusesAndRequester(t.RevisionOrderNumber,
+		[]string{evergreen.TaskSucceeded}, t.BuildVariant, t.DisplayName, t.Project, t.Requester))
+	if err != nil {
+		return errors.Wrapf(err, "error finding the success after %s", t.Id)
+	}
+	orderRange := bson.M{"$gte": firstFailure.RevisionOrderNumber}
+	if nextSuccess != nil {
+		orderRange["$lt"] = nextSuccess.RevisionOrderNumber
+	}
+	_, err = task.UpdateAll(
+		bson.M{
+			task.BuildVariantKey:        t.BuildVariant,
+			task.DisplayNameKey:         t.DisplayName,
+			task.ProjectKey:             t.Project,
+			task.RequesterKey:           t.Requester,
+			task.StatusKey:              evergreen.TaskFailed,
+			task.RevisionOrderNumberKey: orderRange,
+			task.DetailsKey + "." + task.TaskEndDetailType: bson.M{
+				"$ne": evergreen.CommandTypeSystem,
+			},
+		},
+		bson.M{"$set": bson.M{task.BisectCulpritKey: firstFailure.Id}},
+	)
+	if err != nil {
+		return errors.Wrapf(err, "error recording bisect culprit %s", firstFailure.Id)
+	}
+
+	grip.Info(message.Fields{
+		"message":      "bisect found first failure",
+		"task_id":      firstFailure.Id,
+		"revision":     firstFailure.Revision,
+		"failed_task":  t.Id,
+		"last_passing": lastPassing.Id,
+	})
+	event.LogTaskBisectCulpritFound(firstFailure.Id, firstFailure.Execution)
+
+	return nil
+}
+
+// continueBisect continues the bisection that activated a task that has
+// succeeded, from the next failure of the task that isn't a system failure.
+// Display tasks continue the bisections of their execution tasks.
+func continueBisect(t *task.Task) error {
+	if t.DisplayOnly {
+		execTasks, err := task.Find(task.ByIds(t.ExecutionTasks))
+		if err != nil {
+			return errors.Wrapf(err, "error finding tasks to bisect for %s", t.Id)
+		}
+		catcher := grip.NewSimpleCatcher()
+		for i := range execTasks {
+			if execTasks[i].Status == evergreen.TaskSucceeded {
+				catcher.Add(continueBisect(&execTasks[i]))
+			}
+		}
+		return catcher.Resolve()
+	}
+	if t.ActivatedBy != evergreen.BisectTaskActivator {
+		return nil
+	}
+
+	nextFailure, err := task.FindOne(db.Query(bson.M{
+		task.BuildVariantKey: t.BuildVariant,
+		task.DisplayNameKey:  t.DisplayName,
+		task.ProjectKey:      t.Project,
+		task.RequesterKey:    t.Requester,
+		task.StatusKey:       evergreen.TaskFailed,
+		task.RevisionOrderNumberKey: bson.M{
+			"$gt": t.RevisionOrderNumber,
+		},
+		task.DetailsKey + "." + task.TaskEndDetailType: bson.M{
+			"$ne": evergreen.CommandTypeSystem,
+		},
+	}).Sort([]string{task.RevisionOrderNumberKey}))
+	if err != nil {
+		return errors.Wrapf(err, "error finding the failure after %s", t.Id)
+	}
+	if nextFailure == nil {
+		return nil
+	}
+
+	return errors.WithStack(doBisect(nextFailure))
+}
+
+func isSystemFailure(t *task.Task) bool {
+	return t.Status == evergreen.TaskFailed && t.Details.Type == evergreen.CommandTypeSystem
+}
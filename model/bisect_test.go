@@ -0,0 +1,198 @@
+package model
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/evergreen-ci/evergreen"
+	"github.com/evergreen-ci/evergreen/db"
+	"github.com/evergreen-ci/evergreen/model/event"
+	"github.com/evergreen-ci/evergreen/model/task"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/mgo.v2/bson"
+)
+
+func TestBisect(t *testing.T) {
+	assert := assert.New(t)
+	require.NoError(t, db.ClearCollections(task.Collection, event.AllLogCollection))
+
+	// the task passed at revision 1 and failed at revision 8, and
+	// the revisions between them were skipped
+	for i := 1; i <= 8; i++ {
+		tsk := &task.Task{
+			Id:                  fmt.Sprintf("t%d", i),
+			DisplayName:         "test",
+			BuildVariant:        "linux",
+			Project:             "proj",
+			DistroId:            "d",
+			Requester:           evergreen.RepotrackerVersionRequester,
+			RevisionOrderNumber: i,
+			Status:              evergreen.TaskUndispatched,
+		}
+		switch i {
+		case 1:
+			tsk.Status = evergreen.TaskSucceeded
+			tsk.Activated = true
+		case 8:
+			tsk.Status = evergreen.TaskFailed
+			tsk.Activated = true
+		}
+		require.NoError(t, tsk.Insert())
+	}
+	finish := func(id, status string) *task.Task {
+		require.NoError(t, task.UpdateOne(bson.M{task.IdKey: id}, bson.M{"$set": bson.M{task.StatusKey: status}}))
+		tsk, err := task.FindOne(task.ById(id))
+		require.NoError(t, err)
+		return tsk
+	}
+	activatedBy := func(id string) string {
+		tsk, err := task.FindOne(task.ById(id))
+		require.NoError(t, err)
+		if !tsk.Activated {
+			return ""
+		}
+		return tsk.ActivatedBy
+	}
+
+	// the midpoint of revisions 2-7 is activated
+	failed, err := task.FindOne(task.ById("t8"))
+	require.NoError(t, err)
+	require.NoError(t, doBisect(failed))
+	assert.Equal(evergreen.BisectTaskActivator, activatedBy("t5"))
+
+	// nothing else is activated while the midpoint hasn't run
+	require.NoError(t, doBisect(failed))
+	assert.Equal("", activatedBy("t3"))
+	assert.Equal("", activatedBy("t6"))
+
+	// a failure narrows the search to revisions 2-4
+	require.NoError(t, doBisect(finish("t5", evergreen.TaskFailed)))
+	assert.Equal(evergreen.BisectTaskActivator, activatedBy("t3"))
+
+	// a success narrows it to revision 4
+	require.NoError(t, continueBisect(finish("t3", evergreen.TaskSucceeded)))
+	assert.Equal(evergreen.BisectTaskActivator, activatedBy("t4"))
+
+	require.NoError(t, doBisect(finish("t4", evergreen.TaskFailed)))
+	for _, id := range []string{"t4", "t5", "t8"} {
+		tsk, err := task.FindOne(task.ById(id))
+		require.NoError(t, err)
+		assert.Equal("t4", tsk.BisectCulprit)
+	}
+	assert.Equal("", activatedBy("t6"))
+
+	events, err := event.Find(event.AllLogCollection, db.Query(bson.M{
+		event.ResourceIdKey: "t4",
+		event.TypeKey:       event.TaskBisectCulpritFound,
+	}))
+	require.NoError(t, err)
+	assert.Len(events, 1)
+
+	// the culprit is only reported once
+	require.NoError(t, doBisect(failed))
+	events, err = event.Find(event.AllLogCollection, db.Query(bson.M{
+		event.ResourceIdKey: "t4",
+		event.TypeKey:       event.TaskBisectCulpritFound,
+	}))
+	require.NoError(t, err)
+	assert.Len(events, 1)
+}
+
+func TestBisectSkipsSystemFailures(t *testing.T) {
+	assert := assert.New(t)
+	require.NoError(t, db.ClearCollections(task.Collection, event.AllLogCollection))
+
+	// the task passed at revision 1, failed at revision 3 on a system
+	// failure, and failed at revision 5
+	for i := 1; i <= 5; i++ {
+		tsk := &task.Task{
+			Id:                  fmt.Sprintf("t%d", i),
+			DisplayName:         "test",
+			BuildVariant:        "linux",
+			Project:             "proj",
+			DistroId:            "d",
+			Requester:           evergreen.RepotrackerVersionRequester,
+			RevisionOrderNumber: i,
+			Status:              evergreen.TaskUndispatched,
+		}
+		switch i {
+		case 1:
+			tsk.Status = evergreen.TaskSucceeded
+			tsk.Activated = true
+		case 3:
+			tsk.Status = evergreen.TaskFailed
+			tsk.Activated = true
+			tsk.Details.Type = evergreen.CommandTypeSystem
+		case 5:
+			tsk.Status = evergreen.TaskFailed
+			tsk.Activated = true
+		}
+		require.NoError(t, tsk.Insert())
+	}
+	activated := func(id string) bool {
+		tsk, err := task.FindOne(task.ById(id))
+		require.NoError(t, err)
+		return tsk.Activated
+	}
+
+	// a system failure doesn't start a search
+	systemFailed, err := task.FindOne(task.ById("t3"))
+	require.NoError(t, err)
+	require.NoError(t, doBisect(systemFailed))
+	assert.False(activated("t2"))
+
+	// nor does it bound the search of a later failure
+	failed, err := task.FindOne(task.ById("t5"))
+	require.NoError(t, err)
+	require.NoError(t, doBisect(failed))
+	assert.True(activated("t4"))
+	assert.False(activated("t2"))
+}
+
+func TestBisectDisplayTask(t *testing.T) {
+	assert := assert.New(t)
+	require.NoError(t, db.ClearCollections(task.Collection, event.AllLogCollection))
+
+	// the execution task passed at revision 1 and failed at revision 3
+	for i := 1; i <= 3; i++ {
+		tsk := &task.Task{
+			Id:                  fmt.Sprintf("exec%d", i),
+			DisplayName:         "exec",
+			BuildVariant:        "linux",
+			Project:             "proj",
+			DistroId:            "d",
+			Requester:           evergreen.RepotrackerVersionRequester,
+			RevisionOrderNumber: i,
+			Status:              evergreen.TaskUndispatched,
+		}
+		switch i {
+		case 1:
+			tsk.Status = evergreen.TaskSucceeded
+			tsk.Activated = true
+		case 3:
+			tsk.Status = evergreen.TaskFailed
+			tsk.Activated = true
+		}
+		require.NoError(t, tsk.Insert())
+	}
+	displayTask := &task.Task{
+		Id:                  "display3",
+		DisplayName:         "display",
+		BuildVariant:        "linux",
+		Project:             "proj",
+		Requester:           evergreen.RepotrackerVersionRequester,
+		RevisionOrderNumber: 3,
+		Status:              evergreen.TaskFailed,
+		DisplayOnly:         true,
+		ExecutionTasks:      []string{"exec3"},
+	}
+	require.NoError(t, displayTask.Insert())
+
+	// the failed execution task is bisected
+	require.NoError(t, doBisect(displayTask))
+	tsk, err := task.FindOne(task.ById("exec2"))
+	require.NoError(t, err)
+	assert.True(tsk.Activated)
+	assert.Equal(evergreen.BisectTaskActivator, tsk.ActivatedBy)
+}
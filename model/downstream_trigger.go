@@ -0,0 +1,202 @@
+package model
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/evergreen-ci/evergreen"
+	"github.com/evergreen-ci/evergreen/model/artifact"
+	"github.com/evergreen-ci/evergreen/model/build"
+	"github.com/evergreen-ci/evergreen/model/event"
+	"github.com/evergreen-ci/evergreen/model/version"
+	"github.com/evergreen-ci/evergreen/util"
+	"github.com/mongodb/grip"
+	"github.com/mongodb/grip/message"
+	"github.com/pkg/errors"
+)
+
+// maxDownstreamDepth is the longest chain of downstream triggers that a
+// mainline version can start.
+const maxDownstreamDepth = 5
+
+var expansionNameReplacer = regexp.MustCompile("[^a-z0-9]+")
+
+// DownstreamVersionId returns the id of the version of a downstream project
+// created by the success of the given upstream version or build.
+func DownstreamVersionId(projectId, upstreamId string) string {
+	return util.CleanName(fmt.Sprintf("%s_trigger_%s", projectId, upstreamId))
+}
+
+// CreateDownstreamVersions creates the versions of downstream projects for
+// an event, if it is the success of a mainline version or build whose
+// project has downstream triggers. It returns the versions created.
+func CreateDownstreamVersions(e *event.EventLogEntry) ([]version.Version, error) {
+	var level string
+	var upstream *version.Version
+	var upstreamBuild *build.Build
+	var err error
+
+	switch {
+	case e.ResourceType == event.ResourceTypeVersion && e.EventType == event.VersionStateChange:
+		data, ok := e.Data.(*event.VersionEventData)
+		if !ok || data.Status != evergreen.VersionSucceeded {
+			return nil, nil
+		}
+		level = DownstreamLevelVersion
+		upstream, err = version.FindOne(version.ById(e.ResourceId))
+		if err != nil {
+			return nil, errors.Wrapf(err, "problem finding version '%s'", e.ResourceId)
+		}
+
+	case e.ResourceType == event.ResourceTypeBuild && e.EventType == event.BuildStateChange:
+		data, ok := e.Data.(*event.BuildEventData)
+		if !ok || data.Status != evergreen.BuildSucceeded {
+			return nil, nil
+		}
+		level = DownstreamLevelBuild
+		upstreamBuild, err = build.FindOneId(e.ResourceId)
+		if err != nil {
+			return nil, errors.Wrapf(err, "problem finding build '%s'", e.ResourceId)
+		}
+		if upstreamBuild == nil {
+			return nil, errors.Errorf("can't find build '%s'", e.ResourceId)
+		}
+		upstream, err = version.FindOne(version.ById(upstreamBuild.Version))
+		if err != nil {
+			return nil, errors.Wrapf(err, "problem finding version '%s'", upstreamBuild.Version)
+		}
+
+	default:
+		return nil, nil
+	}
+	if upstream == nil {
+		return nil, errors.Errorf("can't find the upstream version of event '%s'", e.ID)
+	}
+
+	// only mainline versions, and the versions that they trigger, start
+	// downstream versions
+	if upstream.Requester != evergreen.RepotrackerVersionRequester && upstream.Requester != evergreen.TriggerRequester {
+		return nil, nil
+	}
+
+	ref, err := FindOneProjectRef(upstream.Identifier)
+	if err != nil {
+		return nil, errors.Wrapf(err, "problem finding project '%s'", upstream.Identifier)
+	}
+	if ref == nil || len(ref.Downstream) == 0 {
+		return nil, nil
+	}
+
+	var expansions map[string]string
+	versions := []version.Version{}
+	catcher := grip.NewBasicCatcher()
+	for _, d := range ref.Downstream {
+		if d.Level != level {
+			continue
+		}
+		if level == DownstreamLevelBuild && d.BuildVariant != "" && d.BuildVariant != upstreamBuild.BuildVariant {
+			continue
+		}
+
+		chain := append([]string{}, upstream.TriggerProjects...)
+		chain = append(chain, upstream.Identifier)
+		if util.StringSliceContains(chain, d.Project) || len(chain) > maxDownstreamDepth {
+			grip.Warning(message.Fields{
+				"message":    "refusing to create downstream version that would loop",
+				"upstream":   upstream.Id,
+				"project":    upstream.Identifier,
+				"downstream": d.Project,
+				"chain":      chain,
+			})
+			continue
+		}
+
+		var downstreamRef *ProjectRef
+		downstreamRef, err = FindOneProjectRef(d.Project)
+		if err != nil {
+			catcher.Add(errors.Wrapf(err, "problem finding downstream project '%s'", d.Project))
+			continue
+		}
+		if downstreamRef == nil || !downstreamRef.Enabled {
+			catcher.Add(errors.Errorf("downstream project '%s' doesn't exist or is disabled", d.Project))
+			continue
+		}
+
+		if expansions == nil {
+			expansions, err = makeDownstreamExpansions(upstream, upstreamBuild)
+			if err != nil {
+				return versions, errors.Wrap(err, "problem collecting upstream artifacts")
+			}
+		}
+
+		upstreamId := upstream.Id
+		if upstreamBuild != nil {
+			upstreamId = upstreamBuild.Id
+		}
+		v := &version.Version{
+			Id:                DownstreamVersionId(d.Project, upstreamId),
+			CreateTime:        time.Now(),
+			Requester:         evergreen.TriggerRequester,
+			TriggerID:         upstream.Id,
+			TriggerType:       level,
+			TriggerProjects:   chain,
+			TriggerExpansions: expansions,
+		}
+		v, err = createVersionFromMostRecent(downstreamRef, v, d.DownstreamVariants)
+		if err != nil {
+			catcher.Add(errors.Wrapf(err, "problem creating downstream version of project '%s'", d.Project))
+			continue
+		}
+		versions = append(versions, *v)
+	}
+
+	return versions, catcher.Resolve()
+}
+
+// makeDownstreamExpansions returns the expansions that describe an upstream
+// version, or one of its builds, to the tasks of a downstream version.
+// Artifacts are named trigger_artifact_<variant>_<task>_<file>.
+func makeDownstreamExpansions(upstream *version.Version, upstreamBuild *build.Build) (map[string]string, error) {
+	expansions := map[string]string{
+		"trigger_id":       upstream.Id,
+		"trigger_project":  upstream.Identifier,
+		"trigger_revision": upstream.Revision,
+		"trigger_branch":   upstream.Branch,
+	}
+
+	builds := []build.Build{}
+	if upstreamBuild != nil {
+		expansions["trigger_build_id"] = upstreamBuild.Id
+		expansions["trigger_build_variant"] = upstreamBuild.BuildVariant
+		builds = append(builds, *upstreamBuild)
+	} else {
+		var err error
+		builds, err = build.Find(build.ByVersion(upstream.Id))
+		if err != nil {
+			return nil, errors.Wrapf(err, "problem finding builds of version '%s'", upstream.Id)
+		}
+	}
+
+	for _, b := range builds {
+		entries, err := artifact.FindAll(artifact.ByBuildId(b.Id))
+		if err != nil {
+			return nil, errors.Wrapf(err, "problem finding artifacts of build '%s'", b.Id)
+		}
+		for _, entry := range entries {
+			for _, file := range entry.Files {
+				name := strings.Join([]string{"trigger_artifact", b.BuildVariant, entry.TaskDisplayName, file.Name}, "_")
+				expansions[expansionName(name)] = file.Link
+			}
+		}
+	}
+
+	return expansions, nil
+}
+
+// expansionName lower cases a name and replaces runs of characters that
+// aren't letters or digits with an underscore.
+func expansionName(name string) string {
+	return strings.Trim(expansionNameReplacer.ReplaceAllString(strings.ToLower(name), "_"), "_")
+}
@@ -0,0 +1,139 @@
+package model
+
+import (
+	"testing"
+	"time"
+
+	"github.com/evergreen-ci/evergreen"
+	"github.com/evergreen-ci/evergreen/db"
+	"github.com/evergreen-ci/evergreen/model/artifact"
+	"github.com/evergreen-ci/evergreen/model/build"
+	"github.com/evergreen-ci/evergreen/model/event"
+	"github.com/evergreen-ci/evergreen/model/task"
+	"github.com/evergreen-ci/evergreen/model/version"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestExpansionName(t *testing.T) {
+	assert := assert.New(t)
+
+	assert.Equal("trigger_artifact_linux_compile_binaries", expansionName("trigger_artifact_linux_compile_Binaries"))
+	assert.Equal("trigger_artifact_rhel_7_2_compile_coverage_report", expansionName("trigger_artifact_rhel-7.2_compile_Coverage Report"))
+	assert.Equal("a_b", expansionName("__a!!b__"))
+}
+
+func TestCreateDownstreamVersions(t *testing.T) {
+	assert := assert.New(t)
+	require.NoError(t, db.ClearCollections(version.Collection, build.Collection, task.Collection,
+		ProjectRefCollection, artifact.Collection))
+
+	config := `
+tasks:
+- name: compile
+buildvariants:
+- name: linux
+  run_on: [d]
+  tasks:
+  - name: compile
+`
+	upstreamRef := &ProjectRef{
+		Identifier: "server",
+		Enabled:    true,
+		Downstream: []DownstreamTrigger{
+			{Project: "tools", Level: DownstreamLevelVersion},
+			{Project: "driver", Level: DownstreamLevelBuild, BuildVariant: "windows"},
+		},
+	}
+	toolsRef := &ProjectRef{
+		Identifier: "tools",
+		Enabled:    true,
+		Downstream: []DownstreamTrigger{
+			{Project: "server", Level: DownstreamLevelVersion},
+		},
+	}
+	driverRef := &ProjectRef{Identifier: "driver", Enabled: true}
+	for _, ref := range []*ProjectRef{upstreamRef, toolsRef, driverRef} {
+		require.NoError(t, ref.Insert())
+	}
+
+	for _, proj := range []string{"server", "tools", "driver"} {
+		v := &version.Version{
+			Id:         proj + "_abcdef",
+			CreateTime: time.Now().Add(-time.Hour),
+			Identifier: proj,
+			Revision:   "abcdef",
+			Branch:     "master",
+			Requester:  evergreen.RepotrackerVersionRequester,
+			Config:     config,
+		}
+		require.NoError(t, v.Insert())
+	}
+	b := &build.Build{Id: "server_linux", BuildVariant: "linux", Version: "server_abcdef"}
+	require.NoError(t, b.Insert())
+	require.NoError(t, artifact.Entry{
+		TaskId:          "server_linux_compile",
+		TaskDisplayName: "compile",
+		BuildId:         b.Id,
+		Files:           []artifact.File{{Name: "Binaries", Link: "http://example.com/server.tgz"}},
+	}.Upsert())
+
+	// failures don't start downstream versions
+	versions, err := CreateDownstreamVersions(&event.EventLogEntry{
+		ResourceType: event.ResourceTypeVersion,
+		EventType:    event.VersionStateChange,
+		ResourceId:   "server_abcdef",
+		Data:         &event.VersionEventData{Status: evergreen.VersionFailed},
+	})
+	assert.NoError(err)
+	assert.Empty(versions)
+
+	// the linux build doesn't match the driver's trigger
+	versions, err = CreateDownstreamVersions(&event.EventLogEntry{
+		ResourceType: event.ResourceTypeBuild,
+		EventType:    event.BuildStateChange,
+		ResourceId:   b.Id,
+		Data:         &event.BuildEventData{Status: evergreen.BuildSucceeded},
+	})
+	assert.NoError(err)
+	assert.Empty(versions)
+
+	versionSucceeded := &event.EventLogEntry{
+		ResourceType: event.ResourceTypeVersion,
+		EventType:    event.VersionStateChange,
+		ResourceId:   "server_abcdef",
+		Data:         &event.VersionEventData{Status: evergreen.VersionSucceeded},
+	}
+	versions, err = CreateDownstreamVersions(versionSucceeded)
+	require.NoError(t, err)
+	require.Len(t, versions, 1)
+	downstream := versions[0]
+	assert.Equal(DownstreamVersionId("tools", "server_abcdef"), downstream.Id)
+	assert.Equal("tools", downstream.Identifier)
+	assert.Equal(evergreen.TriggerRequester, downstream.Requester)
+	assert.Equal("server_abcdef", downstream.TriggerID)
+	assert.Equal(DownstreamLevelVersion, downstream.TriggerType)
+	assert.Equal([]string{"server"}, downstream.TriggerProjects)
+	assert.Equal("abcdef", downstream.TriggerExpansions["trigger_revision"])
+	assert.Equal("http://example.com/server.tgz", downstream.TriggerExpansions["trigger_artifact_linux_compile_binaries"])
+
+	// the same event doesn't create a second version
+	versions, err = CreateDownstreamVersions(versionSucceeded)
+	require.NoError(t, err)
+	require.Len(t, versions, 1)
+	assert.Equal(downstream.Id, versions[0].Id)
+
+	// the downstream version succeeding doesn't loop back to its upstream project
+	versions, err = CreateDownstreamVersions(&event.EventLogEntry{
+		ResourceType: event.ResourceTypeVersion,
+		EventType:    event.VersionStateChange,
+		ResourceId:   downstream.Id,
+		Data:         &event.VersionEventData{Status: evergreen.VersionSucceeded},
+	})
+	assert.NoError(err)
+	assert.Empty(versions)
+
+	found, err := version.Find(version.ByTriggerID("server_abcdef"))
+	require.NoError(t, err)
+	assert.Len(found, 1)
+}
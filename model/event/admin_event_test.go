@@ -47,7 +47,7 @@ func (s *AdminEventSuite) TestEventLogging() {
 	beforeVal := eventData.Changes.Before.(*evergreen.ServiceFlags)
 	afterVal := eventData.Changes.After.(*evergreen.ServiceFlags)
 	s.Equal(before, *beforeVal)
-	s.Equal(false, afterVal.AlertsDisabled)
+	s.Equal(false, afterVal.TaskDispatchDisabled)
 	s.Equal(true, afterVal.MonitorDisabled)
 	s.Equal(true, afterVal.RepotrackerDisabled)
 }
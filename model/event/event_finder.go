@@ -1,6 +1,9 @@
 package event
 
 import (
+	"fmt"
+	"strconv"
+	"strings"
 	"time"
 
 	"github.com/evergreen-ci/evergreen/db"
@@ -118,6 +121,14 @@ func MostRecentHostEvents(id string, n int) db.Q {
 	return HostEventsForId(id).Sort([]string{"-" + TimestampKey}).Limit(n)
 }
 
+func MostRecentHostEventsOfType(id, eventType string, n int) db.Q {
+	filter := resourceTypeKeyIs(ResourceTypeHost)
+	filter[ResourceIdKey] = id
+	filter[TypeKey] = eventType
+
+	return db.Query(filter).Sort([]string{"-" + TimestampKey}).Limit(n)
+}
+
 func HostEventsInOrder(id string) db.Q {
 	return HostEventsForId(id).Sort([]string{TimestampKey})
 }
@@ -238,3 +249,78 @@ func TaskProcessInfoEvents(taskID string, ts time.Time, limit, sort int) db.Q {
 
 	return db.Query(filter).Sort([]string{sortSpec}).Limit(limit)
 }
+
+// EventCursor is a position in the event log, after which the event
+// stream resumes. Events are ordered by the time they were logged, with
+// their IDs breaking ties, since IDs aren't ordered across their legacy
+// and current formats.
+type EventCursor struct {
+	Timestamp time.Time
+	ID        string
+}
+
+// NewEventCursor returns the position of the given event.
+func NewEventCursor(e *EventLogEntry) EventCursor {
+	return EventCursor{Timestamp: e.Timestamp, ID: e.ID}
+}
+
+// EventCursorAt returns a position before all events logged at or after
+// the given time.
+func EventCursorAt(t time.Time) EventCursor {
+	return EventCursor{Timestamp: t.Truncate(time.Millisecond)}
+}
+
+// String encodes the cursor as the milliseconds since the epoch of its
+// time followed by its ID.
+func (c EventCursor) String() string {
+	return fmt.Sprintf("%d_%s", c.Timestamp.UnixNano()/int64(time.Millisecond), c.ID)
+}
+
+// ParseEventCursor decodes a cursor encoded by String. The ID of an event
+// is also accepted if it's an ObjectId, which resumes at its time.
+func ParseEventCursor(s string) (EventCursor, error) {
+	if bson.IsObjectIdHex(s) {
+		return EventCursor{Timestamp: bson.ObjectIdHex(s).Time(), ID: s}, nil
+	}
+	parts := strings.SplitN(s, "_", 2)
+	if len(parts) != 2 {
+		return EventCursor{}, errors.Errorf("invalid event cursor '%s'", s)
+	}
+	ms, err := strconv.ParseInt(parts[0], 10, 64)
+	if err != nil {
+		return EventCursor{}, errors.Errorf("invalid event cursor '%s'", s)
+	}
+	return EventCursor{
+		Timestamp: time.Unix(0, ms*int64(time.Millisecond)),
+		ID:        parts[1],
+	}, nil
+}
+
+// After returns whether the given event is positioned after the cursor.
+func (c EventCursor) After(e *EventLogEntry) bool {
+	if e.Timestamp.Equal(c.Timestamp) {
+		return e.ID > c.ID
+	}
+	return e.Timestamp.After(c.Timestamp)
+}
+
+// EventsAfter builds a query for events of the given resource types
+// that were logged after the cursor and no later than the given time, in
+// the order that they were logged. Events logged recently may still be
+// followed by inserts with earlier times, so limiting the query to events
+// older than that leaves none behind the cursor.
+func EventsAfter(after EventCursor, before time.Time, resourceTypes []string, n int) db.Q {
+	filter := bson.M{
+		"$or": []bson.M{
+			{TimestampKey: bson.M{"$gt": after.Timestamp, "$lte": before}},
+			{TimestampKey: after.Timestamp, idKey: bson.M{"$gt": after.ID}},
+		},
+	}
+	if len(resourceTypes) > 0 {
+		filter[ResourceTypeKey] = bson.M{
+			"$in": resourceTypes,
+		}
+	}
+
+	return db.Query(filter).Sort([]string{TimestampKey, idKey}).Limit(n)
+}
@@ -0,0 +1,39 @@
+package event
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/mgo.v2/bson"
+)
+
+func TestEventCursor(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+
+	now := time.Now().Truncate(time.Millisecond)
+	cursor := EventCursor{Timestamp: now, ID: "some_id"}
+	parsed, err := ParseEventCursor(cursor.String())
+	require.NoError(err)
+	assert.True(now.Equal(parsed.Timestamp))
+	assert.Equal("some_id", parsed.ID)
+
+	id := bson.NewObjectIdWithTime(now)
+	parsed, err = ParseEventCursor(id.Hex())
+	require.NoError(err)
+	assert.True(id.Time().Equal(parsed.Timestamp))
+	assert.Equal(id.Hex(), parsed.ID)
+
+	for _, s := range []string{"", "abc", "abc_def"} {
+		_, err = ParseEventCursor(s)
+		assert.Error(err, s)
+	}
+
+	assert.True(cursor.After(&EventLogEntry{Timestamp: now, ID: "some_other_id"}))
+	assert.False(cursor.After(&EventLogEntry{Timestamp: now, ID: "an_id"}))
+	assert.True(cursor.After(&EventLogEntry{Timestamp: now.Add(time.Millisecond), ID: "an_id"}))
+	assert.False(cursor.After(&EventLogEntry{Timestamp: now.Add(-time.Millisecond), ID: "some_other_id"}))
+	assert.True(EventCursorAt(now).After(&EventLogEntry{Timestamp: now, ID: "an_id"}))
+}
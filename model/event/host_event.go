@@ -17,6 +17,9 @@ func init() {
 	registry.AllowSubscription(ResourceTypeHost, EventHostExpirationWarningSent)
 	registry.AllowSubscription(ResourceTypeHost, EventHostProvisioned)
 	registry.AllowSubscription(ResourceTypeHost, EventHostProvisionFailed)
+	registry.AllowSubscription(ResourceTypeHost, EventHostAgentDeployFailed)
+	registry.AllowSubscription(ResourceTypeHost, EventHostTerminatedExternally)
+	registry.AllowSubscription(ResourceTypeHost, EventHostIdle)
 }
 
 const (
@@ -41,6 +44,7 @@ const (
 	EventHostTeardown              = "HOST_TEARDOWN"
 	EventHostTerminatedExternally  = "HOST_TERMINATED_EXTERNALLY"
 	EventHostExpirationWarningSent = "HOST_EXPIRATION_WARNING_SENT"
+	EventHostIdle                  = "HOST_IDLE"
 )
 
 // implements EventData
@@ -105,8 +109,8 @@ func LogHostProvisionError(hostId string) {
 	LogHostEvent(hostId, EventHostProvisionError, HostEventData{})
 }
 
-func LogHostTerminatedExternally(hostId string) {
-	LogHostEvent(hostId, EventHostStatusChanged, HostEventData{NewStatus: EventHostTerminatedExternally})
+func LogHostTerminatedExternally(hostId, oldStatus string) {
+	LogHostEvent(hostId, EventHostTerminatedExternally, HostEventData{OldStatus: oldStatus, NewStatus: evergreen.HostTerminated})
 }
 
 func LogHostStatusChanged(hostId, oldStatus, newStatus, user string, logs string) {
@@ -163,6 +167,12 @@ func LogExpirationWarningSent(hostID string) {
 	LogHostEvent(hostID, EventHostExpirationWarningSent, HostEventData{})
 }
 
+// LogHostIdle is used when a host has been idle for longer than the
+// idle cutoff, but has not yet been terminated.
+func LogHostIdle(hostID string, idleTime time.Duration) {
+	LogHostEvent(hostID, EventHostIdle, HostEventData{Duration: idleTime})
+}
+
 // UpdateExecutions updates host events to track multiple executions of the same task
 func UpdateExecutions(hostId, taskId string, execution int) error {
 	taskIdKey := bsonutil.MustHaveTag(HostEventData{}, "TaskId")
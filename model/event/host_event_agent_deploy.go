@@ -102,3 +102,27 @@ func (m *RecentHostAgentDeploys) LastAttemptFailed() bool { return m.Last == Eve
 func (m *RecentHostAgentDeploys) AllAttemptsFailed() bool {
 	return m.Count > 0 && m.Success == 0 && m.HostStatusChanged == 0
 }
+
+// ConsecutiveAgentDeployFailures returns the number of failed agent
+// deploy attempts for the host since the most recent successful deploy or
+// status change, looking at no more than limit attempts.
+func ConsecutiveAgentDeployFailures(hostID string, limit int) (int, error) {
+	query := resourceTypeKeyIs(ResourceTypeHost)
+	query[TypeKey] = bson.M{"$in": []string{EventHostAgentDeployed, EventHostAgentDeployFailed, EventHostStatusChanged}}
+	query[ResourceIdKey] = hostID
+
+	events, err := Find(AllLogCollection, db.Query(query).Sort([]string{"-" + TimestampKey}).Limit(limit))
+	if err != nil {
+		return 0, errors.Wrap(err, "problem finding agent deploy events")
+	}
+
+	failures := 0
+	for _, e := range events {
+		if e.EventType != EventHostAgentDeployFailed {
+			break
+		}
+		failures++
+	}
+
+	return failures, nil
+}
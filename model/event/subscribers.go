@@ -2,6 +2,7 @@ package event
 
 import (
 	"fmt"
+	"time"
 
 	"github.com/evergreen-ci/evergreen/util"
 	"github.com/mongodb/anser/bsonutil"
@@ -122,6 +123,61 @@ func (s *Subscriber) Validate() error {
 type WebhookSubscriber struct {
 	URL    string `bson:"url"`
 	Secret []byte `bson:"secret"`
+	// SecretVersion is incremented each time the secret is rotated
+	SecretVersion int `bson:"secret_version,omitempty"`
+	// PreviousSecrets are rotated secrets that payloads are still signed
+	// with until they expire, so receivers have time to switch to the
+	// new secret
+	PreviousSecrets []WebhookSecret `bson:"previous_secrets,omitempty"`
+}
+
+type WebhookSecret struct {
+	Version   int       `bson:"version"`
+	Secret    []byte    `bson:"secret"`
+	ExpiresAt time.Time `bson:"expires_at"`
+}
+
+// SigningKeys returns the current secret followed by the previous secrets
+// that haven't expired yet.
+func (s *WebhookSubscriber) SigningKeys(now time.Time) []util.WebhookSigningKey {
+	keys := []util.WebhookSigningKey{
+		{
+			Version: s.SecretVersion,
+			Secret:  s.Secret,
+		},
+	}
+	for _, secret := range s.PreviousSecrets {
+		if secret.ExpiresAt.After(now) {
+			keys = append(keys, util.WebhookSigningKey{
+				Version: secret.Version,
+				Secret:  secret.Secret,
+			})
+		}
+	}
+
+	return keys
+}
+
+// RotateSecret replaces the secret with a new one. The old secret remains
+// valid for the grace period, and expired secrets are discarded.
+func (s *WebhookSubscriber) RotateSecret(secret []byte, grace time.Duration, now time.Time) {
+	previous := []WebhookSecret{}
+	for _, old := range s.PreviousSecrets {
+		if old.ExpiresAt.After(now) {
+			previous = append(previous, old)
+		}
+	}
+	if grace > 0 && len(s.Secret) != 0 {
+		previous = append(previous, WebhookSecret{
+			Version:   s.SecretVersion,
+			Secret:    s.Secret,
+			ExpiresAt: now.Add(grace),
+		})
+	}
+
+	s.PreviousSecrets = previous
+	s.Secret = secret
+	s.SecretVersion++
 }
 
 func (s *WebhookSubscriber) String() string {
@@ -3,6 +3,7 @@ package event
 import (
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/evergreen-ci/evergreen/db"
 	"github.com/stretchr/testify/assert"
@@ -106,3 +107,34 @@ func TestSubscribersStringerWithMissingAttributes(t *testing.T) {
 
 	assert.True(strings.HasSuffix(webhookSub.String(), "NIL_URL"))
 }
+
+func TestWebhookSecretRotation(t *testing.T) {
+	assert := assert.New(t)
+
+	now := time.Now()
+	sub := WebhookSubscriber{
+		URL:    "https://example.com",
+		Secret: []byte("v0"),
+	}
+	keys := sub.SigningKeys(now)
+	assert.Len(keys, 1)
+	assert.Equal(0, keys[0].Version)
+
+	sub.RotateSecret([]byte("v1"), time.Hour, now)
+	assert.Equal("v1", string(sub.Secret))
+	assert.Equal(1, sub.SecretVersion)
+	keys = sub.SigningKeys(now)
+	assert.Len(keys, 2)
+	assert.Equal(1, keys[0].Version)
+	assert.Equal("v1", string(keys[0].Secret))
+	assert.Equal(0, keys[1].Version)
+	assert.Equal("v0", string(keys[1].Secret))
+
+	// the previous secret is no longer used after the grace period
+	assert.Len(sub.SigningKeys(now.Add(2*time.Hour)), 1)
+
+	// expired secrets are discarded on the next rotation
+	sub.RotateSecret([]byte("v2"), 0, now.Add(2*time.Hour))
+	assert.Equal(2, sub.SecretVersion)
+	assert.Empty(sub.PreviousSecrets)
+}
@@ -1,9 +1,12 @@
 package event
 
 import (
+	"crypto/rand"
+	"encoding/hex"
 	"fmt"
 	"regexp"
 	"strconv"
+	"time"
 
 	"github.com/evergreen-ci/evergreen/db"
 	"github.com/evergreen-ci/evergreen/util"
@@ -37,12 +40,16 @@ type OwnerType string
 const (
 	OwnerTypePerson                         OwnerType = "person"
 	OwnerTypeProject                        OwnerType = "project"
+	OwnerTypeDistro                         OwnerType = "distro"
 	TaskDurationKey                                   = "task-duration-secs"
 	TaskPercentChangeKey                              = "task-percent-change"
 	BuildDurationKey                                  = "build-duration-secs"
 	BuildPercentChangeKey                             = "build-percent-change"
 	VersionDurationKey                                = "version-duration-secs"
 	VersionPercentChangeKey                           = "version-percent-change"
+	TaskNotifySuspectsKey                             = "notify-suspects"
+	HostIdleDurationKey                               = "host-idle-secs"
+	HostAgentDeployFailuresKey                        = "agent-deploy-failures"
 	ImplicitSubscriptionPatchOutcome                  = "patch-outcome"
 	ImplicitSubscriptionBuildBreak                    = "build-break"
 	ImplicitSubscriptionSpawnhostExpiration           = "spawnhost-expiration"
@@ -142,6 +149,27 @@ func FindSubscriptions(resourceType string, selectors []Selector) ([]Subscriptio
 	return out, nil
 }
 
+// SelectorsMatch returns true if every selector is present in the
+// event's selectors, and every regex selector matches the event's
+// selector of the same type, mirroring how subscriptions are matched
+// to events.
+func SelectorsMatch(eventSelectors, selectors, regexSelectors []Selector) bool {
+	for i := range selectors {
+		found := false
+		for j := range eventSelectors {
+			if eventSelectors[j] == selectors[i] {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+
+	return regexSelectorsMatch(eventSelectors, regexSelectors)
+}
+
 func regexSelectorsMatch(selectors []Selector, regexSelectors []Selector) bool {
 	for i := range regexSelectors {
 		selector := findSelector(selectors, regexSelectors[i].Type)
@@ -176,6 +204,8 @@ func findSelector(selectors []Selector, selectorType string) *Selector {
 func (s *Subscription) Upsert() error {
 	if s.ID == "" {
 		s.ID = bson.NewObjectId().Hex()
+	} else if err := s.keepPreviousWebhookSecrets(); err != nil {
+		return err
 	}
 	update := bson.M{
 		subscriptionTypeKey:           s.Type,
@@ -207,6 +237,43 @@ func (s *Subscription) Upsert() error {
 	return nil
 }
 
+// keepPreviousWebhookSecrets carries the rotated secrets of a stored webhook
+// subscription over to its replacement, since clients editing a subscription
+// only send back its current secret.
+func (s *Subscription) keepPreviousWebhookSecrets() error {
+	if s.Subscriber.Type != EvergreenWebhookSubscriberType {
+		return nil
+	}
+	var webhook *WebhookSubscriber
+	switch v := s.Subscriber.Target.(type) {
+	case *WebhookSubscriber:
+		webhook = v
+	case WebhookSubscriber:
+		webhook = &v
+		s.Subscriber.Target = webhook
+	default:
+		return nil
+	}
+	if len(webhook.PreviousSecrets) != 0 {
+		return nil
+	}
+
+	stored, err := FindSubscriptionByID(s.ID)
+	if err != nil {
+		return err
+	}
+	if stored == nil || stored.Owner != s.Owner {
+		return nil
+	}
+	storedWebhook, ok := stored.Subscriber.Target.(*WebhookSubscriber)
+	if !ok {
+		return nil
+	}
+	webhook.PreviousSecrets = storedWebhook.PreviousSecrets
+
+	return nil
+}
+
 func FindSubscriptionByID(id string) (*Subscription, error) {
 	out := Subscription{}
 	err := db.FindOneQ(SubscriptionsCollection, db.Query(bson.M{
@@ -232,6 +299,42 @@ func RemoveSubscription(id string) error {
 	})
 }
 
+// RotateWebhookSecret generates a new secret for a webhook subscription,
+// keeping the old secret valid for the grace period.
+func RotateWebhookSecret(id string, grace time.Duration) (*Subscription, error) {
+	sub, err := FindSubscriptionByID(id)
+	if err != nil {
+		return nil, err
+	}
+	if sub == nil {
+		return nil, errors.Errorf("subscription '%s' not found", id)
+	}
+
+	webhook, ok := sub.Subscriber.Target.(*WebhookSubscriber)
+	if !ok || sub.Subscriber.Type != EvergreenWebhookSubscriberType {
+		return nil, errors.Errorf("subscription '%s' is not a webhook subscription", id)
+	}
+
+	secret := make([]byte, 32)
+	if _, err = rand.Read(secret); err != nil {
+		return nil, errors.Wrap(err, "failed to generate secret")
+	}
+	webhook.RotateSecret([]byte(hex.EncodeToString(secret)), grace, time.Now())
+
+	err = db.Update(SubscriptionsCollection, bson.M{
+		subscriptionIDKey: id,
+	}, bson.M{
+		"$set": bson.M{
+			subscriptionSubscriberKey: sub.Subscriber,
+		},
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to save rotated secret")
+	}
+
+	return sub, nil
+}
+
 func (s *Subscription) Validate() error {
 	catcher := grip.NewBasicCatcher()
 	if len(s.Selectors)+len(s.RegexSelectors) == 0 {
@@ -272,6 +375,17 @@ func (s *Subscription) runCustomValidation() error {
 	if buildPercentVal, ok := s.TriggerData[BuildPercentChangeKey]; ok {
 		catcher.Add(validatePositiveFloat(buildPercentVal))
 	}
+	if hostIdleVal, ok := s.TriggerData[HostIdleDurationKey]; ok {
+		catcher.Add(validatePositiveInt(hostIdleVal))
+	}
+	if agentDeployFailuresVal, ok := s.TriggerData[HostAgentDeployFailuresKey]; ok {
+		catcher.Add(validatePositiveInt(agentDeployFailuresVal))
+	}
+	if notifySuspectsVal, ok := s.TriggerData[TaskNotifySuspectsKey]; ok {
+		if _, err := strconv.ParseBool(notifySuspectsVal); err != nil {
+			catcher.Add(fmt.Errorf("%s must be true or false", notifySuspectsVal))
+		}
+	}
 	return catcher.Resolve()
 }
 
@@ -350,6 +464,8 @@ func IsValidOwnerType(in string) bool {
 		return true
 	case string(OwnerTypeProject):
 		return true
+	case string(OwnerTypeDistro):
+		return true
 	default:
 		return false
 	}
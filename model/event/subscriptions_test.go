@@ -2,6 +2,7 @@ package event
 
 import (
 	"testing"
+	"time"
 
 	"github.com/evergreen-ci/evergreen/db"
 	"github.com/evergreen-ci/evergreen/testutil"
@@ -262,6 +263,26 @@ func (s *subscriptionsSuite) TestRegexSelectorsMatch() {
 	s.False(regexSelectorsMatch(selectors, a.RegexSelectors))
 }
 
+func (s *subscriptionsSuite) TestSelectorsMatch() {
+	eventSelectors := []Selector{
+		{
+			Type: "id",
+			Data: "task1",
+		},
+		{
+			Type: "project",
+			Data: "mci",
+		},
+	}
+
+	s.True(SelectorsMatch(eventSelectors, nil, nil))
+	s.True(SelectorsMatch(eventSelectors, []Selector{{Type: "project", Data: "mci"}}, nil))
+	s.True(SelectorsMatch(eventSelectors, []Selector{{Type: "project", Data: "mci"}}, []Selector{{Type: "id", Data: "^task"}}))
+	s.False(SelectorsMatch(eventSelectors, []Selector{{Type: "project", Data: "other"}}, nil))
+	s.False(SelectorsMatch(eventSelectors, []Selector{{Type: "owner", Data: "me"}}, nil))
+	s.False(SelectorsMatch(eventSelectors, nil, []Selector{{Type: "id", Data: "^build"}}))
+}
+
 func (s *subscriptionsSuite) TestFindByOwnerForPerson() {
 	subscriptions, err := FindSubscriptionsByOwner("me", OwnerTypePerson)
 	s.NoError(err)
@@ -293,3 +314,59 @@ func (s *subscriptionsSuite) TestFindSubscriptionsByOwner() {
 	s.NoError(err)
 	s.Nil(sub)
 }
+
+func (s *subscriptionsSuite) TestRotateWebhookSecret() {
+	sub := Subscription{
+		ID:        bson.NewObjectId().Hex(),
+		Type:      "type1",
+		Trigger:   "trigger1",
+		Selectors: []Selector{{Type: "data1", Data: "something"}},
+		Subscriber: Subscriber{
+			Type: EvergreenWebhookSubscriberType,
+			Target: &WebhookSubscriber{
+				URL:    "https://example.com",
+				Secret: []byte("original"),
+			},
+		},
+		Owner:     "me",
+		OwnerType: OwnerTypePerson,
+	}
+	s.NoError(sub.Upsert())
+
+	rotated, err := RotateWebhookSecret(sub.ID, time.Hour)
+	s.NoError(err)
+	s.Require().NotNil(rotated)
+
+	fromDB, err := FindSubscriptionByID(sub.ID)
+	s.NoError(err)
+	s.Require().NotNil(fromDB)
+	webhook := fromDB.Subscriber.Target.(*WebhookSubscriber)
+	s.Equal(rotated.Subscriber.Target.(*WebhookSubscriber).Secret, webhook.Secret)
+	s.Len(webhook.Secret, 64)
+	s.Equal(1, webhook.SecretVersion)
+	s.Require().Len(webhook.PreviousSecrets, 1)
+	s.Equal("original", string(webhook.PreviousSecrets[0].Secret))
+	s.Len(webhook.SigningKeys(time.Now()), 2)
+
+	// saving the subscription from a client that only knows the current
+	// secret shouldn't drop the rotated one
+	edited := *fromDB
+	edited.Subscriber.Target = WebhookSubscriber{
+		URL:           "https://example.com/edited",
+		Secret:        webhook.Secret,
+		SecretVersion: webhook.SecretVersion,
+	}
+	s.NoError(edited.Upsert())
+	fromDB, err = FindSubscriptionByID(sub.ID)
+	s.NoError(err)
+	s.Require().NotNil(fromDB)
+	webhook = fromDB.Subscriber.Target.(*WebhookSubscriber)
+	s.Equal("https://example.com/edited", webhook.URL)
+	s.Require().Len(webhook.PreviousSecrets, 1)
+	s.Equal("original", string(webhook.PreviousSecrets[0].Secret))
+
+	_, err = RotateWebhookSecret(s.subscriptions[0].ID, time.Hour)
+	s.Error(err)
+	_, err = RotateWebhookSecret("nonexistent", time.Hour)
+	s.Error(err)
+}
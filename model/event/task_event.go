@@ -3,6 +3,7 @@ package event
 import (
 	"time"
 
+	"github.com/evergreen-ci/evergreen"
 	"github.com/mongodb/grip"
 	"github.com/mongodb/grip/message"
 )
@@ -10,6 +11,7 @@ import (
 func init() {
 	registry.AddType(ResourceTypeTask, taskEventDataFactory)
 	registry.AllowSubscription(ResourceTypeTask, TaskFinished)
+	registry.AllowSubscription(ResourceTypeTask, TaskBisectCulpritFound)
 }
 
 const (
@@ -30,6 +32,7 @@ const (
 	TaskPriorityChanged         = "TASK_PRIORITY_CHANGED"
 	TaskJiraAlertCreated        = "TASK_JIRA_ALERT_CREATED"
 	TaskDepdendenciesOverridden = "TASK_DEPENDENCIES_OVERRIDDEN"
+	TaskBisectCulpritFound      = "TASK_BISECT_CULPRIT_FOUND"
 )
 
 // implements Data
@@ -123,6 +126,12 @@ func LogDisplayTaskFinished(taskId string, execution int, status string) {
 	logTaskEvent(taskId, TaskFinished, TaskEventData{Execution: execution, Status: status})
 }
 
+// LogTaskBisectCulpritFound logs that bisection found the task to be the
+// first failure of a run of mainline failures.
+func LogTaskBisectCulpritFound(taskId string, execution int) {
+	logTaskEvent(taskId, TaskBisectCulpritFound, TaskEventData{Execution: execution, Status: evergreen.TaskFailed})
+}
+
 func LogTaskRestarted(taskId string, execution int, userId string) {
 	logTaskEvent(taskId, TaskRestarted, TaskEventData{Execution: execution, UserId: userId})
 }
@@ -66,13 +66,22 @@ func SetBuildActivation(buildId string, active bool, caller string) error {
 
 	// If activating a task, set the ActivatedBy field to be the caller
 	if active {
+		query := bson.M{
+			task.BuildIdKey: buildId,
+			task.StatusKey:  evergreen.TaskUndispatched,
+		}
+		// evergreen doesn't activate tasks that the version's changes
+		// don't affect, but users can
+		if evergreen.IsSystemActivator(caller) {
+			query[task.PathFilteredKey] = bson.M{"$ne": true}
+		}
 		_, err = task.UpdateAll(
-			bson.M{
-				task.BuildIdKey: buildId,
-				task.StatusKey:  evergreen.TaskUndispatched,
-			},
+			query,
 			bson.M{"$set": bson.M{task.ActivatedKey: active, task.ActivatedByKey: caller}},
 		)
+		if err == nil && evergreen.IsSystemActivator(caller) {
+			err = activatePathFilteredDependencies(buildId, caller)
+		}
 	} else {
 
 		// if trying to deactivate a task then only deactivate tasks that have not been activated by a user.
@@ -109,6 +118,42 @@ func SetBuildActivation(buildId string, active bool, caller string) error {
 	return RefreshTasksCache(buildId)
 }
 
+// activatePathFilteredDependencies activates the path-filtered tasks that the
+// active tasks of a build depend on, since those tasks couldn't run otherwise.
+func activatePathFilteredDependencies(buildId, caller string) error {
+	tasks, err := task.Find(task.ByBuildId(buildId).WithFields(task.ActivatedKey, task.DependsOnKey))
+	if err != nil {
+		return errors.Wrapf(err, "problem finding tasks of build '%s'", buildId)
+	}
+	depIds := []string{}
+	for _, t := range tasks {
+		if !t.Activated {
+			continue
+		}
+		for _, dep := range t.DependsOn {
+			depIds = append(depIds, dep.TaskId)
+		}
+	}
+	if len(depIds) == 0 {
+		return nil
+	}
+
+	deps, err := task.Find(db.Query(bson.M{
+		task.IdKey:           bson.M{"$in": depIds},
+		task.PathFilteredKey: true,
+		task.ActivatedKey:    false,
+	}).WithFields(task.IdKey))
+	if err != nil {
+		return errors.Wrapf(err, "problem finding path-filtered dependencies of build '%s'", buildId)
+	}
+	catcher := grip.NewBasicCatcher()
+	for _, dep := range deps {
+		// also activates the dependencies of the dependency
+		catcher.Add(SetActiveState(dep.Id, caller, true))
+	}
+	return catcher.Resolve()
+}
+
 // AbortBuild sets the abort flag on all tasks associated with the build which are in an abortable
 // state, and marks the build as deactivated.
 func AbortBuild(buildId string, caller string) error {
@@ -416,7 +461,7 @@ func AddTasksToBuild(b *build.Build, project *Project, v *version.Version,
 
 	// create the new tasks for the build
 	taskIds := NewTaskIdTable(project, v)
-	tasks, err := createTasksForBuild(project, buildVariant, b, v, taskIds, taskNames, displayNames, generatedBy)
+	tasks, err := createTasksForBuild(project, buildVariant, b, v, taskIds, taskNames, displayNames, generatedBy, nil)
 	if err != nil {
 		return nil, errors.Wrapf(err, "error creating tasks for build %s", b.Id)
 	}
@@ -436,7 +481,8 @@ func AddTasksToBuild(b *build.Build, project *Project, v *version.Version,
 // CreateBuildFromVersion creates a build given all of the necessary information
 // from the corresponding version and project and a list of tasks.
 func CreateBuildFromVersion(project *Project, v *version.Version, taskIds TaskIdConfig,
-	buildName string, activated bool, taskNames []string, displayNames []string, generatedBy string) (string, error) {
+	buildName string, activated bool, taskNames []string, displayNames []string, generatedBy string,
+	changedFiles []string) (string, error) {
 
 	grip.Debugf("Creating %v %v build, activated: %v", v.Requester, buildName, activated)
 
@@ -449,6 +495,9 @@ func CreateBuildFromVersion(project *Project, v *version.Version, taskIds TaskId
 	rev := v.Revision
 	if evergreen.IsPatchRequester(v.Requester) {
 		rev = fmt.Sprintf("patch_%s_%s", v.Revision, v.Id)
+	} else if v.Requester == evergreen.ScheduledVersionRequester || v.Requester == evergreen.TriggerRequester {
+		// these versions share their revision with a mainline version
+		rev = v.Id
 	}
 
 	// create a new build id
@@ -482,7 +531,7 @@ func CreateBuildFromVersion(project *Project, v *version.Version, taskIds TaskId
 	b.BuildNumber = strconv.FormatUint(buildNumber, 10)
 
 	// create all of the necessary tasks for the build
-	tasksForBuild, err := createTasksForBuild(project, buildVariant, b, v, taskIds, taskNames, displayNames, generatedBy)
+	tasksForBuild, err := createTasksForBuild(project, buildVariant, b, v, taskIds, taskNames, displayNames, generatedBy, changedFiles)
 	if err != nil {
 		return "", errors.Wrapf(err, "error creating tasks for build %s", b.Id)
 	}
@@ -537,6 +586,8 @@ func CreateTasksFromGroup(in BuildVariantTaskUnit, proj *Project) []BuildVariant
 			Distros:         in.Distros,
 			ExecTimeoutSecs: in.ExecTimeoutSecs,
 			Stepback:        in.Stepback,
+			Paths:           in.Paths,
+			IgnorePaths:     in.IgnorePaths,
 		}
 		bvt.Populate(taskMap[t])
 		tasks = append(tasks, bvt)
@@ -560,7 +611,7 @@ func shouldNotPatchBuild(t BuildVariantTaskUnit, requester string) bool {
 // appear in the specified build variant.
 func createTasksForBuild(project *Project, buildVariant *BuildVariant, b *build.Build,
 	v *version.Version, taskIds TaskIdConfig, taskNames []string,
-	displayNames []string, generatedBy string) (task.Tasks, error) {
+	displayNames []string, generatedBy string, changedFiles []string) (task.Tasks, error) {
 
 	// the list of tasks we should create.  if tasks are passed in, then
 	// use those, else use the default set
@@ -636,6 +687,10 @@ func createTasksForBuild(project *Project, buildVariant *BuildVariant, b *build.
 
 	for _, t := range tasksToCreate {
 		newTask := createOneTask(execTable.GetId(b.BuildVariant, t.Name), t, project, buildVariant, b, v)
+		if !buildVariant.IsAffectedBy(changedFiles) || !t.IsAffectedBy(changedFiles) {
+			newTask.PathFiltered = true
+			newTask.Activated = false
+		}
 
 		// set Tags based on the spec
 		newTask.Tags = project.GetSpecForTask(t.Name).Tags
@@ -1050,7 +1105,7 @@ func AddNewBuilds(activated bool, v *version.Version, p *Project, tasks TaskVari
 		// Extract the unique set of task names for the variant we're about to create
 		taskNames := tasks.ExecTasks.TaskNames(pair.Variant)
 		displayNames := tasks.DisplayTasks.TaskNames(pair.Variant)
-		buildId, err := CreateBuildFromVersion(p, v, taskIds, pair.Variant, activated, taskNames, displayNames, generatedBy)
+		buildId, err := CreateBuildFromVersion(p, v, taskIds, pair.Variant, activated, taskNames, displayNames, generatedBy, nil)
 		grip.Infof("Creating build for version %s, buildVariant %s, activated=%t",
 			v.Id, pair.Variant, activated)
 		if err != nil {
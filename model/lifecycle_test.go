@@ -14,6 +14,7 @@ import (
 	. "github.com/smartystreets/goconvey/convey"
 	"github.com/smartystreets/goconvey/convey/reporting"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func init() {
@@ -470,6 +471,40 @@ func TestBuildSetActivated(t *testing.T) {
 	})
 }
 
+func TestSetBuildActivationPathFilteredDependencies(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+	require.NoError(db.ClearCollections(build.Collection, task.Collection))
+
+	b := &build.Build{
+		Id: "build",
+		Tasks: []build.TaskCache{
+			{Id: "compile"}, {Id: "generate"}, {Id: "test"}, {Id: "docs"},
+		},
+	}
+	require.NoError(b.Insert())
+	tasks := []task.Task{
+		{Id: "generate", PathFiltered: true},
+		{Id: "compile", PathFiltered: true, DependsOn: []task.Dependency{{TaskId: "generate"}}},
+		{Id: "test", DependsOn: []task.Dependency{{TaskId: "compile"}}},
+		{Id: "docs", PathFiltered: true},
+	}
+	for _, t := range tasks {
+		t.BuildId = b.Id
+		t.DistroId = "d"
+		t.Status = evergreen.TaskUndispatched
+		require.NoError(t.Insert())
+	}
+
+	require.NoError(SetBuildActivation(b.Id, true, evergreen.DefaultTaskActivator))
+	for id, active := range map[string]bool{"test": true, "compile": true, "generate": true, "docs": false} {
+		dbTask, err := task.FindOne(task.ById(id))
+		require.NoError(err)
+		require.NotNil(dbTask)
+		assert.Equal(active, dbTask.Activated, id)
+	}
+}
+
 func TestBuildMarkStarted(t *testing.T) {
 
 	Convey("With a build", t, func() {
@@ -695,7 +730,7 @@ buildvariants:
 
 		Convey("if a non-existent build variant is passed in, an error should be returned", func() {
 
-			buildId, err := CreateBuildFromVersion(project, v, table, "blecch", false, []string{}, nil, "")
+			buildId, err := CreateBuildFromVersion(project, v, table, "blecch", false, []string{}, nil, "", nil)
 			So(err, ShouldNotBeNil)
 			So(buildId, ShouldEqual, "")
 
@@ -704,10 +739,10 @@ buildvariants:
 		Convey("if no task names are passed in to be used, all of the default"+
 			" tasks for the build variant should be created", func() {
 
-			buildId, err := CreateBuildFromVersion(project, v, table, buildVar1.Name, false, []string{}, nil, "")
+			buildId, err := CreateBuildFromVersion(project, v, table, buildVar1.Name, false, []string{}, nil, "", nil)
 			So(err, ShouldBeNil)
 			So(buildId, ShouldNotEqual, "")
-			buildId2, err := CreateBuildFromVersion(project, v, table, buildVar2.Name, false, []string{}, nil, "")
+			buildId2, err := CreateBuildFromVersion(project, v, table, buildVar2.Name, false, []string{}, nil, "", nil)
 			So(err, ShouldBeNil)
 			So(buildId2, ShouldNotEqual, "")
 
@@ -723,7 +758,7 @@ buildvariants:
 			" specified tasks should be created", func() {
 
 			buildId, err := CreateBuildFromVersion(project, v, table, buildVar1.Name, false,
-				[]string{"taskA", "taskB"}, nil, "")
+				[]string{"taskA", "taskB"}, nil, "", nil)
 			So(err, ShouldBeNil)
 			So(buildId, ShouldNotEqual, "")
 
@@ -737,7 +772,7 @@ buildvariants:
 		Convey("ensure distro is populated to tasks", func() {
 
 			buildId, err := CreateBuildFromVersion(project, v, table, buildVar1.Name, false,
-				[]string{"taskA", "taskB"}, nil, "")
+				[]string{"taskA", "taskB"}, nil, "", nil)
 			So(err, ShouldBeNil)
 			So(buildId, ShouldNotEqual, "")
 
@@ -754,7 +789,7 @@ buildvariants:
 		Convey("the build should contain task caches that correspond exactly"+
 			" to the tasks created", func() {
 
-			buildId, err := CreateBuildFromVersion(project, v, table, buildVar2.Name, false, []string{}, nil, "")
+			buildId, err := CreateBuildFromVersion(project, v, table, buildVar2.Name, false, []string{}, nil, "", nil)
 			So(err, ShouldBeNil)
 			So(buildId, ShouldNotEqual, "")
 
@@ -786,7 +821,7 @@ buildvariants:
 
 		Convey("a task cache should not contain execution tasks that are part of a display task", func() {
 
-			buildId, err := CreateBuildFromVersion(project, v, table, buildVar1.Name, false, []string{}, nil, "")
+			buildId, err := CreateBuildFromVersion(project, v, table, buildVar1.Name, false, []string{}, nil, "", nil)
 			So(err, ShouldBeNil)
 			So(buildId, ShouldNotEqual, "")
 
@@ -822,13 +857,13 @@ buildvariants:
 		Convey("all of the tasks created should have the dependencies"+
 			"and priorities specified in the project", func() {
 
-			buildId, err := CreateBuildFromVersion(project, v, table, buildVar1.Name, false, []string{}, nil, "")
+			buildId, err := CreateBuildFromVersion(project, v, table, buildVar1.Name, false, []string{}, nil, "", nil)
 			So(err, ShouldBeNil)
 			So(buildId, ShouldNotEqual, "")
-			buildId2, err := CreateBuildFromVersion(project, v, table, buildVar2.Name, false, []string{}, nil, "")
+			buildId2, err := CreateBuildFromVersion(project, v, table, buildVar2.Name, false, []string{}, nil, "", nil)
 			So(err, ShouldBeNil)
 			So(buildId2, ShouldNotEqual, "")
-			buildId3, err := CreateBuildFromVersion(project, v, table, buildVar3.Name, false, []string{}, nil, "")
+			buildId3, err := CreateBuildFromVersion(project, v, table, buildVar3.Name, false, []string{}, nil, "", nil)
 			So(err, ShouldBeNil)
 			So(buildId3, ShouldNotEqual, "")
 
@@ -874,7 +909,7 @@ buildvariants:
 
 		Convey("all of the build's essential fields should be set correctly", func() {
 
-			buildId, err := CreateBuildFromVersion(project, v, table, buildVar1.Name, false, []string{}, nil, "")
+			buildId, err := CreateBuildFromVersion(project, v, table, buildVar1.Name, false, []string{}, nil, "", nil)
 			So(err, ShouldBeNil)
 			So(buildId, ShouldNotEqual, "")
 
@@ -898,7 +933,7 @@ buildvariants:
 		})
 
 		Convey("find distro name for tasks works", func() {
-			buildId, err := CreateBuildFromVersion(project, v, table, buildVar1.Name, false, []string{}, nil, "")
+			buildId, err := CreateBuildFromVersion(project, v, table, buildVar1.Name, false, []string{}, nil, "", nil)
 
 			So(err, ShouldBeNil)
 			So(buildId, ShouldNotEqual, "")
@@ -918,7 +953,7 @@ buildvariants:
 
 		Convey("all of the tasks' essential fields should be set correctly", func() {
 
-			buildId, err := CreateBuildFromVersion(project, v, table, buildVar1.Name, false, []string{}, nil, "")
+			buildId, err := CreateBuildFromVersion(project, v, table, buildVar1.Name, false, []string{}, nil, "", nil)
 			So(err, ShouldBeNil)
 			So(buildId, ShouldNotEqual, "")
 
@@ -999,7 +1034,7 @@ buildvariants:
 		Convey("if the activated flag is set, the build and all its tasks should be activated",
 			func() {
 
-				buildId, err := CreateBuildFromVersion(project, v, table, buildVar1.Name, true, []string{}, nil, "")
+				buildId, err := CreateBuildFromVersion(project, v, table, buildVar1.Name, true, []string{}, nil, "", nil)
 				So(err, ShouldBeNil)
 				So(buildId, ShouldNotEqual, "")
 
@@ -1142,7 +1177,7 @@ func TestCreateTaskGroup(t *testing.T) {
 	}
 	table := NewTaskIdTable(proj, v)
 
-	buildId, err := CreateBuildFromVersion(proj, v, table, "bv", true, nil, nil, "")
+	buildId, err := CreateBuildFromVersion(proj, v, table, "bv", true, nil, nil, "", nil)
 	assert.NoError(err)
 	dbBuild, err := build.FindOne(build.ById(buildId))
 	assert.NoError(err)
@@ -99,6 +99,7 @@ func (n *Notification) Composer() (message.Composer, error) {
 		}
 
 		payload.Secret = sub.Secret
+		payload.SigningKeys = sub.SigningKeys(time.Now())
 		payload.URL = sub.URL
 		payload.NotificationID = n.ID
 
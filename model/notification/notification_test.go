@@ -203,6 +203,7 @@ func (s *notificationSuite) TestWebhookPayload() {
 	s.NoError(err)
 	s.Require().NotNil(c)
 	s.True(c.Loggable())
+	s.Len(c.Raw().(*util.EvergreenWebhook).SigningKeys, 1)
 }
 
 func (s *notificationSuite) TestJIRACommentPayload() {
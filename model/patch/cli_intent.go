@@ -63,6 +63,10 @@ type cliIntent struct {
 
 	// alias defines the variants and tasks to run this patch on.
 	Alias string `bson:"alias"`
+
+	// SourceRef is the branch, tag, commit, or pull request that the
+	// server built the patch from, if the CLI didn't upload a diff.
+	SourceRef string `bson:"source_ref,omitempty"`
 }
 
 // BSON fields for the patches
@@ -146,6 +150,7 @@ func (c *cliIntent) NewPatch() *Patch {
 		BuildVariants: c.BuildVariants,
 		Alias:         c.Alias,
 		Tasks:         c.Tasks,
+		SourceRef:     c.SourceRef,
 		Patches: []ModulePatch{
 			{
 				ModuleName: c.Module,
@@ -195,6 +200,22 @@ func NewCliIntent(user, project, baseHash, module, patchContent, description str
 	}, nil
 }
 
+// NewRefCliIntent creates an intent for a patch that the server built from
+// a pushed ref of the project's repository, rather than from a diff
+// uploaded by the CLI.
+func NewRefCliIntent(user, project, baseHash, sourceRef, patchContent, description string, finalize bool, variants, tasks []string, alias string) (Intent, error) {
+	if sourceRef == "" {
+		return nil, errors.New("no source ref provided")
+	}
+	intent, err := NewCliIntent(user, project, baseHash, "", patchContent, description, finalize, variants, tasks, alias)
+	if err != nil {
+		return nil, err
+	}
+	intent.(*cliIntent).SourceRef = sourceRef
+
+	return intent, nil
+}
+
 func (c *cliIntent) GetAlias() string {
 	return c.Alias
 }
@@ -194,3 +194,20 @@ func (s *CliIntentSuite) TestNewPatch() {
 	s.Equal(s.alias, patchDoc.Alias)
 	s.Zero(patchDoc.GithubPatchData)
 }
+
+func (s *CliIntentSuite) TestNewRefCliIntent() {
+	intent, err := NewRefCliIntent(s.user, s.projectID, s.hash, "", s.patchContent, s.description, true, s.variants, s.tasks, s.alias)
+	s.Nil(intent)
+	s.Error(err)
+
+	intent, err = NewRefCliIntent(s.user, s.projectID, s.hash, "refs/pull/1/head", s.patchContent, s.description, true, s.variants, s.tasks, s.alias)
+	s.NoError(err)
+	s.NotNil(intent)
+
+	patchDoc := intent.NewPatch()
+	s.NotNil(patchDoc)
+	s.Equal("refs/pull/1/head", patchDoc.SourceRef)
+	s.Len(patchDoc.Patches, 1)
+	s.Empty(patchDoc.Patches[0].ModuleName)
+	s.Equal(s.hash, patchDoc.Patches[0].Githash)
+}
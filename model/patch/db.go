@@ -34,7 +34,10 @@ var (
 	PatchesKey         = bsonutil.MustHaveTag(Patch{}, "Patches")
 	ActivatedKey       = bsonutil.MustHaveTag(Patch{}, "Activated")
 	PatchedConfigKey   = bsonutil.MustHaveTag(Patch{}, "PatchedConfig")
+	SourceRefKey       = bsonutil.MustHaveTag(Patch{}, "SourceRef")
+	SkippedTasksKey    = bsonutil.MustHaveTag(Patch{}, "SkippedTasks")
 	githubPatchDataKey = bsonutil.MustHaveTag(Patch{}, "GithubPatchData")
+	gitlabPatchDataKey = bsonutil.MustHaveTag(Patch{}, "GitlabPatchData")
 
 	// BSON fields for the module patch struct
 	ModulePatchNameKey    = bsonutil.MustHaveTag(ModulePatch{}, "ModuleName")
@@ -59,6 +62,10 @@ var (
 	githubPatchHeadRepoKey   = bsonutil.MustHaveTag(GithubPatch{}, "HeadRepo")
 	githubPatchHeadHashKey   = bsonutil.MustHaveTag(GithubPatch{}, "HeadHash")
 	githubPatchAuthorKey     = bsonutil.MustHaveTag(GithubPatch{}, "Author")
+
+	// BSON fields for GitlabPatch
+	gitlabPatchMRNumberKey  = bsonutil.MustHaveTag(GitlabPatch{}, "MRNumber")
+	gitlabPatchProjectIDKey = bsonutil.MustHaveTag(GitlabPatch{}, "ProjectID")
 )
 
 // Query Validation
@@ -207,3 +214,15 @@ func ByGithubPRAndCreatedBefore(t time.Time, owner, repo string, prNumber int) d
 		bsonutil.GetDottedKeyName(githubPatchDataKey, githubPatchPRNumberKey):  prNumber,
 	})
 }
+
+// ByGitlabMRAndCreatedBefore finds all patches that were created for a GitLab
+// merge request before the given time.
+func ByGitlabMRAndCreatedBefore(t time.Time, projectID, mrNumber int) db.Q {
+	return db.Query(bson.M{
+		CreateTimeKey: bson.M{
+			"$lt": t,
+		},
+		bsonutil.GetDottedKeyName(gitlabPatchDataKey, gitlabPatchProjectIDKey): projectID,
+		bsonutil.GetDottedKeyName(gitlabPatchDataKey, gitlabPatchMRNumberKey):  mrNumber,
+	})
+}
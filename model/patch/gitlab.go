@@ -0,0 +1,244 @@
+package patch
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/evergreen-ci/evergreen"
+	"github.com/evergreen-ci/evergreen/db"
+	"github.com/pkg/errors"
+	"gopkg.in/mgo.v2/bson"
+)
+
+// GitlabIntentType represents patch intents created for GitLab.
+const GitlabIntentType = "gitlab"
+
+// GitlabMergeRequestEvent is the payload of a GitLab merge request webhook.
+// Only the fields that Evergreen uses are included.
+type GitlabMergeRequestEvent struct {
+	ObjectKind       string                       `json:"object_kind"`
+	User             GitlabUser                   `json:"user"`
+	Project          GitlabProject                `json:"project"`
+	ObjectAttributes GitlabMergeRequestAttributes `json:"object_attributes"`
+}
+
+// GitlabUser is a GitLab user in a webhook payload.
+type GitlabUser struct {
+	ID       int    `json:"id"`
+	Name     string `json:"name"`
+	Username string `json:"username"`
+}
+
+// GitlabProject is a GitLab project in a webhook payload.
+type GitlabProject struct {
+	ID                int    `json:"id"`
+	PathWithNamespace string `json:"path_with_namespace"`
+	WebURL            string `json:"web_url"`
+	GitHTTPURL        string `json:"git_http_url"`
+	GitSSHURL         string `json:"git_ssh_url"`
+}
+
+// GitlabMergeRequestAttributes describes the merge request in a merge
+// request webhook payload.
+type GitlabMergeRequestAttributes struct {
+	IID          int    `json:"iid"`
+	Title        string `json:"title"`
+	TargetBranch string `json:"target_branch"`
+	SourceBranch string `json:"source_branch"`
+	AuthorID     int    `json:"author_id"`
+	State        string `json:"state"`
+	Action       string `json:"action"`
+	URL          string `json:"url"`
+	OldRev       string `json:"oldrev"`
+	LastCommit   struct {
+		ID string `json:"id"`
+	} `json:"last_commit"`
+}
+
+// gitlabIntent represents an intent to create a patch build as a result of a
+// GitLab merge request webhook. These intents are processed asynchronously
+// by an amboy queue.
+type gitlabIntent struct {
+	// DocumentID is the same as the MsgID.
+	DocumentID string `bson:"_id"`
+
+	// MsgID is a GUID provided by GitLab (X-Gitlab-Event-UUID) for the
+	// event, or generated by Evergreen for instances that don't send one.
+	MsgID string `bson:"msg_id"`
+
+	// ProjectID is the GitLab ID of the project that this merge request
+	// will be merged into.
+	ProjectID int `bson:"project_id"`
+
+	// ProjectPath is the full path of the project, ex: group/project
+	ProjectPath string `bson:"project_path"`
+
+	// ProjectURL is the web URL of the project.
+	ProjectURL string `bson:"project_url"`
+
+	// RepoURLs are the URLs that the project can be cloned from.
+	RepoURLs []string `bson:"repo_urls"`
+
+	// BaseBranch is the branch that this merge request was opened against
+	BaseBranch string `bson:"base_branch"`
+
+	// MRNumber is the project-level ID (iid) of the merge request.
+	MRNumber int `bson:"mr_number"`
+
+	// User is the username of the GitLab user that triggered the event
+	User string `bson:"user"`
+
+	// AuthorID is the GitLab ID of the merge request's author
+	AuthorID int `bson:"author_id"`
+
+	// HeadHash is the hash of the most recent commit of the merge request.
+	HeadHash string `bson:"head_hash"`
+
+	// Title is the title of the merge request
+	Title string `bson:"title"`
+
+	// CreatedAt is the time that this intent was stored in the database
+	CreatedAt time.Time `bson:"created_at"`
+
+	// Processed indicates whether a patch intent has been processed by the amboy queue.
+	Processed bool `bson:"processed"`
+
+	// ProcessedAt is the time that this intent was processed
+	ProcessedAt time.Time `bson:"processed_at"`
+
+	// IntentType indicates the type of the patch intent, i.e. GitlabIntentType
+	IntentType string `bson:"intent_type"`
+}
+
+// NewGitlabIntent creates an Intent from a GitLab merge request webhook, or
+// returns an error if some part of the event is invalid. If msgID is empty,
+// a new one is generated.
+func NewGitlabIntent(msgID string, event *GitlabMergeRequestEvent) (Intent, error) {
+	if event == nil {
+		return nil, errors.New("merge request event is missing")
+	}
+	if msgID == "" {
+		msgID = bson.NewObjectId().Hex()
+	}
+	if event.Project.ID == 0 {
+		return nil, errors.New("project ID must not be 0")
+	}
+	if event.Project.PathWithNamespace == "" || event.Project.WebURL == "" {
+		return nil, errors.New("project path or URL is empty")
+	}
+	if event.Project.GitHTTPURL == "" && event.Project.GitSSHURL == "" {
+		return nil, errors.New("project has no repository URLs")
+	}
+	if event.ObjectAttributes.IID == 0 {
+		return nil, errors.New("merge request number must not be 0")
+	}
+	if event.ObjectAttributes.TargetBranch == "" {
+		return nil, errors.New("target branch is empty")
+	}
+	if event.ObjectAttributes.LastCommit.ID == "" {
+		return nil, errors.New("head hash must not be empty")
+	}
+	if event.User.Username == "" {
+		return nil, errors.New("GitLab user is missing a username")
+	}
+
+	repoURLs := []string{}
+	for _, u := range []string{event.Project.GitHTTPURL, event.Project.GitSSHURL} {
+		if u != "" {
+			repoURLs = append(repoURLs, u)
+		}
+	}
+
+	return &gitlabIntent{
+		DocumentID:  msgID,
+		MsgID:       msgID,
+		ProjectID:   event.Project.ID,
+		ProjectPath: event.Project.PathWithNamespace,
+		ProjectURL:  strings.TrimSuffix(event.Project.WebURL, "/"),
+		RepoURLs:    repoURLs,
+		BaseBranch:  event.ObjectAttributes.TargetBranch,
+		MRNumber:    event.ObjectAttributes.IID,
+		User:        event.User.Username,
+		AuthorID:    event.ObjectAttributes.AuthorID,
+		HeadHash:    event.ObjectAttributes.LastCommit.ID,
+		Title:       event.ObjectAttributes.Title,
+		IntentType:  GitlabIntentType,
+	}, nil
+}
+
+// SetProcessed should be called by an amboy queue after creating a patch from an intent.
+func (g *gitlabIntent) SetProcessed() error {
+	g.Processed = true
+	g.ProcessedAt = time.Now().Round(time.Millisecond)
+	return updateOneIntent(
+		bson.M{documentIDKey: g.DocumentID},
+		bson.M{"$set": bson.M{
+			processedKey:   g.Processed,
+			processedAtKey: g.ProcessedAt,
+		}},
+	)
+}
+
+// IsProcessed returns whether a patch exists for this intent.
+func (g *gitlabIntent) IsProcessed() bool {
+	return g.Processed
+}
+
+// GetType returns the patch intent, i.e. GitlabIntentType.
+func (g *gitlabIntent) GetType() string {
+	return g.IntentType
+}
+
+// Insert inserts a patch intent in the database.
+func (g *gitlabIntent) Insert() error {
+	g.CreatedAt = time.Now().Round(time.Millisecond)
+	err := db.Insert(IntentCollection, g)
+	if err != nil {
+		g.CreatedAt = time.Time{}
+		return err
+	}
+
+	return nil
+}
+
+func (g *gitlabIntent) ID() string {
+	return g.MsgID
+}
+
+func (g *gitlabIntent) ShouldFinalizePatch() bool {
+	return true
+}
+
+func (g *gitlabIntent) RequesterIdentity() string {
+	return evergreen.GitlabMRRequester
+}
+
+func (g *gitlabIntent) NewPatch() *Patch {
+	mergeRequestURL := fmt.Sprintf("%s/merge_requests/%d", g.ProjectURL, g.MRNumber)
+	patchDoc := &Patch{
+		Alias:       GithubAlias,
+		Description: fmt.Sprintf("'%s' merge request !%d by %s: %s (%s)", g.ProjectPath, g.MRNumber, g.User, g.Title, mergeRequestURL),
+		Author:      evergreen.GitlabPatchUser,
+		Status:      evergreen.PatchCreated,
+		CreateTime:  g.CreatedAt,
+		GitlabPatchData: GitlabPatch{
+			MRNumber:    g.MRNumber,
+			ProjectID:   g.ProjectID,
+			ProjectPath: g.ProjectPath,
+			ProjectURL:  g.ProjectURL,
+			RepoURLs:    g.RepoURLs,
+			BaseBranch:  g.BaseBranch,
+			HeadHash:    g.HeadHash,
+			Author:      g.User,
+			AuthorID:    g.AuthorID,
+		},
+	}
+	return patchDoc
+}
+
+// GetAlias returns the pull request alias, since merge requests run the same
+// variants and tasks as pull requests.
+func (g *gitlabIntent) GetAlias() string {
+	return GithubAlias
+}
@@ -0,0 +1,153 @@
+package patch
+
+import (
+	"testing"
+
+	"github.com/evergreen-ci/evergreen"
+	"github.com/evergreen-ci/evergreen/db"
+	"github.com/evergreen-ci/evergreen/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+	"gopkg.in/mgo.v2/bson"
+)
+
+type GitlabSuite struct {
+	suite.Suite
+	event *GitlabMergeRequestEvent
+}
+
+func TestGitlabSuite(t *testing.T) {
+	suite.Run(t, new(GitlabSuite))
+}
+
+func (s *GitlabSuite) SetupSuite() {
+	db.SetGlobalSessionProvider(testutil.TestConfig().SessionFactory())
+}
+
+func (s *GitlabSuite) SetupTest() {
+	s.Require().NoError(db.Clear(IntentCollection))
+
+	s.event = &GitlabMergeRequestEvent{
+		ObjectKind: "merge_request",
+		User:       GitlabUser{ID: 12, Username: "tanuki"},
+		Project: GitlabProject{
+			ID:                42,
+			PathWithNamespace: "group/evergreen",
+			WebURL:            "https://gitlab.example.com/group/evergreen/",
+			GitHTTPURL:        "https://gitlab.example.com/group/evergreen.git",
+			GitSSHURL:         "git@gitlab.example.com:group/evergreen.git",
+		},
+		ObjectAttributes: GitlabMergeRequestAttributes{
+			IID:          7,
+			Title:        "Art of Merge Requests",
+			TargetBranch: "master",
+			AuthorID:     12,
+			Action:       "open",
+		},
+	}
+	s.event.ObjectAttributes.LastCommit.ID = "67da19930b1b18d346477e99a8e18094a672f48a"
+}
+
+func (s *GitlabSuite) TestNewGitlabIntent() {
+	intent, err := NewGitlabIntent("1", nil)
+	s.Nil(intent)
+	s.Error(err)
+
+	for name, invalidate := range map[string]func(*GitlabMergeRequestEvent){
+		"ProjectID":    func(e *GitlabMergeRequestEvent) { e.Project.ID = 0 },
+		"ProjectPath":  func(e *GitlabMergeRequestEvent) { e.Project.PathWithNamespace = "" },
+		"RepoURLs":     func(e *GitlabMergeRequestEvent) { e.Project.GitHTTPURL, e.Project.GitSSHURL = "", "" },
+		"MRNumber":     func(e *GitlabMergeRequestEvent) { e.ObjectAttributes.IID = 0 },
+		"TargetBranch": func(e *GitlabMergeRequestEvent) { e.ObjectAttributes.TargetBranch = "" },
+		"HeadHash":     func(e *GitlabMergeRequestEvent) { e.ObjectAttributes.LastCommit.ID = "" },
+		"User":         func(e *GitlabMergeRequestEvent) { e.User.Username = "" },
+	} {
+		event := *s.event
+		invalidate(&event)
+		intent, err = NewGitlabIntent("1", &event)
+		s.Nil(intent, name)
+		s.Error(err, name)
+	}
+
+	intent, err = NewGitlabIntent("4", s.event)
+	s.NoError(err)
+	s.Require().NotNil(intent)
+	s.Implements((*Intent)(nil), intent)
+	gitlabIntent, ok := intent.(*gitlabIntent)
+	s.Require().True(ok)
+	s.Equal("4", gitlabIntent.MsgID)
+	s.Equal(42, gitlabIntent.ProjectID)
+	s.Equal("group/evergreen", gitlabIntent.ProjectPath)
+	s.Equal("https://gitlab.example.com/group/evergreen", gitlabIntent.ProjectURL)
+	s.Equal([]string{"https://gitlab.example.com/group/evergreen.git", "git@gitlab.example.com:group/evergreen.git"}, gitlabIntent.RepoURLs)
+	s.Equal(7, gitlabIntent.MRNumber)
+	s.Equal("tanuki", gitlabIntent.User)
+	s.Zero(gitlabIntent.ProcessedAt)
+	s.False(intent.IsProcessed())
+	s.Equal(GitlabIntentType, intent.GetType())
+	s.Equal(evergreen.GitlabMRRequester, intent.RequesterIdentity())
+	s.Equal(GithubAlias, intent.GetAlias())
+
+	intent, err = NewGitlabIntent("", s.event)
+	s.NoError(err)
+	s.Require().NotNil(intent)
+	s.NotEmpty(intent.ID(), "instances that don't identify events get a generated ID")
+}
+
+func (s *GitlabSuite) TestNewPatch() {
+	intent, err := NewGitlabIntent("4", s.event)
+	s.Require().NoError(err)
+
+	patchDoc := intent.NewPatch()
+	s.Require().NotNil(patchDoc)
+	s.Equal("'group/evergreen' merge request !7 by tanuki: Art of Merge Requests (https://gitlab.example.com/group/evergreen/merge_requests/7)", patchDoc.Description)
+	s.Equal(evergreen.GitlabPatchUser, patchDoc.Author)
+	s.Equal(evergreen.PatchCreated, patchDoc.Status)
+	s.Equal(GithubAlias, patchDoc.Alias)
+	s.Empty(patchDoc.Patches)
+	s.False(patchDoc.IsGithubPRPatch())
+	s.True(patchDoc.IsGitlabMRPatch())
+	s.Equal(7, patchDoc.GitlabPatchData.MRNumber)
+	s.Equal(42, patchDoc.GitlabPatchData.ProjectID)
+	s.Equal("master", patchDoc.GitlabPatchData.BaseBranch)
+	s.Equal("67da19930b1b18d346477e99a8e18094a672f48a", patchDoc.GitlabPatchData.HeadHash)
+	s.Equal("tanuki", patchDoc.GitlabPatchData.Author)
+	s.Equal(12, patchDoc.GitlabPatchData.AuthorID)
+	s.Len(patchDoc.GitlabPatchData.RepoURLs, 2)
+}
+
+func (s *GitlabSuite) TestInsertAndSetProcessed() {
+	intent, err := NewGitlabIntent("300", s.event)
+	s.Require().NoError(err)
+	s.NoError(intent.Insert())
+
+	found, err := FindIntent(intent.ID(), intent.GetType())
+	s.NoError(err)
+	s.Require().NotNil(found)
+	s.Equal(intent, found)
+	s.Equal(intent.NewPatch(), found.NewPatch())
+
+	s.NoError(intent.SetProcessed())
+	var intents []gitlabIntent
+	s.NoError(db.FindAllQ(IntentCollection, db.Query(bson.M{processedKey: true}), &intents))
+	s.Require().Len(intents, 1)
+	s.Equal(7, intents[0].MRNumber)
+	s.True(intents[0].IsProcessed())
+	s.Equal(GitlabIntentType, intents[0].GetType())
+}
+
+func TestGitlabPatchAPIURL(t *testing.T) {
+	assert := assert.New(t)
+
+	data := GitlabPatch{ProjectPath: "group/sub/project", ProjectURL: "https://gitlab.example.com/gitlab/group/sub/project"}
+	apiURL, err := data.APIURL()
+	assert.NoError(err)
+	assert.Equal("https://gitlab.example.com/gitlab/api/v4", apiURL)
+
+	data.ProjectURL = "https://gitlab.example.com/other/project"
+	_, err = data.APIURL()
+	assert.Error(err)
+
+	_, err = GitlabPatch{}.APIURL()
+	assert.Error(err)
+}
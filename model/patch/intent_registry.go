@@ -33,6 +33,7 @@ func init() {
 		r: map[string]patchIntentFactory{
 			GithubIntentType: func() Intent { return &githubIntent{} },
 			CliIntentType:    func() Intent { return &cliIntent{} },
+			GitlabIntentType: func() Intent { return &gitlabIntent{} },
 		},
 	}
 }
@@ -2,10 +2,12 @@ package patch
 
 import (
 	"io/ioutil"
+	"strings"
 	"time"
 
 	"github.com/evergreen-ci/evergreen"
 	"github.com/evergreen-ci/evergreen/db"
+	"github.com/pkg/errors"
 	"gopkg.in/mgo.v2"
 	"gopkg.in/mgo.v2/bson"
 )
@@ -25,6 +27,14 @@ type DisplayTask struct {
 	ExecTasks []string
 }
 
+// SkippedTask is a task of a patch's variants that wasn't created because
+// the patch's changes don't affect it.
+type SkippedTask struct {
+	Variant string `bson:"variant"`
+	Task    string `bson:"task"`
+	Reason  string `bson:"reason"`
+}
+
 // Patch stores all details related to a patch request
 type Patch struct {
 	Id              bson.ObjectId  `bson:"_id,omitempty"`
@@ -45,7 +55,10 @@ type Patch struct {
 	Activated       bool           `bson:"activated"`
 	PatchedConfig   string         `bson:"patched_config"`
 	Alias           string         `bson:"alias"`
+	SourceRef       string         `bson:"source_ref,omitempty"`
+	SkippedTasks    []SkippedTask  `bson:"skipped_tasks,omitempty"`
 	GithubPatchData GithubPatch    `bson:"github_patch_data,omitempty"`
+	GitlabPatchData GitlabPatch    `bson:"gitlab_patch_data,omitempty"`
 }
 
 // GithubPatch stores patch data for patches create from GitHub pull requests
@@ -61,6 +74,32 @@ type GithubPatch struct {
 	AuthorUID  int    `bson:"author_uid"`
 }
 
+// GitlabPatch stores patch data for patches created from GitLab merge
+// requests
+type GitlabPatch struct {
+	MRNumber    int      `bson:"mr_number"`
+	ProjectID   int      `bson:"project_id"`
+	ProjectPath string   `bson:"project_path"`
+	ProjectURL  string   `bson:"project_url"`
+	RepoURLs    []string `bson:"repo_urls"`
+	BaseBranch  string   `bson:"base_branch"`
+	HeadHash    string   `bson:"head_hash"`
+	Author      string   `bson:"author"`
+	AuthorID    int      `bson:"author_id"`
+}
+
+// APIURL returns the base URL of the GitLab API of the instance that hosts
+// the merge request's project. The project URL comes from the merge request's
+// webhook, so use ProjectRef.GitlabAPIURL, which checks its host, before
+// sending credentials to it.
+func (g GitlabPatch) APIURL() (string, error) {
+	if g.ProjectPath == "" || !strings.HasSuffix(g.ProjectURL, "/"+g.ProjectPath) {
+		return "", errors.Errorf("project URL '%s' does not end with the project's path '%s'", g.ProjectURL, g.ProjectPath)
+	}
+
+	return strings.TrimSuffix(g.ProjectURL, "/"+g.ProjectPath) + "/api/v4", nil
+}
+
 // ModulePatch stores request details for a patch
 type ModulePatch struct {
 	ModuleName string   `bson:"name"`
@@ -177,6 +216,19 @@ func (p *Patch) SetVariantsTasks(variantsTasks []VariantTasks) error {
 	)
 }
 
+// SetSkippedTasks records the tasks that weren't created for the patch.
+func (p *Patch) SetSkippedTasks(skipped []SkippedTask) error {
+	p.SkippedTasks = skipped
+	return UpdateOne(
+		bson.M{IdKey: p.Id},
+		bson.M{
+			"$set": bson.M{
+				SkippedTasksKey: skipped,
+			},
+		},
+	)
+}
+
 // AddBuildVariants adds more buildvarints to a patch document.
 // This is meant to be used after initial patch creation.
 func (p *Patch) AddBuildVariants(bvs []string) error {
@@ -253,6 +305,31 @@ func (p *Patch) ConfigChanged(remotePath string) bool {
 	return false
 }
 
+// ChangedFiles returns the paths of the files that the patch changes in the
+// project itself, excluding its modules.
+func (p *Patch) ChangedFiles() []string {
+	files := []string{}
+	for _, patchPart := range p.Patches {
+		if patchPart.ModuleName == "" {
+			for _, summary := range patchPart.PatchSet.Summary {
+				files = append(files, summary.Name)
+			}
+		}
+	}
+	return files
+}
+
+// ModulesChanged returns true if the patch changes any of the project's
+// modules.
+func (p *Patch) ModulesChanged() bool {
+	for _, patchPart := range p.Patches {
+		if patchPart.ModuleName != "" && (len(patchPart.PatchSet.Summary) > 0 || patchPart.PatchSet.Patch != "" || patchPart.PatchSet.PatchFileId != "") {
+			return true
+		}
+	}
+	return false
+}
+
 // SetActivated sets the patch to activated in the db
 func (p *Patch) SetActivated(versionId string) error {
 	p.Version = versionId
@@ -287,7 +364,7 @@ func (p *Patch) SetActivation(activated bool) error {
 func (p *Patch) UpdateModulePatch(modulePatch ModulePatch) error {
 	// check that a patch for this module exists
 	query := bson.M{
-		IdKey: p.Id,
+		IdKey:                                 p.Id,
 		PatchesKey + "." + ModulePatchNameKey: modulePatch.ModuleName,
 	}
 	update := bson.M{
@@ -328,3 +405,7 @@ func (p *Patch) RemoveModulePatch(moduleName string) error {
 func (p *Patch) IsGithubPRPatch() bool {
 	return p.GithubPatchData.PRNumber != 0
 }
+
+func (p *Patch) IsGitlabMRPatch() bool {
+	return p.GitlabPatchData.MRNumber != 0
+}
@@ -32,6 +32,26 @@ func TestConfigChanged(t *testing.T) {
 	assert.False(p.ConfigChanged(remoteConfigPath))
 }
 
+func TestModulesChanged(t *testing.T) {
+	assert := assert.New(t)
+	p := &Patch{
+		Patches: []ModulePatch{
+			{
+				PatchSet: PatchSet{
+					Summary: []Summary{{Name: "main.go"}},
+				},
+			},
+			{
+				ModuleName: "enterprise",
+			},
+		},
+	}
+	assert.False(p.ModulesChanged())
+
+	p.Patches[1].PatchSet.Summary = []Summary{{Name: "module.go"}}
+	assert.True(p.ModulesChanged())
+}
+
 type patchSuite struct {
 	suite.Suite
 	testConfig *evergreen.Settings
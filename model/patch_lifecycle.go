@@ -217,6 +217,42 @@ func MakePatchedConfig(ctx context.Context, p *patch.Patch, remoteConfigPath, pr
 	return nil, errors.New("no patch on project")
 }
 
+// checkPatchPreflight returns a PatchConflictError if the patch of the
+// project or of one of its modules doesn't apply cleanly to the revision it's
+// based on. Only the changes that were submitted by users to GitHub projects
+// are checked, since the diffs of pull requests are made against their merge
+// bases, and the files are read with the GitHub API. The check is only an
+// early warning, so patches that are too large to check are skipped, and
+// other errors are logged rather than returned.
+func checkPatchPreflight(ctx context.Context, p *patch.Patch, project *Project, projectRef *ProjectRef, requester string, githubOauthToken string) error {
+	if requester != evergreen.PatchVersionRequester || projectRef.RepoKind == GitRepoType {
+		return nil
+	}
+
+	err := p.FetchPatchFiles()
+	if err == nil {
+		err = CheckPatchConflicts(ctx, p, project, projectRef, nil, githubOauthToken)
+	}
+	if IsPatchConflict(err) {
+		return errors.WithStack(err)
+	}
+	if IsPatchTooLarge(err) {
+		grip.Info(message.WrapError(err, message.Fields{
+			"message":  "skipping conflict check of large patch",
+			"patch_id": p.Id.Hex(),
+			"project":  p.Project,
+		}))
+		return nil
+	}
+
+	grip.Warning(message.WrapError(err, message.Fields{
+		"message":  "can't check patch for conflicts, finalizing it anyway",
+		"patch_id": p.Id.Hex(),
+		"project":  p.Project,
+	}))
+	return nil
+}
+
 // Finalizes a patch:
 // Patches a remote project's configuration file if needed.
 // Creates a version for this patch and links it.
@@ -236,12 +272,22 @@ func FinalizePatch(ctx context.Context, p *patch.Patch, requester string, github
 		return nil, errors.WithStack(err)
 	}
 
-	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
-	defer cancel()
+	// the base revision of patches on other git remotes was already read
+	// from the project's mirror
+	if projectRef.RepoKind != GitRepoType {
+		ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+		defer cancel()
 
-	_, err = thirdparty.GetCommitEvent(ctx, githubOauthToken, projectRef.Owner, projectRef.Repo, p.Githash)
-	if err != nil {
-		return nil, errors.Wrap(err, "Couldn't fetch commit information")
+		_, err = thirdparty.GetCommitEvent(ctx, githubOauthToken, projectRef.Owner, projectRef.Repo, p.Githash)
+		if err != nil {
+			return nil, errors.Wrap(err, "Couldn't fetch commit information")
+		}
+	}
+
+	// reject changes that no longer apply to the revisions they're based
+	// on, rather than failing once tasks apply them on hosts
+	if err = checkPatchPreflight(ctx, p, project, projectRef, requester, githubOauthToken); err != nil {
+		return nil, errors.WithStack(err)
 	}
 
 	patchVersion := &version.Version{
@@ -280,6 +326,16 @@ func FinalizePatch(ctx context.Context, p *patch.Patch, requester string, github
 		}).TVPairsToVariantTasks()
 	}
 
+	// impacts only map the project's own files, so a patch that changes a
+	// module could affect any of its tasks
+	changedFiles := p.ChangedFiles()
+	if project.ImpactSelection && len(changedFiles) > 0 && !p.ModulesChanged() {
+		if err = selectPatchTasksByImpact(p, project, changedFiles); err != nil {
+			return nil, errors.Wrap(err, "problem selecting patch tasks by impact")
+		}
+		tasks = VariantTasksToTVPairs(p.VariantsTasks)
+	}
+
 	taskIds := NewPatchTaskIdTable(project, patchVersion, tasks)
 	variantsProcessed := map[string]bool{}
 	for _, vt := range p.VariantsTasks {
@@ -293,7 +349,12 @@ func FinalizePatch(ctx context.Context, p *patch.Patch, requester string, github
 			displayNames = append(displayNames, dt.Name)
 		}
 		taskNames := tasks.ExecTasks.TaskNames(vt.Variant)
-		buildId, err = CreateBuildFromVersion(project, patchVersion, taskIds, vt.Variant, true, taskNames, displayNames, "")
+		// variants that the patch's changes don't affect are created inactive
+		activated := true
+		if bv := project.FindBuildVariant(vt.Variant); bv != nil {
+			activated = bv.IsAffectedBy(changedFiles)
+		}
+		buildId, err = CreateBuildFromVersion(project, patchVersion, taskIds, vt.Variant, activated, taskNames, displayNames, "", changedFiles)
 		if err != nil {
 			return nil, errors.WithStack(err)
 		}
@@ -301,7 +362,7 @@ func FinalizePatch(ctx context.Context, p *patch.Patch, requester string, github
 		patchVersion.BuildVariants = append(patchVersion.BuildVariants,
 			version.BuildStatus{
 				BuildVariant: vt.Variant,
-				Activated:    true,
+				Activated:    activated,
 				BuildId:      buildId,
 			},
 		)
@@ -366,3 +427,41 @@ func AbortPatchesWithGithubPatchData(createdBefore time.Time, owner, repo string
 
 	return errors.Wrap(catcher.Resolve(), "error aborting patches")
 }
+
+// AbortPatchesWithGitlabPatchData aborts the patches of a GitLab merge
+// request that were created before the given time.
+func AbortPatchesWithGitlabPatchData(createdBefore time.Time, projectID, mrNumber int) error {
+	patches, err := patch.Find(patch.ByGitlabMRAndCreatedBefore(createdBefore, projectID, mrNumber))
+	if err != nil {
+		return errors.Wrap(err, "initial patch fetch failed")
+	}
+	grip.Info(message.Fields{
+		"source":         "gitlab hook",
+		"created_before": createdBefore.String(),
+		"project_id":     projectID,
+		"mr_number":      mrNumber,
+		"message":        "fetched patches to abort",
+		"num_patches":    len(patches),
+	})
+
+	catcher := grip.NewSimpleCatcher()
+	for i := range patches {
+		if patches[i].Version != "" {
+			if err = CancelPatch(&patches[i], evergreen.GitlabMRRequester); err != nil {
+				grip.Error(message.WrapError(err, message.Fields{
+					"source":         "gitlab hook",
+					"created_before": createdBefore.String(),
+					"project_id":     projectID,
+					"mr_number":      mrNumber,
+					"message":        "failed to abort patch's version",
+					"patch_id":       patches[i].Id,
+					"version":        patches[i].Version,
+				}))
+
+				catcher.Add(err)
+			}
+		}
+	}
+
+	return errors.Wrap(catcher.Resolve(), "error aborting patches")
+}
@@ -405,3 +405,37 @@ func TestAddNewPatch(t *testing.T) {
 	assert.Equal(dbTasks[2].DisplayName, "task2")
 	assert.Equal(dbTasks[3].DisplayName, "task3")
 }
+
+func TestCheckPatchPreflight(t *testing.T) {
+	assert := assert.New(t)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	changes := ""
+	for i := 0; i <= maxConflictCheckPatchedFiles; i++ {
+		changes += fmt.Sprintf("diff --git a/file%d b/file%d\n--- a/file%d\n+++ b/file%d\n@@ -1 +1 @@\n-old\n+new\n", i, i, i, i)
+	}
+	p := &patch.Patch{
+		Project: "proj",
+		Githash: "abc",
+		Patches: []patch.ModulePatch{
+			{Githash: "abc", PatchSet: patch.PatchSet{Patch: changes}},
+		},
+	}
+	pref := &ProjectRef{Identifier: "proj", Owner: "evergreen-ci", Repo: "evergreen"}
+
+	// pull requests aren't checked
+	assert.NoError(checkPatchPreflight(ctx, p, &Project{}, pref, evergreen.GithubPRRequester, ""))
+
+	// nor are patches that change too many files to fetch
+	assert.NoError(checkPatchPreflight(ctx, p, &Project{}, pref, evergreen.PatchVersionRequester, ""))
+
+	// nor are patches of projects that aren't on GitHub
+	p.Patches[0].PatchSet.Patch = "diff --git a/file b/file\n--- a/file\n+++ b/file\n@@ -1 +1 @@\n-old\n+new\n"
+	gitRef := &ProjectRef{Identifier: "proj", RepoKind: GitRepoType, RepoURL: "https://gitlab.example.com/group/proj.git"}
+	assert.NoError(checkPatchPreflight(ctx, p, &Project{}, gitRef, evergreen.PatchVersionRequester, ""))
+
+	// errors other than conflicts don't keep the patch from being finalized
+	p.Patches[0].ModuleName = "missing"
+	assert.NoError(checkPatchPreflight(ctx, p, &Project{}, pref, evergreen.PatchVersionRequester, ""))
+}
@@ -0,0 +1,216 @@
+package model
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/evergreen-ci/evergreen"
+	"github.com/evergreen-ci/evergreen/model/manifest"
+	"github.com/evergreen-ci/evergreen/model/patch"
+	"github.com/evergreen-ci/evergreen/model/version"
+	"github.com/evergreen-ci/evergreen/thirdparty"
+	"github.com/pkg/errors"
+)
+
+// PatchConflict is the patch of the project or of one of its modules that
+// doesn't apply cleanly to a revision of its repository.
+type PatchConflict struct {
+	ModuleName string `json:"module,omitempty"`
+	Revision   string `json:"revision"`
+	Output     string `json:"output"`
+}
+
+// PatchConflictError is returned when the changes of a patch don't apply
+// cleanly to the revisions they're based on.
+type PatchConflictError struct {
+	Conflicts []PatchConflict
+}
+
+func (e *PatchConflictError) Error() string {
+	lines := []string{"patch does not apply cleanly"}
+	for _, c := range e.Conflicts {
+		name := "project"
+		if c.ModuleName != "" {
+			name = fmt.Sprintf("module '%s'", c.ModuleName)
+		}
+		lines = append(lines, fmt.Sprintf("%s at revision %s:\n%s", name, c.Revision, c.Output))
+	}
+	return strings.Join(lines, "\n")
+}
+
+// IsPatchConflict returns true if the cause of the error is a
+// PatchConflictError.
+func IsPatchConflict(err error) bool {
+	_, ok := errors.Cause(err).(*PatchConflictError)
+	return ok
+}
+
+const (
+	// maxConflictCheckPatchedFiles is the most files that a patch can change,
+	// across the project and its modules, for it to be checked for
+	// conflicts, since each of the files is fetched with its own request to
+	// the GitHub API.
+	maxConflictCheckPatchedFiles = 100
+
+	// maxConflictCheckFileSize is the size in bytes of the largest file that
+	// can be checked for conflicts, since the GitHub API doesn't return the
+	// contents of larger files.
+	maxConflictCheckFileSize = 1024 * 1024
+)
+
+// PatchTooLargeError is returned when a patch changes too many files, or
+// files that are too large, for it to be checked for conflicts.
+type PatchTooLargeError struct {
+	Reason string
+}
+
+func (e *PatchTooLargeError) Error() string {
+	return fmt.Sprintf("patch is too large to check for conflicts: %s", e.Reason)
+}
+
+// IsPatchTooLarge returns true if the cause of the error is a
+// PatchTooLargeError.
+func IsPatchTooLarge(err error) bool {
+	_, ok := errors.Cause(err).(*PatchTooLargeError)
+	return ok
+}
+
+// CheckPatchConflicts checks that the patch of the project and of each of its
+// modules applies cleanly to the revision of its repository in revisions,
+// which is keyed by module name with the project under the empty name, or
+// else to the revision it's based on. The patch's files must already be
+// fetched. It returns a PatchConflictError describing the patches that don't
+// apply, or a PatchTooLargeError without checking anything if the patch
+// changes more than maxConflictCheckPatchedFiles files or a file larger than
+// maxConflictCheckFileSize.
+func CheckPatchConflicts(ctx context.Context, p *patch.Patch, project *Project, pref *ProjectRef, revisions map[string]string, githubOauthToken string) error {
+	numFiles := 0
+	for _, modulePatch := range p.Patches {
+		numFiles += len(thirdparty.GetPatchedFilePaths(modulePatch.PatchSet.Patch))
+	}
+	if numFiles > maxConflictCheckPatchedFiles {
+		return &PatchTooLargeError{
+			Reason: fmt.Sprintf("it changes %d files, more than the limit of %d", numFiles, maxConflictCheckPatchedFiles),
+		}
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, time.Minute)
+	defer cancel()
+
+	conflicts := []PatchConflict{}
+	for _, modulePatch := range p.Patches {
+		if modulePatch.PatchSet.Patch == "" {
+			continue
+		}
+		revision := modulePatch.Githash
+		if rev, ok := revisions[modulePatch.ModuleName]; ok {
+			revision = rev
+		}
+
+		owner, repo := pref.Owner, pref.Repo
+		if modulePatch.ModuleName != "" {
+			module, err := project.GetModuleByName(modulePatch.ModuleName)
+			if err != nil || module == nil {
+				return errors.Errorf("no module named '%s'", modulePatch.ModuleName)
+			}
+			owner, repo = module.GetRepoOwnerAndName()
+		}
+
+		files := map[string]string{}
+		for _, path := range thirdparty.GetPatchedFilePaths(modulePatch.PatchSet.Patch) {
+			file, err := thirdparty.GetGithubFile(ctx, githubOauthToken, owner, repo, path, revision)
+			if thirdparty.IsFileNotFound(err) {
+				continue
+			}
+			if err != nil {
+				return errors.Wrapf(err, "can't get '%s' at revision %s", path, revision)
+			}
+			if file.GetSize() > maxConflictCheckFileSize {
+				return &PatchTooLargeError{
+					Reason: fmt.Sprintf("'%s' is %d bytes at revision %s, more than the limit of %d", path, file.GetSize(), revision, maxConflictCheckFileSize),
+				}
+			}
+			files[path], err = file.GetContent()
+			if err != nil {
+				return errors.Wrapf(err, "can't decode '%s' at revision %s", path, revision)
+			}
+		}
+
+		out, err := thirdparty.GitApplyCheck(ctx, modulePatch.PatchSet.Patch, files)
+		if err != nil {
+			return errors.WithStack(err)
+		}
+		if out != "" {
+			conflicts = append(conflicts, PatchConflict{
+				ModuleName: modulePatch.ModuleName,
+				Revision:   revision,
+				Output:     out,
+			})
+		}
+	}
+
+	if len(conflicts) > 0 {
+		return &PatchConflictError{Conflicts: conflicts}
+	}
+	return nil
+}
+
+// GetLatestPatchRevisions returns the revisions that the project and the
+// modules of a patch would be based on if it were created now, keyed by
+// module name with the project under the empty name. The project is based
+// on its most recent mainline version, and the modules on their revisions
+// in that version's manifest, or else on the heads of their branches.
+func GetLatestPatchRevisions(ctx context.Context, p *patch.Patch, project *Project, githubOauthToken string) (map[string]string, error) {
+	latest, err := version.FindOne(version.ByMostRecentForRequester(p.Project, evergreen.RepotrackerVersionRequester))
+	if err != nil {
+		return nil, errors.Wrapf(err, "can't find the most recent version of project '%s'", p.Project)
+	}
+	if latest == nil {
+		return nil, errors.Errorf("project '%s' has no mainline versions", p.Project)
+	}
+	revisions := map[string]string{"": latest.Revision}
+
+	var versionManifest *manifest.Manifest
+	for _, modulePatch := range p.Patches {
+		if modulePatch.ModuleName == "" {
+			continue
+		}
+		module, err := project.GetModuleByName(modulePatch.ModuleName)
+		if err != nil || module == nil {
+			return nil, errors.Errorf("no module named '%s'", modulePatch.ModuleName)
+		}
+		if module.Ref != "" {
+			revisions[module.Name] = module.Ref
+			continue
+		}
+
+		if versionManifest == nil {
+			versionManifest, err = manifest.FindOne(manifest.ById(latest.Id))
+			if err != nil {
+				return nil, errors.Wrapf(err, "can't find the manifest of version '%s'", latest.Id)
+			}
+		}
+		if versionManifest != nil {
+			if m, ok := versionManifest.Modules[module.Name]; ok && m.Revision != "" {
+				revisions[module.Name] = m.Revision
+				continue
+			}
+		}
+
+		owner, repo := module.GetRepoOwnerAndName()
+		branchCtx, cancel := context.WithTimeout(ctx, 10*time.Second)
+		branch, err := thirdparty.GetBranchEvent(branchCtx, githubOauthToken, owner, repo, module.Branch)
+		cancel()
+		if err != nil {
+			return nil, errors.Wrapf(err, "can't find the head of branch '%s' of module '%s'", module.Branch, module.Name)
+		}
+		if branch.Commit == nil || branch.Commit.SHA == nil {
+			return nil, errors.Errorf("branch '%s' of module '%s' has no commits", module.Branch, module.Name)
+		}
+		revisions[module.Name] = *branch.Commit.SHA
+	}
+
+	return revisions, nil
+}
@@ -0,0 +1,116 @@
+package model
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/evergreen-ci/evergreen"
+	"github.com/evergreen-ci/evergreen/db"
+	"github.com/evergreen-ci/evergreen/model/manifest"
+	"github.com/evergreen-ci/evergreen/model/patch"
+	"github.com/evergreen-ci/evergreen/model/version"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetLatestPatchRevisions(t *testing.T) {
+	assert := assert.New(t)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	require.NoError(t, db.ClearCollections(version.Collection, manifest.Collection))
+
+	p := &patch.Patch{
+		Project: "proj",
+		Patches: []patch.ModulePatch{
+			{Githash: "old"},
+			{ModuleName: "pinned", Githash: "old-pinned"},
+			{ModuleName: "tracked", Githash: "old-tracked"},
+		},
+	}
+	project := &Project{
+		Modules: []Module{
+			{Name: "pinned", Repo: "git@github.com:evergreen-ci/pinned.git", Branch: "master", Ref: "pinned-ref"},
+			{Name: "tracked", Repo: "git@github.com:evergreen-ci/tracked.git", Branch: "master"},
+		},
+	}
+
+	_, err := GetLatestPatchRevisions(ctx, p, project, "")
+	assert.Error(err)
+
+	for i, revision := range []string{"first", "second"} {
+		v := &version.Version{
+			Id:                  revision,
+			Identifier:          "proj",
+			Revision:            revision,
+			Requester:           evergreen.RepotrackerVersionRequester,
+			RevisionOrderNumber: i + 1,
+		}
+		require.NoError(t, v.Insert())
+	}
+	_, err = (&manifest.Manifest{
+		Id:          "second",
+		Revision:    "second",
+		ProjectName: "proj",
+		Modules: map[string]*manifest.Module{
+			"tracked": {Branch: "master", Revision: "tracked-revision"},
+		},
+	}).TryInsert()
+	require.NoError(t, err)
+
+	revisions, err := GetLatestPatchRevisions(ctx, p, project, "")
+	require.NoError(t, err)
+	assert.Equal(map[string]string{
+		"":        "second",
+		"pinned":  "pinned-ref",
+		"tracked": "tracked-revision",
+	}, revisions)
+}
+
+func TestPatchConflictError(t *testing.T) {
+	err := &PatchConflictError{
+		Conflicts: []PatchConflict{
+			{Revision: "abc", Output: "error: patch failed: a.txt:1"},
+			{ModuleName: "enterprise", Revision: "def", Output: "error: b.txt: does not exist in index"},
+		},
+	}
+	assert.True(t, IsPatchConflict(err))
+	assert.True(t, IsPatchConflict(errors.Wrap(err, "problem finalizing patch")))
+	assert.False(t, IsPatchConflict(errors.New("error processing patch: "+err.Error())))
+	assert.False(t, IsPatchConflict(errors.New("error processing patch")))
+	assert.False(t, IsPatchConflict(nil))
+
+	lines := strings.Split(err.Error(), "\n")
+	assert.Equal(t, []string{
+		"patch does not apply cleanly",
+		"project at revision abc:",
+		"error: patch failed: a.txt:1",
+		"module 'enterprise' at revision def:",
+		"error: b.txt: does not exist in index",
+	}, lines)
+}
+
+func TestCheckPatchConflictsTooManyFiles(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	changes := ""
+	for i := 0; i < maxConflictCheckPatchedFiles; i++ {
+		changes += fmt.Sprintf("diff --git a/file%d b/file%d\n--- a/file%d\n+++ b/file%d\n@@ -1 +1 @@\n-old\n+new\n", i, i, i, i)
+	}
+	p := &patch.Patch{
+		Patches: []patch.ModulePatch{
+			{Githash: "abc", PatchSet: patch.PatchSet{Patch: changes}},
+			{ModuleName: "enterprise", Githash: "def", PatchSet: patch.PatchSet{Patch: "diff --git a/other b/other\n--- a/other\n+++ b/other\n@@ -1 +1 @@\n-old\n+new\n"}},
+		},
+	}
+	pref := &ProjectRef{Identifier: "proj", Owner: "evergreen-ci", Repo: "evergreen"}
+
+	// the files of all of the module patches count toward the limit
+	err := CheckPatchConflicts(ctx, p, &Project{}, pref, nil, "")
+	assert.True(t, IsPatchTooLarge(err))
+	assert.False(t, IsPatchConflict(err))
+	assert.Contains(t, err.Error(), fmt.Sprintf("changes %d files, more than the limit of %d", maxConflictCheckPatchedFiles+1, maxConflictCheckPatchedFiles))
+}
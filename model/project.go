@@ -29,6 +29,8 @@ const (
 type Project struct {
 	Enabled         bool                       `yaml:"enabled,omitempty" bson:"enabled"`
 	Stepback        bool                       `yaml:"stepback,omitempty" bson:"stepback"`
+	Bisect          bool                       `yaml:"bisect,omitempty" bson:"bisect"`
+	ImpactSelection bool                       `yaml:"impact_selection,omitempty" bson:"impact_selection"`
 	BatchTime       int                        `yaml:"batchtime,omitempty" bson:"batch_time"`
 	Owner           string                     `yaml:"owner,omitempty" bson:"owner_name"`
 	Repo            string                     `yaml:"repo,omitempty" bson:"repo_name"`
@@ -74,6 +76,17 @@ type BuildVariantTaskUnit struct {
 	// currently unsupported (TODO EVG-578)
 	ExecTimeoutSecs int   `yaml:"exec_timeout_secs,omitempty" bson:"exec_timeout_secs"`
 	Stepback        *bool `yaml:"stepback,omitempty" bson:"stepback,omitempty"`
+
+	// Paths and IgnorePaths are gitignore-style patterns that limit the task
+	// to versions that change files it cares about (see IsAffectedBy)
+	Paths       []string `yaml:"paths,omitempty" bson:"paths,omitempty"`
+	IgnorePaths []string `yaml:"ignore_paths,omitempty" bson:"ignore_paths,omitempty"`
+}
+
+// IsAffectedBy returns whether the task should run for a version that
+// changed the given files.
+func (bvt *BuildVariantTaskUnit) IsAffectedBy(changedFiles []string) bool {
+	return pathsAffectedBy(bvt.Paths, bvt.IgnorePaths, changedFiles)
 }
 
 func (b BuildVariant) Get(name string) (BuildVariantTaskUnit, error) {
@@ -178,11 +191,22 @@ type BuildVariant struct {
 	// provided for the task
 	RunOn []string `yaml:"run_on,omitempty" bson:"run_on"`
 
+	// Paths and IgnorePaths are gitignore-style patterns that limit the
+	// variant to versions that change files it cares about (see IsAffectedBy)
+	Paths       []string `yaml:"paths,omitempty" bson:"paths,omitempty"`
+	IgnorePaths []string `yaml:"ignore_paths,omitempty" bson:"ignore_paths,omitempty"`
+
 	// all of the tasks/groups to be run on the build variant, compile through tests.
 	Tasks        []BuildVariantTaskUnit `yaml:"tasks,omitempty" bson:"tasks"`
 	DisplayTasks []DisplayTask          `yaml:"display_tasks,omitempty" bson:"display_tasks,omitempty"`
 }
 
+// IsAffectedBy returns whether the variant should run for a version that
+// changed the given files.
+func (bv *BuildVariant) IsAffectedBy(changedFiles []string) bool {
+	return pathsAffectedBy(bv.Paths, bv.IgnorePaths, changedFiles)
+}
+
 type Module struct {
 	Name   string `yaml:"name,omitempty" bson:"name"`
 	Branch string `yaml:"branch,omitempty" bson:"branch"`
@@ -442,6 +466,9 @@ func NewTaskIdTable(p *Project, v *version.Version) TaskIdConfig {
 		rev := v.Revision
 		if evergreen.IsPatchRequester(v.Requester) {
 			rev = fmt.Sprintf("patch_%s_%s", v.Revision, v.Id)
+		} else if v.Requester == evergreen.ScheduledVersionRequester || v.Requester == evergreen.TriggerRequester {
+			// these versions share their revision with a mainline version
+			rev = v.Id
 		}
 		for _, t := range bv.Tasks {
 			if tg := p.FindTaskGroup(t.Name); tg != nil {
@@ -588,11 +615,20 @@ func populateExpansions(d *distro.Distro, v *version.Version, bv *BuildVariant,
 			expansions.Put("github_repo", p.GithubPatchData.BaseRepo)
 			expansions.Put("github_author", p.GithubPatchData.Author)
 		}
+		if v.Requester == evergreen.GitlabMRRequester && p != nil {
+			expansions.Put("gitlab_mr_number", fmt.Sprintf("%d", p.GitlabPatchData.MRNumber))
+			expansions.Put("gitlab_project", p.GitlabPatchData.ProjectPath)
+			expansions.Put("gitlab_author", p.GitlabPatchData.Author)
+		}
 
 	} else {
 		expansions.Put("revision_order_id", strconv.Itoa(v.RevisionOrderNumber))
 	}
 
+	for k, val := range v.TriggerExpansions {
+		expansions.Put(k, val)
+	}
+
 	for _, e := range d.Expansions {
 		expansions.Put(e.Key, e.Value)
 	}
@@ -1001,6 +1037,57 @@ func (p *Project) IgnoresAllFiles(files []string) bool {
 	return true
 }
 
+// UsesPathFilters returns whether any variant or task of the project is
+// limited to changes to certain files.
+func (p *Project) UsesPathFilters() bool {
+	for _, bv := range p.BuildVariants {
+		if len(bv.Paths) > 0 || len(bv.IgnorePaths) > 0 {
+			return true
+		}
+		for _, t := range bv.Tasks {
+			if len(t.Paths) > 0 || len(t.IgnorePaths) > 0 {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// pathsAffectedBy returns whether a change to the given files is relevant to
+// a variant or task with the given paths and ignore_paths: at least one file
+// must match paths, if any are given, and at least one file must not match
+// ignore_paths. If the changed files are unknown, everything is affected.
+func pathsAffectedBy(paths, ignorePaths, changedFiles []string) bool {
+	if len(changedFiles) == 0 {
+		return true
+	}
+	if len(paths) > 0 {
+		// CompileIgnoreLines has a silly API: it always returns a nil error.
+		matcher, _ := ignore.CompileIgnoreLines(paths...)
+		matched := false
+		for _, f := range changedFiles {
+			if matcher.MatchesPath(f) {
+				matched = true
+				break
+			}
+		}
+		if !matched {
+			return false
+		}
+	}
+	if len(ignorePaths) > 0 {
+		ignorer, _ := ignore.CompileIgnoreLines(ignorePaths...)
+		for _, f := range changedFiles {
+			if !ignorer.MatchesPath(f) {
+				return true
+			}
+		}
+		return false
+	}
+
+	return true
+}
+
 func (p *Project) BuildProjectTVPairs(patchDoc *patch.Patch, alias string) {
 	//expand tasks and build variants and include dependencies
 	if len(patchDoc.BuildVariants) == 1 && patchDoc.BuildVariants[0] == "all" {
@@ -26,8 +26,9 @@ const (
 //
 // For example, a user can specify that alias with the CLI tool so that a project
 // admin can define a set of default builders for patch builds. Pull request
-// testing uses a special alias, "__github" to determine the default
-// variants and tasks to run in a patch build.
+// testing, for both GitHub pull requests and GitLab merge requests, uses a
+// special alias, "__github" to determine the default variants and tasks to
+// run in a patch build.
 //
 // An alias can be specified multiple times. The resulting variant/task
 // combinations are the union of the aliases. For example, a user might set the
@@ -39,6 +39,8 @@ import (
 type parserProject struct {
 	Enabled         bool                       `yaml:"enabled,omitempty"`
 	Stepback        bool                       `yaml:"stepback,omitempty"`
+	Bisect          bool                       `yaml:"bisect,omitempty"`
+	ImpactSelection bool                       `yaml:"impact_selection,omitempty"`
 	BatchTime       int                        `yaml:"batchtime,omitempty"`
 	Owner           string                     `yaml:"owner,omitempty"`
 	Repo            string                     `yaml:"repo,omitempty"`
@@ -258,6 +260,8 @@ type parserBV struct {
 	BatchTime    *int               `yaml:"batchtime,omitempty"`
 	Stepback     *bool              `yaml:"stepback,omitempty"`
 	RunOn        parserStringSlice  `yaml:"run_on,omitempty"`
+	Paths        parserStringSlice  `yaml:"paths,omitempty"`
+	IgnorePaths  parserStringSlice  `yaml:"ignore_paths,omitempty"`
 	Tasks        parserBVTaskUnits  `yaml:"tasks,omitempty"`
 	DisplayTasks []displayTask      `yaml:"display_tasks,omitempty"`
 	DependsOn    parserDependencies `yaml:"depends_on,omitempty"`
@@ -314,6 +318,8 @@ type parserBVTaskUnit struct {
 	Stepback        *bool              `yaml:"stepback,omitempty"`
 	Distros         parserStringSlice  `yaml:"distros,omitempty"`
 	RunOn           parserStringSlice  `yaml:"run_on,omitempty"` // Alias for "Distros" TODO: deprecate Distros
+	Paths           parserStringSlice  `yaml:"paths,omitempty"`
+	IgnorePaths     parserStringSlice  `yaml:"ignore_paths,omitempty"`
 }
 
 // UnmarshalYAML allows the YAML parser to read both a single selector string or
@@ -443,6 +449,8 @@ func translateProject(pp *parserProject) (*Project, []error) {
 	proj := &Project{
 		Enabled:         pp.Enabled,
 		Stepback:        pp.Stepback,
+		Bisect:          pp.Bisect,
+		ImpactSelection: pp.ImpactSelection,
 		BatchTime:       pp.BatchTime,
 		Owner:           pp.Owner,
 		Repo:            pp.Repo,
@@ -561,6 +569,8 @@ func evaluateBuildVariants(tse *taskSelectorEvaluator, tgse *tagSelectorEvaluato
 			Stepback:    pbv.Stepback,
 			RunOn:       pbv.RunOn,
 			Tags:        pbv.Tags,
+			Paths:       pbv.Paths,
+			IgnorePaths: pbv.IgnorePaths,
 		}
 		bv.Tasks, errs = evaluateBVTasks(tse, tgse, vse, pbv)
 		// evaluate any rules passed in during matrix construction
@@ -713,6 +723,8 @@ func evaluateBVTasks(tse *taskSelectorEvaluator, tgse *tagSelectorEvaluator, vse
 				ExecTimeoutSecs: pt.ExecTimeoutSecs,
 				Stepback:        pt.Stepback,
 				Distros:         pt.Distros,
+				Paths:           pt.Paths,
+				IgnorePaths:     pt.IgnorePaths,
 			}
 
 			// Task-level dependencies in the variant override variant-level dependencies
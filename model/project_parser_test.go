@@ -1115,3 +1115,28 @@ buildvariants:
 	assert.Equal("task_3", proj.BuildVariants[2].Tasks[0].Requires[0].Name)
 	assert.Equal("task_3", proj.BuildVariants[2].Tasks[1].Requires[0].Name)
 }
+
+func TestParsePathFilters(t *testing.T) {
+	assert := assert.New(t)
+	yml := `
+tasks:
+- name: docs
+- name: compile
+buildvariants:
+- name: bv
+  paths:
+    - src/*
+  tasks:
+  - name: compile
+  - name: docs
+    ignore_paths: ["*.go", "src/*"]
+`
+	proj, errs := projectFromYAML([]byte(yml))
+	assert.NotNil(proj)
+	assert.Empty(errs)
+	assert.Len(proj.BuildVariants, 1)
+	assert.Equal([]string{"src/*"}, proj.BuildVariants[0].Paths)
+	assert.Len(proj.BuildVariants[0].Tasks, 2)
+	assert.Empty(proj.BuildVariants[0].Tasks[0].IgnorePaths)
+	assert.Equal([]string{"*.go", "src/*"}, proj.BuildVariants[0].Tasks[1].IgnorePaths)
+}
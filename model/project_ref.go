@@ -4,11 +4,17 @@ import (
 	"fmt"
 	"math"
 	"net/url"
+	"reflect"
+	"strings"
+	"time"
+	"unicode"
 
 	"github.com/evergreen-ci/evergreen"
 	"github.com/evergreen-ci/evergreen/db"
+	"github.com/evergreen-ci/evergreen/model/patch"
 	"github.com/evergreen-ci/evergreen/util"
 	"github.com/mongodb/anser/bsonutil"
+	"github.com/mongodb/grip"
 	"github.com/pkg/errors"
 	"gopkg.in/mgo.v2"
 	"gopkg.in/mgo.v2/bson"
@@ -21,6 +27,7 @@ type ProjectRef struct {
 	Repo               string `bson:"repo_name" json:"repo_name" yaml:"repo"`
 	Branch             string `bson:"branch_name" json:"branch_name" yaml:"branch"`
 	RepoKind           string `bson:"repo_kind" json:"repo_kind" yaml:"repokind"`
+	RepoURL            string `bson:"repo_url" json:"repo_url" yaml:"repo_url"`
 	Enabled            bool   `bson:"enabled" json:"enabled" yaml:"enabled"`
 	Private            bool   `bson:"private" json:"private" yaml:"private"`
 	BatchTime          int    `bson:"batch_time" json:"batch_time" yaml:"batchtime"`
@@ -43,18 +50,49 @@ type ProjectRef struct {
 	// Admins contain a list of users who are able to access the projects page.
 	Admins []string `bson:"admins" json:"admins"`
 
-	// The "Alerts" field is a map of trigger (e.g. 'task-failed') to
-	// the set of alert deliveries to be processed for that trigger.
-	Alerts map[string][]AlertConfig `bson:"alert_settings" json:"alert_config,omitempty"`
-
-	// TODO: remove the alerts field above
 	NotifyOnBuildFailure bool `bson:"notify_on_failure" json:"notify_on_failure"`
 
+	// CronSchedules create versions from the tip of the branch on a
+	// schedule, independently of new commits
+	CronSchedules []CronSchedule `bson:"cron_schedules,omitempty" json:"cron_schedules"`
+
+	// Downstream triggers create versions of other projects when versions
+	// or builds of this project succeed
+	Downstream []DownstreamTrigger `bson:"downstream,omitempty" json:"downstream"`
+
 	// RepoDetails contain the details of the status of the consistency
 	// between what is in GitHub and what is in Evergreen
 	RepotrackerError *RepositoryErrorDetails `bson:"repotracker_error" json:"repotracker_error"`
 }
 
+// CronSchedule describes when scheduled versions of a project are created.
+// If BuildVariants is empty, the scheduled versions contain every variant of
+// the project.
+type CronSchedule struct {
+	Cron          string   `bson:"cron" json:"cron"`
+	BuildVariants []string `bson:"build_variants,omitempty" json:"build_variants"`
+}
+
+// DownstreamTrigger describes a version of another project to create when a
+// mainline version of a project, or one of its builds, succeeds.
+type DownstreamTrigger struct {
+	// Project is the identifier of the downstream project
+	Project string `bson:"project" json:"project"`
+	// Level is what must succeed to trigger the downstream project, either
+	// "version" or "build"
+	Level string `bson:"level" json:"level"`
+	// BuildVariant limits build level triggers to builds of one variant
+	BuildVariant string `bson:"build_variant,omitempty" json:"build_variant,omitempty"`
+	// DownstreamVariants are the variants of the downstream version. If
+	// empty, the downstream version contains all of its project's variants.
+	DownstreamVariants []string `bson:"downstream_variants,omitempty" json:"downstream_variants,omitempty"`
+}
+
+const (
+	DownstreamLevelVersion = "version"
+	DownstreamLevelBuild   = "build"
+)
+
 // RepositoryErrorDetails indicates whether or not there is an invalid revision and if there is one,
 // what the guessed merge base revision is.
 type RepositoryErrorDetails struct {
@@ -63,32 +101,13 @@ type RepositoryErrorDetails struct {
 	MergeBaseRevision string `bson:"merge_base_revision" json:"merge_base_revision"`
 }
 
-type AlertConfig struct {
-	Provider string `bson:"provider" json:"provider"` //e.g. e-mail, flowdock, SMS
-
-	// Data contains provider-specific on how a notification should be delivered.
-	// Typed as bson.M so that the appropriate provider can parse out necessary details
-	Settings bson.M `bson:"settings" json:"settings"`
-}
-
-func (a AlertConfig) GetSettingsMap() map[string]string {
-	ret := make(map[string]string)
-	for k, v := range a.Settings {
-		ret[k] = fmt.Sprintf("%v", v)
-	}
-	return ret
-}
-
-type EmailAlertData struct {
-	Recipients []string `bson:"recipients"`
-}
-
 var (
 	// bson fields for the ProjectRef struct
 	ProjectRefOwnerKey              = bsonutil.MustHaveTag(ProjectRef{}, "Owner")
 	ProjectRefRepoKey               = bsonutil.MustHaveTag(ProjectRef{}, "Repo")
 	ProjectRefBranchKey             = bsonutil.MustHaveTag(ProjectRef{}, "Branch")
 	ProjectRefRepoKindKey           = bsonutil.MustHaveTag(ProjectRef{}, "RepoKind")
+	ProjectRefRepoURLKey            = bsonutil.MustHaveTag(ProjectRef{}, "RepoURL")
 	ProjectRefEnabledKey            = bsonutil.MustHaveTag(ProjectRef{}, "Enabled")
 	ProjectRefPrivateKey            = bsonutil.MustHaveTag(ProjectRef{}, "Private")
 	ProjectRefBatchTimeKey          = bsonutil.MustHaveTag(ProjectRef{}, "BatchTime")
@@ -98,13 +117,14 @@ var (
 	ProjectRefRemotePathKey         = bsonutil.MustHaveTag(ProjectRef{}, "RemotePath")
 	ProjectRefTrackedKey            = bsonutil.MustHaveTag(ProjectRef{}, "Tracked")
 	ProjectRefLocalConfig           = bsonutil.MustHaveTag(ProjectRef{}, "LocalConfig")
-	ProjectRefAlertsKey             = bsonutil.MustHaveTag(ProjectRef{}, "Alerts")
 	ProjectRefRepotrackerError      = bsonutil.MustHaveTag(ProjectRef{}, "RepotrackerError")
 	ProjectRefAdminsKey             = bsonutil.MustHaveTag(ProjectRef{}, "Admins")
 	projectRefTracksPushEventsKey   = bsonutil.MustHaveTag(ProjectRef{}, "TracksPushEvents")
 	projectRefPRTestingEnabledKey   = bsonutil.MustHaveTag(ProjectRef{}, "PRTestingEnabled")
 	projectRefPatchingDisabledKey   = bsonutil.MustHaveTag(ProjectRef{}, "PatchingDisabled")
 	projectRefNotifyOnFailureKey    = bsonutil.MustHaveTag(ProjectRef{}, "NotifyOnBuildFailure")
+	projectRefCronSchedulesKey      = bsonutil.MustHaveTag(ProjectRef{}, "CronSchedules")
+	projectRefDownstreamKey         = bsonutil.MustHaveTag(ProjectRef{}, "Downstream")
 )
 
 const (
@@ -242,6 +262,166 @@ func FindOneProjectRefByRepoAndBranchWithPRTesting(owner, repo, branch string) (
 	return &projectRefs[target], nil
 }
 
+// FindOneProjectRefByRepoURLAndBranchWithPRTesting finds the enabled
+// project ref for a repository on a git remote that tracks the branch and is
+// set up for PR testing. The repository can be identified by any of its
+// URLs, such as its HTTP and SSH clone URLs. If no project ref matches, nil
+// is returned.
+func FindOneProjectRefByRepoURLAndBranchWithPRTesting(urls []string, branch string) (*ProjectRef, error) {
+	projectRefs := []ProjectRef{}
+	err := db.FindAll(
+		ProjectRefCollection,
+		bson.M{
+			ProjectRefRepoKindKey:         GitRepoType,
+			ProjectRefBranchKey:           branch,
+			ProjectRefEnabledKey:          true,
+			projectRefPRTestingEnabledKey: true,
+		},
+		db.NoProjection,
+		db.NoSort,
+		db.NoSkip,
+		db.NoLimit,
+		&projectRefs,
+	)
+	if err != nil {
+		return nil, errors.Wrapf(err, "Could not fetch project refs with branch '%s'", branch)
+	}
+
+	repos := map[string]bool{}
+	for _, u := range urls {
+		if repo := normalizeRepoURL(u); repo != "" {
+			repos[repo] = true
+		}
+	}
+
+	matches := []ProjectRef{}
+	for _, ref := range projectRefs {
+		if repos[normalizeRepoURL(ref.RepoURL)] {
+			matches = append(matches, ref)
+		}
+	}
+	if len(matches) > 1 {
+		return nil, errors.Errorf("attempt to fetch project ref for %v on branch '%s' "+
+			"found %d project refs, when 1 was expected", urls, branch, len(matches))
+	}
+	if len(matches) == 0 {
+		return nil, nil
+	}
+
+	return &matches[0], nil
+}
+
+// normalizeRepoURL reduces a git remote to its host and path, so that the
+// HTTP and SSH URLs of a repository are the same, regardless of the
+// credentials in them.
+func normalizeRepoURL(remote string) string {
+	remote = strings.TrimSpace(remote)
+	if remote == "" {
+		return ""
+	}
+
+	var host, path string
+	if u, err := url.Parse(remote); err == nil && u.Scheme != "" && u.Host != "" {
+		host, path = u.Hostname(), u.Path
+	} else if i := strings.Index(remote, ":"); i > 0 {
+		// scp-like syntax, e.g. git@gitlab.com:group/project.git
+		host, path = remote[:i], remote[i+1:]
+		if at := strings.LastIndex(host, "@"); at >= 0 {
+			host = host[at+1:]
+		}
+	} else {
+		return ""
+	}
+
+	path = strings.TrimSuffix(strings.Trim(path, "/"), ".git")
+	return strings.ToLower(host + "/" + path)
+}
+
+// validRepoURLSchemes are the transports that a project's remote can use.
+var validRepoURLSchemes = []string{"https", "ssh", "git"}
+
+// repoURLShellCharacters are characters that are special to the shell, which
+// must be percent-encoded in a project's remote.
+const repoURLShellCharacters = "'\"`$;&|<>\\(){}!*?"
+
+// GitlabAPIURL returns the base URL of the GitLab API for a merge request of
+// the project. The URL comes from the merge request's webhook, which only
+// matched the project by its repository URLs, so it must be on the host of
+// the project's repository for the GitLab token to be sent to it.
+func (p *ProjectRef) GitlabAPIURL(data patch.GitlabPatch) (string, error) {
+	repoHost := strings.SplitN(normalizeRepoURL(p.RepoURL), "/", 2)[0]
+	u, err := url.Parse(data.ProjectURL)
+	if err != nil {
+		return "", errors.Wrapf(err, "invalid GitLab project URL '%s'", data.ProjectURL)
+	}
+	if u.Scheme != "https" && u.Scheme != "http" {
+		return "", errors.Errorf("GitLab project URL '%s' must be an HTTP URL", data.ProjectURL)
+	}
+	if repoHost == "" || strings.ToLower(u.Hostname()) != repoHost {
+		return "", errors.Errorf("GitLab project URL '%s' is not on the host of the repository of project '%s'",
+			data.ProjectURL, p.Identifier)
+	}
+
+	return data.APIURL()
+}
+
+// ValidateRepoURL checks that a remote is an HTTPS, SSH or git URL, or an
+// scp-like SSH remote, e.g. git@gitlab.com:group/project.git. Git takes
+// anything else, such as local paths and remote helpers like ext::, and a
+// remote starting with a dash would be read as an option. Characters that
+// are special to the shell must be percent-encoded.
+func ValidateRepoURL(remote string) error {
+	if remote == "" {
+		return errors.New("repository URL can't be empty")
+	}
+	if strings.HasPrefix(remote, "-") {
+		return errors.Errorf("repository URL '%s' can't start with '-'", remote)
+	}
+	for _, r := range remote {
+		if unicode.IsSpace(r) || unicode.IsControl(r) {
+			return errors.Errorf("repository URL '%s' can't contain whitespace", remote)
+		}
+	}
+	// agents clone the remote in a shell script
+	if strings.ContainsAny(remote, repoURLShellCharacters) {
+		return errors.Errorf("repository URL '%s' can't contain any of %s", remote, repoURLShellCharacters)
+	}
+
+	if strings.Contains(remote, "://") {
+		u, err := url.Parse(remote)
+		if err != nil {
+			return errors.Wrapf(err, "invalid repository URL '%s'", remote)
+		}
+		if !util.StringSliceContains(validRepoURLSchemes, u.Scheme) {
+			return errors.Errorf("repository URL '%s' must use one of the schemes %s",
+				remote, strings.Join(validRepoURLSchemes, ", "))
+		}
+		if u.Hostname() == "" || strings.HasPrefix(u.Hostname(), "-") {
+			return errors.Errorf("repository URL '%s' has an invalid host", remote)
+		}
+		return nil
+	}
+
+	// scp-like syntax, which git only recognizes when there's no slash
+	// before the colon
+	i := strings.Index(remote, ":")
+	if i <= 0 || strings.Contains(remote[:i], "/") {
+		return errors.Errorf("repository URL '%s' must be an HTTPS, SSH or git URL", remote)
+	}
+	host := remote[:i]
+	if at := strings.LastIndex(host, "@"); at >= 0 {
+		host = host[at+1:]
+	}
+	if host == "" || strings.HasPrefix(host, "-") {
+		return errors.Errorf("repository URL '%s' has an invalid host", remote)
+	}
+	if path := remote[i+1:]; path == "" || strings.HasPrefix(path, ":") {
+		return errors.Errorf("repository URL '%s' has an invalid path", remote)
+	}
+
+	return nil
+}
+
 // FindProjectRefs returns limit refs starting at project identifier key
 // in the sortDir direction
 func FindProjectRefs(key string, limit int, sortDir int, isAuthenticated bool) ([]ProjectRef, error) {
@@ -297,6 +477,7 @@ func (projectRef *ProjectRef) Upsert() error {
 		bson.M{
 			"$set": bson.M{
 				ProjectRefRepoKindKey:           projectRef.RepoKind,
+				ProjectRefRepoURLKey:            projectRef.RepoURL,
 				ProjectRefEnabledKey:            projectRef.Enabled,
 				ProjectRefPrivateKey:            projectRef.Private,
 				ProjectRefBatchTimeKey:          projectRef.BatchTime,
@@ -309,13 +490,14 @@ func (projectRef *ProjectRef) Upsert() error {
 				ProjectRefRemotePathKey:         projectRef.RemotePath,
 				ProjectRefTrackedKey:            projectRef.Tracked,
 				ProjectRefLocalConfig:           projectRef.LocalConfig,
-				ProjectRefAlertsKey:             projectRef.Alerts,
 				ProjectRefRepotrackerError:      projectRef.RepotrackerError,
 				ProjectRefAdminsKey:             projectRef.Admins,
 				projectRefTracksPushEventsKey:   projectRef.TracksPushEvents,
 				projectRefPRTestingEnabledKey:   projectRef.PRTestingEnabled,
 				projectRefPatchingDisabledKey:   projectRef.PatchingDisabled,
 				projectRefNotifyOnFailureKey:    projectRef.NotifyOnBuildFailure,
+				projectRefCronSchedulesKey:      projectRef.CronSchedules,
+				projectRefDownstreamKey:         projectRef.Downstream,
 			},
 		},
 	)
@@ -344,8 +526,15 @@ func (p *ProjectRef) GetBatchTime(variant *BuildVariant) int {
 	}
 }
 
-// Location generates and returns the ssh hostname and path to the repo.
+// Location generates and returns the ssh hostname and path to the repo, or
+// the remote of repositories that aren't on GitHub.
 func (projectRef *ProjectRef) Location() (string, error) {
+	if projectRef.RepoKind == GitRepoType {
+		if projectRef.RepoURL == "" {
+			return "", errors.Errorf("No repo URL in project ref: %v", projectRef.Identifier)
+		}
+		return projectRef.RepoURL, nil
+	}
 	if projectRef.Owner == "" {
 		return "", errors.Errorf("No owner in project ref: %v", projectRef.Identifier)
 	}
@@ -371,6 +560,79 @@ func (projectRef *ProjectRef) HTTPLocation() (*url.URL, error) {
 	}, nil
 }
 
+// ValidateCronSchedules returns an error describing every cron schedule of
+// the project that isn't a valid cron expression.
+func (p *ProjectRef) ValidateCronSchedules() error {
+	catcher := grip.NewBasicCatcher()
+	for i, schedule := range p.CronSchedules {
+		if _, err := util.ParseCronSchedule(schedule.Cron); err != nil {
+			catcher.Add(errors.Wrapf(err, "cron schedule #%d is invalid", i+1))
+		}
+	}
+	return catcher.Resolve()
+}
+
+// ValidateDownstream returns an error describing every downstream trigger of
+// the project that is malformed.
+func (p *ProjectRef) ValidateDownstream() error {
+	catcher := grip.NewBasicCatcher()
+	for i, d := range p.Downstream {
+		if d.Project == "" {
+			catcher.Add(errors.Errorf("downstream trigger #%d must have a project", i+1))
+		} else if d.Project == p.Identifier {
+			catcher.Add(errors.Errorf("downstream trigger #%d can't trigger its own project", i+1))
+		}
+		if d.Level != DownstreamLevelVersion && d.Level != DownstreamLevelBuild {
+			catcher.Add(errors.Errorf("downstream trigger #%d has invalid level '%s'", i+1, d.Level))
+		}
+		if d.Level == DownstreamLevelVersion && d.BuildVariant != "" {
+			catcher.Add(errors.Errorf("downstream trigger #%d can only limit build level triggers to a variant", i+1))
+		}
+	}
+	return catcher.Resolve()
+}
+
+// ChangedDownstreamProjects returns the projects of the downstream triggers
+// that aren't the same as one of the previous triggers, which are the
+// projects that saving the triggers starts creating versions of.
+func (p *ProjectRef) ChangedDownstreamProjects(previous []DownstreamTrigger) []string {
+	projects := []string{}
+	for _, d := range p.Downstream {
+		unchanged := false
+		for _, prev := range previous {
+			if reflect.DeepEqual(d, prev) {
+				unchanged = true
+				break
+			}
+		}
+		if !unchanged && !util.StringSliceContains(projects, d.Project) {
+			projects = append(projects, d.Project)
+		}
+	}
+	return projects
+}
+
+// ScheduledBuildVariants returns the variants that the project's cron
+// schedules create a version for in the minute containing t. If all is
+// true, a schedule that covers every variant fires at t.
+func (p *ProjectRef) ScheduledBuildVariants(t time.Time) (variants []string, all bool) {
+	for _, schedule := range p.CronSchedules {
+		cron, err := util.ParseCronSchedule(schedule.Cron)
+		if err != nil || !cron.Matches(t) {
+			continue
+		}
+		if len(schedule.BuildVariants) == 0 {
+			return nil, true
+		}
+		for _, bv := range schedule.BuildVariants {
+			if !util.StringSliceContains(variants, bv) {
+				variants = append(variants, bv)
+			}
+		}
+	}
+	return variants, false
+}
+
 func (p *ProjectRef) IsAdmin(userID string, settings evergreen.Settings) bool {
 	return util.StringSliceContains(p.Admins, userID) || util.StringSliceContains(settings.SuperUsers, userID)
 }
@@ -276,6 +276,38 @@ func TestIgnoresAllFiles(t *testing.T) {
 	})
 }
 
+func TestIsAffectedBy(t *testing.T) {
+	assert := assert.New(t)
+	files := []string{
+		"src/cool/test.py",
+		"README.md",
+	}
+
+	bv := &BuildVariant{}
+	assert.True(bv.IsAffectedBy(files))
+
+	bv.Paths = []string{"src/*"}
+	assert.True(bv.IsAffectedBy(files))
+	bv.Paths = []string{"docs/*"}
+	assert.False(bv.IsAffectedBy(files))
+	assert.True(bv.IsAffectedBy(nil), "versions without changed files should always be affected")
+
+	bvt := &BuildVariantTaskUnit{IgnorePaths: []string{"*.md"}}
+	assert.True(bvt.IsAffectedBy(files))
+	assert.False(bvt.IsAffectedBy([]string{"README.md"}))
+	bvt.IgnorePaths = []string{"*.md", "*.py"}
+	assert.False(bvt.IsAffectedBy(files))
+
+	bvt = &BuildVariantTaskUnit{Paths: []string{"src/*"}, IgnorePaths: []string{"*.py"}}
+	assert.False(bvt.IsAffectedBy([]string{"src/cool/test.py"}))
+	assert.True(bvt.IsAffectedBy([]string{"src/cool/test.go"}))
+
+	p := &Project{BuildVariants: BuildVariants{{Name: "bv"}}}
+	assert.False(p.UsesPathFilters())
+	p.BuildVariants[0].Tasks = []BuildVariantTaskUnit{{Name: "t", IgnorePaths: []string{"*.md"}}}
+	assert.True(p.UsesPathFilters())
+}
+
 func boolPtr(b bool) *bool {
 	return &b
 }
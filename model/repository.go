@@ -32,11 +32,14 @@ const (
 
 const (
 	GithubRepoType = "github"
+	// GitRepoType is a repository on any git remote, which is tracked
+	// using a local mirror of it.
+	GitRepoType = "git"
 )
 
-// valid repositories - currently only github supported
+// valid repositories
 var (
-	ValidRepoTypes = []string{GithubRepoType}
+	ValidRepoTypes = []string{GithubRepoType, GitRepoType}
 )
 
 type Revision struct {
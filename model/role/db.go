@@ -0,0 +1,176 @@
+package role
+
+import (
+	"sort"
+
+	"github.com/evergreen-ci/evergreen/db"
+	"github.com/evergreen-ci/evergreen/model/user"
+	"github.com/evergreen-ci/evergreen/util"
+	"github.com/evergreen-ci/gimlet"
+	"github.com/mongodb/anser/bsonutil"
+	"github.com/pkg/errors"
+	mgo "gopkg.in/mgo.v2"
+	"gopkg.in/mgo.v2/bson"
+)
+
+const (
+	Collection = "roles"
+)
+
+var (
+	IDKey           = bsonutil.MustHaveTag(Role{}, "ID")
+	NameKey         = bsonutil.MustHaveTag(Role{}, "Name")
+	ResourceTypeKey = bsonutil.MustHaveTag(Role{}, "ResourceType")
+	ResourcesKey    = bsonutil.MustHaveTag(Role{}, "Resources")
+	PermissionsKey  = bsonutil.MustHaveTag(Role{}, "Permissions")
+)
+
+func ByID(id string) db.Q {
+	return db.Query(bson.M{IDKey: id})
+}
+
+func ByIDs(ids []string) db.Q {
+	return db.Query(bson.M{
+		IDKey: bson.M{
+			"$in": ids,
+		},
+	})
+}
+
+// FindOne returns the stored role matching the query.
+func FindOne(query db.Q) (*Role, error) {
+	r := &Role{}
+	err := db.FindOneQ(Collection, query, r)
+	if err == mgo.ErrNotFound {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to fetch role")
+	}
+
+	return r, nil
+}
+
+// FindByID returns the role with the given id, including the default roles
+// if they haven't been stored.
+func FindByID(id string) (*Role, error) {
+	r, err := FindOne(ByID(id))
+	if err != nil || r != nil {
+		return r, err
+	}
+
+	if builtin, ok := builtinRoles[id]; ok {
+		return &builtin, nil
+	}
+
+	return nil, nil
+}
+
+// Find returns the roles matching the query.
+func Find(query db.Q) ([]Role, error) {
+	roles := []Role{}
+	err := db.FindAllQ(Collection, query, &roles)
+	return roles, errors.Wrap(err, "failed to fetch roles")
+}
+
+// FindAll returns all roles, including the default roles if they haven't
+// been stored, sorted by id.
+func FindAll() ([]Role, error) {
+	roles, err := Find(db.Query(bson.M{}))
+	if err != nil {
+		return nil, err
+	}
+
+	return withBuiltins(roles, nil), nil
+}
+
+// FindRolesForUser returns the roles the user has been given, and the
+// default roles that every user except service accounts has.
+func FindRolesForUser(u gimlet.User) ([]Role, error) {
+	ids := []string{}
+	if dbUser, ok := u.(*user.DBUser); !ok || !dbUser.IsServiceAccount() {
+		for id := range builtinRoles {
+			ids = append(ids, id)
+		}
+	}
+	ids = append(ids, u.Roles()...)
+
+	roles, err := Find(ByIDs(ids))
+	if err != nil {
+		return nil, err
+	}
+
+	return withBuiltins(roles, ids), nil
+}
+
+// UserHasPermission returns whether any of the user's roles grant the
+// permission on the resource.
+func UserHasPermission(u gimlet.User, resourceType, resourceID, permission string) (bool, error) {
+	if u == nil {
+		return false, nil
+	}
+
+	roles, err := FindRolesForUser(u)
+	if err != nil {
+		return false, err
+	}
+
+	return AnyGrants(roles, resourceType, resourceID, permission), nil
+}
+
+// Upsert saves the role, replacing any existing role with the same id.
+func (r *Role) Upsert() error {
+	if err := r.Validate(); err != nil {
+		return errors.Wrap(err, "invalid role")
+	}
+
+	_, err := db.Upsert(Collection, bson.M{IDKey: r.ID}, bson.M{
+		"$set": bson.M{
+			NameKey:         r.Name,
+			ResourceTypeKey: r.ResourceType,
+			ResourcesKey:    r.Resources,
+			PermissionsKey:  r.Permissions,
+		},
+	})
+
+	return errors.Wrapf(err, "failed to save role '%s'", r.ID)
+}
+
+// Remove deletes the role with the given id. Removing a default role
+// restores its original permissions.
+func Remove(id string) error {
+	err := db.Remove(Collection, bson.M{IDKey: id})
+	if err == mgo.ErrNotFound {
+		return nil
+	}
+
+	return errors.Wrapf(err, "failed to remove role '%s'", id)
+}
+
+// withBuiltins adds the default roles that haven't been stored to the
+// roles. If ids is non-nil, only default roles in ids are added.
+func withBuiltins(roles []Role, ids []string) []Role {
+	found := map[string]bool{}
+	for _, r := range roles {
+		found[r.ID] = true
+	}
+
+	for id, builtin := range builtinRoles {
+		if found[id] {
+			continue
+		}
+		if ids != nil && !util.StringSliceContains(ids, id) {
+			continue
+		}
+		roles = append(roles, builtin)
+	}
+
+	sort.Sort(byID(roles))
+	return roles
+}
+
+type byID []Role
+
+func (r byID) Len() int           { return len(r) }
+func (r byID) Less(i, j int) bool { return r[i].ID < r[j].ID }
+func (r byID) Swap(i, j int)      { r[i], r[j] = r[j], r[i] }
@@ -0,0 +1,136 @@
+package role
+
+import (
+	"github.com/evergreen-ci/evergreen/util"
+	"github.com/mongodb/grip"
+	"github.com/pkg/errors"
+)
+
+const (
+	ResourceTypeProject = "project"
+	ResourceTypeDistro  = "distro"
+
+	PermissionView         = "view"
+	PermissionPatch        = "patch"
+	PermissionRestart      = "restart"
+	PermissionEditSettings = "edit_settings"
+	PermissionManageVars   = "manage_vars"
+	PermissionSpawnHosts   = "spawn_hosts"
+
+	// AllResources may be used in a role's resources to grant its
+	// permissions on every resource of the role's type.
+	AllResources = "*"
+
+	// DefaultProjectRoleID and DefaultDistroRoleID are the ids of the
+	// roles that every logged in user has. Unless they are overridden by
+	// roles stored with the same ids, they grant the permissions that
+	// users had before roles existed.
+	DefaultProjectRoleID = "default_project"
+	DefaultDistroRoleID  = "default_distro"
+)
+
+// ValidPermissions are the permissions that can be granted on each type of
+// resource.
+var ValidPermissions = map[string][]string{
+	ResourceTypeProject: {
+		PermissionView,
+		PermissionPatch,
+		PermissionRestart,
+		PermissionEditSettings,
+		PermissionManageVars,
+	},
+	ResourceTypeDistro: {
+		PermissionView,
+		PermissionEditSettings,
+		PermissionSpawnHosts,
+	},
+}
+
+// Role is a named set of permissions on a set of projects or distros.
+type Role struct {
+	ID           string   `bson:"_id" json:"id"`
+	Name         string   `bson:"name" json:"name"`
+	ResourceType string   `bson:"resource_type" json:"resource_type"`
+	Resources    []string `bson:"resources" json:"resources"`
+	Permissions  []string `bson:"permissions" json:"permissions"`
+}
+
+var builtinRoles = map[string]Role{
+	DefaultProjectRoleID: {
+		ID:           DefaultProjectRoleID,
+		Name:         "Default project permissions",
+		ResourceType: ResourceTypeProject,
+		Resources:    []string{AllResources},
+		Permissions:  []string{PermissionView, PermissionPatch, PermissionRestart},
+	},
+	DefaultDistroRoleID: {
+		ID:           DefaultDistroRoleID,
+		Name:         "Default distro permissions",
+		ResourceType: ResourceTypeDistro,
+		Resources:    []string{AllResources},
+		Permissions:  []string{PermissionView, PermissionSpawnHosts},
+	},
+}
+
+// IsDefault returns whether the role is held by every user.
+func (r *Role) IsDefault() bool {
+	_, ok := builtinRoles[r.ID]
+	return ok
+}
+
+// Grants returns whether the role grants the permission on the resource.
+func (r *Role) Grants(resourceType, resourceID, permission string) bool {
+	if r.ResourceType != resourceType {
+		return false
+	}
+	if !util.StringSliceContains(r.Permissions, permission) {
+		return false
+	}
+
+	return util.StringSliceContains(r.Resources, AllResources) ||
+		util.StringSliceContains(r.Resources, resourceID)
+}
+
+// AnyGrants returns whether any of the roles grant the permission on the
+// resource.
+func AnyGrants(roles []Role, resourceType, resourceID, permission string) bool {
+	for _, r := range roles {
+		if r.Grants(resourceType, resourceID, permission) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (r *Role) Validate() error {
+	catcher := grip.NewBasicCatcher()
+	if r.ID == "" {
+		catcher.Add(errors.New("role must have an id"))
+	}
+
+	valid, ok := ValidPermissions[r.ResourceType]
+	if !ok {
+		catcher.Add(errors.Errorf("'%s' is not a valid resource type", r.ResourceType))
+	}
+	for _, permission := range r.Permissions {
+		if ok && !util.StringSliceContains(valid, permission) {
+			catcher.Add(errors.Errorf("'%s' is not a valid permission for a %s", permission, r.ResourceType))
+		}
+	}
+
+	if len(r.Resources) == 0 {
+		catcher.Add(errors.New("role must apply to at least one resource"))
+	}
+	for _, resource := range r.Resources {
+		if resource == "" {
+			catcher.Add(errors.New("role resources cannot be blank"))
+		}
+	}
+
+	if builtin, ok := builtinRoles[r.ID]; ok && builtin.ResourceType != r.ResourceType {
+		catcher.Add(errors.Errorf("role '%s' must apply to %ss", r.ID, builtin.ResourceType))
+	}
+
+	return catcher.Resolve()
+}
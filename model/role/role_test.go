@@ -0,0 +1,198 @@
+package role
+
+import (
+	"testing"
+
+	"github.com/evergreen-ci/evergreen/db"
+	"github.com/evergreen-ci/evergreen/model/user"
+	"github.com/evergreen-ci/evergreen/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func init() {
+	db.SetGlobalSessionProvider(testutil.TestConfig().SessionFactory())
+}
+
+func TestRoleValidate(t *testing.T) {
+	assert := assert.New(t)
+
+	r := Role{
+		ID:           "mci-admins",
+		ResourceType: ResourceTypeProject,
+		Resources:    []string{"mci"},
+		Permissions:  []string{PermissionEditSettings, PermissionManageVars},
+	}
+	assert.NoError(r.Validate())
+
+	r.Permissions = []string{PermissionSpawnHosts}
+	assert.Error(r.Validate())
+
+	r.Permissions = []string{}
+	assert.NoError(r.Validate())
+
+	r.ResourceType = "host"
+	assert.Error(r.Validate())
+
+	r = Role{
+		ID:           "",
+		ResourceType: ResourceTypeDistro,
+		Resources:    []string{},
+	}
+	assert.Error(r.Validate())
+
+	// default roles can't change their resource type
+	r = Role{
+		ID:           DefaultProjectRoleID,
+		ResourceType: ResourceTypeDistro,
+		Resources:    []string{AllResources},
+	}
+	assert.Error(r.Validate())
+}
+
+func TestRoleGrants(t *testing.T) {
+	assert := assert.New(t)
+
+	r := Role{
+		ID:           "mci-admins",
+		ResourceType: ResourceTypeProject,
+		Resources:    []string{"mci"},
+		Permissions:  []string{PermissionEditSettings},
+	}
+	assert.True(r.Grants(ResourceTypeProject, "mci", PermissionEditSettings))
+	assert.False(r.Grants(ResourceTypeProject, "mci", PermissionManageVars))
+	assert.False(r.Grants(ResourceTypeProject, "sys-perf", PermissionEditSettings))
+	assert.False(r.Grants(ResourceTypeDistro, "mci", PermissionEditSettings))
+
+	r.Resources = []string{AllResources}
+	assert.True(r.Grants(ResourceTypeProject, "sys-perf", PermissionEditSettings))
+}
+
+func TestUserHasPermission(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+	require.NoError(db.ClearCollections(Collection, user.Collection))
+
+	u := &user.DBUser{Id: "me"}
+	require.NoError(u.Insert())
+
+	// every user has the default permissions
+	ok, err := UserHasPermission(u, ResourceTypeProject, "mci", PermissionPatch)
+	assert.NoError(err)
+	assert.True(ok)
+	ok, err = UserHasPermission(u, ResourceTypeDistro, "ubuntu", PermissionSpawnHosts)
+	assert.NoError(err)
+	assert.True(ok)
+	ok, err = UserHasPermission(u, ResourceTypeProject, "mci", PermissionEditSettings)
+	assert.NoError(err)
+	assert.False(ok)
+	ok, err = UserHasPermission(nil, ResourceTypeProject, "mci", PermissionPatch)
+	assert.NoError(err)
+	assert.False(ok)
+
+	admins := Role{
+		ID:           "mci-admins",
+		ResourceType: ResourceTypeProject,
+		Resources:    []string{"mci"},
+		Permissions:  []string{PermissionEditSettings},
+	}
+	require.NoError(admins.Upsert())
+	require.NoError(u.AddRole(admins.ID))
+
+	ok, err = UserHasPermission(u, ResourceTypeProject, "mci", PermissionEditSettings)
+	assert.NoError(err)
+	assert.True(ok)
+	ok, err = UserHasPermission(u, ResourceTypeProject, "sys-perf", PermissionEditSettings)
+	assert.NoError(err)
+	assert.False(ok)
+
+	// overriding a default role changes every user's permissions
+	restricted := Role{
+		ID:           DefaultProjectRoleID,
+		ResourceType: ResourceTypeProject,
+		Resources:    []string{AllResources},
+		Permissions:  []string{PermissionRestart},
+	}
+	require.NoError(restricted.Upsert())
+	ok, err = UserHasPermission(u, ResourceTypeProject, "mci", PermissionPatch)
+	assert.NoError(err)
+	assert.False(ok)
+
+	// removing it restores the default
+	require.NoError(Remove(DefaultProjectRoleID))
+	ok, err = UserHasPermission(u, ResourceTypeProject, "mci", PermissionPatch)
+	assert.NoError(err)
+	assert.True(ok)
+}
+
+func TestFindRoles(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+	require.NoError(db.ClearCollections(Collection))
+
+	roles, err := FindAll()
+	assert.NoError(err)
+	require.Len(roles, 2)
+	assert.Equal(DefaultDistroRoleID, roles[0].ID)
+	assert.Equal(DefaultProjectRoleID, roles[1].ID)
+
+	r := Role{
+		ID:           "distro-owners",
+		Name:         "Distro owners",
+		ResourceType: ResourceTypeDistro,
+		Resources:    []string{"ubuntu", "rhel"},
+		Permissions:  []string{PermissionEditSettings},
+	}
+	require.NoError(r.Upsert())
+
+	roles, err = FindAll()
+	assert.NoError(err)
+	assert.Len(roles, 3)
+
+	fromDB, err := FindByID(r.ID)
+	assert.NoError(err)
+	require.NotNil(fromDB)
+	assert.Equal(r, *fromDB)
+
+	fromDB, err = FindByID(DefaultDistroRoleID)
+	assert.NoError(err)
+	require.NotNil(fromDB)
+	assert.True(fromDB.IsDefault())
+
+	fromDB, err = FindByID("nonexistent")
+	assert.NoError(err)
+	assert.Nil(fromDB)
+
+	r.Permissions = []string{"fly"}
+	assert.Error(r.Upsert())
+}
+
+func TestServiceAccountPermissions(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+	require.NoError(db.ClearCollections(Collection, user.Collection))
+
+	bot, err := user.NewServiceAccount("svc-ci", "CI", nil)
+	require.NoError(err)
+
+	// service accounts don't have the default permissions
+	ok, err := UserHasPermission(bot, ResourceTypeProject, "mci", PermissionPatch)
+	assert.NoError(err)
+	assert.False(ok)
+
+	patchers := Role{
+		ID:           "mci-patchers",
+		ResourceType: ResourceTypeProject,
+		Resources:    []string{"mci"},
+		Permissions:  []string{PermissionView, PermissionPatch},
+	}
+	require.NoError(patchers.Upsert())
+	require.NoError(bot.AddRole(patchers.ID))
+
+	ok, err = UserHasPermission(bot, ResourceTypeProject, "mci", PermissionPatch)
+	assert.NoError(err)
+	assert.True(ok)
+	ok, err = UserHasPermission(bot, ResourceTypeProject, "sys-perf", PermissionPatch)
+	assert.NoError(err)
+	assert.False(ok)
+}
@@ -0,0 +1,131 @@
+package model
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/evergreen-ci/evergreen"
+	"github.com/evergreen-ci/evergreen/model/build"
+	"github.com/evergreen-ci/evergreen/model/version"
+	"github.com/evergreen-ci/evergreen/util"
+	"github.com/mongodb/grip"
+	"github.com/mongodb/grip/message"
+	"github.com/pkg/errors"
+)
+
+// ScheduledVersionId returns the id of the version that a project's cron
+// schedules create for the given time.
+func ScheduledVersionId(projectId string, scheduledAt time.Time) string {
+	return util.CleanName(fmt.Sprintf("%s_scheduled_%s", projectId,
+		scheduledAt.UTC().Truncate(time.Minute).Format(build.IdTimeLayout)))
+}
+
+// CreateScheduledVersion creates and activates a version of the project for
+// a cron schedule, from the most recent revision the repotracker has seen on
+// the project's branch. If buildVariants is empty, the version contains all
+// of the project's variants. Scheduled versions are idempotent: if the
+// version for scheduledAt already exists, it is returned instead.
+func CreateScheduledVersion(ref *ProjectRef, scheduledAt time.Time, buildVariants []string) (*version.Version, error) {
+	scheduledAt = scheduledAt.UTC().Truncate(time.Minute)
+	v := &version.Version{
+		Id:         ScheduledVersionId(ref.Identifier, scheduledAt),
+		CreateTime: scheduledAt,
+		Requester:  evergreen.ScheduledVersionRequester,
+	}
+
+	return createVersionFromMostRecent(ref, v, buildVariants)
+}
+
+// createVersionFromMostRecent fills in v, which must have its id, create
+// time and requester set, from the most recent mainline version of the
+// project, and then creates and activates it along with its builds. If
+// buildVariants is empty, the version contains all of the project's
+// variants. If a version with v's id already exists, it is returned instead.
+func createVersionFromMostRecent(ref *ProjectRef, v *version.Version, buildVariants []string) (*version.Version, error) {
+	existing, err := version.FindOne(version.ById(v.Id))
+	if err != nil {
+		return nil, errors.Wrapf(err, "problem finding version '%s'", v.Id)
+	}
+	if existing != nil {
+		return existing, nil
+	}
+
+	tip, err := version.FindOne(version.ByMostRecentForRequester(ref.Identifier, evergreen.RepotrackerVersionRequester))
+	if err != nil {
+		return nil, errors.Wrapf(err, "problem finding most recent version for project '%s'", ref.Identifier)
+	}
+	if tip == nil {
+		return nil, errors.Errorf("project '%s' has no versions to create a version from", ref.Identifier)
+	}
+	if len(tip.Errors) > 0 {
+		return nil, errors.Errorf("the configuration of project '%s' at revision '%s' has errors",
+			ref.Identifier, tip.Revision)
+	}
+
+	project := &Project{}
+	if err = LoadProjectInto([]byte(tip.Config), ref.Identifier, project); err != nil {
+		return nil, errors.Wrapf(err, "problem loading configuration at revision '%s'", tip.Revision)
+	}
+
+	v.Identifier = ref.Identifier
+	v.Revision = tip.Revision
+	v.Author = tip.Author
+	v.AuthorEmail = tip.AuthorEmail
+	v.AuthorID = tip.AuthorID
+	v.Message = tip.Message
+	v.Owner = tip.Owner
+	v.Repo = tip.Repo
+	v.RepoKind = tip.RepoKind
+	v.RemotePath = tip.RemotePath
+	v.Branch = ref.Branch
+	v.Config = tip.Config
+	v.Status = evergreen.VersionCreated
+	v.RevisionOrderNumber = tip.RevisionOrderNumber
+	v.BuildIds = []string{}
+	v.BuildVariants = []version.BuildStatus{}
+
+	taskIds := NewTaskIdTable(project, v)
+	for _, bv := range project.BuildVariants {
+		if bv.Disabled {
+			continue
+		}
+		if len(buildVariants) > 0 && !util.StringSliceContains(buildVariants, bv.Name) {
+			continue
+		}
+
+		buildId, err := CreateBuildFromVersion(project, v, taskIds, bv.Name, true, nil, nil, "", nil)
+		if err != nil {
+			return nil, errors.WithStack(err)
+		}
+		v.BuildIds = append(v.BuildIds, buildId)
+		v.BuildVariants = append(v.BuildVariants, version.BuildStatus{
+			BuildVariant: bv.Name,
+			Activated:    true,
+			ActivateAt:   time.Now(),
+			BuildId:      buildId,
+		})
+	}
+	if len(v.BuildIds) == 0 {
+		return nil, errors.Errorf("version '%s' of project '%s' would have no variants", v.Id, ref.Identifier)
+	}
+
+	if err = v.Insert(); err != nil {
+		catcher := grip.NewBasicCatcher()
+		catcher.Add(err)
+		for _, buildId := range v.BuildIds {
+			catcher.Add(DeleteBuild(buildId))
+		}
+		return nil, errors.Wrapf(catcher.Resolve(), "problem inserting version '%s'", v.Id)
+	}
+
+	grip.Info(message.Fields{
+		"message":   "created version from most recent revision",
+		"project":   ref.Identifier,
+		"version":   v.Id,
+		"requester": v.Requester,
+		"revision":  v.Revision,
+		"variants":  buildVariants,
+	})
+
+	return v, nil
+}
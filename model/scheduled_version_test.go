@@ -0,0 +1,89 @@
+package model
+
+import (
+	"testing"
+	"time"
+
+	"github.com/evergreen-ci/evergreen"
+	"github.com/evergreen-ci/evergreen/db"
+	"github.com/evergreen-ci/evergreen/model/build"
+	"github.com/evergreen-ci/evergreen/model/task"
+	"github.com/evergreen-ci/evergreen/model/version"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCreateScheduledVersion(t *testing.T) {
+	assert := assert.New(t)
+	require.NoError(t, db.ClearCollections(version.Collection, build.Collection, task.Collection))
+
+	ref := &ProjectRef{
+		Identifier: "proj",
+		Branch:     "master",
+		Enabled:    true,
+	}
+	scheduledAt := time.Date(2018, time.June, 4, 2, 0, 0, 0, time.UTC)
+
+	// projects without versions can't be scheduled
+	_, err := CreateScheduledVersion(ref, scheduledAt, nil)
+	assert.Error(err)
+
+	config := `
+tasks:
+- name: compile
+- name: soak
+buildvariants:
+- name: linux
+  run_on: [d]
+  tasks:
+  - name: compile
+  - name: soak
+- name: windows
+  run_on: [d]
+  tasks:
+  - name: compile
+`
+	tip := &version.Version{
+		Id:                  "proj_abcdef",
+		CreateTime:          scheduledAt.Add(-time.Hour),
+		Identifier:          "proj",
+		Revision:            "abcdef",
+		Requester:           evergreen.RepotrackerVersionRequester,
+		RevisionOrderNumber: 7,
+		Config:              config,
+	}
+	require.NoError(t, tip.Insert())
+
+	v, err := CreateScheduledVersion(ref, scheduledAt.Add(30*time.Second), []string{"linux"})
+	require.NoError(t, err)
+	assert.Equal(ScheduledVersionId("proj", scheduledAt), v.Id)
+	assert.Equal(evergreen.ScheduledVersionRequester, v.Requester)
+	assert.Equal("abcdef", v.Revision)
+	assert.Equal(7, v.RevisionOrderNumber)
+	require.Len(t, v.BuildVariants, 1)
+	assert.Equal("linux", v.BuildVariants[0].BuildVariant)
+	assert.True(v.BuildVariants[0].Activated)
+
+	tasks, err := task.Find(task.ByVersion(v.Id))
+	require.NoError(t, err)
+	assert.Len(tasks, 2)
+	for _, tsk := range tasks {
+		assert.True(tsk.Activated)
+		assert.Equal(evergreen.ScheduledVersionRequester, tsk.Requester)
+	}
+
+	// creating the version for the same time again is a no-op
+	again, err := CreateScheduledVersion(ref, scheduledAt, nil)
+	require.NoError(t, err)
+	assert.Equal(v.Id, again.Id)
+	assert.Len(again.BuildVariants, 1)
+
+	// scheduled versions aren't part of the mainline
+	latest, err := version.FindOne(version.ByMostRecentForRequester("proj", evergreen.RepotrackerVersionRequester))
+	require.NoError(t, err)
+	assert.Equal(tip.Id, latest.Id)
+
+	v, err = CreateScheduledVersion(ref, scheduledAt.Add(time.Hour), nil)
+	require.NoError(t, err)
+	assert.Len(v.BuildVariants, 2)
+}
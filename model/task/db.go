@@ -63,6 +63,8 @@ var (
 	TaskGroupKey            = bsonutil.MustHaveTag(Task{}, "TaskGroup")
 	GenerateTaskKey         = bsonutil.MustHaveTag(Task{}, "GenerateTask")
 	GeneratedByKey          = bsonutil.MustHaveTag(Task{}, "GeneratedBy")
+	PathFilteredKey         = bsonutil.MustHaveTag(Task{}, "PathFiltered")
+	BisectCulpritKey        = bsonutil.MustHaveTag(Task{}, "BisectCulprit")
 
 	// BSON fields for the test result struct
 	TestResultStatusKey    = bsonutil.MustHaveTag(TestResult{}, "Status")
@@ -277,6 +279,40 @@ func ByBeforeRevisionWithStatuses(revisionOrder int, statuses []string, buildVar
 	}).Sort([]string{"-" + RevisionOrderNumberKey})
 }
 
+// ByRevisionOrderRange returns the executions of a task, with the given
+// requester, whose revision order numbers are after `after` and no later
+// than `upTo`, oldest first.
+func ByRevisionOrderRange(after, upTo int, buildVariant, displayName, project, requester string) db.Q {
+	return db.Query(bson.M{
+		BuildVariantKey: buildVariant,
+		DisplayNameKey:  displayName,
+		RequesterKey:    requester,
+		RevisionOrderNumberKey: bson.M{
+			"$gt":  after,
+			"$lte": upTo,
+		},
+		ProjectKey: project,
+	}).Sort([]string{RevisionOrderNumberKey})
+}
+
+// ByAfterRevisionWithStatusesAndRequester returns the executions of a task,
+// with the given requester and statuses, that are after the given revision
+// order number, oldest first.
+func ByAfterRevisionWithStatusesAndRequester(revisionOrder int, statuses []string, buildVariant, displayName, project, requester string) db.Q {
+	return db.Query(bson.M{
+		BuildVariantKey: buildVariant,
+		DisplayNameKey:  displayName,
+		RequesterKey:    requester,
+		RevisionOrderNumberKey: bson.M{
+			"$gt": revisionOrder,
+		},
+		StatusKey: bson.M{
+			"$in": statuses,
+		},
+		ProjectKey: project,
+	}).Sort([]string{RevisionOrderNumberKey})
+}
+
 func ByActivatedBeforeRevisionWithStatuses(revisionOrder int, statuses []string, buildVariant string, displayName string, project string) db.Q {
 	return db.Query(bson.M{
 		BuildVariantKey: buildVariant,
@@ -139,6 +139,17 @@ type Task struct {
 	GenerateTask bool `bson:"generate_task,omitempty" json:"generate_task,omitempty"`
 	// GeneratedBy, if present, is the ID of the task that generated this task.
 	GeneratedBy string `bson:"generated_by,omitempty" json:"generated_by,omitempty"`
+
+	// PathFiltered indicates that the files that the task's version changed
+	// don't match the paths of the task or its variant, so it is created
+	// inactive and isn't activated with its build by Evergreen, unless an
+	// active task depends on it.
+	PathFiltered bool `bson:"path_filtered,omitempty" json:"path_filtered,omitempty"`
+
+	// BisectCulprit, if present, is the ID of the task that bisection found
+	// to be the first failure in the run of mainline failures that this
+	// task belongs to.
+	BisectCulprit string `bson:"bisect_culprit,omitempty" json:"bisect_culprit,omitempty"`
 }
 
 // Dependency represents a task that must be completed before the owning
@@ -0,0 +1,346 @@
+package model
+
+import (
+	"regexp"
+	"time"
+
+	"github.com/evergreen-ci/evergreen"
+	"github.com/evergreen-ci/evergreen/db"
+	"github.com/evergreen-ci/evergreen/model/task"
+	"github.com/evergreen-ci/evergreen/util"
+	"github.com/mongodb/anser/bsonutil"
+	"github.com/mongodb/grip"
+	"github.com/pkg/errors"
+	"gopkg.in/mgo.v2"
+	"gopkg.in/mgo.v2/bson"
+)
+
+const (
+	BulkTaskOperationsCollection = "bulk_task_operations"
+
+	// BulkTaskRestart, BulkTaskAbort, BulkTaskSetPriority and
+	// BulkTaskDeactivate are the actions a bulk task operation can apply to
+	// each of its tasks.
+	BulkTaskRestart     = "restart"
+	BulkTaskAbort       = "abort"
+	BulkTaskSetPriority = "set_priority"
+	BulkTaskDeactivate  = "deactivate"
+
+	BulkTaskOperationPending  = "pending"
+	BulkTaskOperationRunning  = "running"
+	BulkTaskOperationFinished = "finished"
+
+	// MaxBulkTasks is the most tasks a single bulk operation can match.
+	MaxBulkTasks = 10000
+)
+
+// ValidBulkTaskActions are the actions a bulk task operation can apply.
+var ValidBulkTaskActions = []string{
+	BulkTaskRestart,
+	BulkTaskAbort,
+	BulkTaskSetPriority,
+	BulkTaskDeactivate,
+}
+
+// BulkTaskFilter selects the tasks of a bulk task operation, either by id or
+// by matching the tasks of a project.
+type BulkTaskFilter struct {
+	TaskIDs []string `bson:"task_ids,omitempty" json:"task_ids"`
+
+	Project       string   `bson:"project,omitempty" json:"project"`
+	Variant       string   `bson:"variant,omitempty" json:"variant"`
+	TaskNameRegex string   `bson:"task_name_regex,omitempty" json:"task_name_regex"`
+	Statuses      []string `bson:"statuses,omitempty" json:"statuses"`
+	// StartTime and EndTime bound the time the tasks were created.
+	StartTime time.Time `bson:"start_time,omitempty" json:"start_time"`
+	EndTime   time.Time `bson:"end_time,omitempty" json:"end_time"`
+	// StartOrder and EndOrder bound the order numbers of the tasks'
+	// versions.
+	StartOrder int `bson:"start_order,omitempty" json:"start_order"`
+	EndOrder   int `bson:"end_order,omitempty" json:"end_order"`
+}
+
+// Validate checks that the filter selects tasks by id or by project, and
+// that its ranges are valid.
+func (f *BulkTaskFilter) Validate() error {
+	catcher := grip.NewBasicCatcher()
+	if len(f.TaskIDs) == 0 && f.Project == "" {
+		catcher.Add(errors.New("must specify either task ids or a project"))
+	}
+	if len(f.TaskIDs) > MaxBulkTasks {
+		catcher.Add(errors.Errorf("cannot specify more than %d task ids", MaxBulkTasks))
+	}
+	if f.TaskNameRegex != "" {
+		if _, err := regexp.Compile(f.TaskNameRegex); err != nil {
+			catcher.Add(errors.Wrapf(err, "invalid task name regex '%s'", f.TaskNameRegex))
+		}
+	}
+	if !util.IsZeroTime(f.StartTime) && !util.IsZeroTime(f.EndTime) && f.EndTime.Before(f.StartTime) {
+		catcher.Add(errors.New("end time cannot be before start time"))
+	}
+	if f.StartOrder < 0 || f.EndOrder < 0 {
+		catcher.Add(errors.New("version order numbers cannot be negative"))
+	}
+	if f.EndOrder > 0 && f.EndOrder < f.StartOrder {
+		catcher.Add(errors.New("end order cannot be before start order"))
+	}
+
+	return catcher.Resolve()
+}
+
+func (f *BulkTaskFilter) query() bson.M {
+	q := bson.M{}
+	if len(f.TaskIDs) > 0 {
+		q[task.IdKey] = bson.M{"$in": f.TaskIDs}
+	}
+	if f.Project != "" {
+		q[task.ProjectKey] = f.Project
+	}
+	if f.Variant != "" {
+		q[task.BuildVariantKey] = f.Variant
+	}
+	if f.TaskNameRegex != "" {
+		q[task.DisplayNameKey] = bson.M{"$regex": f.TaskNameRegex}
+	}
+	if len(f.Statuses) > 0 {
+		q[task.StatusKey] = bson.M{"$in": f.Statuses}
+	}
+
+	createTime := bson.M{}
+	if !util.IsZeroTime(f.StartTime) {
+		createTime["$gte"] = f.StartTime
+	}
+	if !util.IsZeroTime(f.EndTime) {
+		createTime["$lte"] = f.EndTime
+	}
+	if len(createTime) > 0 {
+		q[task.CreateTimeKey] = createTime
+	}
+
+	order := bson.M{}
+	if f.StartOrder > 0 {
+		order["$gte"] = f.StartOrder
+	}
+	if f.EndOrder > 0 {
+		order["$lte"] = f.EndOrder
+	}
+	if len(order) > 0 {
+		q[task.RevisionOrderNumberKey] = order
+	}
+
+	return q
+}
+
+// Matches returns whether the filter selects the task. It must agree with
+// the filter's query.
+func (f *BulkTaskFilter) Matches(t *task.Task) bool {
+	if len(f.TaskIDs) > 0 && !util.StringSliceContains(f.TaskIDs, t.Id) {
+		return false
+	}
+	if f.Project != "" && t.Project != f.Project {
+		return false
+	}
+	if f.Variant != "" && t.BuildVariant != f.Variant {
+		return false
+	}
+	if f.TaskNameRegex != "" {
+		if matched, err := regexp.MatchString(f.TaskNameRegex, t.DisplayName); err != nil || !matched {
+			return false
+		}
+	}
+	if len(f.Statuses) > 0 && !util.StringSliceContains(f.Statuses, t.Status) {
+		return false
+	}
+	if !util.IsZeroTime(f.StartTime) && t.CreateTime.Before(f.StartTime) {
+		return false
+	}
+	if !util.IsZeroTime(f.EndTime) && t.CreateTime.After(f.EndTime) {
+		return false
+	}
+	if f.StartOrder > 0 && t.RevisionOrderNumber < f.StartOrder {
+		return false
+	}
+	if f.EndOrder > 0 && t.RevisionOrderNumber > f.EndOrder {
+		return false
+	}
+
+	return true
+}
+
+// FindBulkTasks returns the tasks that the filter matches, which cannot be
+// more than MaxBulkTasks.
+func FindBulkTasks(f BulkTaskFilter) ([]task.Task, error) {
+	if err := f.Validate(); err != nil {
+		return nil, errors.Wrap(err, "invalid filter")
+	}
+
+	tasks, err := task.Find(db.Query(f.query()).Sort([]string{task.IdKey}).Limit(MaxBulkTasks + 1))
+	if err != nil {
+		return nil, errors.Wrap(err, "problem finding tasks")
+	}
+	if len(tasks) > MaxBulkTasks {
+		return nil, errors.Errorf("filter matches more than %d tasks", MaxBulkTasks)
+	}
+
+	return tasks, nil
+}
+
+// BulkTaskOptions describe what a bulk task operation does to its tasks.
+type BulkTaskOptions struct {
+	Action   string `bson:"action" json:"action" yaml:"action"`
+	Priority int64  `bson:"priority,omitempty" json:"priority" yaml:"priority"`
+	User     string `bson:"user" json:"user" yaml:"user"`
+}
+
+// Validate checks that the action is valid.
+func (o *BulkTaskOptions) Validate() error {
+	if !util.StringSliceContains(ValidBulkTaskActions, o.Action) {
+		return errors.Errorf("'%s' is not a valid action", o.Action)
+	}
+	if o.Action != BulkTaskSetPriority && o.Priority != 0 {
+		return errors.Errorf("priority can only be given for the '%s' action", BulkTaskSetPriority)
+	}
+
+	return nil
+}
+
+// ApplyBulkTaskAction applies the action of the options to one task.
+func ApplyBulkTaskAction(opts BulkTaskOptions, taskID string) error {
+	switch opts.Action {
+	case BulkTaskRestart:
+		return errors.WithStack(TryResetTask(taskID, opts.User, evergreen.RESTV2Package, nil))
+	case BulkTaskAbort:
+		return errors.WithStack(AbortTask(taskID, opts.User))
+	case BulkTaskDeactivate:
+		return errors.WithStack(SetActiveState(taskID, opts.User, false))
+	case BulkTaskSetPriority:
+		t, err := task.FindOne(task.ById(taskID))
+		if err != nil {
+			return errors.Wrapf(err, "problem finding task '%s'", taskID)
+		}
+		if t == nil {
+			return errors.Errorf("task '%s' not found", taskID)
+		}
+		return errors.WithStack(t.SetPriority(opts.Priority, opts.User))
+	}
+
+	return errors.Errorf("'%s' is not a valid action", opts.Action)
+}
+
+// BulkTaskOperation records the progress of applying an action to many
+// tasks in the background. Its id is the id of the job that applies it.
+type BulkTaskOperation struct {
+	ID             string          `bson:"_id" json:"id"`
+	Options        BulkTaskOptions `bson:"options" json:"options"`
+	TaskIDs        []string        `bson:"task_ids" json:"task_ids"`
+	Status         string          `bson:"status" json:"status"`
+	Processed      int             `bson:"processed" json:"processed"`
+	TasksSucceeded []string        `bson:"tasks_succeeded" json:"tasks_succeeded"`
+	TasksErrored   []string        `bson:"tasks_errored" json:"tasks_errored"`
+	CreatedAt      time.Time       `bson:"created_at" json:"created_at"`
+	FinishedAt     time.Time       `bson:"finished_at,omitempty" json:"finished_at"`
+}
+
+var (
+	BulkTaskOperationIDKey             = bsonutil.MustHaveTag(BulkTaskOperation{}, "ID")
+	BulkTaskOperationStatusKey         = bsonutil.MustHaveTag(BulkTaskOperation{}, "Status")
+	BulkTaskOperationProcessedKey      = bsonutil.MustHaveTag(BulkTaskOperation{}, "Processed")
+	BulkTaskOperationTasksSucceededKey = bsonutil.MustHaveTag(BulkTaskOperation{}, "TasksSucceeded")
+	BulkTaskOperationTasksErroredKey   = bsonutil.MustHaveTag(BulkTaskOperation{}, "TasksErrored")
+	BulkTaskOperationFinishedAtKey     = bsonutil.MustHaveTag(BulkTaskOperation{}, "FinishedAt")
+)
+
+// NewBulkTaskOperation returns a pending operation applying the action to
+// the tasks.
+func NewBulkTaskOperation(id string, opts BulkTaskOptions, taskIDs []string) *BulkTaskOperation {
+	return &BulkTaskOperation{
+		ID:             id,
+		Options:        opts,
+		TaskIDs:        taskIDs,
+		Status:         BulkTaskOperationPending,
+		TasksSucceeded: []string{},
+		TasksErrored:   []string{},
+		CreatedAt:      time.Now(),
+	}
+}
+
+// Total returns the number of tasks the operation applies to.
+func (op *BulkTaskOperation) Total() int { return len(op.TaskIDs) }
+
+// Insert saves a new operation.
+func (op *BulkTaskOperation) Insert() error {
+	return errors.Wrapf(db.Insert(BulkTaskOperationsCollection, op),
+		"problem saving bulk task operation '%s'", op.ID)
+}
+
+// FindBulkTaskOperation returns the operation with the given id, or nil if
+// there is none.
+func FindBulkTaskOperation(id string) (*BulkTaskOperation, error) {
+	op := &BulkTaskOperation{}
+	err := db.FindOneQ(BulkTaskOperationsCollection, db.Query(bson.M{BulkTaskOperationIDKey: id}), op)
+	if err == mgo.ErrNotFound {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, errors.Wrapf(err, "problem finding bulk task operation '%s'", id)
+	}
+
+	return op, nil
+}
+
+// Start marks the operation as running.
+func (op *BulkTaskOperation) Start() error {
+	err := db.Update(BulkTaskOperationsCollection,
+		bson.M{BulkTaskOperationIDKey: op.ID},
+		bson.M{"$set": bson.M{BulkTaskOperationStatusKey: BulkTaskOperationRunning}})
+	if err != nil {
+		return errors.Wrapf(err, "problem starting bulk task operation '%s'", op.ID)
+	}
+
+	op.Status = BulkTaskOperationRunning
+	return nil
+}
+
+// AddResult records that the action was applied to the task, which
+// succeeded if err is nil.
+func (op *BulkTaskOperation) AddResult(taskID string, err error) error {
+	key := BulkTaskOperationTasksSucceededKey
+	if err != nil {
+		key = BulkTaskOperationTasksErroredKey
+	}
+	updateErr := db.Update(BulkTaskOperationsCollection,
+		bson.M{BulkTaskOperationIDKey: op.ID},
+		bson.M{
+			"$inc":  bson.M{BulkTaskOperationProcessedKey: 1},
+			"$push": bson.M{key: taskID},
+		})
+	if updateErr != nil {
+		return errors.Wrapf(updateErr, "problem updating progress of bulk task operation '%s'", op.ID)
+	}
+
+	op.Processed++
+	if err != nil {
+		op.TasksErrored = append(op.TasksErrored, taskID)
+	} else {
+		op.TasksSucceeded = append(op.TasksSucceeded, taskID)
+	}
+	return nil
+}
+
+// Finish marks the operation as finished.
+func (op *BulkTaskOperation) Finish() error {
+	now := time.Now()
+	err := db.Update(BulkTaskOperationsCollection,
+		bson.M{BulkTaskOperationIDKey: op.ID},
+		bson.M{"$set": bson.M{
+			BulkTaskOperationStatusKey:     BulkTaskOperationFinished,
+			BulkTaskOperationFinishedAtKey: now,
+		}})
+	if err != nil {
+		return errors.Wrapf(err, "problem finishing bulk task operation '%s'", op.ID)
+	}
+
+	op.Status = BulkTaskOperationFinished
+	op.FinishedAt = now
+	return nil
+}
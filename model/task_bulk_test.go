@@ -0,0 +1,140 @@
+package model
+
+import (
+	"testing"
+	"time"
+
+	"github.com/evergreen-ci/evergreen"
+	"github.com/evergreen-ci/evergreen/db"
+	"github.com/evergreen-ci/evergreen/model/task"
+	"github.com/evergreen-ci/evergreen/testutil"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBulkTaskFilterValidate(t *testing.T) {
+	assert := assert.New(t)
+	now := time.Now()
+
+	assert.Error((&BulkTaskFilter{}).Validate())
+	assert.NoError((&BulkTaskFilter{TaskIDs: []string{"t1"}}).Validate())
+	assert.NoError((&BulkTaskFilter{Project: "mci"}).Validate())
+	assert.Error((&BulkTaskFilter{TaskIDs: make([]string, MaxBulkTasks+1)}).Validate())
+	assert.Error((&BulkTaskFilter{Project: "mci", TaskNameRegex: "("}).Validate())
+	assert.Error((&BulkTaskFilter{Project: "mci", StartTime: now, EndTime: now.Add(-time.Hour)}).Validate())
+	assert.Error((&BulkTaskFilter{Project: "mci", StartOrder: -1}).Validate())
+	assert.Error((&BulkTaskFilter{Project: "mci", StartOrder: 10, EndOrder: 5}).Validate())
+	assert.NoError((&BulkTaskFilter{Project: "mci", StartOrder: 10}).Validate())
+}
+
+func TestBulkTaskFilterMatches(t *testing.T) {
+	assert := assert.New(t)
+	now := time.Now()
+	tsk := &task.Task{
+		Id:                  "t1",
+		Project:             "mci",
+		BuildVariant:        "ubuntu",
+		DisplayName:         "lint-go",
+		Status:              evergreen.TaskFailed,
+		CreateTime:          now,
+		RevisionOrderNumber: 12,
+	}
+
+	assert.True((&BulkTaskFilter{TaskIDs: []string{"t1", "t2"}}).Matches(tsk))
+	assert.False((&BulkTaskFilter{TaskIDs: []string{"t2"}}).Matches(tsk))
+	assert.True((&BulkTaskFilter{Project: "mci", Variant: "ubuntu", TaskNameRegex: "^lint"}).Matches(tsk))
+	assert.False((&BulkTaskFilter{Project: "other"}).Matches(tsk))
+	assert.False((&BulkTaskFilter{Project: "mci", Variant: "windows"}).Matches(tsk))
+	assert.False((&BulkTaskFilter{Project: "mci", TaskNameRegex: "^test"}).Matches(tsk))
+	assert.True((&BulkTaskFilter{Project: "mci", Statuses: []string{evergreen.TaskFailed}}).Matches(tsk))
+	assert.False((&BulkTaskFilter{Project: "mci", Statuses: []string{evergreen.TaskSucceeded}}).Matches(tsk))
+	assert.True((&BulkTaskFilter{Project: "mci", StartTime: now.Add(-time.Hour), EndTime: now.Add(time.Hour)}).Matches(tsk))
+	assert.False((&BulkTaskFilter{Project: "mci", StartTime: now.Add(time.Minute)}).Matches(tsk))
+	assert.False((&BulkTaskFilter{Project: "mci", EndTime: now.Add(-time.Minute)}).Matches(tsk))
+	assert.True((&BulkTaskFilter{Project: "mci", StartOrder: 10, EndOrder: 12}).Matches(tsk))
+	assert.False((&BulkTaskFilter{Project: "mci", StartOrder: 13}).Matches(tsk))
+	assert.False((&BulkTaskFilter{Project: "mci", EndOrder: 11}).Matches(tsk))
+}
+
+func TestBulkTaskOptionsValidate(t *testing.T) {
+	assert := assert.New(t)
+	for _, action := range ValidBulkTaskActions {
+		assert.NoError((&BulkTaskOptions{Action: action}).Validate())
+	}
+	assert.Error((&BulkTaskOptions{Action: "explode"}).Validate())
+	assert.NoError((&BulkTaskOptions{Action: BulkTaskSetPriority, Priority: 10}).Validate())
+	assert.Error((&BulkTaskOptions{Action: BulkTaskRestart, Priority: 10}).Validate())
+}
+
+func TestFindBulkTasks(t *testing.T) {
+	db.SetGlobalSessionProvider(testutil.TestConfig().SessionFactory())
+	require.NoError(t, db.Clear(task.Collection))
+	defer func() {
+		assert.NoError(t, db.Clear(task.Collection))
+	}()
+
+	tasks := []task.Task{
+		{Id: "t1", Project: "mci", BuildVariant: "ubuntu", DisplayName: "lint", Status: evergreen.TaskFailed, RevisionOrderNumber: 1},
+		{Id: "t2", Project: "mci", BuildVariant: "ubuntu", DisplayName: "test", Status: evergreen.TaskFailed, RevisionOrderNumber: 2},
+		{Id: "t3", Project: "mci", BuildVariant: "windows", DisplayName: "lint", Status: evergreen.TaskSucceeded, RevisionOrderNumber: 3},
+		{Id: "t4", Project: "other", BuildVariant: "ubuntu", DisplayName: "lint", Status: evergreen.TaskFailed, RevisionOrderNumber: 1},
+	}
+	for _, tsk := range tasks {
+		require.NoError(t, tsk.Insert())
+	}
+
+	ids := func(f BulkTaskFilter) []string {
+		found, err := FindBulkTasks(f)
+		require.NoError(t, err)
+		out := []string{}
+		for _, tsk := range found {
+			assert.True(t, f.Matches(&tsk))
+			out = append(out, tsk.Id)
+		}
+		return out
+	}
+
+	assert.Equal(t, []string{"t1", "t2", "t3"}, ids(BulkTaskFilter{Project: "mci"}))
+	assert.Equal(t, []string{"t1", "t3"}, ids(BulkTaskFilter{Project: "mci", TaskNameRegex: "^lint$"}))
+	assert.Equal(t, []string{"t1", "t2"}, ids(BulkTaskFilter{Project: "mci", Statuses: []string{evergreen.TaskFailed}}))
+	assert.Equal(t, []string{"t2", "t3"}, ids(BulkTaskFilter{Project: "mci", StartOrder: 2}))
+	assert.Equal(t, []string{"t1", "t4"}, ids(BulkTaskFilter{TaskIDs: []string{"t1", "t4"}}))
+
+	_, err := FindBulkTasks(BulkTaskFilter{})
+	assert.Error(t, err)
+}
+
+func TestBulkTaskOperationLifecycle(t *testing.T) {
+	db.SetGlobalSessionProvider(testutil.TestConfig().SessionFactory())
+	require.NoError(t, db.Clear(BulkTaskOperationsCollection))
+	defer func() {
+		assert.NoError(t, db.Clear(BulkTaskOperationsCollection))
+	}()
+
+	op, err := FindBulkTaskOperation("op")
+	assert.NoError(t, err)
+	assert.Nil(t, op)
+
+	op = NewBulkTaskOperation("op", BulkTaskOptions{Action: BulkTaskAbort, User: "me"}, []string{"t1", "t2"})
+	require.NoError(t, op.Insert())
+	require.NoError(t, op.Start())
+	require.NoError(t, op.AddResult("t1", nil))
+	require.NoError(t, op.AddResult("t2", errors.New("can't abort")))
+
+	found, err := FindBulkTaskOperation("op")
+	require.NoError(t, err)
+	require.NotNil(t, found)
+	assert.Equal(t, BulkTaskOperationRunning, found.Status)
+	assert.Equal(t, 2, found.Total())
+	assert.Equal(t, 2, found.Processed)
+	assert.Equal(t, []string{"t1"}, found.TasksSucceeded)
+	assert.Equal(t, []string{"t2"}, found.TasksErrored)
+	assert.Equal(t, "me", found.Options.User)
+
+	require.NoError(t, op.Finish())
+	found, err = FindBulkTaskOperation("op")
+	require.NoError(t, err)
+	assert.Equal(t, BulkTaskOperationFinished, found.Status)
+	assert.False(t, found.FinishedAt.IsZero())
+}
@@ -0,0 +1,417 @@
+package model
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/evergreen-ci/evergreen"
+	"github.com/evergreen-ci/evergreen/db"
+	"github.com/evergreen-ci/evergreen/model/patch"
+	"github.com/evergreen-ci/evergreen/model/task"
+	"github.com/evergreen-ci/evergreen/util"
+	"github.com/mongodb/anser/bsonutil"
+	"github.com/mongodb/grip"
+	"github.com/mongodb/grip/message"
+	"github.com/pkg/errors"
+	"gopkg.in/mgo.v2/bson"
+)
+
+const (
+	TaskImpactCollection = "task_impacts"
+
+	// TaskImpactSourceCoverage marks the files that a task's coverage
+	// reports showed it executes.
+	TaskImpactSourceCoverage = "coverage"
+	// TaskImpactSourceFailure marks the files changed by patches in which
+	// a task failed while it passed on the patch's base commit.
+	TaskImpactSourceFailure = "failure"
+
+	// maxFailureImpactFiles limits the files of a failure impact to the
+	// most recently correlated ones.
+	maxFailureImpactFiles = 1000
+
+	impactSkipReason = "none of the changed files affect it according to its coverage impact"
+)
+
+var (
+	taskImpactIdKey           = bsonutil.MustHaveTag(TaskImpact{}, "Id")
+	taskImpactProjectKey      = bsonutil.MustHaveTag(TaskImpact{}, "Project")
+	taskImpactBuildVariantKey = bsonutil.MustHaveTag(TaskImpact{}, "BuildVariant")
+	taskImpactTaskNameKey     = bsonutil.MustHaveTag(TaskImpact{}, "TaskName")
+	taskImpactSourceKey       = bsonutil.MustHaveTag(TaskImpact{}, "Source")
+	taskImpactFilesKey        = bsonutil.MustHaveTag(TaskImpact{}, "Files")
+	taskImpactTaskIdKey       = bsonutil.MustHaveTag(TaskImpact{}, "TaskId")
+	taskImpactExecutionKey    = bsonutil.MustHaveTag(TaskImpact{}, "Execution")
+	taskImpactLastUpdatedKey  = bsonutil.MustHaveTag(TaskImpact{}, "LastUpdated")
+)
+
+// TaskImpact maps the source files of a project to a task that they affect,
+// so that patches to projects with impact selection enabled only run the
+// tasks affected by their changes. There is one document for each task and
+// source of the mapping. Coverage impacts also record the task execution
+// whose reports their files come from.
+type TaskImpact struct {
+	Id           string    `bson:"_id" json:"id"`
+	Project      string    `bson:"project" json:"project"`
+	BuildVariant string    `bson:"build_variant" json:"build_variant"`
+	TaskName     string    `bson:"task_name" json:"task_name"`
+	Source       string    `bson:"source" json:"source"`
+	Files        []string  `bson:"files,omitempty" json:"files,omitempty"`
+	TaskId       string    `bson:"task_id,omitempty" json:"task_id,omitempty"`
+	Execution    int       `bson:"execution,omitempty" json:"execution,omitempty"`
+	LastUpdated  time.Time `bson:"last_updated" json:"last_updated"`
+}
+
+func taskImpactId(project, buildVariant, taskName, source string) string {
+	return fmt.Sprintf("%s_%s_%s_%s", project, buildVariant, taskName, source)
+}
+
+func upsertTaskImpact(project, buildVariant, taskName, source string, query, update bson.M) error {
+	if project == "" || buildVariant == "" || taskName == "" {
+		return errors.New("task impact must have a project, build variant, and task")
+	}
+	set, _ := update["$set"].(bson.M)
+	if set == nil {
+		set = bson.M{}
+	}
+	set[taskImpactProjectKey] = project
+	set[taskImpactBuildVariantKey] = buildVariant
+	set[taskImpactTaskNameKey] = taskName
+	set[taskImpactSourceKey] = source
+	set[taskImpactLastUpdatedKey] = time.Now()
+	update["$set"] = set
+
+	if query == nil {
+		query = bson.M{}
+	}
+	query[taskImpactIdKey] = taskImpactId(project, buildVariant, taskName, source)
+
+	_, err := db.Upsert(TaskImpactCollection, query, update)
+	return errors.Wrapf(err, "problem updating impact of task '%s' on variant '%s'", taskName, buildVariant)
+}
+
+// AddCoverageImpact records the files that a task execution executes
+// according to one of its coverage reports. The reports of an execution are
+// merged, while the first report of another execution replaces the files
+// recorded before, since the task may no longer execute them.
+func AddCoverageImpact(project, buildVariant, taskName, taskId string, execution int, files []string) error {
+	merge := func() error {
+		return upsertTaskImpact(project, buildVariant, taskName, TaskImpactSourceCoverage, bson.M{
+			taskImpactTaskIdKey:    taskId,
+			taskImpactExecutionKey: execution,
+		}, bson.M{
+			"$addToSet": bson.M{taskImpactFilesKey: bson.M{"$each": files}},
+		})
+	}
+
+	// the upsert only conflicts with the impact of another execution
+	err := merge()
+	if !db.IsDuplicateKey(errors.Cause(err)) {
+		return err
+	}
+	err = db.Update(TaskImpactCollection, bson.M{
+		taskImpactIdKey: taskImpactId(project, buildVariant, taskName, TaskImpactSourceCoverage),
+		"$or": []bson.M{
+			{taskImpactTaskIdKey: bson.M{"$ne": taskId}},
+			{taskImpactExecutionKey: bson.M{"$ne": execution}},
+		},
+	}, bson.M{
+		"$set": bson.M{
+			taskImpactFilesKey:       files,
+			taskImpactTaskIdKey:      taskId,
+			taskImpactExecutionKey:   execution,
+			taskImpactLastUpdatedKey: time.Now(),
+		},
+	})
+	if db.ResultsNotFound(err) {
+		// another report of this execution replaced the files first
+		return merge()
+	}
+	return errors.Wrapf(err, "problem updating impact of task '%s' on variant '%s'", taskName, buildVariant)
+}
+
+// AddFailureImpact adds files whose changes a task's failures have been
+// correlated with. Files that are already recorded move to the end, and only
+// the last maxFailureImpactFiles files are kept, so files that haven't been
+// correlated with failures in a long time age out.
+func AddFailureImpact(project, buildVariant, taskName string, files []string) error {
+	unique := []string{}
+	for _, file := range files {
+		if !util.StringSliceContains(unique, file) {
+			unique = append(unique, file)
+		}
+	}
+
+	// a field can't be pulled from and pushed to in the same update
+	err := upsertTaskImpact(project, buildVariant, taskName, TaskImpactSourceFailure, nil, bson.M{
+		"$pullAll": bson.M{taskImpactFilesKey: unique},
+	})
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	return upsertTaskImpact(project, buildVariant, taskName, TaskImpactSourceFailure, nil, bson.M{
+		"$push": bson.M{taskImpactFilesKey: bson.M{
+			"$each":  unique,
+			"$slice": -maxFailureImpactFiles,
+		}},
+	})
+}
+
+// FindTaskImpacts returns the impacts recorded for a project's tasks, without
+// their files.
+func FindTaskImpacts(project string) ([]TaskImpact, error) {
+	out := []TaskImpact{}
+	q := db.Query(bson.M{
+		taskImpactProjectKey: project,
+	}).WithoutFields(taskImpactFilesKey)
+	err := db.FindAllQ(TaskImpactCollection, q, &out)
+	if err != nil {
+		return nil, errors.Wrapf(err, "problem finding task impacts for project '%s'", project)
+	}
+	return out, nil
+}
+
+// FindTaskImpactsForFiles returns the impacts of a project's tasks that
+// include any of the given files, without their files.
+func FindTaskImpactsForFiles(project string, files []string) ([]TaskImpact, error) {
+	out := []TaskImpact{}
+	q := db.Query(bson.M{
+		taskImpactProjectKey: project,
+		taskImpactFilesKey:   bson.M{"$in": files},
+	}).WithoutFields(taskImpactFilesKey)
+	err := db.FindAllQ(TaskImpactCollection, q, &out)
+	if err != nil {
+		return nil, errors.Wrapf(err, "problem finding task impacts for project '%s'", project)
+	}
+	return out, nil
+}
+
+// FindCoveredFiles returns the given files that the coverage impact of any of
+// a project's tasks includes.
+func FindCoveredFiles(project string, files []string) ([]string, error) {
+	pipeline := []bson.M{
+		{"$match": bson.M{
+			taskImpactProjectKey: project,
+			taskImpactSourceKey:  TaskImpactSourceCoverage,
+			taskImpactFilesKey:   bson.M{"$in": files},
+		}},
+		{"$project": bson.M{taskImpactFilesKey: 1}},
+		{"$unwind": "$" + taskImpactFilesKey},
+		{"$match": bson.M{taskImpactFilesKey: bson.M{"$in": files}}},
+		{"$group": bson.M{"_id": "$" + taskImpactFilesKey}},
+	}
+	out := []struct {
+		File string `bson:"_id"`
+	}{}
+	if err := db.Aggregate(TaskImpactCollection, pipeline, &out); err != nil {
+		return nil, errors.Wrapf(err, "problem finding covered files for project '%s'", project)
+	}
+
+	covered := make([]string, 0, len(out))
+	for _, doc := range out {
+		covered = append(covered, doc.File)
+	}
+	return covered, nil
+}
+
+// recordFailureImpact correlates the failure of a patch task with the files
+// its patch changed, if the task passed on the patch's base commit and the
+// project selects patch tasks by impact.
+func recordFailureImpact(t *task.Task) error {
+	if !evergreen.IsPatchRequester(t.Requester) {
+		return nil
+	}
+	project, err := FindProjectFromTask(t)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	if !project.ImpactSelection {
+		return nil
+	}
+
+	baseTask, err := t.FindTaskOnBaseCommit()
+	if err != nil {
+		return errors.Wrapf(err, "problem finding base commit task of '%s'", t.Id)
+	}
+	if baseTask == nil || baseTask.Status != evergreen.TaskSucceeded {
+		return nil
+	}
+
+	p, err := patch.FindOne(patch.ByVersion(t.Version))
+	if err != nil {
+		return errors.Wrapf(err, "problem finding patch of version '%s'", t.Version)
+	}
+	if p == nil {
+		return errors.Errorf("no patch found for version '%s'", t.Version)
+	}
+	files := p.ChangedFiles()
+	if len(files) == 0 {
+		return nil
+	}
+
+	return AddFailureImpact(t.Project, t.BuildVariant, t.DisplayName, files)
+}
+
+// selectPatchTasksByImpact removes the tasks of a patch that none of its
+// changed files affect and records them as skipped. Tasks without a coverage
+// impact always run, since nothing is known about the files they execute.
+// If a changed file isn't in any coverage impact, such as a build script or
+// configuration file, it may affect any task, so all of them run.
+func selectPatchTasksByImpact(p *patch.Patch, project *Project, changedFiles []string) error {
+	known, err := FindTaskImpacts(p.Project)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	if len(known) == 0 {
+		return nil
+	}
+	covered, err := FindCoveredFiles(p.Project, changedFiles)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	uncovered := 0
+	for _, file := range changedFiles {
+		if !util.StringSliceContains(covered, file) {
+			uncovered++
+		}
+	}
+	if uncovered > 0 {
+		grip.Info(message.Fields{
+			"message":   "patch changes files that no coverage includes, running all of its tasks",
+			"patch":     p.Id.Hex(),
+			"project":   p.Project,
+			"uncovered": uncovered,
+		})
+		return nil
+	}
+	impacted, err := FindTaskImpactsForFiles(p.Project, changedFiles)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+
+	selected, skipped := selectImpactedTasks(project, p.VariantsTasks, known, impacted)
+	if len(skipped) == 0 {
+		return nil
+	}
+	if len(selected) == 0 {
+		// a patch without tasks would never finish
+		grip.Info(message.Fields{
+			"message": "patch changes affect none of its tasks, running all of them",
+			"patch":   p.Id.Hex(),
+			"project": p.Project,
+		})
+		return nil
+	}
+
+	if err = p.SetVariantsTasks(selected); err != nil {
+		return errors.Wrap(err, "problem updating patch tasks")
+	}
+	return errors.Wrap(p.SetSkippedTasks(skipped), "problem recording skipped patch tasks")
+}
+
+// selectImpactedTasks splits the tasks of a patch into those to run and those
+// to skip, given the impacts recorded for the project's tasks and those that
+// match the patch's changes. Only coverage shows which files a task doesn't
+// execute, so tasks with a coverage impact are skipped if none of their
+// impacts match the changes, unless a task that runs depends on them. Failure
+// impacts only add tasks. Display tasks are skipped when all of their
+// execution tasks are.
+func selectImpactedTasks(project *Project, variantsTasks []patch.VariantTasks, known, impacted []TaskImpact) ([]patch.VariantTasks, []patch.SkippedTask) {
+	covered := map[TVPair]bool{}
+	for _, impact := range known {
+		if impact.Source == TaskImpactSourceCoverage {
+			covered[TVPair{Variant: impact.BuildVariant, TaskName: impact.TaskName}] = true
+		}
+	}
+	affected := map[TVPair]bool{}
+	for _, impact := range impacted {
+		affected[TVPair{Variant: impact.BuildVariant, TaskName: impact.TaskName}] = true
+	}
+
+	selected := []TVPair{}
+	for _, pair := range VariantTasksToTVPairs(variantsTasks).ExecTasks {
+		if !covered[pair] || affected[pair] {
+			selected = append(selected, pair)
+		}
+	}
+	run := map[TVPair]bool{}
+	for _, pair := range IncludePatchDependencies(project, selected) {
+		run[pair] = true
+	}
+
+	out := []patch.VariantTasks{}
+	skipped := []patch.SkippedTask{}
+	for _, vt := range variantsTasks {
+		kept := patch.VariantTasks{Variant: vt.Variant}
+		for _, t := range vt.Tasks {
+			pair := TVPair{Variant: vt.Variant, TaskName: t}
+			if run[pair] {
+				kept.Tasks = append(kept.Tasks, t)
+				continue
+			}
+			skipped = append(skipped, patch.SkippedTask{
+				Variant: vt.Variant,
+				Task:    t,
+				Reason:  impactSkipReason,
+			})
+		}
+
+		for _, dt := range vt.DisplayTasks {
+			keptDisplay := patch.DisplayTask{Name: dt.Name}
+			for _, et := range dt.ExecTasks {
+				pair := TVPair{Variant: vt.Variant, TaskName: et}
+				if run[pair] {
+					keptDisplay.ExecTasks = append(keptDisplay.ExecTasks, et)
+					continue
+				}
+				skipped = append(skipped, patch.SkippedTask{
+					Variant: vt.Variant,
+					Task:    et,
+					Reason:  impactSkipReason,
+				})
+			}
+
+			execTasks := dt.ExecTasks
+			if len(execTasks) == 0 {
+				execTasks = project.findDisplayExecTasks(vt.Variant, dt.Name)
+			}
+			runs := false
+			for _, et := range execTasks {
+				if run[TVPair{Variant: vt.Variant, TaskName: et}] {
+					runs = true
+					break
+				}
+			}
+			if runs {
+				kept.DisplayTasks = append(kept.DisplayTasks, keptDisplay)
+				continue
+			}
+			skipped = append(skipped, patch.SkippedTask{
+				Variant: vt.Variant,
+				Task:    dt.Name,
+				Reason:  "all of its execution tasks were skipped",
+			})
+		}
+
+		if len(kept.Tasks) > 0 || len(kept.DisplayTasks) > 0 {
+			out = append(out, kept)
+		}
+	}
+
+	return out, skipped
+}
+
+// findDisplayExecTasks returns the execution tasks of a display task of a
+// build variant.
+func (p *Project) findDisplayExecTasks(variant, displayTask string) []string {
+	bv := p.FindBuildVariant(variant)
+	if bv == nil {
+		return nil
+	}
+	for _, dt := range bv.DisplayTasks {
+		if dt.Name == displayTask {
+			return dt.ExecutionTasks
+		}
+	}
+	return nil
+}
@@ -0,0 +1,160 @@
+package model
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/evergreen-ci/evergreen/db"
+	"github.com/evergreen-ci/evergreen/model/patch"
+	"github.com/evergreen-ci/evergreen/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/mgo.v2/bson"
+)
+
+func TestTaskImpacts(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+	db.SetGlobalSessionProvider(testutil.TestConfig().SessionFactory())
+	require.NoError(db.Clear(TaskImpactCollection))
+
+	require.NoError(AddCoverageImpact("p", "linux", "unit", "t1", 0, []string{"a.go", "b.go"}))
+	require.NoError(AddCoverageImpact("p", "linux", "unit", "t2", 0, []string{"b.go"}))
+	require.NoError(AddFailureImpact("p", "linux", "unit", []string{"c.go"}))
+	require.NoError(AddFailureImpact("p", "linux", "unit", []string{"c.go", "d.go"}))
+	require.NoError(AddCoverageImpact("p", "linux", "lint", "t3", 0, []string{"a.go"}))
+	require.NoError(AddCoverageImpact("other", "linux", "unit", "t4", 0, []string{"a.go"}))
+	assert.Error(AddCoverageImpact("p", "", "unit", "t5", 0, []string{"a.go"}))
+
+	impacts, err := FindTaskImpacts("p")
+	require.NoError(err)
+	assert.Len(impacts, 3)
+	for _, impact := range impacts {
+		assert.Equal("p", impact.Project)
+		assert.Empty(impact.Files)
+	}
+
+	impacts, err = FindTaskImpactsForFiles("p", []string{"a.go"})
+	require.NoError(err)
+	require.Len(impacts, 1)
+	assert.Equal("lint", impacts[0].TaskName)
+
+	impacts, err = FindTaskImpactsForFiles("p", []string{"d.go", "b.go"})
+	require.NoError(err)
+	assert.Len(impacts, 2)
+	for _, impact := range impacts {
+		assert.Equal("unit", impact.TaskName)
+	}
+
+	covered, err := FindCoveredFiles("p", []string{"a.go", "c.go", "Makefile"})
+	require.NoError(err)
+	assert.Equal([]string{"a.go"}, covered)
+
+	// coverage reports of the same execution are merged, and those of
+	// another execution replace them
+	impact := &TaskImpact{}
+	coverageQuery := db.Query(bson.M{taskImpactIdKey: taskImpactId("p", "linux", "unit", TaskImpactSourceCoverage)})
+	require.NoError(AddCoverageImpact("p", "linux", "unit", "t2", 0, []string{"c.go", "b.go"}))
+	require.NoError(db.FindOneQ(TaskImpactCollection, coverageQuery, impact))
+	assert.Equal([]string{"b.go", "c.go"}, impact.Files)
+	require.NoError(AddCoverageImpact("p", "linux", "unit", "t2", 1, []string{"a.go"}))
+	require.NoError(db.FindOneQ(TaskImpactCollection, coverageQuery, impact))
+	assert.Equal([]string{"a.go"}, impact.Files)
+	assert.Equal("t2", impact.TaskId)
+	assert.Equal(1, impact.Execution)
+
+	// failure impacts keep the most recent files without duplicates
+	query := db.Query(bson.M{taskImpactIdKey: taskImpactId("p", "linux", "unit", TaskImpactSourceFailure)})
+	require.NoError(db.FindOneQ(TaskImpactCollection, query, impact))
+	assert.Equal([]string{"c.go", "d.go"}, impact.Files)
+
+	require.NoError(AddFailureImpact("p", "linux", "unit", []string{"c.go", "e.go", "e.go"}))
+	require.NoError(db.FindOneQ(TaskImpactCollection, query, impact))
+	assert.Equal([]string{"d.go", "c.go", "e.go"}, impact.Files)
+
+	files := []string{}
+	for i := 0; i < maxFailureImpactFiles; i++ {
+		files = append(files, fmt.Sprintf("new%d.go", i))
+	}
+	require.NoError(AddFailureImpact("p", "linux", "unit", files))
+	require.NoError(db.FindOneQ(TaskImpactCollection, query, impact))
+	assert.Equal(files, impact.Files)
+}
+
+func TestSelectImpactedTasks(t *testing.T) {
+	assert := assert.New(t)
+	project := &Project{
+		Tasks: []ProjectTask{
+			{Name: "compile"},
+			{Name: "unit"},
+			{Name: "lint"},
+			{Name: "integration", DependsOn: []TaskUnitDependency{{Name: "compile"}}},
+			{Name: "docs"},
+		},
+		BuildVariants: []BuildVariant{
+			{
+				Name: "linux",
+				Tasks: []BuildVariantTaskUnit{
+					{Name: "compile"}, {Name: "unit"}, {Name: "lint"}, {Name: "integration"}, {Name: "docs"},
+				},
+				DisplayTasks: []DisplayTask{{Name: "checks", ExecutionTasks: []string{"lint"}}},
+			},
+			{
+				Name:  "windows",
+				Tasks: []BuildVariantTaskUnit{{Name: "unit"}},
+			},
+		},
+	}
+	variantsTasks := []patch.VariantTasks{
+		{
+			Variant:      "linux",
+			Tasks:        []string{"compile", "unit", "lint", "integration", "docs"},
+			DisplayTasks: []patch.DisplayTask{{Name: "checks"}},
+		},
+		{
+			Variant: "windows",
+			Tasks:   []string{"unit"},
+		},
+	}
+	known := []TaskImpact{
+		{BuildVariant: "linux", TaskName: "compile", Source: TaskImpactSourceCoverage},
+		{BuildVariant: "linux", TaskName: "unit", Source: TaskImpactSourceCoverage},
+		{BuildVariant: "linux", TaskName: "unit", Source: TaskImpactSourceFailure},
+		{BuildVariant: "linux", TaskName: "lint", Source: TaskImpactSourceCoverage},
+		{BuildVariant: "linux", TaskName: "integration", Source: TaskImpactSourceCoverage},
+		{BuildVariant: "linux", TaskName: "docs", Source: TaskImpactSourceFailure},
+		{BuildVariant: "windows", TaskName: "unit", Source: TaskImpactSourceCoverage},
+	}
+	impacted := []TaskImpact{
+		{BuildVariant: "linux", TaskName: "integration", Source: TaskImpactSourceCoverage},
+	}
+
+	// tasks with only failure impacts always run
+	selected, skipped := selectImpactedTasks(project, variantsTasks, known, impacted)
+	assert.Equal([]patch.VariantTasks{
+		{Variant: "linux", Tasks: []string{"compile", "integration", "docs"}},
+	}, selected)
+	assert.Equal([]patch.SkippedTask{
+		{Variant: "linux", Task: "unit", Reason: impactSkipReason},
+		{Variant: "linux", Task: "lint", Reason: impactSkipReason},
+		{Variant: "linux", Task: "checks", Reason: "all of its execution tasks were skipped"},
+		{Variant: "windows", Task: "unit", Reason: impactSkipReason},
+	}, skipped)
+
+	// a matching failure impact adds a task that its coverage would skip
+	impacted = append(impacted, TaskImpact{BuildVariant: "linux", TaskName: "unit", Source: TaskImpactSourceFailure})
+	selected, skipped = selectImpactedTasks(project, variantsTasks, known, impacted)
+	assert.Equal([]patch.VariantTasks{
+		{Variant: "linux", Tasks: []string{"compile", "unit", "integration", "docs"}},
+	}, selected)
+	assert.Equal([]patch.SkippedTask{
+		{Variant: "linux", Task: "lint", Reason: impactSkipReason},
+		{Variant: "linux", Task: "checks", Reason: "all of its execution tasks were skipped"},
+		{Variant: "windows", Task: "unit", Reason: impactSkipReason},
+	}, skipped)
+
+	// without any matching impact data, nothing is skipped
+	selected, skipped = selectImpactedTasks(project, variantsTasks, nil, nil)
+	assert.Equal(variantsTasks, selected)
+	assert.Empty(skipped)
+}
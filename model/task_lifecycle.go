@@ -426,6 +426,13 @@ func MarkEnd(t *task.Task, caller string, finishTime time.Time, detail *apimodel
 		}
 	}
 
+	if status == evergreen.TaskFailed {
+		grip.Error(message.WrapError(recordFailureImpact(t), message.Fields{
+			"message": "problem recording failure impact",
+			"task":    t.Id,
+		}))
+	}
+
 	// activate/deactivate other task if this is not a patch request's task
 	if !evergreen.IsPatchRequester(t.Requester) {
 		if t.IsPartOfDisplay() {
@@ -454,19 +461,34 @@ func evalStepback(t *task.Task, caller, status string, deactivatePrevious bool)
 			return errors.WithStack(err)
 		}
 		if shouldStepBack {
-			if err = doStepback(t); err != nil {
+			var shouldBisect bool
+			shouldBisect, err = getBisect(t)
+			if err != nil {
+				return errors.WithStack(err)
+			}
+			if shouldBisect {
+				if err = doBisect(t); err != nil {
+					return errors.Wrap(err, "Error during bisect")
+				}
+			} else if err = doStepback(t); err != nil {
 				return errors.Wrap(err, "Error during step back")
 			}
 		} else {
 			grip.Debugln("Not stepping backwards on task failure:", t.Id)
 		}
 
-	} else if deactivatePrevious && status == evergreen.TaskSucceeded {
+	} else if status == evergreen.TaskSucceeded {
+		// a success narrows the bisection that activated the task
+		if err := continueBisect(t); err != nil {
+			return errors.Wrap(err, "Error during bisect")
+		}
+
 		// if the task was successful, ignore running previous
 		// activated tasks for this buildvariant
-
-		if err := DeactivatePreviousTasks(t.Id, caller); err != nil {
-			return errors.Wrap(err, "Error deactivating previous task")
+		if deactivatePrevious {
+			if err := DeactivatePreviousTasks(t.Id, caller); err != nil {
+				return errors.Wrap(err, "Error deactivating previous task")
+			}
 		}
 	}
 
@@ -17,6 +17,7 @@ const (
 	selectorBuildVariant = "build-variant"
 	selectorInVersion    = "in-version"
 	selectorInBuild      = "in-build"
+	selectorDistro       = "distro"
 
 	triggerOutcome                = "outcome"
 	triggerFailure                = "failure"
@@ -61,7 +61,7 @@ func (t *hostBase) Fetch(e *event.EventLogEntry) error {
 }
 
 func (t *hostBase) Selectors() []event.Selector {
-	return []event.Selector{
+	selectors := []event.Selector{
 		{
 			Type: selectorID,
 			Data: t.host.Id,
@@ -74,7 +74,28 @@ func (t *hostBase) Selectors() []event.Selector {
 			Type: selectorOwner,
 			Data: t.host.StartedBy,
 		},
+		{
+			Type: selectorDistro,
+			Data: t.host.Distro.Id,
+		},
+	}
+	if project := t.hostProject(); project != "" {
+		selectors = append(selectors, event.Selector{
+			Type: selectorProject,
+			Data: project,
+		})
+	}
+
+	return selectors
+}
+
+// hostProject returns the project of the task the host is running, or
+// most recently ran.
+func (t *hostBase) hostProject() string {
+	if t.host.RunningTaskProject != "" {
+		return t.host.RunningTaskProject
 	}
+	return t.host.LastProject
 }
 
 type hostTemplateData struct {
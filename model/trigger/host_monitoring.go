@@ -0,0 +1,201 @@
+package trigger
+
+import (
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/evergreen-ci/evergreen/model/alertrecord"
+	"github.com/evergreen-ci/evergreen/model/event"
+	"github.com/evergreen-ci/evergreen/model/notification"
+	restModel "github.com/evergreen-ci/evergreen/rest/model"
+	"github.com/mongodb/grip/message"
+	"github.com/pkg/errors"
+)
+
+func init() {
+	registry.registerEventHandler(event.ResourceTypeHost, event.EventHostAgentDeployFailed, makeHostMonitoringTriggers)
+	registry.registerEventHandler(event.ResourceTypeHost, event.EventHostTerminatedExternally, makeHostMonitoringTriggers)
+	registry.registerEventHandler(event.ResourceTypeHost, event.EventHostIdle, makeHostMonitoringTriggers)
+}
+
+const (
+	triggerProvisionFailed      = "provision-failed"
+	triggerAgentDeployFailed    = "agent-deploy-failed"
+	triggerIdle                 = "idle"
+	triggerTerminatedExternally = "terminated-externally"
+
+	// hostDefaultAgentDeployFailures is the number of consecutive agent
+	// deploy failures before notifying, if the subscription doesn't
+	// specify one
+	hostDefaultAgentDeployFailures = 3
+	// hostDefaultIdleDuration is how long a host must be idle before
+	// notifying, if the subscription doesn't specify a duration
+	hostDefaultIdleDuration = time.Hour
+
+	// hostLogsLimit is the maximum number of characters of host logs to
+	// include in a notification
+	hostLogsLimit = 1000
+)
+
+type hostMonitoringTriggers struct {
+	hostBase
+}
+
+func makeHostMonitoringTriggers() eventHandler {
+	t := &hostMonitoringTriggers{}
+	t.triggers = map[string]trigger{
+		triggerAgentDeployFailed:    t.hostAgentDeployFailed,
+		triggerIdle:                 t.hostIdle,
+		triggerTerminatedExternally: t.hostTerminatedExternally,
+	}
+	return t
+}
+
+// hostProvisionFailed is shared with the spawn host triggers, which
+// handle the provisioning events.
+func (t *hostBase) hostProvisionFailed(sub *event.Subscription) (*notification.Notification, error) {
+	if t.event.EventType != event.EventHostProvisionFailed {
+		return nil, nil
+	}
+
+	description := "The host could not be provisioned."
+	if t.data.Logs != "" {
+		logs, _ := truncateString(t.data.Logs, hostLogsLimit)
+		description = fmt.Sprintf("%s Provisioning logs: %s", description, logs)
+	}
+
+	return t.generateHostNotification(sub, "failed to provision", description)
+}
+
+func (t *hostMonitoringTriggers) hostAgentDeployFailed(sub *event.Subscription) (*notification.Notification, error) {
+	if t.event.EventType != event.EventHostAgentDeployFailed {
+		return nil, nil
+	}
+
+	threshold := hostDefaultAgentDeployFailures
+	if val, ok := sub.TriggerData[event.HostAgentDeployFailuresKey]; ok {
+		var err error
+		threshold, err = strconv.Atoi(val)
+		if err != nil {
+			return nil, errors.Wrapf(err, "subscriber has invalid number of agent deploy failures '%s'", val)
+		}
+	}
+	if threshold <= 0 {
+		threshold = 1
+	}
+
+	// only notify when the number of consecutive failures reaches the
+	// threshold, rather than on every failure after it
+	failures, err := event.ConsecutiveAgentDeployFailures(t.host.Id, threshold+1)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to count agent deploy failures")
+	}
+	if failures != threshold {
+		return nil, nil
+	}
+
+	description := fmt.Sprintf("The agent has failed to deploy %d consecutive times.", failures)
+	if t.data.Logs != "" {
+		logs, _ := truncateString(t.data.Logs, hostLogsLimit)
+		description = fmt.Sprintf("%s The most recent error was: %s", description, logs)
+	}
+
+	return t.generateHostNotification(sub, "failed to deploy the agent", description)
+}
+
+func (t *hostMonitoringTriggers) hostIdle(sub *event.Subscription) (*notification.Notification, error) {
+	if t.event.EventType != event.EventHostIdle {
+		return nil, nil
+	}
+
+	threshold := hostDefaultIdleDuration
+	if val, ok := sub.TriggerData[event.HostIdleDurationKey]; ok {
+		secs, err := strconv.Atoi(val)
+		if err != nil {
+			return nil, errors.Wrapf(err, "subscriber has invalid idle duration '%s'", val)
+		}
+		threshold = time.Duration(secs) * time.Second
+	}
+	if t.data.Duration < threshold {
+		return nil, nil
+	}
+
+	// notify each subscriber once per idle period, which ends when the
+	// host completes another task
+	record, err := alertrecord.FindByHostIdle(sub.ID, t.host.Id, t.host.LastTask)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to fetch alert record")
+	}
+	if record != nil {
+		return nil, nil
+	}
+	if err = alertrecord.InsertNewHostIdleRecord(sub.ID, t.host.Id, t.host.LastTask); err != nil {
+		return nil, errors.Wrap(err, "failed to save alert record")
+	}
+
+	description := fmt.Sprintf("The host has been idle for %s, which exceeds the threshold of %s.",
+		t.data.Duration.Round(time.Minute), threshold)
+
+	return t.generateHostNotification(sub, "been idle", description)
+}
+
+func (t *hostMonitoringTriggers) hostTerminatedExternally(sub *event.Subscription) (*notification.Notification, error) {
+	if t.event.EventType != event.EventHostTerminatedExternally {
+		return nil, nil
+	}
+
+	description := "The host was terminated outside of Evergreen."
+	if t.data.OldStatus != "" {
+		description = fmt.Sprintf("The host was terminated outside of Evergreen while it was %s.", t.data.OldStatus)
+	}
+
+	return t.generateHostNotification(sub, "been terminated externally", description)
+}
+
+func (t *hostBase) generateHostNotification(sub *event.Subscription, pastTenseStatus, description string) (*notification.Notification, error) {
+	api := restModel.APIHost{}
+	if err := api.BuildFromService(t.host); err != nil {
+		return nil, errors.Wrap(err, "error building json model")
+	}
+
+	data := commonTemplateData{
+		ID:              t.host.Id,
+		DisplayName:     t.host.Id,
+		Object:          objectHost,
+		Project:         t.hostProject(),
+		Description:     description,
+		URL:             hostLink(&t.uiConfig, t.host.Id),
+		PastTenseStatus: pastTenseStatus,
+		apiModel:        &api,
+	}
+
+	attachment := message.SlackAttachment{
+		Title:     fmt.Sprintf("Evergreen Host: %s", t.host.Id),
+		TitleLink: data.URL,
+		Text:      description,
+		Color:     evergreenFailColor,
+		Fields: []*message.SlackAttachmentField{
+			{
+				Title: "Distro",
+				Value: t.host.Distro.Id,
+				Short: true,
+			},
+		},
+	}
+	if project := t.hostProject(); project != "" {
+		attachment.Fields = append(attachment.Fields, &message.SlackAttachmentField{
+			Title: "Project",
+			Value: project,
+			Short: true,
+		})
+	}
+	data.slack = []message.SlackAttachment{attachment}
+
+	payload, err := makeCommonPayload(sub, t.Selectors(), &data)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to build notification")
+	}
+
+	return notification.New(t.event, sub.Trigger, &sub.Subscriber, payload)
+}
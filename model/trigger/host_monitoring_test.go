@@ -0,0 +1,155 @@
+package trigger
+
+import (
+	"testing"
+	"time"
+
+	"github.com/evergreen-ci/evergreen"
+	"github.com/evergreen-ci/evergreen/db"
+	"github.com/evergreen-ci/evergreen/model/alertrecord"
+	"github.com/evergreen-ci/evergreen/model/distro"
+	"github.com/evergreen-ci/evergreen/model/event"
+	"github.com/evergreen-ci/evergreen/model/host"
+	"github.com/evergreen-ci/evergreen/model/notification"
+	"github.com/evergreen-ci/evergreen/testutil"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/suite"
+	"gopkg.in/mgo.v2/bson"
+)
+
+func TestHostMonitoringTriggers(t *testing.T) {
+	suite.Run(t, &hostMonitoringTriggersSuite{})
+}
+
+type hostMonitoringTriggersSuite struct {
+	e   event.EventLogEntry
+	h   host.Host
+	sub event.Subscription
+
+	suite.Suite
+}
+
+func (s *hostMonitoringTriggersSuite) SetupSuite() {
+	db.SetGlobalSessionProvider(testutil.TestConfig().SessionFactory())
+}
+
+func (s *hostMonitoringTriggersSuite) SetupTest() {
+	s.Require().NoError(db.ClearCollections(host.Collection, event.AllLogCollection, alertrecord.Collection))
+	s.h = host.Host{
+		Id:          "h0",
+		StartedBy:   evergreen.User,
+		Provisioned: true,
+		LastTask:    "task",
+		LastProject: "project",
+		Distro: distro.Distro{
+			Id: "distro",
+		},
+	}
+	s.Require().NoError(s.h.Insert())
+
+	s.e = event.EventLogEntry{
+		ResourceId:   s.h.Id,
+		Data:         &event.HostEventData{},
+		ResourceType: event.ResourceTypeHost,
+	}
+
+	s.sub = event.Subscription{
+		ID:         bson.NewObjectId().Hex(),
+		Subscriber: event.NewSlackSubscriber("#distro-owners"),
+	}
+}
+
+func (s *hostMonitoringTriggersSuite) TestSelectors() {
+	s.e.EventType = event.EventHostTerminatedExternally
+	t := makeHostMonitoringTriggers()
+	s.NoError(t.Fetch(&s.e))
+
+	selectors := t.Selectors()
+	s.Contains(selectors, event.Selector{Type: selectorDistro, Data: "distro"})
+	s.Contains(selectors, event.Selector{Type: selectorProject, Data: "project"})
+}
+
+func (s *hostMonitoringTriggersSuite) TestProvisionFailed() {
+	s.e.EventType = event.EventHostProvisionFailed
+	s.e.Data = &event.HostEventData{Logs: "setup script failed"}
+	s.sub.Trigger = triggerProvisionFailed
+
+	// provisioning events are handled by the spawn host triggers, but
+	// apply to every host
+	t := makeSpawnHostTriggers()
+	s.NoError(t.Fetch(&s.e))
+	n, err := t.Process(&s.sub)
+	s.NoError(err)
+	s.Require().NotNil(n)
+	s.Contains(n.Payload.(*notification.SlackPayload).Attachments[0].Text, "setup script failed")
+
+	s.e.EventType = event.EventHostProvisioned
+	s.NoError(t.Fetch(&s.e))
+	n, err = t.Process(&s.sub)
+	s.NoError(err)
+	s.Nil(n)
+}
+
+func (s *hostMonitoringTriggersSuite) TestAgentDeployFailed() {
+	s.sub.Trigger = triggerAgentDeployFailed
+	s.sub.TriggerData = map[string]string{event.HostAgentDeployFailuresKey: "2"}
+	s.e.EventType = event.EventHostAgentDeployFailed
+
+	for i := 1; i <= 3; i++ {
+		event.LogHostAgentDeployFailed(s.h.Id, errors.New("ssh failed"))
+		t := makeHostMonitoringTriggers()
+		s.NoError(t.Fetch(&s.e))
+		n, err := t.Process(&s.sub)
+		s.NoError(err)
+		if i == 2 {
+			s.NotNil(n, "attempt %d", i)
+		} else {
+			s.Nil(n, "attempt %d", i)
+		}
+	}
+}
+
+func (s *hostMonitoringTriggersSuite) TestIdle() {
+	s.sub.Trigger = triggerIdle
+	s.sub.TriggerData = map[string]string{event.HostIdleDurationKey: "3600"}
+	s.e.EventType = event.EventHostIdle
+
+	s.e.Data = &event.HostEventData{Duration: 30 * time.Minute}
+	t := makeHostMonitoringTriggers()
+	s.NoError(t.Fetch(&s.e))
+	n, err := t.Process(&s.sub)
+	s.NoError(err)
+	s.Nil(n)
+
+	s.e.Data = &event.HostEventData{Duration: 90 * time.Minute}
+	t = makeHostMonitoringTriggers()
+	s.NoError(t.Fetch(&s.e))
+	n, err = t.Process(&s.sub)
+	s.NoError(err)
+	s.NotNil(n)
+
+	// the host remaining idle shouldn't notify again
+	s.e.Data = &event.HostEventData{Duration: 120 * time.Minute}
+	t = makeHostMonitoringTriggers()
+	s.NoError(t.Fetch(&s.e))
+	n, err = t.Process(&s.sub)
+	s.NoError(err)
+	s.Nil(n)
+}
+
+func (s *hostMonitoringTriggersSuite) TestTerminatedExternally() {
+	s.sub.Trigger = triggerTerminatedExternally
+	s.e.EventType = event.EventHostTerminatedExternally
+	s.e.Data = &event.HostEventData{OldStatus: evergreen.HostRunning}
+
+	t := makeHostMonitoringTriggers()
+	s.NoError(t.Fetch(&s.e))
+	n, err := t.Process(&s.sub)
+	s.NoError(err)
+	s.NotNil(n)
+
+	s.sub.Trigger = triggerIdle
+	n, err = t.Process(&s.sub)
+	s.NoError(err)
+	s.Nil(n)
+}
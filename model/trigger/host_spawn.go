@@ -23,7 +23,8 @@ type spawnHostTriggers struct {
 func makeSpawnHostTriggers() eventHandler {
 	t := &spawnHostTriggers{}
 	t.triggers = map[string]trigger{
-		triggerOutcome: t.hostSpawnOutcome,
+		triggerOutcome:         t.hostSpawnOutcome,
+		triggerProvisionFailed: t.hostProvisionFailed,
 	}
 	return t
 }
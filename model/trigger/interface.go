@@ -28,6 +28,14 @@ type eventHandler interface {
 	ValidateTrigger(string) bool
 }
 
+// additionalNotificationsHandler is implemented by eventHandlers whose
+// triggers can notify recipients other than the subscriber
+type additionalNotificationsHandler interface {
+	// AdditionalNotifications returns the notifications created for
+	// other recipients while processing subscriptions
+	AdditionalNotifications() []notification.Notification
+}
+
 type trigger func(*event.Subscription) (*notification.Notification, error)
 
 type base struct {
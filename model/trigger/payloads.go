@@ -276,6 +276,10 @@ func buildLink(ui *evergreen.UIConfig, buildID string) string {
 	return fmt.Sprintf("%s/build/%s/", ui.Url, buildID)
 }
 
+func hostLink(ui *evergreen.UIConfig, hostID string) string {
+	return fmt.Sprintf("%s/host/%s", ui.Url, hostID)
+}
+
 func versionLink(ui *evergreen.UIConfig, versionID string) string {
 	return fmt.Sprintf("%s/version/%s/", ui.Url, versionID)
 }
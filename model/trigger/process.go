@@ -1,6 +1,8 @@
 package trigger
 
 import (
+	"strings"
+
 	"github.com/evergreen-ci/evergreen/model/event"
 	"github.com/evergreen-ci/evergreen/model/notification"
 	"github.com/mongodb/grip"
@@ -70,5 +72,52 @@ func NotificationsFromEvent(e *event.EventLogEntry) ([]notification.Notification
 		notifications = append(notifications, *n)
 	}
 
+	if extra, ok := h.(additionalNotificationsHandler); ok {
+		notifications = appendUniqueNotifications(notifications, extra.AdditionalNotifications())
+	}
+
 	return notifications, catcher.Resolve()
 }
+
+// appendUniqueNotifications appends the notifications whose IDs are not
+// already present, since notifications with the same ID collide on insert
+func appendUniqueNotifications(notifications []notification.Notification, toAdd []notification.Notification) []notification.Notification {
+	seen := map[string]bool{}
+	for i := range notifications {
+		seen[notifications[i].ID] = true
+	}
+	for i := range toAdd {
+		if seen[toAdd[i].ID] {
+			continue
+		}
+		seen[toAdd[i].ID] = true
+		notifications = append(notifications, toAdd[i])
+	}
+
+	return notifications
+}
+
+// EventSelectors returns the selectors that subscriptions would be
+// matched against for the given event. Events without a registered
+// event handler only have the id and object selectors.
+func EventSelectors(e *event.EventLogEntry) ([]event.Selector, error) {
+	h := registry.findEventHandler(e.ResourceType, e.EventType)
+	if h == nil {
+		return []event.Selector{
+			{
+				Type: selectorID,
+				Data: e.ResourceId,
+			},
+			{
+				Type: selectorObject,
+				Data: strings.ToLower(e.ResourceType),
+			},
+		}, nil
+	}
+
+	if err := h.Fetch(e); err != nil {
+		return nil, errors.Wrapf(err, "error fetching data for event: %s (%s, %s)", e.ID, e.ResourceType, e.EventType)
+	}
+
+	return h.Selectors(), nil
+}
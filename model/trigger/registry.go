@@ -24,16 +24,26 @@ type triggerRegistry struct {
 }
 
 func (r *triggerRegistry) eventHandler(resourceType, eventDataType string) eventHandler {
-	r.lock.RLock()
-	defer r.lock.RUnlock()
-
-	f, ok := r.handlers[registryKey{resourceType: resourceType, eventDataType: eventDataType}]
-	if !ok {
+	h := r.findEventHandler(resourceType, eventDataType)
+	if h == nil {
 		grip.Error(message.Fields{
 			"message": "unknown event handler",
 			"r_type":  resourceType,
 			"cause":   "programmer error",
 		})
+	}
+
+	return h
+}
+
+// findEventHandler returns an eventHandler for the resource type and
+// event type, or nil if none is registered.
+func (r *triggerRegistry) findEventHandler(resourceType, eventDataType string) eventHandler {
+	r.lock.RLock()
+	defer r.lock.RUnlock()
+
+	f, ok := r.handlers[registryKey{resourceType: resourceType, eventDataType: eventDataType}]
+	if !ok {
 		return nil
 	}
 
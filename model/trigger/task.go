@@ -50,6 +50,8 @@ func makeTaskTriggers() eventHandler {
 		triggerRuntimeChangeByPercent:            t.taskRuntimeChange,
 		triggerRegression:                        t.taskRegression,
 		triggerTaskRegressionByTest:              t.taskRegressionByTest,
+		triggerTaskTestStartedFailing:            t.taskTestStartedFailing,
+		triggerTaskBisectCulprit:                 t.taskBisectCulprit,
 	}
 
 	return t
@@ -118,6 +120,9 @@ type taskTriggers struct {
 
 	oldTestResults map[string]*task.TestResult
 
+	testFailures         []testFailureSuspects
+	suspectNotifications map[string]notification.Notification
+
 	base
 }
 
@@ -146,6 +151,16 @@ func (t *taskTriggers) Fetch(e *event.EventLogEntry) error {
 	return nil
 }
 
+// Process only evaluates the bisect culprit trigger for the events logged
+// by bisection, and the other task triggers for tasks finishing.
+func (t *taskTriggers) Process(sub *event.Subscription) (*notification.Notification, error) {
+	if (t.event.EventType == event.TaskBisectCulpritFound) != (sub.Trigger == triggerTaskBisectCulprit) {
+		return nil, nil
+	}
+
+	return t.base.Process(sub)
+}
+
 func (t *taskTriggers) Selectors() []event.Selector {
 	return []event.Selector{
 		{
@@ -354,6 +369,10 @@ func isTaskRegression(t *task.Task) (bool, *alertrecord.AlertRecord, error) {
 	if t.Status != evergreen.TaskFailed || t.Requester != evergreen.RepotrackerVersionRequester {
 		return false, nil, nil
 	}
+	// execution tasks are reported through their display task
+	if t.IsPartOfDisplay() {
+		return false, nil, nil
+	}
 
 	previousTask, err := task.FindOne(task.ByBeforeRevisionWithStatuses(t.RevisionOrderNumber,
 		task.CompletedStatuses, t.BuildVariant, t.DisplayName, t.Project))
@@ -0,0 +1,91 @@
+package trigger
+
+import (
+	"fmt"
+	"strconv"
+
+	"github.com/evergreen-ci/evergreen/model/event"
+	"github.com/evergreen-ci/evergreen/model/notification"
+	"github.com/evergreen-ci/evergreen/model/version"
+	"github.com/mongodb/grip"
+	"github.com/mongodb/grip/message"
+)
+
+func init() {
+	registry.registerEventHandler(event.ResourceTypeTask, event.TaskBisectCulpritFound, makeTaskTriggers)
+}
+
+const triggerTaskBisectCulprit = "bisect-culprit"
+
+func (t *taskTriggers) taskBisectCulprit(sub *event.Subscription) (*notification.Notification, error) {
+	if t.event.EventType != event.TaskBisectCulpritFound {
+		return nil, nil
+	}
+
+	n, err := t.generate(sub, "was found by bisection to have started failing")
+	if err != nil {
+		return nil, err
+	}
+
+	if notify, _ := strconv.ParseBool(sub.TriggerData[event.TaskNotifySuspectsKey]); notify {
+		t.addCulpritNotification(sub.Trigger)
+	}
+
+	return n, nil
+}
+
+// addCulpritNotification adds an email notification for the author of
+// the commit that bisection found to have broken the task.
+func (t *taskTriggers) addCulpritNotification(triggerName string) {
+	v, err := version.FindOne(version.ById(t.task.Version))
+	if err != nil || v == nil || v.AuthorEmail == "" {
+		grip.Error(message.WrapError(err, message.Fields{
+			"source":  "notifications-errors",
+			"message": "failed to find the author of the bisect culprit",
+			"task_id": t.task.Id,
+			"version": t.task.Version,
+		}))
+		return
+	}
+
+	if t.suspectNotifications == nil {
+		t.suspectNotifications = map[string]notification.Notification{}
+	}
+	target := v.AuthorEmail
+	subscriber := event.Subscriber{
+		Type:   event.EmailSubscriberType,
+		Target: &target,
+	}
+	n, err := notification.New(t.event, triggerName, &subscriber, t.culpritEmail(v))
+	if err != nil {
+		grip.Error(message.WrapError(err, message.Fields{
+			"source":  "notifications-errors",
+			"message": "failed to create notification for the author of the bisect culprit",
+			"task_id": t.task.Id,
+		}))
+		return
+	}
+	t.suspectNotifications[n.ID] = *n
+}
+
+const culpritEmailSubjectTemplate string = `Evergreen: your commit caused '%s' to fail in '%s'`
+const culpritEmailTemplate string = `<html>
+<head>
+</head>
+<body>
+<p>Hi,</p>
+
+<p>The Evergreen task <a href="%s">%s</a> on '%s' in '%s' has been failing, and bisecting its mainline history found that it started failing at your commit <a href="%s">%s</a>.</p>
+
+</body>
+</html>
+`
+
+func (t *taskTriggers) culpritEmail(v *version.Version) *message.Email {
+	return &message.Email{
+		Subject: fmt.Sprintf(culpritEmailSubjectTemplate, t.task.DisplayName, t.task.Project),
+		Body: fmt.Sprintf(culpritEmailTemplate, taskLink(&t.uiConfig, t.task.Id, t.task.Execution),
+			t.task.DisplayName, t.task.BuildVariant, t.task.Project, versionLink(&t.uiConfig, v.Id), v.Revision),
+		PlainTextContents: false,
+	}
+}
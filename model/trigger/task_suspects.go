@@ -0,0 +1,363 @@
+package trigger
+
+import (
+	"bytes"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/evergreen-ci/evergreen"
+	"github.com/evergreen-ci/evergreen/model/alertrecord"
+	"github.com/evergreen-ci/evergreen/model/event"
+	"github.com/evergreen-ci/evergreen/model/notification"
+	"github.com/evergreen-ci/evergreen/model/task"
+	"github.com/evergreen-ci/evergreen/model/version"
+	"github.com/evergreen-ci/evergreen/util"
+	"github.com/mongodb/grip"
+	"github.com/mongodb/grip/message"
+	"github.com/pkg/errors"
+)
+
+const (
+	triggerTaskTestStartedFailing = "test-started-failing"
+
+	// suspectsMaxPreviousTasks is the number of previous mainline
+	// executions of a task that are searched for a passing run of a
+	// failing test.
+	suspectsMaxPreviousTasks = 25
+)
+
+// suspectedCommit is a mainline commit between the last passing run of
+// a test and its first failure.
+type suspectedCommit struct {
+	VersionID   string
+	Revision    string
+	Author      string
+	AuthorEmail string
+	Message     string
+	URL         string
+}
+
+// testFailureSuspects groups the tests that started failing in a task
+// since the same passing task, along with the commits since then.
+type testFailureSuspects struct {
+	Tests               []string
+	LastPassingTaskID   string
+	LastPassingRevision string
+	Commits             []suspectedCommit
+}
+
+func (t *taskTriggers) taskTestStartedFailing(sub *event.Subscription) (*notification.Notification, error) {
+	if t.task.Requester != evergreen.RepotrackerVersionRequester || !isFailedTaskStatus(t.task.Status) || t.task.DisplayOnly {
+		return nil, nil
+	}
+
+	// the suspects are computed once per event, since alert records
+	// are written while finding them
+	if t.testFailures == nil {
+		failures, err := t.findTestsStartedFailing()
+		if failures == nil {
+			return nil, errors.Wrap(err, "failed to find tests that started failing")
+		}
+		grip.Error(message.WrapError(err, message.Fields{
+			"source":  "notifications-errors",
+			"message": "errors finding suspected commits",
+			"task_id": t.task.Id,
+		}))
+		t.testFailures = failures
+	}
+	if len(t.testFailures) == 0 {
+		return nil, nil
+	}
+
+	var payload interface{}
+	if sub.Subscriber.Type == event.JIRAIssueSubscriberType {
+		issueSub, ok := sub.Subscriber.Target.(*event.JIRAIssueSubscriber)
+		if !ok {
+			return nil, errors.Errorf("unexpected target data type: '%T'", sub.Subscriber.Target)
+		}
+		issue, err := t.makeJIRATaskPayload(issueSub.Project)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to create jira payload for task")
+		}
+		issue.Description += suspectsJIRADescription(t.testFailures)
+		payload = issue
+
+	} else {
+		data, err := t.makeData(sub, "")
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to collect task data")
+		}
+		data.Description = suspectsDescription(t.testFailures)
+		data.slack = append(data.slack, suspectsSlackAttachments(t.testFailures)...)
+
+		payload, err = makeCommonPayload(sub, t.Selectors(), data)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to build notification")
+		}
+	}
+
+	if notify, _ := strconv.ParseBool(sub.TriggerData[event.TaskNotifySuspectsKey]); notify {
+		t.addSuspectNotifications(sub.Trigger)
+	}
+
+	return notification.New(t.event, sub.Trigger, &sub.Subscriber, payload)
+}
+
+// findTestsStartedFailing finds the failed tests of the task whose most
+// recent result in a previous mainline execution of the task passed,
+// and the commits made since that passing execution.
+func (t *taskTriggers) findTestsStartedFailing() ([]testFailureSuspects, error) {
+	failedTests := []string{}
+	for _, test := range t.task.LocalTestResults {
+		if test.Status == evergreen.TestFailedStatus {
+			failedTests = append(failedTests, test.TestFile)
+		}
+	}
+	failedTests = util.UniqueStrings(failedTests)
+	if len(failedTests) == 0 {
+		return []testFailureSuspects{}, nil
+	}
+
+	previousTasks, err := task.Find(task.ByBeforeRevisionWithStatusesAndRequester(t.task.RevisionOrderNumber,
+		task.CompletedStatuses, t.task.BuildVariant, t.task.DisplayName, t.task.Project,
+		evergreen.RepotrackerVersionRequester).Limit(suspectsMaxPreviousTasks))
+	if err != nil {
+		return nil, errors.Wrap(err, "error fetching previous tasks")
+	}
+	previousResults := make([]map[string]*task.TestResult, len(previousTasks))
+
+	groups := map[string]*testFailureSuspects{}
+	lastPassingTasks := []*task.Task{}
+	catcher := grip.NewBasicCatcher()
+	for _, testFile := range failedTests {
+		var lastPassing *task.Task
+		for i := range previousTasks {
+			if previousResults[i] == nil {
+				if err = previousTasks[i].MergeNewTestResults(); err != nil {
+					return nil, errors.Wrapf(err, "error fetching test results for task '%s'", previousTasks[i].Id)
+				}
+				previousResults[i] = mapTestResultsByTestFile(&previousTasks[i])
+			}
+
+			result, ok := previousResults[i][testFile]
+			if !ok {
+				continue
+			}
+			if result.Status == evergreen.TestFailedStatus {
+				break
+			}
+			if result.Status == evergreen.TestSucceededStatus {
+				lastPassing = &previousTasks[i]
+				break
+			}
+		}
+		if lastPassing == nil {
+			continue
+		}
+
+		record, err := alertrecord.FindByLastTestStartedFailing(testFile, t.task.DisplayName, t.task.BuildVariant, t.task.Project, t.task.RevisionOrderNumber)
+		if err != nil {
+			catcher.Add(errors.Wrap(err, "failed to fetch alert record"))
+			continue
+		}
+		if record != nil && record.RevisionOrderNumber > lastPassing.RevisionOrderNumber {
+			continue
+		}
+		err = alertrecord.InsertNewTestStartedFailingRecord(testFile, t.task.Id, t.task.DisplayName, t.task.BuildVariant, t.task.Project, t.task.RevisionOrderNumber)
+		if err != nil {
+			catcher.Add(err)
+			continue
+		}
+
+		group, ok := groups[lastPassing.Id]
+		if !ok {
+			group = &testFailureSuspects{
+				LastPassingTaskID:   lastPassing.Id,
+				LastPassingRevision: lastPassing.Revision,
+			}
+			groups[lastPassing.Id] = group
+			lastPassingTasks = append(lastPassingTasks, lastPassing)
+		}
+		group.Tests = append(group.Tests, cleanTestName(testFile))
+	}
+
+	out := make([]testFailureSuspects, 0, len(lastPassingTasks))
+	for _, lastPassing := range lastPassingTasks {
+		group := groups[lastPassing.Id]
+		group.Commits, err = t.suspectedCommitsSince(lastPassing.RevisionOrderNumber)
+		if err != nil {
+			catcher.Add(err)
+			continue
+		}
+		out = append(out, *group)
+	}
+
+	return out, catcher.Resolve()
+}
+
+// suspectedCommitsSince returns the mainline commits after the given
+// revision order number, up to and including the task's revision.
+func (t *taskTriggers) suspectedCommitsSince(order int) ([]suspectedCommit, error) {
+	versions, err := version.Find(version.ByProjectIdAndOrderRange(t.task.Project, order, t.task.RevisionOrderNumber))
+	if err != nil {
+		return nil, errors.Wrap(err, "error fetching suspected versions")
+	}
+
+	commits := make([]suspectedCommit, 0, len(versions))
+	for _, v := range versions {
+		commits = append(commits, suspectedCommit{
+			VersionID:   v.Id,
+			Revision:    v.Revision,
+			Author:      v.Author,
+			AuthorEmail: v.AuthorEmail,
+			Message:     strings.SplitN(v.Message, "\n", 2)[0],
+			URL:         versionLink(&t.uiConfig, v.Id),
+		})
+	}
+
+	return commits, nil
+}
+
+// addSuspectNotifications adds an email notification for each author
+// of a suspected commit.
+func (t *taskTriggers) addSuspectNotifications(triggerName string) {
+	if t.suspectNotifications == nil {
+		t.suspectNotifications = map[string]notification.Notification{}
+	}
+
+	for _, email := range suspectAuthorEmails(t.testFailures) {
+		target := email
+		subscriber := event.Subscriber{
+			Type:   event.EmailSubscriberType,
+			Target: &target,
+		}
+		n, err := notification.New(t.event, triggerName, &subscriber, t.suspectEmail())
+		if err != nil {
+			grip.Error(message.WrapError(err, message.Fields{
+				"source":  "notifications-errors",
+				"message": "failed to create notification for suspected author",
+				"task_id": t.task.Id,
+			}))
+			continue
+		}
+		t.suspectNotifications[n.ID] = *n
+	}
+}
+
+// AdditionalNotifications returns the notifications for the authors of
+// suspected commits.
+func (t *taskTriggers) AdditionalNotifications() []notification.Notification {
+	out := make([]notification.Notification, 0, len(t.suspectNotifications))
+	for _, n := range t.suspectNotifications {
+		out = append(out, n)
+	}
+
+	return out
+}
+
+const suspectEmailSubjectTemplate string = `Evergreen: your commit may have caused tests to fail in '%s'`
+const suspectEmailTemplate string = `<html>
+<head>
+</head>
+<body>
+<p>Hi,</p>
+
+<p>The Evergreen task <a href="%s">%s</a> on '%s' in '%s' has started failing, and a commit of yours is one of the suspected causes.</p>
+<p>%s</p>
+
+</body>
+</html>
+`
+
+func (t *taskTriggers) suspectEmail() *message.Email {
+	return &message.Email{
+		Subject: fmt.Sprintf(suspectEmailSubjectTemplate, t.task.Project),
+		Body: fmt.Sprintf(suspectEmailTemplate, taskLink(&t.uiConfig, t.task.Id, t.task.Execution),
+			t.task.DisplayName, t.task.BuildVariant, t.task.Project, suspectsDescription(t.testFailures)),
+		PlainTextContents: false,
+	}
+}
+
+func suspectAuthorEmails(failures []testFailureSuspects) []string {
+	emails := []string{}
+	for _, failure := range failures {
+		for _, commit := range failure.Commits {
+			if commit.AuthorEmail != "" {
+				emails = append(emails, commit.AuthorEmail)
+			}
+		}
+	}
+
+	return util.UniqueStrings(emails)
+}
+
+func suspectsDescription(failures []testFailureSuspects) string {
+	buf := &bytes.Buffer{}
+	for i, failure := range failures {
+		if i > 0 {
+			buf.WriteString(" ")
+		}
+		fmt.Fprintf(buf, "Tests %s started failing since they passed at %s. Suspected commits: %s.",
+			strings.Join(failure.Tests, ", "), shortRevision(failure.LastPassingRevision), formatSuspectedCommits(failure.Commits))
+	}
+
+	return buf.String()
+}
+
+func suspectsJIRADescription(failures []testFailureSuspects) string {
+	buf := &bytes.Buffer{}
+	for _, failure := range failures {
+		fmt.Fprintf(buf, "\nh3. Started failing since %s: %s\n", shortRevision(failure.LastPassingRevision), strings.Join(failure.Tests, ", "))
+		for _, commit := range failure.Commits {
+			fmt.Fprintf(buf, "* [%s|%s] by %s: %s\n", shortRevision(commit.Revision), commit.URL, commit.Author, commit.Message)
+		}
+	}
+
+	return buf.String()
+}
+
+func suspectsSlackAttachments(failures []testFailureSuspects) []message.SlackAttachment {
+	attachments := []message.SlackAttachment{}
+	for _, failure := range failures {
+		if len(attachments) >= slackAttachmentsLimit {
+			break
+		}
+		commits := []string{}
+		for _, commit := range failure.Commits {
+			commits = append(commits, fmt.Sprintf("<%s|%s> by %s: %s", commit.URL, shortRevision(commit.Revision), commit.Author, commit.Message))
+		}
+		attachments = append(attachments, message.SlackAttachment{
+			Title: fmt.Sprintf("Started failing since %s", shortRevision(failure.LastPassingRevision)),
+			Color: evergreenFailColor,
+			Fields: []*message.SlackAttachmentField{
+				{
+					Title: "Tests",
+					Value: strings.Join(failure.Tests, ", "),
+				},
+				{
+					Title: "Suspected commits",
+					Value: strings.Join(commits, "\n"),
+				},
+			},
+		})
+	}
+
+	return attachments
+}
+
+func formatSuspectedCommits(commits []suspectedCommit) string {
+	out := make([]string, 0, len(commits))
+	for _, commit := range commits {
+		out = append(out, fmt.Sprintf("%s by %s (%s)", shortRevision(commit.Revision), commit.Author, commit.Message))
+	}
+
+	return strings.Join(out, "; ")
+}
+
+func shortRevision(revision string) string {
+	if len(revision) > 7 {
+		return revision[:7]
+	}
+	return revision
+}
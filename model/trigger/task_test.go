@@ -12,7 +12,9 @@ import (
 	"github.com/evergreen-ci/evergreen/model/event"
 	"github.com/evergreen-ci/evergreen/model/task"
 	"github.com/evergreen-ci/evergreen/model/testresult"
+	"github.com/evergreen-ci/evergreen/model/version"
 	"github.com/evergreen-ci/evergreen/testutil"
+	"github.com/mongodb/grip/message"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/suite"
 	"gopkg.in/mgo.v2/bson"
@@ -690,6 +692,140 @@ func (s *taskSuite) TestRegressionByTestWithDuplicateTestNames() {
 	s.tryDoubleTrigger(true)
 }
 
+func (s *taskSuite) TestTestStartedFailing() {
+	s.NoError(db.ClearCollections(task.Collection, testresult.Collection, version.Collection))
+	for i := 1; i <= 4; i++ {
+		v := version.Version{
+			Id:                  fmt.Sprintf("version_%d", i),
+			Identifier:          s.task.Project,
+			Revision:            fmt.Sprintf("%d0000000000", i),
+			Author:              fmt.Sprintf("author %d", i),
+			AuthorEmail:         fmt.Sprintf("author%d@example.com", i),
+			Message:             "a commit\nwith details",
+			RevisionOrderNumber: i,
+			Requester:           evergreen.RepotrackerVersionRequester,
+		}
+		s.NoError(v.Insert())
+	}
+	target := "someone@example.com"
+	sub := event.Subscription{
+		ID:      bson.NewObjectId().Hex(),
+		Type:    event.ResourceTypeTask,
+		Trigger: triggerTaskTestStartedFailing,
+		Selectors: []event.Selector{
+			{
+				Type: "project",
+				Data: s.task.Project,
+			},
+		},
+		Subscriber: event.Subscriber{
+			Type:   event.EmailSubscriberType,
+			Target: &target,
+		},
+		TriggerData: map[string]string{
+			event.TaskNotifySuspectsKey: "true",
+		},
+	}
+
+	s.makeTask(1, evergreen.TaskSucceeded)
+	s.makeTest(1, 0, "", evergreen.TestSucceededStatus)
+	s.t = s.makeTaskTriggers(s.task.Id, s.task.Execution)
+	n, err := s.t.taskTestStartedFailing(&sub)
+	s.NoError(err)
+	s.Nil(n)
+
+	// the test was skipped at revision 2, so revisions 2 and 3 are suspects
+	s.makeTask(2, evergreen.TaskSucceeded)
+	s.makeTest(2, 0, "", evergreen.TestSkippedStatus)
+	s.makeTask(3, evergreen.TaskFailed)
+	s.makeTest(3, 0, "", evergreen.TestFailedStatus)
+	s.t = s.makeTaskTriggers(s.task.Id, s.task.Execution)
+	n, err = s.t.taskTestStartedFailing(&sub)
+	s.NoError(err)
+	s.Require().NotNil(n)
+	s.Require().Len(s.t.testFailures, 1)
+	s.Equal([]string{"test_0"}, s.t.testFailures[0].Tests)
+	s.Equal("task_1", s.t.testFailures[0].LastPassingTaskID)
+	s.Require().Len(s.t.testFailures[0].Commits, 2)
+	s.Equal("30000000000", s.t.testFailures[0].Commits[0].Revision)
+	s.Equal("a commit", s.t.testFailures[0].Commits[0].Message)
+	s.Equal("20000000000", s.t.testFailures[0].Commits[1].Revision)
+	s.Contains(n.Payload.(*message.Email).Body, "author 2")
+
+	extra := s.t.AdditionalNotifications()
+	s.Require().Len(extra, 2)
+	for _, author := range extra {
+		s.Equal(event.EmailSubscriberType, author.Subscriber.Type)
+	}
+
+	// the same task finishing again should not notify again
+	s.t = s.makeTaskTriggers(s.task.Id, s.task.Execution)
+	n, err = s.t.taskTestStartedFailing(&sub)
+	s.NoError(err)
+	s.Nil(n)
+
+	// the test continuing to fail should not notify
+	s.makeTask(4, evergreen.TaskFailed)
+	s.makeTest(4, 0, "", evergreen.TestFailedStatus)
+	s.t = s.makeTaskTriggers(s.task.Id, s.task.Execution)
+	n, err = s.t.taskTestStartedFailing(&sub)
+	s.NoError(err)
+	s.Nil(n)
+}
+
+func (s *taskSuite) TestBisectCulprit() {
+	s.NoError(db.ClearCollections(version.Collection))
+	v := version.Version{
+		Id:          s.task.Version,
+		Identifier:  s.task.Project,
+		Revision:    "10000000000",
+		AuthorEmail: "author@example.com",
+		Requester:   evergreen.RepotrackerVersionRequester,
+	}
+	s.NoError(v.Insert())
+
+	target := "someone@example.com"
+	sub := event.Subscription{
+		ID:      bson.NewObjectId().Hex(),
+		Type:    event.ResourceTypeTask,
+		Trigger: triggerTaskBisectCulprit,
+		Selectors: []event.Selector{
+			{
+				Type: "project",
+				Data: s.task.Project,
+			},
+		},
+		Subscriber: event.Subscriber{
+			Type:   event.EmailSubscriberType,
+			Target: &target,
+		},
+		TriggerData: map[string]string{
+			event.TaskNotifySuspectsKey: "true",
+		},
+	}
+
+	// tasks finishing don't fire the trigger
+	s.data.Status = evergreen.TaskFailed
+	n, err := s.t.Process(&sub)
+	s.NoError(err)
+	s.Nil(n)
+
+	// and bisect events don't fire the other task triggers
+	s.event.EventType = event.TaskBisectCulpritFound
+	n, err = s.t.Process(&s.subs[0])
+	s.NoError(err)
+	s.Nil(n)
+
+	n, err = s.t.Process(&sub)
+	s.NoError(err)
+	s.Require().NotNil(n)
+
+	extra := s.t.AdditionalNotifications()
+	s.Require().Len(extra, 1)
+	s.Equal("author@example.com", *extra[0].Subscriber.Target.(*string))
+	s.Contains(extra[0].Payload.(*message.Email).Body, v.Revision)
+}
+
 func (s *taskSuite) makeTaskTriggers(id string, execution int) *taskTriggers {
 	t := makeTaskTriggers()
 	e := event.EventLogEntry{
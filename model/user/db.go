@@ -23,6 +23,15 @@ var (
 	SettingsKey     = bsonutil.MustHaveTag(DBUser{}, "Settings")
 	APIKeyKey       = bsonutil.MustHaveTag(DBUser{}, "APIKey")
 	PubKeysKey      = bsonutil.MustHaveTag(DBUser{}, "PubKeys")
+	RolesKey        = bsonutil.MustHaveTag(DBUser{}, "SystemRoles")
+	LoginCacheKey   = bsonutil.MustHaveTag(DBUser{}, "LoginCache")
+	APITokensKey    = bsonutil.MustHaveTag(DBUser{}, "APITokens")
+
+	ServiceAccountKey = bsonutil.MustHaveTag(DBUser{}, "ServiceAccount")
+)
+
+var (
+	LoginCacheTokenKey = bsonutil.MustHaveTag(LoginCache{}, "Token")
 )
 
 var (
@@ -61,6 +70,14 @@ func ById(userId string) db.Q {
 	return db.Query(bson.M{IdKey: userId})
 }
 
+// ByLoginToken returns a query for the user whose login session has the
+// given token.
+func ByLoginToken(token string) db.Q {
+	return db.Query(bson.M{
+		bsonutil.GetDottedKeyName(LoginCacheKey, LoginCacheTokenKey): token,
+	})
+}
+
 func ByIds(userIds ...string) db.Q {
 	return db.Query(bson.M{
 		IdKey: bson.M{
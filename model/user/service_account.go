@@ -0,0 +1,81 @@
+package user
+
+import (
+	"regexp"
+	"strings"
+
+	"github.com/evergreen-ci/evergreen/db"
+	"github.com/pkg/errors"
+	"gopkg.in/mgo.v2"
+	"gopkg.in/mgo.v2/bson"
+)
+
+// ServiceAccountPrefix begins the id of every service account, so that
+// their actions are clearly attributed wherever a user id is recorded.
+const ServiceAccountPrefix = "svc-"
+
+var serviceAccountIDRegex = regexp.MustCompile("^" + ServiceAccountPrefix + "[a-z0-9][a-z0-9_-]*$")
+
+// IsServiceAccountID returns whether the user id belongs to a service
+// account.
+func IsServiceAccountID(id string) bool {
+	return strings.HasPrefix(id, ServiceAccountPrefix)
+}
+
+// IsServiceAccount returns whether the user is a service account, which
+// cannot log in and can only authenticate with API tokens.
+func (u *DBUser) IsServiceAccount() bool {
+	return u.ServiceAccount
+}
+
+// ByServiceAccount returns a query for all service accounts.
+func ByServiceAccount() db.Q {
+	return db.Query(bson.M{ServiceAccountKey: true})
+}
+
+// NewServiceAccount creates a service account with the given id, display
+// name and roles. Service accounts don't have an API key.
+func NewServiceAccount(id, displayName string, roles []string) (*DBUser, error) {
+	if !serviceAccountIDRegex.MatchString(id) {
+		return nil, errors.Errorf("service account id '%s' must start with '%s' and contain only lowercase letters, numbers, '-' and '_'",
+			id, ServiceAccountPrefix)
+	}
+	if roles == nil {
+		roles = []string{}
+	}
+
+	u := &DBUser{
+		Id:             id,
+		DispName:       displayName,
+		SystemRoles:    roles,
+		ServiceAccount: true,
+	}
+	if err := u.Insert(); err != nil {
+		if mgo.IsDup(err) {
+			return nil, errors.Errorf("user '%s' already exists", id)
+		}
+		return nil, errors.Wrapf(err, "failed to create service account '%s'", id)
+	}
+
+	return u, nil
+}
+
+// FindServiceAccounts returns all service accounts.
+func FindServiceAccounts() ([]DBUser, error) {
+	accounts, err := Find(ByServiceAccount().Sort([]string{IdKey}))
+	return accounts, errors.Wrap(err, "failed to fetch service accounts")
+}
+
+// RemoveServiceAccount deletes the service account with the given id, which
+// revokes all of its tokens.
+func RemoveServiceAccount(id string) error {
+	err := db.Remove(Collection, bson.M{
+		IdKey:             id,
+		ServiceAccountKey: true,
+	})
+	if err == mgo.ErrNotFound {
+		return errors.Errorf("service account '%s' not found", id)
+	}
+
+	return errors.Wrapf(err, "failed to remove service account '%s'", id)
+}
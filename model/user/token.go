@@ -0,0 +1,191 @@
+package user
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"strings"
+	"time"
+
+	"github.com/evergreen-ci/evergreen/util"
+	"github.com/mongodb/anser/bsonutil"
+	"github.com/mongodb/grip"
+	"github.com/pkg/errors"
+	"gopkg.in/mgo.v2/bson"
+)
+
+const (
+	// TokenScopeReadOnly allows a token to make requests that don't modify
+	// anything.
+	TokenScopeReadOnly = "read_only"
+	// TokenScopePatch allows a token to submit and modify patches.
+	TokenScopePatch = "patch"
+	// TokenScopeHost allows a token to spawn and modify hosts.
+	TokenScopeHost = "host"
+	// TokenScopeAdmin allows a token to do anything its user can do.
+	TokenScopeAdmin = "admin"
+
+	// APITokenPrefix begins every API token, which distinguishes them from
+	// users' API keys.
+	APITokenPrefix = "evg_"
+
+	// MaxAPITokenLifetime is the longest time an API token can be valid.
+	MaxAPITokenLifetime = 365 * 24 * time.Hour
+)
+
+// ValidTokenScopes are the scopes an API token can be given.
+var ValidTokenScopes = []string{
+	TokenScopeReadOnly,
+	TokenScopePatch,
+	TokenScopeHost,
+	TokenScopeAdmin,
+}
+
+// APIToken is a named, expiring credential that a user can authenticate
+// with in place of their API key. Only a hash of the token is stored.
+type APIToken struct {
+	Name      string    `bson:"name" json:"name"`
+	Hash      string    `bson:"hash" json:"-"`
+	Scopes    []string  `bson:"scopes" json:"scopes"`
+	CreatedAt time.Time `bson:"created_at" json:"created_at"`
+	ExpiresAt time.Time `bson:"expires_at" json:"expires_at"`
+}
+
+var (
+	APITokenNameKey = bsonutil.MustHaveTag(APIToken{}, "Name")
+	APITokenHashKey = bsonutil.MustHaveTag(APIToken{}, "Hash")
+)
+
+// HasScope returns whether the token was given the scope.
+func (t *APIToken) HasScope(scope string) bool {
+	return util.StringSliceContains(t.Scopes, scope)
+}
+
+// Expired returns whether the token is no longer valid at the given time.
+func (t *APIToken) Expired(now time.Time) bool {
+	return !now.Before(t.ExpiresAt)
+}
+
+// Validate checks that the token can be created.
+func (t *APIToken) Validate(now time.Time) error {
+	catcher := grip.NewBasicCatcher()
+	if strings.TrimSpace(t.Name) == "" {
+		catcher.Add(errors.New("token must have a name"))
+	}
+	if len(t.Scopes) == 0 {
+		catcher.Add(errors.New("token must have at least one scope"))
+	}
+	for _, scope := range t.Scopes {
+		if !util.StringSliceContains(ValidTokenScopes, scope) {
+			catcher.Add(errors.Errorf("'%s' is not a valid token scope", scope))
+		}
+	}
+	if !t.ExpiresAt.After(now) {
+		catcher.Add(errors.New("token must expire in the future"))
+	} else if t.ExpiresAt.Sub(now) > MaxAPITokenLifetime {
+		catcher.Add(errors.Errorf("token cannot be valid for longer than %s", MaxAPITokenLifetime))
+	}
+
+	return catcher.Resolve()
+}
+
+// HashAPIToken returns the hash of the token that is stored in its place.
+func HashAPIToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
+// GetAPIToken returns the user's token with the given name, or nil if the
+// user has no such token.
+func (u *DBUser) GetAPIToken(name string) *APIToken {
+	for i := range u.APITokens {
+		if u.APITokens[i].Name == name {
+			return &u.APITokens[i]
+		}
+	}
+
+	return nil
+}
+
+// FindAPIToken returns the user's unexpired token matching the given
+// token, or nil if there is none.
+func (u *DBUser) FindAPIToken(token string) *APIToken {
+	if !strings.HasPrefix(token, APITokenPrefix) {
+		return nil
+	}
+
+	hash := []byte(HashAPIToken(token))
+	now := time.Now()
+	for i := range u.APITokens {
+		if subtle.ConstantTimeCompare(hash, []byte(u.APITokens[i].Hash)) == 1 {
+			if u.APITokens[i].Expired(now) {
+				return nil
+			}
+			return &u.APITokens[i]
+		}
+	}
+
+	return nil
+}
+
+// AddAPIToken creates a token for the user with the given name, scopes and
+// expiration. It returns the token, which cannot be recovered afterwards.
+func (u *DBUser) AddAPIToken(name string, scopes []string, expiresAt time.Time) (string, error) {
+	now := time.Now()
+	apiToken := APIToken{
+		Name:      name,
+		Scopes:    scopes,
+		CreatedAt: now,
+		ExpiresAt: expiresAt,
+	}
+	if err := apiToken.Validate(now); err != nil {
+		return "", errors.Wrap(err, "invalid token")
+	}
+
+	secret := make([]byte, 32)
+	if _, err := rand.Read(secret); err != nil {
+		return "", errors.Wrap(err, "failed to generate token")
+	}
+	token := APITokenPrefix + hex.EncodeToString(secret)
+	apiToken.Hash = HashAPIToken(token)
+
+	userWithoutToken := bson.M{
+		IdKey: u.Id,
+		bsonutil.GetDottedKeyName(APITokensKey, APITokenNameKey): bson.M{"$ne": name},
+	}
+	update := bson.M{
+		"$push": bson.M{APITokensKey: apiToken},
+	}
+	if err := UpdateOne(userWithoutToken, update); err != nil {
+		return "", errors.Wrapf(err, "failed to add token '%s' for user '%s'", name, u.Id)
+	}
+
+	u.APITokens = append(u.APITokens, apiToken)
+	return token, nil
+}
+
+// DeleteAPIToken revokes the user's token with the given name.
+func (u *DBUser) DeleteAPIToken(name string) error {
+	userWithToken := bson.M{
+		IdKey: u.Id,
+		bsonutil.GetDottedKeyName(APITokensKey, APITokenNameKey): name,
+	}
+	update := bson.M{
+		"$pull": bson.M{
+			APITokensKey: bson.M{APITokenNameKey: name},
+		},
+	}
+	if err := UpdateOne(userWithToken, update); err != nil {
+		return errors.Wrapf(err, "failed to delete token '%s' for user '%s'", name, u.Id)
+	}
+
+	tokens := []APIToken{}
+	for _, t := range u.APITokens {
+		if t.Name != name {
+			tokens = append(tokens, t)
+		}
+	}
+	u.APITokens = tokens
+	return nil
+}
@@ -23,6 +23,19 @@ type DBUser struct {
 	Settings     UserSettings `bson:"settings"`
 	APIKey       string       `bson:"apikey"`
 	SystemRoles  []string     `bson:"roles"`
+	LoginCache   LoginCache   `bson:"login_cache,omitempty" json:"-"`
+	APITokens    []APIToken   `bson:"api_tokens,omitempty" json:"-"`
+
+	ServiceAccount bool `bson:"service_account,omitempty" json:"service_account,omitempty"`
+}
+
+// LoginCache holds the session of a user who logged in through an external
+// identity provider. Token is the value of the user's login cookie, and the
+// session must be refreshed with the provider after TTL.
+type LoginCache struct {
+	Token        string    `bson:"token,omitempty"`
+	TTL          time.Time `bson:"ttl,omitempty"`
+	RefreshToken string    `bson:"refresh_token,omitempty"`
 }
 
 type GithubUser struct {
@@ -136,6 +149,53 @@ func (u *DBUser) DeletePublicKey(keyName string) error {
 	return nil
 }
 
+// AddRole gives the user the role with the given id.
+func (u *DBUser) AddRole(roleID string) error {
+	if err := UpdateOne(bson.M{IdKey: u.Id}, bson.M{
+		"$addToSet": bson.M{RolesKey: roleID},
+	}); err != nil {
+		return errors.Wrapf(err, "failed to add role '%s' to user '%s'", roleID, u.Id)
+	}
+
+	for _, r := range u.SystemRoles {
+		if r == roleID {
+			return nil
+		}
+	}
+	u.SystemRoles = append(u.SystemRoles, roleID)
+	return nil
+}
+
+// RemoveRole removes the role with the given id from the user.
+func (u *DBUser) RemoveRole(roleID string) error {
+	if err := UpdateOne(bson.M{IdKey: u.Id}, bson.M{
+		"$pull": bson.M{RolesKey: roleID},
+	}); err != nil {
+		return errors.Wrapf(err, "failed to remove role '%s' from user '%s'", roleID, u.Id)
+	}
+
+	roles := []string{}
+	for _, r := range u.SystemRoles {
+		if r != roleID {
+			roles = append(roles, r)
+		}
+	}
+	u.SystemRoles = roles
+	return nil
+}
+
+// SetLoginCache replaces the user's login session.
+func (u *DBUser) SetLoginCache(cache LoginCache) error {
+	if err := UpdateOne(bson.M{IdKey: u.Id}, bson.M{
+		"$set": bson.M{LoginCacheKey: cache},
+	}); err != nil {
+		return errors.Wrapf(err, "failed to update login session of user '%s'", u.Id)
+	}
+
+	u.LoginCache = cache
+	return nil
+}
+
 func (u *DBUser) Insert() error {
 	u.CreatedAt = time.Now()
 	return db.Insert(Collection, u)
@@ -1,6 +1,7 @@
 package user
 
 import (
+	"strings"
 	"testing"
 	"time"
 
@@ -137,3 +138,117 @@ func (s *UserTestSuite) TestFindByGithubUID() {
 	s.NoError(err)
 	s.Nil(u)
 }
+
+func (s *UserTestSuite) TestRoles() {
+	s.NoError(s.users[0].AddRole("admins"))
+	s.NoError(s.users[0].AddRole("admins"))
+	s.NoError(s.users[0].AddRole("testers"))
+	s.Equal([]string{"admins", "testers"}, s.users[0].Roles())
+
+	u, err := FindOne(ById(s.users[0].Id))
+	s.NoError(err)
+	s.Require().NotNil(u)
+	s.Equal([]string{"admins", "testers"}, u.Roles())
+
+	s.NoError(s.users[0].RemoveRole("admins"))
+	s.Equal([]string{"testers"}, s.users[0].Roles())
+
+	u, err = FindOne(ById(s.users[0].Id))
+	s.NoError(err)
+	s.Require().NotNil(u)
+	s.Equal([]string{"testers"}, u.Roles())
+}
+
+func (s *UserTestSuite) TestLoginCache() {
+	u, err := FindOne(ByLoginToken("session"))
+	s.NoError(err)
+	s.Nil(u)
+
+	ttl := time.Now().Add(time.Hour).Round(time.Second)
+	s.NoError(s.users[0].SetLoginCache(LoginCache{Token: "session", TTL: ttl, RefreshToken: "refresh"}))
+
+	u, err = FindOne(ByLoginToken("session"))
+	s.NoError(err)
+	s.Require().NotNil(u)
+	s.Equal(s.users[0].Id, u.Id)
+	s.Equal("refresh", u.LoginCache.RefreshToken)
+	s.True(ttl.Equal(u.LoginCache.TTL))
+
+	s.NoError(s.users[0].SetLoginCache(LoginCache{}))
+	u, err = FindOne(ByLoginToken("session"))
+	s.NoError(err)
+	s.Nil(u)
+}
+
+func (s *UserTestSuite) TestAPITokens() {
+	expiresAt := time.Now().Add(time.Hour)
+	token, err := s.users[0].AddAPIToken("ci", []string{TokenScopePatch, TokenScopeReadOnly}, expiresAt)
+	s.NoError(err)
+	s.True(strings.HasPrefix(token, APITokenPrefix))
+	s.Require().Len(s.users[0].APITokens, 1)
+	s.NotEqual(token, s.users[0].APITokens[0].Hash)
+
+	_, err = s.users[0].AddAPIToken("ci", []string{TokenScopeReadOnly}, expiresAt)
+	s.Error(err)
+	_, err = s.users[0].AddAPIToken("bad scope", []string{"everything"}, expiresAt)
+	s.Error(err)
+	_, err = s.users[0].AddAPIToken("expired", []string{TokenScopeReadOnly}, time.Now().Add(-time.Minute))
+	s.Error(err)
+	_, err = s.users[0].AddAPIToken("forever", []string{TokenScopeReadOnly}, time.Now().Add(10*MaxAPITokenLifetime))
+	s.Error(err)
+
+	u, err := FindOne(ById(s.users[0].Id))
+	s.NoError(err)
+	s.Require().NotNil(u)
+	s.Require().Len(u.APITokens, 1)
+	found := u.FindAPIToken(token)
+	s.Require().NotNil(found)
+	s.Equal("ci", found.Name)
+	s.True(found.HasScope(TokenScopePatch))
+	s.False(found.HasScope(TokenScopeAdmin))
+	s.Nil(u.FindAPIToken(token + "0"))
+	s.Nil(u.FindAPIToken(s.users[0].APIKey))
+
+	u.APITokens[0].ExpiresAt = time.Now().Add(-time.Second)
+	s.Nil(u.FindAPIToken(token))
+
+	s.NoError(s.users[0].DeleteAPIToken("ci"))
+	s.Empty(s.users[0].APITokens)
+	s.Error(s.users[0].DeleteAPIToken("ci"))
+	u, err = FindOne(ById(s.users[0].Id))
+	s.NoError(err)
+	s.Require().NotNil(u)
+	s.Nil(u.FindAPIToken(token))
+}
+
+func (s *UserTestSuite) TestServiceAccounts() {
+	_, err := NewServiceAccount("ci", "CI", nil)
+	s.Error(err)
+	_, err = NewServiceAccount("svc-CI bot", "CI", nil)
+	s.Error(err)
+
+	bot, err := NewServiceAccount("svc-ci", "CI", []string{"patchers"})
+	s.NoError(err)
+	s.Require().NotNil(bot)
+	s.True(bot.IsServiceAccount())
+	s.True(IsServiceAccountID(bot.Id))
+	s.False(IsServiceAccountID(s.users[0].Id))
+	s.Empty(bot.APIKey)
+
+	_, err = NewServiceAccount("svc-ci", "CI", nil)
+	s.Error(err)
+
+	accounts, err := FindServiceAccounts()
+	s.NoError(err)
+	s.Require().Len(accounts, 1)
+	s.Equal("svc-ci", accounts[0].Id)
+	s.Equal([]string{"patchers"}, accounts[0].Roles())
+
+	// only service accounts can be removed
+	s.Error(RemoveServiceAccount(s.users[0].Id))
+	s.NoError(RemoveServiceAccount("svc-ci"))
+	s.Error(RemoveServiceAccount("svc-ci"))
+	accounts, err = FindServiceAccounts()
+	s.NoError(err)
+	s.Empty(accounts)
+}
@@ -34,7 +34,12 @@ func FindUserByID(id string) (*user.DBUser, error) {
 
 // GetOrCreateUser fetches a user with the given userId and returns it. If no document exists for
 // that userId, inserts it along with the provided display name and email.
+// Users log in through this, so it refuses service account ids.
 func GetOrCreateUser(userId, displayName, email string) (*user.DBUser, error) {
+	if user.IsServiceAccountID(userId) {
+		return nil, errors.Errorf("'%s' is a service account, which cannot log in", userId)
+	}
+
 	u := &user.DBUser{}
 	_, err := db.FindAndModify(user.Collection, bson.M{user.IdKey: userId}, nil,
 		mgo.Change{
@@ -40,6 +40,7 @@ var (
 	IdentifierKey          = bsonutil.MustHaveTag(Version{}, "Identifier")
 	RemoteKey              = bsonutil.MustHaveTag(Version{}, "Remote")
 	RemoteURLKey           = bsonutil.MustHaveTag(Version{}, "RemotePath")
+	TriggerIDKey           = bsonutil.MustHaveTag(Version{}, "TriggerID")
 )
 
 // ById returns a db.Q object which will filter on {_id : <the id param>}
@@ -99,6 +100,21 @@ func ByProjectIdAndOrder(projectId string, revisionOrderNumber int) db.Q {
 		})
 }
 
+// ByProjectIdAndOrderRange finds non-patch versions for the given project with
+// revision order numbers greater than afterOrder and less than or equal to
+// throughOrder, ordered by most recent first.
+func ByProjectIdAndOrderRange(projectId string, afterOrder, throughOrder int) db.Q {
+	return db.Query(
+		bson.M{
+			IdentifierKey: projectId,
+			RevisionOrderNumberKey: bson.M{
+				"$gt":  afterOrder,
+				"$lte": throughOrder,
+			},
+			RequesterKey: evergreen.RepotrackerVersionRequester,
+		}).Sort([]string{"-" + RevisionOrderNumberKey})
+}
+
 // ByLastVariantActivation finds the most recent non-patch, non-ignored
 // versions in a project that have a particular variant activated.
 func ByLastVariantActivation(projectId, variant string) db.Q {
@@ -150,6 +166,12 @@ func ByMostRecentNonIgnored(projectId string) db.Q {
 	).Sort([]string{"-" + RevisionOrderNumberKey})
 }
 
+// ByTriggerID finds the versions created by downstream triggers of the
+// given upstream version.
+func ByTriggerID(id string) db.Q {
+	return db.Query(bson.M{TriggerIDKey: id}).Sort([]string{CreateTimeKey})
+}
+
 func BySuccessfulBeforeRevision(project string, beforeRevision int) db.Q {
 	return db.Query(
 		bson.M{
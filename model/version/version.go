@@ -50,6 +50,19 @@ type Version struct {
 	// AuthorID is an optional reference to the Evergreen user that authored
 	// this comment, if they can be identified
 	AuthorID string `bson:"author_id,omitempty" json:"author_id,omitempty"`
+
+	// TriggerID is the id of the upstream version whose success, or the
+	// success of one of whose builds, created this version. TriggerType is
+	// the level of the downstream trigger, either "version" or "build".
+	TriggerID   string `bson:"trigger_id,omitempty" json:"trigger_id,omitempty"`
+	TriggerType string `bson:"trigger_type,omitempty" json:"trigger_type,omitempty"`
+	// TriggerProjects are the projects of all of the upstream versions in
+	// the chain of triggers that created this version, which keeps
+	// downstream triggers from looping.
+	TriggerProjects []string `bson:"trigger_projects,omitempty" json:"trigger_projects,omitempty"`
+	// TriggerExpansions describe the upstream version, and are added to the
+	// expansions of this version's tasks.
+	TriggerExpansions map[string]string `bson:"trigger_expansions,omitempty" json:"trigger_expansions,omitempty"`
 }
 
 func (v *Version) LastSuccessful() (*Version, error) {
@@ -27,6 +27,9 @@ func Admin() cli.Command {
 			listEvents(),
 			revert(),
 			fetchAllProjectConfigs(),
+			adminRoles(),
+			adminServiceAccounts(),
+			adminAudit(),
 		},
 	}
 }
@@ -162,8 +165,8 @@ func setServiceFlagValues(args []string, target bool, flags *model.APIServiceFla
 			flags.HostinitDisabled = target
 		case "monitor":
 			flags.MonitorDisabled = target
-		case "alerts", "alert", "notify", "notifications", "notification":
-			flags.AlertsDisabled = target
+		case "alerts", "alert", "notify", "notifications", "notification", "events", "event-processing":
+			flags.EventProcessingDisabled = target
 		case "taskrunner", "new-agents", "agents":
 			flags.TaskrunnerDisabled = target
 		case "github", "repotracker", "gitter", "commits", "repo-tracker":
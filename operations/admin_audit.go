@@ -0,0 +1,137 @@
+package operations
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"os"
+	"time"
+
+	"github.com/evergreen-ci/evergreen/model/audit"
+	"github.com/evergreen-ci/evergreen/rest/client"
+	"github.com/evergreen-ci/evergreen/rest/model"
+	"github.com/pkg/errors"
+	"github.com/urfave/cli"
+)
+
+func adminAudit() cli.Command {
+	return cli.Command{
+		Name:  "audit",
+		Usage: "inspect the log of actions users have taken",
+		Subcommands: []cli.Command{
+			adminAuditExport(),
+		},
+	}
+}
+
+func adminAuditExport() cli.Command {
+	const (
+		actorFlagName        = "actor"
+		resourceTypeFlagName = "resource-type"
+		resourceIDFlagName   = "resource-id"
+		actionFlagName       = "action"
+		startFlagName        = "start"
+		endFlagName          = "end"
+		fileFlagName         = "file"
+		pageSizeFlagName     = "page-size"
+	)
+
+	return cli.Command{
+		Name:  "export",
+		Usage: "write matching audit log entries, newest first, as JSON lines",
+		Flags: []cli.Flag{
+			cli.StringFlag{
+				Name:  actorFlagName,
+				Usage: "only export actions by this user",
+			},
+			cli.StringFlag{
+				Name:  resourceTypeFlagName,
+				Usage: "only export actions on this type of resource (e.g. task, host, project)",
+			},
+			cli.StringFlag{
+				Name:  resourceIDFlagName,
+				Usage: "only export actions on the resource with this id",
+			},
+			cli.StringFlag{
+				Name:  actionFlagName,
+				Usage: "only export this action (e.g. restart, abort, set_priority)",
+			},
+			cli.StringFlag{
+				Name:  startFlagName,
+				Usage: "only export actions at or after this RFC-3339 time",
+			},
+			cli.StringFlag{
+				Name:  endFlagName,
+				Usage: "only export actions before this RFC-3339 time",
+			},
+			cli.StringFlag{
+				Name:  joinFlagNames(fileFlagName, "f"),
+				Usage: "write entries to this file instead of standard output",
+			},
+			cli.IntFlag{
+				Name:  pageSizeFlagName,
+				Usage: "number of entries to fetch per request",
+				Value: 100,
+			},
+		},
+		Action: func(c *cli.Context) error {
+			filter := audit.Filter{
+				Actor:        c.String(actorFlagName),
+				ResourceType: c.String(resourceTypeFlagName),
+				ResourceID:   c.String(resourceIDFlagName),
+				Action:       c.String(actionFlagName),
+				Limit:        c.Int(pageSizeFlagName),
+			}
+			var err error
+			for flagName, t := range map[string]*time.Time{
+				startFlagName: &filter.Start,
+				endFlagName:   &filter.End,
+			} {
+				if c.String(flagName) == "" {
+					continue
+				}
+				if *t, err = time.Parse(time.RFC3339, c.String(flagName)); err != nil {
+					return errors.Wrapf(err, "problem parsing --%s as RFC-3339", flagName)
+				}
+			}
+			if filter.Limit <= 0 {
+				return errors.Errorf("--%s must be positive", pageSizeFlagName)
+			}
+
+			var out io.Writer = os.Stdout
+			if fileName := c.String(fileFlagName); fileName != "" {
+				f, err := os.Create(fileName)
+				if err != nil {
+					return errors.Wrapf(err, "problem creating '%s'", fileName)
+				}
+				defer f.Close()
+				out = f
+			}
+
+			return withRestCommunicator(c, func(ctx context.Context, comm client.Communicator) error {
+				return exportAuditLog(ctx, comm, filter, out)
+			})
+		},
+	}
+}
+
+// exportAuditLog writes every entry matching the filter to out, one JSON
+// document per line, fetching them a page at a time.
+func exportAuditLog(ctx context.Context, comm client.Communicator, filter audit.Filter, out io.Writer) error {
+	encoder := json.NewEncoder(out)
+	for {
+		entries, err := comm.GetAuditLog(ctx, filter)
+		if err != nil {
+			return errors.Wrap(err, "problem getting audit log")
+		}
+		for i := range entries {
+			if err = encoder.Encode(entries[i]); err != nil {
+				return errors.Wrap(err, "problem writing audit log entry")
+			}
+		}
+		if len(entries) < filter.Limit {
+			return nil
+		}
+		filter.Before = model.FromAPIString(entries[len(entries)-1].ID)
+	}
+}
@@ -0,0 +1,232 @@
+package operations
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+
+	"github.com/evergreen-ci/evergreen/rest/client"
+	"github.com/evergreen-ci/evergreen/rest/model"
+	"github.com/mongodb/grip"
+	"github.com/pkg/errors"
+	"github.com/urfave/cli"
+)
+
+func adminRoles() cli.Command {
+	return cli.Command{
+		Name:   "roles",
+		Usage:  "manage the roles that grant users permissions on projects and distros",
+		Before: setPlainLogger,
+		Subcommands: []cli.Command{
+			adminRolesList(),
+			adminRolesSet(),
+			adminRolesDelete(),
+			adminRolesGrant(),
+			adminRolesRevoke(),
+		},
+	}
+}
+
+func adminRolesList() cli.Command {
+	const userFlagName = "user"
+
+	return cli.Command{
+		Name:  "list",
+		Usage: "list all roles, or the roles of a user",
+		Flags: []cli.Flag{
+			cli.StringFlag{
+				Name:  joinFlagNames(userFlagName, "u"),
+				Usage: "only list the roles of this user",
+			},
+		},
+		Action: func(c *cli.Context) error {
+			userID := c.String(userFlagName)
+
+			return withRestCommunicator(c, func(ctx context.Context, comm client.Communicator) error {
+				if userID != "" {
+					roles, err := comm.GetUserRoles(ctx, userID)
+					if err != nil {
+						return errors.Wrapf(err, "problem getting roles of user '%s'", userID)
+					}
+					grip.Infof("%s: %s", userID, strings.Join(roles, ", "))
+					return nil
+				}
+
+				roles, err := comm.GetRoles(ctx)
+				if err != nil {
+					return errors.Wrap(err, "problem getting roles")
+				}
+				out, err := json.MarshalIndent(roles, "", "  ")
+				if err != nil {
+					return errors.Wrap(err, "problem marshalling roles")
+				}
+				grip.Info(string(out))
+				return nil
+			})
+		},
+	}
+}
+
+func adminRolesSet() cli.Command {
+	const (
+		idFlagName           = "id"
+		nameFlagName         = "name"
+		resourceTypeFlagName = "resource-type"
+		resourcesFlagName    = "resource"
+		permissionsFlagName  = "permission"
+	)
+
+	return cli.Command{
+		Name:  "set",
+		Usage: "create a role, or replace the role with the same id",
+		Flags: []cli.Flag{
+			cli.StringFlag{
+				Name:  idFlagName,
+				Usage: "id of the role",
+			},
+			cli.StringFlag{
+				Name:  nameFlagName,
+				Usage: "display name of the role",
+			},
+			cli.StringFlag{
+				Name:  resourceTypeFlagName,
+				Usage: "type of resource the role applies to ('project' or 'distro')",
+			},
+			cli.StringSliceFlag{
+				Name:  resourcesFlagName,
+				Usage: "resource the role applies to, or '*' for all (may be specified multiple times)",
+			},
+			cli.StringSliceFlag{
+				Name:  permissionsFlagName,
+				Usage: "permission the role grants (may be specified multiple times)",
+			},
+		},
+		Before: mergeBeforeFuncs(
+			requireStringFlag(idFlagName),
+			requireStringValueChoices(resourceTypeFlagName, []string{"project", "distro"}),
+		),
+		Action: func(c *cli.Context) error {
+			r := &model.APIRole{
+				ID:           model.ToAPIString(c.String(idFlagName)),
+				Name:         model.ToAPIString(c.String(nameFlagName)),
+				ResourceType: model.ToAPIString(c.String(resourceTypeFlagName)),
+				Resources:    []model.APIString{},
+				Permissions:  []model.APIString{},
+			}
+			for _, resource := range c.StringSlice(resourcesFlagName) {
+				r.Resources = append(r.Resources, model.ToAPIString(resource))
+			}
+			for _, permission := range c.StringSlice(permissionsFlagName) {
+				r.Permissions = append(r.Permissions, model.ToAPIString(permission))
+			}
+
+			return withRestCommunicator(c, func(ctx context.Context, comm client.Communicator) error {
+				if err := comm.UpdateRole(ctx, r); err != nil {
+					return errors.Wrap(err, "problem saving role")
+				}
+				grip.Infof("saved role '%s'", model.FromAPIString(r.ID))
+				return nil
+			})
+		},
+	}
+}
+
+func adminRolesDelete() cli.Command {
+	const idFlagName = "id"
+
+	return cli.Command{
+		Name:  "delete",
+		Usage: "delete a role; deleting a default role restores its original permissions",
+		Flags: []cli.Flag{
+			cli.StringFlag{
+				Name:  idFlagName,
+				Usage: "id of the role",
+			},
+		},
+		Before: requireStringFlag(idFlagName),
+		Action: func(c *cli.Context) error {
+			id := c.String(idFlagName)
+
+			return withRestCommunicator(c, func(ctx context.Context, comm client.Communicator) error {
+				if err := comm.DeleteRole(ctx, id); err != nil {
+					return errors.Wrapf(err, "problem deleting role '%s'", id)
+				}
+				grip.Infof("deleted role '%s'", id)
+				return nil
+			})
+		},
+	}
+}
+
+func adminRolesGrant() cli.Command {
+	return cli.Command{
+		Name:   "grant",
+		Usage:  "give roles to a user",
+		Flags:  userRolesFlags(),
+		Before: mergeBeforeFuncs(requireStringFlag(userRolesUserFlagName), requireStringSliceFlag(userRolesRoleFlagName)),
+		Action: updateUserRoles(true),
+	}
+}
+
+func adminRolesRevoke() cli.Command {
+	return cli.Command{
+		Name:   "revoke",
+		Usage:  "remove roles from a user",
+		Flags:  userRolesFlags(),
+		Before: mergeBeforeFuncs(requireStringFlag(userRolesUserFlagName), requireStringSliceFlag(userRolesRoleFlagName)),
+		Action: updateUserRoles(false),
+	}
+}
+
+const (
+	userRolesUserFlagName = "user"
+	userRolesRoleFlagName = "role"
+)
+
+func userRolesFlags() []cli.Flag {
+	return []cli.Flag{
+		cli.StringFlag{
+			Name:  joinFlagNames(userRolesUserFlagName, "u"),
+			Usage: "id of the user",
+		},
+		cli.StringSliceFlag{
+			Name:  joinFlagNames(userRolesRoleFlagName, "r"),
+			Usage: "id of a role (may be specified multiple times)",
+		},
+	}
+}
+
+func updateUserRoles(grant bool) cli.ActionFunc {
+	return func(c *cli.Context) error {
+		userID := c.String(userRolesUserFlagName)
+		var add, remove []string
+		if grant {
+			add = c.StringSlice(userRolesRoleFlagName)
+		} else {
+			remove = c.StringSlice(userRolesRoleFlagName)
+		}
+
+		return withRestCommunicator(c, func(ctx context.Context, comm client.Communicator) error {
+			roles, err := comm.UpdateUserRoles(ctx, userID, add, remove)
+			if err != nil {
+				return errors.Wrapf(err, "problem updating roles of user '%s'", userID)
+			}
+			grip.Infof("%s: %s", userID, strings.Join(roles, ", "))
+			return nil
+		})
+	}
+}
+
+func withRestCommunicator(c *cli.Context, op func(context.Context, client.Communicator) error) error {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	conf, err := NewClientSettings(c.GlobalString(confFlagName))
+	if err != nil {
+		return errors.Wrap(err, "problem loading configuration")
+	}
+	comm := conf.GetRestCommunicator(ctx)
+	defer comm.Close()
+
+	return op(ctx, comm)
+}
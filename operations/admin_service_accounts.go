@@ -0,0 +1,173 @@
+package operations
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"github.com/evergreen-ci/evergreen/rest/client"
+	"github.com/evergreen-ci/evergreen/rest/model"
+	"github.com/mongodb/grip"
+	"github.com/pkg/errors"
+	"github.com/urfave/cli"
+)
+
+const serviceAccountIDFlagName = "id"
+
+func adminServiceAccounts() cli.Command {
+	return cli.Command{
+		Name:    "service-accounts",
+		Aliases: []string{"service-account"},
+		Usage:   "manage the service accounts that automation authenticates as",
+		Before:  setPlainLogger,
+		Subcommands: []cli.Command{
+			adminServiceAccountsList(),
+			adminServiceAccountsCreate(),
+			adminServiceAccountsDelete(),
+			adminServiceAccountsIssueToken(),
+			adminServiceAccountsRevokeToken(),
+		},
+	}
+}
+
+func serviceAccountIDFlag() cli.Flag {
+	return cli.StringFlag{
+		Name:  joinFlagNames(serviceAccountIDFlagName, "i"),
+		Usage: "id of the service account, which must start with 'svc-'",
+	}
+}
+
+func adminServiceAccountsList() cli.Command {
+	return cli.Command{
+		Name:  "list",
+		Usage: "list all service accounts and their tokens",
+		Action: func(c *cli.Context) error {
+			return withRestCommunicator(c, func(ctx context.Context, comm client.Communicator) error {
+				accounts, err := comm.GetServiceAccounts(ctx)
+				if err != nil {
+					return errors.Wrap(err, "problem getting service accounts")
+				}
+				out, err := json.MarshalIndent(accounts, "", "  ")
+				if err != nil {
+					return errors.Wrap(err, "problem marshalling service accounts")
+				}
+				grip.Info(string(out))
+				return nil
+			})
+		},
+	}
+}
+
+func adminServiceAccountsCreate() cli.Command {
+	const (
+		nameFlagName = "name"
+		roleFlagName = "role"
+	)
+
+	return cli.Command{
+		Name:  "create",
+		Usage: "create a service account",
+		Flags: []cli.Flag{
+			serviceAccountIDFlag(),
+			cli.StringFlag{
+				Name:  joinFlagNames(nameFlagName, "n"),
+				Usage: "display name of the service account",
+			},
+			cli.StringSliceFlag{
+				Name:  joinFlagNames(roleFlagName, "r"),
+				Usage: "id of a role to give the service account (may be specified multiple times)",
+			},
+		},
+		Before: requireStringFlag(serviceAccountIDFlagName),
+		Action: func(c *cli.Context) error {
+			account := &model.APIServiceAccount{
+				ID:          model.ToAPIString(c.String(serviceAccountIDFlagName)),
+				DisplayName: model.ToAPIString(c.String(nameFlagName)),
+				Roles:       []model.APIString{},
+			}
+			for _, r := range c.StringSlice(roleFlagName) {
+				account.Roles = append(account.Roles, model.ToAPIString(r))
+			}
+
+			return withRestCommunicator(c, func(ctx context.Context, comm client.Communicator) error {
+				created, err := comm.CreateServiceAccount(ctx, account)
+				if err != nil {
+					return errors.Wrap(err, "problem creating service account")
+				}
+				grip.Infof("created service account '%s'", model.FromAPIString(created.ID))
+				return nil
+			})
+		},
+	}
+}
+
+func adminServiceAccountsDelete() cli.Command {
+	return cli.Command{
+		Name:   "delete",
+		Usage:  "delete a service account and revoke its tokens",
+		Flags:  []cli.Flag{serviceAccountIDFlag()},
+		Before: requireStringFlag(serviceAccountIDFlagName),
+		Action: func(c *cli.Context) error {
+			id := c.String(serviceAccountIDFlagName)
+
+			return withRestCommunicator(c, func(ctx context.Context, comm client.Communicator) error {
+				if err := comm.DeleteServiceAccount(ctx, id); err != nil {
+					return errors.Wrapf(err, "problem deleting service account '%s'", id)
+				}
+				grip.Infof("deleted service account '%s'", id)
+				return nil
+			})
+		},
+	}
+}
+
+func adminServiceAccountsIssueToken() cli.Command {
+	return cli.Command{
+		Name:   "issue-token",
+		Usage:  "create an API token for a service account, which is only printed once",
+		Flags:  tokenFlags(serviceAccountIDFlag()),
+		Before: mergeBeforeFuncs(requireStringFlag(serviceAccountIDFlagName), requireTokenFlags()),
+		Action: func(c *cli.Context) error {
+			id := c.String(serviceAccountIDFlagName)
+			name := c.String(tokenNameFlagName)
+			scopes := c.StringSlice(tokenScopeFlagName)
+			expiresAt := time.Now().Add(c.Duration(tokenExpiresFlagName))
+
+			return withRestCommunicator(c, func(ctx context.Context, comm client.Communicator) error {
+				token, err := comm.CreateServiceAccountToken(ctx, id, name, scopes, expiresAt)
+				if err != nil {
+					return errors.Wrapf(err, "problem creating token '%s' for service account '%s'", name, id)
+				}
+				printCreatedToken(token)
+				return nil
+			})
+		},
+	}
+}
+
+func adminServiceAccountsRevokeToken() cli.Command {
+	return cli.Command{
+		Name:  "revoke-token",
+		Usage: "revoke one of a service account's API tokens",
+		Flags: []cli.Flag{
+			serviceAccountIDFlag(),
+			cli.StringFlag{
+				Name:  joinFlagNames(tokenNameFlagName, "n"),
+				Usage: "name of the token",
+			},
+		},
+		Before: mergeBeforeFuncs(requireStringFlag(serviceAccountIDFlagName), requireStringFlag(tokenNameFlagName)),
+		Action: func(c *cli.Context) error {
+			id := c.String(serviceAccountIDFlagName)
+			name := c.String(tokenNameFlagName)
+
+			return withRestCommunicator(c, func(ctx context.Context, comm client.Communicator) error {
+				if err := comm.DeleteServiceAccountToken(ctx, id, name); err != nil {
+					return errors.Wrapf(err, "problem revoking token '%s' of service account '%s'", name, id)
+				}
+				grip.Infof("revoked token '%s' of service account '%s'", name, id)
+				return nil
+			})
+		},
+	}
+}
@@ -109,6 +109,15 @@ func requireStringFlag(name string) cli.BeforeFunc {
 	}
 }
 
+func requireStringSliceFlag(name string) cli.BeforeFunc {
+	return func(c *cli.Context) error {
+		if len(c.StringSlice(name)) == 0 {
+			return errors.Errorf("flag '--%s' was not specified", name)
+		}
+		return nil
+	}
+}
+
 func requireStringSliceValueChoices(name string, options []string) cli.BeforeFunc {
 	return func(c *cli.Context) error {
 		catcher := grip.NewBasicCatcher()
@@ -179,6 +188,22 @@ func requireOnlyOneBool(flags ...string) cli.BeforeFunc {
 	}
 }
 
+func requireOnlyOneStringFlag(flags ...string) cli.BeforeFunc {
+	return func(c *cli.Context) error {
+		count := 0
+		for idx := range flags {
+			if c.String(flags[idx]) != "" {
+				count++
+			}
+		}
+
+		if count != 1 {
+			return errors.Errorf("must specify one and only one of: --%s", strings.Join(flags, ", --"))
+		}
+		return nil
+	}
+}
+
 func requireAtLeastOneBool(flags ...string) cli.BeforeFunc {
 	return func(c *cli.Context) error {
 		for idx := range flags {
@@ -41,6 +41,10 @@ type APIError struct {
 }
 
 func (ae APIError) Error() string {
+	// conflicts are reported by the server in full, so they're shown as is
+	if ae.code == http.StatusConflict {
+		return strings.TrimSpace(ae.body)
+	}
 	return fmt.Sprintf("Unexpected reply from server (%v): %v", ae.status, ae.body)
 }
 
@@ -366,6 +370,8 @@ func (ac *legacyClient) PutPatch(incomingPatch patchSubmission) (*patch.Patch, e
 		Tasks       []string `json:"tasks"`
 		Finalize    bool     `json:"finalize"`
 		Alias       string   `json:"alias"`
+		Ref         string   `json:"ref,omitempty"`
+		PRNumber    int      `json:"pr_number,omitempty"`
 	}{
 		incomingPatch.description,
 		incomingPatch.projectId,
@@ -375,6 +381,8 @@ func (ac *legacyClient) PutPatch(incomingPatch patchSubmission) (*patch.Patch, e
 		incomingPatch.tasks,
 		incomingPatch.finalize,
 		incomingPatch.alias,
+		incomingPatch.ref,
+		incomingPatch.prNumber,
 	}
 
 	rPipe, wPipe := io.Pipe()
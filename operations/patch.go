@@ -13,6 +13,8 @@ const (
 	patchFinalizeFlagName    = "finalize"
 	patchVerboseFlagName     = "verbose"
 	patchAliasFlagName       = "alias"
+	patchRefFlagName         = "ref"
+	patchPRFlagName          = "pr"
 )
 
 func getPatchFlags(flags ...cli.Flag) []cli.Flag {
@@ -38,10 +40,23 @@ func getPatchFlags(flags ...cli.Flag) []cli.Flag {
 func Patch() cli.Command {
 	return cli.Command{
 		Name:    "patch",
-		Before:  setPlainLogger,
 		Aliases: []string{"create-patch", "submit-patch"},
 		Usage:   "submit a new patch to evergreen",
-		Flags:   getPatchFlags(),
+		Flags: getPatchFlags(
+			cli.StringFlag{
+				Name:  patchRefFlagName,
+				Usage: "patch the changes of a pushed branch, tag, or commit, instead of the local working tree",
+			},
+			cli.IntFlag{
+				Name:  patchPRFlagName,
+				Usage: "patch the changes of a GitHub pull request, instead of the local working tree",
+			}),
+		Before: mergeBeforeFuncs(setPlainLogger, func(c *cli.Context) error {
+			if c.String(patchRefFlagName) != "" && c.Int(patchPRFlagName) != 0 {
+				return errors.Errorf("can't specify both --%s and --%s", patchRefFlagName, patchPRFlagName)
+			}
+			return nil
+		}),
 		Action: func(c *cli.Context) error {
 			confPath := c.Parent().String(confFlagName)
 			args := c.Args()
@@ -55,6 +70,8 @@ func Patch() cli.Command {
 				ShowSummary: c.Bool(patchVerboseFlagName),
 				Large:       c.Bool(largeFlagName),
 				Alias:       c.String(patchAliasFlagName),
+				Ref:         c.String(patchRefFlagName),
+				PRNumber:    c.Int(patchPRFlagName),
 			}
 
 			ctx, cancel := context.WithCancel(context.Background())
@@ -77,6 +94,15 @@ func Patch() cli.Command {
 				return err
 			}
 
+			// the server builds patches of pushed refs, so they don't
+			// need a checkout
+			if params.Ref != "" || params.PRNumber != 0 {
+				if len(args) > 0 {
+					return errors.New("can't pass arguments to git diff when patching a ref or pull request")
+				}
+				return params.createPatch(ac, conf, &localDiff{})
+			}
+
 			diffData, err := loadGitData(ref.Branch, args...)
 			if err != nil {
 				return err
@@ -0,0 +1,65 @@
+package operations
+
+import (
+	"context"
+	"fmt"
+
+	restmodel "github.com/evergreen-ci/evergreen/rest/model"
+	"github.com/pkg/errors"
+	"github.com/urfave/cli"
+)
+
+func PatchRebase() cli.Command {
+	const baseFlagName = "base"
+
+	return cli.Command{
+		Name:  "rebase-patch",
+		Usage: "create a copy of an existing patch based on the latest mainline revision of its project",
+		Flags: addPatchIDFlag(
+			cli.StringFlag{
+				Name:  joinFlagNames(baseFlagName, "b"),
+				Usage: "the revision to base the new patch on, instead of the latest mainline revision",
+			},
+			cli.BoolFlag{
+				Name:  joinFlagNames(patchFinalizeFlagName, "f"),
+				Usage: "schedule tasks immediately",
+			}),
+		Before: requirePatchIDFlag,
+		Action: func(c *cli.Context) error {
+			confPath := c.Parent().String(confFlagName)
+			patchID := c.String(patchIDFlagName)
+
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+
+			conf, err := NewClientSettings(confPath)
+			if err != nil {
+				return errors.Wrap(err, "problem loading configuration")
+			}
+
+			client := conf.GetRestCommunicator(ctx)
+			defer client.Close()
+
+			// the server checks that the patch's changes still apply to
+			// the new revisions, and reports the conflicts if they don't
+			p, err := client.RebasePatch(ctx, patchID, restmodel.PatchRebaseRequest{
+				Base:     c.String(baseFlagName),
+				Finalize: c.Bool(patchFinalizeFlagName),
+			})
+			if err != nil {
+				return err
+			}
+
+			id := restmodel.FromAPIString(p.Id)
+			link := conf.UIServerHost + "/patch/" + id
+			if p.Activated {
+				link = conf.UIServerHost + "/version/" + id
+			}
+			fmt.Println("Patch rebased.")
+			fmt.Printf("\t     ID : %s\n", id)
+			fmt.Printf("\t   Base : %s\n", restmodel.FromAPIString(p.Githash))
+			fmt.Printf("\t  Build : %s\n", link)
+			return nil
+		},
+	}
+}
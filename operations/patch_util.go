@@ -25,9 +25,13 @@ var patchDisplayTemplate = template.Must(template.New("patch").Parse(`
 	     ID : {{.Patch.Id.Hex}}
 	Created : {{.Patch.CreateTime}}
     Description : {{if .Patch.Description}}{{.Patch.Description}}{{else}}<none>{{end}}
-	  Build : {{.Link}}
+{{if .Patch.SourceRef}}     Source Ref : {{.Patch.SourceRef}}
+{{end}}	  Build : {{.Link}}
       Finalized : {{if .Patch.Activated}}Yes{{else}}No{{end}}
-{{if .ShowSummary}}
+{{if .Patch.SkippedTasks}}
+  Skipped Tasks :
+{{range .Patch.SkippedTasks}}	{{.Variant}}: {{.Task}} ({{.Reason}})
+{{end}}{{end}}{{if .ShowSummary}}
 	Summary :
 {{range .Patch.Patches}}{{if not (eq .ModuleName "") }}Module:{{.ModuleName}}{{end}}
 	Base Commit : {{.Githash}}
@@ -54,6 +58,8 @@ type patchParams struct {
 	Finalize    bool
 	Large       bool
 	ShowSummary bool
+	Ref         string
+	PRNumber    int
 }
 
 type patchSubmission struct {
@@ -65,13 +71,16 @@ type patchSubmission struct {
 	variants    string
 	tasks       []string
 	finalize    bool
+	ref         string
+	prNumber    int
 }
 
 func (p *patchParams) createPatch(ac *legacyClient, conf *ClientSettings, diffData *localDiff) error {
 	if err := validatePatchSize(diffData, p.Large); err != nil {
 		return err
 	}
-	if !p.SkipConfirm && len(diffData.fullPatch) == 0 {
+	fromRef := p.Ref != "" || p.PRNumber != 0
+	if !p.SkipConfirm && len(diffData.fullPatch) == 0 && !fromRef {
 		if !confirm("Patch submission is empty. Continue?(y/n)", true) {
 			return nil
 		}
@@ -96,6 +105,8 @@ func (p *patchParams) createPatch(ac *legacyClient, conf *ClientSettings, diffDa
 		tasks:       p.Tasks,
 		finalize:    p.Finalize,
 		alias:       p.Alias,
+		ref:         p.Ref,
+		prNumber:    p.PRNumber,
 	}
 
 	newPatch, err := ac.PutPatch(patchSub)
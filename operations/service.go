@@ -85,6 +85,7 @@ func startSystemCronJobs(ctx context.Context, env evergreen.Environment) {
 		units.PopulateLastContainerFinishTimeJobs(),
 		units.PopulateParentDecommissionJobs(),
 		units.PopulatePeriodicNotificationJobs(1),
+		units.PopulateScheduledVersionJobs(),
 		units.PopulateContainerStateJobs(env)))
 
 	amboy.IntervalQueueOperation(ctx, env.RemoteQueue(), 15*time.Second, time.Now(), opts, amboy.GroupQueueOperationFactory(
@@ -0,0 +1,150 @@
+package operations
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/evergreen-ci/evergreen/model/user"
+	"github.com/evergreen-ci/evergreen/rest/client"
+	"github.com/evergreen-ci/evergreen/rest/model"
+	"github.com/mongodb/grip"
+	"github.com/pkg/errors"
+	"github.com/urfave/cli"
+)
+
+func Tokens() cli.Command {
+	return cli.Command{
+		Name:    "tokens",
+		Aliases: []string{"token"},
+		Usage:   "manage your scoped, expiring API tokens",
+		Before:  mergeBeforeFuncs(setPlainLogger, requireClientConfig),
+		Subcommands: []cli.Command{
+			tokensCreate(),
+			tokensList(),
+			tokensDelete(),
+		},
+	}
+}
+
+const (
+	tokenNameFlagName    = "name"
+	tokenScopeFlagName   = "scope"
+	tokenExpiresFlagName = "expires"
+)
+
+func tokenFlags(flags ...cli.Flag) []cli.Flag {
+	return append(flags,
+		cli.StringFlag{
+			Name:  joinFlagNames(tokenNameFlagName, "n"),
+			Usage: "name of the token",
+		},
+		cli.StringSliceFlag{
+			Name:  joinFlagNames(tokenScopeFlagName, "s"),
+			Usage: "scope of the token (may be specified multiple times): " + strings.Join(user.ValidTokenScopes, ", "),
+		},
+		cli.DurationFlag{
+			Name:  tokenExpiresFlagName,
+			Usage: "how long the token is valid for",
+			Value: 30 * 24 * time.Hour,
+		},
+	)
+}
+
+func requireTokenFlags() cli.BeforeFunc {
+	return mergeBeforeFuncs(
+		requireStringFlag(tokenNameFlagName),
+		requireStringSliceFlag(tokenScopeFlagName),
+		requireStringSliceValueChoices(tokenScopeFlagName, user.ValidTokenScopes),
+	)
+}
+
+func printCreatedToken(token *model.APIToken) {
+	grip.Infof("Created token '%s', which expires at %s.", model.FromAPIString(token.Name),
+		time.Time(token.ExpiresAt).Local().Format(time.RFC1123))
+	grip.Info("Use it in place of an API key. It will not be shown again:")
+	grip.Info(model.FromAPIString(token.Token))
+}
+
+func tokensCreate() cli.Command {
+	return cli.Command{
+		Name:   "create",
+		Usage:  "create an API token, which is only printed once",
+		Flags:  tokenFlags(),
+		Before: requireTokenFlags(),
+		Action: func(c *cli.Context) error {
+			name := c.String(tokenNameFlagName)
+			scopes := c.StringSlice(tokenScopeFlagName)
+			expiresAt := time.Now().Add(c.Duration(tokenExpiresFlagName))
+
+			return withRestCommunicator(c, func(ctx context.Context, comm client.Communicator) error {
+				token, err := comm.CreateAPIToken(ctx, name, scopes, expiresAt)
+				if err != nil {
+					return errors.Wrapf(err, "problem creating token '%s'", name)
+				}
+				printCreatedToken(token)
+				return nil
+			})
+		},
+	}
+}
+
+func tokensList() cli.Command {
+	return cli.Command{
+		Name:  "list",
+		Usage: "list your API tokens",
+		Action: func(c *cli.Context) error {
+			return withRestCommunicator(c, func(ctx context.Context, comm client.Communicator) error {
+				tokens, err := comm.GetAPITokens(ctx)
+				if err != nil {
+					return errors.Wrap(err, "problem fetching tokens")
+				}
+
+				if len(tokens) == 0 {
+					grip.Info("No tokens found")
+					return nil
+				}
+				for _, token := range tokens {
+					scopes := []string{}
+					for _, scope := range token.Scopes {
+						scopes = append(scopes, model.FromAPIString(scope))
+					}
+					expiresAt := time.Time(token.ExpiresAt)
+					status := "expires"
+					if !time.Now().Before(expiresAt) {
+						status = "expired"
+					}
+					grip.Infof("Name: '%s', Scopes: %s, %s %s", model.FromAPIString(token.Name),
+						strings.Join(scopes, ", "), status, expiresAt.Local().Format(time.RFC1123))
+				}
+				return nil
+			})
+		},
+	}
+}
+
+func tokensDelete() cli.Command {
+	return cli.Command{
+		Name:      "delete",
+		Aliases:   []string{"revoke"},
+		Usage:     "revoke an API token",
+		ArgsUsage: "<name>",
+		Before: func(c *cli.Context) error {
+			if c.NArg() != 1 || c.Args().Get(0) == "" {
+				return errors.New("must specify the name of one token to delete")
+			}
+			return nil
+		},
+		Action: func(c *cli.Context) error {
+			name := c.Args().Get(0)
+
+			return withRestCommunicator(c, func(ctx context.Context, comm client.Communicator) error {
+				if err := comm.DeleteAPIToken(ctx, name); err != nil {
+					return errors.Wrapf(err, "problem deleting token '%s'", name)
+				}
+				grip.Infof("Successfully deleted token: '%s'", name)
+				return nil
+			})
+		},
+	}
+}
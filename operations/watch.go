@@ -0,0 +1,823 @@
+package operations
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/evergreen-ci/evergreen"
+	"github.com/evergreen-ci/evergreen/apimodels"
+	"github.com/evergreen-ci/evergreen/model"
+	"github.com/evergreen-ci/evergreen/rest/client"
+	restmodel "github.com/evergreen-ci/evergreen/rest/model"
+	"github.com/mongodb/grip"
+	"github.com/pkg/errors"
+	"github.com/urfave/cli"
+	"golang.org/x/crypto/ssh/terminal"
+)
+
+const (
+	watchVersionFlagName  = "version"
+	watchIntervalFlagName = "interval"
+
+	// The exit codes of the watch command tell scripts how the patch or
+	// version turned out.
+	watchExitSucceeded  = 0
+	watchExitFailed     = 1
+	watchExitUnfinished = 2
+
+	// watchLogLines is the most lines of the selected task's logs shown.
+	watchLogLines = 20
+
+	watchKeyHelp = "←/→ select task  ↑/↓ select variant  f next failure  t tail logs  r restart  a abort  q quit"
+)
+
+const (
+	ansiReset       = "\x1b[0m"
+	ansiBold        = "\x1b[1m"
+	ansiReverse     = "\x1b[7m"
+	ansiGray        = "\x1b[90m"
+	ansiClearScreen = "\x1b[H\x1b[2J"
+	ansiHideCursor  = "\x1b[?25l"
+	ansiShowCursor  = "\x1b[?25h"
+)
+
+func Watch() cli.Command {
+	return cli.Command{
+		Name:  "watch",
+		Usage: "watch the tasks of a patch or version until they finish",
+		Description: `In a terminal, shows a live grid of the tasks of each variant, colored by
+   status. Select a task with the arrow keys, press 't' to tail its logs, and
+   'r' or 'a' to restart or abort it. Otherwise, prints the status whenever
+   it changes. Stops once no tasks are running or scheduled, and exits with
+   0 if every task succeeded, 1 if any task failed, and 2 if it stopped
+   watching before the tasks finished.`,
+		Flags: addPatchIDFlag(
+			cli.StringFlag{
+				Name:  watchVersionFlagName,
+				Usage: "specify the ID of a version",
+			},
+			cli.DurationFlag{
+				Name:  watchIntervalFlagName,
+				Usage: "how often to check the status of the tasks",
+				Value: 15 * time.Second,
+			},
+		),
+		Before: mergeBeforeFuncs(setPlainLogger, requireClientConfig,
+			requireOnlyOneStringFlag(patchIDFlagName, watchVersionFlagName)),
+		Action: func(c *cli.Context) error {
+			w := &versionWatcher{
+				patchID:   c.String(patchIDFlagName),
+				versionID: c.String(watchVersionFlagName),
+				interval:  c.Duration(watchIntervalFlagName),
+			}
+			if w.interval < time.Second {
+				return errors.Errorf("flag '--%s' must be at least one second", watchIntervalFlagName)
+			}
+
+			code := watchExitUnfinished
+			err := withRestCommunicator(c, func(ctx context.Context, comm client.Communicator) error {
+				var err error
+				w.comm = comm
+				if terminal.IsTerminal(int(os.Stdin.Fd())) && terminal.IsTerminal(int(os.Stdout.Fd())) {
+					code, err = w.watchInteractive(ctx)
+				} else {
+					code, err = w.watch(ctx)
+				}
+				return err
+			})
+			if err != nil {
+				return cli.NewExitError(err.Error(), watchExitUnfinished)
+			}
+			if code != watchExitSucceeded {
+				return cli.NewExitError("", code)
+			}
+			return nil
+		},
+	}
+}
+
+// versionWatcher polls the REST API for the tasks of a patch or version.
+type versionWatcher struct {
+	comm      client.Communicator
+	patchID   string
+	versionID string
+	interval  time.Duration
+	state     watchState
+}
+
+// watchState is what the watch command shows.
+type watchState struct {
+	title    string
+	note     string
+	variants []watchVariant
+
+	// selected is the id of the selected task, which stays selected when
+	// the tasks are refreshed.
+	selected string
+	showLogs bool
+	logs     []string
+
+	// pending is an action on the selected task that the user has to
+	// confirm before it's applied.
+	pending string
+	message string
+}
+
+type watchVariant struct {
+	name  string
+	tasks []restmodel.APITask
+}
+
+type watchSummary struct {
+	succeeded int
+	failed    int
+	running   int
+	scheduled int
+	inactive  int
+}
+
+// refresh fetches the current tasks of the patch or version, and the logs of
+// the selected task if they're shown.
+func (w *versionWatcher) refresh(ctx context.Context) error {
+	versionID := w.versionID
+	title := fmt.Sprintf("version '%s'", w.versionID)
+	if w.patchID != "" {
+		p, err := w.comm.GetPatchByID(ctx, w.patchID)
+		if err != nil {
+			return errors.WithStack(err)
+		}
+		title = fmt.Sprintf("patch %d '%s'", p.PatchNumber, restmodel.FromAPIString(p.Id))
+		if description := restmodel.FromAPIString(p.Description); description != "" {
+			title += " (" + description + ")"
+		}
+		versionID = restmodel.FromAPIString(p.Version)
+	}
+	w.state.title = title
+
+	if versionID == "" {
+		w.state.note = "The patch has not been finalized yet."
+		w.state.variants = nil
+		return nil
+	}
+	w.state.note = ""
+
+	v, err := w.comm.GetVersionByID(ctx, versionID)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+
+	variants := make([]watchVariant, 0, len(v.BuildVariants))
+	for _, bv := range v.BuildVariants {
+		tasks, err := w.comm.GetBuildTasks(ctx, restmodel.FromAPIString(bv.BuildId))
+		if err != nil {
+			return errors.WithStack(err)
+		}
+		sort.Slice(tasks, func(i, j int) bool {
+			return restmodel.FromAPIString(tasks[i].DisplayName) < restmodel.FromAPIString(tasks[j].DisplayName)
+		})
+		variants = append(variants, watchVariant{name: restmodel.FromAPIString(bv.BuildVariant), tasks: tasks})
+	}
+	sort.Slice(variants, func(i, j int) bool { return variants[i].name < variants[j].name })
+	w.state.variants = variants
+	w.state.ensureSelection()
+
+	if w.state.showLogs {
+		return errors.WithStack(w.refreshLogs(ctx))
+	}
+	return nil
+}
+
+func (w *versionWatcher) refreshLogs(ctx context.Context) error {
+	t, _, ok := w.state.selectedTask()
+	if !ok {
+		w.state.logs = nil
+		return nil
+	}
+
+	msgs, err := w.comm.GetRecentTaskLogs(ctx, restmodel.FromAPIString(t.Id), t.Execution, apimodels.TaskLogPrefix)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	w.state.logs = watchLogTail(msgs, watchLogLines)
+
+	return nil
+}
+
+// watchLogTail returns the last lines of the log messages, which are
+// ordered newest first, with the terminal control sequences that they may
+// contain removed.
+func watchLogTail(msgs []apimodels.LogMessage, count int) []string {
+	lines := []string{}
+	for i := 0; i < len(msgs) && len(lines) < count; i++ {
+		msgLines := strings.Split(strings.TrimRight(msgs[i].Message, "\n"), "\n")
+		for j := len(msgLines) - 1; j >= 0 && len(lines) < count; j-- {
+			lines = append(lines, sanitizeWatchText(msgLines[j]))
+		}
+	}
+	for i, j := 0, len(lines)-1; i < j; i, j = i+1, j-1 {
+		lines[i], lines[j] = lines[j], lines[i]
+	}
+
+	return lines
+}
+
+// watchEscapeSequence matches the escape sequences that terminals
+// interpret, such as to move the cursor or change the title.
+var watchEscapeSequence = regexp.MustCompile(`\x1b(\[[0-?]*[ -/]*[@-~]|\][^\x07\x1b]*(\x07|\x1b\\)?|.?)`)
+
+// sanitizeWatchText removes escape sequences and other control characters,
+// except tabs, from text so that it can't change the terminal.
+func sanitizeWatchText(text string) string {
+	return strings.Map(func(r rune) rune {
+		if r != '\t' && unicode.IsControl(r) {
+			return -1
+		}
+		return r
+	}, watchEscapeSequence.ReplaceAllString(text, ""))
+}
+
+// watch prints the status of the tasks whenever it changes until they
+// finish, for when the output isn't a terminal.
+func (w *versionWatcher) watch(ctx context.Context) (int, error) {
+	ticker := time.NewTicker(w.interval)
+	defer ticker.Stop()
+
+	loaded := false
+	last := ""
+	for {
+		if err := w.refresh(ctx); err != nil {
+			if !loaded {
+				return watchExitUnfinished, err
+			}
+			grip.Warning(errors.Wrap(err, "problem refreshing tasks, will try again"))
+		} else {
+			loaded = true
+			if line := w.state.statusLine(); line != last {
+				grip.Info(line)
+				last = line
+			}
+
+			if w.state.finished() {
+				w.state.printFailures()
+				return w.state.summary().exitCode(), nil
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return watchExitUnfinished, errors.WithStack(ctx.Err())
+		case <-ticker.C:
+		}
+	}
+}
+
+// watchInteractive shows the tasks in a live grid and handles key presses
+// until the tasks finish or the user quits.
+func (w *versionWatcher) watchInteractive(ctx context.Context) (int, error) {
+	if err := w.refresh(ctx); err != nil {
+		return watchExitUnfinished, err
+	}
+
+	fd := int(os.Stdin.Fd())
+	oldState, err := terminal.MakeRaw(fd)
+	if err != nil {
+		return watchExitUnfinished, errors.Wrap(err, "problem setting up the terminal")
+	}
+	fmt.Print(ansiHideCursor)
+
+	w.runInteractive(ctx)
+
+	fmt.Print(ansiShowCursor + ansiClearScreen)
+	grip.Warning(errors.Wrap(terminal.Restore(fd, oldState), "problem restoring the terminal"))
+	grip.Info(w.state.statusLine())
+
+	if !w.state.finished() {
+		return watchExitUnfinished, nil
+	}
+	w.state.printFailures()
+	return w.state.summary().exitCode(), nil
+}
+
+func (w *versionWatcher) runInteractive(ctx context.Context) {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	keys := make(chan string)
+	go readWatchKeys(ctx, os.Stdin, keys)
+
+	ticker := time.NewTicker(w.interval)
+	defer ticker.Stop()
+
+	for {
+		width, height, err := terminal.GetSize(int(os.Stdout.Fd()))
+		if err != nil {
+			width, height = 80, 24
+		}
+		fmt.Print(ansiClearScreen + strings.Join(w.state.render(width, height), "\r\n"))
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err = w.refresh(ctx); err != nil {
+				w.state.message = fmt.Sprintf("problem refreshing tasks: %s", err.Error())
+			} else if w.state.finished() {
+				return
+			}
+		case key, ok := <-keys:
+			if !ok {
+				keys = nil
+				continue
+			}
+			if w.handleKey(ctx, key) {
+				return
+			}
+		}
+	}
+}
+
+// handleKey updates the watcher for a key press, and returns whether the
+// user wants to quit.
+func (w *versionWatcher) handleKey(ctx context.Context, key string) bool {
+	s := &w.state
+	if s.pending != "" {
+		action := s.pending
+		s.pending = ""
+		if key != "y" {
+			s.message = fmt.Sprintf("Did not %s the task.", action)
+			return false
+		}
+		w.applyAction(ctx, action)
+		return false
+	}
+
+	s.message = ""
+	switch key {
+	case "q", "ctrl-c":
+		return true
+	case "right", "n":
+		s.moveTask(1)
+	case "left", "p":
+		s.moveTask(-1)
+	case "down", "j":
+		s.moveVariant(1)
+	case "up", "k":
+		s.moveVariant(-1)
+	case "f":
+		if !s.nextFailure() {
+			s.message = "No tasks have failed."
+		}
+	case "t", "enter":
+		s.showLogs = !s.showLogs
+		s.logs = nil
+		if s.showLogs {
+			if err := w.refreshLogs(ctx); err != nil {
+				s.message = fmt.Sprintf("problem getting task logs: %s", err.Error())
+			}
+		}
+		return false
+	case "r", "a":
+		t, variant, ok := s.selectedTask()
+		if !ok {
+			return false
+		}
+		s.pending = model.BulkTaskRestart
+		if key == "a" {
+			s.pending = model.BulkTaskAbort
+		}
+		s.message = fmt.Sprintf("%s '%s' on '%s'? (y/n)", strings.Title(s.pending), restmodel.FromAPIString(t.DisplayName), variant)
+		return false
+	}
+
+	// a different task may be selected, so the logs shown are out of date
+	if s.showLogs {
+		if err := w.refreshLogs(ctx); err != nil {
+			s.message = fmt.Sprintf("problem getting task logs: %s", err.Error())
+		}
+	}
+
+	return false
+}
+
+// applyAction restarts or aborts the selected task.
+func (w *versionWatcher) applyAction(ctx context.Context, action string) {
+	t, _, ok := w.state.selectedTask()
+	if !ok {
+		return
+	}
+
+	_, err := w.comm.StartBulkTaskOperation(ctx, restmodel.BulkTaskRequest{
+		TaskIDs: []string{restmodel.FromAPIString(t.Id)},
+		Action:  action,
+	})
+	if err != nil {
+		w.state.message = fmt.Sprintf("problem trying to %s the task: %s", action, err.Error())
+		return
+	}
+	w.state.message = fmt.Sprintf("Started to %s '%s'.", action, restmodel.FromAPIString(t.DisplayName))
+
+	if err = w.refresh(ctx); err != nil {
+		w.state.message = fmt.Sprintf("problem refreshing tasks: %s", err.Error())
+	}
+}
+
+// readWatchKeys sends the keys pressed until the reader is closed or the
+// context is done.
+func readWatchKeys(ctx context.Context, r io.Reader, keys chan<- string) {
+	defer close(keys)
+
+	buf := make([]byte, 32)
+	for {
+		n, err := r.Read(buf)
+		for _, key := range parseWatchKeys(buf[:n]) {
+			select {
+			case keys <- key:
+			case <-ctx.Done():
+				return
+			}
+		}
+		if err != nil {
+			return
+		}
+	}
+}
+
+// parseWatchKeys returns the names of the keys in input read from a
+// terminal in raw mode.
+func parseWatchKeys(in []byte) []string {
+	keys := []string{}
+	for i := 0; i < len(in); i++ {
+		switch {
+		case in[i] == 0x1b && i+2 < len(in) && in[i+1] == '[':
+			switch in[i+2] {
+			case 'A':
+				keys = append(keys, "up")
+			case 'B':
+				keys = append(keys, "down")
+			case 'C':
+				keys = append(keys, "right")
+			case 'D':
+				keys = append(keys, "left")
+			}
+			i += 2
+		case in[i] == 0x03:
+			keys = append(keys, "ctrl-c")
+		case in[i] == '\r' || in[i] == '\n':
+			keys = append(keys, "enter")
+		default:
+			keys = append(keys, string(in[i]))
+		}
+	}
+
+	return keys
+}
+
+// watchTaskCategory returns the status that the task is counted as: success,
+// failed, started, undispatched, or inactive.
+func watchTaskCategory(t restmodel.APITask) string {
+	switch status := restmodel.FromAPIString(t.Status); status {
+	case evergreen.TaskSucceeded:
+		return evergreen.TaskSucceeded
+	case evergreen.TaskFailed, evergreen.TaskSetupFailed, evergreen.TaskSystemFailed, evergreen.TaskTimedOut:
+		return evergreen.TaskFailed
+	case evergreen.TaskStarted, evergreen.TaskDispatched:
+		return evergreen.TaskStarted
+	case evergreen.TaskUndispatched:
+		if t.Activated {
+			return evergreen.TaskUndispatched
+		}
+	}
+
+	return evergreen.TaskInactive
+}
+
+// watchTaskStatus returns a description of the task's status.
+func watchTaskStatus(t restmodel.APITask) string {
+	switch watchTaskCategory(t) {
+	case evergreen.TaskFailed:
+		switch {
+		case t.Details.TimedOut:
+			return "timed out"
+		case restmodel.FromAPIString(t.Details.Type) == evergreen.CommandTypeSystem:
+			return "system failed"
+		case restmodel.FromAPIString(t.Details.Type) == evergreen.CommandTypeSetup:
+			return "setup failed"
+		}
+		return "failed"
+	case evergreen.TaskSucceeded:
+		return "succeeded"
+	case evergreen.TaskStarted:
+		return "running"
+	case evergreen.TaskUndispatched:
+		return "scheduled"
+	}
+
+	return "inactive"
+}
+
+// watchTaskColor returns the color code of the task's status.
+func watchTaskColor(t restmodel.APITask) string {
+	switch watchTaskStatus(t) {
+	case "succeeded":
+		return "\x1b[32m"
+	case "failed", "timed out":
+		return "\x1b[31m"
+	case "system failed", "setup failed":
+		return "\x1b[35m"
+	case "running":
+		return "\x1b[33m"
+	case "scheduled":
+		return "\x1b[36m"
+	}
+
+	return ansiGray
+}
+
+func (s *watchState) summary() watchSummary {
+	summary := watchSummary{}
+	for _, v := range s.variants {
+		for _, t := range v.tasks {
+			switch watchTaskCategory(t) {
+			case evergreen.TaskSucceeded:
+				summary.succeeded++
+			case evergreen.TaskFailed:
+				summary.failed++
+			case evergreen.TaskStarted:
+				summary.running++
+			case evergreen.TaskUndispatched:
+				summary.scheduled++
+			default:
+				summary.inactive++
+			}
+		}
+	}
+
+	return summary
+}
+
+// finished returns whether every task that's going to run has finished,
+// which includes when none of the tasks are active.
+func (s watchSummary) finished() bool {
+	return s.running == 0 && s.scheduled == 0
+}
+
+func (s watchSummary) exitCode() int {
+	if s.failed > 0 {
+		return watchExitFailed
+	}
+	return watchExitSucceeded
+}
+
+func (s watchSummary) String() string {
+	out := fmt.Sprintf("%d succeeded, %d failed, %d running, %d scheduled", s.succeeded, s.failed, s.running, s.scheduled)
+	if s.finished() {
+		out += " (finished)"
+	}
+	return out
+}
+
+// finished returns whether the tasks of the version have finished, which
+// they haven't while the patch isn't finalized.
+func (s *watchState) finished() bool {
+	return s.note == "" && s.summary().finished()
+}
+
+// printFailures logs each of the failed tasks.
+func (s *watchState) printFailures() {
+	for _, v := range s.variants {
+		for _, t := range v.tasks {
+			if watchTaskCategory(t) == evergreen.TaskFailed {
+				grip.Infof("%s: '%s' on '%s'", watchTaskStatus(t), restmodel.FromAPIString(t.DisplayName), v.name)
+			}
+		}
+	}
+}
+
+func (s *watchState) statusLine() string {
+	if s.note != "" {
+		return fmt.Sprintf("%s: %s", s.title, s.note)
+	}
+	return fmt.Sprintf("%s: %s", s.title, s.summary())
+}
+
+// selectedTask returns the selected task and the name of its variant.
+func (s *watchState) selectedTask() (restmodel.APITask, string, bool) {
+	vIdx, tIdx, ok := s.find(s.selected)
+	if !ok {
+		return restmodel.APITask{}, "", false
+	}
+	return s.variants[vIdx].tasks[tIdx], s.variants[vIdx].name, true
+}
+
+func (s *watchState) find(taskID string) (int, int, bool) {
+	for vIdx, v := range s.variants {
+		for tIdx, t := range v.tasks {
+			if restmodel.FromAPIString(t.Id) == taskID {
+				return vIdx, tIdx, true
+			}
+		}
+	}
+	return 0, 0, false
+}
+
+// taskIDs returns the ids of all the tasks in the order they're shown.
+func (s *watchState) taskIDs() []string {
+	ids := []string{}
+	for _, v := range s.variants {
+		for _, t := range v.tasks {
+			ids = append(ids, restmodel.FromAPIString(t.Id))
+		}
+	}
+	return ids
+}
+
+// ensureSelection selects the first failed task, or else the first task,
+// if the selected task is gone.
+func (s *watchState) ensureSelection() {
+	if _, _, ok := s.find(s.selected); ok {
+		return
+	}
+	s.selected = ""
+	if !s.nextFailure() {
+		if ids := s.taskIDs(); len(ids) > 0 {
+			s.selected = ids[0]
+		}
+	}
+}
+
+// moveTask selects the task delta places after the selected task, wrapping
+// around at the ends.
+func (s *watchState) moveTask(delta int) {
+	ids := s.taskIDs()
+	if len(ids) == 0 {
+		return
+	}
+	idx := 0
+	for i, id := range ids {
+		if id == s.selected {
+			idx = i
+			break
+		}
+	}
+	s.selected = ids[((idx+delta)%len(ids)+len(ids))%len(ids)]
+}
+
+// moveVariant selects the task in the same position of the next variant in
+// the direction of delta that has tasks, or its last task if it has fewer.
+func (s *watchState) moveVariant(delta int) {
+	vIdx, tIdx, ok := s.find(s.selected)
+	if !ok {
+		return
+	}
+	for next := vIdx + delta; next >= 0 && next < len(s.variants); next += delta {
+		tasks := s.variants[next].tasks
+		if len(tasks) == 0 {
+			continue
+		}
+		if tIdx >= len(tasks) {
+			tIdx = len(tasks) - 1
+		}
+		s.selected = restmodel.FromAPIString(tasks[tIdx].Id)
+		return
+	}
+}
+
+// nextFailure selects the next failed task after the selected one, and
+// returns whether there are any failed tasks.
+func (s *watchState) nextFailure() bool {
+	failed := map[string]bool{}
+	for _, v := range s.variants {
+		for _, t := range v.tasks {
+			if watchTaskCategory(t) == evergreen.TaskFailed {
+				failed[restmodel.FromAPIString(t.Id)] = true
+			}
+		}
+	}
+	if len(failed) == 0 {
+		return false
+	}
+
+	ids := s.taskIDs()
+	start := -1
+	for i, id := range ids {
+		if id == s.selected {
+			start = i
+			break
+		}
+	}
+	for i := 1; i <= len(ids); i++ {
+		id := ids[(start+i+len(ids))%len(ids)]
+		if failed[id] {
+			s.selected = id
+			return true
+		}
+	}
+	return false
+}
+
+// render returns the lines to show in a terminal of the given size. Each
+// variant's tasks are colored by status and wrap to fit the width.
+func (s *watchState) render(width, height int) []string {
+	if width < 20 {
+		width = 20
+	}
+
+	lines := []string{
+		ansiBold + truncateWatchText(fmt.Sprintf("Watching %s", s.statusLine()), width) + ansiReset,
+		"",
+	}
+
+	nameWidth := 0
+	for _, v := range s.variants {
+		if l := len([]rune(v.name)); l > nameWidth {
+			nameWidth = l
+		}
+	}
+	if nameWidth > width/3 {
+		nameWidth = width / 3
+	}
+	indent := strings.Repeat(" ", nameWidth+2)
+
+	for _, v := range s.variants {
+		name := truncateWatchText(v.name, nameWidth)
+		line := name + strings.Repeat(" ", nameWidth-len([]rune(name))+2)
+		used := len(indent)
+		for _, t := range v.tasks {
+			cell := truncateWatchText(restmodel.FromAPIString(t.DisplayName), width-len(indent))
+			cellWidth := len([]rune(cell))
+			if used > len(indent) {
+				if used+1+cellWidth > width {
+					lines = append(lines, line)
+					line = indent
+					used = len(indent)
+				} else {
+					line += " "
+					used++
+				}
+			}
+
+			style := watchTaskColor(t)
+			if restmodel.FromAPIString(t.Id) == s.selected {
+				style += ansiReverse
+			}
+			line += style + cell + ansiReset
+			used += cellWidth
+		}
+		lines = append(lines, line)
+	}
+
+	t, variant, selected := s.selectedTask()
+	if selected {
+		lines = append(lines, "", truncateWatchText(fmt.Sprintf("Selected: '%s' on '%s' (%s)",
+			restmodel.FromAPIString(t.DisplayName), variant, watchTaskStatus(t)), width))
+	}
+	if selected && s.showLogs {
+		lines = append(lines, "", ansiBold+truncateWatchText(fmt.Sprintf("Task logs of execution %d:", t.Execution), width)+ansiReset)
+
+		// leave room for the message and the help
+		count := watchLogLines
+		if available := height - len(lines) - 3; available < count {
+			count = available
+		}
+		if count < 3 {
+			count = 3
+		}
+		logs := s.logs
+		if len(logs) > count {
+			logs = logs[len(logs)-count:]
+		}
+		for _, line := range logs {
+			lines = append(lines, truncateWatchText(strings.Replace(line, "\t", "    ", -1), width))
+		}
+	}
+
+	lines = append(lines, "")
+	if s.note != "" {
+		lines = append(lines, truncateWatchText(s.note, width))
+	}
+	if s.message != "" {
+		lines = append(lines, truncateWatchText(s.message, width))
+	}
+	lines = append(lines, ansiGray+truncateWatchText(watchKeyHelp, width)+ansiReset)
+
+	return lines
+}
+
+// truncateWatchText shortens text that's wider than the width.
+func truncateWatchText(text string, width int) string {
+	runes := []rune(text)
+	if len(runes) <= width {
+		return text
+	}
+	if width <= 1 {
+		return string(runes[:width])
+	}
+	return string(runes[:width-1]) + "…"
+}
@@ -0,0 +1,179 @@
+package operations
+
+import (
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/evergreen-ci/evergreen"
+	"github.com/evergreen-ci/evergreen/apimodels"
+	"github.com/evergreen-ci/evergreen/rest/model"
+	"github.com/stretchr/testify/assert"
+)
+
+func watchTestTask(id, status string, activated bool) model.APITask {
+	return model.APITask{
+		Id:          model.ToAPIString(id),
+		DisplayName: model.ToAPIString(id),
+		Status:      model.ToAPIString(status),
+		Activated:   activated,
+	}
+}
+
+func watchTestState() *watchState {
+	return &watchState{
+		title: "version 'v'",
+		variants: []watchVariant{
+			{name: "linux", tasks: []model.APITask{
+				watchTestTask("compile", evergreen.TaskSucceeded, true),
+				watchTestTask("lint", evergreen.TaskFailed, true),
+				watchTestTask("test", evergreen.TaskStarted, true),
+			}},
+			{name: "macos", tasks: []model.APITask{
+				watchTestTask("mac-compile", evergreen.TaskUndispatched, true),
+			}},
+			{name: "windows", tasks: []model.APITask{
+				watchTestTask("win-compile", evergreen.TaskFailed, true),
+				watchTestTask("win-test", evergreen.TaskUndispatched, false),
+			}},
+		},
+	}
+}
+
+func TestParseWatchKeys(t *testing.T) {
+	assert := assert.New(t)
+
+	assert.Equal([]string{"up", "down", "right", "left"}, parseWatchKeys([]byte("\x1b[A\x1b[B\x1b[C\x1b[D")))
+	assert.Equal([]string{"q", "enter", "ctrl-c"}, parseWatchKeys([]byte("q\r\x03")))
+	assert.Equal([]string{"r", "y"}, parseWatchKeys([]byte("ry")))
+	assert.Empty(parseWatchKeys(nil))
+}
+
+func TestWatchSummary(t *testing.T) {
+	assert := assert.New(t)
+
+	s := watchTestState()
+	summary := s.summary()
+	assert.Equal(watchSummary{succeeded: 1, failed: 2, running: 1, scheduled: 1, inactive: 1}, summary)
+	assert.False(summary.finished())
+
+	s.variants[0].tasks[2].Status = model.ToAPIString(evergreen.TaskSucceeded)
+	s.variants[1].tasks[0].Status = model.ToAPIString(evergreen.TaskSucceeded)
+	summary = s.summary()
+	assert.True(summary.finished())
+	assert.Equal(watchExitFailed, summary.exitCode())
+
+	s.variants[0].tasks[1].Status = model.ToAPIString(evergreen.TaskSucceeded)
+	s.variants[2].tasks[0].Status = model.ToAPIString(evergreen.TaskSucceeded)
+	summary = s.summary()
+	assert.True(summary.finished())
+	assert.Equal(watchExitSucceeded, summary.exitCode())
+
+	// nothing is going to run if every task is inactive
+	assert.True(watchSummary{inactive: 3}.finished())
+
+	// the tasks of a patch that isn't finalized haven't finished
+	assert.True(s.finished())
+	s.note = "The patch has not been finalized yet."
+	s.variants = nil
+	assert.False(s.finished())
+}
+
+func TestWatchLogTail(t *testing.T) {
+	assert := assert.New(t)
+
+	msgs := []apimodels.LogMessage{
+		{Message: "fourth\nfifth\n"},
+		{Message: "\x1b[31mthird\x1b[0m"},
+		{Message: "second\x1b]0;title\x07\r"},
+		{Message: "first"},
+	}
+	assert.Equal([]string{"first", "second", "third", "fourth", "fifth"}, watchLogTail(msgs, 10))
+	assert.Equal([]string{"third", "fourth", "fifth"}, watchLogTail(msgs, 3))
+	assert.Empty(watchLogTail(nil, 3))
+
+	assert.Equal("a\tb", sanitizeWatchText("a\tb"))
+	assert.Equal("clear", sanitizeWatchText("\x1b[2J\x1b[Hclear\x1bc\x08"))
+}
+
+func TestWatchTaskStatus(t *testing.T) {
+	assert := assert.New(t)
+
+	task := watchTestTask("t", evergreen.TaskFailed, true)
+	assert.Equal("failed", watchTaskStatus(task))
+	task.Details.Type = model.ToAPIString(evergreen.CommandTypeSystem)
+	assert.Equal("system failed", watchTaskStatus(task))
+	task.Details.TimedOut = true
+	assert.Equal("timed out", watchTaskStatus(task))
+
+	assert.Equal("scheduled", watchTaskStatus(watchTestTask("t", evergreen.TaskUndispatched, true)))
+	assert.Equal("inactive", watchTaskStatus(watchTestTask("t", evergreen.TaskUndispatched, false)))
+	assert.Equal("running", watchTaskStatus(watchTestTask("t", evergreen.TaskDispatched, true)))
+}
+
+func TestWatchSelection(t *testing.T) {
+	assert := assert.New(t)
+
+	s := watchTestState()
+	s.ensureSelection()
+	assert.Equal("lint", s.selected, "the first failed task is selected")
+
+	assert.True(s.nextFailure())
+	assert.Equal("win-compile", s.selected)
+	assert.True(s.nextFailure())
+	assert.Equal("lint", s.selected)
+
+	s.moveTask(1)
+	assert.Equal("test", s.selected)
+	s.moveTask(-3)
+	assert.Equal("win-test", s.selected, "moving wraps around")
+
+	s.moveVariant(-1)
+	assert.Equal("mac-compile", s.selected)
+	s.moveVariant(-1)
+	assert.Equal("compile", s.selected)
+	s.moveVariant(-1)
+	assert.Equal("compile", s.selected, "the first variant stays selected")
+
+	t.Run("RemovedTaskIsReplaced", func(t *testing.T) {
+		s.selected = "gone"
+		s.ensureSelection()
+		assert.Equal("lint", s.selected)
+	})
+	t.Run("NoFailures", func(t *testing.T) {
+		s := &watchState{variants: []watchVariant{{name: "linux", tasks: []model.APITask{
+			watchTestTask("compile", evergreen.TaskSucceeded, true),
+		}}}}
+		assert.False(s.nextFailure())
+		s.ensureSelection()
+		assert.Equal("compile", s.selected)
+	})
+}
+
+func TestWatchRender(t *testing.T) {
+	assert := assert.New(t)
+	ansi := regexp.MustCompile("\x1b\\[[0-9;]*m")
+
+	s := watchTestState()
+	s.ensureSelection()
+	s.showLogs = true
+	s.logs = []string{"first", "second"}
+
+	const width = 40
+	lines := s.render(width, 40)
+	for _, line := range lines {
+		assert.True(len([]rune(ansi.ReplaceAllString(line, ""))) <= width, "'%s' is too wide", line)
+	}
+
+	text := ansi.ReplaceAllString(strings.Join(lines, "\n"), "")
+	assert.Contains(text, "linux    compile lint test")
+	assert.Contains(text, "windows  win-compile win-test")
+	assert.Contains(text, "Selected: 'lint' on 'linux' (failed)")
+	assert.Contains(text, "first\nsecond")
+	assert.Contains(strings.Join(lines, "\n"), ansiReverse+"lint")
+
+	// the variant names take up at most a third of the width
+	lines = s.render(20, 40)
+	text = ansi.ReplaceAllString(strings.Join(lines, "\n"), "")
+	assert.Contains(text, "linux   compile lint\n        test", "tasks wrap under the variant")
+}
@@ -37,6 +37,7 @@ mciModule.controller('AdminSettingsController', ['$scope', '$window', 'mciAdminR
 
       $scope.tempPlugins = resp.data.plugins ? jsyaml.safeDump(resp.data.plugins) : ""
       $scope.tempContainerPools = resp.data.container_pools.pools ? jsyaml.safeDump(resp.data.container_pools.pools) : ""
+      $scope.tempRateLimitRouteGroups = resp.data.rate_limit && resp.data.rate_limit.route_groups && resp.data.rate_limit.route_groups.length ? jsyaml.safeDump(resp.data.rate_limit.route_groups) : ""
 
       $scope.Settings = resp.data;
       $scope.Settings.jira_notifications = $scope.Settings.jira_notifications;
@@ -122,6 +123,15 @@ mciModule.controller('AdminSettingsController', ['$scope', '$window', 'mciAdminR
 
     $scope.Settings.container_pools.pools = parsedContainerPools;
 
+    try {
+      var parsedRouteGroups = jsyaml.safeLoad($scope.tempRateLimitRouteGroups);
+    } catch(e) {
+      notificationService.pushNotification("Error parsing rate limit route groups yaml: " + e, "errorHeader");
+      return;
+    }
+    $scope.Settings.rate_limit = $scope.Settings.rate_limit || {};
+    $scope.Settings.rate_limit.route_groups = parsedRouteGroups || [];
+
     if ($scope.tempPlugins === null || $scope.tempPlugins === undefined || $scope.tempPlugins == "") {
       $scope.Settings.plugins = {};
     }
@@ -140,11 +150,11 @@ mciModule.controller('AdminSettingsController', ['$scope', '$window', 'mciAdminR
     hostinit_disabled: "hostinit",
     monitor_disabled: "monitor",
     notifications_disabled: "notifications",
-    alerts_disabled: "alerts",
     taskrunner_disabled: "taskrunner",
     repotracker_disabled: "repotracker",
     scheduler_disabled: "scheduler",
     github_pr_testing_disabled: "github_pr_testing",
+    gitlab_mr_testing_disabled: "gitlab_mr_testing",
     repotracker_push_event_disabled: "repotracker_push_event",
     cli_updates_disabled: "cli_updates",
     background_stats_disabled: "background stats",
@@ -154,7 +164,8 @@ mciModule.controller('AdminSettingsController', ['$scope', '$window', 'mciAdminR
     slack_notifications_disabled: "slack_notifications",
     email_notifications_disabled: "email_notifications",
     webhook_notifications_disabled: "webhook_notifications",
-    github_status_api_disabled: "github_status_api"
+    github_status_api_disabled: "github_status_api",
+    gitlab_status_api_disabled: "gitlab_status_api"
   }
 
   timestamp = function(ts) {
@@ -1,6 +1,5 @@
 mciModule.controller('ProjectCtrl', function($scope, $window, $http, $location, $mdDialog) {
 
-  $scope.availableTriggers = $window.availableTriggers
   $scope.userId = $window.user.Id;
   $scope.isAdmin = $window.isSuperUser || $window.isAdmin;
   $scope.isSuperUser = $window.isSuperUser;
@@ -87,6 +86,39 @@ mciModule.controller('ProjectCtrl', function($scope, $window, $http, $location,
       label: "a previously passing test in a task fails",
       regex_selectors: taskRegexSelectors(),
     },
+    {
+      trigger: "test-started-failing",
+      resource_type: "TASK",
+      label: "a previously passing test starts failing (with suspected commits)",
+      regex_selectors: taskRegexSelectors(),
+    },
+    {
+      trigger: "bisect-culprit",
+      resource_type: "TASK",
+      label: "bisection finds the commit where a task started failing",
+      regex_selectors: taskRegexSelectors(),
+    },
+    {
+      trigger: "agent-deploy-failed",
+      resource_type: "HOST",
+      label: "a host running this project's tasks repeatedly fails to deploy the agent",
+      extraFields: [
+        {text: "Consecutive failures", key: "agent-deploy-failures", validator: validatePositiveInteger}
+      ]
+    },
+    {
+      trigger: "idle",
+      resource_type: "HOST",
+      label: "a host running this project's tasks is idle for some duration",
+      extraFields: [
+        {text: "Idle duration (seconds)", key: "host-idle-secs", validator: validateDuration}
+      ]
+    },
+    {
+      trigger: "terminated-externally",
+      resource_type: "HOST",
+      label: "a host running this project's tasks is terminated outside of Evergreen",
+    },
   ];
 
   // refreshTrackedProjects will populate the list of projects that should be displayed
@@ -111,48 +143,12 @@ mciModule.controller('ProjectCtrl', function($scope, $window, $http, $location,
     return !isNaN(Number(t)) && Number(t) >= 0
   }
 
-  // TODO: EVG-3408
-  $scope.isValidAlertDefinition = function(spec) {
-    if (!spec) { return false; }
-    if (spec.startsWith("JIRA:") && spec.split(":").length < 3) {
-        return false
-    }
-    if (spec.startsWith("SLACK:") && spec.split(":").length < 2) {
-        return false
-    }
-    return true
-  }
-
-  // TODO: EVG-3408
-  $scope.addAlert = function(obj, trigger){
-    if(!$scope.settingsFormData.alert_config) {
-      $scope.settingsFormData.alert_config = {}
-    }
-    if(!$scope.settingsFormData.alert_config[trigger.id]){
-      $scope.settingsFormData.alert_config[trigger.id] = []
-    }
-    $scope.settingsFormData.alert_config[trigger.id].push(NewAlert(obj.email))
-    obj.editing = false
-  }
-
-  // TODO: EVG-3408
-  $scope.getProjectAlertConfig = function(t){
-    if(!$scope.settingsFormData.alert_config || !$scope.settingsFormData.alert_config[t]){
-      return []
-    }
-    return $scope.settingsFormData.alert_config[t]
-  }
-
   $scope.findProject = function(identifier){
     return _.find($scope.trackedProjects, function(project){
       return project.identifier == identifier;
     })
   }
 
-  $scope.openAlertModal = function(){
-    var modal = $('#alert-modal').modal('show');
-  }
-
   $scope.openAdminModal = function(opt) {
     var modal = $('#admin-modal').modal('show');
     $scope.newProjectMessage = "";
@@ -180,6 +176,50 @@ mciModule.controller('ProjectCtrl', function($scope, $window, $http, $location,
     $scope.isDirty = true;
   }
 
+  // addCronSchedule adds a schedule to the settingsFormData's list of cron schedules
+  $scope.addCronSchedule = function(){
+    var variants = _.filter(_.map(($scope.cron_variants || "").split(","), function(v) {
+      return v.trim();
+    }), function(v) {
+      return v !== "";
+    });
+    $scope.settingsFormData.cron_schedules.push({cron: $scope.cron_expression, build_variants: variants});
+    $scope.cron_expression = "";
+    $scope.cron_variants = "";
+    $scope.isDirty = true;
+  }
+
+  // removeCronSchedule removes the cron schedule located at index
+  $scope.removeCronSchedule = function(index){
+    $scope.settingsFormData.cron_schedules.splice(index, 1);
+    $scope.isDirty = true;
+  }
+
+  // addDownstream adds a trigger to the settingsFormData's list of downstream triggers
+  $scope.addDownstream = function(){
+    var variants = _.filter(_.map(($scope.downstream_variants || "").split(","), function(v) {
+      return v.trim();
+    }), function(v) {
+      return v !== "";
+    });
+    $scope.settingsFormData.downstream.push({
+      project: $scope.downstream_project,
+      level: $scope.downstream_level || "version",
+      build_variant: $scope.downstream_level == "build" ? $scope.downstream_build_variant : "",
+      downstream_variants: variants,
+    });
+    $scope.downstream_project = "";
+    $scope.downstream_build_variant = "";
+    $scope.downstream_variants = "";
+    $scope.isDirty = true;
+  }
+
+  // removeDownstream removes the downstream trigger located at index
+  $scope.removeDownstream = function(index){
+    $scope.settingsFormData.downstream.splice(index, 1);
+    $scope.isDirty = true;
+  }
+
 
   $scope.addProject = function() {
     $scope.modalOpen = false;
@@ -281,12 +321,15 @@ mciModule.controller('ProjectCtrl', function($scope, $window, $http, $location,
           branch_name: $scope.projectRef.branch_name || "master",
           owner_name: $scope.projectRef.owner_name,
           repo_name: $scope.projectRef.repo_name,
+          repo_kind: $scope.projectRef.repo_kind || "github",
+          repo_url: $scope.projectRef.repo_url,
           enabled: $scope.projectRef.enabled,
           private: $scope.projectRef.private,
           patching_disabled: $scope.projectRef.patching_disabled,
-          alert_config: $scope.projectRef.alert_config || {},
           repotracker_error: $scope.projectRef.repotracker_error || {},
           admins : $scope.projectRef.admins || [],
+          cron_schedules: $scope.projectRef.cron_schedules || [],
+          downstream: $scope.projectRef.downstream || [],
           setup_github_hook: $scope.githubHookID != 0,
           tracks_push_events: data.ProjectRef.tracks_push_events || false,
           pr_testing_enabled: data.ProjectRef.pr_testing_enabled || false,
@@ -453,24 +496,6 @@ mciModule.controller('ProjectCtrl', function($scope, $window, $http, $location,
     }
   });
 
-  $scope.getAlertDisplay =function(alertObj){
-    if(alertObj.provider=='email'){
-      return "Send an e-mail to " + alertObj.settings.recipient
-    }
-    if (alertObj.provider=='jira'){
-      return "File a "+alertObj.settings.issue+" JIRA ticket in "+ alertObj.settings.project
-    }
-    if (alertObj.provider=='slack'){
-      return "Send a slack message to "+alertObj.settings.channel
-    }
-    return 'unknown'
-  }
-
-  $scope.removeAlert = function(triggerId, index){
-    $scope.settingsFormData.alert_config[triggerId].splice(index, 1)
-    $scope.isDirty = true;
-  }
-
   $scope.isValidMergeBaseRevision = function(revision){
     return revision && revision.length >= 40;
   }
@@ -576,25 +601,3 @@ mciModule.directive('adminNewProject', function() {
     '</div>'
   };
 });
-
-// TODO: EVG-3408
-mciModule.directive('adminNewAlert', function() {
-    return {
-      restrict: 'E',
-      templateUrl:'/static/partials/alert_modal_form.html',
-      link: function(scope, element, attrs){
-        scope.availableTriggers= [
-          {id:"task_failed", display:"Any task fails..."},
-          {id:"first_task_failed", display:"The first failure in a version occurs..."},
-          {id:"task_fail_transition", display:"A task that had passed in a previous run fails"},
-        ]
-        scope.availableActions= [
-          {id:"email", display:"Send an e-mail"},
-        ]
-        scope.setTrigger = function(index){
-          scope.currentTrigger = scope.availableTriggers[index]
-        }
-        scope.currentTrigger = scope.availableTriggers[0]
-      }
-  };
-});
@@ -306,6 +306,15 @@ function validateDuration(duration) {
   }
   return "";
 }
+function validatePositiveInteger(n) {
+  if (!Number.isInteger(+n)) {
+    return n + " must be an integer";
+  }
+  if (+n <= 0) {
+    return n + " must be positive";
+  }
+  return "";
+}
 function validatePercentage(percent) {
   if (!isFinite(percent)) {
     return percent + " must be a number";
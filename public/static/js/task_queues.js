@@ -26,7 +26,15 @@ mciModule.controller('TaskQueuesCtrl',
   };
 
   $scope.isPatch = function(queueItem){
-    return queueItem.requester != 'gitter_request';
+    return queueItem.requester != 'gitter_request' && !$scope.isScheduled(queueItem) && !$scope.isTriggered(queueItem);
+  }
+
+  $scope.isScheduled = function(queueItem){
+    return queueItem.requester == 'scheduled_request';
+  }
+
+  $scope.isTriggered = function(queueItem){
+    return queueItem.requester == 'trigger_request';
   }
 
   $scope.sumEstimatedDuration = function(distro) {
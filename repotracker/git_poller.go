@@ -0,0 +1,392 @@
+package repotracker
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/evergreen-ci/evergreen/model"
+	"github.com/evergreen-ci/evergreen/model/patch"
+	"github.com/evergreen-ci/evergreen/subprocess"
+	"github.com/evergreen-ci/evergreen/thirdparty"
+	"github.com/pkg/errors"
+)
+
+const (
+	// gitPollerTimeout limits how long each operation of the poller can
+	// take, including updating the mirror, which is slow the first time.
+	gitPollerTimeout = 10 * time.Minute
+
+	// gitLogFormat separates the fields of a commit with unit separators and
+	// ends each commit with a record separator, since commit messages can
+	// contain anything else.
+	gitLogFormat = "--format=%H%x1f%an%x1f%ae%x1f%ct%x1f%B%x1e"
+)
+
+// gitURLCredentials matches the user information of URLs, which can hold
+// passwords and tokens.
+var gitURLCredentials = regexp.MustCompile(`([A-Za-z][A-Za-z0-9+.-]*://)[^/@\s]+@`)
+
+// gitMirrorLocks keeps the repotracker jobs in the process from updating the
+// same mirror at once.
+var gitMirrorLocks = struct {
+	sync.Mutex
+	locks map[string]*sync.Mutex
+}{locks: map[string]*sync.Mutex{}}
+
+// GitRepositoryPoller is a struct that implements the behavior required of a
+// RepoPoller for a repository on any git remote. It keeps a bare mirror of
+// the remote in a local directory and reads from it with git commands.
+type GitRepositoryPoller struct {
+	ProjectRef      *model.ProjectRef
+	MirrorDirectory string
+
+	synced bool
+}
+
+// NewGitRepositoryPoller constructs and returns a pointer to a
+// GitRepositoryPoller struct that keeps its mirror in the mirror directory,
+// or in the temporary directory if it's empty.
+func NewGitRepositoryPoller(projectRef *model.ProjectRef, mirrorDirectory string) *GitRepositoryPoller {
+	if mirrorDirectory == "" {
+		mirrorDirectory = filepath.Join(os.TempDir(), "evergreen-git-mirrors")
+	}
+
+	return &GitRepositoryPoller{
+		ProjectRef:      projectRef,
+		MirrorDirectory: mirrorDirectory,
+	}
+}
+
+// GetRemoteConfig reads the project's configuration file as at a given
+// revision
+func (p *GitRepositoryPoller) GetRemoteConfig(ctx context.Context, revision string) (*model.Project, error) {
+	ctx, cancel := context.WithTimeout(ctx, gitPollerTimeout)
+	defer cancel()
+
+	if err := p.sync(ctx); err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	file := revision + ":" + p.ProjectRef.RemotePath
+	if _, err := p.git(ctx, "cat-file", "-e", "--end-of-options", file); err != nil {
+		if _, err = p.git(ctx, "cat-file", "-e", "--end-of-options", revision+"^{commit}"); err != nil {
+			return nil, errors.Errorf("revision '%s' not found in repository '%s'", revision, p.remote())
+		}
+		return nil, thirdparty.NewFileNotFoundError(p.ProjectRef.RemotePath)
+	}
+
+	projectFileBytes, err := p.git(ctx, "show", "--end-of-options", file)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	projectConfig := &model.Project{}
+	err = model.LoadProjectInto([]byte(projectFileBytes), p.ProjectRef.Identifier, projectConfig)
+	if err != nil {
+		return nil, thirdparty.YAMLFormatError{Message: err.Error()}
+	}
+
+	return projectConfig, nil
+}
+
+// GetChangedFiles returns the paths of the files that a revision changed
+// relative to its first parent.
+func (p *GitRepositoryPoller) GetChangedFiles(ctx context.Context, revision string) ([]string, error) {
+	ctx, cancel := context.WithTimeout(ctx, gitPollerTimeout)
+	defer cancel()
+
+	if err := p.sync(ctx); err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	var out string
+	var err error
+	if _, parentErr := p.git(ctx, "rev-parse", "--verify", "--quiet", "--end-of-options", revision+"^1"); parentErr == nil {
+		out, err = p.git(ctx, "diff", "--name-only", "-z", "--end-of-options", revision+"^1", revision)
+	} else {
+		// the first commit has no parent, so all of its files are new
+		out, err = p.git(ctx, "diff-tree", "--no-commit-id", "--name-only", "-r", "-z", "--root", "--end-of-options", revision)
+	}
+	if err != nil {
+		return nil, errors.Wrapf(err, "error loading commit '%v'", revision)
+	}
+
+	files := []string{}
+	for _, file := range strings.Split(out, "\x00") {
+		if file != "" {
+			files = append(files, file)
+		}
+	}
+	return files, nil
+}
+
+// GetRevisionsSince returns the commits on the project's branch that were
+// made after 'revision'
+func (p *GitRepositoryPoller) GetRevisionsSince(revision string, maxRevisionsToSearch int) ([]model.Revision, error) {
+	ctx, cancel := context.WithTimeout(context.TODO(), gitPollerTimeout)
+	defer cancel()
+
+	if err := p.sync(ctx); err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	revisions, err := p.log(ctx, maxRevisionsToSearch)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	for i := range revisions {
+		if revisions[i].Revision == revision {
+			return revisions[:i], nil
+		}
+	}
+
+	baseRevision, err := p.git(ctx, "merge-base", "--end-of-options", revision, p.branchRef())
+	return []model.Revision{}, setRevisionNotFoundError(p.ProjectRef, revision, strings.TrimSpace(baseRevision), err)
+}
+
+// GetRecentRevisions returns the most recent 'maxRevisions' commits on the
+// project's branch
+func (p *GitRepositoryPoller) GetRecentRevisions(maxRevisions int) ([]model.Revision, error) {
+	ctx, cancel := context.WithTimeout(context.TODO(), gitPollerTimeout)
+	defer cancel()
+
+	if err := p.sync(ctx); err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	return p.log(ctx, maxRevisions)
+}
+
+// GetGitlabMergeRequestDiff returns the merge base of a GitLab merge
+// request's head and the project's branch, and the diff between the two. The
+// mirror fetches the refs that GitLab keeps for the heads of merge requests,
+// so this works for merge requests from forks too.
+func (p *GitRepositoryPoller) GetGitlabMergeRequestDiff(ctx context.Context, mrNumber int, headHash string) (string, string, error) {
+	ctx, cancel := context.WithTimeout(ctx, gitPollerTimeout)
+	defer cancel()
+
+	if err := p.sync(ctx); err != nil {
+		return "", "", errors.WithStack(err)
+	}
+
+	if _, err := p.git(ctx, "cat-file", "-e", headHash+"^{commit}"); err != nil {
+		// the merge request may have been updated since the mirror was synced
+		ref := fmt.Sprintf("refs/merge-requests/%d/head", mrNumber)
+		if _, err = p.git(ctx, "fetch", "--", "origin", "+"+ref+":"+ref); err != nil {
+			return "", "", errors.Wrapf(err, "problem fetching merge request !%d", mrNumber)
+		}
+		if _, err = p.git(ctx, "cat-file", "-e", headHash+"^{commit}"); err != nil {
+			return "", "", errors.Errorf("commit '%s' of merge request !%d not found in repository '%s'",
+				headHash, mrNumber, p.remote())
+		}
+	}
+
+	base, diff, err := p.branchDiff(ctx, headHash)
+	if err != nil {
+		return "", "", errors.Wrapf(err, "problem getting the diff of merge request !%d", mrNumber)
+	}
+
+	return base, diff, nil
+}
+
+// GetRefDiff returns the merge base of a branch, tag or revision of the
+// repository and the project's branch, and the diff between the two.
+func (p *GitRepositoryPoller) GetRefDiff(ctx context.Context, ref string) (string, string, error) {
+	ctx, cancel := context.WithTimeout(ctx, gitPollerTimeout)
+	defer cancel()
+
+	if err := p.sync(ctx); err != nil {
+		return "", "", errors.WithStack(err)
+	}
+
+	head, err := p.git(ctx, "rev-parse", "--verify", "--quiet", "--end-of-options", ref+"^{commit}")
+	if err != nil {
+		return "", "", errors.Errorf("'%s' not found in repository '%s'", ref, p.remote())
+	}
+
+	base, diff, err := p.branchDiff(ctx, strings.TrimSpace(head))
+	if err != nil {
+		return "", "", errors.Wrapf(err, "problem getting the diff of '%s'", ref)
+	}
+
+	return base, diff, nil
+}
+
+// branchDiff returns the merge base of a commit and the project's branch, and
+// the diff between the two.
+func (p *GitRepositoryPoller) branchDiff(ctx context.Context, head string) (string, string, error) {
+	base, err := p.git(ctx, "merge-base", "--end-of-options", head, p.branchRef())
+	if err != nil {
+		return "", "", errors.Wrapf(err, "problem finding the merge base with branch '%s'", p.ProjectRef.Branch)
+	}
+	base = strings.TrimSpace(base)
+
+	diff, err := p.git(ctx, "diff", "--no-color", "--no-ext-diff", "--end-of-options", base, head)
+	if err != nil {
+		return "", "", errors.WithStack(err)
+	}
+	if len(diff) > patch.SizeLimit {
+		return "", "", errors.Errorf("Patch contents must be no greater than %d bytes; was %d bytes",
+			patch.SizeLimit, len(diff))
+	}
+
+	return base, diff, nil
+}
+
+// mirrorPath returns the path of the project's mirror.
+func (p *GitRepositoryPoller) mirrorPath() string {
+	return filepath.Join(p.MirrorDirectory, url.PathEscape(p.ProjectRef.Identifier)+".git")
+}
+
+// remote returns the project's remote without any credentials in it, for
+// error messages.
+func (p *GitRepositoryPoller) remote() string {
+	u, err := url.Parse(p.ProjectRef.RepoURL)
+	if err != nil || u.User == nil {
+		return p.ProjectRef.RepoURL
+	}
+	u.User = nil
+	return u.String()
+}
+
+func (p *GitRepositoryPoller) branchRef() string {
+	return "refs/heads/" + p.ProjectRef.Branch
+}
+
+// sync clones the mirror of the remote if it doesn't exist, or else fetches
+// the latest changes into it. The mirror is only updated the first time the
+// poller needs it.
+func (p *GitRepositoryPoller) sync(ctx context.Context) error {
+	if p.synced {
+		return nil
+	}
+	if p.ProjectRef.RepoURL == "" {
+		return errors.Errorf("project '%s' has no repository URL", p.ProjectRef.Identifier)
+	}
+
+	mirror := p.mirrorPath()
+	gitMirrorLocks.Lock()
+	lock, ok := gitMirrorLocks.locks[mirror]
+	if !ok {
+		lock = &sync.Mutex{}
+		gitMirrorLocks.locks[mirror] = lock
+	}
+	gitMirrorLocks.Unlock()
+
+	lock.Lock()
+	defer lock.Unlock()
+
+	if _, err := os.Stat(mirror); os.IsNotExist(err) {
+		if err = os.MkdirAll(p.MirrorDirectory, 0755); err != nil {
+			return errors.Wrapf(err, "problem creating mirror directory '%s'", p.MirrorDirectory)
+		}
+		if _, err = runGit(ctx, p.MirrorDirectory, "clone", "--mirror", "--", p.ProjectRef.RepoURL, mirror); err != nil {
+			// don't leave a partial mirror behind for the next run
+			_ = os.RemoveAll(mirror)
+			return errors.Wrapf(err, "problem creating mirror of '%s'", p.remote())
+		}
+	} else {
+		// the remote may have changed in the project settings
+		if _, err = p.git(ctx, "remote", "set-url", "--", "origin", p.ProjectRef.RepoURL); err != nil {
+			return errors.WithStack(err)
+		}
+		if _, err = p.git(ctx, "fetch", "--prune", "--", "origin"); err != nil {
+			return errors.Wrapf(err, "problem updating mirror of '%s'", p.remote())
+		}
+	}
+
+	p.synced = true
+	return nil
+}
+
+// log returns the latest commits on the project's branch, newest first. A
+// limit <= 0 returns all of them.
+func (p *GitRepositoryPoller) log(ctx context.Context, limit int) ([]model.Revision, error) {
+	args := []string{"log", gitLogFormat}
+	if limit > 0 {
+		args = append(args, "--max-count="+strconv.Itoa(limit))
+	}
+	args = append(args, "--end-of-options", p.branchRef(), "--")
+
+	out, err := p.git(ctx, args...)
+	if err != nil {
+		return nil, errors.Wrapf(err, "problem listing commits on branch '%s'", p.ProjectRef.Branch)
+	}
+
+	return parseGitLog(out)
+}
+
+func (p *GitRepositoryPoller) git(ctx context.Context, args ...string) (string, error) {
+	return runGit(ctx, p.mirrorPath(), args...)
+}
+
+// parseGitLog converts output of git log in gitLogFormat to revisions.
+func parseGitLog(out string) ([]model.Revision, error) {
+	revisions := []model.Revision{}
+	for _, record := range strings.Split(out, "\x1e") {
+		record = strings.TrimLeft(record, "\n")
+		if record == "" {
+			continue
+		}
+
+		fields := strings.SplitN(record, "\x1f", 5)
+		if len(fields) != 5 {
+			return nil, errors.Errorf("git log returned a commit with missing information: '%s'", record)
+		}
+		timestamp, err := strconv.ParseInt(fields[3], 10, 64)
+		if err != nil {
+			return nil, errors.Wrapf(err, "git log returned an invalid commit time for commit '%s'", fields[0])
+		}
+
+		revisions = append(revisions, model.Revision{
+			Revision:        fields[0],
+			Author:          fields[1],
+			AuthorEmail:     fields[2],
+			CreateTime:      time.Unix(timestamp, 0),
+			RevisionMessage: strings.TrimRight(fields[4], "\n"),
+		})
+	}
+
+	return revisions, nil
+}
+
+// runGit runs a git command in the directory and returns its output. Git
+// won't prompt for credentials, since there's no one to answer.
+//
+// Revisions, branches and remotes come from users, so callers end the options
+// before them, with "--" for commands that take remotes and
+// "--end-of-options" for commands that take revisions, where "--" would start
+// the list of paths instead.
+func runGit(ctx context.Context, dir string, args ...string) (string, error) {
+	cmd, err := subprocess.NewLocalExec("git", args, map[string]string{"GIT_TERMINAL_PROMPT": "0"}, dir)
+	if err != nil {
+		return "", errors.WithStack(err)
+	}
+
+	stdout := &bytes.Buffer{}
+	stderr := &bytes.Buffer{}
+	if err = cmd.SetOutput(subprocess.OutputOptions{Output: stdout, Error: stderr}); err != nil {
+		return "", errors.Wrap(err, "problem configuring command output")
+	}
+	if err = cmd.Run(ctx); err != nil {
+		return "", errors.Wrapf(err, "'git %s' failed: %s",
+			redactGitCredentials(strings.Join(args, " ")), redactGitCredentials(strings.TrimSpace(stderr.String())))
+	}
+
+	return stdout.String(), nil
+}
+
+// redactGitCredentials removes the credentials from the URLs in git's
+// arguments or output, so that errors don't leak them.
+func redactGitCredentials(s string) string {
+	return gitURLCredentials.ReplaceAllString(s, "$1")
+}
@@ -167,8 +167,6 @@ func (gRepoPoller *GithubRepositoryPoller) GetRevisionsSince(revision string, ma
 	}
 
 	if !foundLatest {
-		var revisionDetails *model.RepositoryErrorDetails
-		var revisionError error
 		var err error
 		var baseRevision string
 
@@ -186,36 +184,8 @@ func (gRepoPoller *GithubRepositoryPoller) GetRevisionsSince(revision string, ma
 		} else {
 			err = errors.New("no recent commit found")
 		}
-		if len(revision) < 10 {
-			return nil, errors.Errorf("invalid revision: %v", revision)
-		}
-		if err != nil {
-			// unable to get merge base commit so set projectRef revision details with a blank base revision
-			revisionDetails = &model.RepositoryErrorDetails{
-				Exists:            true,
-				InvalidRevision:   revision[:10],
-				MergeBaseRevision: "",
-			}
-			revisionError = errors.Wrapf(err,
-				"unable to find a suggested merge base commit for revision %v, must fix on projects settings page",
-				revision)
-		} else {
-			// update project ref to have an inconsistent status
-			revisionDetails = &model.RepositoryErrorDetails{
-				Exists:            true,
-				InvalidRevision:   revision[:10],
-				MergeBaseRevision: baseRevision,
-			}
-			revisionError = errors.Errorf("base revision, %v not found, suggested base revision, %v found, must confirm on project settings page",
-				revision, baseRevision)
-		}
-
-		gRepoPoller.ProjectRef.RepotrackerError = revisionDetails
-		if err = gRepoPoller.ProjectRef.Upsert(); err != nil {
-			return []model.Revision{}, errors.Wrap(err, "unable to update projectRef revision details")
-		}
 
-		return []model.Revision{}, revisionError
+		return []model.Revision{}, setRevisionNotFoundError(gRepoPoller.ProjectRef, revision, baseRevision, err)
 	}
 
 	return revisions, nil
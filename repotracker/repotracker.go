@@ -320,19 +320,21 @@ func (repoTracker *RepoTracker) StoreRevisions(ctx context.Context, revisions []
 		}
 		v.Config = string(projectYamlBytes)
 
-		// "Ignore" a version if all changes are to ignored files
-		if len(project.Ignore) > 0 {
-			filenames, err := repoTracker.GetChangedFiles(ctx, revision)
+		// "Ignore" a version if all changes are to ignored files, and skip
+		// variants and tasks whose paths aren't affected by the changes
+		var changedFiles []string
+		if len(project.Ignore) > 0 || project.UsesPathFilters() {
+			changedFiles, err = repoTracker.GetChangedFiles(ctx, revision)
 			if err != nil {
-				return nil, errors.Wrap(err, "error checking GitHub for ignored files")
+				return nil, errors.Wrap(err, "error checking for changed files")
 			}
-			if project.IgnoresAllFiles(filenames) {
+			if project.IgnoresAllFiles(changedFiles) {
 				v.Ignored = true
 			}
 		}
 
 		// We rebind newestVersion each iteration, so the last binding will be the newest version
-		err = errors.Wrapf(createVersionItems(v, ref, project),
+		err = errors.Wrapf(createVersionItems(v, ref, project, changedFiles),
 			"Error creating version items for %s in project %s",
 			v.Id, ref.Identifier)
 		if err != nil {
@@ -493,7 +495,7 @@ func NewVersionFromRevision(ref *model.ProjectRef, rev model.Revision) (*version
 
 // createVersionItems populates and stores all the tasks and builds for a version according to
 // the given project config.
-func createVersionItems(v *version.Version, ref *model.ProjectRef, project *model.Project) error {
+func createVersionItems(v *version.Version, ref *model.ProjectRef, project *model.Project, changedFiles []string) error {
 	// generate all task Ids so that we can easily reference them for dependencies
 	taskIds := model.NewTaskIdTable(project, v)
 
@@ -503,7 +505,7 @@ func createVersionItems(v *version.Version, ref *model.ProjectRef, project *mode
 			continue
 		}
 
-		buildId, err := model.CreateBuildFromVersion(project, v, taskIds, buildvariant.Name, false, nil, nil, "")
+		buildId, err := model.CreateBuildFromVersion(project, v, taskIds, buildvariant.Name, false, nil, nil, "", changedFiles)
 		if err != nil {
 			return errors.WithStack(err)
 		}
@@ -528,12 +530,16 @@ func createVersionItems(v *version.Version, ref *model.ProjectRef, project *mode
 			}
 		}
 
+		// variants that the version's changes don't affect are never
+		// activated automatically, since their ActivateAt isn't set
 		var activateAt time.Time
-		if lastActivation == nil {
-			// if we don't have a last activation time then prepare to activate it immediately.
-			activateAt = time.Now()
-		} else {
-			activateAt = lastActivation.Add(time.Minute * time.Duration(ref.GetBatchTime(&buildvariant)))
+		if buildvariant.IsAffectedBy(changedFiles) {
+			if lastActivation == nil {
+				// if we don't have a last activation time then prepare to activate it immediately.
+				activateAt = time.Now()
+			} else {
+				activateAt = lastActivation.Add(time.Minute * time.Duration(ref.GetBatchTime(&buildvariant)))
+			}
 		}
 
 		grip.Info(message.Fields{
@@ -643,3 +649,38 @@ func makeBuildBreakSubscriber(userID string) (*event.Subscriber, error) {
 
 	return subscriber, nil
 }
+
+// setRevisionNotFoundError records on the project ref that the last revision
+// the repotracker saw can't be found on the branch, along with the merge base
+// of the revision and the branch if it could be found, so that the revision
+// to continue from can be confirmed on the project settings page. It returns
+// the error to report for the revision.
+func setRevisionNotFoundError(projectRef *model.ProjectRef, revision, baseRevision string, baseErr error) error {
+	if len(revision) < 10 {
+		return errors.Errorf("invalid revision: %v", revision)
+	}
+
+	var revisionError error
+	if baseErr != nil {
+		// unable to get merge base commit so set projectRef revision details with a blank base revision
+		baseRevision = ""
+		revisionError = errors.Wrapf(baseErr,
+			"unable to find a suggested merge base commit for revision %v, must fix on projects settings page",
+			revision)
+	} else {
+		revisionError = errors.Errorf("base revision, %v not found, suggested base revision, %v found, must confirm on project settings page",
+			revision, baseRevision)
+	}
+
+	// update project ref to have an inconsistent status
+	projectRef.RepotrackerError = &model.RepositoryErrorDetails{
+		Exists:            true,
+		InvalidRevision:   revision[:10],
+		MergeBaseRevision: baseRevision,
+	}
+	if err := projectRef.Upsert(); err != nil {
+		return errors.Wrap(err, "unable to update projectRef revision details")
+	}
+
+	return revisionError
+}
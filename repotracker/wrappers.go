@@ -21,6 +21,14 @@ const (
 )
 
 func getTracker(conf *evergreen.Settings, project model.ProjectRef) (*RepoTracker, error) {
+	if project.RepoKind == model.GitRepoType {
+		return &RepoTracker{
+			Settings:   conf,
+			ProjectRef: &project,
+			RepoPoller: NewGitRepositoryPoller(&project, conf.RepoTracker.MirrorDirectory),
+		}, nil
+	}
+
 	token, err := conf.GetGithubOauthToken()
 	if err != nil {
 		grip.Warning(message.Fields{
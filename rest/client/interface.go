@@ -9,6 +9,7 @@ import (
 	"github.com/evergreen-ci/evergreen/apimodels"
 	"github.com/evergreen-ci/evergreen/model"
 	"github.com/evergreen-ci/evergreen/model/artifact"
+	"github.com/evergreen-ci/evergreen/model/audit"
 	"github.com/evergreen-ci/evergreen/model/distro"
 	"github.com/evergreen-ci/evergreen/model/event"
 	"github.com/evergreen-ci/evergreen/model/manifest"
@@ -88,6 +89,7 @@ type Communicator interface {
 
 	// The following operations are used by
 	AttachFiles(context.Context, TaskData, []*artifact.File) error
+	SendCoverage(context.Context, TaskData, []string) error
 	GetManifest(context.Context, TaskData) (*manifest.Manifest, error)
 	S3Copy(context.Context, TaskData, *apimodels.S3CopyRequest) error
 	KeyValInc(context.Context, TaskData, *model.KeyVal) error
@@ -129,6 +131,49 @@ type Communicator interface {
 	GetEvents(context.Context, time.Time, int) ([]interface{}, error)
 	RevertSettings(context.Context, string) error
 
+	// Role methods
+	//
+	GetRoles(context.Context) ([]restmodel.APIRole, error)
+	UpdateRole(context.Context, *restmodel.APIRole) error
+	DeleteRole(context.Context, string) error
+	GetUserRoles(context.Context, string) ([]string, error)
+	UpdateUserRoles(context.Context, string, []string, []string) ([]string, error)
+
+	// API token methods
+	//
+	GetAPITokens(context.Context) ([]restmodel.APIToken, error)
+	CreateAPIToken(context.Context, string, []string, time.Time) (*restmodel.APIToken, error)
+	DeleteAPIToken(context.Context, string) error
+
+	// Service account methods
+	//
+	GetServiceAccounts(context.Context) ([]restmodel.APIServiceAccount, error)
+	CreateServiceAccount(context.Context, *restmodel.APIServiceAccount) (*restmodel.APIServiceAccount, error)
+	DeleteServiceAccount(context.Context, string) error
+	CreateServiceAccountToken(context.Context, string, string, []string, time.Time) (*restmodel.APIToken, error)
+	DeleteServiceAccountToken(context.Context, string, string) error
+
+	// GetAuditLog returns the audit log entries matching the filter,
+	// newest first.
+	GetAuditLog(context.Context, audit.Filter) ([]restmodel.APIAuditEntry, error)
+
+	// Patch and version methods
+	//
+	GetPatchByID(context.Context, string) (*restmodel.APIPatch, error)
+	// RebasePatch creates a copy of a patch based on a newer revision of
+	// its project.
+	RebasePatch(context.Context, string, restmodel.PatchRebaseRequest) (*restmodel.APIPatch, error)
+	GetVersionByID(context.Context, string) (*restmodel.APIVersion, error)
+	GetBuildTasks(context.Context, string) ([]restmodel.APITask, error)
+
+	// StartBulkTaskOperation starts applying an action to the tasks
+	// selected by the request.
+	StartBulkTaskOperation(context.Context, restmodel.BulkTaskRequest) (*restmodel.APIBulkTaskOperation, error)
+
+	// GetRecentTaskLogs returns the most recent messages, newest first, of
+	// the logs of the given type for an execution of a task.
+	GetRecentTaskLogs(context.Context, string, int, string) ([]apimodels.LogMessage, error)
+
 	// Host methods
 	GetHostsByUser(context.Context, string) ([]*restmodel.APIHost, error)
 
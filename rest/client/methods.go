@@ -440,6 +440,23 @@ func (c *communicatorImpl) AttachFiles(ctx context.Context, taskData TaskData, t
 	return nil
 }
 
+// SendCoverage sends the source files covered by a task.
+func (c *communicatorImpl) SendCoverage(ctx context.Context, taskData TaskData, files []string) error {
+	info := requestInfo{
+		method:   post,
+		taskData: &taskData,
+		version:  apiVersion1,
+	}
+	info.setTaskPathSuffix("coverage")
+	resp, err := c.retryRequest(ctx, info, files)
+	if err != nil {
+		return errors.Wrapf(err, "failed to post coverage for task %s", taskData.ID)
+	}
+	defer resp.Body.Close()
+
+	return nil
+}
+
 func (c *communicatorImpl) GetManifest(ctx context.Context, taskData TaskData) (*manifest.Manifest, error) {
 	info := requestInfo{
 		method:   get,
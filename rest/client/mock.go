@@ -13,6 +13,7 @@ import (
 	"github.com/evergreen-ci/evergreen/apimodels"
 	serviceModel "github.com/evergreen-ci/evergreen/model"
 	"github.com/evergreen-ci/evergreen/model/artifact"
+	"github.com/evergreen-ci/evergreen/model/audit"
 	"github.com/evergreen-ci/evergreen/model/distro"
 	"github.com/evergreen-ci/evergreen/model/event"
 	"github.com/evergreen-ci/evergreen/model/manifest"
@@ -57,6 +58,7 @@ type Mock struct {
 	GetSubscriptionsFail   bool
 
 	AttachedFiles    map[string][]*artifact.File
+	CoveredFiles     map[string][]string
 	LogID            string
 	LocalTestResults *task.LocalTestResults
 	TestLogs         []*serviceModel.TestLog
@@ -92,6 +94,7 @@ func NewMock(serverURL string) *Mock {
 		ProcInfo:      make(map[string][]*message.ProcessInfo),
 		SysInfo:       make(map[string]*message.SystemInfo),
 		AttachedFiles: make(map[string][]*artifact.File),
+		CoveredFiles:  make(map[string][]string),
 		serverURL:     serverURL,
 	}
 }
@@ -361,6 +364,55 @@ func (c *Mock) GetEvents(ctx context.Context, ts time.Time, limit int) ([]interf
 }
 func (c *Mock) RevertSettings(ctx context.Context, guid string) error { return nil }
 
+func (c *Mock) GetRoles(ctx context.Context) ([]model.APIRole, error)           { return nil, nil }
+func (c *Mock) UpdateRole(ctx context.Context, r *model.APIRole) error          { return nil }
+func (c *Mock) DeleteRole(ctx context.Context, id string) error                 { return nil }
+func (c *Mock) GetUserRoles(ctx context.Context, user string) ([]string, error) { return nil, nil }
+func (c *Mock) UpdateUserRoles(ctx context.Context, user string, add, remove []string) ([]string, error) {
+	return nil, nil
+}
+
+func (c *Mock) GetAPITokens(ctx context.Context) ([]model.APIToken, error) { return nil, nil }
+func (c *Mock) CreateAPIToken(ctx context.Context, name string, scopes []string, expiresAt time.Time) (*model.APIToken, error) {
+	return nil, nil
+}
+func (c *Mock) DeleteAPIToken(ctx context.Context, name string) error { return nil }
+
+func (c *Mock) GetServiceAccounts(ctx context.Context) ([]model.APIServiceAccount, error) {
+	return nil, nil
+}
+func (c *Mock) CreateServiceAccount(ctx context.Context, a *model.APIServiceAccount) (*model.APIServiceAccount, error) {
+	return a, nil
+}
+func (c *Mock) DeleteServiceAccount(ctx context.Context, id string) error { return nil }
+func (c *Mock) CreateServiceAccountToken(ctx context.Context, id, name string, scopes []string, expiresAt time.Time) (*model.APIToken, error) {
+	return nil, nil
+}
+func (c *Mock) DeleteServiceAccountToken(ctx context.Context, id, name string) error { return nil }
+
+func (c *Mock) GetAuditLog(ctx context.Context, f audit.Filter) ([]model.APIAuditEntry, error) {
+	return nil, nil
+}
+
+func (c *Mock) GetPatchByID(ctx context.Context, patchID string) (*model.APIPatch, error) {
+	return nil, nil
+}
+func (c *Mock) RebasePatch(ctx context.Context, patchID string, req model.PatchRebaseRequest) (*model.APIPatch, error) {
+	return nil, nil
+}
+func (c *Mock) GetVersionByID(ctx context.Context, versionID string) (*model.APIVersion, error) {
+	return nil, nil
+}
+func (c *Mock) GetBuildTasks(ctx context.Context, buildID string) ([]model.APITask, error) {
+	return nil, nil
+}
+func (c *Mock) StartBulkTaskOperation(ctx context.Context, req model.BulkTaskRequest) (*model.APIBulkTaskOperation, error) {
+	return nil, nil
+}
+func (c *Mock) GetRecentTaskLogs(ctx context.Context, taskID string, execution int, logType string) ([]apimodels.LogMessage, error) {
+	return nil, nil
+}
+
 // SendResults posts a set of test results for the communicator's task.
 // If results are empty or nil, this operation is a noop.
 func (c *Mock) SendTestResults(ctx context.Context, td TaskData, results *task.LocalTestResults) error {
@@ -379,6 +431,16 @@ func (c *Mock) AttachFiles(ctx context.Context, td TaskData, taskFiles []*artifa
 	return nil
 }
 
+// SendCoverage records the files covered by a task.
+func (c *Mock) SendCoverage(ctx context.Context, td TaskData, files []string) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.CoveredFiles[td.ID] = files
+
+	return nil
+}
+
 // SendTestLog posts a test log for a communicator's task. Is a
 // noop if the test Log is nil.
 func (c *Mock) SendTestLog(ctx context.Context, td TaskData, log *serviceModel.TestLog) (string, error) {
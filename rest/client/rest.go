@@ -6,10 +6,14 @@ import (
 	"fmt"
 	"io/ioutil"
 	"net/http"
+	"net/url"
+	"strconv"
 	"time"
 
 	"github.com/evergreen-ci/evergreen"
+	"github.com/evergreen-ci/evergreen/apimodels"
 	serviceModel "github.com/evergreen-ci/evergreen/model"
+	"github.com/evergreen-ci/evergreen/model/audit"
 	"github.com/evergreen-ci/evergreen/model/event"
 	"github.com/evergreen-ci/evergreen/rest/model"
 	"github.com/evergreen-ci/evergreen/util"
@@ -611,3 +615,494 @@ func (c *communicatorImpl) GetSubscriptions(ctx context.Context) ([]event.Subscr
 
 	return subs, nil
 }
+
+func (c *communicatorImpl) GetRoles(ctx context.Context) ([]model.APIRole, error) {
+	info := requestInfo{
+		method:  get,
+		version: apiVersion2,
+		path:    "roles",
+	}
+
+	resp, err := c.request(ctx, info, nil)
+	if err != nil {
+		return nil, errors.Wrap(err, "problem reaching evergreen API server")
+	}
+	defer resp.Body.Close()
+	if err = readErrorResponse(resp, "problem getting roles"); err != nil {
+		return nil, err
+	}
+
+	roles := []model.APIRole{}
+	if err = util.ReadJSONInto(resp.Body, &roles); err != nil {
+		return nil, errors.Wrap(err, "problem parsing response from server")
+	}
+
+	return roles, nil
+}
+
+func (c *communicatorImpl) UpdateRole(ctx context.Context, r *model.APIRole) error {
+	info := requestInfo{
+		method:  post,
+		version: apiVersion2,
+		path:    "roles",
+	}
+
+	resp, err := c.request(ctx, info, r)
+	if err != nil {
+		return errors.Wrap(err, "problem reaching evergreen API server")
+	}
+	defer resp.Body.Close()
+
+	return readErrorResponse(resp, "problem updating role")
+}
+
+func (c *communicatorImpl) DeleteRole(ctx context.Context, id string) error {
+	info := requestInfo{
+		method:  delete,
+		version: apiVersion2,
+		path:    "roles/" + id,
+	}
+
+	resp, err := c.request(ctx, info, nil)
+	if err != nil {
+		return errors.Wrap(err, "problem reaching evergreen API server")
+	}
+	defer resp.Body.Close()
+
+	return readErrorResponse(resp, "problem deleting role")
+}
+
+func (c *communicatorImpl) GetUserRoles(ctx context.Context, userID string) ([]string, error) {
+	info := requestInfo{
+		method:  get,
+		version: apiVersion2,
+		path:    fmt.Sprintf("users/%s/roles", userID),
+	}
+
+	resp, err := c.request(ctx, info, nil)
+	if err != nil {
+		return nil, errors.Wrap(err, "problem reaching evergreen API server")
+	}
+	defer resp.Body.Close()
+	if err = readErrorResponse(resp, "problem getting user roles"); err != nil {
+		return nil, err
+	}
+
+	roles := model.APIUserRoles{}
+	if err = util.ReadJSONInto(resp.Body, &roles); err != nil {
+		return nil, errors.Wrap(err, "problem parsing response from server")
+	}
+
+	return roles.Roles, nil
+}
+
+func (c *communicatorImpl) UpdateUserRoles(ctx context.Context, userID string, add, remove []string) ([]string, error) {
+	info := requestInfo{
+		method:  post,
+		version: apiVersion2,
+		path:    fmt.Sprintf("users/%s/roles", userID),
+	}
+
+	resp, err := c.request(ctx, info, struct {
+		Add    []string `json:"add"`
+		Remove []string `json:"remove"`
+	}{
+		Add:    add,
+		Remove: remove,
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, "problem reaching evergreen API server")
+	}
+	defer resp.Body.Close()
+	if err = readErrorResponse(resp, "problem updating user roles"); err != nil {
+		return nil, err
+	}
+
+	roles := model.APIUserRoles{}
+	if err = util.ReadJSONInto(resp.Body, &roles); err != nil {
+		return nil, errors.Wrap(err, "problem parsing response from server")
+	}
+
+	return roles.Roles, nil
+}
+
+func (c *communicatorImpl) GetAPITokens(ctx context.Context) ([]model.APIToken, error) {
+	info := requestInfo{
+		method:  get,
+		version: apiVersion2,
+		path:    "user/tokens",
+	}
+
+	resp, err := c.request(ctx, info, nil)
+	if err != nil {
+		return nil, errors.Wrap(err, "problem reaching evergreen API server")
+	}
+	defer resp.Body.Close()
+	if err = readErrorResponse(resp, "problem getting tokens"); err != nil {
+		return nil, err
+	}
+
+	tokens := []model.APIToken{}
+	if err = util.ReadJSONInto(resp.Body, &tokens); err != nil {
+		return nil, errors.Wrap(err, "problem parsing response from server")
+	}
+
+	return tokens, nil
+}
+
+func (c *communicatorImpl) CreateAPIToken(ctx context.Context, name string, scopes []string, expiresAt time.Time) (*model.APIToken, error) {
+	info := requestInfo{
+		method:  post,
+		version: apiVersion2,
+		path:    "user/tokens",
+	}
+
+	return c.createToken(ctx, info, name, scopes, expiresAt)
+}
+
+func (c *communicatorImpl) createToken(ctx context.Context, info requestInfo, name string, scopes []string, expiresAt time.Time) (*model.APIToken, error) {
+	body := model.APIToken{
+		Name:      model.ToAPIString(name),
+		Scopes:    []model.APIString{},
+		ExpiresAt: model.NewTime(expiresAt),
+	}
+	for _, scope := range scopes {
+		body.Scopes = append(body.Scopes, model.ToAPIString(scope))
+	}
+
+	resp, err := c.request(ctx, info, body)
+	if err != nil {
+		return nil, errors.Wrap(err, "problem reaching evergreen API server")
+	}
+	defer resp.Body.Close()
+	if err = readErrorResponse(resp, "problem creating token"); err != nil {
+		return nil, err
+	}
+
+	token := &model.APIToken{}
+	if err = util.ReadJSONInto(resp.Body, token); err != nil {
+		return nil, errors.Wrap(err, "problem parsing response from server")
+	}
+
+	return token, nil
+}
+
+func (c *communicatorImpl) DeleteAPIToken(ctx context.Context, name string) error {
+	info := requestInfo{
+		method:  delete,
+		version: apiVersion2,
+		path:    "user/tokens/" + name,
+	}
+
+	resp, err := c.request(ctx, info, nil)
+	if err != nil {
+		return errors.Wrap(err, "problem reaching evergreen API server")
+	}
+	defer resp.Body.Close()
+
+	return readErrorResponse(resp, fmt.Sprintf("problem deleting token '%s'", name))
+}
+
+func (c *communicatorImpl) GetServiceAccounts(ctx context.Context) ([]model.APIServiceAccount, error) {
+	info := requestInfo{
+		method:  get,
+		version: apiVersion2,
+		path:    "admin/service_accounts",
+	}
+
+	resp, err := c.request(ctx, info, nil)
+	if err != nil {
+		return nil, errors.Wrap(err, "problem reaching evergreen API server")
+	}
+	defer resp.Body.Close()
+	if err = readErrorResponse(resp, "problem getting service accounts"); err != nil {
+		return nil, err
+	}
+
+	accounts := []model.APIServiceAccount{}
+	if err = util.ReadJSONInto(resp.Body, &accounts); err != nil {
+		return nil, errors.Wrap(err, "problem parsing response from server")
+	}
+
+	return accounts, nil
+}
+
+func (c *communicatorImpl) CreateServiceAccount(ctx context.Context, a *model.APIServiceAccount) (*model.APIServiceAccount, error) {
+	info := requestInfo{
+		method:  post,
+		version: apiVersion2,
+		path:    "admin/service_accounts",
+	}
+
+	resp, err := c.request(ctx, info, a)
+	if err != nil {
+		return nil, errors.Wrap(err, "problem reaching evergreen API server")
+	}
+	defer resp.Body.Close()
+	if err = readErrorResponse(resp, "problem creating service account"); err != nil {
+		return nil, err
+	}
+
+	account := &model.APIServiceAccount{}
+	if err = util.ReadJSONInto(resp.Body, account); err != nil {
+		return nil, errors.Wrap(err, "problem parsing response from server")
+	}
+
+	return account, nil
+}
+
+func (c *communicatorImpl) DeleteServiceAccount(ctx context.Context, id string) error {
+	info := requestInfo{
+		method:  delete,
+		version: apiVersion2,
+		path:    "admin/service_accounts/" + id,
+	}
+
+	resp, err := c.request(ctx, info, nil)
+	if err != nil {
+		return errors.Wrap(err, "problem reaching evergreen API server")
+	}
+	defer resp.Body.Close()
+
+	return readErrorResponse(resp, fmt.Sprintf("problem deleting service account '%s'", id))
+}
+
+func (c *communicatorImpl) CreateServiceAccountToken(ctx context.Context, id, name string, scopes []string, expiresAt time.Time) (*model.APIToken, error) {
+	info := requestInfo{
+		method:  post,
+		version: apiVersion2,
+		path:    fmt.Sprintf("admin/service_accounts/%s/tokens", id),
+	}
+
+	return c.createToken(ctx, info, name, scopes, expiresAt)
+}
+
+func (c *communicatorImpl) DeleteServiceAccountToken(ctx context.Context, id, name string) error {
+	info := requestInfo{
+		method:  delete,
+		version: apiVersion2,
+		path:    fmt.Sprintf("admin/service_accounts/%s/tokens/%s", id, name),
+	}
+
+	resp, err := c.request(ctx, info, nil)
+	if err != nil {
+		return errors.Wrap(err, "problem reaching evergreen API server")
+	}
+	defer resp.Body.Close()
+
+	return readErrorResponse(resp, fmt.Sprintf("problem deleting token '%s' of service account '%s'", name, id))
+}
+
+func (c *communicatorImpl) GetAuditLog(ctx context.Context, f audit.Filter) ([]model.APIAuditEntry, error) {
+	params := url.Values{}
+	for param, value := range map[string]string{
+		"actor":         f.Actor,
+		"resource_type": f.ResourceType,
+		"resource_id":   f.ResourceID,
+		"action":        f.Action,
+		"before":        f.Before,
+	} {
+		if value != "" {
+			params.Set(param, value)
+		}
+	}
+	if !f.Start.IsZero() {
+		params.Set("start", f.Start.Format(time.RFC3339))
+	}
+	if !f.End.IsZero() {
+		params.Set("end", f.End.Format(time.RFC3339))
+	}
+	if f.Limit > 0 {
+		params.Set("limit", strconv.Itoa(f.Limit))
+	}
+
+	info := requestInfo{
+		method:  get,
+		version: apiVersion2,
+		path:    "admin/audit?" + params.Encode(),
+	}
+
+	resp, err := c.request(ctx, info, nil)
+	if err != nil {
+		return nil, errors.Wrap(err, "problem reaching evergreen API server")
+	}
+	defer resp.Body.Close()
+	if err = readErrorResponse(resp, "problem getting audit log"); err != nil {
+		return nil, err
+	}
+
+	entries := []model.APIAuditEntry{}
+	if err = util.ReadJSONInto(resp.Body, &entries); err != nil {
+		return nil, errors.Wrap(err, "problem parsing response from server")
+	}
+
+	return entries, nil
+}
+
+func (c *communicatorImpl) GetPatchByID(ctx context.Context, patchID string) (*model.APIPatch, error) {
+	info := requestInfo{
+		method:  get,
+		version: apiVersion2,
+		path:    fmt.Sprintf("patches/%s", patchID),
+	}
+
+	resp, err := c.request(ctx, info, nil)
+	if err != nil {
+		return nil, errors.Wrap(err, "problem reaching evergreen API server")
+	}
+	defer resp.Body.Close()
+	if err = readErrorResponse(resp, fmt.Sprintf("problem getting patch '%s'", patchID)); err != nil {
+		return nil, err
+	}
+
+	p := &model.APIPatch{}
+	if err = util.ReadJSONInto(resp.Body, p); err != nil {
+		return nil, errors.Wrap(err, "problem parsing response from server")
+	}
+
+	return p, nil
+}
+
+func (c *communicatorImpl) RebasePatch(ctx context.Context, patchID string, req model.PatchRebaseRequest) (*model.APIPatch, error) {
+	info := requestInfo{
+		method:  post,
+		version: apiVersion2,
+		path:    fmt.Sprintf("patches/%s/rebase", patchID),
+	}
+
+	resp, err := c.request(ctx, info, req)
+	if err != nil {
+		return nil, errors.Wrap(err, "problem reaching evergreen API server")
+	}
+	defer resp.Body.Close()
+	if err = readErrorResponse(resp, fmt.Sprintf("problem rebasing patch '%s'", patchID)); err != nil {
+		return nil, err
+	}
+
+	p := &model.APIPatch{}
+	if err = util.ReadJSONInto(resp.Body, p); err != nil {
+		return nil, errors.Wrap(err, "problem parsing response from server")
+	}
+
+	return p, nil
+}
+
+func (c *communicatorImpl) GetVersionByID(ctx context.Context, versionID string) (*model.APIVersion, error) {
+	info := requestInfo{
+		method:  get,
+		version: apiVersion2,
+		path:    fmt.Sprintf("versions/%s", versionID),
+	}
+
+	resp, err := c.request(ctx, info, nil)
+	if err != nil {
+		return nil, errors.Wrap(err, "problem reaching evergreen API server")
+	}
+	defer resp.Body.Close()
+	if err = readErrorResponse(resp, fmt.Sprintf("problem getting version '%s'", versionID)); err != nil {
+		return nil, err
+	}
+
+	v := &model.APIVersion{}
+	if err = util.ReadJSONInto(resp.Body, v); err != nil {
+		return nil, errors.Wrap(err, "problem parsing response from server")
+	}
+
+	return v, nil
+}
+
+func (c *communicatorImpl) GetBuildTasks(ctx context.Context, buildID string) ([]model.APITask, error) {
+	info := requestInfo{
+		method:  get,
+		version: apiVersion2,
+		path:    fmt.Sprintf("builds/%s/tasks", buildID),
+	}
+
+	p, err := newPaginatorHelper(&info, c)
+	if err != nil {
+		return nil, err
+	}
+
+	tasks := []model.APITask{}
+	for p.hasMore() {
+		resp, err := p.getNextPage(ctx)
+		if err != nil {
+			return nil, errors.Wrapf(err, "problem getting tasks for build '%s'", buildID)
+		}
+
+		page := []model.APITask{}
+		err = util.ReadJSONInto(resp.Body, &page)
+		resp.Body.Close()
+		if err != nil {
+			return nil, errors.Wrap(err, "problem parsing response from server")
+		}
+		tasks = append(tasks, page...)
+	}
+
+	return tasks, nil
+}
+
+func (c *communicatorImpl) StartBulkTaskOperation(ctx context.Context, req model.BulkTaskRequest) (*model.APIBulkTaskOperation, error) {
+	info := requestInfo{
+		method:  post,
+		version: apiVersion2,
+		path:    "tasks/bulk",
+	}
+
+	resp, err := c.request(ctx, info, req)
+	if err != nil {
+		return nil, errors.Wrap(err, "problem reaching evergreen API server")
+	}
+	defer resp.Body.Close()
+	if err = readErrorResponse(resp, "problem starting bulk task operation"); err != nil {
+		return nil, err
+	}
+
+	op := &model.APIBulkTaskOperation{}
+	if err = util.ReadJSONInto(resp.Body, op); err != nil {
+		return nil, errors.Wrap(err, "problem parsing response from server")
+	}
+
+	return op, nil
+}
+
+func (c *communicatorImpl) GetRecentTaskLogs(ctx context.Context, taskID string, execution int, logType string) ([]apimodels.LogMessage, error) {
+	path := fmt.Sprintf("json/task_log/%s/%d?%s", taskID, execution, url.Values{
+		"type": []string{logType},
+	}.Encode())
+	r, err := c.newRequest(string(get), path, "", "", nil)
+	if err != nil {
+		return nil, errors.Wrap(err, "problem creating request")
+	}
+
+	resp, err := c.doRequest(ctx, r)
+	if err != nil {
+		return nil, errors.Wrap(err, "problem reaching evergreen server")
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, errors.Errorf("problem getting logs for task '%s': received status %s", taskID, resp.Status)
+	}
+
+	logs := struct {
+		LogMessages []apimodels.LogMessage
+	}{}
+	if err = util.ReadJSONInto(resp.Body, &logs); err != nil {
+		return nil, errors.Wrap(err, "problem parsing response from server")
+	}
+
+	return logs.LogMessages, nil
+}
+
+func readErrorResponse(resp *http.Response, msg string) error {
+	if resp.StatusCode == http.StatusOK {
+		return nil
+	}
+
+	errMsg := gimlet.ErrorResponse{}
+	if err := util.ReadJSONInto(resp.Body, &errMsg); err != nil {
+		return errors.Errorf("%s: received status %s", msg, resp.Status)
+	}
+
+	return errors.Wrap(errMsg, msg)
+}
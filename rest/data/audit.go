@@ -0,0 +1,49 @@
+package data
+
+import (
+	"github.com/evergreen-ci/evergreen/model/audit"
+	"github.com/pkg/errors"
+)
+
+// DBAuditConnector is a struct that implements the audit log related
+// methods from the Connector through interactions with the backing
+// database.
+type DBAuditConnector struct{}
+
+// GetAuditLog returns the audit log entries matching the filter, newest
+// first.
+func (ac *DBAuditConnector) GetAuditLog(f audit.Filter) ([]audit.Entry, error) {
+	entries, err := audit.Find(f)
+	return entries, errors.WithStack(err)
+}
+
+// MockAuditConnector is a struct that implements mock versions of the audit
+// log related methods for testing. CachedEntries are ordered newest first.
+type MockAuditConnector struct {
+	CachedEntries []audit.Entry
+}
+
+func (ac *MockAuditConnector) GetAuditLog(f audit.Filter) ([]audit.Entry, error) {
+	entries := []audit.Entry{}
+	started := f.Before == ""
+	for _, e := range ac.CachedEntries {
+		if !started {
+			started = e.ID.Hex() == f.Before
+			continue
+		}
+		if (f.Actor != "" && e.Actor != f.Actor) ||
+			(f.ResourceType != "" && e.ResourceType != f.ResourceType) ||
+			(f.ResourceID != "" && e.ResourceID != f.ResourceID) ||
+			(f.Action != "" && e.Action != f.Action) ||
+			(!f.Start.IsZero() && e.Timestamp.Before(f.Start)) ||
+			(!f.End.IsZero() && !e.Timestamp.Before(f.End)) {
+			continue
+		}
+		entries = append(entries, e)
+		if f.Limit > 0 && len(entries) == f.Limit {
+			break
+		}
+	}
+
+	return entries, nil
+}
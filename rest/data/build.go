@@ -7,6 +7,7 @@ import (
 
 	"github.com/evergreen-ci/evergreen/model"
 	"github.com/evergreen-ci/evergreen/model/build"
+	"github.com/evergreen-ci/evergreen/util"
 	"github.com/evergreen-ci/gimlet"
 	"github.com/pkg/errors"
 )
@@ -31,6 +32,13 @@ func (bc *DBBuildConnector) FindBuildById(buildId string) (*build.Build, error)
 	return b, nil
 }
 
+// FindBuildsByIds queries the backing database for the builds with the
+// given ids.
+func (bc *DBBuildConnector) FindBuildsByIds(buildIds []string) ([]build.Build, error) {
+	builds, err := build.Find(build.ByIds(buildIds))
+	return builds, errors.Wrap(err, "problem finding builds")
+}
+
 // FindProjectByBranch queries the project_refs database to find the name of the
 // project a given branch falls under.
 func (bc *DBBuildConnector) FindProjectByBranch(branch string) (*model.ProjectRef, error) {
@@ -82,6 +90,18 @@ func (bc *MockBuildConnector) FindBuildById(buildId string) (*build.Build, error
 	}
 }
 
+// FindBuildsByIds returns the builds in the CachedBuilds slice with the
+// given ids.
+func (bc *MockBuildConnector) FindBuildsByIds(buildIds []string) ([]build.Build, error) {
+	builds := []build.Build{}
+	for _, b := range bc.CachedBuilds {
+		if util.StringSliceContains(buildIds, b.Id) {
+			builds = append(builds, b)
+		}
+	}
+	return builds, nil
+}
+
 // FindProjectByBranch accesses the map of branch names to project names to find
 // the project corresponding to a given branch.
 func (bc *MockBuildConnector) FindProjectByBranch(branch string) (*model.ProjectRef, error) {
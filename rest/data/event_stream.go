@@ -0,0 +1,116 @@
+package data
+
+import (
+	"strings"
+	"time"
+
+	"github.com/evergreen-ci/evergreen/model/event"
+	"github.com/evergreen-ci/evergreen/model/trigger"
+	restModel "github.com/evergreen-ci/evergreen/rest/model"
+	"github.com/mongodb/grip"
+	"github.com/mongodb/grip/message"
+	"github.com/pkg/errors"
+)
+
+// StreamableResourceTypes are the resource types whose events can be
+// read from the event stream.
+var StreamableResourceTypes = []string{
+	event.ResourceTypeTask,
+	event.ResourceTypeBuild,
+	event.ResourceTypeVersion,
+	event.ResourceTypePatch,
+	event.ResourceTypeHost,
+	event.ResourceTypeDistro,
+}
+
+// eventStreamSettleTime is how long after they're logged events are
+// streamed, so that events logged concurrently with earlier times have
+// been inserted by then.
+const eventStreamSettleTime = 2 * time.Second
+
+type DBEventStreamConnector struct{}
+
+// FindEventsAfter returns up to n events of the given resource types
+// that were logged after the cursor and that match the selectors, along
+// with the position of the last event examined, which the stream should
+// be resumed from.
+func (c *DBEventStreamConnector) FindEventsAfter(after event.EventCursor, resourceTypes []string, selectors, regexSelectors []event.Selector, n int) ([]restModel.APIEventLogEntry, event.EventCursor, error) {
+	events, err := event.Find(event.AllLogCollection, event.EventsAfter(after, time.Now().Add(-eventStreamSettleTime), resourceTypes, n))
+	if err != nil {
+		return nil, after, errors.Wrap(err, "failed to fetch events")
+	}
+
+	return filterStreamEvents(events, after, selectors, regexSelectors, trigger.EventSelectors)
+}
+
+func filterStreamEvents(events []event.EventLogEntry, after event.EventCursor, selectors, regexSelectors []event.Selector,
+	eventSelectors func(*event.EventLogEntry) ([]event.Selector, error)) ([]restModel.APIEventLogEntry, event.EventCursor, error) {
+	out := []restModel.APIEventLogEntry{}
+	last := after
+	for i := range events {
+		last = event.NewEventCursor(&events[i])
+
+		if len(selectors)+len(regexSelectors) > 0 {
+			eventSels, err := eventSelectors(&events[i])
+			if err != nil {
+				grip.Warning(message.WrapError(err, message.Fields{
+					"message":  "could not resolve selectors for streamed event",
+					"event_id": events[i].ID,
+					"r_type":   events[i].ResourceType,
+				}))
+				continue
+			}
+			if !event.SelectorsMatch(eventSels, selectors, regexSelectors) {
+				continue
+			}
+		}
+
+		apiEvent := restModel.APIEventLogEntry{}
+		if err := apiEvent.BuildFromService(events[i]); err != nil {
+			return nil, after, errors.Wrapf(err, "failed to build event '%s'", events[i].ID)
+		}
+		out = append(out, apiEvent)
+	}
+
+	return out, last, nil
+}
+
+// MockEventStreamConnector streams the cached events, which must be in
+// the order they were logged, matching them on their id and object
+// selectors only.
+type MockEventStreamConnector struct {
+	CachedEvents []event.EventLogEntry
+}
+
+func (c *MockEventStreamConnector) FindEventsAfter(after event.EventCursor, resourceTypes []string, selectors, regexSelectors []event.Selector, n int) ([]restModel.APIEventLogEntry, event.EventCursor, error) {
+	events := []event.EventLogEntry{}
+	for i, e := range c.CachedEvents {
+		if len(events) >= n {
+			break
+		}
+		if !after.After(&c.CachedEvents[i]) {
+			continue
+		}
+		for _, resourceType := range resourceTypes {
+			if e.ResourceType == resourceType {
+				events = append(events, e)
+				break
+			}
+		}
+	}
+
+	return filterStreamEvents(events, after, selectors, regexSelectors, mockEventSelectors)
+}
+
+func mockEventSelectors(e *event.EventLogEntry) ([]event.Selector, error) {
+	return []event.Selector{
+		{
+			Type: "id",
+			Data: e.ResourceId,
+		},
+		{
+			Type: "object",
+			Data: strings.ToLower(e.ResourceType),
+		},
+	}, nil
+}
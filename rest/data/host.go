@@ -55,7 +55,8 @@ func (dbc *DBConnector) FindHostByIdWithOwner(hostID string, user gimlet.User) (
 }
 
 // NewIntentHost is a method to insert an intent host given a distro and a public key
-// The public key can be the name of a saved key or the actual key string
+// The public key can be the name of a saved key or the actual key string. It returns
+// an error instead if the user is already running as many spawn hosts as their quota allows.
 func (hc *DBHostConnector) NewIntentHost(distroID, keyNameOrVal, taskID string, user *user.DBUser) (*host.Host, error) {
 	keyVal, err := user.GetPublicKey(keyNameOrVal)
 	if err != nil {
@@ -78,7 +79,7 @@ func (hc *DBHostConnector) NewIntentHost(distroID, keyNameOrVal, taskID string,
 		return nil, err
 	}
 
-	if err := intentHost.Insert(); err != nil {
+	if err := insertSpawnHostWithinQuota(intentHost, user.Username()); err != nil {
 		return nil, err
 	}
 
@@ -30,6 +30,12 @@ type DBConnector struct {
 	DBSubscriptionConnector
 	NotificationConnector
 	DBCreateHostConnector
+	DBEventStreamConnector
+	DBRoleConnector
+	DBServiceAccountConnector
+	DBAuditConnector
+	DBBulkTaskConnector
+	DBQuotaConnector
 }
 
 func (ctx *DBConnector) GetSuperUsers() []string   { return ctx.superUsers }
@@ -65,6 +71,12 @@ type MockConnector struct {
 	MockSubscriptionConnector
 	MockNotificationConnector
 	MockCreateHostConnector
+	MockEventStreamConnector
+	MockRoleConnector
+	MockServiceAccountConnector
+	MockAuditConnector
+	MockBulkTaskConnector
+	MockQuotaConnector
 }
 
 func (ctx *MockConnector) GetSuperUsers() []string   { return ctx.superUsers }
@@ -7,11 +7,13 @@ import (
 
 	"github.com/evergreen-ci/evergreen"
 	"github.com/evergreen-ci/evergreen/model"
+	"github.com/evergreen-ci/evergreen/model/audit"
 	"github.com/evergreen-ci/evergreen/model/build"
 	"github.com/evergreen-ci/evergreen/model/distro"
 	"github.com/evergreen-ci/evergreen/model/event"
 	"github.com/evergreen-ci/evergreen/model/host"
 	"github.com/evergreen-ci/evergreen/model/patch"
+	"github.com/evergreen-ci/evergreen/model/role"
 	"github.com/evergreen-ci/evergreen/model/task"
 	"github.com/evergreen-ci/evergreen/model/testresult"
 	"github.com/evergreen-ci/evergreen/model/user"
@@ -58,6 +60,8 @@ type Connector interface {
 
 	// FindBuildById is a method to find the build matching the same BuildId.
 	FindBuildById(string) (*build.Build, error)
+	// FindBuildsByIds returns the builds with the given ids that exist.
+	FindBuildsByIds([]string) ([]build.Build, error)
 	// SetBuildPriority and SetBuildActivated change the status of the input build
 	SetBuildPriority(string, int64) error
 	SetBuildActivated(string, string, bool) error
@@ -127,6 +131,10 @@ type Connector interface {
 	// FindVersionById returns version given its ID.
 	FindVersionById(string) (*version.Version, error)
 
+	// FindDownstreamVersions returns the versions that downstream triggers
+	// created from the version with the given ID.
+	FindDownstreamVersions(string) ([]version.Version, error)
+
 	// FindPatchesByProject provides access to the patches corresponding to the input project ID
 	// as ordered by creation time.
 	FindPatchesByProject(string, time.Time, int, bool) ([]patch.Patch, error)
@@ -144,6 +152,15 @@ type Connector interface {
 	// AbortPatchesFromPullRequest aborts patches with the same PR Number,
 	// in the same repository, at the pull request's close time
 	AbortPatchesFromPullRequest(*github.PullRequestEvent) error
+	// AbortPatchesFromMergeRequest aborts patches of a GitLab merge request
+	// that were created before it was closed or merged
+	AbortPatchesFromMergeRequest(*patch.GitlabMergeRequestEvent) error
+
+	// RebasePatch creates a copy of the patch with the given ID for the user,
+	// based on the given revision or else the latest mainline revision of its
+	// project, and finalizes it if requested. Patches that are too large to be
+	// checked for conflicts can't be rebased.
+	RebasePatch(context.Context, string, string, string, bool) (*patch.Patch, error)
 
 	// RestartVersion restarts all completed tasks of a version given its ID and the caller.
 	RestartVersion(string, string) error
@@ -174,6 +191,11 @@ type Connector interface {
 
 	AddPublicKey(*user.DBUser, string, string) error
 	DeletePublicKey(*user.DBUser, string) error
+	// AddAPIToken creates a named API token for the user with the given
+	// scopes and expiration, and returns the token.
+	AddAPIToken(*user.DBUser, string, []string, time.Time) (string, error)
+	// DeleteAPIToken revokes the user's API token with the given name.
+	DeleteAPIToken(*user.DBUser, string) error
 	UpdateSettings(*user.DBUser, user.UserSettings) error
 
 	AddPatchIntent(patch.Intent, amboy.Queue) error
@@ -202,10 +224,65 @@ type Connector interface {
 	// GetSubscriptions returns the subscriptions that belong to a user
 	GetSubscriptions(string, event.OwnerType) ([]restModel.APISubscription, error)
 	DeleteSubscription(id string) error
+	// RotateWebhookSecret replaces the secret of a webhook subscription,
+	// keeping the old secret valid for the given grace period
+	RotateWebhookSecret(id string, grace time.Duration) (*restModel.APISubscription, error)
 
 	// Notifications
 	GetNotificationsStats() (*restModel.APIEventStats, error)
 
 	// ListHostsForTask lists running hosts scoped to the task or the task's build.
 	ListHostsForTask(string) ([]host.Host, error)
+
+	// FindEventsAfter returns the events of the given resource types
+	// logged after the given cursor that match the given selectors and
+	// regex selectors, along with the cursor to resume the stream from.
+	FindEventsAfter(event.EventCursor, []string, []event.Selector, []event.Selector, int) ([]restModel.APIEventLogEntry, event.EventCursor, error)
+
+	// GetRoles returns all roles, including the default roles that every
+	// user has.
+	GetRoles() ([]restModel.APIRole, error)
+	// GetRole returns the role with the given id.
+	GetRole(string) (*restModel.APIRole, error)
+	// UpdateRole creates or replaces a role.
+	UpdateRole(role.Role) error
+	// DeleteRole removes a role.
+	DeleteRole(string) error
+	// GetUserRoles returns the ids of a user's roles.
+	GetUserRoles(string) ([]string, error)
+	// UpdateUserRoles adds and removes roles from a user.
+	UpdateUserRoles(string, []string, []string) ([]string, error)
+
+	// GetServiceAccounts returns all service accounts.
+	GetServiceAccounts() ([]user.DBUser, error)
+	// GetServiceAccount returns the service account with the given id.
+	GetServiceAccount(string) (*user.DBUser, error)
+	// CreateServiceAccount creates a service account with the given id,
+	// display name and roles.
+	CreateServiceAccount(string, string, []string) (*user.DBUser, error)
+	// DeleteServiceAccount removes a service account and its tokens.
+	DeleteServiceAccount(string) error
+	// AddServiceAccountToken creates an API token for a service account
+	// with the given name, scopes and expiration, and returns the token.
+	AddServiceAccountToken(string, string, []string, time.Time) (string, error)
+	// DeleteServiceAccountToken revokes a service account's API token.
+	DeleteServiceAccountToken(string, string) error
+
+	// GetAuditLog returns the audit log entries matching the filter,
+	// newest first.
+	GetAuditLog(audit.Filter) ([]audit.Entry, error)
+
+	// FindBulkTasks returns the tasks that a bulk task filter matches.
+	FindBulkTasks(model.BulkTaskFilter) ([]task.Task, error)
+	// StartBulkTaskOperation saves a bulk task operation that applies the
+	// options to the tasks with the given ids, and starts a job on the queue
+	// to apply it.
+	StartBulkTaskOperation(amboy.Queue, model.BulkTaskOptions, []string) (*model.BulkTaskOperation, error)
+	// GetBulkTaskOperation returns the bulk task operation, and its
+	// progress, with the given id.
+	GetBulkTaskOperation(string) (*model.BulkTaskOperation, error)
+
+	// CheckPatchQuota returns an error if the user cannot create another
+	// patch until some of their patches are more than a day old.
+	CheckPatchQuota(string) error
 }
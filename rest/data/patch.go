@@ -1,13 +1,17 @@
 package data
 
 import (
+	"context"
 	"fmt"
 	"net/http"
 	"strings"
 	"time"
 
+	"github.com/evergreen-ci/evergreen"
+	"github.com/evergreen-ci/evergreen/db"
 	"github.com/evergreen-ci/evergreen/model"
 	"github.com/evergreen-ci/evergreen/model/patch"
+	"github.com/evergreen-ci/evergreen/units"
 	"github.com/evergreen-ci/gimlet"
 	"github.com/google/go-github/github"
 	"github.com/pkg/errors"
@@ -107,6 +111,165 @@ func (p *DBPatchConnector) AbortPatchesFromPullRequest(event *github.PullRequest
 	return nil
 }
 
+func (p *DBPatchConnector) AbortPatchesFromMergeRequest(event *patch.GitlabMergeRequestEvent) error {
+	if err := verifyMergeRequestEventForAbort(event); err != nil {
+		return err
+	}
+
+	err := model.AbortPatchesWithGitlabPatchData(time.Now(), event.Project.ID, event.ObjectAttributes.IID)
+	if err != nil {
+		return gimlet.ErrorResponse{
+			StatusCode: http.StatusInternalServerError,
+			Message:    "error aborting patches",
+		}
+	}
+
+	return nil
+}
+
+// RebasePatch creates a copy of a patch, with its changes, variants, tasks,
+// alias, and module patches, that's based on the given revision of the
+// project, or else on the revision of its most recent mainline version. The
+// modules are based on their revisions in that version. If the changes don't
+// apply cleanly to the new revisions, it returns a conflict error describing
+// them instead. Patches that change too many files, or files that are too
+// large, to be checked for conflicts can't be rebased.
+func (pc *DBPatchConnector) RebasePatch(ctx context.Context, patchId, user, base string, finalize bool) (*patch.Patch, error) {
+	p, err := pc.FindPatchById(patchId)
+	if err != nil {
+		return nil, err
+	}
+	pref, err := model.FindOneProjectRef(p.Project)
+	if err != nil {
+		return nil, errors.Wrapf(err, "problem finding project '%s'", p.Project)
+	}
+	if pref == nil {
+		return nil, gimlet.ErrorResponse{
+			StatusCode: http.StatusNotFound,
+			Message:    fmt.Sprintf("project '%s' not found", p.Project),
+		}
+	}
+	if pref.RepoKind == model.GitRepoType {
+		return nil, gimlet.ErrorResponse{
+			StatusCode: http.StatusBadRequest,
+			Message:    fmt.Sprintf("patches can only be rebased for GitHub projects, and '%s' is a git project", pref.Identifier),
+		}
+	}
+
+	token, err := evergreen.GetEnvironment().Settings().GetGithubOauthToken()
+	if err != nil {
+		return nil, errors.Wrap(err, "problem getting github token")
+	}
+
+	project := &model.Project{}
+	if err = model.LoadProjectInto([]byte(p.PatchedConfig), p.Project, project); err != nil {
+		return nil, errors.Wrapf(err, "problem loading the config of patch '%s'", patchId)
+	}
+	revisions, err := model.GetLatestPatchRevisions(ctx, p, project, token)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	if base != "" {
+		revisions[""] = base
+	}
+
+	// check that the changes still apply before creating anything
+	if err = p.FetchPatchFiles(); err != nil {
+		return nil, errors.Wrapf(err, "problem fetching the changes of patch '%s'", patchId)
+	}
+	if err = model.CheckPatchConflicts(ctx, p, project, pref, revisions, token); err != nil {
+		if model.IsPatchConflict(err) {
+			return nil, gimlet.ErrorResponse{
+				StatusCode: http.StatusConflict,
+				Message:    err.Error(),
+			}
+		}
+		if model.IsPatchTooLarge(err) {
+			return nil, gimlet.ErrorResponse{
+				StatusCode: http.StatusBadRequest,
+				Message:    err.Error(),
+			}
+		}
+		return nil, errors.WithStack(err)
+	}
+
+	var changes string
+	for _, modulePatch := range p.Patches {
+		if modulePatch.ModuleName == "" {
+			changes = modulePatch.PatchSet.Patch
+		}
+	}
+
+	// the patch is finalized once its module patches have been added
+	var intent patch.Intent
+	if p.SourceRef != "" {
+		intent, err = patch.NewRefCliIntent(user, p.Project, revisions[""], p.SourceRef, changes, p.Description, false, p.BuildVariants, p.Tasks, p.Alias)
+	} else {
+		intent, err = patch.NewCliIntent(user, p.Project, revisions[""], "", changes, p.Description, false, p.BuildVariants, p.Tasks, p.Alias)
+	}
+	if err != nil {
+		return nil, gimlet.ErrorResponse{
+			StatusCode: http.StatusBadRequest,
+			Message:    err.Error(),
+		}
+	}
+	if err = intent.Insert(); err != nil {
+		return nil, errors.Wrap(err, "problem saving patch intent")
+	}
+
+	patchID := bson.NewObjectId()
+	if err = units.ProcessPatchIntent(ctx, patchID, intent); err != nil {
+		return nil, errors.Wrap(err, "problem processing patch")
+	}
+
+	newPatch, err := patch.FindOne(patch.ById(patchID))
+	if err != nil {
+		return nil, errors.Wrapf(err, "problem finding patch '%s'", patchID.Hex())
+	}
+	if newPatch == nil {
+		return nil, errors.Errorf("patch '%s' wasn't created", patchID.Hex())
+	}
+
+	for _, modulePatch := range p.Patches {
+		if modulePatch.ModuleName == "" {
+			continue
+		}
+		patchFileId := bson.NewObjectId().Hex()
+		if err = db.WriteGridFile(patch.GridFSPrefix, patchFileId, strings.NewReader(modulePatch.PatchSet.Patch)); err != nil {
+			return nil, errors.Wrap(err, "problem writing module patch file")
+		}
+		rebased := patch.ModulePatch{
+			ModuleName: modulePatch.ModuleName,
+			Githash:    revisions[modulePatch.ModuleName],
+			PatchSet: patch.PatchSet{
+				PatchFileId: patchFileId,
+				Summary:     modulePatch.PatchSet.Summary,
+			},
+		}
+		if err = newPatch.UpdateModulePatch(rebased); err != nil {
+			return nil, errors.Wrapf(err, "problem adding module '%s' to patch", modulePatch.ModuleName)
+		}
+		newPatch.Patches = append(newPatch.Patches, rebased)
+	}
+
+	if finalize {
+		if _, err = model.FinalizePatch(ctx, newPatch, evergreen.PatchVersionRequester, token); err != nil {
+			if model.IsPatchConflict(err) {
+				return nil, gimlet.ErrorResponse{
+					StatusCode: http.StatusConflict,
+					Message:    err.Error(),
+				}
+			}
+			return nil, errors.Wrapf(err, "problem finalizing patch '%s'", patchID.Hex())
+		}
+		if newPatch, err = patch.FindOne(patch.ById(patchID)); err != nil {
+			return nil, errors.Wrapf(err, "problem finding patch '%s'", patchID.Hex())
+		}
+	}
+
+	return newPatch, nil
+}
+
 // MockPatchConnector is a struct that implements the Patch related methods
 // from the Connector through interactions with he backing database.
 type MockPatchConnector struct {
@@ -229,11 +392,59 @@ func (hp *MockPatchConnector) FindPatchesByUser(user string, ts time.Time, limit
 	return patchesToReturn, nil
 }
 
+// RebasePatch adds a copy of the cached patch with the given ID, based on
+// the base revision if one is given, to the cached patches.
+func (pc *MockPatchConnector) RebasePatch(ctx context.Context, patchId, user, base string, finalize bool) (*patch.Patch, error) {
+	p, err := pc.FindPatchById(patchId)
+	if err != nil {
+		return nil, err
+	}
+	if base == "" {
+		base = p.Githash
+	}
+
+	rebased := *p
+	rebased.Id = bson.NewObjectId()
+	rebased.Author = user
+	rebased.Githash = base
+	rebased.Activated = finalize
+	rebased.Version = ""
+	rebased.CreateTime = time.Now()
+	rebased.Patches = make([]patch.ModulePatch, len(p.Patches))
+	for i, modulePatch := range p.Patches {
+		rebased.Patches[i] = modulePatch
+		if modulePatch.ModuleName == "" {
+			rebased.Patches[i].Githash = base
+		}
+	}
+	if finalize {
+		rebased.Version = rebased.Id.Hex()
+	}
+	pc.CachedPatches = append(pc.CachedPatches, rebased)
+
+	return &pc.CachedPatches[len(pc.CachedPatches)-1], nil
+}
+
 func (c *MockPatchConnector) AbortPatchesFromPullRequest(event *github.PullRequestEvent) error {
 	_, _, err := verifyPullRequestEventForAbort(event)
 	return err
 }
 
+func (c *MockPatchConnector) AbortPatchesFromMergeRequest(event *patch.GitlabMergeRequestEvent) error {
+	return verifyMergeRequestEventForAbort(event)
+}
+
+func verifyMergeRequestEventForAbort(event *patch.GitlabMergeRequestEvent) error {
+	if event == nil || event.Project.ID == 0 || event.ObjectAttributes.IID == 0 {
+		return gimlet.ErrorResponse{
+			StatusCode: http.StatusBadRequest,
+			Message:    "merge request data is malformed",
+		}
+	}
+
+	return nil
+}
+
 func verifyPullRequestEventForAbort(event *github.PullRequestEvent) (string, string, error) {
 	if event.Number == nil || event.Repo == nil ||
 		event.Repo.FullName == nil || event.PullRequest == nil ||
@@ -18,8 +18,15 @@ type DBPatchIntentConnector struct{}
 
 func (p *DBPatchIntentConnector) AddPatchIntent(intent patch.Intent, queue amboy.Queue) error {
 	patchDoc := intent.NewPatch()
-	projectRef, err := model.FindOneProjectRefByRepoAndBranchWithPRTesting(patchDoc.GithubPatchData.BaseOwner,
-		patchDoc.GithubPatchData.BaseRepo, patchDoc.GithubPatchData.BaseBranch)
+	var projectRef *model.ProjectRef
+	var err error
+	if patchDoc.IsGitlabMRPatch() {
+		projectRef, err = model.FindOneProjectRefByRepoURLAndBranchWithPRTesting(patchDoc.GitlabPatchData.RepoURLs,
+			patchDoc.GitlabPatchData.BaseBranch)
+	} else {
+		projectRef, err = model.FindOneProjectRefByRepoAndBranchWithPRTesting(patchDoc.GithubPatchData.BaseOwner,
+			patchDoc.GithubPatchData.BaseRepo, patchDoc.GithubPatchData.BaseBranch)
+	}
 	if err != nil {
 		return gimlet.ErrorResponse{
 			StatusCode: http.StatusInternalServerError,
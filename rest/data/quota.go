@@ -0,0 +1,126 @@
+package data
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/evergreen-ci/evergreen"
+	"github.com/evergreen-ci/evergreen/db"
+	"github.com/evergreen-ci/evergreen/model/host"
+	"github.com/evergreen-ci/evergreen/model/patch"
+	"github.com/evergreen-ci/evergreen/util"
+	"github.com/evergreen-ci/gimlet"
+	"github.com/pkg/errors"
+	"gopkg.in/mgo.v2/bson"
+)
+
+// patchQuotaWindow is the period that the patch quota applies to.
+const patchQuotaWindow = 24 * time.Hour
+
+// DBQuotaConnector is a struct that implements the quota related methods
+// from the Connector through interactions with the backing database.
+type DBQuotaConnector struct{}
+
+// CheckPatchQuota returns an error if the user has already created as many
+// patches in the last day as the rate limit settings allow.
+func (qc *DBQuotaConnector) CheckPatchQuota(user string) error {
+	return checkPatchQuota(user, time.Now())
+}
+
+func checkPatchQuota(user string, now time.Time) error {
+	config, err := evergreen.GetRateLimitConfig()
+	if err != nil {
+		return errors.Wrap(err, "problem getting rate limit settings")
+	}
+	if config.MaxPatchesPerDay == 0 || config.IsExempt(user) {
+		return nil
+	}
+
+	// the oldest patches in the window are the next to leave it, so the
+	// quota frees up when they do
+	patches, err := patch.Find(db.Query(bson.M{
+		patch.AuthorKey:     user,
+		patch.CreateTimeKey: bson.M{"$gt": now.Add(-patchQuotaWindow)},
+	}).Sort([]string{patch.CreateTimeKey}).Limit(config.MaxPatchesPerDay))
+	if err != nil {
+		return errors.Wrapf(err, "problem finding patches for user '%s'", user)
+	}
+	if len(patches) < config.MaxPatchesPerDay {
+		return nil
+	}
+
+	return gimlet.ErrorResponse{
+		StatusCode: http.StatusTooManyRequests,
+		Message: fmt.Sprintf("user '%s' has already created the maximum of %d patches in the last day, try again after %s",
+			user, config.MaxPatchesPerDay, patches[0].CreateTime.Add(patchQuotaWindow).Format(time.RFC3339)),
+	}
+}
+
+// insertSpawnHostWithinQuota inserts a spawn host for the user unless they're
+// already running as many spawn hosts as the rate limit settings allow.
+// Concurrent requests can all pass the check before any of their hosts are
+// inserted, so the quota is checked again after the insert, counting only the
+// hosts that were created no later than this one, and the host is removed if
+// it's over the quota.
+func insertSpawnHostWithinQuota(h *host.Host, user string) error {
+	config, err := evergreen.GetRateLimitConfig()
+	if err != nil {
+		return errors.Wrap(err, "problem getting rate limit settings")
+	}
+	if config.IsExempt(user) {
+		return errors.Wrapf(h.Insert(), "problem inserting spawn host '%s'", h.Id)
+	}
+
+	count, err := host.Count(host.ByUserWithRunningStatus(user))
+	if err != nil {
+		return errors.Wrapf(err, "problem counting spawn hosts for user '%s'", user)
+	}
+	if count >= config.SpawnHostQuota() {
+		return spawnHostQuotaError(user, config.SpawnHostQuota(), count)
+	}
+
+	if err = h.Insert(); err != nil {
+		return errors.Wrapf(err, "problem inserting spawn host '%s'", h.Id)
+	}
+	count, err = host.Count(db.Query(bson.M{
+		host.StartedByKey:  user,
+		host.StatusKey:     bson.M{"$ne": evergreen.HostTerminated},
+		host.CreateTimeKey: bson.M{"$lte": h.CreationTime},
+	}))
+	if err == nil && count <= config.SpawnHostQuota() {
+		return nil
+	}
+	if removeErr := h.Remove(); removeErr != nil {
+		return errors.Wrapf(removeErr, "problem removing spawn host '%s' after checking the quota", h.Id)
+	}
+	if err != nil {
+		return errors.Wrapf(err, "problem counting spawn hosts for user '%s'", user)
+	}
+
+	return spawnHostQuotaError(user, config.SpawnHostQuota(), count-1)
+}
+
+func spawnHostQuotaError(user string, quota, count int) error {
+	return gimlet.ErrorResponse{
+		StatusCode: http.StatusTooManyRequests,
+		Message: fmt.Sprintf("user '%s' is already running the maximum of %d spawn hosts (%d running), terminate one to spawn another",
+			user, quota, count),
+	}
+}
+
+// MockQuotaConnector is a struct that implements mock versions of the quota
+// related methods for testing. Users in the list are over their quota.
+type MockQuotaConnector struct {
+	OverPatchQuota []string
+}
+
+func (qc *MockQuotaConnector) CheckPatchQuota(user string) error {
+	if util.StringSliceContains(qc.OverPatchQuota, user) {
+		return gimlet.ErrorResponse{
+			StatusCode: http.StatusTooManyRequests,
+			Message:    fmt.Sprintf("user '%s' has already created the maximum number of patches in the last day", user),
+		}
+	}
+	return nil
+}
@@ -0,0 +1,92 @@
+package data
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/evergreen-ci/evergreen"
+	"github.com/evergreen-ci/evergreen/db"
+	"github.com/evergreen-ci/evergreen/model/host"
+	"github.com/evergreen-ci/evergreen/model/patch"
+	"github.com/evergreen-ci/evergreen/testutil"
+	"github.com/evergreen-ci/gimlet"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/mgo.v2/bson"
+)
+
+func TestDBQuotaConnector(t *testing.T) {
+	db.SetGlobalSessionProvider(testutil.TestConfig().SessionFactory())
+	require.NoError(t, db.ClearCollections(evergreen.ConfigCollection, patch.Collection))
+	defer func() {
+		assert.NoError(t, db.ClearCollections(evergreen.ConfigCollection, patch.Collection))
+	}()
+	qc := &DBQuotaConnector{}
+
+	now := time.Now()
+	for _, p := range []patch.Patch{
+		{Id: bson.NewObjectId(), Author: "me", CreateTime: now.Add(-48 * time.Hour)},
+		{Id: bson.NewObjectId(), Author: "me", CreateTime: now.Add(-2 * time.Hour)},
+		{Id: bson.NewObjectId(), Author: "me", CreateTime: now.Add(-time.Hour)},
+		{Id: bson.NewObjectId(), Author: "you", CreateTime: now.Add(-time.Hour)},
+	} {
+		require.NoError(t, p.Insert())
+	}
+
+	// without settings, there is no patch quota
+	assert.NoError(t, qc.CheckPatchQuota("me"))
+
+	config := evergreen.RateLimitConfig{
+		MaxPatchesPerDay: 2,
+	}
+	require.NoError(t, config.Set())
+
+	err := qc.CheckPatchQuota("me")
+	require.Error(t, err)
+	apiErr, ok := err.(gimlet.ErrorResponse)
+	require.True(t, ok)
+	assert.Equal(t, http.StatusTooManyRequests, apiErr.StatusCode)
+	assert.Contains(t, apiErr.Message, now.Add(-2*time.Hour).Add(patchQuotaWindow).Format(time.RFC3339))
+	assert.NoError(t, qc.CheckPatchQuota("you"))
+
+	config.ExemptUsers = []string{"me"}
+	require.NoError(t, config.Set())
+	assert.NoError(t, qc.CheckPatchQuota("me"))
+}
+
+func TestInsertSpawnHostWithinQuota(t *testing.T) {
+	db.SetGlobalSessionProvider(testutil.TestConfig().SessionFactory())
+	require.NoError(t, db.ClearCollections(evergreen.ConfigCollection, host.Collection))
+	defer func() {
+		assert.NoError(t, db.ClearCollections(evergreen.ConfigCollection, host.Collection))
+	}()
+
+	now := time.Now()
+	for _, h := range []host.Host{
+		{Id: "h1", StartedBy: "me", Status: evergreen.HostRunning, CreationTime: now.Add(-time.Hour)},
+		{Id: "h2", StartedBy: "me", Status: evergreen.HostTerminated, CreationTime: now.Add(-time.Hour)},
+	} {
+		require.NoError(t, h.Insert())
+	}
+	config := evergreen.RateLimitConfig{MaxSpawnHostsPerUser: 2}
+	require.NoError(t, config.Set())
+
+	// terminated hosts don't count toward the quota
+	require.NoError(t, insertSpawnHostWithinQuota(&host.Host{Id: "h3", StartedBy: "me", CreationTime: now.Add(-time.Minute)}, "me"))
+
+	err := insertSpawnHostWithinQuota(&host.Host{Id: "h4", StartedBy: "me", CreationTime: now}, "me")
+	require.Error(t, err)
+	apiErr, ok := err.(gimlet.ErrorResponse)
+	require.True(t, ok)
+	assert.Equal(t, http.StatusTooManyRequests, apiErr.StatusCode)
+	h, err := host.FindOne(host.ById("h4"))
+	require.NoError(t, err)
+	assert.Nil(t, h)
+
+	require.NoError(t, insertSpawnHostWithinQuota(&host.Host{Id: "h5", StartedBy: "you", CreationTime: now}, "you"))
+
+	config.ExemptUsers = []string{"me"}
+	require.NoError(t, config.Set())
+	assert.NoError(t, insertSpawnHostWithinQuota(&host.Host{Id: "h8", StartedBy: "me", CreationTime: now}, "me"))
+}
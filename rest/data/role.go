@@ -0,0 +1,233 @@
+package data
+
+import (
+	"fmt"
+	"net/http"
+	"sort"
+
+	"github.com/evergreen-ci/evergreen/model/role"
+	"github.com/evergreen-ci/evergreen/model/user"
+	restModel "github.com/evergreen-ci/evergreen/rest/model"
+	"github.com/evergreen-ci/evergreen/util"
+	"github.com/evergreen-ci/gimlet"
+	"github.com/pkg/errors"
+)
+
+// DBRoleConnector is a struct that implements the Role related methods
+// from the Connector through interactions with the backing database.
+type DBRoleConnector struct{}
+
+// GetRoles returns all roles, including the default roles.
+func (rc *DBRoleConnector) GetRoles() ([]restModel.APIRole, error) {
+	roles, err := role.FindAll()
+	if err != nil {
+		return nil, errors.Wrap(err, "problem finding roles")
+	}
+
+	apiRoles := make([]restModel.APIRole, len(roles))
+	for i := range roles {
+		if err = apiRoles[i].BuildFromService(roles[i]); err != nil {
+			return nil, errors.Wrapf(err, "problem converting role '%s'", roles[i].ID)
+		}
+	}
+
+	return apiRoles, nil
+}
+
+// GetRole returns the role with the given id.
+func (rc *DBRoleConnector) GetRole(id string) (*restModel.APIRole, error) {
+	r, err := role.FindByID(id)
+	if err != nil {
+		return nil, errors.Wrapf(err, "problem finding role '%s'", id)
+	}
+	if r == nil {
+		return nil, gimlet.ErrorResponse{
+			StatusCode: http.StatusNotFound,
+			Message:    fmt.Sprintf("role '%s' not found", id),
+		}
+	}
+
+	apiRole := &restModel.APIRole{}
+	if err = apiRole.BuildFromService(r); err != nil {
+		return nil, errors.Wrapf(err, "problem converting role '%s'", id)
+	}
+
+	return apiRole, nil
+}
+
+// UpdateRole creates the role, or replaces the role with the same id.
+func (rc *DBRoleConnector) UpdateRole(r role.Role) error {
+	if err := r.Validate(); err != nil {
+		return gimlet.ErrorResponse{
+			StatusCode: http.StatusBadRequest,
+			Message:    err.Error(),
+		}
+	}
+
+	return errors.WithStack(r.Upsert())
+}
+
+// DeleteRole removes the role with the given id. Users that have the role
+// keep it in their list of roles, but it no longer grants any permissions.
+func (rc *DBRoleConnector) DeleteRole(id string) error {
+	return errors.WithStack(role.Remove(id))
+}
+
+// GetUserRoles returns the ids of the roles that the user has been given.
+func (rc *DBRoleConnector) GetUserRoles(userID string) ([]string, error) {
+	u, err := findUserForRoles(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	return u.Roles(), nil
+}
+
+// UpdateUserRoles adds and removes roles from the user, and returns the ids
+// of the roles that the user has afterwards.
+func (rc *DBRoleConnector) UpdateUserRoles(userID string, add, remove []string) ([]string, error) {
+	u, err := findUserForRoles(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, id := range add {
+		var r *role.Role
+		r, err = role.FindByID(id)
+		if err != nil {
+			return nil, errors.Wrapf(err, "problem finding role '%s'", id)
+		}
+		if r == nil {
+			return nil, gimlet.ErrorResponse{
+				StatusCode: http.StatusBadRequest,
+				Message:    fmt.Sprintf("role '%s' does not exist", id),
+			}
+		}
+		if err = u.AddRole(id); err != nil {
+			return nil, errors.WithStack(err)
+		}
+	}
+	for _, id := range remove {
+		if err = u.RemoveRole(id); err != nil {
+			return nil, errors.WithStack(err)
+		}
+	}
+
+	return u.Roles(), nil
+}
+
+func findUserForRoles(userID string) (*user.DBUser, error) {
+	u, err := user.FindOne(user.ById(userID))
+	if err != nil {
+		return nil, errors.Wrapf(err, "problem finding user '%s'", userID)
+	}
+	if u == nil {
+		return nil, gimlet.ErrorResponse{
+			StatusCode: http.StatusNotFound,
+			Message:    fmt.Sprintf("user '%s' not found", userID),
+		}
+	}
+
+	return u, nil
+}
+
+// MockRoleConnector is a struct that implements mock versions of
+// Role-related methods for testing.
+type MockRoleConnector struct {
+	CachedRoles     map[string]role.Role
+	CachedUserRoles map[string][]string
+}
+
+func (rc *MockRoleConnector) GetRoles() ([]restModel.APIRole, error) {
+	ids := []string{}
+	for id := range rc.CachedRoles {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+
+	apiRoles := make([]restModel.APIRole, len(ids))
+	for i, id := range ids {
+		if err := apiRoles[i].BuildFromService(rc.CachedRoles[id]); err != nil {
+			return nil, err
+		}
+	}
+
+	return apiRoles, nil
+}
+
+func (rc *MockRoleConnector) GetRole(id string) (*restModel.APIRole, error) {
+	r, ok := rc.CachedRoles[id]
+	if !ok {
+		return nil, gimlet.ErrorResponse{
+			StatusCode: http.StatusNotFound,
+			Message:    fmt.Sprintf("role '%s' not found", id),
+		}
+	}
+
+	apiRole := &restModel.APIRole{}
+	if err := apiRole.BuildFromService(r); err != nil {
+		return nil, err
+	}
+
+	return apiRole, nil
+}
+
+func (rc *MockRoleConnector) UpdateRole(r role.Role) error {
+	if err := r.Validate(); err != nil {
+		return gimlet.ErrorResponse{
+			StatusCode: http.StatusBadRequest,
+			Message:    err.Error(),
+		}
+	}
+	if rc.CachedRoles == nil {
+		rc.CachedRoles = map[string]role.Role{}
+	}
+	rc.CachedRoles[r.ID] = r
+
+	return nil
+}
+
+func (rc *MockRoleConnector) DeleteRole(id string) error {
+	delete(rc.CachedRoles, id)
+	return nil
+}
+
+func (rc *MockRoleConnector) GetUserRoles(userID string) ([]string, error) {
+	roles, ok := rc.CachedUserRoles[userID]
+	if !ok {
+		return nil, gimlet.ErrorResponse{
+			StatusCode: http.StatusNotFound,
+			Message:    fmt.Sprintf("user '%s' not found", userID),
+		}
+	}
+
+	return roles, nil
+}
+
+func (rc *MockRoleConnector) UpdateUserRoles(userID string, add, remove []string) ([]string, error) {
+	roles, err := rc.GetUserRoles(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, id := range add {
+		if _, ok := rc.CachedRoles[id]; !ok {
+			return nil, gimlet.ErrorResponse{
+				StatusCode: http.StatusBadRequest,
+				Message:    fmt.Sprintf("role '%s' does not exist", id),
+			}
+		}
+		if !util.StringSliceContains(roles, id) {
+			roles = append(roles, id)
+		}
+	}
+	updated := []string{}
+	for _, id := range roles {
+		if !util.StringSliceContains(remove, id) {
+			updated = append(updated, id)
+		}
+	}
+	rc.CachedUserRoles[userID] = updated
+
+	return updated, nil
+}
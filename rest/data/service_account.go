@@ -0,0 +1,189 @@
+package data
+
+import (
+	"fmt"
+	"net/http"
+	"sort"
+	"time"
+
+	"github.com/evergreen-ci/evergreen/model/role"
+	"github.com/evergreen-ci/evergreen/model/user"
+	"github.com/evergreen-ci/gimlet"
+	"github.com/pkg/errors"
+)
+
+// DBServiceAccountConnector is a struct that implements the service account
+// related methods from the Connector through interactions with the backing
+// database.
+type DBServiceAccountConnector struct{}
+
+// GetServiceAccounts returns all service accounts.
+func (sac *DBServiceAccountConnector) GetServiceAccounts() ([]user.DBUser, error) {
+	accounts, err := user.FindServiceAccounts()
+	return accounts, errors.WithStack(err)
+}
+
+// GetServiceAccount returns the service account with the given id.
+func (sac *DBServiceAccountConnector) GetServiceAccount(id string) (*user.DBUser, error) {
+	u, err := user.FindOne(user.ById(id))
+	if err != nil {
+		return nil, errors.Wrapf(err, "problem finding service account '%s'", id)
+	}
+	if u == nil || !u.IsServiceAccount() {
+		return nil, gimlet.ErrorResponse{
+			StatusCode: http.StatusNotFound,
+			Message:    fmt.Sprintf("service account '%s' not found", id),
+		}
+	}
+
+	return u, nil
+}
+
+// CreateServiceAccount creates a service account with the given roles,
+// which must exist.
+func (sac *DBServiceAccountConnector) CreateServiceAccount(id, displayName string, roles []string) (*user.DBUser, error) {
+	for _, roleID := range roles {
+		r, err := role.FindByID(roleID)
+		if err != nil {
+			return nil, errors.Wrapf(err, "problem finding role '%s'", roleID)
+		}
+		if r == nil {
+			return nil, gimlet.ErrorResponse{
+				StatusCode: http.StatusBadRequest,
+				Message:    fmt.Sprintf("role '%s' does not exist", roleID),
+			}
+		}
+	}
+
+	u, err := user.NewServiceAccount(id, displayName, roles)
+	if err != nil {
+		return nil, gimlet.ErrorResponse{
+			StatusCode: http.StatusBadRequest,
+			Message:    err.Error(),
+		}
+	}
+
+	return u, nil
+}
+
+// DeleteServiceAccount removes the service account, which revokes its
+// tokens.
+func (sac *DBServiceAccountConnector) DeleteServiceAccount(id string) error {
+	if _, err := sac.GetServiceAccount(id); err != nil {
+		return err
+	}
+
+	return errors.WithStack(user.RemoveServiceAccount(id))
+}
+
+// AddServiceAccountToken creates an API token for the service account, and
+// returns the token.
+func (sac *DBServiceAccountConnector) AddServiceAccountToken(id, name string, scopes []string, expiresAt time.Time) (string, error) {
+	u, err := sac.GetServiceAccount(id)
+	if err != nil {
+		return "", err
+	}
+
+	return (&DBUserConnector{}).AddAPIToken(u, name, scopes, expiresAt)
+}
+
+// DeleteServiceAccountToken revokes one of the service account's tokens.
+func (sac *DBServiceAccountConnector) DeleteServiceAccountToken(id, name string) error {
+	u, err := sac.GetServiceAccount(id)
+	if err != nil {
+		return err
+	}
+
+	return (&DBUserConnector{}).DeleteAPIToken(u, name)
+}
+
+// MockServiceAccountConnector is a struct that implements mock versions of
+// the service account related methods for testing.
+type MockServiceAccountConnector struct {
+	CachedServiceAccounts map[string]*user.DBUser
+}
+
+func (sac *MockServiceAccountConnector) GetServiceAccounts() ([]user.DBUser, error) {
+	ids := []string{}
+	for id := range sac.CachedServiceAccounts {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+
+	accounts := []user.DBUser{}
+	for _, id := range ids {
+		accounts = append(accounts, *sac.CachedServiceAccounts[id])
+	}
+
+	return accounts, nil
+}
+
+func (sac *MockServiceAccountConnector) GetServiceAccount(id string) (*user.DBUser, error) {
+	u, ok := sac.CachedServiceAccounts[id]
+	if !ok {
+		return nil, gimlet.ErrorResponse{
+			StatusCode: http.StatusNotFound,
+			Message:    fmt.Sprintf("service account '%s' not found", id),
+		}
+	}
+
+	return u, nil
+}
+
+func (sac *MockServiceAccountConnector) CreateServiceAccount(id, displayName string, roles []string) (*user.DBUser, error) {
+	if !user.IsServiceAccountID(id) {
+		return nil, gimlet.ErrorResponse{
+			StatusCode: http.StatusBadRequest,
+			Message:    fmt.Sprintf("service account id '%s' must start with '%s'", id, user.ServiceAccountPrefix),
+		}
+	}
+	if _, ok := sac.CachedServiceAccounts[id]; ok {
+		return nil, gimlet.ErrorResponse{
+			StatusCode: http.StatusBadRequest,
+			Message:    fmt.Sprintf("user '%s' already exists", id),
+		}
+	}
+	if sac.CachedServiceAccounts == nil {
+		sac.CachedServiceAccounts = map[string]*user.DBUser{}
+	}
+
+	u := &user.DBUser{
+		Id:             id,
+		DispName:       displayName,
+		SystemRoles:    roles,
+		ServiceAccount: true,
+		CreatedAt:      time.Now(),
+	}
+	sac.CachedServiceAccounts[id] = u
+
+	return u, nil
+}
+
+func (sac *MockServiceAccountConnector) DeleteServiceAccount(id string) error {
+	if _, err := sac.GetServiceAccount(id); err != nil {
+		return err
+	}
+	delete(sac.CachedServiceAccounts, id)
+
+	return nil
+}
+
+func (sac *MockServiceAccountConnector) AddServiceAccountToken(id, name string, scopes []string, expiresAt time.Time) (string, error) {
+	u, err := sac.GetServiceAccount(id)
+	if err != nil {
+		return "", err
+	}
+
+	users := &MockUserConnector{CachedUsers: map[string]*user.DBUser{id: u}}
+	return users.AddAPIToken(u, name, scopes, expiresAt)
+}
+
+func (sac *MockServiceAccountConnector) DeleteServiceAccountToken(id, name string) error {
+	u, err := sac.GetServiceAccount(id)
+	if err != nil {
+		return err
+	}
+
+	users := &MockUserConnector{CachedUsers: map[string]*user.DBUser{id: u}}
+	return users.DeleteAPIToken(u, name)
+}
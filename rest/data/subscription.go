@@ -2,9 +2,11 @@ package data
 
 import (
 	"net/http"
+	"time"
 
 	"github.com/evergreen-ci/evergreen/model/event"
 	restModel "github.com/evergreen-ci/evergreen/rest/model"
+	"github.com/evergreen-ci/evergreen/util"
 	"github.com/evergreen-ci/gimlet"
 	"github.com/mongodb/grip"
 	"github.com/pkg/errors"
@@ -49,6 +51,20 @@ func (dc *DBSubscriptionConnector) DeleteSubscription(id string) error {
 	return event.RemoveSubscription(id)
 }
 
+func (dc *DBSubscriptionConnector) RotateWebhookSecret(id string, grace time.Duration) (*restModel.APISubscription, error) {
+	sub, err := event.RotateWebhookSecret(id, grace)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to rotate webhook secret")
+	}
+
+	apiSub := &restModel.APISubscription{}
+	if err = apiSub.BuildFromService(*sub); err != nil {
+		return nil, errors.Wrap(err, "failed to marshal subscription")
+	}
+
+	return apiSub, nil
+}
+
 type MockSubscriptionConnector struct {
 	MockSubscriptions []event.Subscription
 }
@@ -64,3 +80,24 @@ func (mc *MockSubscriptionConnector) SaveSubscriptions(subscriptions []event.Sub
 func (dc *MockSubscriptionConnector) DeleteSubscription(id string) error {
 	return errors.New("MockSubscriptionConnector unimplemented")
 }
+
+func (mc *MockSubscriptionConnector) RotateWebhookSecret(id string, grace time.Duration) (*restModel.APISubscription, error) {
+	for i := range mc.MockSubscriptions {
+		if mc.MockSubscriptions[i].ID != id {
+			continue
+		}
+		webhook, ok := mc.MockSubscriptions[i].Subscriber.Target.(*event.WebhookSubscriber)
+		if !ok {
+			return nil, errors.Errorf("subscription '%s' is not a webhook subscription", id)
+		}
+		webhook.RotateSecret([]byte(util.RandomString()), grace, time.Now())
+
+		apiSub := &restModel.APISubscription{}
+		if err := apiSub.BuildFromService(mc.MockSubscriptions[i]); err != nil {
+			return nil, err
+		}
+		return apiSub, nil
+	}
+
+	return nil, errors.Errorf("subscription '%s' not found", id)
+}
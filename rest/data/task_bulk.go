@@ -0,0 +1,109 @@
+package data
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/evergreen-ci/evergreen/model"
+	"github.com/evergreen-ci/evergreen/model/task"
+	"github.com/evergreen-ci/evergreen/units"
+	"github.com/evergreen-ci/gimlet"
+	"github.com/mongodb/amboy"
+	"github.com/pkg/errors"
+)
+
+// DBBulkTaskConnector is a struct that implements the bulk task operation
+// related methods from the Connector through interactions with the backing
+// database.
+type DBBulkTaskConnector struct{}
+
+// FindBulkTasks returns the tasks that the filter matches.
+func (bc *DBBulkTaskConnector) FindBulkTasks(f model.BulkTaskFilter) ([]task.Task, error) {
+	if err := f.Validate(); err != nil {
+		return nil, gimlet.ErrorResponse{
+			StatusCode: http.StatusBadRequest,
+			Message:    err.Error(),
+		}
+	}
+
+	tasks, err := model.FindBulkTasks(f)
+	return tasks, errors.WithStack(err)
+}
+
+// StartBulkTaskOperation saves an operation that applies the options to the
+// tasks, and starts a job to apply it.
+func (bc *DBBulkTaskConnector) StartBulkTaskOperation(queue amboy.Queue, opts model.BulkTaskOptions, taskIDs []string) (*model.BulkTaskOperation, error) {
+	op := model.NewBulkTaskOperation(units.NewBulkTaskOperationJobID(), opts, taskIDs)
+	if err := op.Insert(); err != nil {
+		return nil, err
+	}
+	if err := queue.Put(units.NewBulkTaskOperationJob(op.ID)); err != nil {
+		return nil, errors.Wrap(err, "problem starting background job for bulk task operation")
+	}
+
+	return op, nil
+}
+
+// GetBulkTaskOperation returns the bulk task operation with the given id.
+func (bc *DBBulkTaskConnector) GetBulkTaskOperation(id string) (*model.BulkTaskOperation, error) {
+	op, err := model.FindBulkTaskOperation(id)
+	if err != nil {
+		return nil, err
+	}
+	if op == nil {
+		return nil, gimlet.ErrorResponse{
+			StatusCode: http.StatusNotFound,
+			Message:    fmt.Sprintf("bulk task operation '%s' not found", id),
+		}
+	}
+
+	return op, nil
+}
+
+// MockBulkTaskConnector is a struct that implements mock versions of the
+// bulk task operation related methods for testing. Operations are saved,
+// but not run.
+type MockBulkTaskConnector struct {
+	CachedTasks      []task.Task
+	CachedOperations map[string]*model.BulkTaskOperation
+}
+
+func (bc *MockBulkTaskConnector) FindBulkTasks(f model.BulkTaskFilter) ([]task.Task, error) {
+	if err := f.Validate(); err != nil {
+		return nil, gimlet.ErrorResponse{
+			StatusCode: http.StatusBadRequest,
+			Message:    err.Error(),
+		}
+	}
+
+	tasks := []task.Task{}
+	for i := range bc.CachedTasks {
+		if f.Matches(&bc.CachedTasks[i]) {
+			tasks = append(tasks, bc.CachedTasks[i])
+		}
+	}
+
+	return tasks, nil
+}
+
+func (bc *MockBulkTaskConnector) StartBulkTaskOperation(_ amboy.Queue, opts model.BulkTaskOptions, taskIDs []string) (*model.BulkTaskOperation, error) {
+	if bc.CachedOperations == nil {
+		bc.CachedOperations = map[string]*model.BulkTaskOperation{}
+	}
+	op := model.NewBulkTaskOperation(units.NewBulkTaskOperationJobID(), opts, taskIDs)
+	bc.CachedOperations[op.ID] = op
+
+	return op, nil
+}
+
+func (bc *MockBulkTaskConnector) GetBulkTaskOperation(id string) (*model.BulkTaskOperation, error) {
+	op, ok := bc.CachedOperations[id]
+	if !ok {
+		return nil, gimlet.ErrorResponse{
+			StatusCode: http.StatusNotFound,
+			Message:    fmt.Sprintf("bulk task operation '%s' not found", id),
+		}
+	}
+
+	return op, nil
+}
@@ -35,6 +35,36 @@ func (u *DBUserConnector) DeletePublicKey(user *user.DBUser, keyName string) err
 	return user.DeletePublicKey(keyName)
 }
 
+func (u *DBUserConnector) AddAPIToken(dbUser *user.DBUser, name string, scopes []string, expiresAt time.Time) (string, error) {
+	if dbUser.GetAPIToken(name) != nil {
+		return "", gimlet.ErrorResponse{
+			StatusCode: http.StatusBadRequest,
+			Message:    fmt.Sprintf("a token named '%s' already exists", name),
+		}
+	}
+
+	token, err := dbUser.AddAPIToken(name, scopes, expiresAt)
+	if err != nil {
+		return "", gimlet.ErrorResponse{
+			StatusCode: http.StatusBadRequest,
+			Message:    err.Error(),
+		}
+	}
+
+	return token, nil
+}
+
+func (u *DBUserConnector) DeleteAPIToken(dbUser *user.DBUser, name string) error {
+	if dbUser.GetAPIToken(name) == nil {
+		return gimlet.ErrorResponse{
+			StatusCode: http.StatusNotFound,
+			Message:    fmt.Sprintf("token '%s' not found", name),
+		}
+	}
+
+	return dbUser.DeleteAPIToken(name)
+}
+
 func (u *DBUserConnector) UpdateSettings(dbUser *user.DBUser, settings user.UserSettings) error {
 	if strings.HasPrefix(settings.SlackUsername, "#") {
 		return gimlet.ErrorResponse{
@@ -178,6 +208,60 @@ func (muc *MockUserConnector) DeletePublicKey(u *user.DBUser, keyName string) er
 	return nil
 }
 
+func (muc *MockUserConnector) AddAPIToken(dbUser *user.DBUser, name string, scopes []string, expiresAt time.Time) (string, error) {
+	u, ok := muc.CachedUsers[dbUser.Id]
+	if !ok {
+		return "", errors.Errorf("User '%s' doesn't exist", dbUser.Id)
+	}
+	if u.GetAPIToken(name) != nil {
+		return "", gimlet.ErrorResponse{
+			StatusCode: http.StatusBadRequest,
+			Message:    fmt.Sprintf("a token named '%s' already exists", name),
+		}
+	}
+
+	t := user.APIToken{
+		Name:      name,
+		Scopes:    scopes,
+		CreatedAt: time.Now(),
+		ExpiresAt: expiresAt,
+	}
+	if err := t.Validate(t.CreatedAt); err != nil {
+		return "", gimlet.ErrorResponse{
+			StatusCode: http.StatusBadRequest,
+			Message:    err.Error(),
+		}
+	}
+	token := user.APITokenPrefix + name
+	t.Hash = user.HashAPIToken(token)
+	u.APITokens = append(u.APITokens, t)
+
+	return token, nil
+}
+
+func (muc *MockUserConnector) DeleteAPIToken(dbUser *user.DBUser, name string) error {
+	u, ok := muc.CachedUsers[dbUser.Id]
+	if !ok {
+		return errors.Errorf("User '%s' doesn't exist", dbUser.Id)
+	}
+
+	tokens := []user.APIToken{}
+	for _, t := range u.APITokens {
+		if t.Name != name {
+			tokens = append(tokens, t)
+		}
+	}
+	if len(tokens) == len(u.APITokens) {
+		return gimlet.ErrorResponse{
+			StatusCode: http.StatusNotFound,
+			Message:    fmt.Sprintf("token '%s' not found", name),
+		}
+	}
+	u.APITokens = tokens
+
+	return nil
+}
+
 func (muc *MockUserConnector) UpdateSettings(user *user.DBUser, settings user.UserSettings) error {
 	return errors.New("UpdateSettings not implemented for mock connector")
 }
@@ -58,6 +58,12 @@ func (vc *DBVersionConnector) FindVersionById(versionId string) (*version.Versio
 	return v, nil
 }
 
+// FindDownstreamVersions queries the backing database for the versions that
+// downstream triggers created from the version with the given versionId.
+func (vc *DBVersionConnector) FindDownstreamVersions(versionId string) ([]version.Version, error) {
+	return version.Find(version.ByTriggerID(versionId))
+}
+
 // AbortVersion aborts all tasks of a version given its ID.
 // It wraps the service level AbortVersion.
 func (vc *DBVersionConnector) AbortVersion(versionId, caller string) error {
@@ -351,6 +357,19 @@ func (mvc *MockVersionConnector) FindVersionById(versionId string) (*version.Ver
 	}
 }
 
+// FindDownstreamVersions is the mock implementation of the function for the
+// Connector interface, returning the cached versions created by triggers of
+// the given versionId.
+func (mvc *MockVersionConnector) FindDownstreamVersions(versionId string) ([]version.Version, error) {
+	versions := []version.Version{}
+	for _, v := range mvc.CachedVersions {
+		if v.TriggerID == versionId {
+			versions = append(versions, v)
+		}
+	}
+	return versions, nil
+}
+
 // AbortVersion aborts all tasks of a version given its ID. Specifically, it sets the
 // Aborted key of the tasks to true if they are currently in abortable statuses.
 func (mvc *MockVersionConnector) AbortVersion(versionId, caller string) error {
@@ -0,0 +1,465 @@
+package graphql
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strings"
+	"sync"
+
+	"github.com/evergreen-ci/evergreen/util"
+	"github.com/pkg/errors"
+)
+
+// Schema describes the types that queries can select fields from.
+type Schema struct {
+	Query *Object
+}
+
+// Object is a type with fields that can be selected. Besides the fields it
+// defines, an object whose Model is set also has a field for each of the
+// model's JSON fields, which are resolved from the JSON form of the source.
+type Object struct {
+	Name   string
+	Model  interface{}
+	Fields map[string]*Field
+
+	jsonOnce   sync.Once
+	jsonFields map[string]bool
+}
+
+// Field is a field of an object. Fields without a type are leaves, whose
+// values are returned as they are marshalled to JSON.
+type Field struct {
+	Type    *Object
+	Args    []string
+	Resolve ResolveFunc
+}
+
+// ResolveFunc returns the value of a field of the source object.
+type ResolveFunc func(ctx context.Context, source interface{}, args Args) (interface{}, error)
+
+// Args are the arguments given to a field, with variables substituted.
+type Args map[string]interface{}
+
+// String returns the string argument, or the empty string if it isn't
+// given.
+func (a Args) String(name string) (string, error) {
+	switch v := a[name].(type) {
+	case nil:
+		return "", nil
+	case string:
+		return v, nil
+	case EnumValue:
+		return string(v), nil
+	default:
+		return "", errors.Errorf("argument '%s' must be a string", name)
+	}
+}
+
+// Int returns the integer argument, or the default if it isn't given.
+func (a Args) Int(name string, defaultValue int) (int, error) {
+	switch v := a[name].(type) {
+	case nil:
+		return defaultValue, nil
+	case int64:
+		return int(v), nil
+	case int:
+		return v, nil
+	case float64:
+		if v != float64(int(v)) {
+			return 0, errors.Errorf("argument '%s' must be an integer", name)
+		}
+		return int(v), nil
+	case json.Number:
+		i, err := v.Int64()
+		if err != nil {
+			return 0, errors.Errorf("argument '%s' must be an integer", name)
+		}
+		return int(i), nil
+	default:
+		return 0, errors.Errorf("argument '%s' must be an integer", name)
+	}
+}
+
+// Error is an error reported in a response.
+type Error struct {
+	Message   string        `json:"message"`
+	Locations []Location    `json:"locations,omitempty"`
+	Path      []interface{} `json:"path,omitempty"`
+}
+
+func (e *Error) Error() string { return e.Message }
+
+// Response is the result of executing a request.
+type Response struct {
+	Data   interface{} `json:"data"`
+	Errors []*Error    `json:"errors,omitempty"`
+}
+
+// Request is a GraphQL request, as sent over HTTP.
+type Request struct {
+	Query         string                 `json:"query"`
+	OperationName string                 `json:"operationName"`
+	Variables     map[string]interface{} `json:"variables"`
+}
+
+// Execute runs the named operation, or the only operation, of the
+// document. Errors resolving fields are reported in the response, and
+// those fields are null. Only queries are supported.
+func (s *Schema) Execute(ctx context.Context, doc *Document, operationName string, variables map[string]interface{}) *Response {
+	op, err := selectOperation(doc, operationName)
+	if err != nil {
+		return &Response{Errors: []*Error{{Message: err.Error()}}}
+	}
+	if op.Type != "query" {
+		return &Response{Errors: []*Error{{Message: fmt.Sprintf("%s operations are not supported", op.Type)}}}
+	}
+
+	e := &executor{
+		schema:    s,
+		doc:       doc,
+		variables: map[string]interface{}{},
+	}
+	for _, def := range op.Variables {
+		v, ok := variables[def.Name]
+		switch {
+		case ok:
+			e.variables[def.Name] = v
+		case def.HasDefault:
+			e.variables[def.Name] = def.Default
+		case def.Required():
+			return &Response{Errors: []*Error{{Message: fmt.Sprintf("variable '$%s' of type '%s' is required", def.Name, def.Type)}}}
+		}
+	}
+
+	data := e.executeSelections(ctx, s.Query, nil, op.Selections, nil)
+	return &Response{Data: data, Errors: e.errors}
+}
+
+func selectOperation(doc *Document, name string) (*Operation, error) {
+	if name == "" {
+		if len(doc.Operations) != 1 {
+			return nil, errors.New("must specify the name of the operation to execute")
+		}
+		return doc.Operations[0], nil
+	}
+	for _, op := range doc.Operations {
+		if op.Name == name {
+			return op, nil
+		}
+	}
+
+	return nil, errors.Errorf("operation '%s' not found", name)
+}
+
+type executor struct {
+	schema    *Schema
+	doc       *Document
+	variables map[string]interface{}
+	errors    []*Error
+}
+
+func (e *executor) addError(err error, s Selection, path []interface{}) {
+	e.errors = append(e.errors, &Error{
+		Message:   err.Error(),
+		Locations: []Location{s.Location},
+		Path:      append([]interface{}{}, path...),
+	})
+}
+
+// orderedMap is a JSON object whose keys keep the order of the selections
+// that produced them.
+type orderedMap struct {
+	keys   []string
+	values map[string]interface{}
+}
+
+func (m *orderedMap) MarshalJSON() ([]byte, error) {
+	buf := bytes.Buffer{}
+	buf.WriteByte('{')
+	for i, key := range m.keys {
+		if i > 0 {
+			buf.WriteByte(',')
+		}
+		k, err := json.Marshal(key)
+		if err != nil {
+			return nil, err
+		}
+		v, err := json.Marshal(m.values[key])
+		if err != nil {
+			return nil, err
+		}
+		buf.Write(k)
+		buf.WriteByte(':')
+		buf.Write(v)
+	}
+	buf.WriteByte('}')
+
+	return buf.Bytes(), nil
+}
+
+// collectFields groups the fields selected from an object by their
+// response key, expanding fragments and applying directives.
+func (e *executor) collectFields(object string, selections []Selection, keys *[]string, fields map[string][]Selection, visited map[string]bool) error {
+	for _, s := range selections {
+		include, err := e.shouldInclude(s.Directives)
+		if err != nil {
+			return err
+		}
+		if !include {
+			continue
+		}
+
+		switch {
+		case s.IsFragmentSpread():
+			if visited[s.FragmentName] {
+				continue
+			}
+			visited[s.FragmentName] = true
+			f, ok := e.doc.Fragments[s.FragmentName]
+			if !ok {
+				return errors.Errorf("fragment '%s' is not defined", s.FragmentName)
+			}
+			if f.TypeCondition != object {
+				continue
+			}
+			if err = e.collectFields(object, f.Selections, keys, fields, visited); err != nil {
+				return err
+			}
+		case s.IsInlineFragment():
+			if s.TypeCondition != "" && s.TypeCondition != object {
+				continue
+			}
+			if err = e.collectFields(object, s.Selections, keys, fields, visited); err != nil {
+				return err
+			}
+		default:
+			key := s.ResponseKey()
+			if _, ok := fields[key]; !ok {
+				*keys = append(*keys, key)
+			}
+			fields[key] = append(fields[key], s)
+		}
+	}
+
+	return nil
+}
+
+func (e *executor) shouldInclude(directives []Directive) (bool, error) {
+	for _, d := range directives {
+		if d.Name != "skip" && d.Name != "include" {
+			continue
+		}
+		cond, ok := e.resolveValue(d.Arguments["if"]).(bool)
+		if !ok {
+			return false, errors.Errorf("directive '@%s' requires a boolean 'if' argument", d.Name)
+		}
+		if (d.Name == "skip") == cond {
+			return false, nil
+		}
+	}
+
+	return true, nil
+}
+
+func (e *executor) resolveValue(v Value) interface{} {
+	switch val := v.(type) {
+	case Variable:
+		return e.variables[string(val)]
+	case []Value:
+		out := make([]interface{}, len(val))
+		for i := range val {
+			out[i] = e.resolveValue(val[i])
+		}
+		return out
+	case map[string]Value:
+		out := map[string]interface{}{}
+		for k := range val {
+			out[k] = e.resolveValue(val[k])
+		}
+		return out
+	default:
+		return v
+	}
+}
+
+func (e *executor) executeSelections(ctx context.Context, object *Object, source interface{}, selections []Selection, path []interface{}) interface{} {
+	keys := []string{}
+	fields := map[string][]Selection{}
+	if err := e.collectFields(object.Name, selections, &keys, fields, map[string]bool{}); err != nil {
+		e.addError(err, selections[0], path)
+		return nil
+	}
+
+	var doc map[string]interface{}
+	result := &orderedMap{keys: keys, values: map[string]interface{}{}}
+	for _, key := range keys {
+		s := fields[key][0]
+		subselections := []Selection{}
+		for _, f := range fields[key] {
+			subselections = append(subselections, f.Selections...)
+		}
+		fieldPath := append(append([]interface{}{}, path...), key)
+
+		if s.Name == "__typename" {
+			result.values[key] = object.Name
+			continue
+		}
+
+		field, ok := object.Fields[s.Name]
+		if !ok {
+			if !object.hasJSONField(s.Name) {
+				e.addError(errors.Errorf("type '%s' has no field '%s'", object.Name, s.Name), s, fieldPath)
+				result.values[key] = nil
+				continue
+			}
+			if doc == nil {
+				var err error
+				if doc, err = toJSONDocument(source); err != nil {
+					e.addError(err, s, fieldPath)
+					result.values[key] = nil
+					continue
+				}
+			}
+			result.values[key] = e.completeValue(ctx, nil, s, subselections, doc[s.Name], fieldPath)
+			continue
+		}
+
+		args := Args{}
+		for name, v := range s.Arguments {
+			if !util.StringSliceContains(field.Args, name) {
+				e.addError(errors.Errorf("field '%s' of type '%s' has no argument '%s'", s.Name, object.Name, name), s, fieldPath)
+				args = nil
+				break
+			}
+			args[name] = e.resolveValue(v)
+		}
+		if args == nil {
+			result.values[key] = nil
+			continue
+		}
+
+		value, err := field.Resolve(ctx, source, args)
+		if err != nil {
+			e.addError(err, s, fieldPath)
+			result.values[key] = nil
+			continue
+		}
+		result.values[key] = e.completeValue(ctx, field.Type, s, subselections, value, fieldPath)
+	}
+
+	return result
+}
+
+func (e *executor) completeValue(ctx context.Context, object *Object, s Selection, selections []Selection, value interface{}, path []interface{}) interface{} {
+	if isNil(value) {
+		return nil
+	}
+
+	v := reflect.ValueOf(value)
+	if (v.Kind() == reflect.Slice && v.Type().Elem().Kind() != reflect.Uint8) || v.Kind() == reflect.Array {
+		out := make([]interface{}, v.Len())
+		for i := range out {
+			out[i] = e.completeValue(ctx, object, s, selections, v.Index(i).Interface(), append(append([]interface{}{}, path...), i))
+		}
+		return out
+	}
+
+	if object != nil {
+		if len(selections) == 0 {
+			e.addError(errors.Errorf("field '%s' of type '%s' must have a selection of subfields", s.Name, object.Name), s, path)
+			return nil
+		}
+		return e.executeSelections(ctx, object, value, selections, path)
+	}
+
+	if len(selections) == 0 {
+		return value
+	}
+
+	// fields of JSON objects can be selected like fields of objects
+	doc, ok := value.(map[string]interface{})
+	if !ok {
+		e.addError(errors.Errorf("field '%s' is a scalar and cannot have a selection of subfields", s.Name), s, path)
+		return nil
+	}
+	keys := []string{}
+	fields := map[string][]Selection{}
+	if err := e.collectFields("", selections, &keys, fields, map[string]bool{}); err != nil {
+		e.addError(err, s, path)
+		return nil
+	}
+	result := &orderedMap{keys: keys, values: map[string]interface{}{}}
+	for _, key := range keys {
+		sub := fields[key][0]
+		subselections := []Selection{}
+		for _, f := range fields[key] {
+			subselections = append(subselections, f.Selections...)
+		}
+		result.values[key] = e.completeValue(ctx, nil, sub, subselections, doc[sub.Name], append(append([]interface{}{}, path...), key))
+	}
+
+	return result
+}
+
+func (o *Object) hasJSONField(name string) bool {
+	if o.Model == nil {
+		return false
+	}
+
+	o.jsonOnce.Do(func() {
+		o.jsonFields = map[string]bool{}
+		t := reflect.TypeOf(o.Model)
+		for t.Kind() == reflect.Ptr {
+			t = t.Elem()
+		}
+		if t.Kind() != reflect.Struct {
+			return
+		}
+		for i := 0; i < t.NumField(); i++ {
+			f := t.Field(i)
+			if f.PkgPath != "" {
+				continue
+			}
+			tag := strings.Split(f.Tag.Get("json"), ",")[0]
+			switch tag {
+			case "-":
+			case "":
+				o.jsonFields[f.Name] = true
+			default:
+				o.jsonFields[tag] = true
+			}
+		}
+	})
+
+	return o.jsonFields[name]
+}
+
+func toJSONDocument(source interface{}) (map[string]interface{}, error) {
+	out, err := json.Marshal(source)
+	if err != nil {
+		return nil, errors.Wrap(err, "problem marshalling object")
+	}
+	doc := map[string]interface{}{}
+	if err = json.Unmarshal(out, &doc); err != nil {
+		return nil, errors.Wrap(err, "problem reading object")
+	}
+
+	return doc, nil
+}
+
+func isNil(value interface{}) bool {
+	if value == nil {
+		return true
+	}
+	v := reflect.ValueOf(value)
+	switch v.Kind() {
+	case reflect.Ptr, reflect.Map, reflect.Slice, reflect.Interface:
+		return v.IsNil()
+	}
+
+	return false
+}
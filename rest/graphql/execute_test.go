@@ -0,0 +1,183 @@
+package graphql
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type testBook struct {
+	ID     string            `json:"id"`
+	Title  string            `json:"title"`
+	Secret string            `json:"-"`
+	Meta   map[string]string `json:"meta"`
+	Author string
+}
+
+func testSchema() *Schema {
+	books := map[string]*testBook{
+		"b1": {ID: "b1", Title: "One", Meta: map[string]string{"isbn": "1", "lang": "en"}, Author: "a"},
+		"b2": {ID: "b2", Title: "Two", Author: "b"},
+	}
+	book := &Object{Name: "Book", Model: testBook{}}
+	book.Fields = map[string]*Field{
+		"sequel": {
+			Type: book,
+			Resolve: func(ctx context.Context, source interface{}, args Args) (interface{}, error) {
+				if source.(*testBook).ID == "b1" {
+					return books["b2"], nil
+				}
+				return (*testBook)(nil), nil
+			},
+		},
+		"broken": {
+			Resolve: func(ctx context.Context, source interface{}, args Args) (interface{}, error) {
+				return nil, errors.New("broken")
+			},
+		},
+	}
+
+	return &Schema{Query: &Object{
+		Name: "Query",
+		Fields: map[string]*Field{
+			"book": {
+				Type: book,
+				Args: []string{"id"},
+				Resolve: func(ctx context.Context, source interface{}, args Args) (interface{}, error) {
+					id, err := args.String("id")
+					if err != nil {
+						return nil, err
+					}
+					return books[id], nil
+				},
+			},
+			"books": {
+				Type: book,
+				Args: []string{"limit"},
+				Resolve: func(ctx context.Context, source interface{}, args Args) (interface{}, error) {
+					limit, err := args.Int("limit", 10)
+					if err != nil {
+						return nil, err
+					}
+					out := []*testBook{books["b1"], books["b2"]}
+					if limit < len(out) {
+						out = out[:limit]
+					}
+					return out, nil
+				},
+			},
+		},
+	}}
+}
+
+func execute(t *testing.T, query, operationName string, variables map[string]interface{}) (string, []*Error) {
+	doc, err := Parse(query)
+	require.NoError(t, err)
+	resp := testSchema().Execute(context.Background(), doc, operationName, variables)
+	out, err := json.Marshal(resp.Data)
+	require.NoError(t, err)
+	return string(out), resp.Errors
+}
+
+func TestExecute(t *testing.T) {
+	t.Run("NestedFields", func(t *testing.T) {
+		data, errs := execute(t, `{ book(id: "b1") { title, id, __typename, sequel { id Author } } }`, "", nil)
+		assert.Empty(t, errs)
+		assert.Equal(t, `{"book":{"title":"One","id":"b1","__typename":"Book","sequel":{"id":"b2","Author":"b"}}}`, data)
+	})
+	t.Run("Lists", func(t *testing.T) {
+		data, errs := execute(t, `{ books(limit: 2) { id } first: books(limit: 1) { title } }`, "", nil)
+		assert.Empty(t, errs)
+		assert.Equal(t, `{"books":[{"id":"b1"},{"id":"b2"}],"first":[{"title":"One"}]}`, data)
+	})
+	t.Run("Variables", func(t *testing.T) {
+		query := `query Find($id: String!, $limit: Int = 1) { book(id: $id) { id } books(limit: $limit) { id } }`
+		data, errs := execute(t, query, "Find", map[string]interface{}{"id": "b2"})
+		assert.Empty(t, errs)
+		assert.Equal(t, `{"book":{"id":"b2"},"books":[{"id":"b1"}]}`, data)
+
+		doc, err := Parse(query)
+		require.NoError(t, err)
+		resp := testSchema().Execute(context.Background(), doc, "", nil)
+		require.Len(t, resp.Errors, 1)
+		assert.Contains(t, resp.Errors[0].Message, "$id")
+	})
+	t.Run("Fragments", func(t *testing.T) {
+		data, errs := execute(t, `
+			{ book(id: "b1") { ...fields ... on Book { title } ... on Author { name } id @skip(if: true) } }
+			fragment fields on Book { id sequel { id } }
+			fragment unused on Book { id }
+		`, "", nil)
+		assert.Empty(t, errs)
+		assert.Equal(t, `{"book":{"id":"b1","sequel":{"id":"b2"},"title":"One"}}`, data)
+	})
+	t.Run("MergesSelections", func(t *testing.T) {
+		data, errs := execute(t, `{ book(id: "b1") { sequel { id } sequel { title } } }`, "", nil)
+		assert.Empty(t, errs)
+		assert.Equal(t, `{"book":{"sequel":{"id":"b2","title":"Two"}}}`, data)
+	})
+	t.Run("JSONObjects", func(t *testing.T) {
+		data, errs := execute(t, `{ book(id: "b1") { meta { isbn } all: meta } }`, "", nil)
+		assert.Empty(t, errs)
+		assert.Equal(t, `{"book":{"meta":{"isbn":"1"},"all":{"isbn":"1","lang":"en"}}}`, data)
+	})
+	t.Run("NullValues", func(t *testing.T) {
+		data, errs := execute(t, `{ book(id: "b2") { sequel { id } } missing: book(id: "b3") { id } }`, "", nil)
+		assert.Empty(t, errs)
+		assert.Equal(t, `{"book":{"sequel":null},"missing":null}`, data)
+	})
+	t.Run("FieldErrors", func(t *testing.T) {
+		data, errs := execute(t, `{ book(id: "b1") { id broken Secret title { x } sequel } books(first: 1) { id } }`, "", nil)
+		assert.Equal(t, `{"book":{"id":"b1","broken":null,"Secret":null,"title":null,"sequel":null},"books":null}`, data)
+		require.Len(t, errs, 5)
+		assert.Equal(t, "broken", errs[0].Message)
+		assert.Equal(t, []interface{}{"book", "broken"}, errs[0].Path)
+		assert.Contains(t, errs[1].Message, "no field 'Secret'")
+		assert.Contains(t, errs[2].Message, "scalar")
+		assert.Contains(t, errs[3].Message, "must have a selection")
+		assert.Contains(t, errs[4].Message, "no argument 'first'")
+	})
+	t.Run("Operations", func(t *testing.T) {
+		doc, err := Parse(`query A { book(id: "b1") { id } } mutation B { book { id } }`)
+		require.NoError(t, err)
+		resp := testSchema().Execute(context.Background(), doc, "", nil)
+		require.Len(t, resp.Errors, 1)
+		resp = testSchema().Execute(context.Background(), doc, "B", nil)
+		require.Len(t, resp.Errors, 1)
+		assert.Contains(t, resp.Errors[0].Message, "not supported")
+		resp = testSchema().Execute(context.Background(), doc, "C", nil)
+		require.Len(t, resp.Errors, 1)
+		resp = testSchema().Execute(context.Background(), doc, "A", nil)
+		assert.Empty(t, resp.Errors)
+	})
+}
+
+func TestArgs(t *testing.T) {
+	args := Args{"s": "x", "e": EnumValue("E"), "i": int64(3), "f": float64(4), "frac": 4.5, "n": json.Number("5")}
+
+	s, err := args.String("s")
+	assert.NoError(t, err)
+	assert.Equal(t, "x", s)
+	s, err = args.String("e")
+	assert.NoError(t, err)
+	assert.Equal(t, "E", s)
+	s, err = args.String("missing")
+	assert.NoError(t, err)
+	assert.Empty(t, s)
+	_, err = args.String("i")
+	assert.Error(t, err)
+
+	for name, expected := range map[string]int{"i": 3, "f": 4, "n": 5, "missing": 7} {
+		i, err := args.Int(name, 7)
+		assert.NoError(t, err)
+		assert.Equal(t, expected, i)
+	}
+	_, err = args.Int("frac", 0)
+	assert.Error(t, err)
+	_, err = args.Int("s", 0)
+	assert.Error(t, err)
+}
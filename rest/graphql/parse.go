@@ -0,0 +1,807 @@
+package graphql
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/pkg/errors"
+)
+
+// Document is a parsed GraphQL request.
+type Document struct {
+	Operations []*Operation
+	Fragments  map[string]*Fragment
+}
+
+// Operation is a query, mutation or subscription in a document.
+type Operation struct {
+	Type       string
+	Name       string
+	Variables  []VariableDefinition
+	Directives []Directive
+	Selections []Selection
+}
+
+// VariableDefinition declares a variable that an operation takes.
+type VariableDefinition struct {
+	Name       string
+	Type       string
+	Default    Value
+	HasDefault bool
+}
+
+// Required returns whether the variable must be given a value.
+func (v VariableDefinition) Required() bool {
+	return strings.HasSuffix(v.Type, "!") && !v.HasDefault
+}
+
+// Fragment is a named, reusable selection of fields.
+type Fragment struct {
+	Name          string
+	TypeCondition string
+	Directives    []Directive
+	Selections    []Selection
+}
+
+// Selection is a field, a spread of a named fragment, or an inline
+// fragment in a selection set.
+type Selection struct {
+	Alias         string
+	Name          string
+	Arguments     map[string]Value
+	Directives    []Directive
+	Selections    []Selection
+	FragmentName  string
+	TypeCondition string
+	Location      Location
+}
+
+// ResponseKey is the key the selected field has in the response.
+func (s Selection) ResponseKey() string {
+	if s.Alias != "" {
+		return s.Alias
+	}
+	return s.Name
+}
+
+// IsFragmentSpread returns whether the selection spreads a named fragment.
+func (s Selection) IsFragmentSpread() bool { return s.FragmentName != "" }
+
+// IsInlineFragment returns whether the selection is an inline fragment.
+func (s Selection) IsInlineFragment() bool { return s.Name == "" && s.FragmentName == "" }
+
+// Directive modifies how a selection is executed.
+type Directive struct {
+	Name      string
+	Arguments map[string]Value
+}
+
+// Value is a literal in a document: nil, bool, int64, float64, string, an
+// EnumValue, a Variable, []Value or map[string]Value.
+type Value interface{}
+
+// Variable refers to one of the variables of an operation.
+type Variable string
+
+// EnumValue is an unquoted name used as a value.
+type EnumValue string
+
+// Location is a position in a document, for reporting errors.
+type Location struct {
+	Line   int `json:"line"`
+	Column int `json:"column"`
+}
+
+// Parse parses a GraphQL request document.
+func Parse(query string) (*Document, error) {
+	p := &parser{lexer: lexer{src: query, line: 1, col: 1}}
+	if err := p.advance(); err != nil {
+		return nil, err
+	}
+
+	doc := &Document{Fragments: map[string]*Fragment{}}
+	for p.tok.kind != tokenEOF {
+		switch {
+		case p.tok.is(tokenPunctuator, "{"):
+			op := &Operation{Type: "query"}
+			var err error
+			if op.Selections, err = p.parseSelectionSet(); err != nil {
+				return nil, err
+			}
+			doc.Operations = append(doc.Operations, op)
+		case p.tok.is(tokenName, "query"), p.tok.is(tokenName, "mutation"), p.tok.is(tokenName, "subscription"):
+			op, err := p.parseOperation()
+			if err != nil {
+				return nil, err
+			}
+			doc.Operations = append(doc.Operations, op)
+		case p.tok.is(tokenName, "fragment"):
+			f, err := p.parseFragment()
+			if err != nil {
+				return nil, err
+			}
+			if _, ok := doc.Fragments[f.Name]; ok {
+				return nil, errors.Errorf("fragment '%s' is defined more than once", f.Name)
+			}
+			doc.Fragments[f.Name] = f
+		default:
+			return nil, p.unexpected()
+		}
+	}
+
+	if len(doc.Operations) == 0 {
+		return nil, errors.New("document does not contain an operation")
+	}
+	names := map[string]bool{}
+	for _, op := range doc.Operations {
+		if len(doc.Operations) > 1 && op.Name == "" {
+			return nil, errors.New("anonymous operations must be the only operation in a document")
+		}
+		if names[op.Name] {
+			return nil, errors.Errorf("operation '%s' is defined more than once", op.Name)
+		}
+		names[op.Name] = true
+		if err := checkOperationSize(doc, op); err != nil {
+			return nil, err
+		}
+	}
+
+	return doc, nil
+}
+
+const (
+	// MaxDepth is the deepest that fields can be nested in an operation.
+	MaxDepth = 10
+	// MaxFields is the most fields that an operation can select, counting
+	// the fields of a fragment each time it's spread.
+	MaxFields = 500
+)
+
+// checkOperationSize rejects operations that nest fields more deeply or
+// select more of them than the limits allow, since every field can be a
+// lookup in the database.
+func checkOperationSize(doc *Document, op *Operation) error {
+	fields := 0
+	var check func([]Selection, int, map[string]bool) error
+	check = func(selections []Selection, depth int, spread map[string]bool) error {
+		for _, s := range selections {
+			switch {
+			case s.IsFragmentSpread():
+				f, ok := doc.Fragments[s.FragmentName]
+				if !ok {
+					continue
+				}
+				if spread[s.FragmentName] {
+					return errors.Errorf("fragment '%s' spreads itself", s.FragmentName)
+				}
+				spread[s.FragmentName] = true
+				err := check(f.Selections, depth, spread)
+				delete(spread, s.FragmentName)
+				if err != nil {
+					return err
+				}
+			case s.IsInlineFragment():
+				if err := check(s.Selections, depth, spread); err != nil {
+					return err
+				}
+			default:
+				if depth > MaxDepth {
+					return errors.Errorf("operation nests fields more than %d deep", MaxDepth)
+				}
+				fields++
+				if fields > MaxFields {
+					return errors.Errorf("operation selects more than %d fields", MaxFields)
+				}
+				if err := check(s.Selections, depth+1, spread); err != nil {
+					return err
+				}
+			}
+		}
+		return nil
+	}
+
+	return check(op.Selections, 1, map[string]bool{})
+}
+
+type parser struct {
+	lexer lexer
+	tok   token
+}
+
+func (p *parser) advance() error {
+	tok, err := p.lexer.next()
+	if err != nil {
+		return err
+	}
+	p.tok = tok
+	return nil
+}
+
+func (p *parser) unexpected() error {
+	if p.tok.kind == tokenEOF {
+		return errors.Errorf("unexpected end of document at %d:%d", p.tok.loc.Line, p.tok.loc.Column)
+	}
+	return errors.Errorf("unexpected '%s' at %d:%d", p.tok.value, p.tok.loc.Line, p.tok.loc.Column)
+}
+
+// expect consumes the given punctuator.
+func (p *parser) expect(punctuator string) error {
+	if !p.tok.is(tokenPunctuator, punctuator) {
+		return p.unexpected()
+	}
+	return p.advance()
+}
+
+// skip consumes the given punctuator if it is next, and returns whether it
+// was.
+func (p *parser) skip(punctuator string) (bool, error) {
+	if !p.tok.is(tokenPunctuator, punctuator) {
+		return false, nil
+	}
+	return true, p.advance()
+}
+
+func (p *parser) parseName() (string, error) {
+	if p.tok.kind != tokenName {
+		return "", p.unexpected()
+	}
+	name := p.tok.value
+	return name, p.advance()
+}
+
+func (p *parser) parseOperation() (*Operation, error) {
+	op := &Operation{Type: p.tok.value}
+	if err := p.advance(); err != nil {
+		return nil, err
+	}
+
+	var err error
+	if p.tok.kind == tokenName {
+		if op.Name, err = p.parseName(); err != nil {
+			return nil, err
+		}
+	}
+	if p.tok.is(tokenPunctuator, "(") {
+		if op.Variables, err = p.parseVariableDefinitions(); err != nil {
+			return nil, err
+		}
+	}
+	if op.Directives, err = p.parseDirectives(); err != nil {
+		return nil, err
+	}
+	if op.Selections, err = p.parseSelectionSet(); err != nil {
+		return nil, err
+	}
+
+	return op, nil
+}
+
+func (p *parser) parseVariableDefinitions() ([]VariableDefinition, error) {
+	if err := p.expect("("); err != nil {
+		return nil, err
+	}
+
+	defs := []VariableDefinition{}
+	for {
+		done, err := p.skip(")")
+		if err != nil {
+			return nil, err
+		}
+		if done {
+			return defs, nil
+		}
+
+		if err = p.expect("$"); err != nil {
+			return nil, err
+		}
+		def := VariableDefinition{}
+		if def.Name, err = p.parseName(); err != nil {
+			return nil, err
+		}
+		if err = p.expect(":"); err != nil {
+			return nil, err
+		}
+		if def.Type, err = p.parseType(); err != nil {
+			return nil, err
+		}
+		if def.HasDefault, err = p.skip("="); err != nil {
+			return nil, err
+		}
+		if def.HasDefault {
+			if def.Default, err = p.parseValue(true); err != nil {
+				return nil, err
+			}
+		}
+		defs = append(defs, def)
+	}
+}
+
+func (p *parser) parseType() (string, error) {
+	var t string
+	isList, err := p.skip("[")
+	if err != nil {
+		return "", err
+	}
+	if isList {
+		var inner string
+		if inner, err = p.parseType(); err != nil {
+			return "", err
+		}
+		if err = p.expect("]"); err != nil {
+			return "", err
+		}
+		t = "[" + inner + "]"
+	} else if t, err = p.parseName(); err != nil {
+		return "", err
+	}
+
+	nonNull, err := p.skip("!")
+	if err != nil {
+		return "", err
+	}
+	if nonNull {
+		t += "!"
+	}
+
+	return t, nil
+}
+
+func (p *parser) parseFragment() (*Fragment, error) {
+	if err := p.advance(); err != nil {
+		return nil, err
+	}
+
+	f := &Fragment{}
+	var err error
+	if f.Name, err = p.parseName(); err != nil {
+		return nil, err
+	}
+	if f.Name == "on" {
+		return nil, errors.New("fragments cannot be named 'on'")
+	}
+	if !p.tok.is(tokenName, "on") {
+		return nil, p.unexpected()
+	}
+	if err = p.advance(); err != nil {
+		return nil, err
+	}
+	if f.TypeCondition, err = p.parseName(); err != nil {
+		return nil, err
+	}
+	if f.Directives, err = p.parseDirectives(); err != nil {
+		return nil, err
+	}
+	if f.Selections, err = p.parseSelectionSet(); err != nil {
+		return nil, err
+	}
+
+	return f, nil
+}
+
+func (p *parser) parseSelectionSet() ([]Selection, error) {
+	if err := p.expect("{"); err != nil {
+		return nil, err
+	}
+
+	selections := []Selection{}
+	for {
+		done, err := p.skip("}")
+		if err != nil {
+			return nil, err
+		}
+		if done {
+			if len(selections) == 0 {
+				return nil, errors.New("selection sets cannot be empty")
+			}
+			return selections, nil
+		}
+
+		s, err := p.parseSelection()
+		if err != nil {
+			return nil, err
+		}
+		selections = append(selections, s)
+	}
+}
+
+func (p *parser) parseSelection() (Selection, error) {
+	s := Selection{Location: p.tok.loc}
+	spread, err := p.skip("...")
+	if err != nil {
+		return s, err
+	}
+
+	if spread {
+		if p.tok.kind == tokenName && p.tok.value != "on" {
+			if s.FragmentName, err = p.parseName(); err != nil {
+				return s, err
+			}
+			s.Directives, err = p.parseDirectives()
+			return s, err
+		}
+		if p.tok.is(tokenName, "on") {
+			if err = p.advance(); err != nil {
+				return s, err
+			}
+			if s.TypeCondition, err = p.parseName(); err != nil {
+				return s, err
+			}
+		}
+		if s.Directives, err = p.parseDirectives(); err != nil {
+			return s, err
+		}
+		s.Selections, err = p.parseSelectionSet()
+		return s, err
+	}
+
+	if s.Name, err = p.parseName(); err != nil {
+		return s, err
+	}
+	isAlias, err := p.skip(":")
+	if err != nil {
+		return s, err
+	}
+	if isAlias {
+		s.Alias = s.Name
+		if s.Name, err = p.parseName(); err != nil {
+			return s, err
+		}
+	}
+	if p.tok.is(tokenPunctuator, "(") {
+		if s.Arguments, err = p.parseArguments(); err != nil {
+			return s, err
+		}
+	}
+	if s.Directives, err = p.parseDirectives(); err != nil {
+		return s, err
+	}
+	if p.tok.is(tokenPunctuator, "{") {
+		if s.Selections, err = p.parseSelectionSet(); err != nil {
+			return s, err
+		}
+	}
+
+	return s, nil
+}
+
+func (p *parser) parseArguments() (map[string]Value, error) {
+	if err := p.expect("("); err != nil {
+		return nil, err
+	}
+
+	args := map[string]Value{}
+	for {
+		done, err := p.skip(")")
+		if err != nil {
+			return nil, err
+		}
+		if done {
+			return args, nil
+		}
+
+		name, err := p.parseName()
+		if err != nil {
+			return nil, err
+		}
+		if _, ok := args[name]; ok {
+			return nil, errors.Errorf("argument '%s' is given more than once", name)
+		}
+		if err = p.expect(":"); err != nil {
+			return nil, err
+		}
+		if args[name], err = p.parseValue(false); err != nil {
+			return nil, err
+		}
+	}
+}
+
+func (p *parser) parseDirectives() ([]Directive, error) {
+	directives := []Directive{}
+	for {
+		isDirective, err := p.skip("@")
+		if err != nil {
+			return nil, err
+		}
+		if !isDirective {
+			return directives, nil
+		}
+
+		d := Directive{}
+		if d.Name, err = p.parseName(); err != nil {
+			return nil, err
+		}
+		if p.tok.is(tokenPunctuator, "(") {
+			if d.Arguments, err = p.parseArguments(); err != nil {
+				return nil, err
+			}
+		}
+		directives = append(directives, d)
+	}
+}
+
+// parseValue parses a literal. Variables are not allowed in constant
+// values, such as variables' defaults.
+func (p *parser) parseValue(constant bool) (Value, error) {
+	tok := p.tok
+	switch tok.kind {
+	case tokenPunctuator:
+		switch tok.value {
+		case "$":
+			if constant {
+				return nil, p.unexpected()
+			}
+			if err := p.advance(); err != nil {
+				return nil, err
+			}
+			name, err := p.parseName()
+			return Variable(name), err
+		case "[":
+			if err := p.advance(); err != nil {
+				return nil, err
+			}
+			list := []Value{}
+			for {
+				done, err := p.skip("]")
+				if err != nil {
+					return nil, err
+				}
+				if done {
+					return list, nil
+				}
+				v, err := p.parseValue(constant)
+				if err != nil {
+					return nil, err
+				}
+				list = append(list, v)
+			}
+		case "{":
+			if err := p.advance(); err != nil {
+				return nil, err
+			}
+			obj := map[string]Value{}
+			for {
+				done, err := p.skip("}")
+				if err != nil {
+					return nil, err
+				}
+				if done {
+					return obj, nil
+				}
+				name, err := p.parseName()
+				if err != nil {
+					return nil, err
+				}
+				if err = p.expect(":"); err != nil {
+					return nil, err
+				}
+				if obj[name], err = p.parseValue(constant); err != nil {
+					return nil, err
+				}
+			}
+		}
+	case tokenInt:
+		i, err := strconv.ParseInt(tok.value, 10, 64)
+		if err != nil {
+			return nil, errors.Errorf("invalid integer '%s' at %d:%d", tok.value, tok.loc.Line, tok.loc.Column)
+		}
+		return i, p.advance()
+	case tokenFloat:
+		f, err := strconv.ParseFloat(tok.value, 64)
+		if err != nil {
+			return nil, errors.Errorf("invalid float '%s' at %d:%d", tok.value, tok.loc.Line, tok.loc.Column)
+		}
+		return f, p.advance()
+	case tokenString:
+		return tok.value, p.advance()
+	case tokenName:
+		switch tok.value {
+		case "true":
+			return true, p.advance()
+		case "false":
+			return false, p.advance()
+		case "null":
+			return nil, p.advance()
+		default:
+			return EnumValue(tok.value), p.advance()
+		}
+	}
+
+	return nil, p.unexpected()
+}
+
+type tokenKind int
+
+const (
+	tokenEOF tokenKind = iota
+	tokenPunctuator
+	tokenName
+	tokenInt
+	tokenFloat
+	tokenString
+)
+
+type token struct {
+	kind  tokenKind
+	value string
+	loc   Location
+}
+
+func (t token) is(kind tokenKind, value string) bool {
+	return t.kind == kind && t.value == value
+}
+
+type lexer struct {
+	src  string
+	pos  int
+	line int
+	col  int
+}
+
+func (l *lexer) peek() byte {
+	if l.pos >= len(l.src) {
+		return 0
+	}
+	return l.src[l.pos]
+}
+
+func (l *lexer) consume() byte {
+	c := l.src[l.pos]
+	l.pos++
+	if c == '\n' {
+		l.line++
+		l.col = 1
+	} else {
+		l.col++
+	}
+	return c
+}
+
+func (l *lexer) errorf(loc Location, format string, args ...interface{}) error {
+	return errors.Errorf("%s at %d:%d", fmt.Sprintf(format, args...), loc.Line, loc.Column)
+}
+
+func (l *lexer) next() (token, error) {
+	// commas, whitespace, byte order marks and comments are insignificant
+	for l.pos < len(l.src) {
+		c := l.peek()
+		if c == ' ' || c == '\t' || c == '\n' || c == '\r' || c == ',' {
+			l.consume()
+		} else if strings.HasPrefix(l.src[l.pos:], "\ufeff") {
+			l.pos += len("\ufeff")
+		} else if c == '#' {
+			for l.pos < len(l.src) && l.peek() != '\n' {
+				l.consume()
+			}
+		} else {
+			break
+		}
+	}
+
+	loc := Location{Line: l.line, Column: l.col}
+	if l.pos >= len(l.src) {
+		return token{kind: tokenEOF, loc: loc}, nil
+	}
+
+	c := l.peek()
+	switch {
+	case strings.IndexByte("!$()=:@[]{|}", c) >= 0:
+		l.consume()
+		return token{kind: tokenPunctuator, value: string(c), loc: loc}, nil
+	case c == '.':
+		if !strings.HasPrefix(l.src[l.pos:], "...") {
+			return token{}, l.errorf(loc, "unexpected '.'")
+		}
+		l.consume()
+		l.consume()
+		l.consume()
+		return token{kind: tokenPunctuator, value: "...", loc: loc}, nil
+	case c == '_' || isLetter(c):
+		start := l.pos
+		for l.pos < len(l.src) && (l.peek() == '_' || isLetter(l.peek()) || isDigit(l.peek())) {
+			l.consume()
+		}
+		return token{kind: tokenName, value: l.src[start:l.pos], loc: loc}, nil
+	case c == '-' || isDigit(c):
+		return l.lexNumber(loc)
+	case c == '"':
+		return l.lexString(loc)
+	}
+
+	r, _ := utf8.DecodeRuneInString(l.src[l.pos:])
+	return token{}, l.errorf(loc, "unexpected character '%c'", r)
+}
+
+func (l *lexer) lexNumber(loc Location) (token, error) {
+	start := l.pos
+	kind := tokenInt
+	if l.peek() == '-' {
+		l.consume()
+	}
+	if !isDigit(l.peek()) {
+		return token{}, l.errorf(loc, "invalid number")
+	}
+	for isDigit(l.peek()) {
+		l.consume()
+	}
+	if l.peek() == '.' {
+		kind = tokenFloat
+		l.consume()
+		if !isDigit(l.peek()) {
+			return token{}, l.errorf(loc, "invalid number")
+		}
+		for isDigit(l.peek()) {
+			l.consume()
+		}
+	}
+	if l.peek() == 'e' || l.peek() == 'E' {
+		kind = tokenFloat
+		l.consume()
+		if l.peek() == '+' || l.peek() == '-' {
+			l.consume()
+		}
+		if !isDigit(l.peek()) {
+			return token{}, l.errorf(loc, "invalid number")
+		}
+		for isDigit(l.peek()) {
+			l.consume()
+		}
+	}
+
+	return token{kind: kind, value: l.src[start:l.pos], loc: loc}, nil
+}
+
+func (l *lexer) lexString(loc Location) (token, error) {
+	l.consume()
+	var b strings.Builder
+	for {
+		if l.pos >= len(l.src) || l.peek() == '\n' {
+			return token{}, l.errorf(loc, "unterminated string")
+		}
+		c := l.consume()
+		switch c {
+		case '"':
+			return token{kind: tokenString, value: b.String(), loc: loc}, nil
+		case '\\':
+			if l.pos >= len(l.src) {
+				return token{}, l.errorf(loc, "unterminated string")
+			}
+			escaped := l.consume()
+			switch escaped {
+			case '"', '\\', '/':
+				b.WriteByte(escaped)
+			case 'b':
+				b.WriteByte('\b')
+			case 'f':
+				b.WriteByte('\f')
+			case 'n':
+				b.WriteByte('\n')
+			case 'r':
+				b.WriteByte('\r')
+			case 't':
+				b.WriteByte('\t')
+			case 'u':
+				if l.pos+4 > len(l.src) {
+					return token{}, l.errorf(loc, "invalid unicode escape")
+				}
+				code, err := strconv.ParseUint(l.src[l.pos:l.pos+4], 16, 32)
+				if err != nil {
+					return token{}, l.errorf(loc, "invalid unicode escape")
+				}
+				for i := 0; i < 4; i++ {
+					l.consume()
+				}
+				b.WriteRune(rune(code))
+			default:
+				return token{}, l.errorf(loc, "invalid escape '\\%c'", escaped)
+			}
+		default:
+			b.WriteByte(c)
+		}
+	}
+}
+
+func isLetter(c byte) bool { return (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z') }
+
+func isDigit(c byte) bool { return c >= '0' && c <= '9' }
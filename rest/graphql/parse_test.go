@@ -0,0 +1,105 @@
+package graphql
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParse(t *testing.T) {
+	t.Run("Shorthand", func(t *testing.T) {
+		doc, err := Parse(`{ version(id: "v1") { version_id, builds { _id } } }`)
+		require.NoError(t, err)
+		require.Len(t, doc.Operations, 1)
+		op := doc.Operations[0]
+		assert.Equal(t, "query", op.Type)
+		require.Len(t, op.Selections, 1)
+		s := op.Selections[0]
+		assert.Equal(t, "version", s.Name)
+		assert.Equal(t, "v1", s.Arguments["id"])
+		require.Len(t, s.Selections, 2)
+		assert.Equal(t, "builds", s.Selections[1].Name)
+		assert.Equal(t, Location{Line: 1, Column: 3}, s.Location)
+	})
+	t.Run("NamedOperation", func(t *testing.T) {
+		doc, err := Parse(`
+			# fetch a task
+			query Task($id: String!, $limit: Int = 10, $statuses: [String]) @cached {
+				t: task(id: $id) {
+					tests(limit: $limit, status: FAILED) @include(if: true) { results { test_file } }
+					...taskFields
+					... on Task { status }
+				}
+			}
+
+			fragment taskFields on Task { display_name }
+		`)
+		require.NoError(t, err)
+		op := doc.Operations[0]
+		assert.Equal(t, "Task", op.Name)
+		require.Len(t, op.Variables, 3)
+		assert.True(t, op.Variables[0].Required())
+		assert.Equal(t, "Int", op.Variables[1].Type)
+		assert.EqualValues(t, 10, op.Variables[1].Default)
+		assert.False(t, op.Variables[1].Required())
+		assert.Equal(t, "[String]", op.Variables[2].Type)
+		require.Len(t, op.Directives, 1)
+
+		task := op.Selections[0]
+		assert.Equal(t, "t", task.Alias)
+		assert.Equal(t, "t", task.ResponseKey())
+		assert.Equal(t, Variable("id"), task.Arguments["id"])
+		require.Len(t, task.Selections, 3)
+		tests := task.Selections[0]
+		assert.Equal(t, EnumValue("FAILED"), tests.Arguments["status"])
+		require.Len(t, tests.Directives, 1)
+		assert.Equal(t, "include", tests.Directives[0].Name)
+		assert.True(t, task.Selections[1].IsFragmentSpread())
+		assert.True(t, task.Selections[2].IsInlineFragment())
+		assert.Equal(t, "Task", task.Selections[2].TypeCondition)
+
+		require.Contains(t, doc.Fragments, "taskFields")
+		assert.Equal(t, "Task", doc.Fragments["taskFields"].TypeCondition)
+	})
+	t.Run("Values", func(t *testing.T) {
+		doc, err := Parse(`{ f(a: -1, b: 1.5e3, c: "q\"é\n", d: [true, false, null], e: {x: 1}) }`)
+		require.NoError(t, err)
+		args := doc.Operations[0].Selections[0].Arguments
+		assert.EqualValues(t, -1, args["a"])
+		assert.EqualValues(t, 1500, args["b"])
+		assert.Equal(t, "q\"é\n", args["c"])
+		assert.Equal(t, []Value{true, false, nil}, args["d"])
+		assert.Equal(t, map[string]Value{"x": int64(1)}, args["e"])
+	})
+	t.Run("SizeLimits", func(t *testing.T) {
+		_, err := Parse(`{ a { b { c { d { e { f { g { h { i { j } } } } } } } } } }`)
+		assert.NoError(t, err)
+		_, err = Parse(`{ ` + strings.Repeat("x ", MaxFields) + `}`)
+		assert.NoError(t, err)
+	})
+	t.Run("Errors", func(t *testing.T) {
+		for name, query := range map[string]string{
+			"Empty":                  ``,
+			"EmptySelection":         `{ }`,
+			"Unterminated":           `{ version(id: "v1) { _id } }`,
+			"UnexpectedToken":        `{ version(id: "v1") { _id } ]`,
+			"MissingClose":           `{ version { _id }`,
+			"VariableInDefault":      `query ($a: Int = $b) { f }`,
+			"DuplicateArgument":      `{ f(a: 1, a: 2) }`,
+			"DuplicateFragment":      `{ f } fragment a on T { f } fragment a on T { f }`,
+			"MultipleAnonymous":      `{ f } { g }`,
+			"DuplicateOperationName": `query a { f } query a { g }`,
+			"BadCharacter":           `{ f % }`,
+			"TooDeep":                `{ a { b { c { d { e { f { g { h { i { j { k } } } } } } } } } } }`,
+			"TooManyFields":          `{ ...a } fragment a on T { b { ...c } c { ...c } d { ...c } e { ...c } f { ...c } } fragment c on T { ` + strings.Repeat("x ", 100) + `}`,
+			"FragmentCycle":          `{ ...a } fragment a on T { f { ...b } } fragment b on T { ...a }`,
+		} {
+			t.Run(name, func(t *testing.T) {
+				_, err := Parse(query)
+				assert.Error(t, err)
+			})
+		}
+	})
+}
@@ -0,0 +1,128 @@
+package graphql
+
+import (
+	"fmt"
+
+	"github.com/evergreen-ci/evergreen/util"
+	"github.com/pkg/errors"
+)
+
+// Validate checks a document against the schema before it's executed,
+// so that a request selecting fields or passing arguments that the schema
+// doesn't define is rejected as a whole, instead of being executed up to
+// the unknown fields. Fields of JSON values that aren't objects of the
+// schema can't be checked until they are resolved.
+func (s *Schema) Validate(doc *Document) error {
+	v := &validator{
+		doc:   doc,
+		types: map[string]*Object{},
+	}
+	v.addType(s.Query)
+
+	for _, f := range doc.Fragments {
+		if _, ok := v.types[f.TypeCondition]; !ok {
+			return errors.Errorf("fragment '%s' is on unknown type '%s'", f.Name, f.TypeCondition)
+		}
+	}
+	for _, op := range doc.Operations {
+		if op.Type != "query" {
+			continue
+		}
+		if err := v.validateSelections(s.Query, op.Selections); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+type validator struct {
+	doc   *Document
+	types map[string]*Object
+}
+
+func (v *validator) addType(object *Object) {
+	if object == nil || v.types[object.Name] != nil {
+		return
+	}
+	v.types[object.Name] = object
+	for _, field := range object.Fields {
+		v.addType(field.Type)
+	}
+}
+
+func (v *validator) validateSelections(object *Object, selections []Selection) error {
+	for _, s := range selections {
+		switch {
+		case s.IsFragmentSpread():
+			f, ok := v.doc.Fragments[s.FragmentName]
+			if !ok {
+				return locatedErrorf(s, "fragment '%s' is not defined", s.FragmentName)
+			}
+			// fragments are only applied to objects of their type, and
+			// Parse rejects fragments that spread themselves
+			if f.TypeCondition != object.Name {
+				continue
+			}
+			if err := v.validateSelections(object, f.Selections); err != nil {
+				return err
+			}
+		case s.IsInlineFragment():
+			if s.TypeCondition != "" {
+				if _, ok := v.types[s.TypeCondition]; !ok {
+					return locatedErrorf(s, "inline fragment is on unknown type '%s'", s.TypeCondition)
+				}
+				if s.TypeCondition != object.Name {
+					continue
+				}
+			}
+			if err := v.validateSelections(object, s.Selections); err != nil {
+				return err
+			}
+		default:
+			if err := v.validateField(object, s); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+func (v *validator) validateField(object *Object, s Selection) error {
+	if s.Name == "__typename" {
+		if len(s.Selections) > 0 {
+			return locatedErrorf(s, "field '__typename' is a scalar and cannot have a selection of subfields")
+		}
+		return nil
+	}
+
+	field, ok := object.Fields[s.Name]
+	if !ok {
+		if !object.hasJSONField(s.Name) {
+			return locatedErrorf(s, "type '%s' has no field '%s'", object.Name, s.Name)
+		}
+		if len(s.Arguments) > 0 {
+			return locatedErrorf(s, "field '%s' of type '%s' has no arguments", s.Name, object.Name)
+		}
+		return nil
+	}
+
+	for name := range s.Arguments {
+		if !util.StringSliceContains(field.Args, name) {
+			return locatedErrorf(s, "field '%s' of type '%s' has no argument '%s'", s.Name, object.Name, name)
+		}
+	}
+	if field.Type == nil {
+		return nil
+	}
+	if len(s.Selections) == 0 {
+		return locatedErrorf(s, "field '%s' of type '%s' must have a selection of subfields", s.Name, field.Type.Name)
+	}
+
+	return v.validateSelections(field.Type, s.Selections)
+}
+
+func locatedErrorf(s Selection, format string, args ...interface{}) error {
+	return errors.Errorf("%s (line %d, column %d)", fmt.Sprintf(format, args...), s.Location.Line, s.Location.Column)
+}
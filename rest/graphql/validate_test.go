@@ -0,0 +1,76 @@
+package graphql
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidate(t *testing.T) {
+	for name, query := range map[string]string{
+		"NestedFields": `{ book(id: "b1") { title id __typename sequel { id Author } } }`,
+		"JSONObjects":  `{ book(id: "b1") { meta { isbn } all: meta } }`,
+		"Fragments": `
+			{ book(id: "b1") { ...fields ... on Book { title } ... { id } } }
+			fragment fields on Book { id sequel { id } }
+		`,
+		"OtherOperations": `mutation { anything }`,
+	} {
+		t.Run(name, func(t *testing.T) {
+			doc, err := Parse(query)
+			require.NoError(t, err)
+			assert.NoError(t, testSchema().Validate(doc))
+		})
+	}
+
+	for name, test := range map[string]struct {
+		query    string
+		expected string
+	}{
+		"UnknownField": {
+			query:    `{ book(id: "b1") { id Secret } }`,
+			expected: "type 'Book' has no field 'Secret' (line 1, column 23)",
+		},
+		"UnknownRootField": {
+			query:    `{ library { id } }`,
+			expected: "type 'Query' has no field 'library'",
+		},
+		"UnknownArgument": {
+			query:    `{ books(first: 1) { id } }`,
+			expected: "field 'books' of type 'Query' has no argument 'first'",
+		},
+		"ArgumentOfJSONField": {
+			query:    `{ book(id: "b1") { title(lang: "en") } }`,
+			expected: "field 'title' of type 'Book' has no arguments",
+		},
+		"MissingSubfields": {
+			query:    `{ book(id: "b1") { sequel } }`,
+			expected: "field 'sequel' of type 'Book' must have a selection of subfields",
+		},
+		"UnknownFieldInFragment": {
+			query:    `{ book(id: "b1") { ...fields } } fragment fields on Book { isbn }`,
+			expected: "type 'Book' has no field 'isbn'",
+		},
+		"UndefinedFragment": {
+			query:    `{ book(id: "b1") { ...fields } }`,
+			expected: "fragment 'fields' is not defined",
+		},
+		"FragmentOnUnknownType": {
+			query:    `{ book(id: "b1") { id } } fragment fields on Author { name }`,
+			expected: "fragment 'fields' is on unknown type 'Author'",
+		},
+		"InlineFragmentOnUnknownType": {
+			query:    `{ book(id: "b1") { ... on Author { name } } }`,
+			expected: "inline fragment is on unknown type 'Author'",
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			doc, err := Parse(test.query)
+			require.NoError(t, err)
+			err = testSchema().Validate(doc)
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), test.expected)
+		})
+	}
+}
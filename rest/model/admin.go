@@ -27,6 +27,7 @@ func NewConfigModel() *APIAdminSettings {
 		Notify:            &APINotifyConfig{},
 		Plugins:           map[string]map[string]interface{}{},
 		Providers:         &APICloudProviders{},
+		RateLimit:         &APIRateLimitConfig{},
 		RepoTracker:       &APIRepoTrackerConfig{},
 		Scheduler:         &APISchedulerConfig{},
 		ServiceFlags:      &APIServiceFlags{},
@@ -60,6 +61,7 @@ type APIAdminSettings struct {
 	Plugins            map[string]map[string]interface{} `json:"plugins,omitempty"`
 	PprofPort          APIString                         `json:"pprof_port,omitempty"`
 	Providers          *APICloudProviders                `json:"providers,omitempty"`
+	RateLimit          *APIRateLimitConfig               `json:"rate_limit,omitempty"`
 	RepoTracker        *APIRepoTrackerConfig             `json:"repotracker,omitempty"`
 	Scheduler          *APISchedulerConfig               `json:"scheduler,omitempty"`
 	ServiceFlags       *APIServiceFlags                  `json:"service_flags,omitempty"`
@@ -298,6 +300,8 @@ func (a *APIAmboyConfig) ToService() (interface{}, error) {
 type APIapiConfig struct {
 	HttpListenAddr      APIString `json:"http_listen_addr"`
 	GithubWebhookSecret APIString `json:"github_webhook_secret"`
+	GitlabWebhookSecret APIString `json:"gitlab_webhook_secret"`
+	TrustedProxies      []string  `json:"trusted_proxies"`
 }
 
 func (a *APIapiConfig) BuildFromService(h interface{}) error {
@@ -305,6 +309,8 @@ func (a *APIapiConfig) BuildFromService(h interface{}) error {
 	case evergreen.APIConfig:
 		a.HttpListenAddr = ToAPIString(v.HttpListenAddr)
 		a.GithubWebhookSecret = ToAPIString(v.GithubWebhookSecret)
+		a.GitlabWebhookSecret = ToAPIString(v.GitlabWebhookSecret)
+		a.TrustedProxies = v.TrustedProxies
 	default:
 		return errors.Errorf("%T is not a supported type", h)
 	}
@@ -315,6 +321,8 @@ func (a *APIapiConfig) ToService() (interface{}, error) {
 	return evergreen.APIConfig{
 		HttpListenAddr:      FromAPIString(a.HttpListenAddr),
 		GithubWebhookSecret: FromAPIString(a.GithubWebhookSecret),
+		GitlabWebhookSecret: FromAPIString(a.GitlabWebhookSecret),
+		TrustedProxies:      a.TrustedProxies,
 	}, nil
 }
 
@@ -322,6 +330,7 @@ type APIAuthConfig struct {
 	Crowd  *APICrowdConfig      `json:"crowd"`
 	Naive  *APINaiveAuthConfig  `json:"naive"`
 	Github *APIGithubAuthConfig `json:"github"`
+	OIDC   *APIOIDCConfig       `json:"oidc"`
 }
 
 func (a *APIAuthConfig) BuildFromService(h interface{}) error {
@@ -345,6 +354,12 @@ func (a *APIAuthConfig) BuildFromService(h interface{}) error {
 				return err
 			}
 		}
+		if v.OIDC != nil {
+			a.OIDC = &APIOIDCConfig{}
+			if err := a.OIDC.BuildFromService(v.OIDC); err != nil {
+				return err
+			}
+		}
 	default:
 		return errors.Errorf("%T is not a supported type", h)
 	}
@@ -355,6 +370,7 @@ func (a *APIAuthConfig) ToService() (interface{}, error) {
 	var crowd *evergreen.CrowdConfig
 	var naive *evergreen.NaiveAuthConfig
 	var github *evergreen.GithubAuthConfig
+	var oidc *evergreen.OIDCConfig
 	i, err := a.Crowd.ToService()
 	if err != nil {
 		return nil, err
@@ -376,10 +392,18 @@ func (a *APIAuthConfig) ToService() (interface{}, error) {
 	if i != nil {
 		github = i.(*evergreen.GithubAuthConfig)
 	}
+	i, err = a.OIDC.ToService()
+	if err != nil {
+		return nil, err
+	}
+	if i != nil {
+		oidc = i.(*evergreen.OIDCConfig)
+	}
 	return evergreen.AuthConfig{
 		Crowd:  crowd,
 		Naive:  naive,
 		Github: github,
+		OIDC:   oidc,
 	}, nil
 }
 
@@ -529,6 +553,78 @@ func (a *APIGithubAuthConfig) ToService() (interface{}, error) {
 	return &config, nil
 }
 
+type APIOIDCConfig struct {
+	Issuer           APIString            `json:"issuer"`
+	ClientId         APIString            `json:"client_id"`
+	ClientSecret     APIString            `json:"client_secret"`
+	Scopes           []APIString          `json:"scopes"`
+	UsernameClaim    APIString            `json:"username_claim"`
+	DisplayNameClaim APIString            `json:"display_name_claim"`
+	EmailClaim       APIString            `json:"email_claim"`
+	RolesClaim       APIString            `json:"roles_claim"`
+	RoleMappings     []APIOIDCRoleMapping `json:"role_mappings"`
+}
+
+type APIOIDCRoleMapping struct {
+	ClaimValue APIString   `json:"claim_value"`
+	Roles      []APIString `json:"roles"`
+}
+
+func (a *APIOIDCConfig) BuildFromService(h interface{}) error {
+	switch v := h.(type) {
+	case *evergreen.OIDCConfig:
+		if v == nil {
+			return nil
+		}
+		a.Issuer = ToAPIString(v.Issuer)
+		a.ClientId = ToAPIString(v.ClientId)
+		a.ClientSecret = ToAPIString(v.ClientSecret)
+		a.UsernameClaim = ToAPIString(v.UsernameClaim)
+		a.DisplayNameClaim = ToAPIString(v.DisplayNameClaim)
+		a.EmailClaim = ToAPIString(v.EmailClaim)
+		a.RolesClaim = ToAPIString(v.RolesClaim)
+		for _, scope := range v.Scopes {
+			a.Scopes = append(a.Scopes, ToAPIString(scope))
+		}
+		for _, m := range v.RoleMappings {
+			mapping := APIOIDCRoleMapping{ClaimValue: ToAPIString(m.ClaimValue)}
+			for _, r := range m.Roles {
+				mapping.Roles = append(mapping.Roles, ToAPIString(r))
+			}
+			a.RoleMappings = append(a.RoleMappings, mapping)
+		}
+	default:
+		return errors.Errorf("%T is not a supported type", h)
+	}
+	return nil
+}
+
+func (a *APIOIDCConfig) ToService() (interface{}, error) {
+	if a == nil {
+		return nil, nil
+	}
+	config := evergreen.OIDCConfig{
+		Issuer:           FromAPIString(a.Issuer),
+		ClientId:         FromAPIString(a.ClientId),
+		ClientSecret:     FromAPIString(a.ClientSecret),
+		UsernameClaim:    FromAPIString(a.UsernameClaim),
+		DisplayNameClaim: FromAPIString(a.DisplayNameClaim),
+		EmailClaim:       FromAPIString(a.EmailClaim),
+		RolesClaim:       FromAPIString(a.RolesClaim),
+	}
+	for _, scope := range a.Scopes {
+		config.Scopes = append(config.Scopes, FromAPIString(scope))
+	}
+	for _, m := range a.RoleMappings {
+		mapping := evergreen.OIDCRoleMapping{ClaimValue: FromAPIString(m.ClaimValue)}
+		for _, r := range m.Roles {
+			mapping.Roles = append(mapping.Roles, FromAPIString(r))
+		}
+		config.RoleMappings = append(config.RoleMappings, mapping)
+	}
+	return &config, nil
+}
+
 // APIBanner is a public structure representing the banner part of the admin settings
 type APIBanner struct {
 	Text  APIString `json:"banner"`
@@ -946,10 +1042,80 @@ func (a *APIVSphereConfig) ToService() (interface{}, error) {
 	}, nil
 }
 
+type APIRateLimitConfig struct {
+	Default              APIRateLimit             `json:"default"`
+	RouteGroups          []APIRouteGroupRateLimit `json:"route_groups"`
+	ExemptUsers          []string                 `json:"exempt_users"`
+	MaxPatchesPerDay     int                      `json:"max_patches_per_day"`
+	MaxSpawnHostsPerUser int                      `json:"max_spawn_hosts_per_user"`
+}
+
+type APIRateLimit struct {
+	RequestsPerMinute int `json:"requests_per_minute"`
+	Burst             int `json:"burst"`
+}
+
+type APIRouteGroupRateLimit struct {
+	Name         APIString    `json:"name"`
+	PathPrefixes []string     `json:"path_prefixes"`
+	Limit        APIRateLimit `json:"limit"`
+}
+
+func (a *APIRateLimitConfig) BuildFromService(h interface{}) error {
+	switch v := h.(type) {
+	case evergreen.RateLimitConfig:
+		a.Default = APIRateLimit{
+			RequestsPerMinute: v.Default.RequestsPerMinute,
+			Burst:             v.Default.Burst,
+		}
+		a.RouteGroups = []APIRouteGroupRateLimit{}
+		for _, group := range v.RouteGroups {
+			a.RouteGroups = append(a.RouteGroups, APIRouteGroupRateLimit{
+				Name:         ToAPIString(group.Name),
+				PathPrefixes: group.PathPrefixes,
+				Limit: APIRateLimit{
+					RequestsPerMinute: group.Limit.RequestsPerMinute,
+					Burst:             group.Limit.Burst,
+				},
+			})
+		}
+		a.ExemptUsers = v.ExemptUsers
+		a.MaxPatchesPerDay = v.MaxPatchesPerDay
+		a.MaxSpawnHostsPerUser = v.MaxSpawnHostsPerUser
+	default:
+		return errors.Errorf("%T is not a supported type", h)
+	}
+	return nil
+}
+
+func (a *APIRateLimitConfig) ToService() (interface{}, error) {
+	config := evergreen.RateLimitConfig{
+		Default: evergreen.RateLimit{
+			RequestsPerMinute: a.Default.RequestsPerMinute,
+			Burst:             a.Default.Burst,
+		},
+		ExemptUsers:          a.ExemptUsers,
+		MaxPatchesPerDay:     a.MaxPatchesPerDay,
+		MaxSpawnHostsPerUser: a.MaxSpawnHostsPerUser,
+	}
+	for _, group := range a.RouteGroups {
+		config.RouteGroups = append(config.RouteGroups, evergreen.RouteGroupRateLimit{
+			Name:         FromAPIString(group.Name),
+			PathPrefixes: group.PathPrefixes,
+			Limit: evergreen.RateLimit{
+				RequestsPerMinute: group.Limit.RequestsPerMinute,
+				Burst:             group.Limit.Burst,
+			},
+		})
+	}
+	return config, nil
+}
+
 type APIRepoTrackerConfig struct {
-	NumNewRepoRevisionsToFetch int `json:"revs_to_fetch"`
-	MaxRepoRevisionsToSearch   int `json:"max_revs_to_search"`
-	MaxConcurrentRequests      int `json:"max_con_requests"`
+	NumNewRepoRevisionsToFetch int       `json:"revs_to_fetch"`
+	MaxRepoRevisionsToSearch   int       `json:"max_revs_to_search"`
+	MaxConcurrentRequests      int       `json:"max_con_requests"`
+	MirrorDirectory            APIString `json:"mirror_directory"`
 }
 
 func (a *APIRepoTrackerConfig) BuildFromService(h interface{}) error {
@@ -958,6 +1124,7 @@ func (a *APIRepoTrackerConfig) BuildFromService(h interface{}) error {
 		a.NumNewRepoRevisionsToFetch = v.NumNewRepoRevisionsToFetch
 		a.MaxConcurrentRequests = v.MaxConcurrentRequests
 		a.MaxRepoRevisionsToSearch = v.MaxRepoRevisionsToSearch
+		a.MirrorDirectory = ToAPIString(v.MirrorDirectory)
 	default:
 		return errors.Errorf("%T is not a supported type", h)
 	}
@@ -969,6 +1136,7 @@ func (a *APIRepoTrackerConfig) ToService() (interface{}, error) {
 		NumNewRepoRevisionsToFetch: a.NumNewRepoRevisionsToFetch,
 		MaxConcurrentRequests:      a.MaxConcurrentRequests,
 		MaxRepoRevisionsToSearch:   a.MaxRepoRevisionsToSearch,
+		MirrorDirectory:            FromAPIString(a.MirrorDirectory),
 	}, nil
 }
 
@@ -1003,11 +1171,11 @@ type APIServiceFlags struct {
 	TaskDispatchDisabled         bool `json:"task_dispatch_disabled"`
 	HostinitDisabled             bool `json:"hostinit_disabled"`
 	MonitorDisabled              bool `json:"monitor_disabled"`
-	AlertsDisabled               bool `json:"alerts_disabled"`
 	TaskrunnerDisabled           bool `json:"taskrunner_disabled"`
 	RepotrackerDisabled          bool `json:"repotracker_disabled"`
 	SchedulerDisabled            bool `json:"scheduler_disabled"`
 	GithubPRTestingDisabled      bool `json:"github_pr_testing_disabled"`
+	GitlabMRTestingDisabled      bool `json:"gitlab_mr_testing_disabled"`
 	RepotrackerPushEventDisabled bool `json:"repotracker_push_event_disabled"`
 	CLIUpdatesDisabled           bool `json:"cli_updates_disabled"`
 	BackgroundStatsDisabled      bool `json:"background_stats_disabled"`
@@ -1020,6 +1188,7 @@ type APIServiceFlags struct {
 	EmailNotificationsDisabled   bool `json:"email_notifications_disabled"`
 	WebhookNotificationsDisabled bool `json:"webhook_notifications_disabled"`
 	GithubStatusAPIDisabled      bool `json:"github_status_api_disabled"`
+	GitlabStatusAPIDisabled      bool `json:"gitlab_status_api_disabled"`
 }
 
 type APISlackConfig struct {
@@ -1196,11 +1365,11 @@ func (as *APIServiceFlags) BuildFromService(h interface{}) error {
 		as.TaskDispatchDisabled = v.TaskDispatchDisabled
 		as.HostinitDisabled = v.HostinitDisabled
 		as.MonitorDisabled = v.MonitorDisabled
-		as.AlertsDisabled = v.AlertsDisabled
 		as.TaskrunnerDisabled = v.TaskrunnerDisabled
 		as.RepotrackerDisabled = v.RepotrackerDisabled
 		as.SchedulerDisabled = v.SchedulerDisabled
 		as.GithubPRTestingDisabled = v.GithubPRTestingDisabled
+		as.GitlabMRTestingDisabled = v.GitlabMRTestingDisabled
 		as.RepotrackerPushEventDisabled = v.RepotrackerPushEventDisabled
 		as.CLIUpdatesDisabled = v.CLIUpdatesDisabled
 		as.EventProcessingDisabled = v.EventProcessingDisabled
@@ -1209,6 +1378,7 @@ func (as *APIServiceFlags) BuildFromService(h interface{}) error {
 		as.EmailNotificationsDisabled = v.EmailNotificationsDisabled
 		as.WebhookNotificationsDisabled = v.WebhookNotificationsDisabled
 		as.GithubStatusAPIDisabled = v.GithubStatusAPIDisabled
+		as.GitlabStatusAPIDisabled = v.GitlabStatusAPIDisabled
 		as.BackgroundStatsDisabled = v.BackgroundStatsDisabled
 		as.TaskLoggingDisabled = v.TaskLoggingDisabled
 	default:
@@ -1223,11 +1393,11 @@ func (as *APIServiceFlags) ToService() (interface{}, error) {
 		TaskDispatchDisabled:         as.TaskDispatchDisabled,
 		HostinitDisabled:             as.HostinitDisabled,
 		MonitorDisabled:              as.MonitorDisabled,
-		AlertsDisabled:               as.AlertsDisabled,
 		TaskrunnerDisabled:           as.TaskrunnerDisabled,
 		RepotrackerDisabled:          as.RepotrackerDisabled,
 		SchedulerDisabled:            as.SchedulerDisabled,
 		GithubPRTestingDisabled:      as.GithubPRTestingDisabled,
+		GitlabMRTestingDisabled:      as.GitlabMRTestingDisabled,
 		RepotrackerPushEventDisabled: as.RepotrackerPushEventDisabled,
 		CLIUpdatesDisabled:           as.CLIUpdatesDisabled,
 		EventProcessingDisabled:      as.EventProcessingDisabled,
@@ -1236,6 +1406,7 @@ func (as *APIServiceFlags) ToService() (interface{}, error) {
 		EmailNotificationsDisabled:   as.EmailNotificationsDisabled,
 		WebhookNotificationsDisabled: as.WebhookNotificationsDisabled,
 		GithubStatusAPIDisabled:      as.GithubStatusAPIDisabled,
+		GitlabStatusAPIDisabled:      as.GitlabStatusAPIDisabled,
 		BackgroundStatsDisabled:      as.BackgroundStatsDisabled,
 		TaskLoggingDisabled:          as.TaskLoggingDisabled,
 	}, nil
@@ -87,6 +87,8 @@ func TestModelConversion(t *testing.T) {
 	assert.EqualValues(testSettings.Amboy.Name, FromAPIString(apiSettings.Amboy.Name))
 	assert.EqualValues(testSettings.Amboy.LocalStorage, apiSettings.Amboy.LocalStorage)
 	assert.EqualValues(testSettings.Api.HttpListenAddr, FromAPIString(apiSettings.Api.HttpListenAddr))
+	assert.EqualValues(testSettings.Api.GitlabWebhookSecret, FromAPIString(apiSettings.Api.GitlabWebhookSecret))
+	assert.EqualValues(testSettings.Api.TrustedProxies, apiSettings.Api.TrustedProxies)
 	assert.EqualValues(testSettings.AuthConfig.Crowd.Username, FromAPIString(apiSettings.AuthConfig.Crowd.Username))
 	assert.EqualValues(testSettings.AuthConfig.Naive.Users[0].Username, FromAPIString(apiSettings.AuthConfig.Naive.Users[0].Username))
 	assert.EqualValues(testSettings.ContainerPools.Pools[0].Distro, FromAPIString(apiSettings.ContainerPools.Pools[0].Distro))
@@ -95,6 +97,8 @@ func TestModelConversion(t *testing.T) {
 	assert.EqualValues(testSettings.ContainerPools.Pools[0].Port, apiSettings.ContainerPools.Pools[0].Port)
 	assert.EqualValues(testSettings.AuthConfig.Github.ClientId, FromAPIString(apiSettings.AuthConfig.Github.ClientId))
 	assert.Equal(len(testSettings.AuthConfig.Github.Users), len(apiSettings.AuthConfig.Github.Users))
+	assert.EqualValues(testSettings.AuthConfig.OIDC.Issuer, FromAPIString(apiSettings.AuthConfig.OIDC.Issuer))
+	assert.Equal(len(testSettings.AuthConfig.OIDC.RoleMappings), len(apiSettings.AuthConfig.OIDC.RoleMappings))
 	assert.EqualValues(testSettings.HostInit.SSHTimeoutSeconds, apiSettings.HostInit.SSHTimeoutSeconds)
 	assert.EqualValues(testSettings.Jira.Username, FromAPIString(apiSettings.Jira.Username))
 	assert.EqualValues(testSettings.LoggerConfig.DefaultLevel, FromAPIString(apiSettings.LoggerConfig.DefaultLevel))
@@ -107,7 +111,12 @@ func TestModelConversion(t *testing.T) {
 	assert.EqualValues(testSettings.Providers.GCE.ClientEmail, FromAPIString(apiSettings.Providers.GCE.ClientEmail))
 	assert.EqualValues(testSettings.Providers.OpenStack.IdentityEndpoint, FromAPIString(apiSettings.Providers.OpenStack.IdentityEndpoint))
 	assert.EqualValues(testSettings.Providers.VSphere.Host, FromAPIString(apiSettings.Providers.VSphere.Host))
+	assert.EqualValues(testSettings.RateLimit.Default.RequestsPerMinute, apiSettings.RateLimit.Default.RequestsPerMinute)
+	assert.EqualValues(testSettings.RateLimit.RouteGroups[0].Name, FromAPIString(apiSettings.RateLimit.RouteGroups[0].Name))
+	assert.EqualValues(testSettings.RateLimit.RouteGroups[0].Limit.Burst, apiSettings.RateLimit.RouteGroups[0].Limit.Burst)
+	assert.EqualValues(testSettings.RateLimit.MaxPatchesPerDay, apiSettings.RateLimit.MaxPatchesPerDay)
 	assert.EqualValues(testSettings.RepoTracker.MaxConcurrentRequests, apiSettings.RepoTracker.MaxConcurrentRequests)
+	assert.EqualValues(testSettings.RepoTracker.MirrorDirectory, FromAPIString(apiSettings.RepoTracker.MirrorDirectory))
 	assert.EqualValues(testSettings.Scheduler.TaskFinder, FromAPIString(apiSettings.Scheduler.TaskFinder))
 	assert.EqualValues(testSettings.ServiceFlags.HostinitDisabled, apiSettings.ServiceFlags.HostinitDisabled)
 	assert.EqualValues(testSettings.Slack.Level, FromAPIString(apiSettings.Slack.Level))
@@ -125,10 +134,13 @@ func TestModelConversion(t *testing.T) {
 	assert.EqualValues(testSettings.Amboy.Name, dbSettings.Amboy.Name)
 	assert.EqualValues(testSettings.Amboy.LocalStorage, dbSettings.Amboy.LocalStorage)
 	assert.EqualValues(testSettings.Api.HttpListenAddr, dbSettings.Api.HttpListenAddr)
+	assert.EqualValues(testSettings.Api.GitlabWebhookSecret, dbSettings.Api.GitlabWebhookSecret)
+	assert.EqualValues(testSettings.Api.TrustedProxies, dbSettings.Api.TrustedProxies)
 	assert.EqualValues(testSettings.AuthConfig.Crowd.Username, dbSettings.AuthConfig.Crowd.Username)
 	assert.EqualValues(testSettings.AuthConfig.Naive.Users[0].Username, dbSettings.AuthConfig.Naive.Users[0].Username)
 	assert.EqualValues(testSettings.AuthConfig.Github.ClientId, dbSettings.AuthConfig.Github.ClientId)
 	assert.Equal(len(testSettings.AuthConfig.Github.Users), len(dbSettings.AuthConfig.Github.Users))
+	assert.EqualValues(testSettings.AuthConfig.OIDC, dbSettings.AuthConfig.OIDC)
 	assert.EqualValues(testSettings.ContainerPools.Pools[0].Distro, dbSettings.ContainerPools.Pools[0].Distro)
 	assert.EqualValues(testSettings.ContainerPools.Pools[0].Id, dbSettings.ContainerPools.Pools[0].Id)
 	assert.EqualValues(testSettings.ContainerPools.Pools[0].MaxContainers, dbSettings.ContainerPools.Pools[0].MaxContainers)
@@ -145,7 +157,9 @@ func TestModelConversion(t *testing.T) {
 	assert.EqualValues(testSettings.Providers.GCE.ClientEmail, dbSettings.Providers.GCE.ClientEmail)
 	assert.EqualValues(testSettings.Providers.OpenStack.IdentityEndpoint, dbSettings.Providers.OpenStack.IdentityEndpoint)
 	assert.EqualValues(testSettings.Providers.VSphere.Host, dbSettings.Providers.VSphere.Host)
+	assert.EqualValues(testSettings.RateLimit, dbSettings.RateLimit)
 	assert.EqualValues(testSettings.RepoTracker.MaxConcurrentRequests, dbSettings.RepoTracker.MaxConcurrentRequests)
+	assert.EqualValues(testSettings.RepoTracker.MirrorDirectory, dbSettings.RepoTracker.MirrorDirectory)
 	assert.EqualValues(testSettings.Scheduler.TaskFinder, dbSettings.Scheduler.TaskFinder)
 	assert.EqualValues(testSettings.ServiceFlags.HostinitDisabled, dbSettings.ServiceFlags.HostinitDisabled)
 	assert.EqualValues(testSettings.Slack.Level, dbSettings.Slack.Level)
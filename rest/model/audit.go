@@ -0,0 +1,75 @@
+package model
+
+import (
+	"github.com/evergreen-ci/evergreen/model/audit"
+	"github.com/pkg/errors"
+)
+
+// APIAuditEntry is the model to be returned by the API whenever audit log
+// entries are fetched.
+type APIAuditEntry struct {
+	ID           APIString        `json:"id"`
+	Timestamp    APITime          `json:"timestamp"`
+	Actor        APIString        `json:"actor"`
+	SourceIP     APIString        `json:"source_ip"`
+	Method       APIString        `json:"method"`
+	Path         APIString        `json:"path"`
+	Status       int              `json:"status"`
+	Action       APIString        `json:"action"`
+	ResourceType APIString        `json:"resource_type"`
+	ResourceID   APIString        `json:"resource_id"`
+	Changes      []APIAuditChange `json:"changes"`
+}
+
+// APIAuditChange is the before and after value of a field changed by an
+// audited action.
+type APIAuditChange struct {
+	ResourceType APIString   `json:"resource_type"`
+	ResourceID   APIString   `json:"resource_id"`
+	Field        APIString   `json:"field"`
+	Before       interface{} `json:"before"`
+	After        interface{} `json:"after"`
+}
+
+// BuildFromService converts from service level structs to an
+// APIAuditEntry.
+func (a *APIAuditEntry) BuildFromService(h interface{}) error {
+	var e audit.Entry
+	switch v := h.(type) {
+	case audit.Entry:
+		e = v
+	case *audit.Entry:
+		e = *v
+	default:
+		return errors.Errorf("%T is not a supported audit log entry type", h)
+	}
+
+	a.ID = ToAPIString(e.ID.Hex())
+	a.Timestamp = NewTime(e.Timestamp)
+	a.Actor = ToAPIString(e.Actor)
+	a.SourceIP = ToAPIString(e.SourceIP)
+	a.Method = ToAPIString(e.Method)
+	a.Path = ToAPIString(e.Path)
+	a.Status = e.Status
+	a.Action = ToAPIString(e.Action)
+	a.ResourceType = ToAPIString(e.ResourceType)
+	a.ResourceID = ToAPIString(e.ResourceID)
+	a.Changes = make([]APIAuditChange, len(e.Changes))
+	for i, c := range e.Changes {
+		a.Changes[i] = APIAuditChange{
+			ResourceType: ToAPIString(c.ResourceType),
+			ResourceID:   ToAPIString(c.ResourceID),
+			Field:        ToAPIString(c.Field),
+			Before:       c.Before,
+			After:        c.After,
+		}
+	}
+
+	return nil
+}
+
+// ToService is not implemented for APIAuditEntry, since the audit log
+// cannot be modified through the API.
+func (a *APIAuditEntry) ToService() (interface{}, error) {
+	return nil, errors.New("not implemented for audit log entries")
+}
@@ -0,0 +1,44 @@
+package model
+
+import (
+	"testing"
+	"time"
+
+	"github.com/evergreen-ci/evergreen/model/audit"
+	"github.com/stretchr/testify/assert"
+	"gopkg.in/mgo.v2/bson"
+)
+
+func TestAPIAuditEntry(t *testing.T) {
+	assert := assert.New(t)
+	e := audit.Entry{
+		ID:           bson.NewObjectId(),
+		Timestamp:    time.Now(),
+		Actor:        "me",
+		SourceIP:     "10.0.0.1",
+		Method:       "PATCH",
+		Path:         "/rest/v2/tasks/t1",
+		Status:       200,
+		Action:       "update",
+		ResourceType: "task",
+		ResourceID:   "t1",
+		Changes: []audit.Change{
+			{ResourceType: "task", ResourceID: "t1", Field: "priority", Before: 0, After: 10},
+		},
+	}
+
+	apiEntry := APIAuditEntry{}
+	assert.NoError(apiEntry.BuildFromService(&e))
+	assert.Equal(e.ID.Hex(), FromAPIString(apiEntry.ID))
+	assert.Equal("me", FromAPIString(apiEntry.Actor))
+	assert.Equal("10.0.0.1", FromAPIString(apiEntry.SourceIP))
+	assert.Equal(200, apiEntry.Status)
+	assert.Equal("task", FromAPIString(apiEntry.ResourceType))
+	assert.Len(apiEntry.Changes, 1)
+	assert.Equal("priority", FromAPIString(apiEntry.Changes[0].Field))
+	assert.Equal(10, apiEntry.Changes[0].After)
+
+	assert.Error(apiEntry.BuildFromService(&audit.Change{}))
+	_, err := apiEntry.ToService()
+	assert.Error(err)
+}
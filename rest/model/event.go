@@ -0,0 +1,47 @@
+package model
+
+import (
+	"errors"
+
+	"github.com/evergreen-ci/evergreen/model/event"
+)
+
+// APIEventLogEntry is a generic representation of an entry in the
+// event log, as returned by the event stream.
+type APIEventLogEntry struct {
+	ID           APIString   `json:"id"`
+	ResourceType APIString   `json:"resource_type"`
+	ResourceId   APIString   `json:"resource_id"`
+	EventType    APIString   `json:"event_type"`
+	Timestamp    APITime     `json:"timestamp"`
+	Data         interface{} `json:"data"`
+}
+
+func (e *APIEventLogEntry) BuildFromService(h interface{}) error {
+	switch v := h.(type) {
+	case event.EventLogEntry:
+		e.ID = ToAPIString(v.ID)
+		e.ResourceType = ToAPIString(v.ResourceType)
+		e.ResourceId = ToAPIString(v.ResourceId)
+		e.EventType = ToAPIString(v.EventType)
+		e.Timestamp = NewTime(v.Timestamp)
+		e.Data = v.Data
+	case *event.EventLogEntry:
+		return e.BuildFromService(*v)
+	default:
+		return errors.New("unrecognized type for APIEventLogEntry")
+	}
+
+	return nil
+}
+
+func (e *APIEventLogEntry) ToService() (interface{}, error) {
+	return nil, errors.New("ToService not implemented for APIEventLogEntry")
+}
+
+// APIEventStreamBatch is a batch of events returned by the event
+// stream, along with the position to resume the stream from.
+type APIEventStreamBatch struct {
+	Events      []APIEventLogEntry `json:"events"`
+	LastEventID APIString          `json:"last_event_id"`
+}
@@ -7,30 +7,40 @@ import (
 
 // APIPatch is the model to be returned by the API whenever patches are fetched.
 type APIPatch struct {
-	Id              APIString     `json:"patch_id"`
-	Description     APIString     `json:"description"`
-	ProjectId       APIString     `json:"project_id"`
-	Branch          APIString     `json:"branch"`
-	Githash         APIString     `json:"git_hash"`
-	PatchNumber     int           `json:"patch_number"`
-	Author          APIString     `json:"author"`
-	Version         APIString     `json:"version"`
-	Status          APIString     `json:"status"`
-	CreateTime      APITime       `json:"create_time"`
-	StartTime       APITime       `json:"start_time"`
-	FinishTime      APITime       `json:"finish_time"`
-	Variants        []APIString   `json:"builds"`
-	Tasks           []APIString   `json:"tasks"`
-	VariantsTasks   []variantTask `json:"variants_tasks"`
-	Activated       bool          `json:"activated"`
-	Alias           APIString     `json:"alias,omitempty"`
-	GithubPatchData githubPatch   `json:"github_patch_data,omitempty"`
+	Id              APIString        `json:"patch_id"`
+	Description     APIString        `json:"description"`
+	ProjectId       APIString        `json:"project_id"`
+	Branch          APIString        `json:"branch"`
+	Githash         APIString        `json:"git_hash"`
+	PatchNumber     int              `json:"patch_number"`
+	Author          APIString        `json:"author"`
+	Version         APIString        `json:"version"`
+	Status          APIString        `json:"status"`
+	CreateTime      APITime          `json:"create_time"`
+	StartTime       APITime          `json:"start_time"`
+	FinishTime      APITime          `json:"finish_time"`
+	Variants        []APIString      `json:"builds"`
+	Tasks           []APIString      `json:"tasks"`
+	VariantsTasks   []variantTask    `json:"variants_tasks"`
+	Activated       bool             `json:"activated"`
+	Alias           APIString        `json:"alias,omitempty"`
+	SourceRef       APIString        `json:"source_ref,omitempty"`
+	SkippedTasks    []APISkippedTask `json:"skipped_tasks,omitempty"`
+	GithubPatchData githubPatch      `json:"github_patch_data,omitempty"`
+	GitlabPatchData gitlabPatch      `json:"gitlab_patch_data,omitempty"`
 }
 type variantTask struct {
 	Name  APIString   `json:"name"`
 	Tasks []APIString `json:"tasks"`
 }
 
+// APISkippedTask is a task of a patch that wasn't run, and why.
+type APISkippedTask struct {
+	Variant APIString `json:"variant"`
+	Task    APIString `json:"task"`
+	Reason  APIString `json:"reason"`
+}
+
 // BuildFromService converts from service level structs to an APIPatch
 func (apiPatch *APIPatch) BuildFromService(h interface{}) error {
 	v, ok := h.(patch.Patch)
@@ -73,8 +83,21 @@ func (apiPatch *APIPatch) BuildFromService(h interface{}) error {
 	apiPatch.VariantsTasks = variantTasks
 	apiPatch.Activated = v.Activated
 	apiPatch.Alias = ToAPIString(v.Alias)
+	apiPatch.SourceRef = ToAPIString(v.SourceRef)
+	apiPatch.SkippedTasks = nil
+	for _, st := range v.SkippedTasks {
+		apiPatch.SkippedTasks = append(apiPatch.SkippedTasks, APISkippedTask{
+			Variant: ToAPIString(st.Variant),
+			Task:    ToAPIString(st.Task),
+			Reason:  ToAPIString(st.Reason),
+		})
+	}
 	apiPatch.GithubPatchData = githubPatch{}
-	return errors.WithStack(apiPatch.GithubPatchData.BuildFromService(v.GithubPatchData))
+	if err := apiPatch.GithubPatchData.BuildFromService(v.GithubPatchData); err != nil {
+		return errors.WithStack(err)
+	}
+	apiPatch.GitlabPatchData = gitlabPatch{}
+	return errors.WithStack(apiPatch.GitlabPatchData.BuildFromService(v.GitlabPatchData))
 }
 
 // ToService converts a service layer patch using the data from APIPatch
@@ -112,3 +135,40 @@ func (g *githubPatch) BuildFromService(h interface{}) error {
 func (g *githubPatch) ToService() (interface{}, error) {
 	return nil, errors.New("(*githubPatch) ToService not implemented for read-only route")
 }
+
+type gitlabPatch struct {
+	MRNumber    int       `json:"mr_number"`
+	ProjectID   int       `json:"project_id"`
+	ProjectPath APIString `json:"project_path"`
+	BaseBranch  APIString `json:"base_branch"`
+	HeadHash    APIString `json:"head_hash"`
+	Author      APIString `json:"author"`
+}
+
+// BuildFromService converts from service level structs to an APIPatch
+func (g *gitlabPatch) BuildFromService(h interface{}) error {
+	v, ok := h.(patch.GitlabPatch)
+	if !ok {
+		return errors.New("incorrect type when fetching converting gitlab patch type")
+	}
+	g.MRNumber = v.MRNumber
+	g.ProjectID = v.ProjectID
+	g.ProjectPath = ToAPIString(v.ProjectPath)
+	g.BaseBranch = ToAPIString(v.BaseBranch)
+	g.HeadHash = ToAPIString(v.HeadHash)
+	g.Author = ToAPIString(v.Author)
+	return nil
+}
+
+// ToService converts a service layer patch using the data from APIPatch
+func (g *gitlabPatch) ToService() (interface{}, error) {
+	return nil, errors.New("(*gitlabPatch) ToService not implemented for read-only route")
+}
+
+// PatchRebaseRequest is the format of a POST request to
+// /patches/{patch_id}/rebase. The patch is rebased onto the base revision,
+// or else onto the latest mainline revision of its project.
+type PatchRebaseRequest struct {
+	Base     string `json:"base"`
+	Finalize bool   `json:"finalize"`
+}
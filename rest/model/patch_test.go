@@ -7,6 +7,7 @@ import (
 	"github.com/evergreen-ci/evergreen"
 	"github.com/evergreen-ci/evergreen/model/patch"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 	"gopkg.in/mgo.v2/bson"
 )
 
@@ -43,6 +44,9 @@ func TestAPIPatch(t *testing.T) {
 		Activated:     true,
 		PatchedConfig: "config",
 		Alias:         "__github",
+		SkippedTasks: []patch.SkippedTask{
+			{Variant: "linux", Task: "lint", Reason: "not affected"},
+		},
 		GithubPatchData: patch.GithubPatch{
 			PRNumber:  123,
 			BaseOwner: "evergreen-ci",
@@ -84,6 +88,10 @@ func TestAPIPatch(t *testing.T) {
 		}
 	}
 	assert.Equal("__github", FromAPIString(a.Alias))
+	require.Len(t, a.SkippedTasks, 1)
+	assert.Equal("linux", FromAPIString(a.SkippedTasks[0].Variant))
+	assert.Equal("lint", FromAPIString(a.SkippedTasks[0].Task))
+	assert.Equal("not affected", FromAPIString(a.SkippedTasks[0].Reason))
 	assert.NotZero(a.GithubPatchData)
 }
 
@@ -109,3 +117,24 @@ func TestGithubPatch(t *testing.T) {
 	assert.Equal("hash", FromAPIString(a.HeadHash))
 	assert.Equal("octocat", FromAPIString(a.Author))
 }
+
+func TestGitlabPatch(t *testing.T) {
+	assert := assert.New(t)
+	p := patch.GitlabPatch{
+		MRNumber:    7,
+		ProjectID:   42,
+		ProjectPath: "group/evergreen",
+		BaseBranch:  "master",
+		HeadHash:    "hash",
+		Author:      "tanuki",
+	}
+	a := gitlabPatch{}
+	err := a.BuildFromService(p)
+	assert.NoError(err)
+	assert.Equal(7, a.MRNumber)
+	assert.Equal(42, a.ProjectID)
+	assert.Equal("group/evergreen", FromAPIString(a.ProjectPath))
+	assert.Equal("master", FromAPIString(a.BaseBranch))
+	assert.Equal("hash", FromAPIString(a.HeadHash))
+	assert.Equal("tanuki", FromAPIString(a.Author))
+}
@@ -8,27 +8,35 @@ import (
 )
 
 type APIProject struct {
-	BatchTime          int                      `json:"batch_time"`
-	Branch             APIString                `json:"branch_name"`
-	DisplayName        APIString                `json:"display_name"`
-	Enabled            bool                     `json:"enabled"`
-	Identifier         APIString                `json:"identifier"`
-	Owner              APIString                `json:"owner_name"`
-	Private            bool                     `json:"private"`
-	RemotePath         APIString                `json:"remote_path"`
-	Repo               APIString                `json:"repo_name"`
-	Tracked            bool                     `json:"tracked"`
-	AlertSettings      map[string][]alertConfig `json:"alert_settings"`
-	DeactivatePrevious bool                     `json:"deactivate_previous"`
-	Admins             []APIString              `json:"admins"`
-	Vars               map[string]string        `json:"vars"`
-	TracksPushEvents   bool                     `json:"tracks_push_events"`
-	PRTestingEnabled   bool                     `json:"pr_testing_enabled"`
+	BatchTime          int               `json:"batch_time"`
+	Branch             APIString         `json:"branch_name"`
+	DisplayName        APIString         `json:"display_name"`
+	Enabled            bool              `json:"enabled"`
+	Identifier         APIString         `json:"identifier"`
+	Owner              APIString         `json:"owner_name"`
+	Private            bool              `json:"private"`
+	RemotePath         APIString         `json:"remote_path"`
+	Repo               APIString         `json:"repo_name"`
+	Tracked            bool              `json:"tracked"`
+	DeactivatePrevious bool              `json:"deactivate_previous"`
+	Admins             []APIString       `json:"admins"`
+	Vars               map[string]string `json:"vars"`
+	TracksPushEvents   bool              `json:"tracks_push_events"`
+	PRTestingEnabled   bool              `json:"pr_testing_enabled"`
+	CronSchedules      []APICronSchedule `json:"cron_schedules"`
+	Downstream         []APIDownstream   `json:"downstream"`
 }
 
-type alertConfig struct {
-	Provider APIString         `json:"provider"`
-	Settings map[string]string `json:"settings"`
+type APIDownstream struct {
+	Project            APIString   `json:"project"`
+	Level              APIString   `json:"level"`
+	BuildVariant       APIString   `json:"build_variant"`
+	DownstreamVariants []APIString `json:"downstream_variants"`
+}
+
+type APICronSchedule struct {
+	Cron          APIString   `json:"cron"`
+	BuildVariants []APIString `json:"build_variants"`
 }
 
 func (apiProject *APIProject) BuildFromService(p interface{}) error {
@@ -49,20 +57,6 @@ func (apiProject *APIProject) BuildFromService(p interface{}) error {
 	apiProject.TracksPushEvents = v.TracksPushEvents
 	apiProject.PRTestingEnabled = v.PRTestingEnabled
 
-	alertSettings := make(map[string][]alertConfig)
-	for k, v := range v.Alerts {
-		configArr := []alertConfig{}
-		for _, c := range v {
-			config := alertConfig{
-				Provider: ToAPIString(c.Provider),
-				Settings: c.GetSettingsMap(),
-			}
-			configArr = append(configArr, config)
-		}
-		alertSettings[k] = configArr
-	}
-
-	apiProject.AlertSettings = alertSettings
 	apiProject.DeactivatePrevious = v.DeactivatePrevious
 
 	admins := []APIString{}
@@ -71,6 +65,34 @@ func (apiProject *APIProject) BuildFromService(p interface{}) error {
 	}
 	apiProject.Admins = admins
 
+	schedules := []APICronSchedule{}
+	for _, schedule := range v.CronSchedules {
+		variants := []APIString{}
+		for _, bv := range schedule.BuildVariants {
+			variants = append(variants, ToAPIString(bv))
+		}
+		schedules = append(schedules, APICronSchedule{
+			Cron:          ToAPIString(schedule.Cron),
+			BuildVariants: variants,
+		})
+	}
+	apiProject.CronSchedules = schedules
+
+	downstream := []APIDownstream{}
+	for _, d := range v.Downstream {
+		variants := []APIString{}
+		for _, bv := range d.DownstreamVariants {
+			variants = append(variants, ToAPIString(bv))
+		}
+		downstream = append(downstream, APIDownstream{
+			Project:            ToAPIString(d.Project),
+			Level:              ToAPIString(d.Level),
+			BuildVariant:       ToAPIString(d.BuildVariant),
+			DownstreamVariants: variants,
+		})
+	}
+	apiProject.Downstream = downstream
+
 	return nil
 }
 
@@ -0,0 +1,66 @@
+package model
+
+import (
+	"github.com/evergreen-ci/evergreen/model/role"
+	"github.com/pkg/errors"
+)
+
+// APIRole is the model to be returned by the API whenever roles are fetched.
+type APIRole struct {
+	ID           APIString   `json:"id"`
+	Name         APIString   `json:"name"`
+	ResourceType APIString   `json:"resource_type"`
+	Resources    []APIString `json:"resources"`
+	Permissions  []APIString `json:"permissions"`
+}
+
+// BuildFromService converts from service level structs to an APIRole.
+func (apiRole *APIRole) BuildFromService(h interface{}) error {
+	var r role.Role
+	switch v := h.(type) {
+	case role.Role:
+		r = v
+	case *role.Role:
+		r = *v
+	default:
+		return errors.Errorf("%T is not a supported role type", h)
+	}
+
+	apiRole.ID = ToAPIString(r.ID)
+	apiRole.Name = ToAPIString(r.Name)
+	apiRole.ResourceType = ToAPIString(r.ResourceType)
+	apiRole.Resources = []APIString{}
+	for _, resource := range r.Resources {
+		apiRole.Resources = append(apiRole.Resources, ToAPIString(resource))
+	}
+	apiRole.Permissions = []APIString{}
+	for _, permission := range r.Permissions {
+		apiRole.Permissions = append(apiRole.Permissions, ToAPIString(permission))
+	}
+
+	return nil
+}
+
+// ToService returns a service layer role using the data from APIRole.
+func (apiRole *APIRole) ToService() (interface{}, error) {
+	r := role.Role{
+		ID:           FromAPIString(apiRole.ID),
+		Name:         FromAPIString(apiRole.Name),
+		ResourceType: FromAPIString(apiRole.ResourceType),
+		Resources:    []string{},
+		Permissions:  []string{},
+	}
+	for _, resource := range apiRole.Resources {
+		r.Resources = append(r.Resources, FromAPIString(resource))
+	}
+	for _, permission := range apiRole.Permissions {
+		r.Permissions = append(r.Permissions, FromAPIString(permission))
+	}
+
+	return r, nil
+}
+
+// APIUserRoles is the model returned by the API for the roles of a user.
+type APIUserRoles struct {
+	Roles []string `json:"roles"`
+}
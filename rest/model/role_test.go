@@ -0,0 +1,31 @@
+package model
+
+import (
+	"testing"
+
+	"github.com/evergreen-ci/evergreen/model/role"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRoleRoundTrip(t *testing.T) {
+	assert := assert.New(t)
+
+	r := role.Role{
+		ID:           "mci-admins",
+		Name:         "MCI admins",
+		ResourceType: role.ResourceTypeProject,
+		Resources:    []string{"mci"},
+		Permissions:  []string{role.PermissionEditSettings, role.PermissionManageVars},
+	}
+	apiRole := &APIRole{}
+	assert.NoError(apiRole.BuildFromService(r))
+	assert.Equal("mci-admins", FromAPIString(apiRole.ID))
+	assert.Equal(role.ResourceTypeProject, FromAPIString(apiRole.ResourceType))
+	assert.Len(apiRole.Permissions, 2)
+
+	out, err := apiRole.ToService()
+	assert.NoError(err)
+	assert.Equal(r, out)
+
+	assert.Error(apiRole.BuildFromService("mci-admins"))
+}
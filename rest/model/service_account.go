@@ -0,0 +1,68 @@
+package model
+
+import (
+	"time"
+
+	"github.com/evergreen-ci/evergreen/model/user"
+	"github.com/pkg/errors"
+)
+
+// APIServiceAccount is the model to be returned by the API whenever service
+// accounts are fetched.
+type APIServiceAccount struct {
+	ID          APIString   `json:"id"`
+	DisplayName APIString   `json:"display_name"`
+	Roles       []APIString `json:"roles"`
+	Tokens      []APIToken  `json:"tokens"`
+	CreatedAt   APITime     `json:"created_at"`
+}
+
+// BuildFromService converts from service level structs to an
+// APIServiceAccount.
+func (a *APIServiceAccount) BuildFromService(h interface{}) error {
+	var u user.DBUser
+	switch v := h.(type) {
+	case user.DBUser:
+		u = v
+	case *user.DBUser:
+		u = *v
+	default:
+		return errors.Errorf("%T is not a supported service account type", h)
+	}
+	if !u.IsServiceAccount() {
+		return errors.Errorf("user '%s' is not a service account", u.Id)
+	}
+
+	a.ID = ToAPIString(u.Id)
+	a.DisplayName = ToAPIString(u.DispName)
+	a.Roles = []APIString{}
+	for _, r := range u.Roles() {
+		a.Roles = append(a.Roles, ToAPIString(r))
+	}
+	a.Tokens = make([]APIToken, len(u.APITokens))
+	for i := range u.APITokens {
+		if err := a.Tokens[i].BuildFromService(u.APITokens[i]); err != nil {
+			return errors.Wrapf(err, "problem converting token '%s'", u.APITokens[i].Name)
+		}
+	}
+	a.CreatedAt = NewTime(u.CreatedAt)
+
+	return nil
+}
+
+// ToService returns a service layer user using the data from
+// APIServiceAccount. Tokens are not converted.
+func (a *APIServiceAccount) ToService() (interface{}, error) {
+	u := user.DBUser{
+		Id:             FromAPIString(a.ID),
+		DispName:       FromAPIString(a.DisplayName),
+		SystemRoles:    []string{},
+		CreatedAt:      time.Time(a.CreatedAt),
+		ServiceAccount: true,
+	}
+	for _, r := range a.Roles {
+		u.SystemRoles = append(u.SystemRoles, FromAPIString(r))
+	}
+
+	return u, nil
+}
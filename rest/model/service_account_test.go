@@ -0,0 +1,41 @@
+package model
+
+import (
+	"testing"
+	"time"
+
+	"github.com/evergreen-ci/evergreen/model/user"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAPIServiceAccount(t *testing.T) {
+	assert := assert.New(t)
+	u := user.DBUser{
+		Id:             "svc-ci",
+		DispName:       "CI",
+		SystemRoles:    []string{"patchers"},
+		ServiceAccount: true,
+		APITokens: []user.APIToken{
+			{Name: "deploy", Hash: "secret", Scopes: []string{user.TokenScopePatch}, ExpiresAt: time.Now()},
+		},
+	}
+
+	apiAccount := APIServiceAccount{}
+	assert.NoError(apiAccount.BuildFromService(&u))
+	assert.Equal("svc-ci", FromAPIString(apiAccount.ID))
+	assert.Equal("CI", FromAPIString(apiAccount.DisplayName))
+	assert.Len(apiAccount.Roles, 1)
+	assert.Len(apiAccount.Tokens, 1)
+	assert.Nil(apiAccount.Tokens[0].Token)
+
+	out, err := apiAccount.ToService()
+	assert.NoError(err)
+	roundTripped, ok := out.(user.DBUser)
+	assert.True(ok)
+	assert.Equal(u.Id, roundTripped.Id)
+	assert.Equal(u.SystemRoles, roundTripped.SystemRoles)
+	assert.True(roundTripped.IsServiceAccount())
+
+	human := user.DBUser{Id: "me"}
+	assert.Error(apiAccount.BuildFromService(human))
+}
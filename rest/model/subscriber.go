@@ -23,8 +23,9 @@ type APIGithubPRSubscriber struct {
 }
 
 type APIWebhookSubscriber struct {
-	URL    APIString `json:"url" mapstructure:"url"`
-	Secret APIString `json:"secret" mapstructure:"secret"`
+	URL           APIString `json:"url" mapstructure:"url"`
+	Secret        APIString `json:"secret" mapstructure:"secret"`
+	SecretVersion int       `json:"secret_version" mapstructure:"secret_version"`
 }
 
 func (s *APISubscriber) BuildFromService(h interface{}) error {
@@ -172,6 +173,7 @@ func (s *APIWebhookSubscriber) BuildFromService(h interface{}) error {
 	case *event.WebhookSubscriber:
 		s.URL = ToAPIString(v.URL)
 		s.Secret = ToAPIString(string(v.Secret))
+		s.SecretVersion = v.SecretVersion
 
 	default:
 		return errors.New("unknown type for APIWebhookSubscriber")
@@ -182,8 +184,9 @@ func (s *APIWebhookSubscriber) BuildFromService(h interface{}) error {
 
 func (s *APIWebhookSubscriber) ToService() (interface{}, error) {
 	return event.WebhookSubscriber{
-		URL:    FromAPIString(s.URL),
-		Secret: []byte(FromAPIString(s.Secret)),
+		URL:           FromAPIString(s.URL),
+		Secret:        []byte(FromAPIString(s.Secret)),
+		SecretVersion: s.SecretVersion,
 	}, nil
 }
 
@@ -0,0 +1,110 @@
+package model
+
+import (
+	"time"
+
+	"github.com/evergreen-ci/evergreen/model"
+	"github.com/pkg/errors"
+)
+
+// BulkTaskRequest is the format of a POST request to /tasks/bulk. The tasks
+// are either the ones listed by id, or the ones of the project that match
+// the rest of the filter.
+type BulkTaskRequest struct {
+	TaskIDs       []string  `json:"task_ids"`
+	Project       string    `json:"project"`
+	Variant       string    `json:"variant"`
+	TaskNameRegex string    `json:"task_name_regex"`
+	Statuses      []string  `json:"statuses"`
+	StartTime     time.Time `json:"start_time"`
+	EndTime       time.Time `json:"end_time"`
+	StartOrder    int       `json:"start_order"`
+	EndOrder      int       `json:"end_order"`
+
+	Action   string `json:"action"`
+	Priority int64  `json:"priority"`
+	DryRun   bool   `json:"dry_run"`
+}
+
+// Filter returns the filter that selects the request's tasks.
+func (r *BulkTaskRequest) Filter() model.BulkTaskFilter {
+	return model.BulkTaskFilter{
+		TaskIDs:       r.TaskIDs,
+		Project:       r.Project,
+		Variant:       r.Variant,
+		TaskNameRegex: r.TaskNameRegex,
+		Statuses:      r.Statuses,
+		StartTime:     r.StartTime,
+		EndTime:       r.EndTime,
+		StartOrder:    r.StartOrder,
+		EndOrder:      r.EndOrder,
+	}
+}
+
+// Options returns the options for the request's action, made by the given
+// user.
+func (r *BulkTaskRequest) Options(user string) model.BulkTaskOptions {
+	return model.BulkTaskOptions{
+		Action:   r.Action,
+		Priority: r.Priority,
+		User:     user,
+	}
+}
+
+// APIBulkTaskOperation is the model to be returned by the API whenever bulk
+// task operations are fetched or started. Tasks is only set for a dry run,
+// which returns the tasks an operation would apply to without starting it.
+type APIBulkTaskOperation struct {
+	ID             APIString   `json:"id"`
+	Action         APIString   `json:"action"`
+	Priority       int64       `json:"priority"`
+	User           APIString   `json:"user"`
+	Status         APIString   `json:"status"`
+	Total          int         `json:"total"`
+	Processed      int         `json:"processed"`
+	TasksSucceeded []APIString `json:"tasks_succeeded"`
+	TasksErrored   []APIString `json:"tasks_errored"`
+	CreatedAt      APITime     `json:"created_at"`
+	FinishedAt     APITime     `json:"finished_at"`
+	Tasks          []APITask   `json:"tasks,omitempty"`
+}
+
+// BuildFromService converts from service level structs to an
+// APIBulkTaskOperation.
+func (a *APIBulkTaskOperation) BuildFromService(h interface{}) error {
+	var op *model.BulkTaskOperation
+	switch v := h.(type) {
+	case model.BulkTaskOperation:
+		op = &v
+	case *model.BulkTaskOperation:
+		op = v
+	default:
+		return errors.Errorf("%T is not a supported bulk task operation type", h)
+	}
+
+	a.ID = ToAPIString(op.ID)
+	a.Action = ToAPIString(op.Options.Action)
+	a.Priority = op.Options.Priority
+	a.User = ToAPIString(op.Options.User)
+	a.Status = ToAPIString(op.Status)
+	a.Total = op.Total()
+	a.Processed = op.Processed
+	a.TasksSucceeded = make([]APIString, len(op.TasksSucceeded))
+	for i, id := range op.TasksSucceeded {
+		a.TasksSucceeded[i] = ToAPIString(id)
+	}
+	a.TasksErrored = make([]APIString, len(op.TasksErrored))
+	for i, id := range op.TasksErrored {
+		a.TasksErrored[i] = ToAPIString(id)
+	}
+	a.CreatedAt = NewTime(op.CreatedAt)
+	a.FinishedAt = NewTime(op.FinishedAt)
+
+	return nil
+}
+
+// ToService is not implemented for APIBulkTaskOperation, since operations
+// can only be changed by the job that applies them.
+func (a *APIBulkTaskOperation) ToService() (interface{}, error) {
+	return nil, errors.New("not implemented for bulk task operations")
+}
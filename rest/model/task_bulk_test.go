@@ -0,0 +1,61 @@
+package model
+
+import (
+	"testing"
+	"time"
+
+	"github.com/evergreen-ci/evergreen/model"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBulkTaskRequest(t *testing.T) {
+	assert := assert.New(t)
+	r := BulkTaskRequest{
+		Project:       "mci",
+		Variant:       "ubuntu",
+		TaskNameRegex: "^lint",
+		Statuses:      []string{"failed"},
+		StartOrder:    10,
+		EndOrder:      20,
+		Action:        model.BulkTaskSetPriority,
+		Priority:      50,
+	}
+
+	f := r.Filter()
+	assert.Equal("mci", f.Project)
+	assert.Equal("ubuntu", f.Variant)
+	assert.Equal("^lint", f.TaskNameRegex)
+	assert.Equal([]string{"failed"}, f.Statuses)
+	assert.Equal(10, f.StartOrder)
+	assert.Equal(20, f.EndOrder)
+
+	opts := r.Options("me")
+	assert.Equal(model.BulkTaskSetPriority, opts.Action)
+	assert.EqualValues(50, opts.Priority)
+	assert.Equal("me", opts.User)
+}
+
+func TestAPIBulkTaskOperation(t *testing.T) {
+	assert := assert.New(t)
+	op := model.NewBulkTaskOperation("op", model.BulkTaskOptions{Action: model.BulkTaskRestart, User: "me"}, []string{"t1", "t2", "t3"})
+	op.Status = model.BulkTaskOperationFinished
+	op.Processed = 3
+	op.TasksSucceeded = []string{"t1", "t2"}
+	op.TasksErrored = []string{"t3"}
+	op.FinishedAt = time.Now()
+
+	apiOp := APIBulkTaskOperation{}
+	assert.NoError(apiOp.BuildFromService(op))
+	assert.Equal("op", FromAPIString(apiOp.ID))
+	assert.Equal(model.BulkTaskRestart, FromAPIString(apiOp.Action))
+	assert.Equal("me", FromAPIString(apiOp.User))
+	assert.Equal(model.BulkTaskOperationFinished, FromAPIString(apiOp.Status))
+	assert.Equal(3, apiOp.Total)
+	assert.Equal(3, apiOp.Processed)
+	assert.Len(apiOp.TasksSucceeded, 2)
+	assert.Equal("t3", FromAPIString(apiOp.TasksErrored[0]))
+
+	assert.Error(apiOp.BuildFromService(&model.BulkTaskOptions{}))
+	_, err := apiOp.ToService()
+	assert.Error(err)
+}
@@ -2,6 +2,7 @@ package model
 
 import (
 	"reflect"
+	"time"
 
 	"github.com/evergreen-ci/evergreen/model/user"
 	"github.com/pkg/errors"
@@ -29,6 +30,54 @@ func (apiPubKey *APIPubKey) ToService() (interface{}, error) {
 	return nil, errors.Errorf("ToService() is not impelemented for APIPubKey")
 }
 
+// APIToken is the model for a user's API token. The token itself is only
+// set when the token is created.
+type APIToken struct {
+	Name      APIString   `json:"name"`
+	Token     APIString   `json:"token,omitempty"`
+	Scopes    []APIString `json:"scopes"`
+	CreatedAt APITime     `json:"created_at"`
+	ExpiresAt APITime     `json:"expires_at"`
+}
+
+// BuildFromService converts from service level structs to an APIToken.
+func (apiToken *APIToken) BuildFromService(h interface{}) error {
+	var t user.APIToken
+	switch v := h.(type) {
+	case user.APIToken:
+		t = v
+	case *user.APIToken:
+		t = *v
+	default:
+		return errors.Errorf("%T is not a supported token type", h)
+	}
+
+	apiToken.Name = ToAPIString(t.Name)
+	apiToken.Scopes = []APIString{}
+	for _, scope := range t.Scopes {
+		apiToken.Scopes = append(apiToken.Scopes, ToAPIString(scope))
+	}
+	apiToken.CreatedAt = NewTime(t.CreatedAt)
+	apiToken.ExpiresAt = NewTime(t.ExpiresAt)
+
+	return nil
+}
+
+// ToService returns a service layer token using the data from APIToken.
+func (apiToken *APIToken) ToService() (interface{}, error) {
+	t := user.APIToken{
+		Name:      FromAPIString(apiToken.Name),
+		Scopes:    []string{},
+		CreatedAt: time.Time(apiToken.CreatedAt),
+		ExpiresAt: time.Time(apiToken.ExpiresAt),
+	}
+	for _, scope := range apiToken.Scopes {
+		t.Scopes = append(t.Scopes, FromAPIString(scope))
+	}
+
+	return t, nil
+}
+
 type APIUserSettings struct {
 	Timezone      APIString                   `json:"timezone"`
 	GithubUser    *APIGithubUser              `json:"github_user"`
@@ -2,6 +2,7 @@ package model
 
 import (
 	"testing"
+	"time"
 
 	"github.com/evergreen-ci/evergreen/model/user"
 	"github.com/stretchr/testify/assert"
@@ -51,3 +52,29 @@ func runTests(t *testing.T, in user.UserSettings) {
 	assert.NoError(err)
 	assert.EqualValues(in, origSettings)
 }
+
+func TestAPIToken(t *testing.T) {
+	assert := assert.New(t)
+	now := time.Now().Round(time.Millisecond)
+	token := user.APIToken{
+		Name:      "ci",
+		Hash:      "secret",
+		Scopes:    []string{user.TokenScopeReadOnly, user.TokenScopePatch},
+		CreatedAt: now,
+		ExpiresAt: now.Add(time.Hour),
+	}
+
+	apiToken := APIToken{}
+	assert.NoError(apiToken.BuildFromService(&token))
+	assert.Equal("ci", FromAPIString(apiToken.Name))
+	assert.Nil(apiToken.Token)
+	assert.Len(apiToken.Scopes, 2)
+
+	out, err := apiToken.ToService()
+	assert.NoError(err)
+	roundTripped, ok := out.(user.APIToken)
+	assert.True(ok)
+	assert.Empty(roundTripped.Hash)
+	assert.Equal(token.Scopes, roundTripped.Scopes)
+	assert.True(token.ExpiresAt.Equal(roundTripped.ExpiresAt))
+}
@@ -25,6 +25,10 @@ type APIVersion struct {
 	Errors   []APIString `json:"errors"`
 	Warnings []APIString `json:"warnings"`
 	Ignored  bool        `json:"ignored"`
+
+	Requester   APIString `json:"requester"`
+	TriggerID   APIString `json:"trigger_id"`
+	TriggerType APIString `json:"trigger_type"`
 }
 
 type buildDetail struct {
@@ -51,6 +55,9 @@ func (apiVersion *APIVersion) BuildFromService(h interface{}) error {
 	apiVersion.Repo = ToAPIString(v.Repo)
 	apiVersion.Branch = ToAPIString(v.Branch)
 	apiVersion.Order = v.RevisionOrderNumber
+	apiVersion.Requester = ToAPIString(v.Requester)
+	apiVersion.TriggerID = ToAPIString(v.TriggerID)
+	apiVersion.TriggerType = ToAPIString(v.TriggerType)
 
 	var bd buildDetail
 	for _, t := range v.BuildVariants {
@@ -4,6 +4,7 @@ import (
 	"testing"
 	"time"
 
+	"github.com/evergreen-ci/evergreen"
 	"github.com/evergreen-ci/evergreen/model/version"
 	"github.com/stretchr/testify/assert"
 )
@@ -77,6 +78,22 @@ func TestVersionBuildFromService(t *testing.T) {
 	assert.Equal(bvs[1].BuildId, ToAPIString(bi2))
 }
 
+func TestVersionBuildFromServiceWithTrigger(t *testing.T) {
+	assert := assert.New(t)
+
+	v := &version.Version{
+		Id:          "downstream_trigger_upstream",
+		Requester:   evergreen.TriggerRequester,
+		TriggerID:   "upstream",
+		TriggerType: "version",
+	}
+	apiVersion := &APIVersion{}
+	assert.NoError(apiVersion.BuildFromService(v))
+	assert.Equal(ToAPIString(evergreen.TriggerRequester), apiVersion.Requester)
+	assert.Equal(ToAPIString("upstream"), apiVersion.TriggerID)
+	assert.Equal(ToAPIString("version"), apiVersion.TriggerType)
+}
+
 func TestVersionToService(t *testing.T) {
 	assert := assert.New(t)
 	apiVersion := &APIVersion{}
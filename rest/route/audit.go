@@ -0,0 +1,156 @@
+package route
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"github.com/evergreen-ci/evergreen/model/audit"
+	"github.com/evergreen-ci/evergreen/rest/data"
+	"github.com/evergreen-ci/evergreen/rest/model"
+	"github.com/evergreen-ci/gimlet"
+	"github.com/mongodb/grip"
+	"github.com/mongodb/grip/message"
+	"github.com/pkg/errors"
+)
+
+// NewAuditMiddleware returns a middleware that records every request made
+// by a user that can modify something in the audit log. Handlers can add
+// the changes they make to the entry in the request's context. It must run
+// after the user is attached to the request. Source addresses are only read
+// from the X-Forwarded-For header of requests made through the trusted
+// proxies.
+func NewAuditMiddleware(trustedProxies []string) gimlet.Middleware {
+	return &auditMiddleware{trustedProxies: trustedProxies}
+}
+
+type auditMiddleware struct {
+	trustedProxies []string
+}
+
+type auditResponseWriter struct {
+	http.ResponseWriter
+	status int
+}
+
+func (rw *auditResponseWriter) WriteHeader(status int) {
+	rw.status = status
+	rw.ResponseWriter.WriteHeader(status)
+}
+
+func (m *auditMiddleware) ServeHTTP(rw http.ResponseWriter, r *http.Request, next http.HandlerFunc) {
+	u := gimlet.GetUser(r.Context())
+	if u == nil || !audit.IsMutating(r.Method) {
+		next(rw, r)
+		return
+	}
+
+	entry := audit.NewEntry(r, u.Username(), m.trustedProxies)
+	arw := &auditResponseWriter{ResponseWriter: rw, status: http.StatusOK}
+	next(arw, r.WithContext(audit.WithEntry(r.Context(), entry)))
+
+	if entry.Skipped() {
+		return
+	}
+	entry.Status = arw.status
+	grip.Error(message.WrapError(entry.Insert(), message.Fields{
+		"message": "problem recording audit log entry",
+		"actor":   entry.Actor,
+		"method":  entry.Method,
+		"path":    entry.Path,
+	}))
+}
+
+////////////////////////////////////////////////////////////////////////
+//
+// GET /rest/v2/admin/audit
+
+func makeFetchAuditLog(sc data.Connector) gimlet.RouteHandler {
+	return &auditLogGetHandler{sc: sc}
+}
+
+type auditLogGetHandler struct {
+	filter audit.Filter
+
+	sc data.Connector
+}
+
+func (h *auditLogGetHandler) Factory() gimlet.RouteHandler {
+	return &auditLogGetHandler{sc: h.sc}
+}
+
+func (h *auditLogGetHandler) Parse(ctx context.Context, r *http.Request) error {
+	var err error
+	vals := r.URL.Query()
+
+	h.filter = audit.Filter{
+		Actor:        vals.Get("actor"),
+		ResourceType: vals.Get("resource_type"),
+		ResourceID:   vals.Get("resource_id"),
+		Action:       vals.Get("action"),
+		Before:       vals.Get("before"),
+	}
+	for param, t := range map[string]*time.Time{
+		"start": &h.filter.Start,
+		"end":   &h.filter.End,
+	} {
+		if vals.Get(param) == "" {
+			continue
+		}
+		*t, err = time.Parse(time.RFC3339, vals.Get(param))
+		if err != nil {
+			return gimlet.ErrorResponse{
+				StatusCode: http.StatusBadRequest,
+				Message:    errors.Wrapf(err, "problem parsing %s time as RFC-3339", param).Error(),
+			}
+		}
+	}
+
+	h.filter.Limit, err = getLimit(vals)
+	return errors.WithStack(err)
+}
+
+func (h *auditLogGetHandler) Run(ctx context.Context) gimlet.Responder {
+	resp := gimlet.NewResponseBuilder()
+
+	limit := h.filter.Limit
+	h.filter.Limit++
+	entries, err := h.sc.GetAuditLog(h.filter)
+	if err != nil {
+		return gimlet.MakeJSONErrorResponder(err)
+	}
+
+	if len(entries) > limit {
+		entries = entries[:limit]
+		err = resp.SetPages(&gimlet.ResponsePages{
+			Next: &gimlet.Page{
+				BaseURL:         h.sc.GetURL(),
+				KeyQueryParam:   "before",
+				LimitQueryParam: "limit",
+				Relation:        "next",
+				Key:             entries[limit-1].ID.Hex(),
+				Limit:           limit,
+			},
+		})
+		if err != nil {
+			return gimlet.MakeJSONInternalErrorResponder(errors.Wrap(err,
+				"problem paginating response"))
+		}
+	}
+
+	catcher := grip.NewBasicCatcher()
+	for i := range entries {
+		apiEntry := &model.APIAuditEntry{}
+		catcher.Add(apiEntry.BuildFromService(entries[i]))
+		catcher.Add(resp.AddData(apiEntry))
+	}
+	if catcher.HasErrors() {
+		return gimlet.MakeJSONInternalErrorResponder(catcher.Resolve())
+	}
+
+	if err = resp.SetStatus(http.StatusOK); err != nil {
+		return gimlet.MakeJSONInternalErrorResponder(err)
+	}
+
+	return resp
+}
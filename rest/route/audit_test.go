@@ -0,0 +1,108 @@
+package route
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/evergreen-ci/evergreen/db"
+	"github.com/evergreen-ci/evergreen/model/audit"
+	"github.com/evergreen-ci/evergreen/model/user"
+	"github.com/evergreen-ci/evergreen/rest/data"
+	"github.com/evergreen-ci/evergreen/rest/model"
+	"github.com/evergreen-ci/evergreen/testutil"
+	"github.com/evergreen-ci/gimlet"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/mgo.v2/bson"
+)
+
+func TestAuditMiddleware(t *testing.T) {
+	db.SetGlobalSessionProvider(testutil.TestConfig().SessionFactory())
+	require.NoError(t, db.Clear(audit.Collection))
+	defer func() {
+		assert.NoError(t, db.Clear(audit.Collection))
+	}()
+
+	m := NewAuditMiddleware(nil)
+	serve := func(r *http.Request) *audit.Entry {
+		var entry *audit.Entry
+		m.ServeHTTP(httptest.NewRecorder(), r, func(rw http.ResponseWriter, r *http.Request) {
+			entry = audit.GetEntry(r.Context())
+			audit.RecordChange(r.Context(), "task", "t1", bson.M{"priority": 0}, bson.M{"priority": 10})
+			rw.WriteHeader(http.StatusAccepted)
+		})
+		return entry
+	}
+	u := &user.DBUser{Id: "me"}
+
+	// requests that can't modify anything aren't audited
+	r := httptest.NewRequest(http.MethodGet, "/rest/v2/tasks/t1", nil)
+	assert.Nil(t, serve(r.WithContext(gimlet.AttachUser(r.Context(), u))))
+
+	// nor are requests made without a user, such as those from agents
+	assert.Nil(t, serve(httptest.NewRequest(http.MethodPatch, "/rest/v2/tasks/t1", nil)))
+
+	r = httptest.NewRequest(http.MethodPatch, "/rest/v2/tasks/t1", nil)
+	require.NotNil(t, serve(r.WithContext(gimlet.AttachUser(r.Context(), u))))
+
+	entries, err := audit.Find(audit.Filter{})
+	require.NoError(t, err)
+	require.Len(t, entries, 1)
+	assert.Equal(t, "me", entries[0].Actor)
+	assert.Equal(t, http.MethodPatch, entries[0].Method)
+	assert.Equal(t, http.StatusAccepted, entries[0].Status)
+	assert.Equal(t, "task", entries[0].ResourceType)
+	assert.Equal(t, "t1", entries[0].ResourceID)
+	assert.Equal(t, "update", entries[0].Action)
+	require.Len(t, entries[0].Changes, 1)
+	assert.Equal(t, "priority", entries[0].Changes[0].Field)
+}
+
+func TestAuditLogGetHandler(t *testing.T) {
+	now := time.Now()
+	sc := &data.MockConnector{URL: "https://evergreen.example.net"}
+	sc.MockAuditConnector.CachedEntries = []audit.Entry{
+		{ID: bson.NewObjectId(), Actor: "a", Action: "restart", ResourceType: "task", ResourceID: "t3", Timestamp: now},
+		{ID: bson.NewObjectId(), Actor: "b", Action: "abort", ResourceType: "task", ResourceID: "t2", Timestamp: now.Add(-time.Minute)},
+		{ID: bson.NewObjectId(), Actor: "a", Action: "extend", ResourceType: "host", ResourceID: "h1", Timestamp: now.Add(-time.Hour)},
+	}
+
+	get := func(query string) gimlet.Responder {
+		h := makeFetchAuditLog(sc).Factory()
+		r, err := http.NewRequest(http.MethodGet, "/admin/audit?"+query, nil)
+		require.NoError(t, err)
+		require.NoError(t, h.Parse(context.Background(), r))
+		resp := h.Run(context.Background())
+		require.Equal(t, http.StatusOK, resp.Status())
+		return resp
+	}
+	ids := func(resp gimlet.Responder) []string {
+		out := []string{}
+		for _, entry := range resp.Data().([]interface{}) {
+			out = append(out, model.FromAPIString(entry.(*model.APIAuditEntry).ResourceID))
+		}
+		return out
+	}
+
+	resp := get("")
+	assert.Equal(t, []string{"t3", "t2", "h1"}, ids(resp))
+	assert.Nil(t, resp.Pages())
+
+	assert.Equal(t, []string{"t3", "h1"}, ids(get("actor=a")))
+	assert.Equal(t, []string{"t2"}, ids(get("resource_type=task&action=abort")))
+	assert.Equal(t, []string{"t3", "t2"}, ids(get("start="+now.Add(-30*time.Minute).Format(time.RFC3339))))
+
+	resp = get("limit=2")
+	assert.Equal(t, []string{"t3", "t2"}, ids(resp))
+	require.NotNil(t, resp.Pages())
+	assert.Equal(t, sc.CachedEntries[1].ID.Hex(), resp.Pages().Next.Key)
+	assert.Equal(t, []string{"h1"}, ids(get("limit=2&before="+resp.Pages().Next.Key)))
+
+	h := makeFetchAuditLog(sc).Factory()
+	r, err := http.NewRequest(http.MethodGet, "/admin/audit?start=yesterday", nil)
+	require.NoError(t, err)
+	assert.Error(t, h.Parse(context.Background(), r))
+}
@@ -2,10 +2,13 @@ package route
 
 import (
 	"context"
+	"fmt"
 	"net/http"
+	"strings"
 
 	"github.com/evergreen-ci/evergreen"
 	"github.com/evergreen-ci/evergreen/auth"
+	"github.com/evergreen-ci/evergreen/model/user"
 	"github.com/evergreen-ci/evergreen/rest/data"
 	"github.com/evergreen-ci/evergreen/util"
 	"github.com/evergreen-ci/gimlet"
@@ -74,6 +77,65 @@ func (p *ProjectAdminAuthenticator) Authenticate(ctx context.Context, sc data.Co
 	}
 }
 
+// ProjectPermissionAuthenticator only allows users with a permission on the
+// project in the request's project context. It requires that the project
+// context be available.
+type ProjectPermissionAuthenticator struct {
+	Permission string
+}
+
+// Authenticate checks that the user is a super user, one of the project's
+// admins, or has a role granting the permission on the project.
+func (p *ProjectPermissionAuthenticator) Authenticate(ctx context.Context, sc data.Connector) error {
+	projCtx := MustHaveProjectContext(ctx)
+	ok, err := auth.HasProjectPermission(sc.GetSuperUsers(), gimlet.GetUser(ctx), projCtx.ProjectRef, p.Permission)
+	if err != nil {
+		return gimlet.ErrorResponse{
+			StatusCode: http.StatusInternalServerError,
+			Message:    err.Error(),
+		}
+	}
+	if !ok {
+		return gimlet.ErrorResponse{
+			StatusCode: http.StatusNotFound,
+			Message:    "Not found",
+		}
+	}
+
+	return nil
+}
+
+// NewProjectPermissionMiddleware returns a middleware for gimlet routes that
+// does what ProjectPermissionAuthenticator does for route managers: it
+// attaches the request's project context and only allows users with the
+// permission on that project. It must run after the user is attached to the
+// request.
+func NewProjectPermissionMiddleware(sc data.Connector, permission string) gimlet.Middleware {
+	return &projectPermissionMiddleware{
+		sc:            sc,
+		authenticator: &ProjectPermissionAuthenticator{Permission: permission},
+	}
+}
+
+type projectPermissionMiddleware struct {
+	sc            data.Connector
+	authenticator *ProjectPermissionAuthenticator
+}
+
+func (m *projectPermissionMiddleware) ServeHTTP(rw http.ResponseWriter, r *http.Request, next http.HandlerFunc) {
+	ctx, err := PrefetchProjectContext(r.Context(), m.sc, r)
+	if err != nil {
+		gimlet.WriteResponse(rw, gimlet.MakeJSONInternalErrorResponder(err))
+		return
+	}
+	if err = m.authenticator.Authenticate(ctx, m.sc); err != nil {
+		gimlet.WriteResponse(rw, gimlet.MakeJSONInternalErrorResponder(err))
+		return
+	}
+
+	next(rw, r.WithContext(ctx))
+}
+
 // RequireUserAuthenticator requires that a user be attached to a request.
 type RequireUserAuthenticator struct{}
 
@@ -98,3 +160,89 @@ func validPriority(priority int64, user gimlet.User, sc data.Connector) bool {
 	}
 	return true
 }
+
+// NewAPITokenMiddleware returns a middleware that authenticates requests
+// made with one of a user's API tokens in place of their API key. It must
+// run before the user middleware, which would otherwise reject the token as
+// an invalid API key. The token's user is only attached to the request by
+// the token scope middleware of the route, so routes that don't declare the
+// scope they require don't accept tokens.
+func NewAPITokenMiddleware(sc data.Connector) gimlet.Middleware {
+	return &apiTokenMiddleware{sc: sc}
+}
+
+type apiTokenMiddleware struct {
+	sc data.Connector
+}
+
+// apiTokenAuth is a request's API token and the user it authenticated.
+type apiTokenAuth struct {
+	token *user.APIToken
+	user  *user.DBUser
+}
+
+func (m *apiTokenMiddleware) ServeHTTP(rw http.ResponseWriter, r *http.Request, next http.HandlerFunc) {
+	key := r.Header.Get(evergreen.APIKeyHeader)
+	if !strings.HasPrefix(key, user.APITokenPrefix) {
+		next(rw, r)
+		return
+	}
+
+	found, err := m.sc.FindUserById(r.Header.Get(evergreen.APIUserHeader))
+	if err != nil {
+		gimlet.WriteTextResponse(rw, http.StatusInternalServerError, "problem finding user")
+		return
+	}
+	var token *user.APIToken
+	u, ok := found.(*user.DBUser)
+	if ok && u != nil {
+		token = u.FindAPIToken(key)
+	}
+	if token == nil {
+		gimlet.WriteTextResponse(rw, http.StatusUnauthorized, "invalid or expired API token")
+		return
+	}
+
+	// the user middleware would compare the token to the user's API key
+	r = r.WithContext(context.WithValue(r.Context(), apiTokenContext, &apiTokenAuth{token: token, user: u}))
+	header := http.Header{}
+	for k, v := range r.Header {
+		header[k] = v
+	}
+	header.Del(evergreen.APIKeyHeader)
+	r.Header = header
+
+	next(rw, r)
+}
+
+// NewTokenScopeMiddleware returns a route middleware that declares the API
+// token scope that the route requires. Requests made with a token that
+// doesn't have the scope are rejected, and the user of a token that does is
+// attached to the request. Every token can use routes that require the
+// read-only scope, and tokens with the admin scope can use every route.
+func NewTokenScopeMiddleware(scope string) gimlet.Middleware {
+	return &tokenScopeMiddleware{scope: scope}
+}
+
+type tokenScopeMiddleware struct {
+	scope string
+}
+
+func (m *tokenScopeMiddleware) ServeHTTP(rw http.ResponseWriter, r *http.Request, next http.HandlerFunc) {
+	tokenAuth, ok := r.Context().Value(apiTokenContext).(*apiTokenAuth)
+	if !ok {
+		next(rw, r)
+		return
+	}
+	if !tokenHasScope(tokenAuth.token, m.scope) {
+		gimlet.WriteTextResponse(rw, http.StatusForbidden,
+			fmt.Sprintf("API token '%s' does not allow this request", tokenAuth.token.Name))
+		return
+	}
+
+	next(rw, r.WithContext(gimlet.AttachUser(r.Context(), tokenAuth.user)))
+}
+
+func tokenHasScope(token *user.APIToken, scope string) bool {
+	return scope == user.TokenScopeReadOnly || token.HasScope(user.TokenScopeAdmin) || token.HasScope(scope)
+}
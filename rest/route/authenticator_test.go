@@ -3,14 +3,19 @@ package route
 import (
 	"context"
 	"net/http"
+	"net/http/httptest"
 	"testing"
+	"time"
 
+	"github.com/evergreen-ci/evergreen"
 	"github.com/evergreen-ci/evergreen/model"
+	"github.com/evergreen-ci/evergreen/model/role"
 	"github.com/evergreen-ci/evergreen/model/user"
 	"github.com/evergreen-ci/evergreen/rest/data"
 	"github.com/evergreen-ci/gimlet"
 	. "github.com/smartystreets/goconvey/convey"
 	"github.com/smartystreets/goconvey/convey/reporting"
+	"github.com/stretchr/testify/assert"
 )
 
 func init() {
@@ -120,3 +125,146 @@ func TestSuperUserAuthenticator(t *testing.T) {
 	})
 
 }
+
+func TestAPITokenMiddleware(t *testing.T) {
+	assert := assert.New(t)
+
+	u := &user.DBUser{Id: "me", APIKey: "key"}
+	sc := &data.MockConnector{}
+	sc.MockUserConnector.CachedUsers = map[string]*user.DBUser{u.Id: u}
+	expiresAt := time.Now().Add(time.Hour)
+	patchToken, err := sc.AddAPIToken(u, "patch", []string{user.TokenScopePatch}, expiresAt)
+	assert.NoError(err)
+	adminToken, err := sc.AddAPIToken(u, "admin", []string{user.TokenScopeAdmin}, expiresAt)
+	assert.NoError(err)
+
+	m := NewAPITokenMiddleware(sc)
+	// routes without a token scope middleware get nil as their scope
+	serve := func(scope *string, userName, key string) (*httptest.ResponseRecorder, *http.Request) {
+		r := httptest.NewRequest(http.MethodPost, "/rest/v2/route", nil)
+		r.Header.Set(evergreen.APIUserHeader, userName)
+		r.Header.Set(evergreen.APIKeyHeader, key)
+		rw := httptest.NewRecorder()
+		var served *http.Request
+		handler := func(rw http.ResponseWriter, r *http.Request) {
+			served = r
+		}
+		m.ServeHTTP(rw, r, func(rw http.ResponseWriter, r *http.Request) {
+			if scope == nil {
+				handler(rw, r)
+				return
+			}
+			NewTokenScopeMiddleware(*scope).ServeHTTP(rw, r, handler)
+		})
+		return rw, served
+	}
+	readOnly := user.TokenScopeReadOnly
+	patch := user.TokenScopePatch
+	host := user.TokenScopeHost
+	admin := user.TokenScopeAdmin
+
+	// API keys are left to the user middleware
+	rw, served := serve(&host, "me", "key")
+	assert.Equal(http.StatusOK, rw.Code)
+	assert.NotNil(served)
+	assert.Nil(gimlet.GetUser(served.Context()))
+	assert.Equal("key", served.Header.Get(evergreen.APIKeyHeader))
+
+	rw, served = serve(&patch, "me", patchToken)
+	assert.Equal(http.StatusOK, rw.Code)
+	assert.NotNil(served)
+	assert.Equal(u, gimlet.GetUser(served.Context()))
+	assert.Empty(served.Header.Get(evergreen.APIKeyHeader))
+
+	rw, served = serve(&readOnly, "me", patchToken)
+	assert.Equal(http.StatusOK, rw.Code)
+	assert.NotNil(served)
+	assert.Equal(u, gimlet.GetUser(served.Context()))
+
+	rw, served = serve(&host, "me", patchToken)
+	assert.Equal(http.StatusForbidden, rw.Code)
+	assert.Nil(served)
+
+	rw, served = serve(&admin, "me", patchToken)
+	assert.Equal(http.StatusForbidden, rw.Code)
+	assert.Nil(served)
+
+	rw, served = serve(&admin, "me", adminToken)
+	assert.Equal(http.StatusOK, rw.Code)
+	assert.NotNil(served)
+	assert.Equal(u, gimlet.GetUser(served.Context()))
+
+	// routes that don't declare a scope don't authenticate tokens
+	rw, served = serve(nil, "me", adminToken)
+	assert.Equal(http.StatusOK, rw.Code)
+	assert.NotNil(served)
+	assert.Nil(gimlet.GetUser(served.Context()))
+	assert.Empty(served.Header.Get(evergreen.APIKeyHeader))
+
+	rw, served = serve(&readOnly, "someone", patchToken)
+	assert.Equal(http.StatusUnauthorized, rw.Code)
+	assert.Nil(served)
+
+	u.APITokens[0].ExpiresAt = time.Now().Add(-time.Minute)
+	rw, served = serve(&readOnly, "me", patchToken)
+	assert.Equal(http.StatusUnauthorized, rw.Code)
+	assert.Nil(served)
+
+	assert.NoError(sc.DeleteAPIToken(u, "admin"))
+	rw, served = serve(&readOnly, "me", adminToken)
+	assert.Equal(http.StatusUnauthorized, rw.Code)
+	assert.Nil(served)
+}
+
+func TestMethodHandlerTokenScope(t *testing.T) {
+	assert := assert.New(t)
+	assert.Equal(user.TokenScopeReadOnly, MethodHandler{MethodType: http.MethodGet}.tokenScope())
+	assert.Equal(user.TokenScopeAdmin, MethodHandler{MethodType: http.MethodPost}.tokenScope())
+	assert.Equal(user.TokenScopePatch, getPatchAbortManager("/patches/{patch_id}/abort", 2).Methods[0].tokenScope())
+	assert.Equal(user.TokenScopeHost, getHostTerminateRouteManager("/hosts/{host_id}/terminate", 2).Methods[0].tokenScope())
+}
+
+func TestProjectPermissionMiddleware(t *testing.T) {
+	assert := assert.New(t)
+
+	sc := &data.MockConnector{}
+	sc.SetSuperUsers([]string{"root"})
+	sc.MockContextConnector.CachedContext = model.Context{
+		ProjectRef: &model.ProjectRef{
+			Identifier: "proj",
+			Admins:     []string{"admin"},
+		},
+	}
+
+	m := NewProjectPermissionMiddleware(sc, role.PermissionRestart)
+	serve := func(u gimlet.User) (*httptest.ResponseRecorder, *http.Request) {
+		r := httptest.NewRequest(http.MethodPost, "/rest/v2/builds/b1/abort", nil)
+		if u != nil {
+			r = r.WithContext(gimlet.AttachUser(r.Context(), u))
+		}
+		rw := httptest.NewRecorder()
+		var served *http.Request
+		m.ServeHTTP(rw, r, func(rw http.ResponseWriter, r *http.Request) {
+			served = r
+		})
+		return rw, served
+	}
+
+	rw, served := serve(&user.DBUser{Id: "root"})
+	assert.Equal(http.StatusOK, rw.Code)
+	assert.NotNil(served)
+	assert.Equal("proj", MustHaveProjectContext(served.Context()).ProjectRef.Identifier)
+
+	rw, served = serve(&user.DBUser{Id: "admin"})
+	assert.Equal(http.StatusOK, rw.Code)
+	assert.NotNil(served)
+
+	rw, served = serve(nil)
+	assert.Equal(http.StatusNotFound, rw.Code)
+	assert.Nil(served)
+
+	sc.MockContextConnector.CachedContext = model.Context{}
+	rw, served = serve(&user.DBUser{Id: "root"})
+	assert.Equal(http.StatusNotFound, rw.Code)
+	assert.Nil(served)
+}
@@ -125,3 +125,41 @@ func (tbh *tasksByBuildHandler) Run(ctx context.Context) gimlet.Responder {
 
 	return resp
 }
+
+type tasksByBuildArgs struct {
+	buildId string
+	status  string
+}
+
+// tasksByBuildPaginator is the PaginatorFunc that pages through the tasks
+// of a build in the same order as the tasks by build route.
+func tasksByBuildPaginator(key string, limit int, args interface{}, sc data.Connector) ([]model.Model, *PageResult, error) {
+	tbArgs, ok := args.(tasksByBuildArgs)
+	if !ok {
+		return nil, nil, errors.New("tasks by build pagination args had wrong type")
+	}
+
+	tasks, err := sc.FindTasksByBuildId(tbArgs.buildId, key, tbArgs.status, limit*2, 1)
+	if err != nil {
+		return nil, nil, errors.Wrap(err, "Database error")
+	}
+
+	pages := &PageResult{Next: makeNextTasksPage(tasks, limit)}
+	if pages.Next != nil {
+		tasks = tasks[:limit]
+	}
+
+	models := make([]model.Model, len(tasks))
+	for i := range tasks {
+		taskModel := &model.APITask{}
+		if err = taskModel.BuildFromService(&tasks[i]); err != nil {
+			return nil, nil, err
+		}
+		if err = taskModel.BuildFromService(sc.GetURL()); err != nil {
+			return nil, nil, err
+		}
+		models[i] = taskModel
+	}
+
+	return models, pages, nil
+}
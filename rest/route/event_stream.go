@@ -0,0 +1,235 @@
+package route
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/evergreen-ci/evergreen/auth"
+	"github.com/evergreen-ci/evergreen/model/event"
+	"github.com/evergreen-ci/evergreen/model/role"
+	"github.com/evergreen-ci/evergreen/rest/data"
+	"github.com/evergreen-ci/evergreen/rest/model"
+	"github.com/evergreen-ci/evergreen/util"
+	"github.com/evergreen-ci/gimlet"
+	"github.com/pkg/errors"
+)
+
+const (
+	eventStreamDefaultTimeout = 30 * time.Second
+	eventStreamMaxTimeout     = 2 * time.Minute
+	eventStreamPollInterval   = time.Second
+	eventStreamDefaultLimit   = 100
+)
+
+////////////////////////////////////////////////////////////////////////
+//
+// GET /rest/v2/events/stream
+
+func makeEventStreamHandler(sc data.Connector) gimlet.RouteHandler {
+	return &eventStreamHandler{
+		sc: sc,
+	}
+}
+
+// eventStreamHandler long-polls the event log: it returns as soon as
+// at least one matching event has been logged after the requested
+// position, or an empty batch once the timeout elapses. Clients resume
+// the stream by passing the returned last_event_id as "after" (or as
+// the Last-Event-ID header) in their next request. Without either, the
+// stream starts at the time of the request.
+type eventStreamHandler struct {
+	after          event.EventCursor
+	resourceTypes  []string
+	selectors      []event.Selector
+	regexSelectors []event.Selector
+	timeout        time.Duration
+	limit          int
+
+	// canViewProject caches whether the user can view each project
+	canViewProject map[string]bool
+
+	sc data.Connector
+}
+
+func (h *eventStreamHandler) Factory() gimlet.RouteHandler {
+	return &eventStreamHandler{
+		canViewProject: map[string]bool{},
+		sc:             h.sc,
+	}
+}
+
+func (h *eventStreamHandler) Parse(ctx context.Context, r *http.Request) error {
+	var err error
+	vals := r.URL.Query()
+
+	after := vals.Get("after")
+	if after == "" {
+		after = r.Header.Get("Last-Event-ID")
+	}
+	if after == "" {
+		h.after = event.EventCursorAt(time.Now())
+	} else if h.after, err = event.ParseEventCursor(after); err != nil {
+		return gimlet.ErrorResponse{
+			StatusCode: http.StatusBadRequest,
+			Message:    err.Error(),
+		}
+	}
+
+	h.resourceTypes = []string{}
+	for _, types := range vals["resource_types"] {
+		for _, resourceType := range strings.Split(types, ",") {
+			resourceType = strings.ToUpper(strings.TrimSpace(resourceType))
+			if resourceType == "" {
+				continue
+			}
+			if !util.StringSliceContains(data.StreamableResourceTypes, resourceType) {
+				return gimlet.ErrorResponse{
+					StatusCode: http.StatusBadRequest,
+					Message:    "cannot stream events for resource type " + resourceType,
+				}
+			}
+			h.resourceTypes = append(h.resourceTypes, resourceType)
+		}
+	}
+	if len(h.resourceTypes) == 0 {
+		h.resourceTypes = data.StreamableResourceTypes
+	}
+
+	if h.selectors, err = parseStreamSelectors(vals["selector"]); err != nil {
+		return errors.WithStack(err)
+	}
+	if h.regexSelectors, err = parseStreamSelectors(vals["regex_selector"]); err != nil {
+		return errors.WithStack(err)
+	}
+
+	h.timeout = eventStreamDefaultTimeout
+	if timeout := vals.Get("timeout"); timeout != "" {
+		secs, err := strconv.Atoi(timeout)
+		if err != nil || secs < 0 {
+			return gimlet.ErrorResponse{
+				StatusCode: http.StatusBadRequest,
+				Message:    "timeout must be a non-negative number of seconds",
+			}
+		}
+		h.timeout = time.Duration(secs) * time.Second
+		if h.timeout > eventStreamMaxTimeout {
+			h.timeout = eventStreamMaxTimeout
+		}
+	}
+
+	h.limit, err = getLimit(vals)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	if _, ok := vals["limit"]; !ok {
+		h.limit = eventStreamDefaultLimit
+	}
+
+	return nil
+}
+
+// parseStreamSelectors parses selectors of the form "type:data".
+func parseStreamSelectors(in []string) ([]event.Selector, error) {
+	selectors := []event.Selector{}
+	for _, s := range in {
+		parts := strings.SplitN(s, ":", 2)
+		if len(parts) != 2 || parts[0] == "" || parts[1] == "" {
+			return nil, gimlet.ErrorResponse{
+				StatusCode: http.StatusBadRequest,
+				Message:    "selectors must be of the form 'type:data', got " + s,
+			}
+		}
+		selectors = append(selectors, event.Selector{
+			Type: parts[0],
+			Data: parts[1],
+		})
+	}
+
+	return selectors, nil
+}
+
+func (h *eventStreamHandler) Run(ctx context.Context) gimlet.Responder {
+	ctx, cancel := context.WithTimeout(ctx, h.timeout)
+	defer cancel()
+
+	batch := model.APIEventStreamBatch{
+		Events: []model.APIEventLogEntry{},
+	}
+	after := h.after
+	timer := time.NewTimer(0)
+	defer timer.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			batch.LastEventID = model.ToAPIString(after.String())
+			return gimlet.NewJSONResponse(batch)
+		case <-timer.C:
+			events, last, err := h.sc.FindEventsAfter(after, h.resourceTypes, h.selectors, h.regexSelectors, h.limit)
+			if err != nil {
+				return gimlet.MakeJSONInternalErrorResponder(errors.Wrap(err, "Database error"))
+			}
+			after = last
+
+			events, err = h.filterViewable(ctx, events)
+			if err != nil {
+				return gimlet.MakeJSONErrorResponder(err)
+			}
+			if len(events) > 0 {
+				batch.Events = events
+				batch.LastEventID = model.ToAPIString(after.String())
+				return gimlet.NewJSONResponse(batch)
+			}
+
+			timer.Reset(eventStreamPollInterval)
+		}
+	}
+}
+
+// filterViewable drops the events of tasks, builds, versions and patches of
+// projects that the user can't view. Host and distro events don't belong to
+// projects.
+func (h *eventStreamHandler) filterViewable(ctx context.Context, events []model.APIEventLogEntry) ([]model.APIEventLogEntry, error) {
+	out := []model.APIEventLogEntry{}
+	for _, e := range events {
+		var taskID, buildID, versionID, patchID string
+		id := model.FromAPIString(e.ResourceId)
+		switch model.FromAPIString(e.ResourceType) {
+		case event.ResourceTypeTask:
+			taskID = id
+		case event.ResourceTypeBuild:
+			buildID = id
+		case event.ResourceTypeVersion:
+			versionID = id
+		case event.ResourceTypePatch:
+			patchID = id
+		default:
+			out = append(out, e)
+			continue
+		}
+
+		projCtx, err := h.sc.FetchContext(taskID, buildID, versionID, patchID, "")
+		if err != nil {
+			return nil, errors.Wrapf(err, "problem finding the project of event '%s'", model.FromAPIString(e.ID))
+		}
+		if projCtx.ProjectRef == nil {
+			continue
+		}
+		canView, ok := h.canViewProject[projCtx.ProjectRef.Identifier]
+		if !ok {
+			canView, err = auth.HasProjectPermission(h.sc.GetSuperUsers(), gimlet.GetUser(ctx), projCtx.ProjectRef, role.PermissionView)
+			if err != nil {
+				return nil, errors.WithStack(err)
+			}
+			h.canViewProject[projCtx.ProjectRef.Identifier] = canView
+		}
+		if canView {
+			out = append(out, e)
+		}
+	}
+
+	return out, nil
+}
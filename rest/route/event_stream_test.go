@@ -0,0 +1,192 @@
+package route
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	serviceModel "github.com/evergreen-ci/evergreen/model"
+	"github.com/evergreen-ci/evergreen/model/event"
+	"github.com/evergreen-ci/evergreen/model/user"
+	"github.com/evergreen-ci/evergreen/rest/data"
+	"github.com/evergreen-ci/evergreen/rest/model"
+	"github.com/evergreen-ci/gimlet"
+	"github.com/stretchr/testify/suite"
+	"gopkg.in/mgo.v2/bson"
+)
+
+type eventStreamSuite struct {
+	sc     *data.MockConnector
+	ids    []string
+	times  []time.Time
+	cancel context.CancelFunc
+	ctx    context.Context
+
+	suite.Suite
+}
+
+func TestEventStreamSuite(t *testing.T) {
+	suite.Run(t, &eventStreamSuite{})
+}
+
+func (s *eventStreamSuite) SetupTest() {
+	s.ctx, s.cancel = context.WithCancel(context.Background())
+	s.ctx = gimlet.AttachUser(s.ctx, &user.DBUser{Id: "me"})
+	start := time.Now().Add(-time.Minute).Truncate(time.Millisecond)
+	s.times = []time.Time{start, start.Add(time.Second), start.Add(time.Second)}
+	s.ids = []string{"event1", "event2", "event3"}
+	s.sc = &data.MockConnector{
+		MockContextConnector: data.MockContextConnector{
+			CachedContext: serviceModel.Context{
+				ProjectRef: &serviceModel.ProjectRef{Identifier: "proj", Admins: []string{"me"}},
+			},
+		},
+		MockEventStreamConnector: data.MockEventStreamConnector{
+			CachedEvents: []event.EventLogEntry{
+				{
+					ID:           s.ids[0],
+					Timestamp:    s.times[0],
+					ResourceType: event.ResourceTypeTask,
+					ResourceId:   "task1",
+					EventType:    event.TaskFinished,
+					Data:         &event.TaskEventData{Status: "success"},
+				},
+				{
+					ID:           s.ids[1],
+					Timestamp:    s.times[1],
+					ResourceType: event.ResourceTypeHost,
+					ResourceId:   "host1",
+					EventType:    event.EventHostProvisioned,
+					Data:         &event.HostEventData{},
+				},
+				{
+					ID:           s.ids[2],
+					Timestamp:    s.times[2],
+					ResourceType: event.ResourceTypeTask,
+					ResourceId:   "task2",
+					EventType:    event.TaskFinished,
+					Data:         &event.TaskEventData{Status: "failed"},
+				},
+			},
+		},
+	}
+}
+
+func (s *eventStreamSuite) TearDownTest() {
+	s.cancel()
+}
+
+func (s *eventStreamSuite) parse(url string, header http.Header) *eventStreamHandler {
+	h := makeEventStreamHandler(s.sc).Factory()
+	r, err := http.NewRequest(http.MethodGet, url, nil)
+	s.Require().NoError(err)
+	for k, v := range header {
+		r.Header[k] = v
+	}
+	s.Require().NoError(h.Parse(s.ctx, r))
+
+	return h.(*eventStreamHandler)
+}
+
+func (s *eventStreamSuite) TestParse() {
+	cursor := event.EventCursor{Timestamp: s.times[1], ID: s.ids[1]}
+	h := s.parse("/events/stream?after="+cursor.String()+"&resource_types=task,host&selector=project:mci&regex_selector=id:^foo:bar&timeout=5&limit=3", nil)
+	s.True(cursor.Timestamp.Equal(h.after.Timestamp))
+	s.Equal(cursor.ID, h.after.ID)
+	s.Equal([]string{event.ResourceTypeTask, event.ResourceTypeHost}, h.resourceTypes)
+	s.Equal([]event.Selector{{Type: "project", Data: "mci"}}, h.selectors)
+	s.Equal([]event.Selector{{Type: "id", Data: "^foo:bar"}}, h.regexSelectors)
+	s.Equal(5*time.Second, h.timeout)
+	s.Equal(3, h.limit)
+
+	legacyID := bson.NewObjectIdWithTime(s.times[0].Truncate(time.Second)).Hex()
+	h = s.parse("/events/stream", http.Header{"Last-Event-Id": []string{legacyID}})
+	s.True(s.times[0].Truncate(time.Second).Equal(h.after.Timestamp))
+	s.Equal(legacyID, h.after.ID)
+	s.Equal(data.StreamableResourceTypes, h.resourceTypes)
+	s.Equal(eventStreamDefaultTimeout, h.timeout)
+	s.Equal(eventStreamDefaultLimit, h.limit)
+
+	h = s.parse("/events/stream", nil)
+	s.False(h.after.Timestamp.IsZero())
+	s.Empty(h.after.ID)
+
+	for _, url := range []string{
+		"/events/stream?after=abc",
+		"/events/stream?after=abc_def",
+		"/events/stream?resource_types=admin",
+		"/events/stream?selector=project",
+		"/events/stream?timeout=-1",
+	} {
+		r, err := http.NewRequest(http.MethodGet, url, nil)
+		s.Require().NoError(err)
+		s.Error(makeEventStreamHandler(s.sc).Factory().Parse(s.ctx, r), url)
+	}
+}
+
+func (s *eventStreamSuite) TestRunReturnsMatchingEvents() {
+	h := s.parse("/events/stream?after="+event.NewEventCursor(&s.sc.CachedEvents[0]).String()+"&resource_types=task", nil)
+
+	resp := h.Run(s.ctx)
+	s.Equal(http.StatusOK, resp.Status())
+	batch, ok := resp.Data().(model.APIEventStreamBatch)
+	s.Require().True(ok)
+	s.Require().Len(batch.Events, 1)
+	s.Equal("task2", model.FromAPIString(batch.Events[0].ResourceId))
+	s.Equal(event.EventCursor{Timestamp: s.times[2], ID: s.ids[2]}.String(), model.FromAPIString(batch.LastEventID))
+}
+
+func (s *eventStreamSuite) TestRunResumesAtEventsWithTheSameTime() {
+	h := s.parse("/events/stream?after="+event.NewEventCursor(&s.sc.CachedEvents[1]).String(), nil)
+
+	resp := h.Run(s.ctx)
+	batch, ok := resp.Data().(model.APIEventStreamBatch)
+	s.Require().True(ok)
+	s.Require().Len(batch.Events, 1)
+	s.Equal("task2", model.FromAPIString(batch.Events[0].ResourceId))
+}
+
+func (s *eventStreamSuite) TestRunFiltersBySelectors() {
+	h := s.parse("/events/stream?after="+event.NewEventCursor(&s.sc.CachedEvents[0]).String()+"&selector=object:host", nil)
+
+	resp := h.Run(s.ctx)
+	batch, ok := resp.Data().(model.APIEventStreamBatch)
+	s.Require().True(ok)
+	s.Require().Len(batch.Events, 1)
+	s.Equal("host1", model.FromAPIString(batch.Events[0].ResourceId))
+}
+
+func (s *eventStreamSuite) TestRunDropsEventsOfProjectsTheUserCantView() {
+	// the user is no longer an admin of the project, and has no role that
+	// can view it
+	s.sc.MockContextConnector.CachedContext.ProjectRef.Admins = nil
+	h := s.parse("/events/stream?after="+event.NewEventCursor(&s.sc.CachedEvents[0]).String()+"&timeout=1", nil)
+	h.canViewProject["proj"] = false
+
+	resp := h.Run(s.ctx)
+	batch, ok := resp.Data().(model.APIEventStreamBatch)
+	s.Require().True(ok)
+	s.Require().Len(batch.Events, 1)
+	s.Equal("host1", model.FromAPIString(batch.Events[0].ResourceId))
+
+	// events whose project can't be found are dropped too
+	s.sc.MockContextConnector.CachedContext.ProjectRef = nil
+	h = s.parse("/events/stream?after="+event.NewEventCursor(&s.sc.CachedEvents[1]).String()+"&timeout=1", nil)
+	resp = h.Run(s.ctx)
+	batch, ok = resp.Data().(model.APIEventStreamBatch)
+	s.Require().True(ok)
+	s.Empty(batch.Events)
+	s.Equal(event.EventCursor{Timestamp: s.times[2], ID: s.ids[2]}.String(), model.FromAPIString(batch.LastEventID))
+}
+
+func (s *eventStreamSuite) TestRunTimesOutWithResumableID() {
+	h := s.parse("/events/stream?after="+event.NewEventCursor(&s.sc.CachedEvents[0]).String()+"&timeout=1&regex_selector=id:^nothing$", nil)
+
+	resp := h.Run(s.ctx)
+	s.Equal(http.StatusOK, resp.Status())
+	batch, ok := resp.Data().(model.APIEventStreamBatch)
+	s.Require().True(ok)
+	s.Empty(batch.Events)
+	s.Equal(event.EventCursor{Timestamp: s.times[2], ID: s.ids[2]}.String(), model.FromAPIString(batch.LastEventID))
+}
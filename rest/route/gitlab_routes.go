@@ -0,0 +1,157 @@
+package route
+
+import (
+	"context"
+	"crypto/subtle"
+	"net/http"
+
+	"github.com/evergreen-ci/evergreen/model/patch"
+	"github.com/evergreen-ci/evergreen/rest/data"
+	"github.com/evergreen-ci/gimlet"
+	"github.com/mongodb/amboy"
+	"github.com/mongodb/grip"
+	"github.com/mongodb/grip/message"
+	"github.com/pkg/errors"
+)
+
+const (
+	gitlabMergeRequestKind = "merge_request"
+
+	gitlabActionOpen   = "open"
+	gitlabActionReopen = "reopen"
+	gitlabActionUpdate = "update"
+	gitlabActionClose  = "close"
+	gitlabActionMerge  = "merge"
+)
+
+////////////////////////////////////////////////////////////////////////
+//
+// POST /rest/v2/hooks/gitlab
+
+type gitlabHookHandler struct {
+	sc     data.Connector
+	queue  amboy.Queue
+	secret []byte
+
+	event *patch.GitlabMergeRequestEvent
+	msgID string
+}
+
+func makeGitlabHookHandler(sc data.Connector, queue amboy.Queue, secret []byte) gimlet.RouteHandler {
+	if len(secret) == 0 {
+		grip.Warning("GitLab webhook secret is empty! GitLab webhooks have been disabled!")
+	}
+
+	return &gitlabHookHandler{
+		sc:     sc,
+		queue:  queue,
+		secret: secret,
+	}
+}
+
+func (h *gitlabHookHandler) Factory() gimlet.RouteHandler {
+	return &gitlabHookHandler{
+		sc:     h.sc,
+		queue:  h.queue,
+		secret: h.secret,
+	}
+}
+
+func (h *gitlabHookHandler) Parse(ctx context.Context, r *http.Request) error {
+	h.msgID = r.Header.Get("X-Gitlab-Event-UUID")
+
+	if len(h.secret) == 0 || h.queue == nil {
+		return gimlet.ErrorResponse{
+			StatusCode: http.StatusServiceUnavailable,
+			Message:    "GitLab webhooks are not configured",
+		}
+	}
+
+	// GitLab sends the secret token of the webhook as-is, rather than
+	// signing the payload
+	token := []byte(r.Header.Get("X-Gitlab-Token"))
+	if subtle.ConstantTimeCompare(token, h.secret) != 1 {
+		grip.Error(message.Fields{
+			"source":  "gitlab hook",
+			"message": "rejecting gitlab webhook with an invalid token",
+			"msg_id":  h.msgID,
+		})
+		return gimlet.ErrorResponse{
+			StatusCode: http.StatusUnauthorized,
+			Message:    "invalid webhook token",
+		}
+	}
+
+	h.event = &patch.GitlabMergeRequestEvent{}
+	if err := gimlet.GetJSON(r.Body, h.event); err != nil {
+		return gimlet.ErrorResponse{
+			StatusCode: http.StatusBadRequest,
+			Message:    errors.Wrap(err, "problem parsing request body").Error(),
+		}
+	}
+
+	return nil
+}
+
+func (h *gitlabHookHandler) Run(ctx context.Context) gimlet.Responder {
+	// other events, such as pushes, are accepted so that GitLab doesn't
+	// disable the webhook, but are ignored
+	if h.event.ObjectKind != gitlabMergeRequestKind {
+		return gimlet.NewJSONResponse(struct{}{})
+	}
+
+	action := h.event.ObjectAttributes.Action
+	switch {
+	case action == gitlabActionOpen || action == gitlabActionReopen ||
+		(action == gitlabActionUpdate && h.event.ObjectAttributes.OldRev != ""):
+		intent, err := patch.NewGitlabIntent(h.msgID, h.event)
+		if err != nil {
+			grip.Error(message.WrapError(err, message.Fields{
+				"source":  "gitlab hook",
+				"msg_id":  h.msgID,
+				"action":  action,
+				"message": "failed to create intent",
+			}))
+			return gimlet.MakeJSONErrorResponder(gimlet.ErrorResponse{
+				StatusCode: http.StatusBadRequest,
+				Message:    err.Error(),
+			})
+		}
+
+		grip.Info(message.Fields{
+			"source":    "gitlab hook",
+			"msg_id":    h.msgID,
+			"action":    action,
+			"message":   "mr accepted, attempting to queue",
+			"project":   h.event.Project.PathWithNamespace,
+			"ref":       h.event.ObjectAttributes.TargetBranch,
+			"mr_number": h.event.ObjectAttributes.IID,
+			"creator":   h.event.User.Username,
+			"hash":      h.event.ObjectAttributes.LastCommit.ID,
+		})
+
+		if err = h.sc.AddPatchIntent(intent, h.queue); err != nil {
+			return gimlet.MakeJSONErrorResponder(err)
+		}
+
+	case action == gitlabActionClose || action == gitlabActionMerge:
+		grip.Info(message.Fields{
+			"source":  "gitlab hook",
+			"msg_id":  h.msgID,
+			"action":  action,
+			"message": "merge request closed; aborting patch",
+		})
+
+		if err := h.sc.AbortPatchesFromMergeRequest(h.event); err != nil {
+			grip.Error(message.WrapError(err, message.Fields{
+				"source":  "gitlab hook",
+				"msg_id":  h.msgID,
+				"action":  action,
+				"message": "failed to abort patches",
+			}))
+			return gimlet.MakeJSONErrorResponder(err)
+		}
+	}
+
+	return gimlet.NewJSONResponse(struct{}{})
+}
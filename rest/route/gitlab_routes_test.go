@@ -0,0 +1,111 @@
+package route
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/evergreen-ci/evergreen/model/patch"
+	"github.com/evergreen-ci/evergreen/rest/data"
+	"github.com/evergreen-ci/gimlet"
+	"github.com/mongodb/amboy/queue"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newGitlabHookRequest(t *testing.T, token string, event *patch.GitlabMergeRequestEvent) *http.Request {
+	body, err := json.Marshal(event)
+	require.NoError(t, err)
+	req, err := http.NewRequest(http.MethodPost, "/rest/v2/hooks/gitlab", bytes.NewReader(body))
+	require.NoError(t, err)
+	req.Header.Add("X-Gitlab-Token", token)
+	req.Header.Add("X-Gitlab-Event-UUID", "c6ae2f62-13e2-4b2c-8a1b-3a7cf8b9d2e1")
+	return req
+}
+
+func TestGitlabHookHandler(t *testing.T) {
+	assert := assert.New(t)
+	ctx := context.Background()
+
+	sc := &data.MockConnector{MockPatchIntentConnector: data.MockPatchIntentConnector{
+		CachedIntents: map[data.MockPatchIntentKey]patch.Intent{},
+	}}
+	event := &patch.GitlabMergeRequestEvent{
+		ObjectKind: "merge_request",
+		User:       patch.GitlabUser{ID: 12, Username: "tanuki"},
+		Project: patch.GitlabProject{
+			ID:                42,
+			PathWithNamespace: "group/evergreen",
+			WebURL:            "https://gitlab.example.com/group/evergreen",
+			GitHTTPURL:        "https://gitlab.example.com/group/evergreen.git",
+		},
+		ObjectAttributes: patch.GitlabMergeRequestAttributes{
+			IID:          7,
+			TargetBranch: "master",
+			AuthorID:     12,
+			Action:       "open",
+		},
+	}
+	event.ObjectAttributes.LastCommit.ID = "67da19930b1b18d346477e99a8e18094a672f48a"
+
+	// without a secret, webhooks are rejected
+	h := makeGitlabHookHandler(sc, queue.NewLocalUnordered(1), nil).Factory()
+	err := h.Parse(ctx, newGitlabHookRequest(t, "", event))
+	assert.Error(err)
+
+	h = makeGitlabHookHandler(sc, queue.NewLocalUnordered(1), []byte("secret")).Factory()
+	err = h.Parse(ctx, newGitlabHookRequest(t, "wrong", event))
+	assert.Error(err)
+	errResp, ok := err.(gimlet.ErrorResponse)
+	assert.True(ok)
+	assert.Equal(http.StatusUnauthorized, errResp.StatusCode)
+
+	// opening a merge request adds an intent
+	h = h.Factory()
+	assert.NoError(h.Parse(ctx, newGitlabHookRequest(t, "secret", event)))
+	resp := h.Run(ctx)
+	assert.Equal(http.StatusOK, resp.Status())
+	require.Len(t, sc.CachedIntents, 1)
+	for _, intent := range sc.CachedIntents {
+		assert.Equal(patch.GitlabIntentType, intent.GetType())
+		assert.Equal("c6ae2f62-13e2-4b2c-8a1b-3a7cf8b9d2e1", intent.ID())
+	}
+
+	// updates that don't push commits are ignored
+	sc.CachedIntents = map[data.MockPatchIntentKey]patch.Intent{}
+	event.ObjectAttributes.Action = "update"
+	h = h.Factory()
+	assert.NoError(h.Parse(ctx, newGitlabHookRequest(t, "secret", event)))
+	resp = h.Run(ctx)
+	assert.Equal(http.StatusOK, resp.Status())
+	assert.Empty(sc.CachedIntents)
+
+	event.ObjectAttributes.OldRev = "8a4fe72b7df25d8d8dd25a95c1c8e5dd41bb3c9a"
+	h = h.Factory()
+	assert.NoError(h.Parse(ctx, newGitlabHookRequest(t, "secret", event)))
+	resp = h.Run(ctx)
+	assert.Equal(http.StatusOK, resp.Status())
+	assert.Len(sc.CachedIntents, 1)
+
+	// closing a merge request aborts its patches
+	event.ObjectAttributes.Action = "close"
+	h = h.Factory()
+	assert.NoError(h.Parse(ctx, newGitlabHookRequest(t, "secret", event)))
+	resp = h.Run(ctx)
+	assert.Equal(http.StatusOK, resp.Status())
+
+	event.ObjectAttributes.IID = 0
+	h = h.Factory()
+	assert.NoError(h.Parse(ctx, newGitlabHookRequest(t, "secret", event)))
+	resp = h.Run(ctx)
+	assert.Equal(http.StatusBadRequest, resp.Status())
+
+	// events for anything but merge requests are ignored
+	event.ObjectKind = "push"
+	h = h.Factory()
+	assert.NoError(h.Parse(ctx, newGitlabHookRequest(t, "secret", event)))
+	resp = h.Run(ctx)
+	assert.Equal(http.StatusOK, resp.Status())
+}
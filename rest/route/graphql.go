@@ -0,0 +1,404 @@
+package route
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/evergreen-ci/evergreen/auth"
+	"github.com/evergreen-ci/evergreen/model/audit"
+	"github.com/evergreen-ci/evergreen/model/role"
+	"github.com/evergreen-ci/evergreen/rest/data"
+	"github.com/evergreen-ci/evergreen/rest/graphql"
+	"github.com/evergreen-ci/evergreen/rest/model"
+	"github.com/evergreen-ci/gimlet"
+	"github.com/pkg/errors"
+)
+
+////////////////////////////////////////////////////////////////////////
+//
+// POST /rest/v2/graphql
+
+func makeGraphQLHandler(sc data.Connector) gimlet.RouteHandler {
+	return &graphQLHandler{
+		schema: newGraphQLSchema(sc),
+		sc:     sc,
+	}
+}
+
+type graphQLHandler struct {
+	request graphql.Request
+	doc     *graphql.Document
+
+	schema *graphql.Schema
+	sc     data.Connector
+}
+
+func (h *graphQLHandler) Factory() gimlet.RouteHandler {
+	return &graphQLHandler{
+		schema: h.schema,
+		sc:     h.sc,
+	}
+}
+
+func (h *graphQLHandler) Parse(ctx context.Context, r *http.Request) error {
+	if err := gimlet.GetJSON(r.Body, &h.request); err != nil {
+		return gimlet.ErrorResponse{
+			StatusCode: http.StatusBadRequest,
+			Message:    errors.Wrap(err, "problem parsing request").Error(),
+		}
+	}
+
+	var err error
+	if h.doc, err = graphql.Parse(h.request.Query); err != nil {
+		return gimlet.ErrorResponse{
+			StatusCode: http.StatusBadRequest,
+			Message:    errors.Wrap(err, "invalid query").Error(),
+		}
+	}
+	if err = h.schema.Validate(h.doc); err != nil {
+		return gimlet.ErrorResponse{
+			StatusCode: http.StatusBadRequest,
+			Message:    errors.Wrap(err, "invalid query").Error(),
+		}
+	}
+
+	return nil
+}
+
+func (h *graphQLHandler) Run(ctx context.Context) gimlet.Responder {
+	// queries are sent with POST, but don't modify anything
+	audit.Skip(ctx)
+
+	return gimlet.NewJSONResponse(h.schema.Execute(ctx, h.doc, h.request.OperationName, h.request.Variables))
+}
+
+// newGraphQLSchema returns the schema of the GraphQL API, whose objects are
+// the REST API models and whose fields are resolved through the connector.
+// Lists of tasks and tests are paginated by the same PaginatorFuncs as
+// their REST routes.
+func newGraphQLSchema(sc data.Connector) *graphql.Schema {
+	version := &graphql.Object{Name: "Version", Model: model.APIVersion{}}
+	build := &graphql.Object{Name: "Build", Model: model.APIBuild{}}
+	task := &graphql.Object{Name: "Task", Model: model.APITask{}}
+	test := &graphql.Object{Name: "Test", Model: model.APITest{}}
+	patch := &graphql.Object{Name: "Patch", Model: model.APIPatch{}}
+	page := &graphql.Object{
+		Name: "Page",
+		Fields: map[string]*graphql.Field{
+			"key": {
+				Resolve: func(_ context.Context, source interface{}, _ graphql.Args) (interface{}, error) {
+					return source.(*Page).Key, nil
+				},
+			},
+			"limit": {
+				Resolve: func(_ context.Context, source interface{}, _ graphql.Args) (interface{}, error) {
+					return source.(*Page).Limit, nil
+				},
+			},
+		},
+	}
+
+	version.Fields = map[string]*graphql.Field{
+		"builds": {
+			Type: build,
+			Resolve: func(ctx context.Context, source interface{}, _ graphql.Args) (interface{}, error) {
+				return graphQLFindVersionBuilds(ctx, sc, source.(*model.APIVersion))
+			},
+		},
+	}
+	build.Fields = map[string]*graphql.Field{
+		"version": {
+			Type: version,
+			Resolve: func(ctx context.Context, source interface{}, _ graphql.Args) (interface{}, error) {
+				return graphQLFindVersion(ctx, sc, model.FromAPIString(source.(*model.APIBuild).Version))
+			},
+		},
+		"tasks": graphQLPaginatedField(sc, "TaskPage", task, page, tasksByBuildPaginator, []string{"status"},
+			func(source interface{}, args graphql.Args) (interface{}, error) {
+				status, err := args.String("status")
+				return tasksByBuildArgs{
+					buildId: model.FromAPIString(source.(*model.APIBuild).Id),
+					status:  status,
+				}, err
+			}),
+	}
+	task.Fields = map[string]*graphql.Field{
+		"build": {
+			Type: build,
+			Resolve: func(ctx context.Context, source interface{}, _ graphql.Args) (interface{}, error) {
+				return graphQLFindBuild(ctx, sc, model.FromAPIString(source.(*model.APITask).BuildId))
+			},
+		},
+		"version": {
+			Type: version,
+			Resolve: func(ctx context.Context, source interface{}, _ graphql.Args) (interface{}, error) {
+				return graphQLFindVersion(ctx, sc, model.FromAPIString(source.(*model.APITask).Version))
+			},
+		},
+		"artifacts": {
+			Resolve: func(_ context.Context, source interface{}, _ graphql.Args) (interface{}, error) {
+				t := *source.(*model.APITask)
+				t.Artifacts = nil
+				if err := t.GetArtifacts(); err != nil {
+					return nil, err
+				}
+				return t.Artifacts, nil
+			},
+		},
+		"tests": graphQLPaginatedField(sc, "TestPage", test, page, testPaginator, []string{"status", "execution"},
+			func(source interface{}, args graphql.Args) (interface{}, error) {
+				t := source.(*model.APITask)
+				status, err := args.String("status")
+				if err != nil {
+					return nil, err
+				}
+				execution, err := args.Int("execution", t.Execution)
+				return testGetHandlerArgs{
+					taskId:        model.FromAPIString(t.Id),
+					testStatus:    status,
+					testExecution: execution,
+				}, err
+			}),
+	}
+	patch.Fields = map[string]*graphql.Field{
+		"version": {
+			Type: version,
+			Resolve: func(ctx context.Context, source interface{}, _ graphql.Args) (interface{}, error) {
+				id := model.FromAPIString(source.(*model.APIPatch).Version)
+				if id == "" {
+					return nil, nil
+				}
+				return graphQLFindVersion(ctx, sc, id)
+			},
+		},
+	}
+
+	return &graphql.Schema{
+		Query: &graphql.Object{
+			Name: "Query",
+			Fields: map[string]*graphql.Field{
+				"version": {
+					Type: version,
+					Args: []string{"id"},
+					Resolve: func(ctx context.Context, _ interface{}, args graphql.Args) (interface{}, error) {
+						id, err := args.String("id")
+						if err != nil {
+							return nil, err
+						}
+						return graphQLFindVersion(ctx, sc, id)
+					},
+				},
+				"build": {
+					Type: build,
+					Args: []string{"id"},
+					Resolve: func(ctx context.Context, _ interface{}, args graphql.Args) (interface{}, error) {
+						id, err := args.String("id")
+						if err != nil {
+							return nil, err
+						}
+						return graphQLFindBuild(ctx, sc, id)
+					},
+				},
+				"task": {
+					Type: task,
+					Args: []string{"id"},
+					Resolve: func(ctx context.Context, _ interface{}, args graphql.Args) (interface{}, error) {
+						id, err := args.String("id")
+						if err != nil {
+							return nil, err
+						}
+						return graphQLFindTask(ctx, sc, id)
+					},
+				},
+				"patch": {
+					Type: patch,
+					Args: []string{"id"},
+					Resolve: func(ctx context.Context, _ interface{}, args graphql.Args) (interface{}, error) {
+						id, err := args.String("id")
+						if err != nil {
+							return nil, err
+						}
+						if err = graphQLCheckCanView(ctx, sc, "", "", "", id); err != nil {
+							return nil, err
+						}
+						p, err := sc.FindPatchById(id)
+						if err != nil {
+							return nil, err
+						}
+						apiPatch := &model.APIPatch{}
+						return apiPatch, apiPatch.BuildFromService(*p)
+					},
+				},
+			},
+		},
+	}
+}
+
+// graphQLPage is a page of results from a PaginatorFunc.
+type graphQLPage struct {
+	results []model.Model
+	pages   *PageResult
+}
+
+// graphQLPaginatedField returns a field that pages through a list of items
+// with a PaginatorFunc. Like the PaginationExecutor, it takes the key to
+// start at and the number of items to return, and returns the pages
+// before and after the results.
+func graphQLPaginatedField(sc data.Connector, name string, item, page *graphql.Object, paginator PaginatorFunc,
+	args []string, makeArgs func(interface{}, graphql.Args) (interface{}, error)) *graphql.Field {
+	pageResults := &graphql.Object{
+		Name: name,
+		Fields: map[string]*graphql.Field{
+			"results": {
+				Type: item,
+				Resolve: func(_ context.Context, source interface{}, _ graphql.Args) (interface{}, error) {
+					return source.(*graphQLPage).results, nil
+				},
+			},
+			"next": {
+				Type: page,
+				Resolve: func(_ context.Context, source interface{}, _ graphql.Args) (interface{}, error) {
+					return source.(*graphQLPage).pages.Next, nil
+				},
+			},
+			"prev": {
+				Type: page,
+				Resolve: func(_ context.Context, source interface{}, _ graphql.Args) (interface{}, error) {
+					return source.(*graphQLPage).pages.Prev, nil
+				},
+			},
+		},
+	}
+
+	return &graphql.Field{
+		Type: pageResults,
+		Args: append([]string{"start_at", "limit"}, args...),
+		Resolve: func(_ context.Context, source interface{}, fieldArgs graphql.Args) (interface{}, error) {
+			key, err := fieldArgs.String("start_at")
+			if err != nil {
+				return nil, err
+			}
+			limit, err := fieldArgs.Int("limit", defaultLimit)
+			if err != nil {
+				return nil, err
+			}
+			if limit <= 0 {
+				return nil, errors.New("limit must be positive")
+			}
+			paginatorArgs, err := makeArgs(source, fieldArgs)
+			if err != nil {
+				return nil, err
+			}
+
+			results, pages, err := paginator(key, limit, paginatorArgs, sc)
+			if err != nil {
+				return nil, err
+			}
+			if pages == nil {
+				pages = &PageResult{}
+			}
+			return &graphQLPage{results: results, pages: pages}, nil
+		},
+	}
+}
+
+// graphQLCheckCanView returns a not found error unless the user can view the
+// project of the task, build, version, or patch, since each object that a
+// query reaches must be as visible to the user as it is through its REST
+// route.
+func graphQLCheckCanView(ctx context.Context, sc data.Connector, taskID, buildID, versionID, patchID string) error {
+	projCtx, err := sc.FetchContext(taskID, buildID, versionID, patchID, "")
+	if err != nil {
+		return err
+	}
+	ok, err := auth.HasProjectPermission(sc.GetSuperUsers(), gimlet.GetUser(ctx), projCtx.ProjectRef, role.PermissionView)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return gimlet.ErrorResponse{
+			StatusCode: http.StatusNotFound,
+			Message:    "not found",
+		}
+	}
+
+	return nil
+}
+
+func graphQLFindVersion(ctx context.Context, sc data.Connector, id string) (*model.APIVersion, error) {
+	if err := graphQLCheckCanView(ctx, sc, "", "", id, ""); err != nil {
+		return nil, err
+	}
+	v, err := sc.FindVersionById(id)
+	if err != nil {
+		return nil, err
+	}
+	apiVersion := &model.APIVersion{}
+	return apiVersion, apiVersion.BuildFromService(v)
+}
+
+func graphQLFindBuild(ctx context.Context, sc data.Connector, id string) (*model.APIBuild, error) {
+	if err := graphQLCheckCanView(ctx, sc, "", id, "", ""); err != nil {
+		return nil, err
+	}
+	b, err := sc.FindBuildById(id)
+	if err != nil {
+		return nil, err
+	}
+	apiBuild := &model.APIBuild{}
+	return apiBuild, apiBuild.BuildFromService(*b)
+}
+
+// graphQLFindVersionBuilds returns the builds of a version in the order of
+// its build variants, finding them all at once.
+func graphQLFindVersionBuilds(ctx context.Context, sc data.Connector, v *model.APIVersion) ([]*model.APIBuild, error) {
+	// the builds of a version are in the version's project
+	if err := graphQLCheckCanView(ctx, sc, "", "", model.FromAPIString(v.Id), ""); err != nil {
+		return nil, err
+	}
+	ids := make([]string, 0, len(v.BuildVariants))
+	for _, bv := range v.BuildVariants {
+		ids = append(ids, model.FromAPIString(bv.BuildId))
+	}
+	builds, err := sc.FindBuildsByIds(ids)
+	if err != nil {
+		return nil, err
+	}
+
+	byId := map[string]*model.APIBuild{}
+	for _, b := range builds {
+		apiBuild := &model.APIBuild{}
+		if err = apiBuild.BuildFromService(b); err != nil {
+			return nil, err
+		}
+		byId[b.Id] = apiBuild
+	}
+	out := make([]*model.APIBuild, 0, len(ids))
+	for _, id := range ids {
+		if b, ok := byId[id]; ok {
+			out = append(out, b)
+		}
+	}
+	return out, nil
+}
+
+func graphQLFindTask(ctx context.Context, sc data.Connector, id string) (*model.APITask, error) {
+	if err := graphQLCheckCanView(ctx, sc, id, "", "", ""); err != nil {
+		return nil, err
+	}
+	t, err := sc.FindTaskById(id)
+	if err != nil {
+		return nil, err
+	}
+	if t == nil {
+		return nil, gimlet.ErrorResponse{
+			StatusCode: http.StatusNotFound,
+			Message:    "task not found",
+		}
+	}
+	apiTask := &model.APITask{}
+	if err = apiTask.BuildFromService(t); err != nil {
+		return nil, err
+	}
+	return apiTask, apiTask.BuildFromService(sc.GetURL())
+}
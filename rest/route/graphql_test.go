@@ -0,0 +1,118 @@
+package route
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/evergreen-ci/evergreen"
+	serviceModel "github.com/evergreen-ci/evergreen/model"
+	"github.com/evergreen-ci/evergreen/model/build"
+	"github.com/evergreen-ci/evergreen/model/task"
+	"github.com/evergreen-ci/evergreen/model/testresult"
+	"github.com/evergreen-ci/evergreen/model/user"
+	"github.com/evergreen-ci/evergreen/model/version"
+	"github.com/evergreen-ci/evergreen/rest/data"
+	"github.com/evergreen-ci/gimlet"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGraphQLHandler(t *testing.T) {
+	sc := &data.MockConnector{URL: "https://evergreen.example.net"}
+	sc.SetSuperUsers([]string{"root"})
+	sc.MockContextConnector.CachedContext = serviceModel.Context{
+		ProjectRef: &serviceModel.ProjectRef{Identifier: "proj"},
+	}
+	ctx := gimlet.AttachUser(context.Background(), &user.DBUser{Id: "root"})
+	sc.MockVersionConnector.CachedVersions = []version.Version{
+		{
+			Id:       "v1",
+			Revision: "abc",
+			BuildVariants: []version.BuildStatus{
+				{BuildVariant: "linux", BuildId: "b1"},
+			},
+		},
+	}
+	sc.MockBuildConnector.CachedBuilds = []build.Build{
+		{Id: "b1", Version: "v1", BuildVariant: "linux"},
+	}
+	sc.MockTaskConnector.CachedTasks = []task.Task{
+		{Id: "t1", BuildId: "b1", Version: "v1", Status: evergreen.TaskSucceeded},
+		{Id: "t2", BuildId: "b1", Version: "v1", Status: evergreen.TaskFailed},
+		{Id: "t3", BuildId: "b1", Version: "v1", Status: evergreen.TaskFailed},
+	}
+	sc.MockTestConnector.CachedTests = []testresult.TestResult{
+		{ID: "test1", TestFile: "a_test", Status: evergreen.TestSucceededStatus},
+		{ID: "test2", TestFile: "b_test", Status: evergreen.TestFailedStatus},
+	}
+
+	query := func(body string) string {
+		h := makeGraphQLHandler(sc).Factory()
+		r, err := http.NewRequest(http.MethodPost, "/graphql", bytes.NewBufferString(body))
+		require.NoError(t, err)
+		require.NoError(t, h.Parse(ctx, r))
+		resp := h.Run(ctx)
+		require.Equal(t, http.StatusOK, resp.Status())
+		out, err := json.Marshal(resp.Data())
+		require.NoError(t, err)
+		return string(out)
+	}
+
+	t.Run("NestedObjects", func(t *testing.T) {
+		out := query(`{"query": "{ version(id: \"v1\") { revision builds { _id tasks(start_at: \"t1\") { results { task_id } } } } }"}`)
+		assert.JSONEq(t, `{"data": {"version": {"revision": "abc", "builds": [
+			{"_id": "b1", "tasks": {"results": [{"task_id": "t1"}, {"task_id": "t2"}, {"task_id": "t3"}]}}
+		]}}}`, out)
+	})
+	t.Run("Pagination", func(t *testing.T) {
+		out := query(`{"query": "query($limit: Int) { build(id: \"b1\") { tasks(start_at: \"t1\", limit: $limit) { results { task_id } next { key limit } } } }", "variables": {"limit": 2}}`)
+		assert.JSONEq(t, `{"data": {"build": {"tasks": {
+			"results": [{"task_id": "t1"}, {"task_id": "t2"}],
+			"next": {"key": "t3", "limit": 1}
+		}}}}`, out)
+	})
+	t.Run("TaskTests", func(t *testing.T) {
+		out := query(`{"query": "{ task(id: \"t2\") { build { version { version_id } } tests(start_at: \"test1\") { results { test_file status } next { key } } } }"}`)
+		assert.JSONEq(t, `{"data": {"task": {
+			"build": {"version": {"version_id": "v1"}},
+			"tests": {"results": [{"test_file": "a_test", "status": "pass"}, {"test_file": "b_test", "status": "fail"}], "next": null}
+		}}}`, out)
+	})
+	t.Run("FieldErrors", func(t *testing.T) {
+		out := query(`{"query": "{ version(id: \"v1\") { revision } build(id: \"missing\") { _id } }"}`)
+		assert.Contains(t, out, `"data":{"version":{"revision":"abc"},"build":null}`)
+		assert.Contains(t, out, `"path":["build"]`)
+	})
+	t.Run("ProjectPermissions", func(t *testing.T) {
+		ctx = gimlet.AttachUser(context.Background(), &user.DBUser{Id: "admin"})
+		defer func() {
+			ctx = gimlet.AttachUser(context.Background(), &user.DBUser{Id: "root"})
+		}()
+		sc.MockContextConnector.CachedContext.ProjectRef.Admins = []string{"admin"}
+		out := query(`{"query": "{ task(id: \"t1\") { task_id } }"}`)
+		assert.JSONEq(t, `{"data": {"task": {"task_id": "t1"}}}`, out)
+
+		ctx = context.Background()
+		out = query(`{"query": "{ task(id: \"t1\") { task_id } version(id: \"v1\") { revision } }"}`)
+		assert.Contains(t, out, `"data":{"task":null,"version":null}`)
+		assert.Contains(t, out, `"path":["task"]`)
+		assert.Contains(t, out, `"path":["version"]`)
+	})
+	t.Run("InvalidQuery", func(t *testing.T) {
+		h := makeGraphQLHandler(sc).Factory()
+		r, err := http.NewRequest(http.MethodPost, "/graphql", bytes.NewBufferString(`{"query": "{ version("}`))
+		require.NoError(t, err)
+		assert.Error(t, h.Parse(context.Background(), r))
+
+		// queries of fields the schema doesn't define aren't executed
+		h = makeGraphQLHandler(sc).Factory()
+		r, err = http.NewRequest(http.MethodPost, "/graphql", bytes.NewBufferString(`{"query": "{ version(id: \"v1\") { revision owner { name } } }"}`))
+		require.NoError(t, err)
+		err = h.Parse(context.Background(), r)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "type 'Version' has no field 'owner'")
+	})
+}
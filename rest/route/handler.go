@@ -4,6 +4,7 @@ import (
 	"context"
 	"net/http"
 
+	"github.com/evergreen-ci/evergreen/model/user"
 	"github.com/evergreen-ci/evergreen/rest/data"
 	"github.com/evergreen-ci/evergreen/rest/model"
 	"github.com/evergreen-ci/gimlet"
@@ -19,11 +20,25 @@ type MethodHandler struct {
 	// MethodType is the HTTP Method Type that this handler will handler.
 	// POST, PUT, DELETE, etc.
 	MethodType string
+	// TokenScope is the API token scope that the method requires. If it's
+	// empty, GET requests require the read-only scope and other requests
+	// require the admin scope.
+	TokenScope string
 
 	Authenticator
 	RequestHandler
 }
 
+func (m MethodHandler) tokenScope() string {
+	if m.TokenScope != "" {
+		return m.TokenScope
+	}
+	if m.MethodType == http.MethodGet {
+		return user.TokenScopeReadOnly
+	}
+	return user.TokenScopeAdmin
+}
+
 // ResponseData holds the information that the handler function will need to form
 // its encoded response. A ResponseData is generated by a RequestHandler's Execute
 // function and parsed in the main handler method.
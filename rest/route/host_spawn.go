@@ -9,7 +9,11 @@ import (
 	"time"
 
 	"github.com/evergreen-ci/evergreen"
+	"github.com/evergreen-ci/evergreen/auth"
 	"github.com/evergreen-ci/evergreen/cloud"
+	"github.com/evergreen-ci/evergreen/model/audit"
+	"github.com/evergreen-ci/evergreen/model/role"
+	"github.com/evergreen-ci/evergreen/model/user"
 	"github.com/evergreen-ci/evergreen/rest/data"
 	"github.com/evergreen-ci/evergreen/rest/model"
 	"github.com/evergreen-ci/evergreen/util"
@@ -43,6 +47,17 @@ func (hph *hostPostHandler) Parse(ctx context.Context, r *http.Request) error {
 func (hph *hostPostHandler) Run(ctx context.Context) gimlet.Responder {
 	user := MustHaveUser(ctx)
 
+	ok, err := auth.HasDistroPermission(hph.sc.GetSuperUsers(), user, hph.Distro, role.PermissionSpawnHosts)
+	if err != nil {
+		return gimlet.MakeJSONErrorResponder(errors.Wrap(err, "error checking permissions"))
+	}
+	if !ok {
+		return gimlet.MakeJSONErrorResponder(gimlet.ErrorResponse{
+			StatusCode: http.StatusUnauthorized,
+			Message:    fmt.Sprintf("cannot spawn hosts of distro '%s'", hph.Distro),
+		})
+	}
+
 	intentHost, err := hph.sc.NewIntentHost(hph.Distro, hph.KeyName, "", user)
 	if err != nil {
 		return gimlet.MakeJSONErrorResponder(errors.Wrap(err, "error spawning host"))
@@ -64,6 +79,7 @@ func getHostTerminateRouteManager(route string, version int) *RouteManager {
 		Methods: []MethodHandler{
 			{
 				MethodType:     http.MethodPost,
+				TokenScope:     user.TokenScopeHost,
 				Authenticator:  &RequireUserAuthenticator{},
 				RequestHandler: &hostTerminateHandler{},
 			},
@@ -127,6 +143,7 @@ func getHostChangeRDPPasswordRouteManager(route string, version int) *RouteManag
 		Methods: []MethodHandler{
 			{
 				MethodType:     http.MethodPost,
+				TokenScope:     user.TokenScopeHost,
 				Authenticator:  &RequireUserAuthenticator{},
 				RequestHandler: &hostChangeRDPPasswordHandler{},
 			},
@@ -203,6 +220,7 @@ func getHostExtendExpirationRouteManager(route string, version int) *RouteManage
 		Methods: []MethodHandler{
 			{
 				MethodType:     http.MethodPost,
+				TokenScope:     user.TokenScopeHost,
 				Authenticator:  &RequireUserAuthenticator{},
 				RequestHandler: &hostExtendExpirationHandler{},
 			},
@@ -279,12 +297,14 @@ func (h *hostExtendExpirationHandler) Execute(ctx context.Context, sc data.Conne
 		}
 	}
 
+	before := *host
 	if err := sc.SetHostExpirationTime(host, newExp); err != nil {
 		return ResponseData{}, gimlet.ErrorResponse{
 			StatusCode: http.StatusInternalServerError,
 			Message:    err.Error(),
 		}
 	}
+	audit.RecordChange(ctx, "host", host.Id, before, host)
 
 	return ResponseData{}, nil
 }
@@ -0,0 +1,496 @@
+package route
+
+import (
+	"context"
+	"encoding"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"reflect"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/evergreen-ci/evergreen"
+	"github.com/evergreen-ci/evergreen/rest/data"
+	"github.com/evergreen-ci/evergreen/rest/model"
+	"github.com/evergreen-ci/gimlet"
+	"github.com/pkg/errors"
+	"gopkg.in/mgo.v2/bson"
+)
+
+const openAPIVersion = "3.0.0"
+
+// openAPIDocument is an OpenAPI 3 description of the REST v2 API.
+type openAPIDocument struct {
+	OpenAPI    string                                      `json:"openapi"`
+	Info       openAPIInfo                                 `json:"info"`
+	Servers    []openAPIServer                             `json:"servers"`
+	Paths      map[string]map[string]*openAPIOperationSpec `json:"paths"`
+	Components openAPIComponents                           `json:"components"`
+	Security   []map[string][]string                       `json:"security"`
+}
+
+type openAPIInfo struct {
+	Title   string `json:"title"`
+	Version string `json:"version"`
+}
+
+type openAPIServer struct {
+	URL string `json:"url"`
+}
+
+type openAPIOperationSpec struct {
+	OperationID string                      `json:"operationId"`
+	Summary     string                      `json:"summary,omitempty"`
+	Parameters  []openAPIParameter          `json:"parameters,omitempty"`
+	RequestBody *openAPIRequestBody         `json:"requestBody,omitempty"`
+	Responses   map[string]*openAPIResponse `json:"responses"`
+}
+
+type openAPIParameter struct {
+	Name     string         `json:"name"`
+	In       string         `json:"in"`
+	Required bool           `json:"required,omitempty"`
+	Schema   *openAPISchema `json:"schema"`
+}
+
+type openAPIRequestBody struct {
+	Required bool                        `json:"required"`
+	Content  map[string]openAPIMediaType `json:"content"`
+}
+
+type openAPIResponse struct {
+	Description string                      `json:"description"`
+	Headers     map[string]openAPIHeader    `json:"headers,omitempty"`
+	Content     map[string]openAPIMediaType `json:"content,omitempty"`
+}
+
+type openAPIHeader struct {
+	Description string         `json:"description"`
+	Schema      *openAPISchema `json:"schema"`
+}
+
+type openAPIMediaType struct {
+	Schema *openAPISchema `json:"schema"`
+}
+
+type openAPIComponents struct {
+	Schemas         map[string]*openAPISchema        `json:"schemas"`
+	SecuritySchemes map[string]openAPISecurityScheme `json:"securitySchemes"`
+}
+
+type openAPISecurityScheme struct {
+	Type string `json:"type"`
+	In   string `json:"in"`
+	Name string `json:"name"`
+}
+
+type openAPISchema struct {
+	Ref                  string                    `json:"$ref,omitempty"`
+	Type                 string                    `json:"type,omitempty"`
+	Format               string                    `json:"format,omitempty"`
+	Items                *openAPISchema            `json:"items,omitempty"`
+	Properties           map[string]*openAPISchema `json:"properties,omitempty"`
+	AdditionalProperties *openAPISchema            `json:"additionalProperties,omitempty"`
+}
+
+// openAPIOperation annotates a route with what reflection on its
+// registration can't find: the types of its request and response bodies,
+// and its query parameters. Every route registered by GetHandler must have
+// one in openAPIOperations.
+type openAPIOperation struct {
+	summary string
+	query   []string
+	// request and response are values of the types of the request and
+	// response bodies, or nil if the route has no body.
+	request  interface{}
+	response interface{}
+	// paginated routes return a Link header with the next and previous
+	// pages.
+	paginated bool
+}
+
+// generateOpenAPI returns the OpenAPI document for the routes that
+// GetHandler registers.
+func generateOpenAPI() (*openAPIDocument, error) {
+	routes, err := getRegisteredRoutes()
+	if err != nil {
+		return nil, errors.Wrap(err, "problem finding routes")
+	}
+
+	doc := &openAPIDocument{
+		OpenAPI: openAPIVersion,
+		Info: openAPIInfo{
+			Title:   "Evergreen REST API",
+			Version: "v2",
+		},
+		Servers: []openAPIServer{{URL: "/" + evergreen.RestRoutePrefix + "/v2"}},
+		Paths:   map[string]map[string]*openAPIOperationSpec{},
+		Components: openAPIComponents{
+			Schemas: map[string]*openAPISchema{},
+			SecuritySchemes: map[string]openAPISecurityScheme{
+				"apiUser": {Type: "apiKey", In: "header", Name: evergreen.APIUserHeader},
+				"apiKey":  {Type: "apiKey", In: "header", Name: evergreen.APIKeyHeader},
+			},
+		},
+		Security: []map[string][]string{
+			{},
+			{"apiUser": {}, "apiKey": {}},
+		},
+	}
+	schemas := &openAPISchemas{schemas: doc.Components.Schemas, types: map[string]reflect.Type{}}
+
+	catcher := &openAPIMissing{}
+	for _, r := range routes {
+		op, ok := openAPIOperations[r.String()]
+		if !ok {
+			catcher.add(r.String())
+			continue
+		}
+		if _, ok = doc.Paths[r.path]; !ok {
+			doc.Paths[r.path] = map[string]*openAPIOperationSpec{}
+		}
+		doc.Paths[r.path][strings.ToLower(r.method)] = op.spec(r, schemas)
+	}
+	if err = catcher.resolve(); err != nil {
+		return nil, err
+	}
+
+	return doc, nil
+}
+
+func (op openAPIOperation) spec(r openAPIRoute, schemas *openAPISchemas) *openAPIOperationSpec {
+	spec := &openAPIOperationSpec{
+		OperationID: r.operationID(),
+		Summary:     op.summary,
+		Responses: map[string]*openAPIResponse{
+			"200": {Description: "OK"},
+			"default": {
+				Description: "error",
+				Content: map[string]openAPIMediaType{
+					"application/json": {Schema: schemas.schemaFor(reflect.TypeOf(gimlet.ErrorResponse{}))},
+				},
+			},
+		},
+	}
+
+	for _, name := range r.pathParameters() {
+		spec.Parameters = append(spec.Parameters, openAPIParameter{
+			Name:     name,
+			In:       "path",
+			Required: true,
+			Schema:   &openAPISchema{Type: "string"},
+		})
+	}
+	for _, name := range op.query {
+		spec.Parameters = append(spec.Parameters, openAPIParameter{
+			Name:   name,
+			In:     "query",
+			Schema: &openAPISchema{Type: "string"},
+		})
+	}
+
+	if op.request != nil {
+		spec.RequestBody = &openAPIRequestBody{
+			Required: true,
+			Content: map[string]openAPIMediaType{
+				"application/json": {Schema: schemas.schemaFor(reflect.TypeOf(op.request))},
+			},
+		}
+	}
+	if op.response != nil {
+		spec.Responses["200"].Content = map[string]openAPIMediaType{
+			"application/json": {Schema: schemas.schemaFor(reflect.TypeOf(op.response))},
+		}
+	}
+	if op.paginated {
+		spec.Responses["200"].Headers = map[string]openAPIHeader{
+			"Link": {
+				Description: "the next and previous pages of results",
+				Schema:      &openAPISchema{Type: "string"},
+			},
+		}
+	}
+
+	return spec
+}
+
+////////////////////////////////////////////////////////////////////////
+//
+// routes
+
+type openAPIRoute struct {
+	method string
+	path   string
+}
+
+func (r openAPIRoute) String() string { return r.method + " " + r.path }
+
+func (r openAPIRoute) pathParameters() []string {
+	names := []string{}
+	for _, part := range strings.Split(r.path, "/") {
+		if strings.HasPrefix(part, "{") && strings.HasSuffix(part, "}") {
+			names = append(names, strings.SplitN(strings.Trim(part, "{}"), ":", 2)[0])
+		}
+	}
+	return names
+}
+
+// operationID derives a name for the operation from its method and path,
+// e.g. getTasksByTaskId for GET /tasks/{task_id}.
+func (r openAPIRoute) operationID() string {
+	id := strings.ToLower(r.method)
+	for _, part := range strings.Split(r.path, "/") {
+		if strings.HasPrefix(part, "{") && strings.HasSuffix(part, "}") {
+			id += "By"
+			part = strings.Trim(part, "{}")
+		}
+		for _, word := range strings.FieldsFunc(part, func(c rune) bool { return c == '_' || c == '.' || c == '-' }) {
+			id += strings.ToUpper(word[:1]) + word[1:]
+		}
+	}
+	if id == strings.ToLower(r.method) {
+		id += "Root"
+	}
+	return id
+}
+
+type openAPIRoutes []openAPIRoute
+
+func (r openAPIRoutes) Len() int      { return len(r) }
+func (r openAPIRoutes) Swap(i, j int) { r[i], r[j] = r[j], r[i] }
+func (r openAPIRoutes) Less(i, j int) bool {
+	if r[i].path != r[j].path {
+		return r[i].path < r[j].path
+	}
+	return r[i].method < r[j].method
+}
+
+// getRegisteredRoutes registers the REST v2 routes on a new application and
+// returns the method and path of each of them, relative to the version
+// prefix.
+func getRegisteredRoutes() ([]openAPIRoute, error) {
+	app := gimlet.NewApp()
+	app.SetPrefix(evergreen.RestRoutePrefix)
+	// the GitHub webhook route is only registered if there's a secret
+	GetHandler(app, &data.MockConnector{}, nil, []byte("secret"), []byte("secret"))
+	if err := app.Resolve(); err != nil {
+		return nil, errors.Wrap(err, "problem resolving routes")
+	}
+	router, err := app.Router()
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	// The router's types are vendored by gimlet, so its routes are walked
+	// through reflection.
+	prefix := "/" + evergreen.RestRoutePrefix + "/v2"
+	routes := []openAPIRoute{}
+	var walkErr error
+	walk := reflect.ValueOf(router).MethodByName("Walk")
+	walkFunc := reflect.MakeFunc(walk.Type().In(0), func(args []reflect.Value) []reflect.Value {
+		out := args[0].MethodByName("GetPathTemplate").Call(nil)
+		if !out[1].IsNil() {
+			walkErr = out[1].Interface().(error)
+			return []reflect.Value{reflect.Zero(reflect.TypeOf((*error)(nil)).Elem())}
+		}
+		path := strings.TrimPrefix(out[0].String(), prefix)
+		if path == "" {
+			path = "/"
+		}
+
+		out = args[0].MethodByName("GetMethods").Call(nil)
+		for _, method := range out[0].Interface().([]string) {
+			routes = append(routes, openAPIRoute{method: method, path: path})
+		}
+		return []reflect.Value{reflect.Zero(reflect.TypeOf((*error)(nil)).Elem())}
+	})
+	if out := walk.Call([]reflect.Value{walkFunc}); !out[0].IsNil() {
+		return nil, errors.Wrap(out[0].Interface().(error), "problem walking routes")
+	}
+	if walkErr != nil {
+		return nil, errors.Wrap(walkErr, "problem walking routes")
+	}
+
+	sort.Sort(openAPIRoutes(routes))
+	return routes, nil
+}
+
+type openAPIMissing struct {
+	routes []string
+}
+
+func (m *openAPIMissing) add(route string) { m.routes = append(m.routes, route) }
+
+func (m *openAPIMissing) resolve() error {
+	if len(m.routes) == 0 {
+		return nil
+	}
+	return errors.Errorf("routes are missing from openAPIOperations: %s", strings.Join(m.routes, ", "))
+}
+
+////////////////////////////////////////////////////////////////////////
+//
+// schemas
+
+var (
+	timeType          = reflect.TypeOf(time.Time{})
+	apiTimeType       = reflect.TypeOf(model.APITime{})
+	objectIDType      = reflect.TypeOf(bson.ObjectId(""))
+	jsonMarshalerType = reflect.TypeOf((*json.Marshaler)(nil)).Elem()
+	textMarshalerType = reflect.TypeOf((*encoding.TextMarshaler)(nil)).Elem()
+)
+
+// openAPISchemas builds schemas from the JSON encoding of Go types. Named
+// structs are added to the document's components and referred to by name.
+type openAPISchemas struct {
+	schemas map[string]*openAPISchema
+	types   map[string]reflect.Type
+}
+
+func (s *openAPISchemas) schemaFor(t reflect.Type) *openAPISchema {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+
+	switch t {
+	case timeType, apiTimeType:
+		return &openAPISchema{Type: "string", Format: "date-time"}
+	case objectIDType:
+		return &openAPISchema{Type: "string"}
+	}
+	if t.Implements(jsonMarshalerType) || reflect.PtrTo(t).Implements(jsonMarshalerType) {
+		return &openAPISchema{}
+	}
+	if t.Implements(textMarshalerType) || reflect.PtrTo(t).Implements(textMarshalerType) {
+		return &openAPISchema{Type: "string"}
+	}
+
+	switch t.Kind() {
+	case reflect.Bool:
+		return &openAPISchema{Type: "boolean"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32:
+		return &openAPISchema{Type: "integer"}
+	case reflect.Int64, reflect.Uint64:
+		return &openAPISchema{Type: "integer", Format: "int64"}
+	case reflect.Float32, reflect.Float64:
+		return &openAPISchema{Type: "number"}
+	case reflect.String:
+		return &openAPISchema{Type: "string"}
+	case reflect.Slice, reflect.Array:
+		if t.Elem().Kind() == reflect.Uint8 {
+			return &openAPISchema{Type: "string", Format: "byte"}
+		}
+		return &openAPISchema{Type: "array", Items: s.schemaFor(t.Elem())}
+	case reflect.Map:
+		return &openAPISchema{Type: "object", AdditionalProperties: s.schemaFor(t.Elem())}
+	case reflect.Struct:
+		if t.Name() == "" {
+			return s.structSchema(t)
+		}
+		return &openAPISchema{Ref: "#/components/schemas/" + s.addStruct(t)}
+	}
+
+	// interfaces can hold anything
+	return &openAPISchema{}
+}
+
+// addStruct adds the schema of a named struct to the components, and
+// returns its name.
+func (s *openAPISchemas) addStruct(t reflect.Type) string {
+	name := t.Name()
+	if existing, ok := s.types[name]; ok && existing != t {
+		parts := strings.Split(t.PkgPath(), "/")
+		name = strings.Title(parts[len(parts)-1]) + name
+		if existing, ok = s.types[name]; ok && existing != t {
+			panic(fmt.Sprintf("two types are named %s", name))
+		}
+	}
+	if _, ok := s.types[name]; ok {
+		return name
+	}
+
+	// add the type before its fields, which may refer to it
+	s.types[name] = t
+	s.schemas[name] = &openAPISchema{}
+	*s.schemas[name] = *s.structSchema(t)
+
+	return name
+}
+
+func (s *openAPISchemas) structSchema(t reflect.Type) *openAPISchema {
+	schema := &openAPISchema{Type: "object", Properties: map[string]*openAPISchema{}}
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		tag := strings.Split(f.Tag.Get("json"), ",")[0]
+		if tag == "-" {
+			continue
+		}
+		if f.Anonymous && tag == "" {
+			ft := f.Type
+			for ft.Kind() == reflect.Ptr {
+				ft = ft.Elem()
+			}
+			if ft.Kind() == reflect.Struct {
+				for name, prop := range s.structSchema(ft).Properties {
+					schema.Properties[name] = prop
+				}
+				continue
+			}
+		}
+		if f.PkgPath != "" {
+			continue
+		}
+		if tag == "" {
+			tag = f.Name
+		}
+		schema.Properties[tag] = s.schemaFor(f.Type)
+	}
+
+	return schema
+}
+
+////////////////////////////////////////////////////////////////////////
+//
+// GET /rest/v2/openapi.json
+
+var (
+	openAPISpec     []byte
+	openAPISpecErr  error
+	openAPISpecOnce sync.Once
+)
+
+// GetOpenAPISpec returns the OpenAPI 3 document describing the REST v2
+// routes as indented JSON. It is only generated once.
+func GetOpenAPISpec() ([]byte, error) {
+	openAPISpecOnce.Do(func() {
+		var doc *openAPIDocument
+		if doc, openAPISpecErr = generateOpenAPI(); openAPISpecErr != nil {
+			return
+		}
+		openAPISpec, openAPISpecErr = json.MarshalIndent(doc, "", "  ")
+		openAPISpec = append(openAPISpec, '\n')
+	})
+
+	return openAPISpec, openAPISpecErr
+}
+
+func makeFetchOpenAPISpec() gimlet.RouteHandler {
+	return &openAPISpecHandler{}
+}
+
+type openAPISpecHandler struct{}
+
+func (h *openAPISpecHandler) Factory() gimlet.RouteHandler                     { return h }
+func (h *openAPISpecHandler) Parse(ctx context.Context, r *http.Request) error { return nil }
+
+func (h *openAPISpecHandler) Run(ctx context.Context) gimlet.Responder {
+	spec, err := GetOpenAPISpec()
+	if err != nil {
+		return gimlet.MakeJSONInternalErrorResponder(err)
+	}
+
+	return gimlet.NewJSONResponse(json.RawMessage(spec))
+}
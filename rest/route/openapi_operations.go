@@ -0,0 +1,449 @@
+package route
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/evergreen-ci/evergreen/apimodels"
+	"github.com/evergreen-ci/evergreen/model/patch"
+	"github.com/evergreen-ci/evergreen/rest/graphql"
+	"github.com/evergreen-ci/evergreen/rest/model"
+)
+
+var (
+	paginationQuery = []string{"start_at", "limit"}
+
+	// statusChangeRequest is the body of requests that change the
+	// activation and priority of builds, patches and tasks.
+	statusChangeRequest = struct {
+		Activated *bool  `json:"activated"`
+		Priority  *int64 `json:"priority"`
+	}{}
+)
+
+// openAPIOperations annotates each REST v2 route, keyed by its method and
+// path, for the OpenAPI document.
+var openAPIOperations = map[string]openAPIOperation{
+	"GET /": {
+		summary: "placeholder for the root of the API",
+	},
+	"GET /admin": {
+		summary:  "get the banner, for older versions of the CLI",
+		response: model.APIAdminSettings{},
+	},
+	"GET /admin/audit": {
+		summary:   "get the audit log of changes made by users, newest first",
+		query:     []string{"actor", "resource_type", "resource_id", "action", "start", "end", "before", "limit"},
+		response:  []model.APIAuditEntry{},
+		paginated: true,
+	},
+	"GET /admin/banner": {
+		summary:  "get the banner",
+		response: model.APIBanner{},
+	},
+	"POST /admin/banner": {
+		summary:  "set the banner",
+		request:  model.APIBanner{},
+		response: model.APIBanner{},
+	},
+	"GET /admin/events": {
+		summary:   "get the log of changes to the admin settings",
+		query:     []string{"ts", "limit"},
+		response:  []model.APIAdminEvent{},
+		paginated: true,
+	},
+	"POST /admin/restart": {
+		summary: "restart the tasks that failed in a time range",
+		request: struct {
+			StartTime  time.Time `json:"start_time"`
+			EndTime    time.Time `json:"end_time"`
+			DryRun     bool      `json:"dry_run"`
+			OnlyRed    bool      `json:"only_red"`
+			OnlyPurple bool      `json:"only_purple"`
+		}{},
+		response: model.RestartTasksResponse{},
+	},
+	"POST /admin/revert": {
+		summary: "revert a change to the admin settings",
+		request: struct {
+			GUID string `json:"guid"`
+		}{},
+		response: struct{}{},
+	},
+	"GET /admin/service_accounts": {
+		summary:  "list the service accounts",
+		response: []model.APIServiceAccount{},
+	},
+	"POST /admin/service_accounts": {
+		summary:  "create a service account",
+		request:  model.APIServiceAccount{},
+		response: model.APIServiceAccount{},
+	},
+	"GET /admin/service_accounts/{account_id}": {
+		summary:  "get a service account",
+		response: model.APIServiceAccount{},
+	},
+	"DELETE /admin/service_accounts/{account_id}": {
+		summary:  "delete a service account and revoke its tokens",
+		response: struct{}{},
+	},
+	"POST /admin/service_accounts/{account_id}/tokens": {
+		summary:  "create an API token for a service account",
+		request:  model.APIToken{},
+		response: model.APIToken{},
+	},
+	"DELETE /admin/service_accounts/{account_id}/tokens/{token_name}": {
+		summary:  "revoke an API token of a service account",
+		response: struct{}{},
+	},
+	"POST /admin/service_flags": {
+		summary: "set the service flags",
+		request: struct {
+			Flags model.APIServiceFlags `json:"service_flags"`
+		}{},
+		response: model.APIServiceFlags{},
+	},
+	"GET /admin/settings": {
+		summary:  "get the admin settings",
+		response: model.APIAdminSettings{},
+	},
+	"POST /admin/settings": {
+		summary:  "change the admin settings",
+		request:  model.APIAdminSettings{},
+		response: model.APIAdminSettings{},
+	},
+	"DELETE /admin/task_queue": {
+		summary:  "clear the task queue of a distro",
+		query:    []string{"distro"},
+		response: struct{}{},
+	},
+	"GET /alias/{name}": {
+		summary:  "get the aliases of a project",
+		response: []model.APIAlias{},
+	},
+	"GET /builds/{build_id}": {
+		summary:  "get a build",
+		response: model.APIBuild{},
+	},
+	"PATCH /builds/{build_id}": {
+		summary:  "change the activation or priority of a build",
+		request:  statusChangeRequest,
+		response: model.APIBuild{},
+	},
+	"POST /builds/{build_id}/abort": {
+		summary:  "abort a build",
+		response: model.APIBuild{},
+	},
+	"POST /builds/{build_id}/restart": {
+		summary:  "restart a build",
+		response: model.APIBuild{},
+	},
+	"GET /builds/{build_id}/tasks": {
+		summary:   "get the tasks of a build",
+		query:     append([]string{"status", "fetch_all_executions"}, paginationQuery...),
+		response:  []model.APITask{},
+		paginated: true,
+	},
+	"GET /cost/distro/{distro_id}": {
+		summary:  "get the cost of a distro over a time range",
+		query:    []string{"starttime", "duration"},
+		response: model.APIDistroCost{},
+	},
+	"GET /cost/project/{project_id}/tasks": {
+		summary:   "get the cost of the tasks of a project over a time range",
+		query:     append([]string{"starttime", "duration"}, paginationQuery...),
+		response:  []model.APITaskCost{},
+		paginated: true,
+	},
+	"GET /cost/version/{version_id}": {
+		summary:  "get the cost of a version",
+		response: model.APIVersionCost{},
+	},
+	"GET /distros": {
+		summary:  "list the distros",
+		response: []model.APIDistro{},
+	},
+	"GET /events/stream": {
+		summary:  "wait for events after an event, which may be given by the Last-Event-ID header",
+		query:    []string{"after", "resource_types", "selector", "regex_selector", "timeout", "limit"},
+		response: model.APIEventStreamBatch{},
+	},
+	"POST /graphql": {
+		summary:  "query versions, builds, tasks, tests and patches with GraphQL",
+		request:  graphql.Request{},
+		response: graphql.Response{},
+	},
+	"POST /hooks/github": {
+		summary: "receive a GitHub webhook",
+	},
+	"POST /hooks/gitlab": {
+		summary: "receive a GitLab merge request webhook",
+		request: patch.GitlabMergeRequestEvent{},
+	},
+	"GET /hosts": {
+		summary:   "list the hosts",
+		query:     []string{"status", "host_id", "limit"},
+		response:  []model.APIHost{},
+		paginated: true,
+	},
+	"POST /hosts": {
+		summary: "spawn a host",
+		request: struct {
+			Distro  string `json:"distro"`
+			KeyName string `json:"keyname"`
+		}{},
+		response: model.APIHost{},
+	},
+	"GET /hosts/{host_id}": {
+		summary:  "get a host",
+		response: model.APIHost{},
+	},
+	"POST /hosts/{host_id}/change_password": {
+		summary: "change the RDP password of a spawn host",
+		request: model.APISpawnHostModify{},
+	},
+	"POST /hosts/{host_id}/extend_expiration": {
+		summary: "extend the expiration of a spawn host",
+		request: model.APISpawnHostModify{},
+	},
+	"POST /hosts/{host_id}/terminate": {
+		summary: "terminate a spawn host",
+	},
+	"POST /hosts/{task_id}/create": {
+		summary:  "create a host for a task",
+		request:  apimodels.CreateHost{},
+		response: struct{}{},
+	},
+	"GET /hosts/{task_id}/list": {
+		summary:  "list the hosts created by a task or its build",
+		response: []model.CreateHost{},
+	},
+	"GET /keys": {
+		summary:  "list your public keys",
+		response: []model.APIPubKey{},
+	},
+	"POST /keys": {
+		summary: "add a public key",
+		request: model.APIPubKey{},
+	},
+	"DELETE /keys/{key_name}": {
+		summary: "delete a public key",
+	},
+	"GET /openapi.json": {
+		summary:  "get this document",
+		response: json.RawMessage{},
+	},
+	"GET /patches/{patch_id}": {
+		summary:  "get a patch",
+		response: model.APIPatch{},
+	},
+	"PATCH /patches/{patch_id}": {
+		summary:  "change the activation or priority of a patch",
+		request:  statusChangeRequest,
+		response: model.APIPatch{},
+	},
+	"POST /patches/{patch_id}/abort": {
+		summary:  "abort a patch",
+		response: model.APIPatch{},
+	},
+	"POST /patches/{patch_id}/rebase": {
+		summary:  "create a copy of a patch based on a newer revision, after checking that its changes still apply",
+		request:  model.PatchRebaseRequest{},
+		response: model.APIPatch{},
+	},
+	"POST /patches/{patch_id}/restart": {
+		summary:  "restart a patch",
+		response: model.APIPatch{},
+	},
+	"GET /projects": {
+		summary:   "list the projects",
+		query:     paginationQuery,
+		response:  []model.APIProject{},
+		paginated: true,
+	},
+	"GET /projects/{project_id}/patches": {
+		summary:   "list the patches of a project, newest first",
+		query:     paginationQuery,
+		response:  []model.APIPatch{},
+		paginated: true,
+	},
+	"GET /projects/{project_id}/recent_versions": {
+		summary:  "get the recent versions of a project grouped by build variant",
+		query:    []string{"offset", "limit"},
+		response: model.VersionVariantData{},
+	},
+	"GET /projects/{project_id}/revisions/{commit_hash}/tasks": {
+		summary:   "get the tasks of a project at a revision",
+		query:     append([]string{"status"}, paginationQuery...),
+		response:  []model.APITask{},
+		paginated: true,
+	},
+	"GET /roles": {
+		summary:  "list the roles",
+		response: []model.APIRole{},
+	},
+	"POST /roles": {
+		summary:  "create or update a role",
+		request:  model.APIRole{},
+		response: model.APIRole{},
+	},
+	"GET /roles/{role_id}": {
+		summary:  "get a role",
+		response: model.APIRole{},
+	},
+	"DELETE /roles/{role_id}": {
+		summary:  "delete a role",
+		response: struct{}{},
+	},
+	"GET /status/cli_version": {
+		summary:  "get the current version of the CLI and where to download it",
+		response: model.APICLIUpdate{},
+	},
+	"GET /status/hosts/distros": {
+		summary:  "get the number of hosts of each distro by status",
+		response: model.APIHostStatsByDistro{},
+	},
+	"GET /status/notifications": {
+		summary:  "get the number of pending notifications",
+		response: model.APIEventStats{},
+	},
+	"GET /status/recent_tasks": {
+		summary:  "get the number of recent tasks by status, or the tasks themselves if verbose",
+		query:    []string{"minutes", "verbose", "status"},
+		response: model.APITaskStats{},
+	},
+	"GET /subscriptions": {
+		summary:  "list the subscriptions of an owner",
+		query:    []string{"owner", "type"},
+		response: []model.APISubscription{},
+	},
+	"POST /subscriptions": {
+		summary: "create or update subscriptions",
+		request: []model.APISubscription{},
+	},
+	"DELETE /subscriptions": {
+		summary: "delete a subscription",
+		query:   []string{"id"},
+	},
+	"POST /subscriptions/{subscription_id}/rotate_secret": {
+		summary: "rotate the secret of a webhook subscription",
+		request: struct {
+			GracePeriodSecs *int `json:"grace_period_secs"`
+		}{},
+		response: model.APISubscription{},
+	},
+	"POST /tasks/bulk": {
+		summary:  "restart, abort, deactivate or set the priority of the tasks matching a filter in the background, or list them in a dry run",
+		request:  model.BulkTaskRequest{},
+		response: model.APIBulkTaskOperation{},
+	},
+	"GET /tasks/bulk/{job_id}": {
+		summary:  "get the progress of a bulk task operation",
+		response: model.APIBulkTaskOperation{},
+	},
+	"GET /tasks/{task_id}": {
+		summary:  "get a task",
+		query:    []string{"fetch_all_executions"},
+		response: model.APITask{},
+	},
+	"PATCH /tasks/{task_id}": {
+		summary:  "change the activation or priority of a task",
+		request:  statusChangeRequest,
+		response: model.APITask{},
+	},
+	"POST /tasks/{task_id}/abort": {
+		summary:  "abort a task",
+		response: model.APITask{},
+	},
+	"POST /tasks/{task_id}/generate": {
+		summary: "generate tasks from JSON project files",
+		request: []json.RawMessage{},
+	},
+	"GET /tasks/{task_id}/metrics/process": {
+		summary:   "get the process metrics of a task",
+		query:     paginationQuery,
+		response:  []model.APIProcessMetrics{},
+		paginated: true,
+	},
+	"GET /tasks/{task_id}/metrics/system": {
+		summary:   "get the system metrics of a task",
+		query:     paginationQuery,
+		response:  []model.APISystemMetrics{},
+		paginated: true,
+	},
+	"POST /tasks/{task_id}/restart": {
+		summary:  "restart a task",
+		response: model.APITask{},
+	},
+	"GET /tasks/{task_id}/tests": {
+		summary:   "get the test results of a task",
+		query:     append([]string{"status", "execution"}, paginationQuery...),
+		response:  []model.APITest{},
+		paginated: true,
+	},
+	"GET /user/settings": {
+		summary:  "get your settings",
+		response: model.APIUserSettings{},
+	},
+	"POST /user/settings": {
+		summary: "change your settings",
+		request: model.APIUserSettings{},
+	},
+	"GET /user/tokens": {
+		summary:  "list your API tokens",
+		response: []model.APIToken{},
+	},
+	"POST /user/tokens": {
+		summary:  "create an API token, which is only returned once",
+		request:  model.APIToken{},
+		response: model.APIToken{},
+	},
+	"DELETE /user/tokens/{token_name}": {
+		summary:  "revoke one of your API tokens",
+		response: struct{}{},
+	},
+	"GET /users/{user_id}/hosts": {
+		summary:   "list the hosts of a user",
+		query:     []string{"status", "host_id", "limit"},
+		response:  []model.APIHost{},
+		paginated: true,
+	},
+	"GET /users/{user_id}/patches": {
+		summary:   "list the patches of a user, newest first",
+		query:     paginationQuery,
+		response:  []model.APIPatch{},
+		paginated: true,
+	},
+	"GET /users/{user_id}/roles": {
+		summary:  "get the roles of a user",
+		response: model.APIUserRoles{},
+	},
+	"POST /users/{user_id}/roles": {
+		summary: "add and remove roles of a user",
+		request: struct {
+			Add    []string `json:"add"`
+			Remove []string `json:"remove"`
+		}{},
+		response: model.APIUserRoles{},
+	},
+	"GET /versions/{version_id}": {
+		summary:  "get a version",
+		response: model.APIVersion{},
+	},
+	"POST /versions/{version_id}/abort": {
+		summary:  "abort a version",
+		response: model.APIVersion{},
+	},
+	"GET /versions/{version_id}/builds": {
+		summary:  "get the builds of a version",
+		response: []model.APIBuild{},
+	},
+	"GET /versions/{version_id}/downstream": {
+		summary:  "get the versions that downstream triggers created from a version",
+		response: []model.APIVersion{},
+	},
+	"POST /versions/{version_id}/restart": {
+		summary:  "restart a version",
+		response: model.APIVersion{},
+	},
+}
@@ -0,0 +1,114 @@
+package route
+
+import (
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/evergreen-ci/evergreen/rest/model"
+	"github.com/evergreen-ci/evergreen/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestOpenAPIOperationsMatchRoutes(t *testing.T) {
+	routes, err := getRegisteredRoutes()
+	require.NoError(t, err)
+	require.NotEmpty(t, routes)
+
+	registered := map[string]bool{}
+	for _, r := range routes {
+		registered[r.String()] = true
+		_, ok := openAPIOperations[r.String()]
+		assert.True(t, ok, "route '%s' must be added to openAPIOperations", r)
+	}
+	for route := range openAPIOperations {
+		assert.True(t, registered[route], "route '%s' in openAPIOperations is not registered", route)
+	}
+}
+
+func TestOpenAPISpecIsCurrent(t *testing.T) {
+	spec, err := GetOpenAPISpec()
+	require.NoError(t, err)
+
+	checkedIn, err := ioutil.ReadFile(filepath.Join(testutil.GetDirectoryOfFile(), "..", "openapi.json"))
+	require.NoError(t, err)
+	assert.True(t, string(spec) == string(checkedIn), "rest/openapi.json is out of date, run 'make generate-openapi'")
+}
+
+func TestOpenAPISpec(t *testing.T) {
+	doc, err := generateOpenAPI()
+	require.NoError(t, err)
+
+	assert.Equal(t, openAPIVersion, doc.OpenAPI)
+	require.Contains(t, doc.Paths, "/tasks/{task_id}")
+	op := doc.Paths["/tasks/{task_id}"]["get"]
+	require.NotNil(t, op)
+	assert.Equal(t, "getTasksByTaskId", op.OperationID)
+	require.Len(t, op.Parameters, 2)
+	assert.Equal(t, "task_id", op.Parameters[0].Name)
+	assert.Equal(t, "path", op.Parameters[0].In)
+	assert.True(t, op.Parameters[0].Required)
+	assert.Equal(t, "fetch_all_executions", op.Parameters[1].Name)
+	assert.Equal(t, "query", op.Parameters[1].In)
+	assert.Equal(t, "#/components/schemas/APITask", op.Responses["200"].Content["application/json"].Schema.Ref)
+
+	tests := doc.Paths["/tasks/{task_id}/tests"]["get"]
+	require.NotNil(t, tests)
+	assert.Contains(t, tests.Responses["200"].Headers, "Link")
+	assert.Equal(t, "array", tests.Responses["200"].Content["application/json"].Schema.Type)
+
+	task := doc.Components.Schemas["APITask"]
+	require.NotNil(t, task)
+	assert.Equal(t, "object", task.Type)
+	assert.Equal(t, "string", task.Properties["task_id"].Type)
+	assert.Equal(t, "date-time", task.Properties["create_time"].Format)
+	assert.Equal(t, "array", task.Properties["depends_on"].Type)
+}
+
+func TestOpenAPISchemas(t *testing.T) {
+	schemas := &openAPISchemas{schemas: map[string]*openAPISchema{}, types: map[string]reflect.Type{}}
+
+	type nested struct {
+		Name     string            `json:"name"`
+		Children []nested          `json:"children"`
+		Labels   map[string]string `json:"labels"`
+		Ignored  string            `json:"-"`
+		private  string
+	}
+	schema := schemas.schemaFor(reflect.TypeOf(&nested{}))
+	assert.Equal(t, "#/components/schemas/nested", schema.Ref)
+
+	n := schemas.schemas["nested"]
+	require.NotNil(t, n)
+	assert.Len(t, n.Properties, 3)
+	assert.Equal(t, "#/components/schemas/nested", n.Properties["children"].Items.Ref)
+	assert.Equal(t, "string", n.Properties["labels"].AdditionalProperties.Type)
+
+	schema = schemas.schemaFor(reflect.TypeOf(struct {
+		Key model.APIString `json:"key"`
+		Raw []byte          `json:"raw"`
+	}{}))
+	assert.Empty(t, schema.Ref)
+	assert.Equal(t, "string", schema.Properties["key"].Type)
+	assert.Equal(t, "byte", schema.Properties["raw"].Format)
+}
+
+func TestOpenAPISpecHandler(t *testing.T) {
+	h := makeFetchOpenAPISpec().Factory()
+	r, err := http.NewRequest(http.MethodGet, "/openapi.json", nil)
+	require.NoError(t, err)
+	require.NoError(t, h.Parse(context.Background(), r))
+	resp := h.Run(context.Background())
+	require.Equal(t, http.StatusOK, resp.Status())
+
+	out, err := json.Marshal(resp.Data())
+	require.NoError(t, err)
+	doc := map[string]interface{}{}
+	require.NoError(t, json.Unmarshal(out, &doc))
+	assert.Equal(t, openAPIVersion, doc["openapi"])
+}
@@ -7,6 +7,11 @@ import (
 	"time"
 
 	"github.com/evergreen-ci/evergreen"
+	"github.com/evergreen-ci/evergreen/auth"
+	"github.com/evergreen-ci/evergreen/model/audit"
+	"github.com/evergreen-ci/evergreen/model/patch"
+	"github.com/evergreen-ci/evergreen/model/role"
+	"github.com/evergreen-ci/evergreen/model/user"
 	"github.com/evergreen-ci/evergreen/rest/data"
 	"github.com/evergreen-ci/evergreen/rest/model"
 	"github.com/evergreen-ci/evergreen/util"
@@ -370,9 +375,11 @@ func getPatchAbortManager(route string, version int) *RouteManager {
 		Version: version,
 		Methods: []MethodHandler{
 			{
-				MethodType:     http.MethodPost,
-				Authenticator:  &RequireUserAuthenticator{},
-				RequestHandler: p.Handler(),
+				MethodType:        http.MethodPost,
+				TokenScope:        user.TokenScopePatch,
+				PrefetchFunctions: []PrefetchFunc{PrefetchProjectContext},
+				Authenticator:     &ProjectPermissionAuthenticator{Permission: role.PermissionRestart},
+				RequestHandler:    p.Handler(),
 			},
 		},
 	}
@@ -428,9 +435,11 @@ func getPatchRestartManager(route string, version int) *RouteManager {
 		Version: version,
 		Methods: []MethodHandler{
 			{
-				MethodType:     http.MethodPost,
-				Authenticator:  &RequireUserAuthenticator{},
-				RequestHandler: p.Handler(),
+				MethodType:        http.MethodPost,
+				TokenScope:        user.TokenScopePatch,
+				PrefetchFunctions: []PrefetchFunc{PrefetchProjectContext},
+				Authenticator:     &ProjectPermissionAuthenticator{Permission: role.PermissionRestart},
+				RequestHandler:    p.Handler(),
 			},
 		},
 	}
@@ -473,3 +482,93 @@ func (p *patchRestartHandler) Execute(ctx context.Context, sc data.Connector) (R
 		Result: []model.Model{patchModel},
 	}, nil
 }
+
+////////////////////////////////////////////////////////////////////////
+//
+// POST /rest/v2/patches/{patch_id}/rebase
+
+type patchRebaseHandler struct {
+	request model.PatchRebaseRequest
+
+	patchId string
+	sc      data.Connector
+}
+
+func makeRebasePatch(sc data.Connector) gimlet.RouteHandler {
+	return &patchRebaseHandler{
+		sc: sc,
+	}
+}
+
+func (p *patchRebaseHandler) Factory() gimlet.RouteHandler {
+	return &patchRebaseHandler{
+		sc: p.sc,
+	}
+}
+
+func (p *patchRebaseHandler) Parse(ctx context.Context, r *http.Request) error {
+	p.patchId = gimlet.GetVars(r)["patch_id"]
+	if !patch.IsValidId(p.patchId) {
+		return gimlet.ErrorResponse{
+			StatusCode: http.StatusBadRequest,
+			Message:    fmt.Sprintf("'%s' is not a valid patch id", p.patchId),
+		}
+	}
+
+	// the request body is optional
+	body := util.NewRequestReader(r)
+	defer body.Close()
+	if length, err := util.ReadJSONIntoWithLength(body, &p.request); err != nil && length > 0 {
+		return gimlet.ErrorResponse{
+			StatusCode: http.StatusBadRequest,
+			Message:    errors.Wrap(err, "problem parsing request body").Error(),
+		}
+	}
+
+	return nil
+}
+
+func (p *patchRebaseHandler) Run(ctx context.Context) gimlet.Responder {
+	u := MustHaveUser(ctx)
+
+	existing, err := p.sc.FindPatchById(p.patchId)
+	if err != nil {
+		return gimlet.MakeJSONErrorResponder(errors.Wrap(err, "Database error"))
+	}
+	projectRef, err := p.sc.FindProjectByBranch(existing.Project)
+	if err != nil {
+		return gimlet.MakeJSONInternalErrorResponder(errors.Wrapf(err, "problem finding project '%s'", existing.Project))
+	}
+	if projectRef == nil {
+		return gimlet.MakeJSONErrorResponder(gimlet.ErrorResponse{
+			StatusCode: http.StatusNotFound,
+			Message:    fmt.Sprintf("project '%s' not found", existing.Project),
+		})
+	}
+	ok, err := auth.HasProjectPermission(p.sc.GetSuperUsers(), u, projectRef, role.PermissionPatch)
+	if err != nil {
+		return gimlet.MakeJSONInternalErrorResponder(err)
+	}
+	if !ok {
+		return gimlet.MakeJSONErrorResponder(gimlet.ErrorResponse{
+			StatusCode: http.StatusUnauthorized,
+			Message:    fmt.Sprintf("user '%s' cannot patch project '%s'", u.Username(), existing.Project),
+		})
+	}
+	if err = p.sc.CheckPatchQuota(u.Username()); err != nil {
+		return gimlet.MakeJSONErrorResponder(err)
+	}
+
+	rebased, err := p.sc.RebasePatch(ctx, p.patchId, u.Username(), p.request.Base, p.request.Finalize)
+	if err != nil {
+		return gimlet.MakeJSONErrorResponder(errors.Wrapf(err, "problem rebasing patch '%s'", p.patchId))
+	}
+	audit.SetAction(ctx, "patch", rebased.Id.Hex(), "rebase")
+
+	patchModel := &model.APIPatch{}
+	if err = patchModel.BuildFromService(*rebased); err != nil {
+		return gimlet.MakeJSONInternalErrorResponder(errors.Wrap(err, "API model error"))
+	}
+
+	return gimlet.NewJSONResponse(patchModel)
+}
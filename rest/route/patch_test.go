@@ -6,6 +6,7 @@ import (
 	"testing"
 	"time"
 
+	dbModel "github.com/evergreen-ci/evergreen/model"
 	"github.com/evergreen-ci/evergreen/model/patch"
 	"github.com/evergreen-ci/evergreen/model/user"
 	"github.com/evergreen-ci/evergreen/model/version"
@@ -468,3 +469,86 @@ func executePatchesByUserRequest(user string, ts time.Time, limit int, sc *data.
 
 	return pe.Execute(context.TODO(), sc)
 }
+
+////////////////////////////////////////////////////////////////////////
+//
+// Tests for rebase patch route
+
+type PatchRebaseSuite struct {
+	sc     *data.MockConnector
+	objIds []bson.ObjectId
+
+	suite.Suite
+}
+
+func TestPatchRebaseSuite(t *testing.T) {
+	suite.Run(t, new(PatchRebaseSuite))
+}
+
+func (s *PatchRebaseSuite) SetupTest() {
+	s.objIds = []bson.ObjectId{bson.NewObjectId(), bson.NewObjectId()}
+
+	s.sc = &data.MockConnector{
+		MockPatchConnector: data.MockPatchConnector{
+			CachedPatches: []patch.Patch{
+				{
+					Id:            s.objIds[0],
+					Project:       "proj",
+					Githash:       "old",
+					Author:        "author",
+					BuildVariants: []string{"bv"},
+					Tasks:         []string{"t1"},
+					Patches:       []patch.ModulePatch{{Githash: "old"}},
+				},
+				{Id: s.objIds[1], Project: "missing"},
+			},
+		},
+		MockBuildConnector: data.MockBuildConnector{
+			CachedProjects: map[string]*dbModel.ProjectRef{
+				"proj": {Identifier: "proj", Admins: []string{"admin", "busy"}},
+			},
+		},
+		MockQuotaConnector: data.MockQuotaConnector{
+			OverPatchQuota: []string{"busy"},
+		},
+	}
+}
+
+func (s *PatchRebaseSuite) TestRebase() {
+	ctx := gimlet.AttachUser(context.Background(), &user.DBUser{Id: "admin"})
+
+	rm := makeRebasePatch(s.sc).(*patchRebaseHandler)
+	rm.patchId = s.objIds[0].Hex()
+	rm.request = model.PatchRebaseRequest{Base: "new", Finalize: true}
+	res := rm.Run(ctx)
+	s.Require().Equal(http.StatusOK, res.Status())
+
+	p, ok := res.Data().(*model.APIPatch)
+	s.Require().True(ok)
+	s.NotEqual(s.objIds[0].Hex(), model.FromAPIString(p.Id))
+	s.Equal("new", model.FromAPIString(p.Githash))
+	s.Equal("admin", model.FromAPIString(p.Author))
+	s.Require().Len(p.Variants, 1)
+	s.Equal("bv", model.FromAPIString(p.Variants[0]))
+	s.True(p.Activated)
+	s.Len(s.sc.MockPatchConnector.CachedPatches, 3)
+}
+
+func (s *PatchRebaseSuite) TestRebaseChecksQuota() {
+	ctx := gimlet.AttachUser(context.Background(), &user.DBUser{Id: "busy"})
+
+	rm := makeRebasePatch(s.sc).(*patchRebaseHandler)
+	rm.patchId = s.objIds[0].Hex()
+	res := rm.Run(ctx)
+	s.Equal(http.StatusTooManyRequests, res.Status())
+	s.Len(s.sc.MockPatchConnector.CachedPatches, 2)
+}
+
+func (s *PatchRebaseSuite) TestRebaseFailsWithoutProject() {
+	ctx := gimlet.AttachUser(context.Background(), &user.DBUser{Id: "admin"})
+
+	rm := makeRebasePatch(s.sc).(*patchRebaseHandler)
+	rm.patchId = s.objIds[1].Hex()
+	res := rm.Run(ctx)
+	s.Equal(http.StatusNotFound, res.Status())
+}
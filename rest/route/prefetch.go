@@ -4,7 +4,9 @@ import (
 	"context"
 	"net/http"
 
+	"github.com/evergreen-ci/evergreen/auth"
 	"github.com/evergreen-ci/evergreen/model"
+	"github.com/evergreen-ci/evergreen/model/role"
 	"github.com/evergreen-ci/evergreen/model/user"
 	"github.com/evergreen-ci/evergreen/rest/data"
 	"github.com/evergreen-ci/gimlet"
@@ -19,6 +21,9 @@ const (
 	// Key value used to map user and project data to request context.
 	// These are private custom types to avoid key collisions.
 	RequestContext requestContextKey = 0
+	// apiTokenContext maps the API token that authenticated a request, and
+	// its user, to the request context.
+	apiTokenContext requestContextKey = 1
 )
 
 // PrefetchFunc is a function signature that defines types of functions which may
@@ -51,6 +56,19 @@ func PrefetchProjectContext(ctx context.Context, sc data.Connector, r *http.Requ
 		}
 	}
 
+	if opCtx.ProjectRef != nil && opCtx.ProjectRef.Private && user != nil {
+		canView, err := auth.HasProjectPermission(sc.GetSuperUsers(), user, opCtx.ProjectRef, role.PermissionView)
+		if err != nil {
+			return ctx, err
+		}
+		if !canView {
+			return ctx, gimlet.ErrorResponse{
+				StatusCode: http.StatusNotFound,
+				Message:    "Project not found",
+			}
+		}
+	}
+
 	if opCtx.Patch != nil && user == nil {
 		return ctx, gimlet.ErrorResponse{
 			StatusCode: http.StatusNotFound,
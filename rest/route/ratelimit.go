@@ -0,0 +1,172 @@
+package route
+
+import (
+	"fmt"
+	"math"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/evergreen-ci/evergreen"
+	"github.com/evergreen-ci/gimlet"
+	"github.com/mongodb/grip"
+	"github.com/mongodb/grip/message"
+)
+
+// rateLimitConfigTTL is how long the middleware uses the rate limit
+// settings before reading them again.
+const rateLimitConfigTTL = time.Minute
+
+// NewRateLimitMiddleware returns a middleware that limits how often each
+// user can make requests to the REST v2 routes in each route group, using
+// the rate limits in the admin settings. Requests over the limit get a 429
+// response with a Retry-After header. It must run after the user is
+// attached to the request; requests without a user are not limited.
+func NewRateLimitMiddleware() gimlet.Middleware {
+	return &rateLimitMiddleware{
+		getConfig: evergreen.GetRateLimitConfig,
+		buckets:   map[rateLimitKey]*tokenBucket{},
+	}
+}
+
+type rateLimitMiddleware struct {
+	getConfig func() (*evergreen.RateLimitConfig, error)
+
+	mu        sync.Mutex
+	config    *evergreen.RateLimitConfig
+	refreshed time.Time
+	buckets   map[rateLimitKey]*tokenBucket
+}
+
+type rateLimitKey struct {
+	user  string
+	group string
+}
+
+// tokenBucket holds a user's remaining allowance of requests in a route
+// group as of the last time it was updated.
+type tokenBucket struct {
+	tokens  float64
+	updated time.Time
+}
+
+// refill adds the tokens earned since the bucket was last updated.
+func (b *tokenBucket) refill(limit evergreen.RateLimit, now time.Time) {
+	perSecond := float64(limit.RequestsPerMinute) / 60
+	b.tokens = math.Min(float64(limit.Burst), b.tokens+now.Sub(b.updated).Seconds()*perSecond)
+	b.updated = now
+}
+
+func (m *rateLimitMiddleware) ServeHTTP(rw http.ResponseWriter, r *http.Request, next http.HandlerFunc) {
+	path, ok := restV2RoutePath(r.URL.Path)
+	u := gimlet.GetUser(r.Context())
+	if !ok || u == nil {
+		next(rw, r)
+		return
+	}
+
+	retryAfter, limited := m.take(u.Username(), path, time.Now())
+	if !limited {
+		next(rw, r)
+		return
+	}
+
+	rw.Header().Set("Retry-After", strconv.Itoa(int(math.Ceil(retryAfter.Seconds()))))
+	gimlet.WriteJSONResponse(rw, http.StatusTooManyRequests, gimlet.ErrorResponse{
+		StatusCode: http.StatusTooManyRequests,
+		Message:    fmt.Sprintf("rate limit exceeded for user '%s', retry after %s", u.Username(), retryAfter.Round(time.Second)),
+	})
+}
+
+// take uses one of the user's requests for the route group of the path,
+// and returns whether the user is over the limit, and if so, how long until
+// another request is allowed.
+func (m *rateLimitMiddleware) take(user, path string, now time.Time) (time.Duration, bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	config := m.currentConfig(now)
+	if config == nil || config.IsExempt(user) {
+		return 0, false
+	}
+	group, limit := config.LimitForPath(path)
+	if limit.IsZero() {
+		return 0, false
+	}
+	if limit.Burst < 1 {
+		limit.Burst = 1
+	}
+
+	key := rateLimitKey{user: user, group: group}
+	bucket, ok := m.buckets[key]
+	if !ok {
+		bucket = &tokenBucket{tokens: float64(limit.Burst), updated: now}
+		m.buckets[key] = bucket
+	}
+	bucket.refill(limit, now)
+
+	if bucket.tokens < 1 {
+		perSecond := float64(limit.RequestsPerMinute) / 60
+		return time.Duration((1 - bucket.tokens) / perSecond * float64(time.Second)), true
+	}
+	bucket.tokens--
+
+	return 0, false
+}
+
+// currentConfig returns the rate limit settings, reading them again if
+// they're out of date. Buckets that have refilled completely are dropped
+// when the settings are read, since they're the same as new buckets.
+func (m *rateLimitMiddleware) currentConfig(now time.Time) *evergreen.RateLimitConfig {
+	if m.config != nil && now.Sub(m.refreshed) < rateLimitConfigTTL {
+		return m.config
+	}
+
+	config, err := m.getConfig()
+	if err != nil {
+		grip.Warning(message.WrapError(err, message.Fields{
+			"message": "problem getting rate limit settings, using previous settings",
+		}))
+		return m.config
+	}
+	m.config = config
+	m.refreshed = now
+
+	limits := map[string]evergreen.RateLimit{evergreen.DefaultRateLimitRouteGroup: config.Default}
+	for _, group := range config.RouteGroups {
+		limits[group.Name] = group.Limit
+	}
+	for key, bucket := range m.buckets {
+		limit, ok := limits[key.group]
+		if !ok || limit.IsZero() {
+			delete(m.buckets, key)
+			continue
+		}
+		bucket.refill(limit, now)
+		if bucket.tokens >= float64(limit.Burst) {
+			delete(m.buckets, key)
+		}
+	}
+
+	return m.config
+}
+
+// restV2RoutePath returns the path of a request to a REST v2 route,
+// relative to /rest/v2, and whether the request is to a REST v2 route.
+func restV2RoutePath(path string) (string, bool) {
+	for _, prefix := range []string{
+		"/" + evergreen.RestRoutePrefix + "/v2",
+		"/" + evergreen.APIRoutePrefix + "/" + evergreen.RestRoutePrefix + "/v2",
+	} {
+		if path == prefix {
+			return "/", true
+		}
+		if strings.HasPrefix(path, prefix+"/") {
+			return strings.TrimPrefix(path, prefix), true
+		}
+	}
+
+	return "", false
+}
@@ -0,0 +1,112 @@
+package route
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/evergreen-ci/evergreen"
+	"github.com/evergreen-ci/evergreen/model/user"
+	"github.com/evergreen-ci/gimlet"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRateLimitMiddleware(t *testing.T) {
+	config := &evergreen.RateLimitConfig{
+		Default: evergreen.RateLimit{RequestsPerMinute: 60, Burst: 2},
+		RouteGroups: []evergreen.RouteGroupRateLimit{
+			{Name: "hosts", PathPrefixes: []string{"/hosts"}, Limit: evergreen.RateLimit{RequestsPerMinute: 6, Burst: 1}},
+		},
+		ExemptUsers: []string{"robot"},
+	}
+	var configErr error
+	m := &rateLimitMiddleware{
+		getConfig: func() (*evergreen.RateLimitConfig, error) { return config, configErr },
+		buckets:   map[rateLimitKey]*tokenBucket{},
+	}
+
+	serve := func(username, path string) *httptest.ResponseRecorder {
+		r := httptest.NewRequest(http.MethodGet, path, nil)
+		if username != "" {
+			r = r.WithContext(gimlet.AttachUser(r.Context(), &user.DBUser{Id: username}))
+		}
+		rw := httptest.NewRecorder()
+		m.ServeHTTP(rw, r, func(rw http.ResponseWriter, r *http.Request) {
+			rw.WriteHeader(http.StatusOK)
+		})
+		return rw
+	}
+
+	assert.Equal(t, http.StatusOK, serve("me", "/rest/v2/tasks/t1").Code)
+	assert.Equal(t, http.StatusOK, serve("me", "/api/rest/v2/tasks/t1").Code)
+	rw := serve("me", "/rest/v2/tasks/t1")
+	assert.Equal(t, http.StatusTooManyRequests, rw.Code)
+	assert.Equal(t, "1", rw.Header().Get("Retry-After"))
+
+	// route groups and users have separate allowances
+	assert.Equal(t, http.StatusOK, serve("me", "/rest/v2/hosts").Code)
+	rw = serve("me", "/rest/v2/hosts/h1")
+	assert.Equal(t, http.StatusTooManyRequests, rw.Code)
+	assert.Equal(t, "10", rw.Header().Get("Retry-After"))
+	assert.Equal(t, http.StatusOK, serve("you", "/rest/v2/tasks/t1").Code)
+
+	// exempt users, requests without users, and requests to other routes
+	// aren't limited
+	for i := 0; i < 5; i++ {
+		assert.Equal(t, http.StatusOK, serve("robot", "/rest/v2/tasks/t1").Code)
+		assert.Equal(t, http.StatusOK, serve("", "/rest/v2/tasks/t1").Code)
+		assert.Equal(t, http.StatusOK, serve("me", "/task/t1").Code)
+	}
+
+	// if the settings can't be read, the previous ones are used
+	m.refreshed = time.Time{}
+	configErr = errors.New("no database")
+	assert.Equal(t, http.StatusTooManyRequests, serve("me", "/rest/v2/tasks/t1").Code)
+}
+
+func TestRateLimitMiddlewareTake(t *testing.T) {
+	config := &evergreen.RateLimitConfig{
+		Default: evergreen.RateLimit{RequestsPerMinute: 60, Burst: 3},
+	}
+	m := &rateLimitMiddleware{
+		getConfig: func() (*evergreen.RateLimitConfig, error) { return config, nil },
+		buckets:   map[rateLimitKey]*tokenBucket{},
+	}
+
+	now := time.Now()
+	for i := 0; i < 3; i++ {
+		_, limited := m.take("me", "/tasks", now)
+		assert.False(t, limited)
+	}
+	retryAfter, limited := m.take("me", "/tasks", now)
+	assert.True(t, limited)
+	assert.Equal(t, time.Second, retryAfter)
+
+	// the bucket refills at the rate of the limit
+	_, limited = m.take("me", "/tasks", now.Add(1500*time.Millisecond))
+	assert.False(t, limited)
+	retryAfter, limited = m.take("me", "/tasks", now.Add(1500*time.Millisecond))
+	assert.True(t, limited)
+	assert.Equal(t, 500*time.Millisecond, retryAfter)
+
+	// full buckets are dropped when the settings are read again
+	require.Len(t, m.buckets, 1)
+	_, limited = m.take("you", "/tasks", now.Add(2*time.Second))
+	assert.False(t, limited)
+	require.Len(t, m.buckets, 2)
+	_, limited = m.take("you", "/tasks", now.Add(rateLimitConfigTTL+time.Minute))
+	assert.False(t, limited)
+	assert.Len(t, m.buckets, 1)
+
+	// removing the limit stops limiting
+	config = &evergreen.RateLimitConfig{}
+	m.refreshed = time.Time{}
+	for i := 0; i < 10; i++ {
+		_, limited = m.take("me", "/tasks", now.Add(3*time.Minute))
+		assert.False(t, limited)
+	}
+	assert.Empty(t, m.buckets)
+}
@@ -0,0 +1,250 @@
+package route
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/evergreen-ci/evergreen/model/role"
+	"github.com/evergreen-ci/evergreen/rest/data"
+	"github.com/evergreen-ci/evergreen/rest/model"
+	"github.com/evergreen-ci/gimlet"
+	"github.com/pkg/errors"
+)
+
+////////////////////////////////////////////////////////////////////////
+//
+// GET /rest/v2/roles
+
+func makeGetRoles(sc data.Connector) gimlet.RouteHandler {
+	return &rolesGetHandler{
+		sc: sc,
+	}
+}
+
+type rolesGetHandler struct {
+	sc data.Connector
+}
+
+func (h *rolesGetHandler) Factory() gimlet.RouteHandler {
+	return &rolesGetHandler{
+		sc: h.sc,
+	}
+}
+
+func (h *rolesGetHandler) Parse(ctx context.Context, r *http.Request) error {
+	return nil
+}
+
+func (h *rolesGetHandler) Run(ctx context.Context) gimlet.Responder {
+	roles, err := h.sc.GetRoles()
+	if err != nil {
+		return gimlet.MakeJSONErrorResponder(err)
+	}
+
+	return gimlet.NewJSONResponse(roles)
+}
+
+////////////////////////////////////////////////////////////////////////
+//
+// POST /rest/v2/roles
+
+func makeUpdateRole(sc data.Connector) gimlet.RouteHandler {
+	return &rolePostHandler{
+		sc: sc,
+	}
+}
+
+type rolePostHandler struct {
+	role role.Role
+
+	sc data.Connector
+}
+
+func (h *rolePostHandler) Factory() gimlet.RouteHandler {
+	return &rolePostHandler{
+		sc: h.sc,
+	}
+}
+
+func (h *rolePostHandler) Parse(ctx context.Context, r *http.Request) error {
+	apiRole := model.APIRole{}
+	if err := gimlet.GetJSON(r.Body, &apiRole); err != nil {
+		return errors.Wrap(err, "problem parsing request")
+	}
+
+	i, err := apiRole.ToService()
+	if err != nil {
+		return errors.Wrap(err, "problem converting role")
+	}
+	h.role = i.(role.Role)
+
+	return nil
+}
+
+func (h *rolePostHandler) Run(ctx context.Context) gimlet.Responder {
+	if err := h.sc.UpdateRole(h.role); err != nil {
+		return gimlet.MakeJSONErrorResponder(err)
+	}
+
+	apiRole := &model.APIRole{}
+	if err := apiRole.BuildFromService(h.role); err != nil {
+		return gimlet.MakeJSONInternalErrorResponder(err)
+	}
+
+	return gimlet.NewJSONResponse(apiRole)
+}
+
+////////////////////////////////////////////////////////////////////////
+//
+// GET /rest/v2/roles/{role_id}
+
+func makeGetRole(sc data.Connector) gimlet.RouteHandler {
+	return &roleGetHandler{
+		sc: sc,
+	}
+}
+
+type roleGetHandler struct {
+	id string
+
+	sc data.Connector
+}
+
+func (h *roleGetHandler) Factory() gimlet.RouteHandler {
+	return &roleGetHandler{
+		sc: h.sc,
+	}
+}
+
+func (h *roleGetHandler) Parse(ctx context.Context, r *http.Request) error {
+	h.id = gimlet.GetVars(r)["role_id"]
+	return nil
+}
+
+func (h *roleGetHandler) Run(ctx context.Context) gimlet.Responder {
+	apiRole, err := h.sc.GetRole(h.id)
+	if err != nil {
+		return gimlet.MakeJSONErrorResponder(err)
+	}
+
+	return gimlet.NewJSONResponse(apiRole)
+}
+
+////////////////////////////////////////////////////////////////////////
+//
+// DELETE /rest/v2/roles/{role_id}
+
+func makeDeleteRole(sc data.Connector) gimlet.RouteHandler {
+	return &roleDeleteHandler{
+		sc: sc,
+	}
+}
+
+type roleDeleteHandler struct {
+	id string
+
+	sc data.Connector
+}
+
+func (h *roleDeleteHandler) Factory() gimlet.RouteHandler {
+	return &roleDeleteHandler{
+		sc: h.sc,
+	}
+}
+
+func (h *roleDeleteHandler) Parse(ctx context.Context, r *http.Request) error {
+	h.id = gimlet.GetVars(r)["role_id"]
+	return nil
+}
+
+func (h *roleDeleteHandler) Run(ctx context.Context) gimlet.Responder {
+	if err := h.sc.DeleteRole(h.id); err != nil {
+		return gimlet.MakeJSONErrorResponder(err)
+	}
+
+	return gimlet.NewJSONResponse(struct{}{})
+}
+
+////////////////////////////////////////////////////////////////////////
+//
+// GET /rest/v2/users/{user_id}/roles
+
+func makeGetUserRoles(sc data.Connector) gimlet.RouteHandler {
+	return &userRolesGetHandler{
+		sc: sc,
+	}
+}
+
+type userRolesGetHandler struct {
+	userID string
+
+	sc data.Connector
+}
+
+func (h *userRolesGetHandler) Factory() gimlet.RouteHandler {
+	return &userRolesGetHandler{
+		sc: h.sc,
+	}
+}
+
+func (h *userRolesGetHandler) Parse(ctx context.Context, r *http.Request) error {
+	h.userID = gimlet.GetVars(r)["user_id"]
+	return nil
+}
+
+func (h *userRolesGetHandler) Run(ctx context.Context) gimlet.Responder {
+	roles, err := h.sc.GetUserRoles(h.userID)
+	if err != nil {
+		return gimlet.MakeJSONErrorResponder(err)
+	}
+
+	return gimlet.NewJSONResponse(model.APIUserRoles{Roles: roles})
+}
+
+////////////////////////////////////////////////////////////////////////
+//
+// POST /rest/v2/users/{user_id}/roles
+
+func makeUpdateUserRoles(sc data.Connector) gimlet.RouteHandler {
+	return &userRolesPostHandler{
+		sc: sc,
+	}
+}
+
+type userRolesPostHandler struct {
+	userID string
+	Add    []string `json:"add"`
+	Remove []string `json:"remove"`
+
+	sc data.Connector
+}
+
+func (h *userRolesPostHandler) Factory() gimlet.RouteHandler {
+	return &userRolesPostHandler{
+		sc: h.sc,
+	}
+}
+
+func (h *userRolesPostHandler) Parse(ctx context.Context, r *http.Request) error {
+	h.userID = gimlet.GetVars(r)["user_id"]
+	if err := gimlet.GetJSON(r.Body, h); err != nil {
+		return errors.Wrap(err, "problem parsing request")
+	}
+	if len(h.Add) == 0 && len(h.Remove) == 0 {
+		return gimlet.ErrorResponse{
+			StatusCode: http.StatusBadRequest,
+			Message:    "must specify roles to add or remove",
+		}
+	}
+
+	return nil
+}
+
+func (h *userRolesPostHandler) Run(ctx context.Context) gimlet.Responder {
+	roles, err := h.sc.UpdateUserRoles(h.userID, h.Add, h.Remove)
+	if err != nil {
+		return gimlet.MakeJSONErrorResponder(err)
+	}
+
+	return gimlet.NewJSONResponse(model.APIUserRoles{Roles: roles})
+}
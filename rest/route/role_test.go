@@ -0,0 +1,163 @@
+package route
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/evergreen-ci/evergreen/model/role"
+	"github.com/evergreen-ci/evergreen/model/user"
+	"github.com/evergreen-ci/evergreen/rest/data"
+	"github.com/evergreen-ci/evergreen/rest/model"
+	"github.com/evergreen-ci/gimlet"
+	"github.com/stretchr/testify/suite"
+)
+
+type RoleRouteSuite struct {
+	sc  *data.MockConnector
+	ctx context.Context
+
+	suite.Suite
+}
+
+func TestRoleRouteSuite(t *testing.T) {
+	suite.Run(t, new(RoleRouteSuite))
+}
+
+func (s *RoleRouteSuite) SetupTest() {
+	s.sc = &data.MockConnector{
+		MockRoleConnector: data.MockRoleConnector{
+			CachedRoles: map[string]role.Role{
+				role.DefaultProjectRoleID: {
+					ID:           role.DefaultProjectRoleID,
+					ResourceType: role.ResourceTypeProject,
+					Resources:    []string{role.AllResources},
+					Permissions:  []string{role.PermissionView},
+				},
+			},
+			CachedUserRoles: map[string][]string{
+				"me": {},
+			},
+		},
+	}
+	s.ctx = gimlet.AttachUser(context.Background(), &user.DBUser{Id: "admin"})
+}
+
+func (s *RoleRouteSuite) TestCreateAndFetchRole() {
+	body, err := json.Marshal(map[string]interface{}{
+		"id":            "mci-admins",
+		"resource_type": role.ResourceTypeProject,
+		"resources":     []string{"mci"},
+		"permissions":   []string{role.PermissionEditSettings},
+	})
+	s.Require().NoError(err)
+	req, err := http.NewRequest(http.MethodPost, "/roles", bytes.NewBuffer(body))
+	s.Require().NoError(err)
+
+	h := makeUpdateRole(s.sc).Factory()
+	s.Require().NoError(h.Parse(s.ctx, req))
+	resp := h.Run(s.ctx)
+	s.Equal(http.StatusOK, resp.Status())
+	s.Contains(s.sc.CachedRoles, "mci-admins")
+
+	h = makeGetRoles(s.sc).Factory()
+	resp = h.Run(s.ctx)
+	s.Equal(http.StatusOK, resp.Status())
+	roles, ok := resp.Data().([]model.APIRole)
+	s.Require().True(ok)
+	s.Len(roles, 2)
+
+	h = &roleGetHandler{id: "mci-admins", sc: s.sc}
+	resp = h.Run(s.ctx)
+	s.Equal(http.StatusOK, resp.Status())
+	apiRole, ok := resp.Data().(*model.APIRole)
+	s.Require().True(ok)
+	s.Equal("mci-admins", model.FromAPIString(apiRole.ID))
+
+	h = &roleDeleteHandler{id: "mci-admins", sc: s.sc}
+	resp = h.Run(s.ctx)
+	s.Equal(http.StatusOK, resp.Status())
+	s.NotContains(s.sc.CachedRoles, "mci-admins")
+
+	h = &roleGetHandler{id: "mci-admins", sc: s.sc}
+	resp = h.Run(s.ctx)
+	s.Equal(http.StatusNotFound, resp.Status())
+}
+
+func (s *RoleRouteSuite) TestCreateInvalidRole() {
+	body, err := json.Marshal(map[string]interface{}{
+		"id":            "mci-admins",
+		"resource_type": role.ResourceTypeProject,
+		"resources":     []string{"mci"},
+		"permissions":   []string{role.PermissionSpawnHosts},
+	})
+	s.Require().NoError(err)
+	req, err := http.NewRequest(http.MethodPost, "/roles", bytes.NewBuffer(body))
+	s.Require().NoError(err)
+
+	h := makeUpdateRole(s.sc).Factory()
+	s.Require().NoError(h.Parse(s.ctx, req))
+	resp := h.Run(s.ctx)
+	s.Equal(http.StatusBadRequest, resp.Status())
+	s.NotContains(s.sc.CachedRoles, "mci-admins")
+}
+
+func (s *RoleRouteSuite) TestUpdateUserRoles() {
+	body, err := json.Marshal(map[string]interface{}{
+		"add": []string{role.DefaultProjectRoleID},
+	})
+	s.Require().NoError(err)
+	req, err := http.NewRequest(http.MethodPost, "/users/me/roles", bytes.NewBuffer(body))
+	s.Require().NoError(err)
+
+	h := makeUpdateUserRoles(s.sc).Factory()
+	s.Require().NoError(h.Parse(s.ctx, req))
+	h.(*userRolesPostHandler).userID = "me"
+	resp := h.Run(s.ctx)
+	s.Equal(http.StatusOK, resp.Status())
+	s.Equal([]string{role.DefaultProjectRoleID}, s.sc.CachedUserRoles["me"])
+
+	h = &userRolesGetHandler{userID: "me", sc: s.sc}
+	resp = h.Run(s.ctx)
+	s.Equal(http.StatusOK, resp.Status())
+	s.Equal(model.APIUserRoles{Roles: []string{role.DefaultProjectRoleID}}, resp.Data())
+
+	// roles must exist to be added
+	body, err = json.Marshal(map[string]interface{}{
+		"add": []string{"nonexistent"},
+	})
+	s.Require().NoError(err)
+	req, err = http.NewRequest(http.MethodPost, "/users/me/roles", bytes.NewBuffer(body))
+	s.Require().NoError(err)
+	h = makeUpdateUserRoles(s.sc).Factory()
+	s.Require().NoError(h.Parse(s.ctx, req))
+	h.(*userRolesPostHandler).userID = "me"
+	resp = h.Run(s.ctx)
+	s.Equal(http.StatusBadRequest, resp.Status())
+
+	body, err = json.Marshal(map[string]interface{}{
+		"remove": []string{role.DefaultProjectRoleID},
+	})
+	s.Require().NoError(err)
+	req, err = http.NewRequest(http.MethodPost, "/users/me/roles", bytes.NewBuffer(body))
+	s.Require().NoError(err)
+	h = makeUpdateUserRoles(s.sc).Factory()
+	s.Require().NoError(h.Parse(s.ctx, req))
+	h.(*userRolesPostHandler).userID = "me"
+	resp = h.Run(s.ctx)
+	s.Equal(http.StatusOK, resp.Status())
+	s.Empty(s.sc.CachedUserRoles["me"])
+
+	// unknown users aren't found
+	h = &userRolesGetHandler{userID: "you", sc: s.sc}
+	resp = h.Run(s.ctx)
+	s.Equal(http.StatusNotFound, resp.Status())
+
+	// requests must change something
+	req, err = http.NewRequest(http.MethodPost, "/users/me/roles", bytes.NewBuffer([]byte("{}")))
+	s.Require().NoError(err)
+	h = makeUpdateUserRoles(s.sc).Factory()
+	s.Error(h.Parse(s.ctx, req))
+}
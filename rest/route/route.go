@@ -26,6 +26,7 @@ type RouteManager struct {
 // these to the given router.
 func (rm *RouteManager) Register(app *gimlet.APIApp, sc data.Connector) {
 	for _, method := range rm.Methods {
-		app.AddRoute(rm.Route).Version(rm.Version).Handler(makeHandler(method, sc)).Method(method.MethodType)
+		app.AddRoute(rm.Route).Version(rm.Version).Wrap(NewTokenScopeMiddleware(method.tokenScope())).
+			Handler(makeHandler(method, sc)).Method(method.MethodType)
 	}
 }
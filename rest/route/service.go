@@ -1,6 +1,8 @@
 package route
 
 import (
+	"github.com/evergreen-ci/evergreen/model/role"
+	"github.com/evergreen-ci/evergreen/model/user"
 	"github.com/evergreen-ci/evergreen/rest/data"
 	"github.com/evergreen-ci/gimlet"
 	"github.com/mongodb/amboy"
@@ -9,18 +11,18 @@ import (
 // AttachHandler attaches the api's request handlers to the given mux router.
 // It builds a Connector then attaches each of the main functions for
 // the api to the router.
-func AttachHandler(app *gimlet.APIApp, queue amboy.Queue, URL string, superUsers []string, githubSecret []byte) {
+func AttachHandler(app *gimlet.APIApp, queue amboy.Queue, URL string, superUsers []string, githubSecret, gitlabSecret []byte) {
 	sc := &data.DBConnector{}
 
 	sc.SetURL(URL)
 	sc.SetSuperUsers(superUsers)
-	GetHandler(app, sc, queue, githubSecret)
+	GetHandler(app, sc, queue, githubSecret, gitlabSecret)
 }
 
 // GetHandler builds each of the functions that this api implements and then
 // registers them on the given router. It then returns the given router as an
 // http handler which can be given more functions.
-func GetHandler(app *gimlet.APIApp, sc data.Connector, queue amboy.Queue, githubSecret []byte) {
+func GetHandler(app *gimlet.APIApp, sc data.Connector, queue amboy.Queue, githubSecret, gitlabSecret []byte) {
 	routes := map[string]routeManagerFactory{
 		"/cost/distro/{distro_id}":           getCostByDistroIdRouteManager,
 		"/cost/project/{project_id}/tasks":   getCostTaskByProjectRouteManager,
@@ -43,6 +45,7 @@ func GetHandler(app *gimlet.APIApp, sc data.Connector, queue amboy.Queue, github
 		"/status/notifications":                                getNotificationsStatusRouteManager,
 		"/status/recent_tasks":                                 getRecentTasksRouteManager,
 		"/subscriptions":                                       getSubscriptionRouteManager,
+		"/subscriptions/{subscription_id}/rotate_secret":       getSubscriptionRotateSecretRouteManager,
 		"/tasks/{task_id}":                                     getTaskRouteManager,
 		"/tasks/{task_id}/abort":                               getTaskAbortManager,
 		"/tasks/{task_id}/generate":                            getGenerateManager,
@@ -62,32 +65,61 @@ func GetHandler(app *gimlet.APIApp, sc data.Connector, queue amboy.Queue, github
 
 	superUser := gimlet.NewRestrictAccessToUsers(sc.GetSuperUsers())
 	checkUser := gimlet.NewRequireAuthHandler()
+	canRestart := NewProjectPermissionMiddleware(sc, role.PermissionRestart)
+	readOnlyScope := NewTokenScopeMiddleware(user.TokenScopeReadOnly)
+	patchScope := NewTokenScopeMiddleware(user.TokenScopePatch)
+	hostScope := NewTokenScopeMiddleware(user.TokenScopeHost)
+	adminScope := NewTokenScopeMiddleware(user.TokenScopeAdmin)
 
-	app.AddRoute("/").Version(2).Get().RouteHandler(makePlaceHolderManger(sc))
-	app.AddRoute("/admin").Version(2).Get().RouteHandler(makeLegacyAdminConfig(sc))
-	app.AddRoute("/admin/banner").Version(2).Get().Wrap(checkUser).RouteHandler(makeFetchAdminBanner(sc))
-	app.AddRoute("/admin/banner").Version(2).Post().Wrap(superUser).RouteHandler(makeSetAdminBanner(sc))
-	app.AddRoute("/admin/events").Version(2).Get().Wrap(superUser).RouteHandler(makeFetchAdminEvents(sc))
-	app.AddRoute("/admin/revert").Version(2).Post().Wrap(superUser).RouteHandler(makeRevertRouteManager(sc))
-	app.AddRoute("/admin/restart").Version(2).Post().Wrap(superUser).RouteHandler(makeRestartRoute(sc, queue))
-	app.AddRoute("/admin/task_queue").Version(2).Delete().Wrap(superUser).RouteHandler(makeClearTaskQueueHandler(sc))
-	app.AddRoute("/admin/service_flags").Version(2).Post().Wrap(superUser).RouteHandler(makeSetServiceFlagsRouteManager(sc))
-	app.AddRoute("/admin/settings").Version(2).Get().Wrap(superUser).RouteHandler(makeFetchAdminSettings(sc))
-	app.AddRoute("/admin/settings").Version(2).Post().Wrap(superUser).RouteHandler(makeSetAdminSettings(sc))
-	app.AddRoute("/alias/{name}").Version(2).Get().RouteHandler(makeFetchAliases(sc))
-	app.AddRoute("/hosts").Version(2).Get().RouteHandler(makeFetchHosts(sc))
-	app.AddRoute("/hosts").Version(2).Post().Wrap(checkUser).RouteHandler(makeSpawnHostCreateRoute(sc))
-	app.AddRoute("/hosts/{host_id}").Version(2).Get().RouteHandler(makeGetHostByID(sc))
-	app.AddRoute("/hosts/{task_id}/create").Version(2).Post().RouteHandler(makeHostCreateRouteManager(sc))
-	app.AddRoute("/hosts/{task_id}/list").Version(2).Get().RouteHandler(makeHostListRouteManager(sc))
-	app.AddRoute("/builds/{build_id}").Version(2).Get().RouteHandler(makeGetBuildByID(sc))
-	app.AddRoute("/builds/{build_id}").Version(2).Patch().Wrap(checkUser).RouteHandler(makeChangeStatusForBuild(sc))
-	app.AddRoute("/builds/{build_id}/abort").Version(2).Post().Wrap(checkUser).RouteHandler(makeAbortBuild(sc))
-	app.AddRoute("/builds/{build_id}/restart").Version(2).Post().Wrap(checkUser).RouteHandler(makeRestartBuild(sc))
-	app.AddRoute("/builds/{build_id}/tasks").Version(2).Get().Wrap(checkUser).RouteHandler(makeFetchTasksByBuild(sc))
-	app.AddRoute("/users/{user_id}/hosts").Version(2).Get().Wrap(checkUser).RouteHandler(makeFetchHosts(sc))
-	app.AddRoute("/versions/{version_id}").Version(2).Get().RouteHandler(makeGetVersionByID(sc))
-	app.AddRoute("/versions/{version_id}/builds").Version(2).Get().RouteHandler(makeGetVersionByID(sc))
-	app.AddRoute("/patches/{patch_id}").Version(2).Get().RouteHandler(makeFetchPatchByID(sc))
-	app.AddRoute("/patches/{patch_id}").Version(2).Patch().Wrap(checkUser).RouteHandler(makeChangePatchStatus(sc))
+	app.AddRoute("/").Version(2).Get().Wrap(readOnlyScope).RouteHandler(makePlaceHolderManger(sc))
+	app.AddRoute("/admin").Version(2).Get().Wrap(adminScope).RouteHandler(makeLegacyAdminConfig(sc))
+	app.AddRoute("/admin/audit").Version(2).Get().Wrap(adminScope, superUser).RouteHandler(makeFetchAuditLog(sc))
+	app.AddRoute("/admin/banner").Version(2).Get().Wrap(readOnlyScope, checkUser).RouteHandler(makeFetchAdminBanner(sc))
+	app.AddRoute("/admin/banner").Version(2).Post().Wrap(adminScope, superUser).RouteHandler(makeSetAdminBanner(sc))
+	app.AddRoute("/admin/events").Version(2).Get().Wrap(adminScope, superUser).RouteHandler(makeFetchAdminEvents(sc))
+	app.AddRoute("/admin/revert").Version(2).Post().Wrap(adminScope, superUser).RouteHandler(makeRevertRouteManager(sc))
+	app.AddRoute("/admin/restart").Version(2).Post().Wrap(adminScope, superUser).RouteHandler(makeRestartRoute(sc, queue))
+	app.AddRoute("/admin/task_queue").Version(2).Delete().Wrap(adminScope, superUser).RouteHandler(makeClearTaskQueueHandler(sc))
+	app.AddRoute("/admin/service_flags").Version(2).Post().Wrap(adminScope, superUser).RouteHandler(makeSetServiceFlagsRouteManager(sc))
+	app.AddRoute("/admin/settings").Version(2).Get().Wrap(adminScope, superUser).RouteHandler(makeFetchAdminSettings(sc))
+	app.AddRoute("/admin/settings").Version(2).Post().Wrap(adminScope, superUser).RouteHandler(makeSetAdminSettings(sc))
+	app.AddRoute("/admin/service_accounts").Version(2).Get().Wrap(adminScope, superUser).RouteHandler(makeGetServiceAccounts(sc))
+	app.AddRoute("/admin/service_accounts").Version(2).Post().Wrap(adminScope, superUser).RouteHandler(makeCreateServiceAccount(sc))
+	app.AddRoute("/admin/service_accounts/{account_id}").Version(2).Get().Wrap(adminScope, superUser).RouteHandler(makeGetServiceAccount(sc))
+	app.AddRoute("/admin/service_accounts/{account_id}").Version(2).Delete().Wrap(adminScope, superUser).RouteHandler(makeDeleteServiceAccount(sc))
+	app.AddRoute("/admin/service_accounts/{account_id}/tokens").Version(2).Post().Wrap(adminScope, superUser).RouteHandler(makeCreateServiceAccountToken(sc))
+	app.AddRoute("/admin/service_accounts/{account_id}/tokens/{token_name}").Version(2).Delete().Wrap(adminScope, superUser).RouteHandler(makeDeleteServiceAccountToken(sc))
+	app.AddRoute("/alias/{name}").Version(2).Get().Wrap(readOnlyScope).RouteHandler(makeFetchAliases(sc))
+	app.AddRoute("/events/stream").Version(2).Get().Wrap(readOnlyScope, checkUser).RouteHandler(makeEventStreamHandler(sc))
+	app.AddRoute("/graphql").Version(2).Post().Wrap(readOnlyScope, checkUser).RouteHandler(makeGraphQLHandler(sc))
+	app.AddRoute("/hooks/gitlab").Version(2).Post().Wrap(adminScope).RouteHandler(makeGitlabHookHandler(sc, queue, gitlabSecret))
+	app.AddRoute("/hosts").Version(2).Get().Wrap(readOnlyScope).RouteHandler(makeFetchHosts(sc))
+	app.AddRoute("/hosts").Version(2).Post().Wrap(hostScope, checkUser).RouteHandler(makeSpawnHostCreateRoute(sc))
+	app.AddRoute("/hosts/{host_id}").Version(2).Get().Wrap(readOnlyScope).RouteHandler(makeGetHostByID(sc))
+	app.AddRoute("/hosts/{task_id}/create").Version(2).Post().Wrap(adminScope).RouteHandler(makeHostCreateRouteManager(sc))
+	app.AddRoute("/hosts/{task_id}/list").Version(2).Get().Wrap(readOnlyScope).RouteHandler(makeHostListRouteManager(sc))
+	app.AddRoute("/builds/{build_id}").Version(2).Get().Wrap(readOnlyScope).RouteHandler(makeGetBuildByID(sc))
+	app.AddRoute("/builds/{build_id}").Version(2).Patch().Wrap(adminScope, checkUser, canRestart).RouteHandler(makeChangeStatusForBuild(sc))
+	app.AddRoute("/builds/{build_id}/abort").Version(2).Post().Wrap(adminScope, checkUser, canRestart).RouteHandler(makeAbortBuild(sc))
+	app.AddRoute("/builds/{build_id}/restart").Version(2).Post().Wrap(adminScope, checkUser, canRestart).RouteHandler(makeRestartBuild(sc))
+	app.AddRoute("/builds/{build_id}/tasks").Version(2).Get().Wrap(readOnlyScope, checkUser).RouteHandler(makeFetchTasksByBuild(sc))
+	app.AddRoute("/tasks/bulk").Version(2).Post().Wrap(adminScope, checkUser).RouteHandler(makeBulkTaskOperation(sc, queue))
+	app.AddRoute("/tasks/bulk/{job_id}").Version(2).Get().Wrap(readOnlyScope, checkUser).RouteHandler(makeFetchBulkTaskOperation(sc))
+	app.AddRoute("/roles").Version(2).Get().Wrap(adminScope, superUser).RouteHandler(makeGetRoles(sc))
+	app.AddRoute("/roles").Version(2).Post().Wrap(adminScope, superUser).RouteHandler(makeUpdateRole(sc))
+	app.AddRoute("/roles/{role_id}").Version(2).Get().Wrap(adminScope, superUser).RouteHandler(makeGetRole(sc))
+	app.AddRoute("/roles/{role_id}").Version(2).Delete().Wrap(adminScope, superUser).RouteHandler(makeDeleteRole(sc))
+	app.AddRoute("/user/tokens").Version(2).Get().Wrap(readOnlyScope, checkUser).RouteHandler(makeFetchUserTokens(sc))
+	app.AddRoute("/user/tokens").Version(2).Post().Wrap(adminScope, checkUser).RouteHandler(makeCreateUserToken(sc))
+	app.AddRoute("/user/tokens/{token_name}").Version(2).Delete().Wrap(adminScope, checkUser).RouteHandler(makeDeleteUserToken(sc))
+	app.AddRoute("/users/{user_id}/hosts").Version(2).Get().Wrap(readOnlyScope, checkUser).RouteHandler(makeFetchHosts(sc))
+	app.AddRoute("/users/{user_id}/roles").Version(2).Get().Wrap(adminScope, superUser).RouteHandler(makeGetUserRoles(sc))
+	app.AddRoute("/users/{user_id}/roles").Version(2).Post().Wrap(adminScope, superUser).RouteHandler(makeUpdateUserRoles(sc))
+	app.AddRoute("/versions/{version_id}").Version(2).Get().Wrap(readOnlyScope).RouteHandler(makeGetVersionByID(sc))
+	app.AddRoute("/versions/{version_id}/builds").Version(2).Get().Wrap(readOnlyScope).RouteHandler(makeGetVersionByID(sc))
+	app.AddRoute("/versions/{version_id}/downstream").Version(2).Get().Wrap(readOnlyScope).RouteHandler(makeGetDownstreamVersions(sc))
+	app.AddRoute("/openapi.json").Version(2).Get().Wrap(readOnlyScope).RouteHandler(makeFetchOpenAPISpec())
+	app.AddRoute("/patches/{patch_id}").Version(2).Get().Wrap(readOnlyScope).RouteHandler(makeFetchPatchByID(sc))
+	app.AddRoute("/patches/{patch_id}").Version(2).Patch().Wrap(patchScope, checkUser, canRestart).RouteHandler(makeChangePatchStatus(sc))
+	app.AddRoute("/patches/{patch_id}/rebase").Version(2).Post().Wrap(patchScope, checkUser).RouteHandler(makeRebasePatch(sc))
 }
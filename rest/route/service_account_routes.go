@@ -0,0 +1,310 @@
+package route
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/evergreen-ci/evergreen/model/audit"
+	"github.com/evergreen-ci/evergreen/model/user"
+	"github.com/evergreen-ci/evergreen/rest/data"
+	"github.com/evergreen-ci/evergreen/rest/model"
+	"github.com/evergreen-ci/gimlet"
+	"github.com/pkg/errors"
+)
+
+const (
+	serviceAccountResourceType      = "service_account"
+	serviceAccountTokenResourceType = "service_account_token"
+)
+
+// serviceAccountTokenID identifies one of a service account's tokens in the
+// audit log.
+func serviceAccountTokenID(accountID, name string) string {
+	return accountID + "/" + name
+}
+
+////////////////////////////////////////////////////////////////////////
+//
+// GET /rest/v2/admin/service_accounts
+
+func makeGetServiceAccounts(sc data.Connector) gimlet.RouteHandler {
+	return &serviceAccountsGetHandler{
+		sc: sc,
+	}
+}
+
+type serviceAccountsGetHandler struct {
+	sc data.Connector
+}
+
+func (h *serviceAccountsGetHandler) Factory() gimlet.RouteHandler {
+	return &serviceAccountsGetHandler{
+		sc: h.sc,
+	}
+}
+
+func (h *serviceAccountsGetHandler) Parse(ctx context.Context, r *http.Request) error {
+	return nil
+}
+
+func (h *serviceAccountsGetHandler) Run(ctx context.Context) gimlet.Responder {
+	accounts, err := h.sc.GetServiceAccounts()
+	if err != nil {
+		return gimlet.MakeJSONErrorResponder(err)
+	}
+
+	apiAccounts := make([]model.APIServiceAccount, len(accounts))
+	for i := range accounts {
+		if err = apiAccounts[i].BuildFromService(accounts[i]); err != nil {
+			return gimlet.MakeJSONInternalErrorResponder(err)
+		}
+	}
+
+	return gimlet.NewJSONResponse(apiAccounts)
+}
+
+////////////////////////////////////////////////////////////////////////
+//
+// POST /rest/v2/admin/service_accounts
+
+func makeCreateServiceAccount(sc data.Connector) gimlet.RouteHandler {
+	return &serviceAccountPostHandler{
+		sc: sc,
+	}
+}
+
+type serviceAccountPostHandler struct {
+	account user.DBUser
+
+	sc data.Connector
+}
+
+func (h *serviceAccountPostHandler) Factory() gimlet.RouteHandler {
+	return &serviceAccountPostHandler{
+		sc: h.sc,
+	}
+}
+
+func (h *serviceAccountPostHandler) Parse(ctx context.Context, r *http.Request) error {
+	apiAccount := model.APIServiceAccount{}
+	if err := gimlet.GetJSON(r.Body, &apiAccount); err != nil {
+		return errors.Wrap(err, "problem parsing request")
+	}
+
+	i, err := apiAccount.ToService()
+	if err != nil {
+		return errors.Wrap(err, "problem converting service account")
+	}
+	h.account = i.(user.DBUser)
+
+	return nil
+}
+
+func (h *serviceAccountPostHandler) Run(ctx context.Context) gimlet.Responder {
+	u, err := h.sc.CreateServiceAccount(h.account.Id, h.account.DispName, h.account.SystemRoles)
+	if err != nil {
+		return gimlet.MakeJSONErrorResponder(err)
+	}
+	audit.SetAction(ctx, serviceAccountResourceType, u.Id, "create")
+	audit.RecordChange(ctx, serviceAccountResourceType, u.Id, nil, u)
+
+	apiAccount := &model.APIServiceAccount{}
+	if err = apiAccount.BuildFromService(u); err != nil {
+		return gimlet.MakeJSONInternalErrorResponder(err)
+	}
+
+	return gimlet.NewJSONResponse(apiAccount)
+}
+
+////////////////////////////////////////////////////////////////////////
+//
+// GET /rest/v2/admin/service_accounts/{account_id}
+
+func makeGetServiceAccount(sc data.Connector) gimlet.RouteHandler {
+	return &serviceAccountGetHandler{
+		sc: sc,
+	}
+}
+
+type serviceAccountGetHandler struct {
+	id string
+
+	sc data.Connector
+}
+
+func (h *serviceAccountGetHandler) Factory() gimlet.RouteHandler {
+	return &serviceAccountGetHandler{
+		sc: h.sc,
+	}
+}
+
+func (h *serviceAccountGetHandler) Parse(ctx context.Context, r *http.Request) error {
+	h.id = gimlet.GetVars(r)["account_id"]
+	return nil
+}
+
+func (h *serviceAccountGetHandler) Run(ctx context.Context) gimlet.Responder {
+	u, err := h.sc.GetServiceAccount(h.id)
+	if err != nil {
+		return gimlet.MakeJSONErrorResponder(err)
+	}
+
+	apiAccount := &model.APIServiceAccount{}
+	if err = apiAccount.BuildFromService(u); err != nil {
+		return gimlet.MakeJSONInternalErrorResponder(err)
+	}
+
+	return gimlet.NewJSONResponse(apiAccount)
+}
+
+////////////////////////////////////////////////////////////////////////
+//
+// DELETE /rest/v2/admin/service_accounts/{account_id}
+
+func makeDeleteServiceAccount(sc data.Connector) gimlet.RouteHandler {
+	return &serviceAccountDeleteHandler{
+		sc: sc,
+	}
+}
+
+type serviceAccountDeleteHandler struct {
+	id string
+
+	sc data.Connector
+}
+
+func (h *serviceAccountDeleteHandler) Factory() gimlet.RouteHandler {
+	return &serviceAccountDeleteHandler{
+		sc: h.sc,
+	}
+}
+
+func (h *serviceAccountDeleteHandler) Parse(ctx context.Context, r *http.Request) error {
+	h.id = gimlet.GetVars(r)["account_id"]
+	return nil
+}
+
+func (h *serviceAccountDeleteHandler) Run(ctx context.Context) gimlet.Responder {
+	u, err := h.sc.GetServiceAccount(h.id)
+	if err != nil {
+		return gimlet.MakeJSONErrorResponder(err)
+	}
+	if err = h.sc.DeleteServiceAccount(h.id); err != nil {
+		return gimlet.MakeJSONErrorResponder(err)
+	}
+	audit.SetAction(ctx, serviceAccountResourceType, h.id, "delete")
+	audit.RecordChange(ctx, serviceAccountResourceType, h.id, u, nil)
+
+	return gimlet.NewJSONResponse(struct{}{})
+}
+
+////////////////////////////////////////////////////////////////////////
+//
+// POST /rest/v2/admin/service_accounts/{account_id}/tokens
+
+func makeCreateServiceAccountToken(sc data.Connector) gimlet.RouteHandler {
+	return &serviceAccountTokenPostHandler{
+		sc: sc,
+	}
+}
+
+type serviceAccountTokenPostHandler struct {
+	id    string
+	token user.APIToken
+
+	sc data.Connector
+}
+
+func (h *serviceAccountTokenPostHandler) Factory() gimlet.RouteHandler {
+	return &serviceAccountTokenPostHandler{
+		sc: h.sc,
+	}
+}
+
+func (h *serviceAccountTokenPostHandler) Parse(ctx context.Context, r *http.Request) error {
+	h.id = gimlet.GetVars(r)["account_id"]
+
+	apiToken := model.APIToken{}
+	if err := gimlet.GetJSON(r.Body, &apiToken); err != nil {
+		return errors.Wrap(err, "problem parsing request")
+	}
+
+	i, err := apiToken.ToService()
+	if err != nil {
+		return errors.Wrap(err, "problem converting token")
+	}
+	h.token = i.(user.APIToken)
+
+	return nil
+}
+
+func (h *serviceAccountTokenPostHandler) Run(ctx context.Context) gimlet.Responder {
+	token, err := h.sc.AddServiceAccountToken(h.id, h.token.Name, h.token.Scopes, h.token.ExpiresAt)
+	if err != nil {
+		return gimlet.MakeJSONErrorResponder(err)
+	}
+
+	u, err := h.sc.GetServiceAccount(h.id)
+	if err != nil {
+		return gimlet.MakeJSONErrorResponder(err)
+	}
+	audit.SetAction(ctx, serviceAccountResourceType, h.id, "create_token")
+	audit.RecordChange(ctx, serviceAccountTokenResourceType, serviceAccountTokenID(h.id, h.token.Name), nil, u.GetAPIToken(h.token.Name), user.APITokenHashKey)
+
+	apiToken := &model.APIToken{}
+	if err = apiToken.BuildFromService(u.GetAPIToken(h.token.Name)); err != nil {
+		return gimlet.MakeJSONInternalErrorResponder(err)
+	}
+	apiToken.Token = model.ToAPIString(token)
+
+	return gimlet.NewJSONResponse(apiToken)
+}
+
+////////////////////////////////////////////////////////////////////////
+//
+// DELETE /rest/v2/admin/service_accounts/{account_id}/tokens/{token_name}
+
+func makeDeleteServiceAccountToken(sc data.Connector) gimlet.RouteHandler {
+	return &serviceAccountTokenDeleteHandler{
+		sc: sc,
+	}
+}
+
+type serviceAccountTokenDeleteHandler struct {
+	id   string
+	name string
+
+	sc data.Connector
+}
+
+func (h *serviceAccountTokenDeleteHandler) Factory() gimlet.RouteHandler {
+	return &serviceAccountTokenDeleteHandler{
+		sc: h.sc,
+	}
+}
+
+func (h *serviceAccountTokenDeleteHandler) Parse(ctx context.Context, r *http.Request) error {
+	vars := gimlet.GetVars(r)
+	h.id = vars["account_id"]
+	h.name = vars["token_name"]
+	return nil
+}
+
+func (h *serviceAccountTokenDeleteHandler) Run(ctx context.Context) gimlet.Responder {
+	u, err := h.sc.GetServiceAccount(h.id)
+	if err != nil {
+		return gimlet.MakeJSONErrorResponder(err)
+	}
+	var token *user.APIToken
+	if existing := u.GetAPIToken(h.name); existing != nil {
+		copied := *existing
+		token = &copied
+	}
+	if err = h.sc.DeleteServiceAccountToken(h.id, h.name); err != nil {
+		return gimlet.MakeJSONErrorResponder(err)
+	}
+	audit.SetAction(ctx, serviceAccountResourceType, h.id, "delete_token")
+	audit.RecordChange(ctx, serviceAccountTokenResourceType, serviceAccountTokenID(h.id, h.name), token, nil, user.APITokenHashKey)
+
+	return gimlet.NewJSONResponse(struct{}{})
+}
@@ -0,0 +1,160 @@
+package route
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/evergreen-ci/evergreen/model/audit"
+	"github.com/evergreen-ci/evergreen/model/user"
+	"github.com/evergreen-ci/evergreen/rest/data"
+	"github.com/evergreen-ci/evergreen/rest/model"
+	"github.com/stretchr/testify/suite"
+)
+
+type ServiceAccountRouteSuite struct {
+	sc  *data.MockConnector
+	ctx context.Context
+
+	suite.Suite
+}
+
+func TestServiceAccountRouteSuite(t *testing.T) {
+	suite.Run(t, new(ServiceAccountRouteSuite))
+}
+
+func (s *ServiceAccountRouteSuite) SetupTest() {
+	s.sc = &data.MockConnector{}
+	s.ctx = context.Background()
+}
+
+func (s *ServiceAccountRouteSuite) create(id string, roles ...string) int {
+	apiRoles := []model.APIString{}
+	for _, r := range roles {
+		apiRoles = append(apiRoles, model.ToAPIString(r))
+	}
+	body, err := json.Marshal(model.APIServiceAccount{
+		ID:          model.ToAPIString(id),
+		DisplayName: model.ToAPIString("CI"),
+		Roles:       apiRoles,
+	})
+	s.Require().NoError(err)
+
+	h := makeCreateServiceAccount(s.sc).Factory()
+	r, err := http.NewRequest(http.MethodPost, "/admin/service_accounts", bytes.NewBuffer(body))
+	s.Require().NoError(err)
+	s.Require().NoError(h.Parse(s.ctx, r))
+	return h.Run(s.ctx).Status()
+}
+
+func (s *ServiceAccountRouteSuite) TestCreateAndDelete() {
+	s.Equal(http.StatusOK, s.create("svc-ci", "patchers"))
+	s.Equal(http.StatusBadRequest, s.create("svc-ci"))
+	s.Equal(http.StatusBadRequest, s.create("ci"))
+
+	resp := makeGetServiceAccounts(s.sc).Run(s.ctx)
+	s.Require().Equal(http.StatusOK, resp.Status())
+	accounts, ok := resp.Data().([]model.APIServiceAccount)
+	s.Require().True(ok)
+	s.Require().Len(accounts, 1)
+	s.Equal("svc-ci", model.FromAPIString(accounts[0].ID))
+	s.Equal("patchers", model.FromAPIString(accounts[0].Roles[0]))
+
+	get := &serviceAccountGetHandler{sc: s.sc, id: "svc-ci"}
+	s.Equal(http.StatusOK, get.Run(s.ctx).Status())
+
+	del := &serviceAccountDeleteHandler{sc: s.sc, id: "svc-ci"}
+	s.Equal(http.StatusOK, del.Run(s.ctx).Status())
+	s.Equal(http.StatusNotFound, del.Run(s.ctx).Status())
+	s.Equal(http.StatusNotFound, get.Run(s.ctx).Status())
+}
+
+func (s *ServiceAccountRouteSuite) TestTokens() {
+	s.Require().Equal(http.StatusOK, s.create("svc-ci"))
+
+	body, err := json.Marshal(model.APIToken{
+		Name:      model.ToAPIString("deploy"),
+		Scopes:    []model.APIString{model.ToAPIString(user.TokenScopePatch)},
+		ExpiresAt: model.NewTime(time.Now().Add(time.Hour)),
+	})
+	s.Require().NoError(err)
+	h := &serviceAccountTokenPostHandler{sc: s.sc}
+	r, err := http.NewRequest(http.MethodPost, "/admin/service_accounts/svc-ci/tokens", bytes.NewBuffer(body))
+	s.Require().NoError(err)
+	s.Require().NoError(h.Parse(s.ctx, r))
+	h.id = "svc-ci"
+	resp := h.Run(s.ctx)
+	s.Require().Equal(http.StatusOK, resp.Status())
+	token, ok := resp.Data().(*model.APIToken)
+	s.Require().True(ok)
+	s.Equal("deploy", model.FromAPIString(token.Name))
+
+	account, err := s.sc.GetServiceAccount("svc-ci")
+	s.Require().NoError(err)
+	s.NotNil(account.FindAPIToken(model.FromAPIString(token.Token)))
+
+	h.id = "svc-other"
+	s.Equal(http.StatusNotFound, h.Run(s.ctx).Status())
+
+	del := &serviceAccountTokenDeleteHandler{sc: s.sc, id: "svc-ci", name: "deploy"}
+	s.Equal(http.StatusOK, del.Run(s.ctx).Status())
+	s.Empty(account.APITokens)
+	s.Equal(http.StatusNotFound, del.Run(s.ctx).Status())
+}
+
+func (s *ServiceAccountRouteSuite) TestChangesAreAudited() {
+	newEntry := func() *audit.Entry {
+		r := httptest.NewRequest(http.MethodPost, "/rest/v2/admin/service_accounts", nil)
+		entry := audit.NewEntry(r, "root", nil)
+		s.ctx = audit.WithEntry(context.Background(), entry)
+		return entry
+	}
+
+	entry := newEntry()
+	s.Require().Equal(http.StatusOK, s.create("svc-ci"))
+	s.Equal("root", entry.Actor)
+	s.Equal(serviceAccountResourceType, entry.ResourceType)
+	s.Equal("svc-ci", entry.ResourceID)
+	s.Equal("create", entry.Action)
+	s.NotEmpty(entry.Changes)
+
+	entry = newEntry()
+	body, err := json.Marshal(model.APIToken{
+		Name:      model.ToAPIString("deploy"),
+		Scopes:    []model.APIString{model.ToAPIString(user.TokenScopePatch)},
+		ExpiresAt: model.NewTime(time.Now().Add(time.Hour)),
+	})
+	s.Require().NoError(err)
+	h := &serviceAccountTokenPostHandler{sc: s.sc}
+	r, err := http.NewRequest(http.MethodPost, "/admin/service_accounts/svc-ci/tokens", bytes.NewBuffer(body))
+	s.Require().NoError(err)
+	s.Require().NoError(h.Parse(s.ctx, r))
+	h.id = "svc-ci"
+	s.Require().Equal(http.StatusOK, h.Run(s.ctx).Status())
+	s.Equal("create_token", entry.Action)
+	s.Require().NotEmpty(entry.Changes)
+	for _, change := range entry.Changes {
+		s.Equal(serviceAccountTokenResourceType, change.ResourceType)
+		s.Equal("svc-ci/deploy", change.ResourceID)
+		if change.Field == user.APITokenHashKey {
+			s.Equal(audit.RedactedValue, change.After)
+		}
+	}
+
+	entry = newEntry()
+	del := &serviceAccountTokenDeleteHandler{sc: s.sc, id: "svc-ci", name: "deploy"}
+	s.Require().Equal(http.StatusOK, del.Run(s.ctx).Status())
+	s.Equal("delete_token", entry.Action)
+	s.NotEmpty(entry.Changes)
+
+	entry = newEntry()
+	delAccount := &serviceAccountDeleteHandler{sc: s.sc, id: "svc-ci"}
+	s.Require().Equal(http.StatusOK, delAccount.Run(s.ctx).Status())
+	s.Equal("delete", entry.Action)
+	s.Equal("svc-ci", entry.ResourceID)
+	s.NotEmpty(entry.Changes)
+}
@@ -4,12 +4,18 @@ import (
 	"context"
 	"fmt"
 	"net/http"
+	"time"
 
+	"github.com/evergreen-ci/evergreen/auth"
+	dbModel "github.com/evergreen-ci/evergreen/model"
+	"github.com/evergreen-ci/evergreen/model/audit"
 	"github.com/evergreen-ci/evergreen/model/event"
+	"github.com/evergreen-ci/evergreen/model/role"
 	"github.com/evergreen-ci/evergreen/rest/data"
 	"github.com/evergreen-ci/evergreen/rest/model"
 	"github.com/evergreen-ci/evergreen/util"
 	"github.com/evergreen-ci/gimlet"
+	"github.com/pkg/errors"
 )
 
 func getSubscriptionRouteManager(route string, version int) *RouteManager {
@@ -83,6 +89,16 @@ func (s *subscriptionPostHandler) ParseAndValidate(ctx context.Context, r *http.
 			}
 		}
 
+		if dbSubscription.OwnerType == event.OwnerTypeDistro {
+			if dbSubscription.Owner == "" {
+				return gimlet.ErrorResponse{
+					StatusCode: http.StatusBadRequest,
+					Message:    "Distro subscriptions must specify the distro as the owner",
+				}
+			}
+			dbSubscription.Selectors = addDistroSelector(dbSubscription.Selectors, dbSubscription.Owner)
+		}
+
 		if ok, msg := isSubscriptionAllowed(dbSubscription); !ok {
 			return gimlet.ErrorResponse{
 				StatusCode: http.StatusBadRequest,
@@ -132,6 +148,22 @@ func isSubscriptionAllowed(sub event.Subscription) (bool, string) {
 	return true, ""
 }
 
+// addDistroSelector restricts a distro-owned subscription to events for
+// hosts of that distro.
+func addDistroSelector(selectors []event.Selector, distroID string) []event.Selector {
+	out := []event.Selector{}
+	for _, selector := range selectors {
+		if selector.Type != "distro" {
+			out = append(out, selector)
+		}
+	}
+
+	return append(out, event.Selector{
+		Type: "distro",
+		Data: distroID,
+	})
+}
+
 func validateSelectors(subscriber event.Subscriber, selectors []event.Selector) (bool, string) {
 	for i := range selectors {
 		if len(selectors[i].Type) == 0 || len(selectors[i].Data) == 0 {
@@ -143,10 +175,36 @@ func validateSelectors(subscriber event.Subscriber, selectors []event.Selector)
 }
 
 func (s *subscriptionPostHandler) Execute(ctx context.Context, sc data.Connector) (ResponseData, error) {
+	for _, subscription := range s.dbSubscriptions {
+		if subscription.OwnerType != event.OwnerTypeDistro {
+			continue
+		}
+		if err := checkDistroSubscriptionPermission(sc, MustHaveUser(ctx), subscription.Owner); err != nil {
+			return ResponseData{}, err
+		}
+	}
+
+	previous := make([]*event.Subscription, len(s.dbSubscriptions))
+	if audit.GetEntry(ctx) != nil {
+		for i, subscription := range s.dbSubscriptions {
+			if subscription.ID == "" {
+				continue
+			}
+			existing, err := event.FindSubscriptionByID(subscription.ID)
+			if err != nil {
+				return ResponseData{}, errors.Wrapf(err, "problem finding subscription '%s'", subscription.ID)
+			}
+			previous[i] = existing
+		}
+	}
+
 	err := sc.SaveSubscriptions(s.dbSubscriptions)
 	if err != nil {
 		return ResponseData{}, err
 	}
+	for i, subscription := range s.dbSubscriptions {
+		audit.RecordChange(ctx, "subscription", subscription.ID, previous[i], subscription)
+	}
 
 	return ResponseData{}, nil
 }
@@ -203,7 +261,9 @@ func (s *subscriptionGetHandler) Execute(_ context.Context, sc data.Connector) (
 }
 
 type subscriptionDeleteHandler struct {
-	id string
+	id           string
+	ownerType    event.OwnerType
+	subscription *event.Subscription
 }
 
 func (s *subscriptionDeleteHandler) Handler() RequestHandler {
@@ -233,7 +293,9 @@ func (s *subscriptionDeleteHandler) ParseAndValidate(ctx context.Context, r *htt
 			Message:    "Subscription not found",
 		}
 	}
-	if subscription.Owner != u.Username() {
+	s.ownerType = subscription.OwnerType
+	s.subscription = subscription
+	if s.ownerType != event.OwnerTypeDistro && subscription.Owner != u.Username() {
 		return gimlet.ErrorResponse{
 			StatusCode: http.StatusUnauthorized,
 			Message:    "Cannot delete subscriptions for someone other than yourself",
@@ -243,11 +305,147 @@ func (s *subscriptionDeleteHandler) ParseAndValidate(ctx context.Context, r *htt
 	return nil
 }
 
-func (s *subscriptionDeleteHandler) Execute(_ context.Context, sc data.Connector) (ResponseData, error) {
+func (s *subscriptionDeleteHandler) Execute(ctx context.Context, sc data.Connector) (ResponseData, error) {
+	if s.ownerType == event.OwnerTypeDistro {
+		if err := checkDistroSubscriptionPermission(sc, MustHaveUser(ctx), s.subscription.Owner); err != nil {
+			return ResponseData{}, err
+		}
+	}
+
 	err := sc.DeleteSubscription(s.id)
 	if err != nil {
 		return ResponseData{}, err
 	}
+	audit.RecordChange(ctx, "subscription", s.id, s.subscription, nil)
 
 	return ResponseData{}, nil
 }
+
+// checkDistroSubscriptionPermission returns an error if the user can't edit
+// the settings of the distro, and so can't change its subscriptions.
+func checkDistroSubscriptionPermission(sc data.Connector, u gimlet.User, distroID string) error {
+	ok, err := auth.HasDistroPermission(sc.GetSuperUsers(), u, distroID, role.PermissionEditSettings)
+	if err != nil {
+		return errors.Wrap(err, "problem checking permissions")
+	}
+	if !ok {
+		return gimlet.ErrorResponse{
+			StatusCode: http.StatusUnauthorized,
+			Message:    fmt.Sprintf("not authorized to change subscriptions of distro '%s'", distroID),
+		}
+	}
+	return nil
+}
+
+// defaultWebhookSecretGracePeriod is how long a rotated webhook secret
+// remains valid if the request doesn't specify a grace period.
+const defaultWebhookSecretGracePeriod = 24 * time.Hour
+
+func getSubscriptionRotateSecretRouteManager(route string, version int) *RouteManager {
+	return &RouteManager{
+		Route:   route,
+		Version: version,
+		Methods: []MethodHandler{
+			{
+				MethodType:     http.MethodPost,
+				Authenticator:  &RequireUserAuthenticator{},
+				RequestHandler: &subscriptionRotateSecretHandler{},
+			},
+		},
+	}
+}
+
+type subscriptionRotateSecretHandler struct {
+	GracePeriodSecs *int `json:"grace_period_secs"`
+
+	id           string
+	subscription *event.Subscription
+}
+
+func (s *subscriptionRotateSecretHandler) Handler() RequestHandler {
+	return &subscriptionRotateSecretHandler{}
+}
+
+func (s *subscriptionRotateSecretHandler) ParseAndValidate(ctx context.Context, r *http.Request) error {
+	if r.Body != nil && r.ContentLength != 0 {
+		if err := util.ReadJSONInto(util.NewRequestReader(r), s); err != nil {
+			return gimlet.ErrorResponse{
+				StatusCode: http.StatusBadRequest,
+				Message:    "Error parsing request body: " + err.Error(),
+			}
+		}
+	}
+	if s.GracePeriodSecs != nil && *s.GracePeriodSecs < 0 {
+		return gimlet.ErrorResponse{
+			StatusCode: http.StatusBadRequest,
+			Message:    "Grace period cannot be negative",
+		}
+	}
+
+	s.id = gimlet.GetVars(r)["subscription_id"]
+	subscription, err := event.FindSubscriptionByID(s.id)
+	if err != nil {
+		return gimlet.ErrorResponse{
+			StatusCode: http.StatusInternalServerError,
+			Message:    err.Error(),
+		}
+	}
+	if subscription == nil {
+		return gimlet.ErrorResponse{
+			StatusCode: http.StatusNotFound,
+			Message:    "Subscription not found",
+		}
+	}
+	if subscription.Subscriber.Type != event.EvergreenWebhookSubscriberType {
+		return gimlet.ErrorResponse{
+			StatusCode: http.StatusBadRequest,
+			Message:    "Only webhook subscriptions have a secret",
+		}
+	}
+	s.subscription = subscription
+
+	return nil
+}
+
+func (s *subscriptionRotateSecretHandler) Execute(ctx context.Context, sc data.Connector) (ResponseData, error) {
+	u := MustHaveUser(ctx)
+
+	allowed := auth.IsSuperUser(sc.GetSuperUsers(), u)
+	var err error
+	switch s.subscription.OwnerType {
+	case event.OwnerTypePerson:
+		allowed = allowed || s.subscription.Owner == u.Username()
+	case event.OwnerTypeProject:
+		var projectRef *dbModel.ProjectRef
+		projectRef, err = sc.FindProjectByBranch(s.subscription.Owner)
+		if err != nil {
+			return ResponseData{}, err
+		}
+		allowed, err = auth.HasProjectPermission(sc.GetSuperUsers(), u, projectRef, role.PermissionEditSettings)
+	case event.OwnerTypeDistro:
+		allowed, err = auth.HasDistroPermission(sc.GetSuperUsers(), u, s.subscription.Owner, role.PermissionEditSettings)
+	}
+	if err != nil {
+		return ResponseData{}, errors.Wrap(err, "problem checking permissions")
+	}
+	if !allowed {
+		return ResponseData{}, gimlet.ErrorResponse{
+			StatusCode: http.StatusUnauthorized,
+			Message:    "Not authorized to rotate the secret of this subscription",
+		}
+	}
+
+	grace := defaultWebhookSecretGracePeriod
+	if s.GracePeriodSecs != nil {
+		grace = time.Duration(*s.GracePeriodSecs) * time.Second
+	}
+
+	subscription, err := sc.RotateWebhookSecret(s.id, grace)
+	if err != nil {
+		return ResponseData{}, err
+	}
+
+	return ResponseData{
+		Result: []model.Model{subscription},
+	}, nil
+}
@@ -10,6 +10,7 @@ import (
 
 	"github.com/evergreen-ci/evergreen/db"
 	"github.com/evergreen-ci/evergreen/model/event"
+	"github.com/evergreen-ci/evergreen/model/role"
 	"github.com/evergreen-ci/evergreen/model/user"
 	"github.com/evergreen-ci/evergreen/rest/data"
 	"github.com/evergreen-ci/evergreen/rest/model"
@@ -45,7 +46,8 @@ func (s *SubscriptionRouteSuite) SetupSuite() {
 }
 
 func (s *SubscriptionRouteSuite) SetupTest() {
-	s.NoError(db.ClearCollections(event.SubscriptionsCollection))
+	s.NoError(db.ClearCollections(event.SubscriptionsCollection, role.Collection))
+	s.sc.SetSuperUsers([]string{"root"})
 }
 
 func (s *SubscriptionRouteSuite) TestSubscriptionPost() {
@@ -517,3 +519,95 @@ func (s *SubscriptionRouteSuite) TestAcceptSubscriptionWithOnlyRegexSelectors()
 	s.NoError(err)
 	s.NoError(s.postHandler.RequestHandler.ParseAndValidate(ctx, request))
 }
+
+func (s *SubscriptionRouteSuite) TestRotateWebhookSecret() {
+	s.NoError(db.Clear(event.SubscriptionsCollection))
+	ctx := gimlet.AttachUser(context.Background(), &user.DBUser{Id: "me"})
+
+	subscription := event.Subscription{
+		ID:        "5949645c9acd9604fdd202da",
+		Type:      "atype",
+		Trigger:   "atrigger",
+		Owner:     "me",
+		OwnerType: event.OwnerTypePerson,
+		Selectors: []event.Selector{{Type: "seltype", Data: "seldata"}},
+		Subscriber: event.Subscriber{
+			Type: event.EvergreenWebhookSubscriberType,
+			Target: &event.WebhookSubscriber{
+				URL:    "https://example.com",
+				Secret: []byte("original"),
+			},
+		},
+	}
+	s.NoError(subscription.Upsert())
+
+	h := &subscriptionRotateSecretHandler{}
+	r, err := http.NewRequest(http.MethodPost, "/subscriptions/5949645c9acd9704fdd202da/rotate_secret", nil)
+	s.NoError(err)
+	s.EqualError(h.ParseAndValidate(ctx, r), "404 (Not Found): Subscription not found")
+
+	h = &subscriptionRotateSecretHandler{
+		id:           subscription.ID,
+		subscription: &subscription,
+	}
+	otherCtx := gimlet.AttachUser(context.Background(), &user.DBUser{Id: "thanos"})
+	_, err = h.Execute(otherCtx, s.sc)
+	s.EqualError(err, "401 (Unauthorized): Not authorized to rotate the secret of this subscription")
+
+	resp, err := h.Execute(ctx, s.sc)
+	s.NoError(err)
+	s.Require().Len(resp.Result, 1)
+	apiSub := resp.Result[0].(*model.APISubscription)
+	target := apiSub.Subscriber.Target.(model.APIWebhookSubscriber)
+	s.Equal(1, target.SecretVersion)
+	s.NotEqual("original", model.FromAPIString(target.Secret))
+
+	dbSub, err := event.FindSubscriptionByID(subscription.ID)
+	s.NoError(err)
+	s.Require().NotNil(dbSub)
+	webhook := dbSub.Subscriber.Target.(*event.WebhookSubscriber)
+	s.Equal(model.FromAPIString(target.Secret), string(webhook.Secret))
+	s.Len(webhook.SigningKeys(time.Now()), 2)
+}
+
+func (s *SubscriptionRouteSuite) TestDistroSubscriptionsNeedEditSettings() {
+	editor := &role.Role{
+		ID:           "distro_editor",
+		Name:         "distro editor",
+		ResourceType: role.ResourceTypeDistro,
+		Resources:    []string{"d1"},
+		Permissions:  []string{role.PermissionEditSettings},
+	}
+	s.NoError(editor.Upsert())
+	subscription := event.Subscription{
+		ID:        "5949645c9acd9604fdd202db",
+		Type:      "HOST",
+		Trigger:   "atrigger",
+		Owner:     "d1",
+		OwnerType: event.OwnerTypeDistro,
+		Selectors: []event.Selector{{Type: "distro", Data: "d1"}},
+		Subscriber: event.Subscriber{
+			Type:   event.SlackSubscriberType,
+			Target: "#hosts",
+		},
+	}
+
+	otherCtx := gimlet.AttachUser(context.Background(), &user.DBUser{Id: "thanos"})
+	editorCtx := gimlet.AttachUser(context.Background(), &user.DBUser{Id: "me", SystemRoles: []string{editor.ID}})
+
+	h := &subscriptionPostHandler{dbSubscriptions: []event.Subscription{subscription}}
+	_, err := h.Execute(otherCtx, s.sc)
+	s.EqualError(err, "401 (Unauthorized): not authorized to change subscriptions of distro 'd1'")
+	_, err = h.Execute(editorCtx, s.sc)
+	s.NoError(err)
+
+	rotate := &subscriptionRotateSecretHandler{id: subscription.ID, subscription: &subscription}
+	_, err = rotate.Execute(otherCtx, s.sc)
+	s.EqualError(err, "401 (Unauthorized): Not authorized to rotate the secret of this subscription")
+
+	del := &subscriptionDeleteHandler{id: subscription.ID, ownerType: event.OwnerTypeDistro, subscription: &subscription}
+	_, err = del.Execute(otherCtx, s.sc)
+	s.EqualError(err, "401 (Unauthorized): not authorized to change subscriptions of distro 'd1'")
+	_, err = del.Execute(editorCtx, s.sc)
+	s.NoError(err)
+}
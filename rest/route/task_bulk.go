@@ -0,0 +1,190 @@
+package route
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+
+	"github.com/evergreen-ci/evergreen"
+	"github.com/evergreen-ci/evergreen/auth"
+	dbModel "github.com/evergreen-ci/evergreen/model"
+	"github.com/evergreen-ci/evergreen/model/audit"
+	"github.com/evergreen-ci/evergreen/model/role"
+	"github.com/evergreen-ci/evergreen/rest/data"
+	"github.com/evergreen-ci/evergreen/rest/model"
+	"github.com/evergreen-ci/gimlet"
+	"github.com/mongodb/amboy"
+	"github.com/pkg/errors"
+)
+
+////////////////////////////////////////////////////////////////////////
+//
+// POST /rest/v2/tasks/bulk
+
+func makeBulkTaskOperation(sc data.Connector, queue amboy.Queue) gimlet.RouteHandler {
+	return &bulkTaskOperationHandler{
+		sc:    sc,
+		queue: queue,
+	}
+}
+
+type bulkTaskOperationHandler struct {
+	request model.BulkTaskRequest
+
+	sc    data.Connector
+	queue amboy.Queue
+}
+
+func (h *bulkTaskOperationHandler) Factory() gimlet.RouteHandler {
+	return &bulkTaskOperationHandler{
+		sc:    h.sc,
+		queue: h.queue,
+	}
+}
+
+func (h *bulkTaskOperationHandler) Parse(ctx context.Context, r *http.Request) error {
+	if err := gimlet.GetJSON(r.Body, &h.request); err != nil {
+		return errors.Wrap(err, "problem parsing request body")
+	}
+
+	filter := h.request.Filter()
+	if err := filter.Validate(); err != nil {
+		return gimlet.ErrorResponse{
+			StatusCode: http.StatusBadRequest,
+			Message:    err.Error(),
+		}
+	}
+	opts := h.request.Options("")
+	if err := opts.Validate(); err != nil {
+		return gimlet.ErrorResponse{
+			StatusCode: http.StatusBadRequest,
+			Message:    err.Error(),
+		}
+	}
+
+	return nil
+}
+
+func (h *bulkTaskOperationHandler) Run(ctx context.Context) gimlet.Responder {
+	u := MustHaveUser(ctx)
+	opts := h.request.Options(u.Username())
+	if !validPriority(opts.Priority, u, h.sc) {
+		return gimlet.MakeJSONErrorResponder(gimlet.ErrorResponse{
+			StatusCode: http.StatusUnauthorized,
+			Message: fmt.Sprintf("Insufficient privilege to set priority to %d, "+
+				"non-superusers can only set priority at or below %d", opts.Priority, evergreen.MaxTaskPriority),
+		})
+	}
+
+	tasks, err := h.sc.FindBulkTasks(h.request.Filter())
+	if err != nil {
+		return gimlet.MakeJSONErrorResponder(err)
+	}
+
+	// the user needs permission to restart tasks in every project that
+	// the filter matched tasks in
+	checked := map[string]bool{}
+	taskIDs := make([]string, len(tasks))
+	for i, t := range tasks {
+		taskIDs[i] = t.Id
+		if checked[t.Project] {
+			continue
+		}
+		checked[t.Project] = true
+
+		projectRef, err := h.sc.FindProjectByBranch(t.Project)
+		if err != nil {
+			return gimlet.MakeJSONInternalErrorResponder(errors.Wrapf(err, "problem finding project '%s'", t.Project))
+		}
+		ok, err := auth.HasProjectPermission(h.sc.GetSuperUsers(), u, projectRef, role.PermissionRestart)
+		if err != nil {
+			return gimlet.MakeJSONInternalErrorResponder(err)
+		}
+		if !ok {
+			return gimlet.MakeJSONErrorResponder(gimlet.ErrorResponse{
+				StatusCode: http.StatusUnauthorized,
+				Message:    fmt.Sprintf("user '%s' cannot modify the tasks of project '%s'", u.Username(), t.Project),
+			})
+		}
+	}
+
+	if h.request.DryRun {
+		audit.Skip(ctx)
+
+		op := dbModel.NewBulkTaskOperation("", opts, taskIDs)
+		apiOp := &model.APIBulkTaskOperation{}
+		if err = apiOp.BuildFromService(op); err != nil {
+			return gimlet.MakeJSONInternalErrorResponder(errors.Wrap(err, "API model error"))
+		}
+		apiOp.Tasks = make([]model.APITask, len(tasks))
+		for i := range tasks {
+			if err = apiOp.Tasks[i].BuildFromService(&tasks[i]); err != nil {
+				return gimlet.MakeJSONInternalErrorResponder(errors.Wrap(err, "API model error"))
+			}
+			if err = apiOp.Tasks[i].BuildFromService(h.sc.GetURL()); err != nil {
+				return gimlet.MakeJSONInternalErrorResponder(errors.Wrap(err, "API model error"))
+			}
+		}
+
+		return gimlet.NewJSONResponse(apiOp)
+	}
+
+	op, err := h.sc.StartBulkTaskOperation(h.queue, opts, taskIDs)
+	if err != nil {
+		return gimlet.MakeJSONErrorResponder(errors.Wrap(err, "problem starting bulk task operation"))
+	}
+	audit.SetAction(ctx, "bulk_task_operation", op.ID, opts.Action)
+
+	apiOp := &model.APIBulkTaskOperation{}
+	if err = apiOp.BuildFromService(op); err != nil {
+		return gimlet.MakeJSONInternalErrorResponder(errors.Wrap(err, "API model error"))
+	}
+
+	return gimlet.NewJSONResponse(apiOp)
+}
+
+////////////////////////////////////////////////////////////////////////
+//
+// GET /rest/v2/tasks/bulk/{job_id}
+
+func makeFetchBulkTaskOperation(sc data.Connector) gimlet.RouteHandler {
+	return &bulkTaskOperationGetHandler{sc: sc}
+}
+
+type bulkTaskOperationGetHandler struct {
+	jobID string
+
+	sc data.Connector
+}
+
+func (h *bulkTaskOperationGetHandler) Factory() gimlet.RouteHandler {
+	return &bulkTaskOperationGetHandler{sc: h.sc}
+}
+
+func (h *bulkTaskOperationGetHandler) Parse(ctx context.Context, r *http.Request) error {
+	h.jobID = gimlet.GetVars(r)["job_id"]
+	return nil
+}
+
+func (h *bulkTaskOperationGetHandler) Run(ctx context.Context) gimlet.Responder {
+	u := MustHaveUser(ctx)
+	op, err := h.sc.GetBulkTaskOperation(h.jobID)
+	if err != nil {
+		return gimlet.MakeJSONErrorResponder(err)
+	}
+
+	// only the user who started an operation and superusers can see it
+	if op.Options.User != u.Username() && !auth.IsSuperUser(h.sc.GetSuperUsers(), u) {
+		return gimlet.MakeJSONErrorResponder(gimlet.ErrorResponse{
+			StatusCode: http.StatusNotFound,
+			Message:    fmt.Sprintf("bulk task operation '%s' not found", h.jobID),
+		})
+	}
+
+	apiOp := &model.APIBulkTaskOperation{}
+	if err = apiOp.BuildFromService(op); err != nil {
+		return gimlet.MakeJSONInternalErrorResponder(errors.Wrap(err, "API model error"))
+	}
+
+	return gimlet.NewJSONResponse(apiOp)
+}
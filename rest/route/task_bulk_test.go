@@ -0,0 +1,121 @@
+package route
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	dbModel "github.com/evergreen-ci/evergreen/model"
+	"github.com/evergreen-ci/evergreen/model/task"
+	"github.com/evergreen-ci/evergreen/model/user"
+	"github.com/evergreen-ci/evergreen/rest/data"
+	"github.com/evergreen-ci/evergreen/rest/model"
+	"github.com/evergreen-ci/gimlet"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBulkTaskOperationHandler(t *testing.T) {
+	sc := &data.MockConnector{URL: "https://evergreen.example.net"}
+	sc.SetSuperUsers([]string{"root"})
+	sc.MockBuildConnector.CachedProjects = map[string]*dbModel.ProjectRef{
+		"mci": {Identifier: "mci", Admins: []string{"admin"}},
+	}
+	sc.MockBulkTaskConnector.CachedTasks = []task.Task{
+		{Id: "t1", Project: "mci", BuildVariant: "ubuntu", DisplayName: "lint", Status: "failed"},
+		{Id: "t2", Project: "mci", BuildVariant: "ubuntu", DisplayName: "test", Status: "failed"},
+		{Id: "t3", Project: "mci", BuildVariant: "windows", DisplayName: "lint", Status: "success"},
+		{Id: "t4", Project: "secret", BuildVariant: "ubuntu", DisplayName: "lint", Status: "failed"},
+	}
+
+	post := func(username string, body interface{}) (gimlet.Responder, error) {
+		payload, err := json.Marshal(body)
+		require.NoError(t, err)
+		r, err := http.NewRequest(http.MethodPost, "/tasks/bulk", bytes.NewBuffer(payload))
+		require.NoError(t, err)
+		ctx := gimlet.AttachUser(context.Background(), &user.DBUser{Id: username})
+		h := makeBulkTaskOperation(sc, nil).Factory()
+		if err = h.Parse(ctx, r); err != nil {
+			return nil, err
+		}
+		return h.Run(ctx), nil
+	}
+
+	// requests must select tasks and have a valid action
+	_, err := post("admin", model.BulkTaskRequest{Action: dbModel.BulkTaskRestart})
+	assert.Error(t, err)
+	_, err = post("admin", model.BulkTaskRequest{Project: "mci", Action: "explode"})
+	assert.Error(t, err)
+	_, err = post("admin", model.BulkTaskRequest{Project: "mci", TaskNameRegex: "(", Action: dbModel.BulkTaskRestart})
+	assert.Error(t, err)
+
+	// a dry run lists the tasks without starting an operation
+	resp, err := post("admin", model.BulkTaskRequest{
+		Project:  "mci",
+		Variant:  "ubuntu",
+		Statuses: []string{"failed"},
+		Action:   dbModel.BulkTaskRestart,
+		DryRun:   true,
+	})
+	require.NoError(t, err)
+	require.Equal(t, http.StatusOK, resp.Status())
+	apiOp := resp.Data().(*model.APIBulkTaskOperation)
+	assert.Equal(t, 2, apiOp.Total)
+	require.Len(t, apiOp.Tasks, 2)
+	assert.Equal(t, "t1", model.FromAPIString(apiOp.Tasks[0].Id))
+	assert.Equal(t, "t2", model.FromAPIString(apiOp.Tasks[1].Id))
+	assert.Empty(t, sc.MockBulkTaskConnector.CachedOperations)
+
+	resp, err = post("admin", model.BulkTaskRequest{TaskIDs: []string{"t1", "t3"}, Action: dbModel.BulkTaskDeactivate})
+	require.NoError(t, err)
+	require.Equal(t, http.StatusOK, resp.Status())
+	apiOp = resp.Data().(*model.APIBulkTaskOperation)
+	assert.Equal(t, dbModel.BulkTaskOperationPending, model.FromAPIString(apiOp.Status))
+	assert.Equal(t, "admin", model.FromAPIString(apiOp.User))
+	assert.Equal(t, 2, apiOp.Total)
+	assert.Empty(t, apiOp.Tasks)
+	op := sc.MockBulkTaskConnector.CachedOperations[model.FromAPIString(apiOp.ID)]
+	require.NotNil(t, op)
+	assert.Equal(t, []string{"t1", "t3"}, op.TaskIDs)
+
+	// users need permission on every project with a matching task
+	resp, err = post("admin", model.BulkTaskRequest{TaskIDs: []string{"t1", "t4"}, Action: dbModel.BulkTaskAbort})
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusUnauthorized, resp.Status())
+
+	// only superusers can set high priorities
+	resp, err = post("admin", model.BulkTaskRequest{Project: "mci", Action: dbModel.BulkTaskSetPriority, Priority: 1000})
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusUnauthorized, resp.Status())
+	resp, err = post("root", model.BulkTaskRequest{Project: "mci", Action: dbModel.BulkTaskSetPriority, Priority: 1000})
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusOK, resp.Status())
+}
+
+func TestBulkTaskOperationGetHandler(t *testing.T) {
+	sc := &data.MockConnector{URL: "https://evergreen.example.net"}
+	sc.SetSuperUsers([]string{"root"})
+	op := dbModel.NewBulkTaskOperation("job", dbModel.BulkTaskOptions{Action: dbModel.BulkTaskRestart, User: "me"}, []string{"t1", "t2"})
+	op.Processed = 1
+	op.TasksSucceeded = []string{"t1"}
+	sc.MockBulkTaskConnector.CachedOperations = map[string]*dbModel.BulkTaskOperation{op.ID: op}
+
+	get := func(username, id string) gimlet.Responder {
+		ctx := gimlet.AttachUser(context.Background(), &user.DBUser{Id: username})
+		h := makeFetchBulkTaskOperation(sc).Factory().(*bulkTaskOperationGetHandler)
+		h.jobID = id
+		return h.Run(ctx)
+	}
+
+	resp := get("me", "job")
+	require.Equal(t, http.StatusOK, resp.Status())
+	apiOp := resp.Data().(*model.APIBulkTaskOperation)
+	assert.Equal(t, 2, apiOp.Total)
+	assert.Equal(t, 1, apiOp.Processed)
+
+	assert.Equal(t, http.StatusOK, get("root", "job").Status())
+	assert.Equal(t, http.StatusNotFound, get("someone", "job").Status())
+	assert.Equal(t, http.StatusNotFound, get("me", "nope").Status())
+}
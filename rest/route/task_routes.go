@@ -9,6 +9,8 @@ import (
 
 	"github.com/evergreen-ci/evergreen"
 	"github.com/evergreen-ci/evergreen/auth"
+	"github.com/evergreen-ci/evergreen/model/audit"
+	"github.com/evergreen-ci/evergreen/model/role"
 	"github.com/evergreen-ci/evergreen/model/task"
 	"github.com/evergreen-ci/evergreen/rest/data"
 	"github.com/evergreen-ci/evergreen/rest/model"
@@ -21,7 +23,7 @@ func getTaskRestartRouteManager(route string, version int) *RouteManager {
 	trh := &taskRestartHandler{}
 	taskRestart := MethodHandler{
 		PrefetchFunctions: []PrefetchFunc{PrefetchProjectContext},
-		Authenticator:     &RequireUserAuthenticator{},
+		Authenticator:     &ProjectPermissionAuthenticator{Permission: role.PermissionRestart},
 		RequestHandler:    trh.Handler(),
 		MethodType:        http.MethodPost,
 	}
@@ -38,7 +40,7 @@ func getTaskRouteManager(route string, version int) *RouteManager {
 	tep := &TaskExecutionPatchHandler{}
 	taskExecutionPatch := MethodHandler{
 		PrefetchFunctions: []PrefetchFunc{PrefetchProjectContext},
-		Authenticator:     &NoAuthAuthenticator{},
+		Authenticator:     &ProjectPermissionAuthenticator{Permission: role.PermissionRestart},
 		RequestHandler:    tep.Handler(),
 		MethodType:        http.MethodPatch,
 	}
@@ -81,9 +83,10 @@ func getTaskAbortManager(route string, version int) *RouteManager {
 		Version: version,
 		Methods: []MethodHandler{
 			{
-				MethodType:     http.MethodPost,
-				Authenticator:  &RequireUserAuthenticator{},
-				RequestHandler: t.Handler(),
+				MethodType:        http.MethodPost,
+				PrefetchFunctions: []PrefetchFunc{PrefetchProjectContext},
+				Authenticator:     &ProjectPermissionAuthenticator{Permission: role.PermissionRestart},
+				RequestHandler:    t.Handler(),
 			},
 		},
 	}
@@ -401,6 +404,7 @@ func (tep *TaskExecutionPatchHandler) ParseAndValidate(ctx context.Context, r *h
 // Execute sets the Activated and Priority field of the given task and returns
 // an updated version of the task.
 func (tep *TaskExecutionPatchHandler) Execute(ctx context.Context, sc data.Connector) (ResponseData, error) {
+	before := *tep.task
 	if tep.Priority != nil {
 		priority := *tep.Priority
 		if priority > evergreen.MaxTaskPriority &&
@@ -425,6 +429,7 @@ func (tep *TaskExecutionPatchHandler) Execute(ctx context.Context, sc data.Conne
 	if err != nil {
 		return ResponseData{}, errors.Wrap(err, "Database error")
 	}
+	audit.RecordChange(ctx, "task", tep.task.Id, before, refreshedTask)
 
 	taskModel := &model.APITask{}
 	err = taskModel.BuildFromService(refreshedTask)
@@ -11,6 +11,7 @@ import (
 	"github.com/evergreen-ci/evergreen/rest/model"
 	"github.com/evergreen-ci/evergreen/thirdparty"
 	"github.com/evergreen-ci/evergreen/util"
+	"github.com/evergreen-ci/gimlet"
 	"github.com/google/go-github/github"
 	"github.com/pkg/errors"
 )
@@ -123,3 +124,129 @@ func (h *userSettingsGetHandler) Execute(ctx context.Context, sc data.Connector)
 		Result: []model.Model{&apiSettings},
 	}, nil
 }
+
+////////////////////////////////////////////////////////////////////////
+//
+// GET /rest/v2/user/tokens
+
+func makeFetchUserTokens(sc data.Connector) gimlet.RouteHandler {
+	return &userTokensGetHandler{
+		sc: sc,
+	}
+}
+
+type userTokensGetHandler struct {
+	sc data.Connector
+}
+
+func (h *userTokensGetHandler) Factory() gimlet.RouteHandler {
+	return &userTokensGetHandler{
+		sc: h.sc,
+	}
+}
+
+func (h *userTokensGetHandler) Parse(ctx context.Context, r *http.Request) error {
+	return nil
+}
+
+func (h *userTokensGetHandler) Run(ctx context.Context) gimlet.Responder {
+	u := MustHaveUser(ctx)
+
+	tokens := make([]model.APIToken, len(u.APITokens))
+	for i := range u.APITokens {
+		if err := tokens[i].BuildFromService(u.APITokens[i]); err != nil {
+			return gimlet.MakeJSONInternalErrorResponder(errors.Wrapf(err, "problem converting token '%s'", u.APITokens[i].Name))
+		}
+	}
+
+	return gimlet.NewJSONResponse(tokens)
+}
+
+////////////////////////////////////////////////////////////////////////
+//
+// POST /rest/v2/user/tokens
+
+func makeCreateUserToken(sc data.Connector) gimlet.RouteHandler {
+	return &userTokenPostHandler{
+		sc: sc,
+	}
+}
+
+type userTokenPostHandler struct {
+	token user.APIToken
+
+	sc data.Connector
+}
+
+func (h *userTokenPostHandler) Factory() gimlet.RouteHandler {
+	return &userTokenPostHandler{
+		sc: h.sc,
+	}
+}
+
+func (h *userTokenPostHandler) Parse(ctx context.Context, r *http.Request) error {
+	apiToken := model.APIToken{}
+	if err := gimlet.GetJSON(r.Body, &apiToken); err != nil {
+		return errors.Wrap(err, "problem parsing request")
+	}
+
+	i, err := apiToken.ToService()
+	if err != nil {
+		return errors.Wrap(err, "problem converting token")
+	}
+	h.token = i.(user.APIToken)
+
+	return nil
+}
+
+func (h *userTokenPostHandler) Run(ctx context.Context) gimlet.Responder {
+	u := MustHaveUser(ctx)
+
+	token, err := h.sc.AddAPIToken(u, h.token.Name, h.token.Scopes, h.token.ExpiresAt)
+	if err != nil {
+		return gimlet.MakeJSONErrorResponder(err)
+	}
+
+	apiToken := &model.APIToken{}
+	if err = apiToken.BuildFromService(u.GetAPIToken(h.token.Name)); err != nil {
+		return gimlet.MakeJSONInternalErrorResponder(err)
+	}
+	apiToken.Token = model.ToAPIString(token)
+
+	return gimlet.NewJSONResponse(apiToken)
+}
+
+////////////////////////////////////////////////////////////////////////
+//
+// DELETE /rest/v2/user/tokens/{token_name}
+
+func makeDeleteUserToken(sc data.Connector) gimlet.RouteHandler {
+	return &userTokenDeleteHandler{
+		sc: sc,
+	}
+}
+
+type userTokenDeleteHandler struct {
+	name string
+
+	sc data.Connector
+}
+
+func (h *userTokenDeleteHandler) Factory() gimlet.RouteHandler {
+	return &userTokenDeleteHandler{
+		sc: h.sc,
+	}
+}
+
+func (h *userTokenDeleteHandler) Parse(ctx context.Context, r *http.Request) error {
+	h.name = gimlet.GetVars(r)["token_name"]
+	return nil
+}
+
+func (h *userTokenDeleteHandler) Run(ctx context.Context) gimlet.Responder {
+	if err := h.sc.DeleteAPIToken(MustHaveUser(ctx), h.name); err != nil {
+		return gimlet.MakeJSONErrorResponder(err)
+	}
+
+	return gimlet.NewJSONResponse(struct{}{})
+}
@@ -6,13 +6,17 @@ import (
 	"encoding/json"
 	"net/http"
 	"testing"
+	"time"
 
 	"github.com/evergreen-ci/evergreen/db"
 	"github.com/evergreen-ci/evergreen/model"
 	"github.com/evergreen-ci/evergreen/model/user"
 	"github.com/evergreen-ci/evergreen/rest/data"
+	restmodel "github.com/evergreen-ci/evergreen/rest/model"
 	"github.com/evergreen-ci/evergreen/testutil"
 	"github.com/evergreen-ci/gimlet"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 	"github.com/stretchr/testify/suite"
 )
 
@@ -109,3 +113,51 @@ func (s *UserRouteSuite) TestUndefinedInput() {
 	s.EqualValues("something", dbUser.Settings.SlackUsername)
 	s.EqualValues("you", dbUser.Settings.GithubUser.LastKnownAs)
 }
+
+func TestUserTokenRoutes(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+
+	u := &user.DBUser{Id: "me"}
+	sc := &data.MockConnector{}
+	sc.MockUserConnector.CachedUsers = map[string]*user.DBUser{u.Id: u}
+	ctx := gimlet.AttachUser(context.Background(), u)
+
+	expiresAt := restmodel.NewTime(time.Now().Add(time.Hour))
+	body, err := json.Marshal(restmodel.APIToken{
+		Name:      restmodel.ToAPIString("ci"),
+		Scopes:    []restmodel.APIString{restmodel.ToAPIString(user.TokenScopePatch)},
+		ExpiresAt: expiresAt,
+	})
+	require.NoError(err)
+	post := makeCreateUserToken(sc).Factory()
+	r, err := http.NewRequest(http.MethodPost, "/user/tokens", bytes.NewBuffer(body))
+	require.NoError(err)
+	require.NoError(post.Parse(ctx, r))
+	resp := post.Run(ctx)
+	require.Equal(http.StatusOK, resp.Status())
+	created, ok := resp.Data().(*restmodel.APIToken)
+	require.True(ok)
+	assert.Equal("ci", restmodel.FromAPIString(created.Name))
+	assert.NotEmpty(restmodel.FromAPIString(created.Token))
+
+	// names must be unique
+	post = makeCreateUserToken(sc).Factory()
+	r, err = http.NewRequest(http.MethodPost, "/user/tokens", bytes.NewBuffer(body))
+	require.NoError(err)
+	require.NoError(post.Parse(ctx, r))
+	assert.Equal(http.StatusBadRequest, post.Run(ctx).Status())
+
+	get := makeFetchUserTokens(sc).Factory()
+	resp = get.Run(ctx)
+	require.Equal(http.StatusOK, resp.Status())
+	tokens, ok := resp.Data().([]restmodel.APIToken)
+	require.True(ok)
+	require.Len(tokens, 1)
+	assert.Nil(tokens[0].Token)
+
+	del := &userTokenDeleteHandler{sc: sc, name: "ci"}
+	assert.Equal(http.StatusOK, del.Run(ctx).Status())
+	assert.Empty(u.APITokens)
+	assert.Equal(http.StatusNotFound, del.Run(ctx).Status())
+}
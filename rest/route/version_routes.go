@@ -4,6 +4,7 @@ import (
 	"context"
 	"net/http"
 
+	"github.com/evergreen-ci/evergreen/model/role"
 	"github.com/evergreen-ci/evergreen/rest/data"
 	"github.com/evergreen-ci/evergreen/rest/model"
 	"github.com/evergreen-ci/gimlet"
@@ -120,6 +121,60 @@ func (h *buildsForVersionHandler) Run(ctx context.Context) gimlet.Responder {
 	return gimlet.NewJSONResponse(buildModels)
 }
 
+////////////////////////////////////////////////////////////////////////
+//
+// GET /rest/v2/versions/{version_id}/downstream
+
+// downstreamVersionsHandler is a RequestHandler for fetching the versions that
+// downstream triggers created from a version
+type downstreamVersionsHandler struct {
+	versionId string
+	sc        data.Connector
+}
+
+func makeGetDownstreamVersions(sc data.Connector) gimlet.RouteHandler {
+	return &downstreamVersionsHandler{
+		sc: sc,
+	}
+}
+
+func (h *downstreamVersionsHandler) Factory() gimlet.RouteHandler {
+	return &downstreamVersionsHandler{
+		sc: h.sc,
+	}
+}
+
+func (h *downstreamVersionsHandler) Parse(ctx context.Context, r *http.Request) error {
+	h.versionId = gimlet.GetVars(r)["version_id"]
+
+	if h.versionId == "" {
+		return errors.New("request data incomplete")
+	}
+
+	return nil
+}
+
+func (h *downstreamVersionsHandler) Run(ctx context.Context) gimlet.Responder {
+	if _, err := h.sc.FindVersionById(h.versionId); err != nil {
+		return gimlet.MakeJSONErrorResponder(errors.Wrap(err, "Database error in finding the version"))
+	}
+
+	versions, err := h.sc.FindDownstreamVersions(h.versionId)
+	if err != nil {
+		return gimlet.MakeJSONErrorResponder(errors.Wrap(err, "Database error in finding downstream versions"))
+	}
+
+	versionModels := []model.Model{}
+	for i := range versions {
+		versionModel := &model.APIVersion{}
+		if err = versionModel.BuildFromService(&versions[i]); err != nil {
+			return gimlet.MakeJSONInternalErrorResponder(errors.Wrap(err, "API model error"))
+		}
+		versionModels = append(versionModels, versionModel)
+	}
+	return gimlet.NewJSONResponse(versionModels)
+}
+
 // versionAbortHandler is a RequestHandler for aborting all tasks of a version.
 type versionAbortHandler struct {
 	versionId string
@@ -130,9 +185,10 @@ func getAbortVersionRouteManager(route string, version int) *RouteManager {
 		Route: route,
 		Methods: []MethodHandler{
 			{
-				Authenticator:  &RequireUserAuthenticator{},
-				RequestHandler: &versionAbortHandler{},
-				MethodType:     http.MethodPost,
+				PrefetchFunctions: []PrefetchFunc{PrefetchProjectContext},
+				Authenticator:     &ProjectPermissionAuthenticator{Permission: role.PermissionRestart},
+				RequestHandler:    &versionAbortHandler{},
+				MethodType:        http.MethodPost,
 			},
 		},
 		Version: version,
@@ -194,9 +250,10 @@ func getRestartVersionRouteManager(route string, version int) *RouteManager {
 		Route: route,
 		Methods: []MethodHandler{
 			{
-				Authenticator:  &RequireUserAuthenticator{},
-				RequestHandler: &versionRestartHandler{},
-				MethodType:     http.MethodPost,
+				PrefetchFunctions: []PrefetchFunc{PrefetchProjectContext},
+				Authenticator:     &ProjectPermissionAuthenticator{Permission: role.PermissionRestart},
+				RequestHandler:    &versionRestartHandler{},
+				MethodType:        http.MethodPost,
 			},
 		},
 		Version: version,
@@ -0,0 +1,21 @@
+package main
+
+import (
+	"flag"
+	"io/ioutil"
+
+	"github.com/evergreen-ci/evergreen/rest/route"
+	"github.com/mongodb/grip"
+)
+
+// generate-openapi writes the OpenAPI document describing the REST v2 API,
+// which is checked in so clients can be generated from it.
+func main() {
+	var output string
+	flag.StringVar(&output, "output", "rest/openapi.json", "path to write the document to")
+	flag.Parse()
+
+	spec, err := route.GetOpenAPISpec()
+	grip.EmergencyFatal(err)
+	grip.EmergencyFatal(ioutil.WriteFile(output, spec, 0644))
+}
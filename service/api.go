@@ -14,7 +14,9 @@ import (
 	"github.com/evergreen-ci/evergreen/model/event"
 	"github.com/evergreen-ci/evergreen/model/host"
 	"github.com/evergreen-ci/evergreen/model/task"
+	"github.com/evergreen-ci/evergreen/model/user"
 	"github.com/evergreen-ci/evergreen/model/version"
+	"github.com/evergreen-ci/evergreen/rest/route"
 	"github.com/evergreen-ci/evergreen/util"
 	"github.com/evergreen-ci/evergreen/validator"
 	"github.com/evergreen-ci/gimlet"
@@ -299,6 +301,30 @@ func (as *APIServer) AttachFiles(w http.ResponseWriter, r *http.Request) {
 	gimlet.WriteJSON(w, fmt.Sprintf("Artifact files for task %v successfully attached", t.Id))
 }
 
+// AttachCoverage records the source files that a task's coverage reports show
+// it executes, merging the reports attached during the same execution. Only
+// mainline tasks are recorded, since patches may change what a task covers.
+func (as *APIServer) AttachCoverage(w http.ResponseWriter, r *http.Request) {
+	t := MustHaveTask(r)
+
+	files := []string{}
+	if err := util.ReadJSONInto(util.NewRequestReader(r), &files); err != nil {
+		as.LoggedError(w, r, http.StatusBadRequest, errors.Wrapf(err, "error reading coverage for task %s", t.Id))
+		return
+	}
+
+	if evergreen.IsPatchRequester(t.Requester) {
+		gimlet.WriteJSON(w, fmt.Sprintf("Coverage of patch task %s is not recorded", t.Id))
+		return
+	}
+
+	if err := model.AddCoverageImpact(t.Project, t.BuildVariant, t.DisplayName, t.Id, t.Execution, files); err != nil {
+		as.LoggedError(w, r, http.StatusInternalServerError, err)
+		return
+	}
+	gimlet.WriteJSON(w, fmt.Sprintf("Coverage of %d files for task %s successfully attached", len(files), t.Id))
+}
+
 // AppendTaskLog appends the received logs to the task's internal logs.
 func (as *APIServer) AppendTaskLog(w http.ResponseWriter, r *http.Request) {
 	if as.GetSettings().ServiceFlags.TaskLoggingDisabled {
@@ -534,6 +560,9 @@ func (as *APIServer) GetServiceApp() *gimlet.APIApp {
 	checkUser := gimlet.NewRequireAuthHandler()
 	checkTask := gimlet.WrapperMiddleware(as.checkTask)
 	checkHost := gimlet.WrapperMiddleware(as.checkHost)
+	readOnlyScope := route.NewTokenScopeMiddleware(user.TokenScopeReadOnly)
+	patchScope := route.NewTokenScopeMiddleware(user.TokenScopePatch)
+	hostScope := route.NewTokenScopeMiddleware(user.TokenScopeHost)
 
 	app := gimlet.NewApp()
 	app.SetPrefix("/api")
@@ -559,25 +588,25 @@ func (as *APIServer) GetServiceApp() *gimlet.APIApp {
 	app.AddRoute("/task_queue/limit").Handler(as.checkTaskQueueSize).Get()
 
 	// CLI Operation Backends
-	app.AddRoute("/tasks/{projectId}").Wrap(checkUser, checkProject).Handler(as.listTasks).Get()
-	app.AddRoute("/variants/{projectId}").Wrap(checkUser, checkProject).Handler(as.listVariants).Get()
-	app.AddRoute("/projects").Wrap(checkUser).Handler(as.listProjects).Get()
+	app.AddRoute("/tasks/{projectId}").Wrap(readOnlyScope, checkUser, checkProject).Handler(as.listTasks).Get()
+	app.AddRoute("/variants/{projectId}").Wrap(readOnlyScope, checkUser, checkProject).Handler(as.listVariants).Get()
+	app.AddRoute("/projects").Wrap(readOnlyScope, checkUser).Handler(as.listProjects).Get()
 
 	// Patches
-	app.PrefixRoute("/patches").Route("/").Wrap(checkUser).Handler(as.submitPatch).Put()
-	app.PrefixRoute("/patches").Route("/mine").Wrap(checkUser).Handler(as.listPatches).Get()
-	app.PrefixRoute("/patches").Route("/{patchId:\\w+}").Wrap(checkUser).Handler(as.summarizePatch).Get()
-	app.PrefixRoute("/patches").Route("/{patchId:\\w+}").Wrap(checkUser).Handler(as.existingPatchRequest).Post()
-	app.PrefixRoute("/patches").Route("/{patchId:\\w+}/{projectId}/modules").Wrap(checkUser, checkProject).Handler(as.listPatchModules).Get()
-	app.PrefixRoute("/patches").Route("/{patchId:\\w+}/modules").Wrap(checkUser).Handler(as.deletePatchModule).Delete()
-	app.PrefixRoute("/patches").Route("/{patchId:\\w+}/modules").Wrap(checkUser).Handler(as.updatePatchModule).Post()
+	app.PrefixRoute("/patches").Route("/").Wrap(patchScope, checkUser).Handler(as.submitPatch).Put()
+	app.PrefixRoute("/patches").Route("/mine").Wrap(readOnlyScope, checkUser).Handler(as.listPatches).Get()
+	app.PrefixRoute("/patches").Route("/{patchId:\\w+}").Wrap(readOnlyScope, checkUser).Handler(as.summarizePatch).Get()
+	app.PrefixRoute("/patches").Route("/{patchId:\\w+}").Wrap(patchScope, checkUser).Handler(as.existingPatchRequest).Post()
+	app.PrefixRoute("/patches").Route("/{patchId:\\w+}/{projectId}/modules").Wrap(readOnlyScope, checkUser, checkProject).Handler(as.listPatchModules).Get()
+	app.PrefixRoute("/patches").Route("/{patchId:\\w+}/modules").Wrap(patchScope, checkUser).Handler(as.deletePatchModule).Delete()
+	app.PrefixRoute("/patches").Route("/{patchId:\\w+}/modules").Wrap(patchScope, checkUser).Handler(as.updatePatchModule).Post()
 
 	// SpawnHosts
-	app.Route().Prefix("/spawn").Wrap(checkUser).Route("/{instance_id:[\\w_\\-\\@]+}/").Handler(as.hostInfo).Get()
-	app.Route().Prefix("/spawn").Wrap(checkUser).Route("/{instance_id:[\\w_\\-\\@]+}/").Handler(as.modifyHost).Post()
-	app.Route().Prefix("/spawns").Wrap(checkUser).Route("/").Handler(as.requestHost).Put()
-	app.Route().Prefix("/spawns").Wrap(checkUser).Route("/{user}/").Handler(as.hostsInfoForUser).Get()
-	app.Route().Prefix("/spawns").Wrap(checkUser).Route("/distros/list/").Handler(as.listDistros).Get()
+	app.Route().Prefix("/spawn").Wrap(readOnlyScope, checkUser).Route("/{instance_id:[\\w_\\-\\@]+}/").Handler(as.hostInfo).Get()
+	app.Route().Prefix("/spawn").Wrap(hostScope, checkUser).Route("/{instance_id:[\\w_\\-\\@]+}/").Handler(as.modifyHost).Post()
+	app.Route().Prefix("/spawns").Wrap(hostScope, checkUser).Route("/").Handler(as.requestHost).Put()
+	app.Route().Prefix("/spawns").Wrap(readOnlyScope, checkUser).Route("/{user}/").Handler(as.hostsInfoForUser).Get()
+	app.Route().Prefix("/spawns").Wrap(readOnlyScope, checkUser).Route("/distros/list/").Handler(as.listDistros).Get()
 
 	// Agent routes
 	app.Route().Version(2).Route("/agent/next_task").Wrap(checkHost).Handler(as.NextTask).Get()
@@ -593,6 +622,7 @@ func (as *APIServer) GetServiceApp() *gimlet.APIApp {
 	app.Route().Version(2).Route("/task/{taskId}/system_info").Wrap(checkTaskSecret, checkHost).Handler(as.TaskSystemInfo).Post()
 	app.Route().Version(2).Route("/task/{taskId}/process_info").Wrap(checkTaskSecret, checkHost).Handler(as.TaskProcessInfo).Post()
 	app.Route().Version(2).Route("/task/{taskId}/files").Wrap(checkTask, checkHost).Handler(as.AttachFiles).Post()
+	app.Route().Version(2).Route("/task/{taskId}/coverage").Wrap(checkTask, checkHost).Handler(as.AttachCoverage).Post()
 	app.Route().Version(2).Route("/task/{taskId}/distro").Wrap(checkTask).Handler(as.GetDistro).Get()
 	app.Route().Version(2).Route("/task/{taskId}/version").Wrap(checkTask).Handler(as.GetVersion).Get()
 	app.Route().Version(2).Route("/task/{taskId}/project_ref").Wrap(checkTask).Handler(as.GetProjectRef).Get()
@@ -8,9 +8,13 @@ import (
 	"time"
 
 	"github.com/evergreen-ci/evergreen"
+	"github.com/evergreen-ci/evergreen/auth"
 	"github.com/evergreen-ci/evergreen/db"
 	"github.com/evergreen-ci/evergreen/model"
 	"github.com/evergreen-ci/evergreen/model/patch"
+	"github.com/evergreen-ci/evergreen/model/role"
+	"github.com/evergreen-ci/evergreen/repotracker"
+	"github.com/evergreen-ci/evergreen/rest/data"
 	"github.com/evergreen-ci/evergreen/thirdparty"
 	"github.com/evergreen-ci/evergreen/units"
 	"github.com/evergreen-ci/evergreen/util"
@@ -44,6 +48,8 @@ func (as *APIServer) submitPatch(w http.ResponseWriter, r *http.Request) {
 		Tasks       []string `json:"tasks"`
 		Finalize    bool     `json:"finalize"`
 		Alias       string   `json:"alias"`
+		Ref         string   `json:"ref"`
+		PRNumber    int      `json:"pr_number"`
 	}{}
 	if err := util.ReadJSONInto(util.NewRequestReaderWithSize(r, patch.SizeLimit), &data); err != nil {
 		as.LoggedError(w, r, http.StatusBadRequest, err)
@@ -60,13 +66,46 @@ func (as *APIServer) submitPatch(w http.ResponseWriter, r *http.Request) {
 		as.LoggedError(w, r, http.StatusBadRequest, errors.Wrapf(err, "project %s is not specified", data.Project))
 		return
 	}
+	if pref == nil {
+		as.LoggedError(w, r, http.StatusNotFound, errors.Errorf("project %s not found", data.Project))
+		return
+	}
+
+	canPatch, err := auth.HasProjectPermission(as.Settings.SuperUsers, dbUser, pref, role.PermissionPatch)
+	if err != nil {
+		as.LoggedError(w, r, http.StatusInternalServerError, errors.Wrap(err, "problem checking permissions"))
+		return
+	}
+	if !canPatch {
+		as.LoggedError(w, r, http.StatusUnauthorized, errors.Errorf("user %s cannot patch project %s", dbUser.Id, data.Project))
+		return
+	}
 
 	if pref.PatchingDisabled || !pref.Enabled {
 		as.LoggedError(w, r, http.StatusUnauthorized, errors.New("patching is disabled"))
 		return
 	}
 
-	intent, err := patch.NewCliIntent(dbUser.Id, data.Project, data.Githash, r.FormValue("module"), data.Patch, data.Description, data.Finalize, variants, data.Tasks, data.Alias)
+	if !as.checkPatchQuota(w, r, dbUser.Id) {
+		return
+	}
+
+	var intent patch.Intent
+	if data.Ref != "" || data.PRNumber != 0 {
+		if data.Patch != "" || data.Githash != "" {
+			as.LoggedError(w, r, http.StatusBadRequest, errors.New("patches from a ref can't include a diff or a base"))
+			return
+		}
+		var base, sourceRef, diff string
+		base, sourceRef, diff, err = as.getRefPatch(r.Context(), pref, data.Ref, data.PRNumber)
+		if err != nil {
+			as.LoggedError(w, r, http.StatusBadRequest, err)
+			return
+		}
+		intent, err = patch.NewRefCliIntent(dbUser.Id, data.Project, base, sourceRef, diff, data.Description, data.Finalize, variants, data.Tasks, data.Alias)
+	} else {
+		intent, err = patch.NewCliIntent(dbUser.Id, data.Project, data.Githash, r.FormValue("module"), data.Patch, data.Description, data.Finalize, variants, data.Tasks, data.Alias)
+	}
 	if err != nil {
 		as.LoggedError(w, r, http.StatusBadRequest, err)
 		return
@@ -82,10 +121,11 @@ func (as *APIServer) submitPatch(w http.ResponseWriter, r *http.Request) {
 	}
 
 	patchID := bson.NewObjectId()
-	job := units.NewPatchIntentProcessor(patchID, intent)
-	job.Run(r.Context())
-
-	if err = job.Error(); err != nil {
+	if err = units.ProcessPatchIntent(r.Context(), patchID, intent); err != nil {
+		if model.IsPatchConflict(err) {
+			as.LoggedError(w, r, http.StatusConflict, err)
+			return
+		}
 		as.LoggedError(w, r, http.StatusInternalServerError, errors.Wrap(err, "error processing patch"))
 		return
 	}
@@ -103,6 +143,96 @@ func (as *APIServer) submitPatch(w http.ResponseWriter, r *http.Request) {
 	gimlet.WriteJSONResponse(w, http.StatusCreated, PatchAPIResponse{Patch: patchDoc})
 }
 
+// getRefPatch builds a patch of the project's branch from a branch, tag, or
+// commit of its repository, or from a pull request, with the GitHub API. The
+// patches of git projects come from the repotracker's mirror of the
+// repository instead, which has no pull requests. It returns the merge base
+// of the patch, the ref that it was built from, and its diff.
+func (as *APIServer) getRefPatch(ctx context.Context, pref *model.ProjectRef, ref string, prNumber int) (string, string, string, error) {
+	if ref != "" && prNumber != 0 {
+		return "", "", "", errors.New("can't patch from both a ref and a pull request")
+	}
+	if pref.RepoKind == model.GitRepoType {
+		if prNumber != 0 {
+			return "", "", "", errors.Errorf("patches from a pull request are only supported for GitHub projects, and '%s' is a git project", pref.Identifier)
+		}
+		poller := repotracker.NewGitRepositoryPoller(pref, as.Settings.RepoTracker.MirrorDirectory)
+		base, diff, err := poller.GetRefDiff(ctx, ref)
+		if err != nil {
+			return "", "", "", errors.WithStack(err)
+		}
+		return base, ref, diff, nil
+	}
+
+	token, err := as.Settings.GetGithubOauthToken()
+	if err != nil {
+		return "", "", "", errors.Wrap(err, "can't get github token")
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, 30*time.Second)
+	defer cancel()
+
+	if prNumber != 0 {
+		if prNumber < 0 {
+			return "", "", "", errors.Errorf("invalid pull request number %d", prNumber)
+		}
+		gh := patch.GithubPatch{
+			PRNumber:  prNumber,
+			BaseOwner: pref.Owner,
+			BaseRepo:  pref.Repo,
+		}
+		if _, err = thirdparty.GetGithubPullRequest(ctx, token, pref.Owner, pref.Repo, prNumber); err != nil {
+			return "", "", "", errors.WithStack(err)
+		}
+		var base, diff string
+		base, err = thirdparty.GetPullRequestMergeBase(ctx, token, gh)
+		if err != nil {
+			return "", "", "", errors.Wrapf(err, "can't find the merge base of pull request #%d", prNumber)
+		}
+		diff, _, err = thirdparty.GetGithubPullRequestDiff(ctx, token, &gh)
+		if err != nil {
+			return "", "", "", errors.Wrapf(err, "can't get the diff of pull request #%d", prNumber)
+		}
+		return base, fmt.Sprintf("refs/pull/%d/head", prNumber), diff, nil
+	}
+
+	commit, err := thirdparty.GetCommitEvent(ctx, token, pref.Owner, pref.Repo, ref)
+	if err != nil {
+		return "", "", "", errors.Wrapf(err, "can't find '%s' in '%s/%s'", ref, pref.Owner, pref.Repo)
+	}
+	if commit == nil || commit.SHA == nil {
+		return "", "", "", errors.Errorf("can't find '%s' in '%s/%s'", ref, pref.Owner, pref.Repo)
+	}
+	head := *commit.SHA
+	base, err := thirdparty.GetGithubMergeBaseRevision(ctx, token, pref.Owner, pref.Repo, pref.Branch, head)
+	if err != nil {
+		return "", "", "", errors.Wrapf(err, "can't find the merge base of '%s' and branch '%s'", ref, pref.Branch)
+	}
+	diff, _, err := thirdparty.GetGithubCompareDiff(ctx, token, pref.Owner, pref.Repo, base, head)
+	if err != nil {
+		return "", "", "", errors.Wrapf(err, "can't get the diff of '%s'", ref)
+	}
+
+	return base, ref, diff, nil
+}
+
+// checkPatchQuota writes an error response and returns false if the user
+// cannot create another patch today.
+func (as *APIServer) checkPatchQuota(w http.ResponseWriter, r *http.Request, user string) bool {
+	qc := &data.DBQuotaConnector{}
+	err := qc.CheckPatchQuota(user)
+	if err == nil {
+		return true
+	}
+
+	if apiErr, ok := err.(gimlet.ErrorResponse); ok {
+		as.LoggedError(w, r, apiErr.StatusCode, err)
+	} else {
+		as.LoggedError(w, r, http.StatusInternalServerError, errors.Wrap(err, "problem checking patch quota"))
+	}
+	return false
+}
+
 // Get the patch with the specified request it
 func getPatchFromRequest(r *http.Request) (*patch.Patch, error) {
 	// get id and secret from the request.
@@ -302,6 +432,10 @@ func (as *APIServer) existingPatchRequest(w http.ResponseWriter, r *http.Request
 		p.PatchedConfig = string(projectYamlBytes)
 		_, err = model.FinalizePatch(ctx, p, evergreen.PatchVersionRequester, githubOauthToken)
 		if err != nil {
+			if model.IsPatchConflict(err) {
+				as.LoggedError(w, r, http.StatusConflict, err)
+				return
+			}
 			as.LoggedError(w, r, http.StatusInternalServerError, err)
 			return
 		}
@@ -61,7 +61,11 @@ func (as *APIServer) requestHost(w http.ResponseWriter, r *http.Request) {
 	hc := &data.DBHostConnector{}
 	spawnHost, err := hc.NewIntentHost(hostRequest.Distro, hostRequest.PublicKey, "", user)
 	if err != nil {
-		http.Error(w, err.Error(), http.StatusBadRequest)
+		status := http.StatusBadRequest
+		if apiErr, ok := err.(gimlet.ErrorResponse); ok {
+			status = apiErr.StatusCode
+		}
+		http.Error(w, err.Error(), status)
 		return
 	}
 	if spawnHost == nil {
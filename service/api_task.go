@@ -6,7 +6,6 @@ import (
 	"time"
 
 	"github.com/evergreen-ci/evergreen"
-	"github.com/evergreen-ci/evergreen/alerts"
 	"github.com/evergreen-ci/evergreen/apimodels"
 	"github.com/evergreen-ci/evergreen/model"
 	"github.com/evergreen-ci/evergreen/model/event"
@@ -15,6 +14,7 @@ import (
 	"github.com/evergreen-ci/evergreen/units"
 	"github.com/evergreen-ci/evergreen/util"
 	"github.com/evergreen-ci/gimlet"
+	"github.com/mongodb/amboy"
 	"github.com/mongodb/grip"
 	"github.com/mongodb/grip/message"
 	"github.com/mongodb/grip/sometimes"
@@ -169,6 +169,9 @@ func (as *APIServer) EndTask(w http.ResponseWriter, r *http.Request) {
 	if len(updates.BuildNewStatus) != 0 {
 		event.LogBuildStateChangeEvent(t.BuildId, updates.BuildNewStatus)
 	}
+	if t.Requester == evergreen.GitlabMRRequester {
+		queueGitlabStatusUpdates(as.queue, t, updates)
+	}
 
 	// the task was aborted if it is still in undispatched.
 	// the active state should be inactive.
@@ -199,22 +202,6 @@ func (as *APIServer) EndTask(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	if !evergreen.IsPatchRequester(t.Requester) {
-		if t.IsPartOfDisplay() {
-			parent := t.DisplayTask
-			if parent.IsFinished() {
-				grip.Error(errors.Wrapf(alerts.RunTaskFailureTriggers(parent.Id),
-					"processing alert triggers for display task %s", parent.Id))
-			}
-		} else {
-			grip.Infoln("Processing alert triggers for task", t.Id)
-
-			grip.Error(errors.Wrapf(alerts.RunTaskFailureTriggers(t.Id),
-				"processing alert triggers for task %s", t.Id))
-		}
-	}
-	// TODO(EVG-223) process patch-specific triggers
-
 	// update the bookkeeping entry for the task
 	err = task.UpdateExpectedDuration(t, t.TimeTaken)
 	if err != nil {
@@ -259,6 +246,26 @@ func (as *APIServer) EndTask(w http.ResponseWriter, r *http.Request) {
 	gimlet.WriteJSON(w, endTaskResp)
 }
 
+// queueGitlabStatusUpdates reports the builds and patches of GitLab merge
+// requests that a task finished to GitLab.
+func queueGitlabStatusUpdates(queue amboy.Queue, t *task.Task, updates model.StatusChanges) {
+	var jobs []amboy.Job
+	if updates.BuildNewStatus == evergreen.BuildSucceeded || updates.BuildNewStatus == evergreen.BuildFailed {
+		jobs = append(jobs, units.NewGitlabStatusUpdateJobForFinishedBuild(t.BuildId))
+	}
+	if updates.PatchNewStatus == evergreen.PatchSucceeded || updates.PatchNewStatus == evergreen.PatchFailed {
+		jobs = append(jobs, units.NewGitlabStatusUpdateJobForFinishedPatch(t.Version))
+	}
+
+	for _, job := range jobs {
+		grip.Error(message.WrapError(queue.Put(job), message.Fields{
+			"message": "failed to queue gitlab status update",
+			"task_id": t.Id,
+			"job_id":  job.ID(),
+		}))
+	}
+}
+
 // assignNextAvailableTask gets the next task from the queue and sets the running task field
 // of currentHost.
 func assignNextAvailableTask(taskQueue *model.TaskQueue, currentHost *host.Host) (*task.Task, error) {
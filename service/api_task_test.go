@@ -20,9 +20,11 @@ import (
 	"github.com/evergreen-ci/evergreen/model/alertrecord"
 	"github.com/evergreen-ci/evergreen/model/build"
 	"github.com/evergreen-ci/evergreen/model/distro"
+	"github.com/evergreen-ci/evergreen/model/event"
 	"github.com/evergreen-ci/evergreen/model/host"
 	"github.com/evergreen-ci/evergreen/model/task"
 	modelUtil "github.com/evergreen-ci/evergreen/model/testutil"
+	"github.com/evergreen-ci/evergreen/model/trigger"
 	"github.com/evergreen-ci/evergreen/model/version"
 	"github.com/evergreen-ci/evergreen/testutil"
 	"github.com/mongodb/amboy"
@@ -571,7 +573,8 @@ func TestTaskLifecycleEndpoints(t *testing.T) {
 
 	Convey("with tasks, a host, a build, and a task queue", t, func() {
 		if err := db.ClearCollections(host.Collection, task.Collection, model.TaskQueuesCollection,
-			build.Collection, model.ProjectRefCollection, version.Collection, alertrecord.Collection); err != nil {
+			build.Collection, model.ProjectRefCollection, version.Collection, alertrecord.Collection,
+			event.AllLogCollection, event.SubscriptionsCollection); err != nil {
 			t.Fatalf("clearing db: %v", err)
 		}
 
@@ -784,6 +787,7 @@ func TestTaskLifecycleEndpoints(t *testing.T) {
 				BuildId:      buildId,
 				BuildVariant: "bv",
 				Version:      versionId,
+				Requester:    evergreen.RepotrackerVersionRequester,
 			}
 			So(execTask.Insert(), ShouldBeNil)
 			displayTask := task.Task{
@@ -798,6 +802,7 @@ func TestTaskLifecycleEndpoints(t *testing.T) {
 				DisplayOnly:    true,
 				BuildVariant:   "bv",
 				ExecutionTasks: []string{execTask.Id},
+				Requester:      evergreen.RepotrackerVersionRequester,
 			}
 			So(displayTask.Insert(), ShouldBeNil)
 
@@ -833,18 +838,43 @@ func TestTaskLifecycleEndpoints(t *testing.T) {
 				So(err, ShouldBeNil)
 				So(dbBuild.Tasks[2].Status, ShouldEqual, evergreen.TaskFailed)
 			})
-			Convey("alerts should be created for the build failure", func() {
+			Convey("the regression trigger should fire for the display task only", func() {
+				sub := event.Subscription{
+					ID:        "regression-sub",
+					Type:      event.ResourceTypeTask,
+					Trigger:   "regression",
+					Selectors: []event.Selector{{Type: "project", Data: projectId}},
+					Subscriber: event.Subscriber{
+						Type:   event.EmailSubscriberType,
+						Target: "someone@example.com",
+					},
+					OwnerType: event.OwnerTypeProject,
+					Owner:     projectId,
+				}
+				So(sub.Upsert(), ShouldBeNil)
+
+				displayEvents, err := event.Find(event.AllLogCollection, event.TaskEventsInOrder(displayTask.Id))
+				So(err, ShouldBeNil)
+				So(displayEvents, ShouldHaveLength, 1)
+				n, err := trigger.NotificationsFromEvent(&displayEvents[0])
+				So(err, ShouldBeNil)
+				So(n, ShouldHaveLength, 1)
+
 				dbAlert, err := alertrecord.FindOne(alertrecord.ByLastFailureTransition(
 					displayTask.DisplayName, displayTask.BuildVariant, displayTask.Project))
 				So(err, ShouldBeNil)
-				if err != nil {
-					return
-				}
-
+				So(dbAlert, ShouldNotBeNil)
 				So(dbAlert.Type, ShouldEqual, alertrecord.TaskFailTransitionId)
 				So(dbAlert.TaskId, ShouldEqual, displayTask.Id)
 
-				// alerts should not have been created for the execution task
+				// the execution task should not notify on its own
+				execEvents, err := event.Find(event.AllLogCollection, event.TaskEventsInOrder(execTask.Id))
+				So(err, ShouldBeNil)
+				So(execEvents, ShouldHaveLength, 1)
+				n, err = trigger.NotificationsFromEvent(&execEvents[0])
+				So(err, ShouldBeNil)
+				So(n, ShouldBeEmpty)
+
 				execTaskAlert, err := alertrecord.FindOne(alertrecord.ByLastFailureTransition(
 					execTask.DisplayName, execTask.BuildVariant, execTask.Project))
 				So(err, ShouldBeNil)
@@ -9,6 +9,7 @@ import (
 	"github.com/evergreen-ci/evergreen"
 	"github.com/evergreen-ci/evergreen/auth"
 	"github.com/evergreen-ci/evergreen/model"
+	"github.com/evergreen-ci/evergreen/model/role"
 	"github.com/evergreen-ci/evergreen/model/user"
 	"github.com/evergreen-ci/evergreen/plugin"
 	"github.com/evergreen-ci/evergreen/util"
@@ -102,20 +103,72 @@ func (uis *UIServer) GetSettings() evergreen.Settings {
 }
 
 // requireAdmin takes in a request handler and returns a wrapped version which verifies that requests are
-// authenticated and that the user is either a super user or is part of the project context's project's admins.
+// authenticated and that the user can edit the settings of the project context's project, either as a
+// super user, as one of the project's admins, or through a role.
 func (uis *UIServer) requireAdmin(next http.HandlerFunc) http.HandlerFunc {
-	return func(w http.ResponseWriter, r *http.Request) {
-		ctx := r.Context()
-		// get the project context
-		projCtx := MustHaveProjectContext(r)
-		if dbUser := gimlet.GetUser(ctx); dbUser != nil {
-			if uis.isSuperUser(dbUser) || isAdmin(dbUser, projCtx.ProjectRef) {
+	return uis.requireProjectPermission(role.PermissionEditSettings)(next)
+}
+
+// requireProjectPermission returns a wrapper which verifies that requests are authenticated and that
+// the user has the permission on the project context's project. Requests without a project in their
+// context, such as ones creating a project, are only allowed through for super users.
+func (uis *UIServer) requireProjectPermission(permission string) func(http.HandlerFunc) http.HandlerFunc {
+	return func(next http.HandlerFunc) http.HandlerFunc {
+		return func(w http.ResponseWriter, r *http.Request) {
+			projCtx := MustHaveProjectContext(r)
+			dbUser := gimlet.GetUser(r.Context())
+			if dbUser == nil {
+				uis.RedirectToLogin(w, r)
+				return
+			}
+			if projCtx.ProjectRef == nil {
+				if !uis.isSuperUser(dbUser) {
+					http.Error(w, "Project not found", http.StatusNotFound)
+					return
+				}
 				next(w, r)
 				return
 			}
+
+			ok, err := auth.HasProjectPermission(uis.Settings.SuperUsers, dbUser, projCtx.ProjectRef, permission)
+			if err != nil {
+				uis.LoggedError(w, r, http.StatusInternalServerError, errors.Wrap(err, "error checking permissions"))
+				return
+			}
+			if !ok {
+				http.Error(w, fmt.Sprintf("Unauthorized: missing permission '%s' on project '%s'", permission, projCtx.ProjectRef.Identifier), http.StatusUnauthorized)
+				return
+			}
+
+			next(w, r)
 		}
+	}
+}
 
-		uis.RedirectToLogin(w, r)
+// requireDistroPermission returns a wrapper which verifies that requests are authenticated and that
+// the user has the permission on the distro in the request's URL.
+func (uis *UIServer) requireDistroPermission(permission string) func(http.HandlerFunc) http.HandlerFunc {
+	return func(next http.HandlerFunc) http.HandlerFunc {
+		return func(w http.ResponseWriter, r *http.Request) {
+			dbUser := gimlet.GetUser(r.Context())
+			if dbUser == nil {
+				uis.RedirectToLogin(w, r)
+				return
+			}
+
+			distroID := gimlet.GetVars(r)["distro_id"]
+			ok, err := auth.HasDistroPermission(uis.Settings.SuperUsers, dbUser, distroID, permission)
+			if err != nil {
+				uis.LoggedError(w, r, http.StatusInternalServerError, errors.Wrap(err, "error checking permissions"))
+				return
+			}
+			if !ok {
+				http.Error(w, fmt.Sprintf("Unauthorized: missing permission '%s' on distro '%s'", permission, distroID), http.StatusUnauthorized)
+				return
+			}
+
+			next(w, r)
+		}
 	}
 }
 
@@ -219,6 +272,18 @@ func (uis *UIServer) loadCtx(next http.HandlerFunc) http.HandlerFunc {
 			return
 		}
 
+		if usr != nil && projCtx.ProjectRef != nil && projCtx.ProjectRef.Private {
+			ok, err := auth.HasProjectPermission(uis.Settings.SuperUsers, usr, projCtx.ProjectRef, role.PermissionView)
+			if err != nil {
+				uis.LoggedError(w, r, http.StatusInternalServerError, errors.Wrap(err, "error checking permissions"))
+				return
+			}
+			if !ok {
+				http.Error(w, "Project not found", http.StatusNotFound)
+				return
+			}
+		}
+
 		r = setUIRequestContext(r, projCtx)
 
 		next(w, r)
@@ -226,13 +291,20 @@ func (uis *UIServer) loadCtx(next http.HandlerFunc) http.HandlerFunc {
 }
 
 // populateProjectRefs loads all project refs into the context. If includePrivate is true,
-// all available projects will be included, otherwise only public projects will be loaded.
-// Sets IsAdmin to true if the user id is located in a project's admin list.
+// the private projects that the user can view will be included, otherwise only public
+// projects will be loaded. Sets IsAdmin to true if the user id is located in a project's admin list.
 func (pc *projectContext) populateProjectRefs(includePrivate, isSuperUser bool, user gimlet.User) error {
 	allProjs, err := model.FindAllTrackedProjectRefs()
 	if err != nil {
 		return err
 	}
+	var roles []role.Role
+	if includePrivate && user != nil {
+		roles, err = role.FindRolesForUser(user)
+		if err != nil {
+			return err
+		}
+	}
 	pc.AllProjects = make([]UIProjectFields, 0, len(allProjs))
 	// User is not logged in, so only include public projects.
 	for _, p := range allProjs {
@@ -243,7 +315,8 @@ func (pc *projectContext) populateProjectRefs(includePrivate, isSuperUser bool,
 		if !p.Enabled {
 			continue
 		}
-		if !p.Private || includePrivate {
+		if !p.Private || (includePrivate && (isSuperUser || isAdmin(user, &p) ||
+			role.AnyGrants(roles, role.ResourceTypeProject, p.Identifier, role.PermissionView))) {
 			uiProj := UIProjectFields{
 				DisplayName: p.DisplayName,
 				Identifier:  p.Identifier,
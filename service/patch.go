@@ -209,14 +209,20 @@ func (uis *UIServer) schedulePatch(w http.ResponseWriter, r *http.Request) {
 		if projCtx.Patch.IsGithubPRPatch() {
 			requester = evergreen.GithubPRRequester
 		}
+		if projCtx.Patch.IsGitlabMRPatch() {
+			requester = evergreen.GitlabMRRequester
+		}
 
 		ctx, cancel := context.WithCancel(r.Context())
 		defer cancel()
 
 		ver, err := model.FinalizePatch(ctx, projCtx.Patch, requester, githubOauthToken)
 		if err != nil {
-			uis.LoggedError(w, r, http.StatusInternalServerError,
-				errors.Wrap(err, "Error finalizing patch"))
+			code := http.StatusInternalServerError
+			if model.IsPatchConflict(err) {
+				code = http.StatusConflict
+			}
+			uis.LoggedError(w, r, code, errors.Wrap(err, "Error finalizing patch"))
 			return
 		}
 
@@ -228,6 +234,14 @@ func (uis *UIServer) schedulePatch(w http.ResponseWriter, r *http.Request) {
 				return
 			}
 		}
+		if projCtx.Patch.IsGitlabMRPatch() {
+			job := units.NewGitlabStatusUpdateJobForNewPatch(projCtx.Patch.Id.Hex())
+			if err := uis.queue.Put(job); err != nil {
+				uis.LoggedError(w, r, http.StatusInternalServerError,
+					errors.Wrap(err, "Error adding gitlab status update job to queue"))
+				return
+			}
+		}
 
 		PushFlash(uis.CookieStore, r, w, NewSuccessFlash("Patch builds are scheduled."))
 		gimlet.WriteJSON(w, struct {
@@ -10,9 +10,11 @@ import (
 	"time"
 
 	"github.com/evergreen-ci/evergreen"
-	"github.com/evergreen-ci/evergreen/alerts"
+	"github.com/evergreen-ci/evergreen/auth"
 	"github.com/evergreen-ci/evergreen/model"
+	"github.com/evergreen-ci/evergreen/model/audit"
 	"github.com/evergreen-ci/evergreen/model/event"
+	"github.com/evergreen-ci/evergreen/model/role"
 	"github.com/evergreen-ci/evergreen/model/user"
 	restModel "github.com/evergreen-ci/evergreen/rest/model"
 	"github.com/evergreen-ci/evergreen/units"
@@ -23,7 +25,6 @@ import (
 	"github.com/mongodb/grip"
 	"github.com/mongodb/grip/message"
 	"github.com/pkg/errors"
-	"gopkg.in/mgo.v2/bson"
 )
 
 // publicProjectFields are the fields needed by the UI
@@ -61,20 +62,10 @@ func (uis *UIServer) projectsPage(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	// construct a json-marshaling friendly representation of our supported triggers
-	allTaskTriggers := []interface{}{}
-	for _, taskTrigger := range alerts.AvailableTaskFailTriggers {
-		allTaskTriggers = append(allTaskTriggers, struct {
-			Id      string `json:"id"`
-			Display string `json:"display"`
-		}{taskTrigger.Id(), taskTrigger.Display()})
-	}
-
 	data := struct {
-		AllProjects       []model.ProjectRef
-		AvailableTriggers []interface{}
+		AllProjects []model.ProjectRef
 		ViewData
-	}{allProjects, allTaskTriggers, uis.GetCommonViewData(w, r, true, true)}
+	}{allProjects, uis.GetCommonViewData(w, r, true, true)}
 
 	uis.render.WriteResponse(w, http.StatusOK, data, "base", "projects.html", "base_angular.html", "menu.html")
 }
@@ -182,30 +173,31 @@ func (uis *UIServer) modifyProject(w http.ResponseWriter, r *http.Request) {
 		http.Error(w, "Project not found", http.StatusNotFound)
 		return
 	}
+	origProjectRef := *projectRef
 
 	responseRef := struct {
-		Identifier         string               `json:"id"`
-		DisplayName        string               `json:"display_name"`
-		RemotePath         string               `json:"remote_path"`
-		BatchTime          int                  `json:"batch_time"`
-		DeactivatePrevious bool                 `json:"deactivate_previous"`
-		Branch             string               `json:"branch_name"`
-		ProjVarsMap        map[string]string    `json:"project_vars"`
-		ProjectAliases     []model.ProjectAlias `json:"project_aliases"`
-		DeleteAliases      []string             `json:"delete_aliases"`
-		PrivateVars        map[string]bool      `json:"private_vars"`
-		Enabled            bool                 `json:"enabled"`
-		Private            bool                 `json:"private"`
-		Owner              string               `json:"owner_name"`
-		Repo               string               `json:"repo_name"`
-		Admins             []string             `json:"admins"`
-		TracksPushEvents   bool                 `json:"tracks_push_events"`
-		PRTestingEnabled   bool                 `json:"pr_testing_enabled"`
-		PatchingDisabled   bool                 `json:"patching_disabled"`
-		AlertConfig        map[string][]struct {
-			Provider string                 `json:"provider"`
-			Settings map[string]interface{} `json:"settings"`
-		} `json:"alert_config"`
+		Identifier           string                      `json:"id"`
+		DisplayName          string                      `json:"display_name"`
+		RemotePath           string                      `json:"remote_path"`
+		BatchTime            int                         `json:"batch_time"`
+		DeactivatePrevious   bool                        `json:"deactivate_previous"`
+		Branch               string                      `json:"branch_name"`
+		ProjVarsMap          map[string]string           `json:"project_vars"`
+		ProjectAliases       []model.ProjectAlias        `json:"project_aliases"`
+		DeleteAliases        []string                    `json:"delete_aliases"`
+		PrivateVars          map[string]bool             `json:"private_vars"`
+		Enabled              bool                        `json:"enabled"`
+		Private              bool                        `json:"private"`
+		Owner                string                      `json:"owner_name"`
+		Repo                 string                      `json:"repo_name"`
+		RepoKind             string                      `json:"repo_kind"`
+		RepoURL              string                      `json:"repo_url"`
+		Admins               []string                    `json:"admins"`
+		CronSchedules        []model.CronSchedule        `json:"cron_schedules"`
+		Downstream           []model.DownstreamTrigger   `json:"downstream"`
+		TracksPushEvents     bool                        `json:"tracks_push_events"`
+		PRTestingEnabled     bool                        `json:"pr_testing_enabled"`
+		PatchingDisabled     bool                        `json:"patching_disabled"`
 		NotifyOnBuildFailure bool                        `json:"notify_on_failure"`
 		SetupGithubHook      bool                        `json:"setup_github_hook"`
 		ForceRepotrackerRun  bool                        `json:"force_repotracker_run"`
@@ -223,6 +215,24 @@ func (uis *UIServer) modifyProject(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	if responseRef.RepoKind == "" {
+		responseRef.RepoKind = model.GithubRepoType
+	}
+	if !util.StringSliceContains(model.ValidRepoTypes, responseRef.RepoKind) {
+		http.Error(w, fmt.Sprintf("invalid repository kind '%s'", responseRef.RepoKind), http.StatusBadRequest)
+		return
+	}
+	if responseRef.RepoKind == model.GitRepoType {
+		if responseRef.RepoURL == "" {
+			http.Error(w, "no repository URL specified", http.StatusBadRequest)
+			return
+		}
+		if err = model.ValidateRepoURL(responseRef.RepoURL); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+	}
+
 	errs := []string{}
 	for i, pd := range responseRef.ProjectAliases {
 		if strings.TrimSpace(pd.Alias) == "" {
@@ -276,21 +286,47 @@ func (uis *UIServer) modifyProject(w http.ResponseWriter, r *http.Request) {
 	projectRef.Owner = responseRef.Owner
 	projectRef.DeactivatePrevious = responseRef.DeactivatePrevious
 	projectRef.Repo = responseRef.Repo
+	projectRef.RepoKind = responseRef.RepoKind
+	projectRef.RepoURL = responseRef.RepoURL
 	projectRef.Admins = responseRef.Admins
 	projectRef.Identifier = id
 	projectRef.TracksPushEvents = responseRef.TracksPushEvents
 	projectRef.PRTestingEnabled = responseRef.PRTestingEnabled
 	projectRef.PatchingDisabled = responseRef.PatchingDisabled
 	projectRef.NotifyOnBuildFailure = responseRef.NotifyOnBuildFailure
+	projectRef.CronSchedules = responseRef.CronSchedules
+	projectRef.Downstream = responseRef.Downstream
+	validationCatcher := grip.NewBasicCatcher()
+	validationCatcher.Add(projectRef.ValidateCronSchedules())
+	validationCatcher.Add(projectRef.ValidateDownstream())
+	if validationCatcher.HasErrors() {
+		http.Error(w, validationCatcher.Resolve().Error(), http.StatusBadRequest)
+		return
+	}
 
-	projectRef.Alerts = map[string][]model.AlertConfig{}
-	for triggerId, alerts := range responseRef.AlertConfig {
-		//TODO validate the triggerID, provider, and settings.
-		for _, alert := range alerts {
-			projectRef.Alerts[triggerId] = append(projectRef.Alerts[triggerId], model.AlertConfig{
-				Provider: alert.Provider,
-				Settings: bson.M(alert.Settings),
-			})
+	// triggers create versions of other projects, so the user must be able to
+	// edit the settings of the projects that they add triggers for
+	for _, downstreamID := range projectRef.ChangedDownstreamProjects(origProjectRef.Downstream) {
+		var downstreamRef *model.ProjectRef
+		downstreamRef, err = model.FindOneProjectRef(downstreamID)
+		if err != nil {
+			uis.LoggedError(w, r, http.StatusInternalServerError, err)
+			return
+		}
+		if downstreamRef == nil {
+			http.Error(w, fmt.Sprintf("downstream project '%s' not found", downstreamID), http.StatusBadRequest)
+			return
+		}
+		var ok bool
+		ok, err = auth.HasProjectPermission(uis.Settings.SuperUsers, dbUser, downstreamRef, role.PermissionEditSettings)
+		if err != nil {
+			uis.LoggedError(w, r, http.StatusInternalServerError, err)
+			return
+		}
+		if !ok {
+			http.Error(w, fmt.Sprintf("Unauthorized: missing permission to edit the settings of downstream project '%s'", downstreamID),
+				http.StatusUnauthorized)
+			return
 		}
 	}
 
@@ -300,6 +336,19 @@ func (uis *UIServer) modifyProject(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	if projectVarsChanged(projectVars, responseRef.ProjVarsMap, responseRef.PrivateVars) {
+		var ok bool
+		ok, err = auth.HasProjectPermission(uis.Settings.SuperUsers, dbUser, projectRef, role.PermissionManageVars)
+		if err != nil {
+			uis.LoggedError(w, r, http.StatusInternalServerError, err)
+			return
+		}
+		if !ok {
+			http.Error(w, "Unauthorized: missing permission to manage project variables", http.StatusUnauthorized)
+			return
+		}
+	}
+
 	if responseRef.SetupGithubHook {
 		var hook *model.GithubHook
 		hook, err = model.FindGithubHook(responseRef.Owner, responseRef.Repo)
@@ -362,6 +411,7 @@ func (uis *UIServer) modifyProject(w http.ResponseWriter, r *http.Request) {
 		uis.LoggedError(w, r, http.StatusInternalServerError, err)
 		return
 	}
+	audit.RecordChange(r.Context(), "project", id, origProjectRef, projectRef)
 
 	catcher := grip.NewSimpleCatcher()
 	for _, apiSubscription := range responseRef.Subscriptions {
@@ -376,10 +426,13 @@ func (uis *UIServer) modifyProject(w http.ResponseWriter, r *http.Request) {
 				Type: "project",
 				Data: projectRef.Identifier,
 			},
-			{
+		}
+		// hosts aren't associated with a requester
+		if subscription.Type != event.ResourceTypeHost {
+			subscription.Selectors = append(subscription.Selectors, event.Selector{
 				Type: "requester",
 				Data: evergreen.RepotrackerVersionRequester,
-			},
+			})
 		}
 		subscription.OwnerType = event.OwnerTypeProject
 		subscription.Owner = projectRef.Identifier
@@ -408,6 +461,7 @@ func (uis *UIServer) modifyProject(w http.ResponseWriter, r *http.Request) {
 			}
 		}
 	}
+	origProjectVars := *projectVars
 	projectVars.Vars = responseRef.ProjVarsMap
 	projectVars.PrivateVars = responseRef.PrivateVars
 
@@ -416,6 +470,7 @@ func (uis *UIServer) modifyProject(w http.ResponseWriter, r *http.Request) {
 		uis.LoggedError(w, r, http.StatusInternalServerError, err)
 		return
 	}
+	audit.RecordChange(r.Context(), "project", id, origProjectVars, projectVars, "vars")
 
 	for i := range responseRef.ProjectAliases {
 		responseRef.ProjectAliases[i].ProjectID = id
@@ -595,3 +650,36 @@ func (uis *UIServer) setupGithubHook(projectRef *model.ProjectRef) (int, error)
 
 	return *hook.ID, nil
 }
+
+// projectVarsChanged returns whether submitted project variables differ
+// from the existing ones. Private variables are redacted when sent to the
+// client, so an empty value for one is not a change.
+func projectVarsChanged(existing *model.ProjectVars, vars map[string]string, privateVars map[string]bool) bool {
+	if existing == nil {
+		existing = &model.ProjectVars{}
+	}
+	if len(existing.Vars) != len(vars) {
+		return true
+	}
+	for k, v := range vars {
+		old, ok := existing.Vars[k]
+		if !ok {
+			return true
+		}
+		if old != v && !(existing.PrivateVars[k] && v == "") {
+			return true
+		}
+	}
+	for k, v := range privateVars {
+		if existing.PrivateVars[k] != v {
+			return true
+		}
+	}
+	for k, v := range existing.PrivateVars {
+		if privateVars[k] != v {
+			return true
+		}
+	}
+
+	return false
+}
@@ -6,6 +6,7 @@ import (
 	"time"
 
 	"github.com/evergreen-ci/evergreen"
+	"github.com/evergreen-ci/evergreen/rest/data"
 	"github.com/evergreen-ci/evergreen/rest/route"
 	"github.com/evergreen-ci/gimlet"
 	"github.com/mongodb/grip"
@@ -41,8 +42,11 @@ func GetServer(addr string, n http.Handler) *http.Server {
 func GetRouter(as *APIServer, uis *UIServer) (http.Handler, error) {
 	app := gimlet.NewApp()
 	app.AddMiddleware(gimlet.MakeRecoveryLogger())
+	app.AddMiddleware(route.NewAPITokenMiddleware(&data.DBConnector{}))
 	app.AddMiddleware(gimlet.UserMiddleware(uis.UserManager, GetUserMiddlewareConf()))
 	app.AddMiddleware(gimlet.NewAuthenticationHandler(gimlet.NewBasicAuthenticator(nil, nil), uis.UserManager))
+	app.AddMiddleware(route.NewRateLimitMiddleware())
+	app.AddMiddleware(route.NewAuditMiddleware(as.Settings.Api.TrustedProxies))
 	app.AddMiddleware(gimlet.NewStatic("", http.Dir(filepath.Join(uis.Home, "public"))))
 	app.AddMiddleware(gimlet.NewStatic("/clients", http.Dir(filepath.Join(uis.Home, evergreen.ClientDirectory))))
 
@@ -50,7 +54,8 @@ func GetRouter(as *APIServer, uis *UIServer) (http.Handler, error) {
 	// need/want to access and construct it separately.
 	rest := GetRESTv1App(as)
 
-	route.AttachHandler(rest, as.queue, as.Settings.Ui.Url, as.Settings.SuperUsers, []byte(as.Settings.Api.GithubWebhookSecret))
+	route.AttachHandler(rest, as.queue, as.Settings.Ui.Url, as.Settings.SuperUsers,
+		[]byte(as.Settings.Api.GithubWebhookSecret), []byte(as.Settings.Api.GitlabWebhookSecret))
 
 	// Historically all rest interfaces were available in the API
 	// and UI endpoints. While there were no users of restv1 in
@@ -59,7 +64,8 @@ func GetRouter(as *APIServer, uis *UIServer) (http.Handler, error) {
 	// endpoints.
 	apiRestV2 := gimlet.NewApp()
 	apiRestV2.SetPrefix(evergreen.APIRoutePrefix + "/" + evergreen.RestRoutePrefix)
-	route.AttachHandler(apiRestV2, as.queue, as.Settings.Ui.Url, as.Settings.SuperUsers, []byte(as.Settings.Api.GithubWebhookSecret))
+	route.AttachHandler(apiRestV2, as.queue, as.Settings.Ui.Url, as.Settings.SuperUsers,
+		[]byte(as.Settings.Api.GithubWebhookSecret), []byte(as.Settings.Api.GitlabWebhookSecret))
 
 	// in the future the following functions will be above this
 	// point, and we'll just have the app, but during the legacy
@@ -10,8 +10,10 @@ import (
 	"github.com/evergreen-ci/evergreen"
 	"github.com/evergreen-ci/evergreen/auth"
 	"github.com/evergreen-ci/evergreen/cloud"
+	"github.com/evergreen-ci/evergreen/model/audit"
 	"github.com/evergreen-ci/evergreen/model/distro"
 	"github.com/evergreen-ci/evergreen/model/host"
+	"github.com/evergreen-ci/evergreen/model/role"
 	"github.com/evergreen-ci/evergreen/model/task"
 	"github.com/evergreen-ci/evergreen/rest/data"
 	restModel "github.com/evergreen-ci/evergreen/rest/model"
@@ -49,12 +51,18 @@ func (uis *UIServer) spawnPage(w http.ResponseWriter, r *http.Request) {
 		}
 	}
 
+	limits, err := evergreen.GetRateLimitConfig()
+	if err != nil {
+		uis.LoggedError(w, r, http.StatusInternalServerError, errors.Wrap(err, "Error getting spawn host quota"))
+		return
+	}
+
 	uis.render.WriteResponse(w, http.StatusOK, struct {
 		Distro          distro.Distro
 		Task            *task.Task
 		MaxHostsPerUser int
 		ViewData
-	}{spawnDistro, spawnTask, cloud.MaxSpawnHostsPerUser, uis.GetCommonViewData(w, r, false, true)}, "base", "spawned_hosts.html", "base_angular.html", "menu.html")
+	}{spawnDistro, spawnTask, limits.SpawnHostQuota(), uis.GetCommonViewData(w, r, false, true)}, "base", "spawned_hosts.html", "base_angular.html", "menu.html")
 }
 
 func (uis *UIServer) getSpawnedHosts(w http.ResponseWriter, r *http.Request) {
@@ -112,6 +120,16 @@ func (uis *UIServer) requestNewHost(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	ok, err := auth.HasDistroPermission(uis.Settings.SuperUsers, authedUser, putParams.Distro, role.PermissionSpawnHosts)
+	if err != nil {
+		uis.LoggedError(w, r, http.StatusInternalServerError, errors.Wrap(err, "Error checking permissions"))
+		return
+	}
+	if !ok {
+		http.Error(w, fmt.Sprintf("Unauthorized: cannot spawn hosts of distro '%s'", putParams.Distro), http.StatusUnauthorized)
+		return
+	}
+
 	// save the supplied public key if needed
 	if putParams.SaveKey {
 		if err := authedUser.AddPublicKey(putParams.KeyName, putParams.PublicKey); err != nil {
@@ -124,6 +142,10 @@ func (uis *UIServer) requestNewHost(w http.ResponseWriter, r *http.Request) {
 	spawnHost, err := hc.NewIntentHost(putParams.Distro, putParams.PublicKey, putParams.Task, authedUser)
 
 	if err != nil {
+		if apiErr, ok := err.(gimlet.ErrorResponse); ok {
+			uis.LoggedError(w, r, apiErr.StatusCode, err)
+			return
+		}
 		uis.LoggedError(w, r, http.StatusInternalServerError, errors.Wrap(err, "Error spawning host"))
 		return
 	}
@@ -167,6 +189,9 @@ func (uis *UIServer) modifySpawnHost(w http.ResponseWriter, r *http.Request) {
 		http.Error(w, "no action specified", http.StatusBadRequest)
 		return
 	}
+	audit.SetAction(ctx, "host", h.Id, *updateParams.Action)
+	before := *h
+
 	// determine what action needs to be taken
 	switch *updateParams.Action {
 	case HostTerminate:
@@ -182,6 +207,7 @@ func (uis *UIServer) modifySpawnHost(w http.ResponseWriter, r *http.Request) {
 			uis.LoggedError(w, r, http.StatusInternalServerError, err)
 			return
 		}
+		audit.RecordChange(ctx, "host", h.Id, before, h)
 		gimlet.WriteJSON(w, "host terminated")
 		return
 
@@ -219,6 +245,7 @@ func (uis *UIServer) modifySpawnHost(w http.ResponseWriter, r *http.Request) {
 			uis.LoggedError(w, r, http.StatusInternalServerError, errors.Wrap(err, "Error extending host expiration time"))
 			return
 		}
+		audit.RecordChange(ctx, "host", h.Id, before, h)
 		PushFlash(uis.CookieStore, r, w, NewSuccessFlash(fmt.Sprintf("Host expiration "+
 			"extension successful; %v will expire on %v", hostId,
 			futureExpiration.Format(time.RFC850))))
@@ -12,6 +12,7 @@ import (
 	"github.com/evergreen-ci/evergreen"
 	"github.com/evergreen-ci/evergreen/apimodels"
 	"github.com/evergreen-ci/evergreen/model"
+	"github.com/evergreen-ci/evergreen/model/audit"
 	"github.com/evergreen-ci/evergreen/model/event"
 	"github.com/evergreen-ci/evergreen/model/host"
 	"github.com/evergreen-ci/evergreen/model/task"
@@ -650,6 +651,8 @@ func (uis *UIServer) taskModify(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
 	authUser := gimlet.GetUser(ctx)
 	authName := authUser.DisplayName()
+	audit.SetAction(ctx, "task", projCtx.Task.Id, putParams.Action)
+	before := *projCtx.Task
 
 	// determine what action needs to be taken
 	switch putParams.Action {
@@ -664,6 +667,7 @@ func (uis *UIServer) taskModify(w http.ResponseWriter, r *http.Request) {
 		if err != nil {
 			uis.LoggedError(w, r, http.StatusInternalServerError, err)
 		}
+		audit.RecordChange(ctx, "task", before.Id, before, projCtx.Task)
 		gimlet.WriteJSON(w, projCtx.Task)
 		return
 	case "abort":
@@ -677,6 +681,7 @@ func (uis *UIServer) taskModify(w http.ResponseWriter, r *http.Request) {
 		if err != nil {
 			uis.LoggedError(w, r, http.StatusInternalServerError, err)
 		}
+		audit.RecordChange(ctx, "task", before.Id, before, projCtx.Task)
 		gimlet.WriteJSON(w, projCtx.Task)
 		return
 	case "set_active":
@@ -692,6 +697,7 @@ func (uis *UIServer) taskModify(w http.ResponseWriter, r *http.Request) {
 		if err != nil {
 			uis.LoggedError(w, r, http.StatusInternalServerError, err)
 		}
+		audit.RecordChange(ctx, "task", before.Id, before, projCtx.Task)
 		gimlet.WriteJSON(w, projCtx.Task)
 		return
 	case "set_priority":
@@ -717,6 +723,7 @@ func (uis *UIServer) taskModify(w http.ResponseWriter, r *http.Request) {
 		if err != nil {
 			uis.LoggedError(w, r, http.StatusInternalServerError, err)
 		}
+		audit.RecordChange(ctx, "task", before.Id, before, projCtx.Task)
 		gimlet.WriteJSON(w, projCtx.Task)
 		return
 	case "override_dependencies":
@@ -729,6 +736,7 @@ func (uis *UIServer) taskModify(w http.ResponseWriter, r *http.Request) {
 			http.Error(w, err.Error(), http.StatusInternalServerError)
 			return
 		}
+		audit.RecordChange(ctx, "task", before.Id, before, projCtx.Task)
 		gimlet.WriteJSON(w, projCtx.Task)
 		return
 	default:
@@ -60,6 +60,8 @@ func MockConfig() *evergreen.Settings {
 		Api: evergreen.APIConfig{
 			HttpListenAddr:      "addr",
 			GithubWebhookSecret: "secret",
+			GitlabWebhookSecret: "gitlab secret",
+			TrustedProxies:      []string{"10.0.0.0/8"},
 		},
 		ApiUrl: "api",
 		AuthConfig: evergreen.AuthConfig{
@@ -77,6 +79,16 @@ func MockConfig() *evergreen.Settings {
 				Users:        []string{"ghuser"},
 				Organization: "ghorg",
 			},
+			OIDC: &evergreen.OIDCConfig{
+				Issuer:       "https://idp.example.com",
+				ClientId:     "oidcclient",
+				ClientSecret: "oidcsecret",
+				Scopes:       []string{"openid", "email"},
+				RolesClaim:   "groups",
+				RoleMappings: []evergreen.OIDCRoleMapping{
+					{ClaimValue: "evergreen-admins", Roles: []string{"admins"}},
+				},
+			},
 		},
 		Banner:            "banner",
 		BannerTheme:       "important",
@@ -148,10 +160,30 @@ func MockConfig() *evergreen.Settings {
 				Password: "vsphere_pass",
 			},
 		},
+		RateLimit: evergreen.RateLimitConfig{
+			Default: evergreen.RateLimit{
+				RequestsPerMinute: 600,
+				Burst:             100,
+			},
+			RouteGroups: []evergreen.RouteGroupRateLimit{
+				{
+					Name:         "hosts",
+					PathPrefixes: []string{"/hosts"},
+					Limit: evergreen.RateLimit{
+						RequestsPerMinute: 60,
+						Burst:             10,
+					},
+				},
+			},
+			ExemptUsers:          []string{"robot"},
+			MaxPatchesPerDay:     100,
+			MaxSpawnHostsPerUser: 5,
+		},
 		RepoTracker: evergreen.RepoTrackerConfig{
 			NumNewRepoRevisionsToFetch: 10,
 			MaxRepoRevisionsToSearch:   20,
 			MaxConcurrentRequests:      30,
+			MirrorDirectory:            "/data/mirrors",
 		},
 		Scheduler: evergreen.SchedulerConfig{
 			TaskFinder: "legacy",
@@ -160,11 +192,11 @@ func MockConfig() *evergreen.Settings {
 			TaskDispatchDisabled:         true,
 			HostinitDisabled:             true,
 			MonitorDisabled:              true,
-			AlertsDisabled:               true,
 			TaskrunnerDisabled:           true,
 			RepotrackerDisabled:          true,
 			SchedulerDisabled:            true,
 			GithubPRTestingDisabled:      true,
+			GitlabMRTestingDisabled:      true,
 			RepotrackerPushEventDisabled: true,
 			CLIUpdatesDisabled:           true,
 			EventProcessingDisabled:      true,
@@ -173,6 +205,7 @@ func MockConfig() *evergreen.Settings {
 			EmailNotificationsDisabled:   true,
 			WebhookNotificationsDisabled: true,
 			GithubStatusAPIDisabled:      true,
+			GitlabStatusAPIDisabled:      true,
 		},
 		Slack: evergreen.SlackConfig{
 			Options: &send.SlackOptions{
@@ -37,6 +37,12 @@ type FileNotFoundError struct {
 	filepath string
 }
 
+// NewFileNotFoundError returns the error for when the file at the path can't
+// be found.
+func NewFileNotFoundError(filepath string) FileNotFoundError {
+	return FileNotFoundError{filepath: filepath}
+}
+
 func (nfe FileNotFoundError) Error() string {
 	return fmt.Sprintf("Requested file at %v not found", nfe.filepath)
 }
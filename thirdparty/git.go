@@ -2,9 +2,11 @@ package thirdparty
 
 import (
 	"bytes"
+	"context"
 	"fmt"
 	"io"
 	"io/ioutil"
+	"os"
 	"os/exec"
 	"path/filepath"
 	"strconv"
@@ -134,3 +136,87 @@ func GetPatchSummaries(patchContent string) ([]patch.Summary, error) {
 	}
 	return summaries, nil
 }
+
+// GetPatchedFilePaths returns the paths of the existing files that a patch
+// changes, deletes, renames, or copies, in the order they appear in the
+// patch. Files that the patch creates aren't included.
+func GetPatchedFilePaths(patchContent string) []string {
+	paths := []string{}
+	seen := map[string]bool{}
+	for _, line := range strings.Split(patchContent, "\n") {
+		var path string
+		switch {
+		case strings.HasPrefix(line, "--- a/"):
+			path = strings.TrimPrefix(line, "--- a/")
+		case strings.HasPrefix(line, "rename from "):
+			path = strings.TrimPrefix(line, "rename from ")
+		case strings.HasPrefix(line, "copy from "):
+			path = strings.TrimPrefix(line, "copy from ")
+		default:
+			continue
+		}
+		// unified diffs may follow the path with a tab and a timestamp
+		path = strings.TrimRight(strings.SplitN(path, "\t", 2)[0], "\r")
+		if path == "" || seen[path] {
+			continue
+		}
+		seen[path] = true
+		paths = append(paths, path)
+	}
+	return paths
+}
+
+// GitApplyCheck checks whether a patch applies cleanly to the given
+// contents of the files it changes, keyed by path. Files missing from the
+// map are treated as not existing. If the patch doesn't apply, it returns
+// the output of git apply describing the conflicts.
+func GitApplyCheck(ctx context.Context, patchContent string, files map[string]string) (string, error) {
+	dir, err := ioutil.TempDir("", "patch-check")
+	if err != nil {
+		return "", errors.Wrap(err, "can't create directory to check patch")
+	}
+	defer os.RemoveAll(dir) //nolint: evg
+
+	// the files are checked in their own repository, so that the patch's
+	// paths can't resolve against a repository that contains the directory
+	if out, err := exec.CommandContext(ctx, "git", "init", "-q", dir).CombinedOutput(); err != nil {
+		return "", errors.Wrapf(err, "can't initialize repository to check patch: %s", out)
+	}
+	for path, content := range files {
+		filePath := filepath.Join(dir, filepath.FromSlash(path))
+		if !strings.HasPrefix(filePath, dir+string(filepath.Separator)) {
+			return "", errors.Errorf("invalid path '%s' in patch", path)
+		}
+		if err = os.MkdirAll(filepath.Dir(filePath), 0755); err != nil {
+			return "", errors.Wrapf(err, "can't create directory for '%s'", path)
+		}
+		if err = ioutil.WriteFile(filePath, []byte(content), 0644); err != nil {
+			return "", errors.Wrapf(err, "can't write '%s'", path)
+		}
+	}
+
+	patchFile, err := ioutil.TempFile("", "patch-check")
+	if err != nil {
+		return "", errors.Wrap(err, "can't create patch file")
+	}
+	defer os.Remove(patchFile.Name()) //nolint: evg
+	if _, err = patchFile.WriteString(patchContent); err != nil {
+		_ = patchFile.Close()
+		return "", errors.Wrap(err, "can't write patch file")
+	}
+	if err = patchFile.Close(); err != nil {
+		return "", errors.Wrap(err, "can't write patch file")
+	}
+
+	cmd := exec.CommandContext(ctx, "git", "apply", "--check", patchFile.Name())
+	cmd.Dir = dir
+	out, err := cmd.CombinedOutput()
+	if err != nil {
+		if _, ok := err.(*exec.ExitError); ok && ctx.Err() == nil {
+			return strings.TrimSpace(string(out)), nil
+		}
+		return "", errors.Wrapf(err, "can't check patch: %s", out)
+	}
+
+	return "", nil
+}
@@ -1,6 +1,7 @@
 package thirdparty
 
 import (
+	"context"
 	"testing"
 
 	"github.com/evergreen-ci/evergreen/db"
@@ -59,3 +60,50 @@ func TestGetPatchSummaries(t *testing.T) {
 	assert.Equal(0, summaries[2].Additions)
 	assert.Equal(0, summaries[2].Deletions)
 }
+
+func TestGetPatchedFilePaths(t *testing.T) {
+	assert.Equal(t, []string{"test.txt", "test2.txt"}, GetPatchedFilePaths(patchText))
+}
+
+func TestGitApplyCheck(t *testing.T) {
+	assert := assert.New(t)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	lorem := "Lorem ipsum dolor sit amet, consectetur adipiscing elit, sed do eiusmod tempor incididunt ut labore et dolore magna aliqua. Ut enim ad minim veniam, quis nostrud exercitation ullamco laboris nisi ut aliquip ex ea commodo consequat. Duis aute irure dolor in reprehenderit in voluptate velit esse cillum dolore eu fugiat nulla pariatur. Excepteur sint occaecat cupidatat non proident, sunt in culpa qui officia deserunt mollit anim id est laborum.\n\n"
+	out, err := GitApplyCheck(ctx, patchText, map[string]string{
+		"test.txt":  lorem,
+		"test2.txt": "more text!\n",
+	})
+	assert.NoError(err)
+	assert.Empty(out)
+
+	// the changed file no longer matches the patch
+	out, err = GitApplyCheck(ctx, patchText, map[string]string{
+		"test.txt":  "something else\n",
+		"test2.txt": "more text!\n",
+	})
+	assert.NoError(err)
+	assert.Contains(out, "test.txt")
+
+	// the deleted file no longer exists
+	out, err = GitApplyCheck(ctx, patchText, map[string]string{
+		"test.txt": lorem,
+	})
+	assert.NoError(err)
+	assert.Contains(out, "test2.txt")
+
+	// the new file already exists
+	out, err = GitApplyCheck(ctx, patchText, map[string]string{
+		"test.txt":  lorem,
+		"test2.txt": "more text!\n",
+		"test3.txt": "",
+	})
+	assert.NoError(err)
+	assert.Contains(out, "test3.txt")
+
+	_, err = GitApplyCheck(ctx, patchText, map[string]string{
+		"../test.txt": lorem,
+	})
+	assert.Error(err)
+}
@@ -684,6 +684,61 @@ func GetGithubPullRequestDiff(ctx context.Context, token string, gh *patch.Githu
 	return diff, summaries, nil
 }
 
+// GetGithubPullRequest returns a pull request of a repository.
+func GetGithubPullRequest(ctx context.Context, token, owner, repo string, prNumber int) (*github.PullRequest, error) {
+	httpClient, err := getGithubClient(token)
+	if err != nil {
+		return nil, errors.Wrap(err, "can't fetch data from github")
+	}
+	defer util.PutHTTPClient(httpClient)
+	client := github.NewClient(httpClient)
+
+	pr, _, err := client.PullRequests.Get(ctx, owner, repo, prNumber)
+	if err != nil {
+		return nil, errors.Wrapf(err, "problem getting pull request #%d of '%s/%s'", prNumber, owner, repo)
+	}
+	if pr == nil || pr.Head == nil || pr.Head.SHA == nil {
+		return nil, errors.Errorf("pull request #%d of '%s/%s' is missing data", prNumber, owner, repo)
+	}
+
+	return pr, nil
+}
+
+// GetGithubCompareDiff downloads the diff between two commits of a
+// repository. This function does not use go-github because this operation
+// is not supported
+func GetGithubCompareDiff(ctx context.Context, token, owner, repo, base, head string) (string, []patch.Summary, error) {
+	all := rehttp.RetryAll(rehttp.RetryMaxRetries(NumGithubRetries-1), githubShouldRetry)
+	client, err := util.GetRetryableOauth2HTTPClient(token, all, util.RehttpDelay(GithubSleepTimeSecs, NumGithubRetries))
+	if err != nil {
+		return "", nil, errors.Wrap(err, "error getting http client")
+	}
+	defer util.PutHTTPClient(client)
+
+	u := &url.URL{
+		Scheme: "https",
+		Host:   "api.github.com",
+		Path:   fmt.Sprintf("/repos/%s/%s/compare/%s...%s", owner, repo, base, head),
+	}
+	req, err := http.NewRequest("GET", u.String(), nil)
+	if err != nil {
+		return "", nil, errors.Wrap(err, "failed to create github request")
+	}
+	req = req.WithContext(ctx)
+
+	diff, err := doGithubRequest(client, req, githubAcceptDiff)
+	if err != nil {
+		return "", nil, errors.Wrap(err, "failed to fetch diff from github")
+	}
+
+	summaries, err := GetPatchSummaries(diff)
+	if err != nil {
+		return "", nil, errors.Wrap(err, "failed to get patch summary")
+	}
+
+	return diff, summaries, nil
+}
+
 func doGithubRequest(client *http.Client, req *http.Request, accept string) (string, error) {
 	req.Header.Del("Accept")
 	req.Header.Add("Accept", accept)
@@ -0,0 +1,131 @@
+package thirdparty
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/evergreen-ci/evergreen/util"
+	"github.com/pkg/errors"
+)
+
+// GitLab commit status states
+const (
+	GitlabStatePending  = "pending"
+	GitlabStateRunning  = "running"
+	GitlabStateSuccess  = "success"
+	GitlabStateFailed   = "failed"
+	GitlabStateCanceled = "canceled"
+)
+
+// GitlabDeveloperAccess is the lowest access level of a project member that
+// can push to the project.
+const GitlabDeveloperAccess = 30
+
+// GitlabCommitStatus is a commit status in the GitLab API.
+type GitlabCommitStatus struct {
+	State       string `json:"state"`
+	Ref         string `json:"ref,omitempty"`
+	Name        string `json:"name"`
+	TargetURL   string `json:"target_url"`
+	Description string `json:"description"`
+}
+
+// SetGitlabCommitStatus creates or updates the status of a commit in a
+// GitLab project. apiURL is the base URL of the GitLab API, e.g.
+// https://gitlab.com/api/v4.
+func SetGitlabCommitStatus(ctx context.Context, apiURL, token string, projectID int, sha string, status GitlabCommitStatus) error {
+	path := fmt.Sprintf("projects/%d/statuses/%s", projectID, url.PathEscape(sha))
+	_, err := gitlabRequest(ctx, http.MethodPost, apiURL, path, token, status)
+	return errors.Wrapf(err, "problem setting status of commit '%s' in GitLab project %d", sha, projectID)
+}
+
+// CreateGitlabMergeRequestNote adds a comment to a GitLab merge request.
+func CreateGitlabMergeRequestNote(ctx context.Context, apiURL, token string, projectID, mrNumber int, body string) error {
+	path := fmt.Sprintf("projects/%d/merge_requests/%d/notes", projectID, mrNumber)
+	_, err := gitlabRequest(ctx, http.MethodPost, apiURL, path, token, struct {
+		Body string `json:"body"`
+	}{Body: body})
+	return errors.Wrapf(err, "problem commenting on merge request !%d in GitLab project %d", mrNumber, projectID)
+}
+
+// GetGitlabProjectAccessLevel returns the access level that a user has to a
+// GitLab project, including access inherited from its groups, or 0 if the
+// user isn't a member.
+func GetGitlabProjectAccessLevel(ctx context.Context, apiURL, token string, projectID, userID int) (int, error) {
+	path := fmt.Sprintf("projects/%d/members/all/%d", projectID, userID)
+	body, err := gitlabRequest(ctx, http.MethodGet, apiURL, path, token, nil)
+	if err != nil {
+		if errors.Cause(err) == errGitlabNotFound {
+			return 0, nil
+		}
+		return 0, errors.Wrapf(err, "problem getting membership of user %d in GitLab project %d", userID, projectID)
+	}
+
+	member := struct {
+		AccessLevel int `json:"access_level"`
+	}{}
+	if err = json.Unmarshal(body, &member); err != nil {
+		return 0, errors.Wrap(err, "problem parsing GitLab project member")
+	}
+
+	return member.AccessLevel, nil
+}
+
+var errGitlabNotFound = errors.New("not found")
+
+// gitlabRequest makes a request to the GitLab API with data as its JSON body
+// and returns the body of the response. Responses other than 2xx are errors.
+func gitlabRequest(ctx context.Context, method, apiURL, path, token string, data interface{}) ([]byte, error) {
+	if token == "" {
+		return nil, errors.New("no GitLab token given")
+	}
+
+	var body *bytes.Reader
+	if data != nil {
+		jsonBytes, err := json.Marshal(data)
+		if err != nil {
+			return nil, errors.WithStack(err)
+		}
+		body = bytes.NewReader(jsonBytes)
+	} else {
+		body = bytes.NewReader(nil)
+	}
+
+	req, err := http.NewRequest(method, strings.TrimSuffix(apiURL, "/")+"/"+path, body)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	req = req.WithContext(ctx)
+	req.Header.Add("PRIVATE-TOKEN", token)
+	req.Header.Add("Content-Type", "application/json")
+	req.Header.Add("Accept", "application/json")
+
+	client := util.GetHTTPClient()
+	defer util.PutHTTPClient(client)
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, errors.Wrapf(err, "problem making GitLab request to '%s'", path)
+	}
+	defer resp.Body.Close()
+
+	respBody, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return nil, errors.Wrap(err, "problem reading GitLab response")
+	}
+	if resp.StatusCode == http.StatusNotFound {
+		return nil, errGitlabNotFound
+	}
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return nil, errors.Errorf("GitLab request to '%s' failed with %d %s: %s",
+			path, resp.StatusCode, http.StatusText(resp.StatusCode), strings.TrimSpace(string(respBody)))
+	}
+
+	return respBody, nil
+}
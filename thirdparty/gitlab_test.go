@@ -0,0 +1,101 @@
+package thirdparty
+
+import (
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type gitlabTestRequest struct {
+	method string
+	path   string
+	token  string
+	body   map[string]interface{}
+}
+
+func newGitlabTestServer(t *testing.T, requests *[]gitlabTestRequest) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		req := gitlabTestRequest{
+			method: r.Method,
+			path:   r.URL.EscapedPath(),
+			token:  r.Header.Get("PRIVATE-TOKEN"),
+		}
+		body, err := ioutil.ReadAll(r.Body)
+		require.NoError(t, err)
+		if len(body) > 0 {
+			require.NoError(t, json.Unmarshal(body, &req.body))
+		}
+		*requests = append(*requests, req)
+
+		switch r.URL.Path {
+		case "/api/v4/projects/1/members/all/2":
+			_, _ = w.Write([]byte(`{"id": 2, "username": "tanuki", "access_level": 40}`))
+		case "/api/v4/projects/1/statuses/abc", "/api/v4/projects/1/merge_requests/3/notes":
+			w.WriteHeader(http.StatusCreated)
+			_, _ = w.Write([]byte(`{}`))
+		case "/api/v4/projects/2/statuses/abc":
+			w.WriteHeader(http.StatusBadRequest)
+			_, _ = w.Write([]byte(`{"message": "Cannot transition status"}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+}
+
+func TestGitlabAPI(t *testing.T) {
+	assert := assert.New(t)
+	ctx := context.Background()
+
+	requests := []gitlabTestRequest{}
+	server := newGitlabTestServer(t, &requests)
+	defer server.Close()
+	apiURL := server.URL + "/api/v4/"
+
+	t.Run("CommitStatus", func(t *testing.T) {
+		requests = requests[:0]
+		err := SetGitlabCommitStatus(ctx, apiURL, "token", 1, "abc", GitlabCommitStatus{
+			State:       GitlabStateSuccess,
+			Name:        "evergreen",
+			TargetURL:   "https://evergreen.example.com/version/v",
+			Description: "tasks succeeded",
+		})
+		assert.NoError(err)
+		require.Len(t, requests, 1)
+		assert.Equal(http.MethodPost, requests[0].method)
+		assert.Equal("/api/v4/projects/1/statuses/abc", requests[0].path)
+		assert.Equal("token", requests[0].token)
+		assert.Equal("success", requests[0].body["state"])
+		assert.Equal("evergreen", requests[0].body["name"])
+		assert.NotContains(requests[0].body, "ref")
+
+		err = SetGitlabCommitStatus(ctx, apiURL, "token", 2, "abc", GitlabCommitStatus{State: GitlabStateSuccess})
+		assert.Error(err)
+		assert.Contains(err.Error(), "Cannot transition status")
+
+		assert.Error(SetGitlabCommitStatus(ctx, apiURL, "", 1, "abc", GitlabCommitStatus{}))
+	})
+	t.Run("MergeRequestNote", func(t *testing.T) {
+		requests = requests[:0]
+		assert.NoError(CreateGitlabMergeRequestNote(ctx, apiURL, "token", 1, 3, "the patch failed"))
+		require.Len(t, requests, 1)
+		assert.Equal("/api/v4/projects/1/merge_requests/3/notes", requests[0].path)
+		assert.Equal("the patch failed", requests[0].body["body"])
+
+		assert.Error(CreateGitlabMergeRequestNote(ctx, apiURL, "token", 1, 4, "the patch failed"))
+	})
+	t.Run("AccessLevel", func(t *testing.T) {
+		level, err := GetGitlabProjectAccessLevel(ctx, apiURL, "token", 1, 2)
+		assert.NoError(err)
+		assert.Equal(40, level)
+
+		level, err = GetGitlabProjectAccessLevel(ctx, apiURL, "token", 1, 3)
+		assert.NoError(err, "non-members have no access")
+		assert.Zero(level)
+	})
+}
@@ -637,10 +637,6 @@ func PopulateLegacyRunnerJobs(env evergreen.Environment, part int) amboy.QueueOp
 		catcher := grip.NewBasicCatcher()
 		ts := util.RoundPartOfHour(part).Format(tsFormat)
 
-		if !flags.AlertsDisabled {
-			catcher.Add(queue.Put(NewLegacyAlertsRunnerJob(env, ts)))
-		}
-
 		if !flags.MonitorDisabled {
 			catcher.Add(queue.Put(NewLegacyMonitorRunnerJob(env, ts)))
 		}
@@ -655,7 +651,7 @@ func PopulatePeriodicNotificationJobs(parts int) amboy.QueueOperation {
 		if err != nil {
 			return errors.WithStack(err)
 		}
-		if flags.AlertsDisabled {
+		if flags.EventProcessingDisabled {
 			return nil
 		}
 
@@ -665,3 +661,50 @@ func PopulatePeriodicNotificationJobs(parts int) amboy.QueueOperation {
 		return catcher.Resolve()
 	}
 }
+
+// PopulateScheduledVersionJobs queues the jobs that create versions for the
+// cron schedules of projects that fired in the last few minutes. Looking
+// back more than one interval means that a late run doesn't drop a
+// schedule; the jobs' IDs keep the versions from being created twice.
+func PopulateScheduledVersionJobs() amboy.QueueOperation {
+	const lookback = 5 * time.Minute
+
+	return func(queue amboy.Queue) error {
+		flags, err := evergreen.GetServiceFlags()
+		if err != nil {
+			return errors.WithStack(err)
+		}
+		if flags.RepotrackerDisabled {
+			grip.InfoWhen(sometimes.Percent(evergreen.DegradedLoggingPercent), message.Fields{
+				"message": "repotracker is disabled",
+				"impact":  "scheduled versions disabled",
+				"mode":    "degraded",
+			})
+			return nil
+		}
+
+		projects, err := model.FindAllTrackedProjectRefs()
+		if err != nil {
+			return errors.WithStack(err)
+		}
+
+		now := time.Now().UTC()
+		catcher := grip.NewBasicCatcher()
+		for _, proj := range projects {
+			if !proj.Enabled || len(proj.CronSchedules) == 0 {
+				continue
+			}
+
+			for ts := now.Add(-lookback).Truncate(time.Minute); !ts.After(now); ts = ts.Add(time.Minute) {
+				variants, all := proj.ScheduledBuildVariants(ts)
+				if !all && len(variants) == 0 {
+					continue
+				}
+
+				catcher.Add(queue.Put(NewScheduledVersionJob(proj.Identifier, ts, variants)))
+			}
+		}
+
+		return catcher.Resolve()
+	}
+}
@@ -6,6 +6,7 @@ import (
 	"time"
 
 	"github.com/evergreen-ci/evergreen"
+	"github.com/evergreen-ci/evergreen/model"
 	"github.com/evergreen-ci/evergreen/model/event"
 	"github.com/evergreen-ci/evergreen/model/notification"
 	"github.com/evergreen-ci/evergreen/model/trigger"
@@ -124,6 +125,45 @@ func tryProcessOneEvent(e *event.EventLogEntry) (n []notification.Notification,
 	return n, err
 }
 
+// tryCreateDownstreamVersions creates the versions of downstream projects
+// triggered by an event.
+func tryCreateDownstreamVersions(e *event.EventLogEntry) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = errors.Errorf("panicked while creating downstream versions for event %s", e.ID)
+			grip.Alert(message.WrapError(err, message.Fields{
+				"job":         eventMetaJobName,
+				"source":      "events-processing",
+				"event_id":    e.ID,
+				"event_type":  e.ResourceType,
+				"panic_value": r,
+			}))
+		}
+	}()
+
+	versions, err := model.CreateDownstreamVersions(e)
+	for _, v := range versions {
+		grip.Info(message.Fields{
+			"job":        eventMetaJobName,
+			"source":     "events-processing",
+			"message":    "created downstream version",
+			"event_id":   e.ID,
+			"upstream":   v.TriggerID,
+			"downstream": v.Id,
+			"project":    v.Identifier,
+		})
+	}
+	grip.Error(message.WrapError(err, message.Fields{
+		"job":        eventMetaJobName,
+		"source":     "events-processing",
+		"message":    "errors creating downstream versions for event",
+		"event_id":   e.ID,
+		"event_type": e.ResourceType,
+	}))
+
+	return err
+}
+
 func (j *eventMetaJob) dispatchLoop(ctx context.Context) error {
 	// TODO: if this is a perf problem, it could be multithreaded. For now,
 	// we just log time
@@ -141,6 +181,10 @@ func (j *eventMetaJob) dispatchLoop(ctx context.Context) error {
 		notifications[i], err = tryProcessOneEvent(&j.events[i])
 		catcher.Add(err)
 
+		if !j.flags.RepotrackerDisabled {
+			catcher.Add(tryCreateDownstreamVersions(&j.events[i]))
+		}
+
 		for _, n := range notifications[i] {
 			catcher.Add(bulk.Append(n))
 		}
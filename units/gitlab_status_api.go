@@ -0,0 +1,354 @@
+package units
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/evergreen-ci/evergreen"
+	"github.com/evergreen-ci/evergreen/model"
+	"github.com/evergreen-ci/evergreen/model/build"
+	"github.com/evergreen-ci/evergreen/model/patch"
+	"github.com/evergreen-ci/evergreen/thirdparty"
+	"github.com/mongodb/amboy"
+	"github.com/mongodb/amboy/dependency"
+	"github.com/mongodb/amboy/job"
+	"github.com/mongodb/amboy/registry"
+	"github.com/mongodb/grip"
+	"github.com/mongodb/grip/message"
+	"github.com/mongodb/grip/sometimes"
+	"github.com/pkg/errors"
+	"gopkg.in/mgo.v2/bson"
+)
+
+const (
+	gitlabStatusUpdateJobName = "gitlab-status-update"
+
+	gitlabUpdateTypeNewPatch      = "new-patch"
+	gitlabUpdateTypeRequestAuth   = "request-auth"
+	gitlabUpdateTypeBadConfig     = "bad-config"
+	gitlabUpdateTypeFinishedPatch = "finished-patch"
+	gitlabUpdateTypeFinishedBuild = "finished-build"
+
+	gitlabStatusName = "evergreen"
+)
+
+func init() {
+	registry.AddJobType(gitlabStatusUpdateJobName, func() amboy.Job { return makeGitlabStatusUpdateJob() })
+}
+
+type gitlabStatusUpdateJob struct {
+	job.Base `bson:"job_base" json:"job_base" yaml:"job_base"`
+	env      evergreen.Environment
+	urlBase  string
+	token    string
+
+	FetchID    string `bson:"fetch_id" json:"fetch_id" yaml:"fetch_id"`
+	UpdateType string `bson:"update_type" json:"update_type" yaml:"update_type"`
+}
+
+// gitlabStatusUpdate is a commit status to set on the head of a merge
+// request, and an optional note to add to the merge request.
+type gitlabStatusUpdate struct {
+	data       patch.GitlabPatch
+	projectRef *model.ProjectRef
+	status     thirdparty.GitlabCommitStatus
+	note       string
+}
+
+func makeGitlabStatusUpdateJob() *gitlabStatusUpdateJob {
+	j := &gitlabStatusUpdateJob{
+		Base: job.Base{
+			JobType: amboy.JobType{
+				Name:    gitlabStatusUpdateJobName,
+				Version: 0,
+			},
+		},
+	}
+	j.SetDependency(dependency.NewAlways())
+	j.SetPriority(1)
+	return j
+}
+
+func newGitlabStatusUpdateJob(updateType, fetchID string) *gitlabStatusUpdateJob {
+	j := makeGitlabStatusUpdateJob()
+	j.FetchID = fetchID
+	j.UpdateType = updateType
+
+	j.SetID(fmt.Sprintf("%s:%s-%s-%s", gitlabStatusUpdateJobName, updateType, fetchID, time.Now().String()))
+	return j
+}
+
+// NewGitlabStatusUpdateJobForNewPatch creates a job to report a newly
+// created merge request patch as pending on GitLab.
+func NewGitlabStatusUpdateJobForNewPatch(patchID string) amboy.Job {
+	return newGitlabStatusUpdateJob(gitlabUpdateTypeNewPatch, patchID)
+}
+
+// NewGitlabStatusUpdateJobForExternalPatch reports on GitLab that a user
+// must manually authorize this patch.
+func NewGitlabStatusUpdateJobForExternalPatch(patchID string) amboy.Job {
+	return newGitlabStatusUpdateJob(gitlabUpdateTypeRequestAuth, patchID)
+}
+
+// NewGitlabStatusUpdateJobForBadConfig marks a merge request as failed
+// because its evergreen configuration is bad.
+func NewGitlabStatusUpdateJobForBadConfig(intentID string) amboy.Job {
+	return newGitlabStatusUpdateJob(gitlabUpdateTypeBadConfig, intentID)
+}
+
+// NewGitlabStatusUpdateJobForFinishedPatch reports the outcome of a merge
+// request patch on GitLab, as a commit status and a note on the merge
+// request.
+func NewGitlabStatusUpdateJobForFinishedPatch(patchID string) amboy.Job {
+	return newGitlabStatusUpdateJob(gitlabUpdateTypeFinishedPatch, patchID)
+}
+
+// NewGitlabStatusUpdateJobForFinishedBuild reports the outcome of a build of
+// a merge request patch on GitLab, as a commit status for the variant.
+func NewGitlabStatusUpdateJobForFinishedBuild(buildID string) amboy.Job {
+	return newGitlabStatusUpdateJob(gitlabUpdateTypeFinishedBuild, buildID)
+}
+
+func (j *gitlabStatusUpdateJob) preamble() error {
+	if j.env == nil {
+		j.env = evergreen.GetEnvironment()
+	}
+	uiConfig := evergreen.UIConfig{}
+	if err := uiConfig.Get(); err != nil {
+		return err
+	}
+	j.urlBase = uiConfig.Url
+	if len(j.urlBase) == 0 {
+		return errors.New("UI URL is empty")
+	}
+
+	if j.token == "" {
+		var err error
+		j.token, err = j.env.Settings().GetGitlabToken()
+		if err != nil {
+			return err
+		}
+	}
+
+	flags, err := evergreen.GetServiceFlags()
+	if err != nil {
+		return errors.Wrap(err, "error retrieving admin settings")
+	}
+	if flags.GitlabStatusAPIDisabled {
+		grip.InfoWhen(sometimes.Percent(evergreen.DegradedLoggingPercent), message.Fields{
+			"job":     gitlabStatusUpdateJobName,
+			"message": "gitlab status updates are disabled, not updating status",
+		})
+		return errors.New("gitlab status updates are disabled, not updating status")
+	}
+
+	return nil
+}
+
+func (j *gitlabStatusUpdateJob) fetch() (*gitlabStatusUpdate, error) {
+	update := &gitlabStatusUpdate{
+		status: thirdparty.GitlabCommitStatus{Name: gitlabStatusName},
+	}
+
+	switch j.UpdateType {
+	case gitlabUpdateTypeBadConfig:
+		intent, err := patch.FindIntent(j.FetchID, patch.GitlabIntentType)
+		if err != nil {
+			return nil, errors.Wrap(err, "can't fetch patch intent")
+		}
+		update.data = intent.NewPatch().GitlabPatchData
+
+		projectRef, err := model.FindOneProjectRefByRepoURLAndBranchWithPRTesting(update.data.RepoURLs, update.data.BaseBranch)
+		if err != nil {
+			return nil, errors.Wrap(err, "can't fetch project ref")
+		}
+		if projectRef == nil {
+			return nil, errors.New("can't find project ref")
+		}
+		update.projectRef = projectRef
+
+		update.status.TargetURL = fmt.Sprintf("%s/waterfall/%s", j.urlBase, projectRef.Identifier)
+		update.status.State = thirdparty.GitlabStateFailed
+		update.status.Description = "project config was invalid"
+
+	case gitlabUpdateTypeNewPatch, gitlabUpdateTypeRequestAuth, gitlabUpdateTypeFinishedPatch:
+		patchDoc, err := findGitlabPatch(j.FetchID)
+		if err != nil {
+			return nil, err
+		}
+		update.data = patchDoc.GitlabPatchData
+		if update.projectRef, err = findGitlabPatchProjectRef(patchDoc); err != nil {
+			return nil, err
+		}
+
+		switch j.UpdateType {
+		case gitlabUpdateTypeNewPatch:
+			update.status.TargetURL = fmt.Sprintf("%s/version/%s", j.urlBase, j.FetchID)
+			update.status.State = thirdparty.GitlabStatePending
+			update.status.Description = "preparing to run tasks"
+
+		case gitlabUpdateTypeRequestAuth:
+			update.status.TargetURL = fmt.Sprintf("%s/patch/%s", j.urlBase, j.FetchID)
+			update.status.State = thirdparty.GitlabStateFailed
+			update.status.Description = "patch must be manually authorized"
+			update.note = fmt.Sprintf("Evergreen won't test this merge request until a project member [authorizes the patch](%s).",
+				update.status.TargetURL)
+
+		case gitlabUpdateTypeFinishedPatch:
+			if err = j.finishedPatchUpdate(patchDoc, update); err != nil {
+				return nil, err
+			}
+		}
+
+	case gitlabUpdateTypeFinishedBuild:
+		b, err := build.FindOne(build.ById(j.FetchID))
+		if err != nil {
+			return nil, errors.Wrap(err, "can't fetch build")
+		}
+		if b == nil {
+			return nil, errors.New("can't find build")
+		}
+		if !b.IsFinished() {
+			return nil, errors.Errorf("build '%s' is not finished", b.Id)
+		}
+		patchDoc, err := findGitlabPatch(b.Version)
+		if err != nil {
+			return nil, err
+		}
+		update.data = patchDoc.GitlabPatchData
+		if update.projectRef, err = findGitlabPatchProjectRef(patchDoc); err != nil {
+			return nil, err
+		}
+
+		update.status.Name = fmt.Sprintf("%s/%s", gitlabStatusName, b.BuildVariant)
+		update.status.TargetURL = fmt.Sprintf("%s/build/%s", j.urlBase, b.Id)
+		update.status.State = thirdparty.GitlabStateFailed
+		if b.Status == evergreen.BuildSucceeded {
+			update.status.State = thirdparty.GitlabStateSuccess
+		}
+		update.status.Description = gitlabBuildDescription(b)
+
+	default:
+		return nil, errors.Errorf("unknown update type '%s'", j.UpdateType)
+	}
+
+	return update, nil
+}
+
+func (j *gitlabStatusUpdateJob) finishedPatchUpdate(patchDoc *patch.Patch, update *gitlabStatusUpdate) error {
+	var state, outcome string
+	switch patchDoc.Status {
+	case evergreen.PatchSucceeded:
+		state, outcome = thirdparty.GitlabStateSuccess, "succeeded"
+	case evergreen.PatchFailed:
+		state, outcome = thirdparty.GitlabStateFailed, "failed"
+	default:
+		return errors.Errorf("patch '%s' is not finished", patchDoc.Id.Hex())
+	}
+
+	duration := patchDoc.FinishTime.Sub(patchDoc.StartTime)
+	update.status.TargetURL = fmt.Sprintf("%s/version/%s", j.urlBase, patchDoc.Version)
+	update.status.State = state
+	update.status.Description = fmt.Sprintf("patch finished in %s", duration.String())
+
+	note := fmt.Sprintf("Evergreen patch [%s](%s) in %s.", outcome, update.status.TargetURL, duration.String())
+	if state == thirdparty.GitlabStateFailed {
+		builds, err := build.Find(build.ByVersion(patchDoc.Version))
+		if err != nil {
+			return errors.Wrap(err, "can't fetch builds")
+		}
+		failed := []string{}
+		for _, b := range builds {
+			if b.Status == evergreen.BuildFailed {
+				failed = append(failed, fmt.Sprintf("[%s](%s/build/%s)", b.DisplayName, j.urlBase, b.Id))
+			}
+		}
+		if len(failed) > 0 {
+			note += " Failed variants: " + strings.Join(failed, ", ") + "."
+		}
+	}
+	update.note = note
+
+	return nil
+}
+
+func findGitlabPatch(id string) (*patch.Patch, error) {
+	if !bson.IsObjectIdHex(id) {
+		return nil, errors.Errorf("'%s' is not a valid patch ID", id)
+	}
+	patchDoc, err := patch.FindOne(patch.ById(bson.ObjectIdHex(id)))
+	if err != nil {
+		return nil, errors.Wrap(err, "can't fetch patch")
+	}
+	if patchDoc == nil {
+		return nil, errors.New("can't find patch")
+	}
+	if !patchDoc.IsGitlabMRPatch() {
+		return nil, errors.Errorf("patch '%s' is not for a GitLab merge request", id)
+	}
+
+	return patchDoc, nil
+}
+
+func findGitlabPatchProjectRef(patchDoc *patch.Patch) (*model.ProjectRef, error) {
+	projectRef, err := model.FindOneProjectRef(patchDoc.Project)
+	if err != nil {
+		return nil, errors.Wrap(err, "can't fetch project ref")
+	}
+	if projectRef == nil {
+		return nil, errors.Errorf("can't find project ref '%s'", patchDoc.Project)
+	}
+
+	return projectRef, nil
+}
+
+// gitlabBuildDescription summarizes the outcome of the tasks in a build.
+func gitlabBuildDescription(b *build.Build) string {
+	succeeded, failed := 0, 0
+	for _, t := range b.Tasks {
+		switch t.Status {
+		case evergreen.TaskSucceeded:
+			succeeded++
+		case evergreen.TaskFailed:
+			failed++
+		}
+	}
+	if succeeded == 0 && failed == 0 {
+		return "no tasks were run"
+	}
+
+	return fmt.Sprintf("%d succeeded, %d failed", succeeded, failed)
+}
+
+func (j *gitlabStatusUpdateJob) Run(ctx context.Context) {
+	defer j.MarkComplete()
+
+	j.AddError(j.preamble())
+	if j.HasErrors() {
+		return
+	}
+
+	update, err := j.fetch()
+	if err != nil {
+		j.AddError(err)
+		return
+	}
+
+	apiURL, err := update.projectRef.GitlabAPIURL(update.data)
+	if err != nil {
+		j.AddError(err)
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, 30*time.Second)
+	defer cancel()
+
+	j.AddError(thirdparty.SetGitlabCommitStatus(ctx, apiURL, j.token, update.data.ProjectID,
+		update.data.HeadHash, update.status))
+	if update.note != "" {
+		j.AddError(thirdparty.CreateGitlabMergeRequestNote(ctx, apiURL, j.token, update.data.ProjectID,
+			update.data.MRNumber, update.note))
+	}
+}
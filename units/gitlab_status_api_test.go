@@ -0,0 +1,231 @@
+package units
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/evergreen-ci/evergreen"
+	"github.com/evergreen-ci/evergreen/db"
+	"github.com/evergreen-ci/evergreen/mock"
+	"github.com/evergreen-ci/evergreen/model"
+	"github.com/evergreen-ci/evergreen/model/build"
+	"github.com/evergreen-ci/evergreen/model/patch"
+	"github.com/evergreen-ci/evergreen/testutil"
+	"github.com/evergreen-ci/evergreen/thirdparty"
+	"github.com/mongodb/amboy"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+	"gopkg.in/mgo.v2/bson"
+)
+
+type gitlabRecordedRequest struct {
+	path string
+	body map[string]interface{}
+}
+
+type gitlabStatusUpdateSuite struct {
+	env      *mock.Environment
+	patchDoc *patch.Patch
+	buildDoc *build.Build
+	server   *httptest.Server
+	requests []gitlabRecordedRequest
+	cancel   context.CancelFunc
+
+	suite.Suite
+}
+
+func TestGitlabStatusUpdate(t *testing.T) {
+	suite.Run(t, new(gitlabStatusUpdateSuite))
+}
+
+func (s *gitlabStatusUpdateSuite) SetupSuite() {
+	db.SetGlobalSessionProvider(testutil.TestConfig().SessionFactory())
+}
+
+func (s *gitlabStatusUpdateSuite) SetupTest() {
+	s.NoError(db.ClearCollections(evergreen.ConfigCollection, patch.Collection, patch.IntentCollection, build.Collection,
+		model.ProjectRefCollection))
+
+	uiConfig := evergreen.UIConfig{}
+	uiConfig.Url = "https://example.com"
+	s.Require().NoError(uiConfig.Set())
+
+	ctx, cancel := context.WithCancel(context.Background())
+	s.cancel = cancel
+
+	s.env = &mock.Environment{}
+	s.Require().NoError(s.env.Configure(ctx, filepath.Join(evergreen.FindEvergreenHome(), testutil.TestDir, testutil.TestSettings), nil))
+
+	s.requests = nil
+	s.server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		req := gitlabRecordedRequest{path: r.URL.Path}
+		body, _ := ioutil.ReadAll(r.Body)
+		_ = json.Unmarshal(body, &req.body)
+		s.requests = append(s.requests, req)
+		w.WriteHeader(http.StatusCreated)
+		_, _ = w.Write([]byte("{}"))
+	}))
+
+	startTime := time.Now().Truncate(time.Millisecond)
+	id := bson.NewObjectId()
+	s.patchDoc = &patch.Patch{
+		Id:         id,
+		Project:    "evergreen",
+		Version:    id.Hex(),
+		Status:     evergreen.PatchFailed,
+		StartTime:  startTime,
+		FinishTime: startTime.Add(10 * time.Minute),
+		GitlabPatchData: patch.GitlabPatch{
+			MRNumber:    7,
+			ProjectID:   42,
+			ProjectPath: "group/evergreen",
+			ProjectURL:  s.server.URL + "/group/evergreen",
+			BaseBranch:  "master",
+			HeadHash:    "67da19930b1b18d346477e99a8e18094a672f48a",
+			Author:      "tanuki",
+			AuthorID:    12,
+		},
+	}
+
+	s.buildDoc = &build.Build{
+		Id:           bson.NewObjectId().Hex(),
+		BuildVariant: "testvariant",
+		DisplayName:  "Test Variant",
+		Version:      s.patchDoc.Version,
+		Status:       evergreen.BuildFailed,
+		Tasks: []build.TaskCache{
+			{Id: "t1", Status: evergreen.TaskSucceeded},
+			{Id: "t2", Status: evergreen.TaskFailed},
+		},
+	}
+
+	s.NoError(s.patchDoc.Insert())
+	s.NoError(s.buildDoc.Insert())
+	s.NoError((&model.ProjectRef{
+		Identifier: "evergreen",
+		RepoKind:   model.GitRepoType,
+		RepoURL:    s.server.URL + "/group/evergreen.git",
+		Branch:     "master",
+	}).Insert())
+}
+
+func (s *gitlabStatusUpdateSuite) TearDownTest() {
+	s.server.Close()
+	s.cancel()
+	evergreen.ResetEnvironment()
+}
+
+func (s *gitlabStatusUpdateSuite) runJob(j amboy.Job) {
+	job, ok := j.(*gitlabStatusUpdateJob)
+	s.Require().True(ok)
+	job.env = s.env
+	job.token = "token"
+	job.Run(context.Background())
+	s.NoError(job.Error())
+}
+
+func (s *gitlabStatusUpdateSuite) statusPath() string {
+	return fmt.Sprintf("/api/v4/projects/42/statuses/%s", s.patchDoc.GitlabPatchData.HeadHash)
+}
+
+func (s *gitlabStatusUpdateSuite) TestRunInDegradedMode() {
+	s.Require().NoError(evergreen.SetServiceFlags(evergreen.ServiceFlags{GitlabStatusAPIDisabled: true}))
+
+	job, ok := NewGitlabStatusUpdateJobForNewPatch(s.patchDoc.Version).(*gitlabStatusUpdateJob)
+	s.Require().True(ok)
+	job.env = s.env
+	job.token = "token"
+	job.Run(context.Background())
+
+	s.Error(job.Error())
+	s.Contains(job.Error().Error(), "gitlab status updates are disabled, not updating status")
+	s.Empty(s.requests)
+}
+
+func (s *gitlabStatusUpdateSuite) TestForPatchCreated() {
+	s.runJob(NewGitlabStatusUpdateJobForNewPatch(s.patchDoc.Version))
+
+	s.Require().Len(s.requests, 1)
+	s.Equal(s.statusPath(), s.requests[0].path)
+	s.Equal(thirdparty.GitlabStatePending, s.requests[0].body["state"])
+	s.Equal("evergreen", s.requests[0].body["name"])
+	s.Equal("preparing to run tasks", s.requests[0].body["description"])
+	s.Equal(fmt.Sprintf("https://example.com/version/%s", s.patchDoc.Version), s.requests[0].body["target_url"])
+}
+
+func (s *gitlabStatusUpdateSuite) TestForExternalPatch() {
+	s.runJob(NewGitlabStatusUpdateJobForExternalPatch(s.patchDoc.Version))
+
+	s.Require().Len(s.requests, 2)
+	s.Equal(s.statusPath(), s.requests[0].path)
+	s.Equal(thirdparty.GitlabStateFailed, s.requests[0].body["state"])
+	s.Equal("patch must be manually authorized", s.requests[0].body["description"])
+	s.Equal("/api/v4/projects/42/merge_requests/7/notes", s.requests[1].path)
+	s.Contains(s.requests[1].body["body"], fmt.Sprintf("https://example.com/patch/%s", s.patchDoc.Version))
+}
+
+func (s *gitlabStatusUpdateSuite) TestForFinishedPatch() {
+	s.runJob(NewGitlabStatusUpdateJobForFinishedPatch(s.patchDoc.Version))
+
+	s.Require().Len(s.requests, 2)
+	s.Equal(s.statusPath(), s.requests[0].path)
+	s.Equal(thirdparty.GitlabStateFailed, s.requests[0].body["state"])
+	s.Equal("patch finished in 10m0s", s.requests[0].body["description"])
+	s.Equal("/api/v4/projects/42/merge_requests/7/notes", s.requests[1].path)
+	s.Contains(s.requests[1].body["body"], "Evergreen patch [failed]")
+	s.Contains(s.requests[1].body["body"], fmt.Sprintf("[Test Variant](https://example.com/build/%s)", s.buildDoc.Id))
+}
+
+func (s *gitlabStatusUpdateSuite) TestForFinishedBuild() {
+	s.runJob(NewGitlabStatusUpdateJobForFinishedBuild(s.buildDoc.Id))
+
+	s.Require().Len(s.requests, 1)
+	s.Equal(s.statusPath(), s.requests[0].path)
+	s.Equal(thirdparty.GitlabStateFailed, s.requests[0].body["state"])
+	s.Equal("evergreen/testvariant", s.requests[0].body["name"])
+	s.Equal("1 succeeded, 1 failed", s.requests[0].body["description"])
+	s.Equal(fmt.Sprintf("https://example.com/build/%s", s.buildDoc.Id), s.requests[0].body["target_url"])
+}
+
+func (s *gitlabStatusUpdateSuite) TestProjectURLOnOtherHost() {
+	// the webhook's project URL points at the test server, which isn't the
+	// host of the project's repository
+	projectRef := &model.ProjectRef{
+		Identifier: "evergreen",
+		RepoKind:   model.GitRepoType,
+		RepoURL:    "https://gitlab.example.com/group/evergreen.git",
+		Branch:     "master",
+	}
+	s.Require().NoError(projectRef.Upsert())
+
+	job, ok := NewGitlabStatusUpdateJobForNewPatch(s.patchDoc.Version).(*gitlabStatusUpdateJob)
+	s.Require().True(ok)
+	job.env = s.env
+	job.token = "token"
+	job.Run(context.Background())
+
+	s.Error(job.Error())
+	s.Empty(s.requests)
+}
+
+func TestGitlabBuildDescription(t *testing.T) {
+	assert := assert.New(t)
+
+	b := &build.Build{}
+	assert.Equal("no tasks were run", gitlabBuildDescription(b))
+
+	b.Tasks = []build.TaskCache{
+		{Status: evergreen.TaskSucceeded},
+		{Status: evergreen.TaskSucceeded},
+		{Status: evergreen.TaskFailed},
+		{Status: evergreen.TaskUndispatched},
+	}
+	assert.Equal("2 succeeded, 1 failed", gitlabBuildDescription(b))
+}
@@ -128,7 +128,7 @@ func (j *hostMonitorExternalStateCheckJob) Run(ctx context.Context) {
 
 		j.AddError(model.ClearAndResetStrandedTask(j.host))
 
-		event.LogHostTerminatedExternally(j.HostID)
+		event.LogHostTerminatedExternally(j.HostID, j.host.Status)
 
 		// the instance was terminated from outside our control
 		j.AddError(errors.Wrapf(j.host.SetTerminated("external"), "error setting host %s terminated", j.HostID))
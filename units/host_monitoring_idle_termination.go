@@ -7,6 +7,7 @@ import (
 
 	"github.com/evergreen-ci/evergreen"
 	"github.com/evergreen-ci/evergreen/cloud"
+	"github.com/evergreen-ci/evergreen/model/event"
 	"github.com/evergreen-ci/evergreen/model/host"
 	"github.com/mongodb/amboy"
 	"github.com/mongodb/amboy/dependency"
@@ -26,6 +27,10 @@ const (
 
 	// MaxTimeNextPayment is the amount of time we wait to have left before marking a host as idle
 	maxTimeTilNextPayment = 5 * time.Minute
+
+	// idleEventInterval is the minimum amount of time between idle
+	// events logged for a host that remains idle.
+	idleEventInterval = 30 * time.Minute
 )
 
 func init() {
@@ -118,6 +123,9 @@ func (j *idleHostJob) Run(ctx context.Context) {
 	tilNextPayment := manager.TimeTilNextPayment(j.host)
 
 	if tilNextPayment > maxTimeTilNextPayment {
+		if idleTime >= idleTimeCutoff && !j.host.IsWaitingForAgent() {
+			j.AddError(j.logIdleEvent(idleTime))
+		}
 		return
 	}
 
@@ -142,3 +150,21 @@ func (j *idleHostJob) Run(ctx context.Context) {
 		j.AddError(tjob.Error())
 	}
 }
+
+// logIdleEvent records that the host is idle, unless an idle event has
+// already been logged for the host recently during this idle period.
+func (j *idleHostJob) logIdleEvent(idleTime time.Duration) error {
+	events, err := event.Find(event.AllLogCollection, event.MostRecentHostEventsOfType(j.host.Id, event.EventHostIdle, 1))
+	if err != nil {
+		return errors.Wrapf(err, "error finding idle events for host %s", j.host.Id)
+	}
+	if len(events) != 0 {
+		last := events[0].Timestamp
+		if last.After(j.host.LastTaskCompletedTime) && time.Since(last) < idleEventInterval {
+			return nil
+		}
+	}
+
+	event.LogHostIdle(j.host.Id, idleTime)
+	return nil
+}
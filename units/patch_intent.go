@@ -14,6 +14,7 @@ import (
 	"github.com/evergreen-ci/evergreen/model/event"
 	"github.com/evergreen-ci/evergreen/model/patch"
 	"github.com/evergreen-ci/evergreen/model/user"
+	"github.com/evergreen-ci/evergreen/repotracker"
 	"github.com/evergreen-ci/evergreen/thirdparty"
 	"github.com/evergreen-ci/evergreen/util"
 	"github.com/evergreen-ci/evergreen/validator"
@@ -49,6 +50,10 @@ type patchIntentProcessor struct {
 
 	user   *user.DBUser
 	intent patch.Intent
+
+	// finishErr is the error that kept the patch from being created, with
+	// its type, which the job's errors don't keep
+	finishErr error
 }
 
 // NewPatchIntentProcessor creates an amboy job to create a patch from the
@@ -64,6 +69,19 @@ func NewPatchIntentProcessor(patchID bson.ObjectId, intent patch.Intent) amboy.J
 	return j
 }
 
+// ProcessPatchIntent creates the patch of an intent right away, instead of
+// on a queue. Conflicts of the patch's changes are returned as a
+// model.PatchConflictError, and other errors as the job's errors.
+func ProcessPatchIntent(ctx context.Context, patchID bson.ObjectId, intent patch.Intent) error {
+	j := NewPatchIntentProcessor(patchID, intent).(*patchIntentProcessor)
+	j.Run(ctx)
+
+	if model.IsPatchConflict(j.finishErr) {
+		return j.finishErr
+	}
+	return j.Error()
+}
+
 func makePatchIntentProcessor() *patchIntentProcessor {
 	j := &patchIntentProcessor{
 		Base: job.Base{
@@ -88,8 +106,10 @@ func (j *patchIntentProcessor) Run(ctx context.Context) {
 		j.env = evergreen.GetEnvironment()
 	}
 
+	// merge requests are read from the project's mirror, so they don't need
+	// to talk to GitHub
 	githubOauthToken, err := j.env.Settings().GetGithubOauthToken()
-	if err != nil {
+	if err != nil && j.IntentType != patch.GitlabIntentType {
 		j.AddError(err)
 	}
 	if j.intent == nil {
@@ -104,11 +124,20 @@ func (j *patchIntentProcessor) Run(ctx context.Context) {
 	patchDoc := j.intent.NewPatch()
 
 	if err = j.finishPatch(ctx, patchDoc, githubOauthToken); err != nil {
+		j.finishErr = err
 		j.AddError(err)
-		if j.IntentType == patch.GithubIntentType && strings.HasPrefix(err.Error(), errInvalidPatchedConfig) {
-			update := NewGithubStatusUpdateJobForBadConfig(j.intent.ID())
-			update.Run(ctx)
-			j.AddError(update.Error())
+		if strings.HasPrefix(err.Error(), errInvalidPatchedConfig) {
+			var update amboy.Job
+			switch j.IntentType {
+			case patch.GithubIntentType:
+				update = NewGithubStatusUpdateJobForBadConfig(j.intent.ID())
+			case patch.GitlabIntentType:
+				update = NewGitlabStatusUpdateJobForBadConfig(j.intent.ID())
+			}
+			if update != nil {
+				update.Run(ctx)
+				j.AddError(update.Error())
+			}
 		}
 		return
 	}
@@ -138,6 +167,21 @@ func (j *patchIntentProcessor) Run(ctx context.Context) {
 			patchDoc.GithubPatchData.BaseOwner, patchDoc.GithubPatchData.BaseRepo,
 			patchDoc.GithubPatchData.PRNumber))
 	}
+
+	if j.IntentType == patch.GitlabIntentType {
+		var update amboy.Job
+		if len(patchDoc.Version) == 0 {
+			update = NewGitlabStatusUpdateJobForExternalPatch(patchDoc.Id.Hex())
+
+		} else {
+			update = NewGitlabStatusUpdateJobForNewPatch(patchDoc.Id.Hex())
+		}
+		update.Run(ctx)
+		j.AddError(update.Error())
+
+		j.AddError(model.AbortPatchesWithGitlabPatchData(patchDoc.CreateTime,
+			patchDoc.GitlabPatchData.ProjectID, patchDoc.GitlabPatchData.MRNumber))
+	}
 }
 
 func (j *patchIntentProcessor) finishPatch(ctx context.Context, patchDoc *patch.Patch, githubOauthToken string) error {
@@ -153,6 +197,10 @@ func (j *patchIntentProcessor) finishPatch(ctx context.Context, patchDoc *patch.
 		canFinalize, err = j.buildGithubPatchDoc(ctx, patchDoc, githubOauthToken)
 		catcher.Add(err)
 
+	case patch.GitlabIntentType:
+		canFinalize, err = j.buildGitlabPatchDoc(ctx, patchDoc)
+		catcher.Add(err)
+
 	default:
 		return errors.Errorf("Intent type '%s' is unknown", j.IntentType)
 	}
@@ -431,25 +479,145 @@ func findEvergreenUserForPR(githubUID int) (*user.DBUser, error) {
 	}
 
 	// Otherwise, use the github patch user
-	u, err = user.FindOne(user.ById(evergreen.GithubPatchUser))
+	return findOrCreatePatchUser(evergreen.GithubPatchUser, "Github Pull Requests")
+}
+
+// findOrCreatePatchUser returns the user that owns patches created
+// automatically from a code review system, creating it if it doesn't exist.
+func findOrCreatePatchUser(id, displayName string) (*user.DBUser, error) {
+	u, err := user.FindOne(user.ById(id))
 	if err != nil {
 		return u, err
 	}
 	// and if that user doesn't exist, make it
 	if u == nil {
 		u = &user.DBUser{
-			Id:       evergreen.GithubPatchUser,
-			DispName: "Github Pull Requests",
+			Id:       id,
+			DispName: displayName,
 			APIKey:   util.RandomString(),
 		}
 		if err = u.Insert(); err != nil {
-			return nil, errors.Wrap(err, "failed to create github pull request user")
+			return nil, errors.Wrapf(err, "failed to create user '%s'", id)
 		}
 	}
 
 	return u, err
 }
 
+func (j *patchIntentProcessor) buildGitlabPatchDoc(ctx context.Context, patchDoc *patch.Patch) (bool, error) {
+	flags, err := evergreen.GetServiceFlags()
+	if err != nil {
+		return false, errors.Wrap(err, "gitlab mr testing is disabled, error retrieving admin settings")
+	}
+	if flags.GitlabMRTestingDisabled {
+		grip.InfoWhen(sometimes.Percent(evergreen.DegradedLoggingPercent), message.Fields{
+			"job":     patchIntentJobName,
+			"message": "gitlab mr testing is disabled, not processing merge request",
+
+			"intent_type": j.IntentType,
+			"intent_id":   j.IntentID,
+		})
+		return false, errors.New("gitlab mr testing is disabled, not processing merge request")
+	}
+	defer j.intent.SetProcessed()
+
+	data := patchDoc.GitlabPatchData
+	projectRef, err := model.FindOneProjectRefByRepoURLAndBranchWithPRTesting(data.RepoURLs, data.BaseBranch)
+	if err != nil {
+		return false, errors.Wrapf(err, "Could not fetch project ref for GitLab project '%s' with branch '%s'",
+			data.ProjectPath, data.BaseBranch)
+	}
+	if projectRef == nil {
+		return false, errors.Errorf("Could not find project ref for GitLab project '%s' with branch '%s'",
+			data.ProjectPath, data.BaseBranch)
+	}
+
+	isMember, err := authGitlabMergeRequest(ctx, projectRef, data, j.env.Settings())
+	if err != nil {
+		grip.Error(message.WrapError(err, message.Fields{
+			"message":     "gitlab API failure",
+			"source":      "patch intents",
+			"job":         j.ID(),
+			"patch_id":    j.PatchID,
+			"project":     data.ProjectPath,
+			"mr_number":   data.MRNumber,
+			"intent_type": j.IntentType,
+			"intent_id":   j.IntentID,
+		}))
+		return false, err
+	}
+
+	poller := repotracker.NewGitRepositoryPoller(projectRef, j.env.Settings().RepoTracker.MirrorDirectory)
+	base, patchContent, err := poller.GetGitlabMergeRequestDiff(ctx, data.MRNumber, data.HeadHash)
+	if err != nil {
+		return isMember, err
+	}
+	summaries, err := thirdparty.GetPatchSummaries(patchContent)
+	if err != nil {
+		return isMember, err
+	}
+
+	patchDoc.Githash = base
+	patchFileID := fmt.Sprintf("%s_%s", patchDoc.Id.Hex(), patchDoc.Githash)
+	patchDoc.Patches = append(patchDoc.Patches, patch.ModulePatch{
+		ModuleName: "",
+		Githash:    patchDoc.Githash,
+		PatchSet: patch.PatchSet{
+			PatchFileId: patchFileID,
+			Summary:     summaries,
+		},
+	})
+	patchDoc.Project = projectRef.Identifier
+
+	if err = db.WriteGridFile(patch.GridFSPrefix, patchFileID, strings.NewReader(patchContent)); err != nil {
+		return isMember, errors.Wrap(err, "failed to write patch file to db")
+	}
+
+	// the project's config is read from the head of the merge request, since
+	// the patched config can't be fetched from GitHub
+	project, err := poller.GetRemoteConfig(ctx, data.HeadHash)
+	if err != nil {
+		return isMember, errors.Wrap(err, errInvalidPatchedConfig)
+	}
+	projectYamlBytes, err := yaml.Marshal(project)
+	if err != nil {
+		return isMember, errors.Wrap(err, errInvalidPatchedConfig)
+	}
+	patchDoc.PatchedConfig = string(projectYamlBytes)
+
+	j.user, err = findOrCreatePatchUser(evergreen.GitlabPatchUser, "Gitlab Merge Requests")
+	if err != nil {
+		return isMember, errors.Wrap(err, "failed to fetch user")
+	}
+	patchDoc.Author = j.user.Id
+
+	return isMember, nil
+}
+
+// authGitlabMergeRequest returns whether the author of a merge request can
+// push to the project, and so whether its patch can run without a project
+// member authorizing it.
+func authGitlabMergeRequest(ctx context.Context, projectRef *model.ProjectRef, data patch.GitlabPatch, settings *evergreen.Settings) (bool, error) {
+	apiURL, err := projectRef.GitlabAPIURL(data)
+	if err != nil {
+		return false, err
+	}
+	token, err := settings.GetGitlabToken()
+	if err != nil {
+		return false, err
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+
+	accessLevel, err := thirdparty.GetGitlabProjectAccessLevel(ctx, apiURL, token, data.ProjectID, data.AuthorID)
+	if err != nil {
+		return false, err
+	}
+
+	return accessLevel >= thirdparty.GitlabDeveloperAccess, nil
+}
+
 func authAndFetchPRMergeBase(ctx context.Context, patchDoc *patch.Patch, requiredOrganization, githubUser, githubOauthToken string) (bool, error) {
 	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
 	defer cancel()
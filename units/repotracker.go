@@ -84,12 +84,6 @@ func (j *repotrackerJob) Run(ctx context.Context) {
 		j.AddError(errors.New("settings is empty"))
 		return
 	}
-	token, err := settings.GetGithubOauthToken()
-	if err != nil {
-		j.AddError(errors.New("github token is missing"))
-		return
-	}
-
 	ref, err := model.FindOneProjectRef(j.ProjectID)
 	if err != nil {
 		j.AddError(err)
@@ -100,10 +94,20 @@ func (j *repotrackerJob) Run(ctx context.Context) {
 		return
 	}
 
-	if !repotracker.CheckGithubAPIResources(ctx, token) {
-		j.AddError(errors.Errorf("skipping repotracker run [%s] for %s because of github limit issues",
-			j.ID(), j.ProjectID))
-		return
+	// projects on other git remotes don't use the github API
+	if ref.RepoKind != model.GitRepoType {
+		var token string
+		token, err = settings.GetGithubOauthToken()
+		if err != nil {
+			j.AddError(errors.New("github token is missing"))
+			return
+		}
+
+		if !repotracker.CheckGithubAPIResources(ctx, token) {
+			j.AddError(errors.Errorf("skipping repotracker run [%s] for %s because of github limit issues",
+				j.ID(), j.ProjectID))
+			return
+		}
 	}
 
 	err = repotracker.CollectRevisionsForProject(ctx, settings, *ref)
@@ -0,0 +1,111 @@
+package units
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/evergreen-ci/evergreen"
+	"github.com/evergreen-ci/evergreen/model"
+	"github.com/mongodb/amboy"
+	"github.com/mongodb/amboy/dependency"
+	"github.com/mongodb/amboy/job"
+	"github.com/mongodb/amboy/registry"
+	"github.com/mongodb/grip"
+	"github.com/mongodb/grip/message"
+	"github.com/mongodb/grip/sometimes"
+	"github.com/pkg/errors"
+)
+
+const scheduledVersionJobName = "scheduled-version"
+
+func init() {
+	registry.AddJobType(scheduledVersionJobName, func() amboy.Job {
+		return makeScheduledVersionJob()
+	})
+}
+
+type scheduledVersionJob struct {
+	ProjectID     string    `bson:"project_id" json:"project_id" yaml:"project_id"`
+	ScheduledAt   time.Time `bson:"scheduled_at" json:"scheduled_at" yaml:"scheduled_at"`
+	BuildVariants []string  `bson:"build_variants" json:"build_variants" yaml:"build_variants"`
+	job.Base      `bson:"metadata" json:"metadata" yaml:"metadata"`
+}
+
+func makeScheduledVersionJob() *scheduledVersionJob {
+	j := &scheduledVersionJob{
+		Base: job.Base{
+			JobType: amboy.JobType{
+				Name:    scheduledVersionJobName,
+				Version: 0,
+			},
+		},
+	}
+	j.SetDependency(dependency.NewAlways())
+	return j
+}
+
+// NewScheduledVersionJob creates a job that creates the version of a project
+// for the cron schedules that fire at scheduledAt. If buildVariants is empty,
+// the version contains all of the project's variants.
+func NewScheduledVersionJob(projectID string, scheduledAt time.Time, buildVariants []string) amboy.Job {
+	j := makeScheduledVersionJob()
+	j.ProjectID = projectID
+	j.ScheduledAt = scheduledAt.UTC().Truncate(time.Minute)
+	j.BuildVariants = buildVariants
+
+	j.SetID(fmt.Sprintf("%s:%s:%s", scheduledVersionJobName, projectID, j.ScheduledAt.Format(tsFormat)))
+	return j
+}
+
+func (j *scheduledVersionJob) Run(_ context.Context) {
+	defer j.MarkComplete()
+
+	flags, err := evergreen.GetServiceFlags()
+	if err != nil {
+		j.AddError(errors.Wrap(err, "error retrieving admin settings"))
+		return
+	}
+	if flags.RepotrackerDisabled {
+		grip.InfoWhen(sometimes.Percent(evergreen.DegradedLoggingPercent), message.Fields{
+			"job":     scheduledVersionJobName,
+			"id":      j.ID(),
+			"message": "repotracker is disabled",
+		})
+		return
+	}
+
+	ref, err := model.FindOneProjectRef(j.ProjectID)
+	if err != nil {
+		j.AddError(errors.WithStack(err))
+		return
+	}
+	if ref == nil {
+		j.AddError(errors.Errorf("can't find project ref for project '%s'", j.ProjectID))
+		return
+	}
+	if !ref.Enabled {
+		return
+	}
+
+	v, err := model.CreateScheduledVersion(ref, j.ScheduledAt, j.BuildVariants)
+	if err != nil {
+		grip.Error(message.WrapError(err, message.Fields{
+			"job":          scheduledVersionJobName,
+			"job_id":       j.ID(),
+			"project":      j.ProjectID,
+			"scheduled_at": j.ScheduledAt,
+			"message":      "problem creating scheduled version",
+		}))
+		j.AddError(err)
+		return
+	}
+
+	grip.Info(message.Fields{
+		"job":          scheduledVersionJobName,
+		"job_id":       j.ID(),
+		"project":      j.ProjectID,
+		"scheduled_at": j.ScheduledAt,
+		"version":      v.Id,
+	})
+}
@@ -0,0 +1,32 @@
+package units
+
+import (
+	"testing"
+	"time"
+
+	"github.com/mongodb/amboy/registry"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestScheduledVersionJob(t *testing.T) {
+	assert := assert.New(t)
+
+	factory, err := registry.GetJobFactory(scheduledVersionJobName)
+	assert.NoError(err)
+	assert.NotNil(factory)
+
+	j, ok := factory().(*scheduledVersionJob)
+	assert.True(ok)
+	assert.NotNil(j)
+
+	// jobs for the same minute of the same project share an ID, so that
+	// a schedule never creates more than one version
+	scheduledAt := time.Date(2018, time.June, 4, 2, 0, 0, 0, time.UTC)
+	jOne := NewScheduledVersionJob("proj", scheduledAt, nil)
+	jTwo := NewScheduledVersionJob("proj", scheduledAt.Add(30*time.Second), nil)
+	jThree := NewScheduledVersionJob("proj", scheduledAt.Add(time.Minute), nil)
+	jFour := NewScheduledVersionJob("other", scheduledAt, nil)
+	assert.Equal(jOne.ID(), jTwo.ID())
+	assert.NotEqual(jOne.ID(), jThree.ID())
+	assert.NotEqual(jOne.ID(), jFour.ID())
+}
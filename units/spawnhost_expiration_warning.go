@@ -6,7 +6,8 @@ import (
 	"time"
 
 	"github.com/evergreen-ci/evergreen"
-	"github.com/evergreen-ci/evergreen/alerts"
+	"github.com/evergreen-ci/evergreen/model/alertrecord"
+	"github.com/evergreen-ci/evergreen/model/event"
 	"github.com/evergreen-ci/evergreen/model/host"
 	"github.com/mongodb/amboy"
 	"github.com/mongodb/amboy/dependency"
@@ -16,6 +17,7 @@ import (
 	"github.com/mongodb/grip/message"
 	"github.com/mongodb/grip/sometimes"
 	"github.com/pkg/errors"
+	"gopkg.in/mgo.v2/bson"
 )
 
 const spawnhostExpirationWarningsName = "spawnhost-expiration-warnings"
@@ -56,11 +58,11 @@ func (j *spawnhostExpirationWarningsJob) Run(ctx context.Context) {
 		j.AddError(errors.Wrap(err, "error retrieving admin settings"))
 		return
 	}
-	if flags.AlertsDisabled {
+	if flags.EventProcessingDisabled {
 		grip.InfoWhen(sometimes.Percent(evergreen.DegradedLoggingPercent), message.Fields{
 			"runner":  "alerter",
 			"id":      j.ID(),
-			"message": "alerts are disabled, exiting",
+			"message": "event processing is disabled, exiting",
 		})
 		return
 	}
@@ -81,7 +83,7 @@ func (j *spawnhostExpirationWarningsJob) Run(ctx context.Context) {
 			j.AddError(errors.New("spawnhost expiration warning run canceled"))
 			return
 		}
-		if err = alerts.RunSpawnWarningTriggers(&h); err != nil {
+		if err = runSpawnWarningTriggers(&h); err != nil {
 			j.AddError(err)
 			grip.Error(message.WrapError(err, message.Fields{
 				"runner":  "monitor",
@@ -93,3 +95,41 @@ func (j *spawnhostExpirationWarningsJob) Run(ctx context.Context) {
 	}
 
 }
+
+// runSpawnWarningTriggers logs an expiration warning event for the host
+// the first time it is within twelve hours, and within two hours, of
+// expiring. The spawnhost expiration trigger notifies the host's owner.
+func runSpawnWarningTriggers(h *host.Host) error {
+	warnings := []struct {
+		alertType string
+		window    time.Duration
+	}{
+		{alertType: alertrecord.SpawnHostTwoHourWarning, window: 2 * time.Hour},
+		{alertType: alertrecord.SpawnHostTwelveHourWarning, window: 12 * time.Hour},
+	}
+
+	for _, warning := range warnings {
+		if h.ExpirationTime.IsZero() || time.Until(h.ExpirationTime) > warning.window {
+			continue
+		}
+		rec, err := alertrecord.FindOne(alertrecord.ByHostAlertRecordType(h.Id, warning.alertType))
+		if err != nil {
+			return errors.WithStack(err)
+		}
+		if rec != nil {
+			continue
+		}
+
+		event.LogExpirationWarningSent(h.Id)
+		rec = &alertrecord.AlertRecord{
+			Id:     bson.NewObjectId(),
+			Type:   warning.alertType,
+			HostId: h.Id,
+		}
+		if err = rec.Insert(); err != nil {
+			return errors.WithStack(err)
+		}
+	}
+
+	return nil
+}
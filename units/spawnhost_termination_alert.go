@@ -70,8 +70,8 @@ func (j *spawnhostTerminationAlertJob) Run(_ context.Context) {
 		j.AddError(errors.Wrap(err, "error retrieving admin settings"))
 		return
 	}
-	if config.ServiceFlags.AlertsDisabled {
-		j.AddError(errors.New("alerts are disabled"))
+	if config.ServiceFlags.EmailNotificationsDisabled {
+		j.AddError(errors.New("email notifications are disabled"))
 		return
 	}
 
@@ -0,0 +1,119 @@
+package units
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/evergreen-ci/evergreen/model"
+	"github.com/mongodb/amboy"
+	"github.com/mongodb/amboy/dependency"
+	"github.com/mongodb/amboy/job"
+	"github.com/mongodb/amboy/registry"
+	"github.com/mongodb/grip"
+	"github.com/mongodb/grip/message"
+	"github.com/pkg/errors"
+	"gopkg.in/mgo.v2/bson"
+)
+
+const bulkTaskOperationJobName = "bulk-task-operation"
+
+func init() {
+	registry.AddJobType(bulkTaskOperationJobName, func() amboy.Job { return makeBulkTaskOperationJob() })
+}
+
+type bulkTaskOperationJob struct {
+	job.Base `bson:"job_base" json:"job_base" yaml:"job_base"`
+}
+
+func makeBulkTaskOperationJob() *bulkTaskOperationJob {
+	j := &bulkTaskOperationJob{
+		Base: job.Base{
+			JobType: amboy.JobType{
+				Name:    bulkTaskOperationJobName,
+				Version: 0,
+			},
+		},
+	}
+	j.SetDependency(dependency.NewAlways())
+	return j
+}
+
+// NewBulkTaskOperationJobID returns a unique id for a bulk task operation
+// and the job that applies it.
+func NewBulkTaskOperationJobID() string {
+	return fmt.Sprintf("%s:%s", bulkTaskOperationJobName, bson.NewObjectId().Hex())
+}
+
+// NewBulkTaskOperationJob creates a job that applies the bulk task
+// operation with the given id, which records the job's progress.
+func NewBulkTaskOperationJob(id string) amboy.Job {
+	j := makeBulkTaskOperationJob()
+	j.SetID(id)
+	j.SetPriority(1)
+	return j
+}
+
+func (j *bulkTaskOperationJob) Run(ctx context.Context) {
+	defer j.MarkComplete()
+
+	op, err := model.FindBulkTaskOperation(j.ID())
+	if err != nil {
+		j.AddError(err)
+		return
+	}
+	if op == nil {
+		j.AddError(errors.Errorf("bulk task operation '%s' not found", j.ID()))
+		return
+	}
+	if op.Status == model.BulkTaskOperationFinished {
+		return
+	}
+	if err = op.Start(); err != nil {
+		j.AddError(err)
+		return
+	}
+
+	// skip tasks that were processed before the job was interrupted
+	processed := map[string]bool{}
+	for _, id := range append(op.TasksSucceeded, op.TasksErrored...) {
+		processed[id] = true
+	}
+	for _, taskID := range op.TaskIDs {
+		if processed[taskID] {
+			continue
+		}
+		// the results so far are recorded, so a later run of the job
+		// picks up where this one stopped
+		if ctx.Err() != nil {
+			j.AddError(errors.New("bulk task operation run canceled"))
+			return
+		}
+
+		actionErr := model.ApplyBulkTaskAction(op.Options, taskID)
+		grip.Error(message.WrapError(actionErr, message.Fields{
+			"message": "problem applying bulk task action",
+			"job":     j.ID(),
+			"action":  op.Options.Action,
+			"task_id": taskID,
+			"user":    op.Options.User,
+		}))
+		if err = op.AddResult(taskID, actionErr); err != nil {
+			j.AddError(err)
+			return
+		}
+	}
+
+	if err = op.Finish(); err != nil {
+		j.AddError(err)
+		return
+	}
+
+	grip.Info(message.Fields{
+		"message":         "finished bulk task operation",
+		"job":             j.ID(),
+		"action":          op.Options.Action,
+		"user":            op.Options.User,
+		"tasks_succeeded": len(op.TasksSucceeded),
+		"tasks_errored":   len(op.TasksErrored),
+	})
+}
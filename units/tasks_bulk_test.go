@@ -0,0 +1,71 @@
+package units
+
+import (
+	"context"
+	"testing"
+
+	"github.com/evergreen-ci/evergreen/db"
+	"github.com/evergreen-ci/evergreen/model"
+	"github.com/evergreen-ci/evergreen/model/event"
+	"github.com/evergreen-ci/evergreen/model/task"
+	"github.com/evergreen-ci/evergreen/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBulkTaskOperationJob(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+	db.SetGlobalSessionProvider(testutil.TestConfig().SessionFactory())
+	require.NoError(db.ClearCollections(task.Collection, event.AllLogCollection, model.BulkTaskOperationsCollection))
+	defer func() {
+		assert.NoError(db.ClearCollections(task.Collection, event.AllLogCollection, model.BulkTaskOperationsCollection))
+	}()
+
+	require.NoError((&task.Task{Id: "t1", Activated: true}).Insert())
+	require.NoError((&task.Task{Id: "t2", Activated: true}).Insert())
+
+	id := NewBulkTaskOperationJobID()
+	opts := model.BulkTaskOptions{Action: model.BulkTaskSetPriority, Priority: 20, User: "me"}
+	require.NoError(model.NewBulkTaskOperation(id, opts, []string{"t1", "missing", "t2"}).Insert())
+
+	j := NewBulkTaskOperationJob(id)
+	j.Run(context.Background())
+	assert.NoError(j.Error())
+
+	op, err := model.FindBulkTaskOperation(id)
+	require.NoError(err)
+	require.NotNil(op)
+	assert.Equal(model.BulkTaskOperationFinished, op.Status)
+	assert.Equal(3, op.Processed)
+	assert.Equal([]string{"t1", "t2"}, op.TasksSucceeded)
+	assert.Equal([]string{"missing"}, op.TasksErrored)
+
+	for _, taskID := range []string{"t1", "t2"} {
+		tsk, err := task.FindOne(task.ById(taskID))
+		require.NoError(err)
+		assert.EqualValues(20, tsk.Priority)
+	}
+
+	// a canceled job stops before applying the action to any more tasks
+	canceledID := NewBulkTaskOperationJobID()
+	require.NoError(model.NewBulkTaskOperation(canceledID, model.BulkTaskOptions{Action: model.BulkTaskSetPriority, Priority: 30, User: "me"}, []string{"t1"}).Insert())
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	j = NewBulkTaskOperationJob(canceledID)
+	j.Run(ctx)
+	assert.Error(j.Error())
+	op, err = model.FindBulkTaskOperation(canceledID)
+	require.NoError(err)
+	require.NotNil(op)
+	assert.NotEqual(model.BulkTaskOperationFinished, op.Status)
+	assert.Equal(0, op.Processed)
+	tsk, err := task.FindOne(task.ById("t1"))
+	require.NoError(err)
+	assert.EqualValues(20, tsk.Priority)
+
+	// the job fails if its operation doesn't exist
+	j = NewBulkTaskOperationJob(NewBulkTaskOperationJobID())
+	j.Run(context.Background())
+	assert.Error(j.Error())
+}
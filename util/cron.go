@@ -0,0 +1,159 @@
+package util
+
+import (
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// CronSchedule is a parsed cron expression in the standard five field
+// format: "minute hour day-of-month month day-of-week". Each field
+// accepts "*", single values, ranges ("1-5"), lists ("1,15") and steps
+// ("*/15", "0-30/10"). The "@hourly", "@daily", "@midnight", "@weekly"
+// and "@monthly" shorthands are also accepted. Schedules are always
+// evaluated in UTC.
+type CronSchedule struct {
+	minutes     uint64
+	hours       uint64
+	daysOfMonth uint64
+	months      uint64
+	daysOfWeek  uint64
+
+	// anyDay fields record whether the day fields were unrestricted, which
+	// matters because a time matches a cron expression that restricts both
+	// day fields when either of them matches.
+	anyDayOfMonth bool
+	anyDayOfWeek  bool
+}
+
+type cronField struct {
+	name     string
+	min, max int
+}
+
+var (
+	cronFields = []cronField{
+		{name: "minute", min: 0, max: 59},
+		{name: "hour", min: 0, max: 23},
+		{name: "day of month", min: 1, max: 31},
+		{name: "month", min: 1, max: 12},
+		{name: "day of week", min: 0, max: 6},
+	}
+
+	cronDescriptors = map[string]string{
+		"@hourly":   "0 * * * *",
+		"@daily":    "0 0 * * *",
+		"@midnight": "0 0 * * *",
+		"@weekly":   "0 0 * * 0",
+		"@monthly":  "0 0 1 * *",
+	}
+)
+
+// ParseCronSchedule parses a cron expression, returning an error if it is
+// malformed.
+func ParseCronSchedule(expr string) (*CronSchedule, error) {
+	expr = strings.TrimSpace(expr)
+	if descriptor, ok := cronDescriptors[expr]; ok {
+		expr = descriptor
+	}
+
+	fields := strings.Fields(expr)
+	if len(fields) != len(cronFields) {
+		return nil, errors.Errorf("cron expression '%s' must have %d fields", expr, len(cronFields))
+	}
+
+	values := make([]uint64, len(cronFields))
+	for i, field := range fields {
+		bits, err := parseCronField(field, cronFields[i])
+		if err != nil {
+			return nil, errors.Wrapf(err, "invalid cron expression '%s'", expr)
+		}
+		values[i] = bits
+	}
+
+	return &CronSchedule{
+		minutes:       values[0],
+		hours:         values[1],
+		daysOfMonth:   values[2],
+		months:        values[3],
+		daysOfWeek:    values[4],
+		anyDayOfMonth: strings.HasPrefix(fields[2], "*"),
+		anyDayOfWeek:  strings.HasPrefix(fields[4], "*"),
+	}, nil
+}
+
+func parseCronField(field string, spec cronField) (uint64, error) {
+	var bits uint64
+	for _, part := range strings.Split(field, ",") {
+		step := 1
+		if idx := strings.Index(part, "/"); idx >= 0 {
+			var err error
+			step, err = strconv.Atoi(part[idx+1:])
+			if err != nil || step <= 0 {
+				return 0, errors.Errorf("invalid step in %s field '%s'", spec.name, field)
+			}
+			part = part[:idx]
+		}
+
+		low, high := spec.min, spec.max
+		if part != "*" {
+			bounds := strings.SplitN(part, "-", 2)
+			var err error
+			low, err = strconv.Atoi(bounds[0])
+			if err != nil {
+				return 0, errors.Errorf("invalid value in %s field '%s'", spec.name, field)
+			}
+			high = low
+			if len(bounds) == 2 {
+				high, err = strconv.Atoi(bounds[1])
+				if err != nil {
+					return 0, errors.Errorf("invalid value in %s field '%s'", spec.name, field)
+				}
+			} else if step > 1 {
+				// "5/15" is shorthand for "5-max/15"
+				high = spec.max
+			}
+		}
+		if low < spec.min || high > spec.max || low > high {
+			return 0, errors.Errorf("%s field '%s' must be between %d and %d", spec.name, field, spec.min, spec.max)
+		}
+
+		for i := low; i <= high; i += step {
+			bits |= 1 << uint(i)
+		}
+	}
+
+	return bits, nil
+}
+
+// Matches returns whether the minute containing t is one of the schedule's
+// activation times.
+func (s *CronSchedule) Matches(t time.Time) bool {
+	t = t.UTC()
+	if s.minutes&(1<<uint(t.Minute())) == 0 ||
+		s.hours&(1<<uint(t.Hour())) == 0 ||
+		s.months&(1<<uint(t.Month())) == 0 {
+		return false
+	}
+
+	dayOfMonth := s.daysOfMonth&(1<<uint(t.Day())) != 0
+	dayOfWeek := s.daysOfWeek&(1<<uint(t.Weekday())) != 0
+	if s.anyDayOfMonth || s.anyDayOfWeek {
+		return dayOfMonth && dayOfWeek
+	}
+	return dayOfMonth || dayOfWeek
+}
+
+// ActivationsBetween returns the schedule's activation times, truncated to
+// the minute, that fall after start and no later than end.
+func (s *CronSchedule) ActivationsBetween(start, end time.Time) []time.Time {
+	activations := []time.Time{}
+	for t := start.UTC().Truncate(time.Minute).Add(time.Minute); !t.After(end); t = t.Add(time.Minute) {
+		if s.Matches(t) {
+			activations = append(activations, t)
+		}
+	}
+	return activations
+}
@@ -0,0 +1,91 @@
+package util
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseCronSchedule(t *testing.T) {
+	assert := assert.New(t)
+
+	for _, expr := range []string{
+		"* * * * *",
+		"0 2 * * *",
+		"*/15 0-6,18-23 1,15 * 1-5",
+		"5/10 * * 6 *",
+		"@daily",
+		" @weekly ",
+	} {
+		_, err := ParseCronSchedule(expr)
+		assert.NoError(err, expr)
+	}
+
+	for _, expr := range []string{
+		"",
+		"* * * *",
+		"* * * * * *",
+		"60 * * * *",
+		"* 24 * * *",
+		"* * 0 * *",
+		"* * * 13 *",
+		"* * * * 7",
+		"5-1 * * * *",
+		"*/0 * * * *",
+		"a * * * *",
+		"@yearly",
+	} {
+		_, err := ParseCronSchedule(expr)
+		assert.Error(err, expr)
+	}
+}
+
+func TestCronScheduleMatches(t *testing.T) {
+	assert := assert.New(t)
+
+	// a monday
+	monday := time.Date(2018, time.June, 4, 2, 0, 30, 0, time.UTC)
+
+	nightly, err := ParseCronSchedule("0 2 * * *")
+	require.NoError(t, err)
+	assert.True(nightly.Matches(monday))
+	assert.False(nightly.Matches(monday.Add(time.Minute)))
+	assert.False(nightly.Matches(monday.Add(time.Hour)))
+
+	// times are evaluated in UTC
+	est := time.FixedZone("EST", -5*60*60)
+	assert.True(nightly.Matches(monday.In(est)))
+
+	weekdays, err := ParseCronSchedule("*/30 9-17 * * 1-5")
+	require.NoError(t, err)
+	assert.True(weekdays.Matches(time.Date(2018, time.June, 4, 9, 30, 0, 0, time.UTC)))
+	assert.False(weekdays.Matches(time.Date(2018, time.June, 4, 9, 15, 0, 0, time.UTC)))
+	assert.False(weekdays.Matches(time.Date(2018, time.June, 3, 9, 30, 0, 0, time.UTC)))
+
+	// when both day fields are restricted, either can match
+	days, err := ParseCronSchedule("0 0 1 * 1")
+	require.NoError(t, err)
+	assert.True(days.Matches(time.Date(2018, time.June, 1, 0, 0, 0, 0, time.UTC)))
+	assert.True(days.Matches(time.Date(2018, time.June, 4, 0, 0, 0, 0, time.UTC)))
+	assert.False(days.Matches(time.Date(2018, time.June, 5, 0, 0, 0, 0, time.UTC)))
+}
+
+func TestCronScheduleActivationsBetween(t *testing.T) {
+	assert := assert.New(t)
+
+	schedule, err := ParseCronSchedule("*/15 * * * *")
+	require.NoError(t, err)
+
+	start := time.Date(2018, time.June, 4, 2, 0, 0, 0, time.UTC)
+	activations := schedule.ActivationsBetween(start, start.Add(time.Hour))
+	assert.Equal([]time.Time{
+		start.Add(15 * time.Minute),
+		start.Add(30 * time.Minute),
+		start.Add(45 * time.Minute),
+		start.Add(time.Hour),
+	}, activations)
+
+	assert.Empty(schedule.ActivationsBetween(start.Add(time.Minute), start.Add(10*time.Minute)))
+}
@@ -25,6 +25,9 @@ type EvergreenWebhook struct {
 	Secret         []byte      `bson:"secret"`
 	Body           []byte      `bson:"body"`
 	Headers        http.Header `bson:"headers"`
+	// SigningKeys are the keys used to produce the timestamped
+	// signatures. If empty, Secret is used as the only key.
+	SigningKeys []WebhookSigningKey `bson:"signing_keys,omitempty"`
 }
 
 type evergreenWebhookMessage struct {
@@ -131,8 +134,19 @@ func (w *evergreenWebhookLogger) send(m message.Composer) error {
 		}
 	}
 
+	keys := raw.SigningKeys
+	if len(keys) == 0 {
+		keys = []WebhookSigningKey{{Secret: raw.Secret}}
+	}
+	signatures, err := SignWebhookPayload(keys, time.Now(), raw.Body)
+	if err != nil {
+		return errors.Wrap(err, "evergreen-webhook failed to sign payload")
+	}
+
 	req.Header.Del(evergreenHMACHeader)
 	req.Header.Add(evergreenHMACHeader, hash)
+	req.Header.Del(EvergreenWebhookSignaturesHeader)
+	req.Header.Add(EvergreenWebhookSignaturesHeader, signatures)
 	req.Header.Del(evergreenNotificationIDHeader)
 	req.Header.Add(evergreenNotificationIDHeader, raw.NotificationID)
 
@@ -7,6 +7,7 @@ import (
 	"io/ioutil"
 	"net/http"
 	"testing"
+	"time"
 
 	"github.com/mongodb/grip"
 	"github.com/mongodb/grip/message"
@@ -78,7 +79,9 @@ func TestEvergreenWebhookSender(t *testing.T) {
 	s.Send(m)
 	assert.Equal("https://example.com", transport.lastUrl)
 
-	assert.Len(transport.header, 3)
+	assert.Len(transport.header, 4)
+	assert.NoError(VerifyWebhookSignature(transport.header.Get(EvergreenWebhookSignaturesHeader), []byte("something important"),
+		[]WebhookSigningKey{{Secret: []byte("hi")}}, DefaultWebhookTimestampTolerance, time.Now()))
 	assert.Len(transport.header["Test"], 2)
 	assert.Contains(transport.header["Test"], "test1")
 	assert.Contains(transport.header["Test"], "test2")
@@ -0,0 +1,150 @@
+package util
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+const (
+	// EvergreenWebhookSignaturesHeader is the header containing the
+	// timestamped signatures of an Evergreen webhook. Its value has the form
+	//
+	//     t=<unix timestamp>,k<key version>=<hex sha256 hmac>[,k<key version>=...]
+	//
+	// where each HMAC is calculated over "<unix timestamp>.<body>" with the
+	// signing key of that version. While a subscription's secret is being
+	// rotated, the payload is signed with every key that is still valid.
+	EvergreenWebhookSignaturesHeader = "X-Evergreen-Signatures"
+
+	// DefaultWebhookTimestampTolerance is the maximum age of a webhook
+	// timestamp that VerifyEvergreenWebhook accepts by default.
+	DefaultWebhookTimestampTolerance = 5 * time.Minute
+
+	webhookTimestampPrefix = "t="
+	webhookKeyPrefix       = "k"
+)
+
+// WebhookSigningKey is a versioned secret used to sign webhook payloads.
+type WebhookSigningKey struct {
+	Version int    `bson:"version"`
+	Secret  []byte `bson:"secret"`
+}
+
+// SignWebhookPayload returns the value of the EvergreenWebhookSignaturesHeader
+// for a body sent at the given time.
+func SignWebhookPayload(keys []WebhookSigningKey, timestamp time.Time, body []byte) (string, error) {
+	if len(keys) == 0 {
+		return "", errors.New("no signing keys provided")
+	}
+
+	ts := strconv.FormatInt(timestamp.Unix(), 10)
+	parts := []string{webhookTimestampPrefix + ts}
+	for _, key := range keys {
+		if len(key.Secret) == 0 {
+			return "", errors.Errorf("signing key version %d is empty", key.Version)
+		}
+		parts = append(parts, fmt.Sprintf("%s%d=%s", webhookKeyPrefix, key.Version, hex.EncodeToString(webhookMAC(key.Secret, ts, body))))
+	}
+
+	return strings.Join(parts, ","), nil
+}
+
+// VerifyWebhookSignature checks that a value of the
+// EvergreenWebhookSignaturesHeader contains a valid signature of the body
+// by any of the keys, and that its timestamp is no more than tolerance away
+// from now. Receivers should additionally reject notification IDs they have
+// already processed to fully protect against replayed requests.
+func VerifyWebhookSignature(header string, body []byte, keys []WebhookSigningKey, tolerance time.Duration, now time.Time) error {
+	if header == "" {
+		return errors.New("webhook signature is empty")
+	}
+
+	ts := ""
+	signatures := map[int][]byte{}
+	for _, part := range strings.Split(header, ",") {
+		part = strings.TrimSpace(part)
+		if strings.HasPrefix(part, webhookTimestampPrefix) {
+			ts = strings.TrimPrefix(part, webhookTimestampPrefix)
+			continue
+		}
+
+		if !strings.HasPrefix(part, webhookKeyPrefix) {
+			continue
+		}
+		kv := strings.SplitN(strings.TrimPrefix(part, webhookKeyPrefix), "=", 2)
+		if len(kv) != 2 {
+			continue
+		}
+		version, err := strconv.Atoi(kv[0])
+		if err != nil {
+			continue
+		}
+		sig, err := hex.DecodeString(kv[1])
+		if err != nil {
+			continue
+		}
+		signatures[version] = sig
+	}
+
+	if ts == "" {
+		return errors.New("webhook signature has no timestamp")
+	}
+	unix, err := strconv.ParseInt(ts, 10, 64)
+	if err != nil {
+		return errors.Wrapf(err, "webhook signature has invalid timestamp '%s'", ts)
+	}
+	sent := time.Unix(unix, 0)
+	if now.Sub(sent) > tolerance || sent.Sub(now) > tolerance {
+		return errors.Errorf("webhook timestamp %s is outside of the tolerance of %s", sent.UTC().Format(time.RFC3339), tolerance)
+	}
+
+	for _, key := range keys {
+		sig, ok := signatures[key.Version]
+		if !ok || len(key.Secret) == 0 {
+			continue
+		}
+		if hmac.Equal(sig, webhookMAC(key.Secret, ts, body)) {
+			return nil
+		}
+	}
+
+	return errors.New("webhook signature does not match any signing key")
+}
+
+// VerifyEvergreenWebhook verifies the signature of an incoming Evergreen
+// webhook request, as described by VerifyWebhookSignature, and returns the
+// request body.
+func VerifyEvergreenWebhook(r *http.Request, keys []WebhookSigningKey, tolerance time.Duration) ([]byte, error) {
+	if r.Body == nil {
+		return nil, errors.New("webhook request has no body")
+	}
+	body, err := ioutil.ReadAll(r.Body)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to read webhook body")
+	}
+	r.Body = ioutil.NopCloser(bytes.NewReader(body))
+
+	if err = VerifyWebhookSignature(r.Header.Get(EvergreenWebhookSignaturesHeader), body, keys, tolerance, time.Now()); err != nil {
+		return nil, err
+	}
+
+	return body, nil
+}
+
+func webhookMAC(secret []byte, timestamp string, body []byte) []byte {
+	mac := hmac.New(sha256.New, secret)
+	_, _ = mac.Write([]byte(timestamp))
+	_, _ = mac.Write([]byte("."))
+	_, _ = mac.Write(body)
+	return mac.Sum(nil)
+}
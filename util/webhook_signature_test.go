@@ -0,0 +1,78 @@
+package util
+
+import (
+	"bytes"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWebhookSignatures(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+
+	now := time.Now()
+	body := []byte("something important")
+	current := WebhookSigningKey{Version: 2, Secret: []byte("new secret")}
+	previous := WebhookSigningKey{Version: 1, Secret: []byte("old secret")}
+
+	_, err := SignWebhookPayload(nil, now, body)
+	assert.Error(err)
+
+	header, err := SignWebhookPayload([]WebhookSigningKey{current, previous}, now, body)
+	require.NoError(err)
+	assert.True(strings.HasPrefix(header, "t="))
+	assert.Contains(header, ",k2=")
+	assert.Contains(header, ",k1=")
+
+	// receivers that know either key can verify the payload
+	assert.NoError(VerifyWebhookSignature(header, body, []WebhookSigningKey{current}, time.Minute, now))
+	assert.NoError(VerifyWebhookSignature(header, body, []WebhookSigningKey{previous}, time.Minute, now))
+
+	// a key with the wrong version doesn't verify
+	assert.Error(VerifyWebhookSignature(header, body, []WebhookSigningKey{{Version: 3, Secret: current.Secret}}, time.Minute, now))
+	// a different secret doesn't verify
+	assert.Error(VerifyWebhookSignature(header, body, []WebhookSigningKey{{Version: 2, Secret: []byte("forged")}}, time.Minute, now))
+	// a modified body doesn't verify
+	assert.Error(VerifyWebhookSignature(header, []byte("something forged"), []WebhookSigningKey{current}, time.Minute, now))
+	// a replayed request doesn't verify after the tolerance has passed
+	assert.Error(VerifyWebhookSignature(header, body, []WebhookSigningKey{current}, time.Minute, now.Add(2*time.Minute)))
+	// a modified timestamp doesn't verify
+	forged := strings.Replace(header, strings.Split(header, ",")[0], "t=1", 1)
+	assert.Error(VerifyWebhookSignature(forged, body, []WebhookSigningKey{current}, 100*365*24*time.Hour, now))
+
+	assert.Error(VerifyWebhookSignature("", body, []WebhookSigningKey{current}, time.Minute, now))
+	assert.Error(VerifyWebhookSignature("k2=abcd", body, []WebhookSigningKey{current}, time.Minute, now))
+}
+
+func TestVerifyEvergreenWebhook(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+
+	key := WebhookSigningKey{Version: 1, Secret: []byte("secret")}
+	body := []byte("something important")
+	header, err := SignWebhookPayload([]WebhookSigningKey{key}, time.Now(), body)
+	require.NoError(err)
+
+	req, err := http.NewRequest(http.MethodPost, "https://example.com", bytes.NewReader(body))
+	require.NoError(err)
+	req.Header.Set(EvergreenWebhookSignaturesHeader, header)
+
+	out, err := VerifyEvergreenWebhook(req, []WebhookSigningKey{key}, DefaultWebhookTimestampTolerance)
+	assert.NoError(err)
+	assert.Equal(body, out)
+
+	// the body can still be read by the handler
+	reread, err := ioutil.ReadAll(req.Body)
+	assert.NoError(err)
+	assert.Equal(body, reread)
+
+	req.Header.Del(EvergreenWebhookSignaturesHeader)
+	_, err = VerifyEvergreenWebhook(req, []WebhookSigningKey{key}, DefaultWebhookTimestampTolerance)
+	assert.Error(err)
+}
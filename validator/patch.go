@@ -27,40 +27,40 @@ func GetPatchedProject(ctx context.Context, p *patch.Patch, githubOauthToken str
 	ctx, cancel = context.WithTimeout(ctx, 10*time.Second)
 	defer cancel()
 
-	// try to get the remote project file data at the requested revision
+	// use the patched config if the patch already has one, otherwise try to
+	// get the remote project file data at the requested revision
 	var projectFileBytes []byte
-	hash := p.Githash
-
-	if p.IsGithubPRPatch() {
-		hash = p.GithubPatchData.HeadHash
-	}
+	if p.PatchedConfig != "" {
+		projectFileBytes = []byte(p.PatchedConfig)
+	} else {
+		hash := p.Githash
 
-	githubFile, err := thirdparty.GetGithubFile(ctx, githubOauthToken, projectRef.Owner,
-		projectRef.Repo, projectRef.RemotePath, hash)
-	if err != nil {
-		// if the project file doesn't exist, but our patch includes a project file,
-		// we try to apply the diff and proceed.
-		if !(p.ConfigChanged(projectRef.RemotePath) && thirdparty.IsFileNotFound(err)) {
-			// return an error if the github error is network/auth-related or we aren't patching the config
-			return nil, errors.Wrapf(err, "Could not get github file at '%s/%s'@%s: %s", projectRef.Owner,
-				projectRef.Repo, projectRef.RemotePath, hash)
+		if p.IsGithubPRPatch() {
+			hash = p.GithubPatchData.HeadHash
 		}
-	} else {
-		// we successfully got the project file in base64, so we decode it
-		projectFileBytes, err = base64.StdEncoding.DecodeString(*githubFile.Content)
+
+		githubFile, err := thirdparty.GetGithubFile(ctx, githubOauthToken, projectRef.Owner,
+			projectRef.Repo, projectRef.RemotePath, hash)
 		if err != nil {
-			return nil, errors.Wrapf(err, "Could not decode github file at '%s/%s'@%s: %s", projectRef.Owner,
-				projectRef.Repo, projectRef.RemotePath, hash)
+			// if the project file doesn't exist, but our patch includes a project file,
+			// we try to apply the diff and proceed.
+			if !(p.ConfigChanged(projectRef.RemotePath) && thirdparty.IsFileNotFound(err)) {
+				// return an error if the github error is network/auth-related or we aren't patching the config
+				return nil, errors.Wrapf(err, "Could not get github file at '%s/%s'@%s: %s", projectRef.Owner,
+					projectRef.Repo, projectRef.RemotePath, hash)
+			}
+		} else {
+			// we successfully got the project file in base64, so we decode it
+			projectFileBytes, err = base64.StdEncoding.DecodeString(*githubFile.Content)
+			if err != nil {
+				return nil, errors.Wrapf(err, "Could not decode github file at '%s/%s'@%s: %s", projectRef.Owner,
+					projectRef.Repo, projectRef.RemotePath, hash)
+			}
 		}
 	}
 
 	project := &model.Project{}
 
-	// if the patched config exists, use that as the project file bytes.
-	if p.PatchedConfig != "" {
-		projectFileBytes = []byte(p.PatchedConfig)
-	}
-
 	// apply remote configuration patch if needed
 	if !p.IsGithubPRPatch() && p.ConfigChanged(projectRef.RemotePath) && p.PatchedConfig == "" {
 		project, err = model.MakePatchedConfig(ctx, p, projectRef.RemotePath, string(projectFileBytes))